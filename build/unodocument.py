@@ -0,0 +1,202 @@
+#!/usr/bin/env python3
+#
+# unodocument prepares the documents thanks to a running LibreOffice
+# instance, i.e., one which accepts the UNO connections on a socket (see the
+# --accept switch of soffice), for what unoconverter cannot do, e.g.:
+#
+#   unodocument --port 2002 tracked-changes --mode accept --output out.odt in.docx
+#   unodocument --port 2002 statistics --output statistics.json in.doc
+#
+# The exit code is 0 on success, 3 if the command does not apply to the
+# document, e.g., the tracked changes of a spreadsheet, 6 if LibreOffice
+# cannot load the document, like unoconverter, and 1 otherwise.
+
+import argparse
+import json
+import os
+import sys
+
+import uno
+from com.sun.star.beans import PropertyValue
+
+__version__ = "1.0.0"
+
+EXIT_ERROR = 1
+EXIT_NOT_SUPPORTED = 3
+EXIT_LOAD_FAILED = 6
+
+
+class NotSupportedError(Exception):
+    """The command does not apply to the document."""
+
+
+class LoadError(Exception):
+    """LibreOffice cannot load the document."""
+
+
+def property_value(name, value):
+    prop = PropertyValue()
+    prop.Name = name
+    prop.Value = value
+
+    return prop
+
+
+def typed_value(value):
+    """Returns the value of a "key=value" argument as a boolean, an integer
+    or a string, like unoconverter."""
+    if value in ("true", "false"):
+        return value == "true"
+
+    try:
+        return int(value)
+    except ValueError:
+        return value
+
+
+def file_url(path):
+    return uno.systemPathToFileUrl(os.path.abspath(path))
+
+
+def connect(port):
+    local_context = uno.getComponentContext()
+    resolver = local_context.ServiceManager.createInstanceWithContext(
+        "com.sun.star.bridge.UnoUrlResolver", local_context
+    )
+
+    return resolver.resolve(
+        "uno:socket,host=127.0.0.1,port=%d,tcpNoDelay=1;urp;StarOffice.ComponentContext" % port
+    )
+
+
+def load(context, args):
+    """Loads the document, hidden, with the same properties as
+    unoconverter."""
+    properties = [property_value("Hidden", True)]
+
+    if args.import_filter_name:
+        properties.append(property_value("FilterName", args.import_filter_name))
+
+    if args.password:
+        properties.append(property_value("Password", args.password))
+
+    for item in args.imports:
+        key, separator, value = item.partition("=")
+        if separator:
+            properties.append(property_value(key, typed_value(value)))
+        else:
+            properties.append(property_value("FilterOptions", item))
+
+    desktop = context.ServiceManager.createInstanceWithContext("com.sun.star.frame.Desktop", context)
+
+    try:
+        document = desktop.loadComponentFromURL(file_url(args.input), "_blank", 0, tuple(properties))
+    except Exception as e:
+        raise LoadError(str(e))
+
+    if document is None:
+        raise LoadError("no document")
+
+    return document
+
+
+def tracked_changes(context, document, args):
+    """Shows, accepts or rejects all the tracked changes of a text document,
+    then stores it as an ODT document, LibreOffice's own format, so that the
+    conversion renders what LibreOffice has in memory."""
+    if not document.supportsService("com.sun.star.text.TextDocument"):
+        raise NotSupportedError("not a text document")
+
+    document.RecordChanges = False
+
+    if args.mode == "show":
+        document.ShowChanges = True
+    else:
+        command = ".uno:AcceptAllTrackedChanges" if args.mode == "accept" else ".uno:RejectAllTrackedChanges"
+        dispatcher = context.ServiceManager.createInstanceWithContext("com.sun.star.frame.DispatchHelper", context)
+        dispatcher.executeDispatch(document.getCurrentController().getFrame(), command, "", 0, ())
+
+    document.storeToURL(file_url(args.output), (property_value("FilterName", "writer8"),))
+
+
+def statistics(context, document, args):
+    """Writes the counts of a document, as LibreOffice lays it out, in a JSON
+    file, i.e., the pages of a text document or of a drawing, the slides of
+    a presentation or the sheets of a spreadsheet."""
+    counts = {}
+
+    if document.supportsService("com.sun.star.text.TextDocument"):
+        # Jumping to the last page forces the layout of the whole document.
+        cursor = document.getCurrentController().getViewCursor()
+        cursor.jumpToLastPage()
+        counts["pages"] = cursor.getPage()
+    elif document.supportsService("com.sun.star.sheet.SpreadsheetDocument"):
+        counts["sheets"] = document.Sheets.getCount()
+    elif document.supportsService("com.sun.star.presentation.PresentationDocument"):
+        counts["slides"] = document.DrawPages.getCount()
+    elif document.supportsService("com.sun.star.drawing.DrawingDocument"):
+        counts["pages"] = document.DrawPages.getCount()
+    else:
+        raise NotSupportedError("neither a text document, a spreadsheet, a presentation nor a drawing")
+
+    with open(args.output, "w") as f:
+        json.dump(counts, f)
+
+
+COMMANDS = {
+    "tracked-changes": tracked_changes,
+    "statistics": statistics,
+}
+
+
+def parse_args():
+    parser = argparse.ArgumentParser(prog="unodocument", description="Prepare documents with LibreOffice.")
+    parser.add_argument("--version", action="version", version="%(prog)s " + __version__)
+    parser.add_argument("--port", type=int, default=2002, help="port of the LibreOffice instance")
+    parser.add_argument("--import-filter-name", help="name of the import filter")
+    parser.add_argument("--import", dest="imports", action="append", default=[], help="import filter options or load properties (key=value)")
+    parser.add_argument("--password", help="password of the document")
+
+    commands = parser.add_subparsers(dest="command", required=True)
+
+    command = commands.add_parser("tracked-changes", help="show, accept or reject all the tracked changes of a text document")
+    command.add_argument("--mode", choices=["show", "accept", "reject"], required=True)
+    command.add_argument("--output", required=True, help="path of the resulting ODT document")
+    command.add_argument("input")
+
+    command = commands.add_parser("statistics", help="write the page, slide or sheet count of a document")
+    command.add_argument("--output", required=True, help="path of the resulting JSON file")
+    command.add_argument("input")
+
+    return parser.parse_args()
+
+
+def main():
+    args = parse_args()
+
+    try:
+        context = connect(args.port)
+        document = load(context, args)
+    except LoadError as e:
+        print("load '%s': %s" % (args.input, e), file=sys.stderr)
+        return EXIT_LOAD_FAILED
+    except Exception as e:
+        print("connect to LibreOffice: %s" % e, file=sys.stderr)
+        return EXIT_ERROR
+
+    try:
+        COMMANDS[args.command](context, document, args)
+    except NotSupportedError as e:
+        print("%s '%s': %s" % (args.command, args.input, e), file=sys.stderr)
+        return EXIT_NOT_SUPPORTED
+    except Exception as e:
+        print("%s '%s': %s" % (args.command, args.input, e), file=sys.stderr)
+        return EXIT_ERROR
+    finally:
+        document.close(True)
+
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
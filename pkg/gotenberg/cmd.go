@@ -2,22 +2,63 @@ package gotenberg
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"syscall"
 
 	"go.uber.org/zap"
 )
 
+// sensitiveFlags are the flags which values are never logged, e.g., the
+// passwords of the documents.
+var sensitiveFlags = []string{
+	"--password",
+}
+
+// sensitiveProperties are the "key=value" arguments which values are never
+// logged, e.g., the passwords of the encrypted PDFs.
+var sensitiveProperties = []string{
+	"DocumentOpenPassword",
+	"PermissionPassword",
+}
+
+// redactArgs returns a copy of the arguments of a command, with the values
+// of the sensitive flags redacted.
+func redactArgs(args []string) []string {
+	redactedArgs := make([]string, len(args))
+	copy(redactedArgs, args)
+
+	for i := 0; i < len(redactedArgs)-1; i++ {
+		for _, flag := range sensitiveFlags {
+			if redactedArgs[i] == flag {
+				redactedArgs[i+1] = "[REDACTED]"
+			}
+		}
+	}
+
+	for i, arg := range redactedArgs {
+		key, _, ok := strings.Cut(arg, "=")
+		if ok && slices.Contains(sensitiveProperties, key) {
+			redactedArgs[i] = key + "=[REDACTED]"
+		}
+	}
+
+	return redactedArgs
+}
+
 // Cmd wraps an [exec.Cmd].
 type Cmd struct {
 	ctx     context.Context
 	logger  *zap.Logger
 	process *exec.Cmd
+	output  *bytes.Buffer
 }
 
 // Command creates a [Cmd] without a context. It configures the internal
@@ -56,6 +97,16 @@ func CommandContext(ctx context.Context, logger *zap.Logger, binPath string, arg
 	}, nil
 }
 
+// SetEnv adds environment variables, as "key=value", to the ones the unix
+// process inherits. It should be called before starting the command.
+func (cmd *Cmd) SetEnv(env ...string) {
+	if len(env) == 0 {
+		return
+	}
+
+	cmd.process.Env = append(os.Environ(), env...)
+}
+
 // Start starts the command but does not wait for its completion.
 func (cmd *Cmd) Start() error {
 	err := cmd.pipeOutput()
@@ -63,7 +114,7 @@ func (cmd *Cmd) Start() error {
 		return fmt.Errorf("pipe unix process output: %w", err)
 	}
 
-	cmd.logger.Debug(fmt.Sprintf("start unix process: %s", strings.Join(cmd.process.Args, " ")))
+	cmd.logger.Debug(fmt.Sprintf("start unix process: %s", strings.Join(redactArgs(cmd.process.Args), " ")))
 
 	err = cmd.process.Start()
 	if err != nil {
@@ -132,9 +183,35 @@ func (cmd *Cmd) Exec() (int, error) {
 	}
 }
 
+// ExecOutput executes the command like [Cmd.Exec], and also returns its
+// combined stdout and stderr.
+func (cmd *Cmd) ExecOutput() ([]byte, int, error) {
+	cmd.output = new(bytes.Buffer)
+
+	exitCode, err := cmd.Exec()
+
+	if checkedEntry := cmd.logger.Check(zap.DebugLevel, "check for debug level before logging unix process output"); checkedEntry != nil {
+		for _, line := range strings.Split(cmd.output.String(), "\n") {
+			if line != "" {
+				cmd.logger.Debug(line)
+			}
+		}
+	}
+
+	return cmd.output.Bytes(), exitCode, err
+}
+
 // pipeOutput creates logs entries according to the process stdout and stderr.
 // It does nothing if the logging level is not debug.
 func (cmd *Cmd) pipeOutput() error {
+	// The output is captured, and logged once the process exits.
+	if cmd.output != nil {
+		cmd.process.Stdout = cmd.output
+		cmd.process.Stderr = cmd.output
+
+		return nil
+	}
+
 	checkedEntry := cmd.logger.Check(zap.DebugLevel, "check for debug level before piping unix process output")
 	if checkedEntry == nil {
 		return nil
@@ -183,6 +260,16 @@ func (cmd *Cmd) pipeOutput() error {
 	return nil
 }
 
+// Pid returns the identifier of the unix process, or 0 if the command has not
+// started.
+func (cmd *Cmd) Pid() int {
+	if cmd.process == nil || cmd.process.Process == nil {
+		return 0
+	}
+
+	return cmd.process.Process.Pid
+}
+
 // Kill kills the unix process and all its children without creating orphans.
 //
 // See https://medium.com/@felixge/killing-a-child-process-and-all-of-its-children-in-go-54079af94773.
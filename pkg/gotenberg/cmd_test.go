@@ -2,6 +2,7 @@ package gotenberg
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -52,6 +53,21 @@ func TestCommandContext(t *testing.T) {
 	}
 }
 
+func TestRedactArgs(t *testing.T) {
+	args := []string{"unoconverter", "--password", "secret", "--export", "PermissionPassword=owner=secret", "--export", "Quality=90", "--output", "foo.pdf", "--password"}
+
+	actual := strings.Join(redactArgs(args), " ")
+	expect := "unoconverter --password [REDACTED] --export PermissionPassword=[REDACTED] --export Quality=90 --output foo.pdf --password"
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+
+	if args[2] != "secret" {
+		t.Errorf("expected the arguments to be left untouched but got %v", args)
+	}
+}
+
 func TestCmd_Start(t *testing.T) {
 	tests := []struct {
 		scenario         string
@@ -200,6 +216,44 @@ func TestCmd_Exec(t *testing.T) {
 	}
 }
 
+func TestCmd_ExecOutput(t *testing.T) {
+	cmd, err := CommandContext(context.Background(), zap.NewNop(), "sh", "-c", "echo foo; echo bar >&2; exit 3")
+	if err != nil {
+		t.Fatalf("expected no error from CommandContext(), but got: %v", err)
+	}
+
+	output, exitCode, err := cmd.ExecOutput()
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3 but got %d", exitCode)
+	}
+
+	if string(output) != "foo\nbar\n" {
+		t.Errorf("expected output 'foo\\nbar\\n' but got '%s'", output)
+	}
+}
+
+func TestCmd_SetEnv(t *testing.T) {
+	cmd, err := CommandContext(context.Background(), zap.NewNop(), "sh", "-c", "echo \"$FOO\"")
+	if err != nil {
+		t.Fatalf("expected no error from CommandContext(), but got: %v", err)
+	}
+
+	cmd.SetEnv("FOO=bar")
+
+	output, _, err := cmd.ExecOutput()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if string(output) != "bar\n" {
+		t.Errorf("expected output 'bar\\n' but got '%s'", output)
+	}
+}
+
 func TestCmd_pipeOutput(t *testing.T) {
 	tests := []struct {
 		scenario              string
@@ -269,6 +323,26 @@ func TestCmd_pipeOutput(t *testing.T) {
 	}
 }
 
+func TestCmd_Pid(t *testing.T) {
+	cmd := Command(zap.NewNop(), "sleep", "60")
+	if cmd.Pid() != 0 {
+		t.Errorf("expected 0 as PID of a non-started command but got %d", cmd.Pid())
+	}
+
+	err := cmd.process.Start()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = cmd.Kill()
+	}()
+
+	if cmd.Pid() != cmd.process.Process.Pid {
+		t.Errorf("expected %d as PID but got %d", cmd.process.Process.Pid, cmd.Pid())
+	}
+}
+
 func TestCmd_Kill(t *testing.T) {
 	tests := []struct {
 		scenario string
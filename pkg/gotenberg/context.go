@@ -102,10 +102,18 @@ func (ctx *Context) Modules(kind interface{}) ([]interface{}, error) {
 // loadModule calls the Provision and/or Validate methods of the requested
 // module if it satisfies the [Provisioner] and/or [Validator] interfaces.
 func (ctx *Context) loadModule(id string, instance interface{}) error {
+	// The instance is registered before its provisioning, so that two modules
+	// which depend on each other, e.g., the API which gets the routes of a
+	// module which gets the metrics of the API, share the same instances
+	// instead of provisioning new ones endlessly.
+	ctx.moduleInstances[id] = instance
+
 	if prov, ok := instance.(Provisioner); ok {
 		// The instance can be provisioned.
 		err := prov.Provision(ctx)
 		if err != nil {
+			delete(ctx.moduleInstances, id)
+
 			return fmt.Errorf("provision module %s: %w", id, err)
 		}
 	}
@@ -114,11 +122,11 @@ func (ctx *Context) loadModule(id string, instance interface{}) error {
 		// The instance can be validated.
 		err := validator.Validate()
 		if err != nil {
+			delete(ctx.moduleInstances, id)
+
 			return fmt.Errorf("validate module %s: %w", id, err)
 		}
 	}
 
-	ctx.moduleInstances[id] = instance
-
 	return nil
 }
@@ -132,6 +132,39 @@ func TestContext_Modules(t *testing.T) {
 			kind:        new(Provisioner),
 			expectError: false,
 		},
+		{
+			scenario: "success (modules which depend on each other)",
+			mods: func() []ModuleDescriptor {
+				foo := &struct {
+					ModuleMock
+					ProvisionerMock
+				}{}
+				bar := &struct {
+					ModuleMock
+					ProvisionerMock
+					ValidatorMock
+				}{}
+				foo.DescriptorMock = func() ModuleDescriptor {
+					return ModuleDescriptor{ID: "foo", New: func() Module { return foo }}
+				}
+				foo.ProvisionMock = func(ctx *Context) error {
+					_, err := ctx.Modules(new(Validator))
+					return err
+				}
+				bar.DescriptorMock = func() ModuleDescriptor {
+					return ModuleDescriptor{ID: "bar", New: func() Module { return bar }}
+				}
+				bar.ProvisionMock = func(ctx *Context) error {
+					_, err := ctx.Modules(new(Provisioner))
+					return err
+				}
+				bar.ValidateMock = func() error { return nil }
+
+				return []ModuleDescriptor{foo.Descriptor(), bar.Descriptor()}
+			}(),
+			kind:        new(Provisioner),
+			expectError: false,
+		},
 		{
 			scenario: "success (one module)",
 			mods: func() []ModuleDescriptor {
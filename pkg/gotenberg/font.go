@@ -0,0 +1,153 @@
+package gotenberg
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/font/sfnt"
+)
+
+const (
+	// FontTrueType represents the TrueType font format.
+	FontTrueType string = "truetype"
+
+	// FontOpenType represents the OpenType font format, i.e., with
+	// PostScript outlines.
+	FontOpenType string = "opentype"
+)
+
+// ErrInvalidFont happens if a file is not a TrueType nor an OpenType font.
+var ErrInvalidFont = errors.New("invalid font")
+
+// Font gathers the names of a font file, as read from its name table.
+type Font struct {
+	// Path is the path of the font file.
+	Path string
+
+	// Format is either [FontTrueType] or [FontOpenType].
+	Format string
+
+	// Family is the typographic family of the font, e.g., "Corporate Sans",
+	// as used by CSS.
+	Family string
+
+	// Style is the typographic style of the font within its family, e.g.,
+	// "SemiBold Italic".
+	Style string
+
+	// LegacyFamily is the family of the font in the style-linking model of
+	// the word processors, e.g., "Corporate Sans SemiBold". It equals Family
+	// for the regular, bold, italic and bold italic styles.
+	LegacyFamily string
+
+	// LegacyStyle is the style of the font in the style-linking model of the
+	// word processors, i.e., either "Regular", "Bold", "Italic" or
+	// "Bold Italic".
+	LegacyStyle string
+
+	// Weight is the weight of the font, from 1 to 1000, as used by CSS,
+	// e.g., 400 for regular and 700 for bold.
+	Weight int
+
+	// Italic tells if the font is italic or oblique.
+	Italic bool
+}
+
+// os2Table reads the weight and the italic flag of a font from its OS/2
+// table. It returns false if the font has no such table.
+//
+// See https://learn.microsoft.com/en-us/typography/opentype/spec/os2.
+func os2Table(content []byte) (weight int, italic bool, ok bool) {
+	if len(content) < 12 {
+		return 0, false, false
+	}
+
+	numTables := int(binary.BigEndian.Uint16(content[4:6]))
+	for i := 0; i < numTables; i++ {
+		record := 12 + i*16
+		if record+16 > len(content) {
+			return 0, false, false
+		}
+
+		if string(content[record:record+4]) != "OS/2" {
+			continue
+		}
+
+		offset := int(binary.BigEndian.Uint32(content[record+8 : record+12]))
+		if offset+64 > len(content) {
+			return 0, false, false
+		}
+
+		weight = int(binary.BigEndian.Uint16(content[offset+4 : offset+6]))
+		italic = binary.BigEndian.Uint16(content[offset+62:offset+64])&1 == 1
+
+		return weight, italic, true
+	}
+
+	return 0, false, false
+}
+
+// ReadFont reads the names of a TrueType or OpenType font file. It returns
+// an [ErrInvalidFont] error if the file is not such a font.
+func ReadFont(path string) (Font, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Font{}, fmt.Errorf("read font: %w", err)
+	}
+
+	f, err := sfnt.Parse(content)
+	if err != nil {
+		return Font{}, fmt.Errorf("parse '%s': %v: %w", filepath.Base(path), err, ErrInvalidFont)
+	}
+
+	var buf sfnt.Buffer
+
+	name := func(ids ...sfnt.NameID) string {
+		for _, id := range ids {
+			value, err := f.Name(&buf, id)
+			if err == nil && value != "" {
+				return value
+			}
+		}
+
+		return ""
+	}
+
+	font := Font{
+		Path:         path,
+		Format:       FontTrueType,
+		Family:       name(sfnt.NameIDTypographicFamily, sfnt.NameIDFamily),
+		Style:        name(sfnt.NameIDTypographicSubfamily, sfnt.NameIDSubfamily),
+		LegacyFamily: name(sfnt.NameIDFamily),
+		LegacyStyle:  name(sfnt.NameIDSubfamily),
+	}
+
+	weight, italic, ok := os2Table(content)
+	if ok && weight >= 1 && weight <= 1000 {
+		font.Weight = weight
+		font.Italic = italic
+	} else {
+		style := strings.ToLower(font.LegacyStyle)
+
+		font.Weight = 400
+		if strings.Contains(style, "bold") {
+			font.Weight = 700
+		}
+		font.Italic = strings.Contains(style, "italic")
+	}
+
+	if font.Family == "" {
+		return Font{}, fmt.Errorf("'%s' has no family name: %w", filepath.Base(path), ErrInvalidFont)
+	}
+
+	// The CFF fonts start with the "OTTO" tag.
+	if strings.HasPrefix(string(content), "OTTO") {
+		font.Format = FontOpenType
+	}
+
+	return font, nil
+}
@@ -0,0 +1,93 @@
+package gotenberg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/gomedium"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestReadFont(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		content     []byte
+		expectFont  Font
+		expectError error
+	}{
+		{
+			scenario: "regular",
+			content:  goregular.TTF,
+			expectFont: Font{
+				Format:       FontTrueType,
+				Family:       "Go",
+				Style:        "Regular",
+				LegacyFamily: "Go",
+				LegacyStyle:  "Regular",
+				Weight:       400,
+			},
+		},
+		{
+			scenario: "bold italic",
+			content:  gobolditalic.TTF,
+			expectFont: Font{
+				Format:       FontTrueType,
+				Family:       "Go",
+				Style:        "Bold Italic",
+				LegacyFamily: "Go",
+				LegacyStyle:  "Bold Italic",
+				Weight:       600,
+				Italic:       true,
+			},
+		},
+		{
+			scenario: "medium",
+			content:  gomedium.TTF,
+			expectFont: Font{
+				Format:       FontTrueType,
+				Family:       "Go Medium",
+				Style:        "Regular",
+				LegacyFamily: "Go Medium",
+				LegacyStyle:  "Regular",
+				Weight:       500,
+			},
+		},
+		{
+			scenario:    "not a font",
+			content:     []byte("foo"),
+			expectError: ErrInvalidFont,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "font.ttf")
+
+			err := os.WriteFile(path, tc.content, 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			font, err := ReadFont(path)
+
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected error %v but got: %v", tc.expectError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			tc.expectFont.Path = path
+			if !reflect.DeepEqual(font, tc.expectFont) {
+				t.Errorf("expected %+v but got %+v", tc.expectFont, font)
+			}
+		})
+	}
+}
@@ -1,12 +1,21 @@
 package gotenberg
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 
 	"github.com/google/uuid"
 )
 
+// workingDirMarker is the file which marks a working directory as the one of
+// a Gotenberg process. It contains the hostname and the PID of this process.
+const workingDirMarker = ".gotenberg"
+
 // FileSystem provides utilities for managing temporary directories. It creates
 // unique directory names based on UUIDs to ensure isolation of temporary files
 // for different modules.
@@ -22,6 +31,37 @@ func NewFileSystem() *FileSystem {
 	}
 }
 
+// IsOrphanedWorkingDir reports whether the given directory is the working
+// directory of a Gotenberg process of the same host which is not running
+// anymore, e.g., after a crash. The directories without the marker of a
+// [FileSystem], or with the one of a process of another host, never are.
+func IsOrphanedWorkingDir(path string) bool {
+	b, err := os.ReadFile(filepath.Join(path, workingDirMarker))
+	if err != nil {
+		return false
+	}
+
+	hostname, rawPid, ok := strings.Cut(strings.TrimSpace(string(b)), "\n")
+	if !ok {
+		return false
+	}
+
+	pid, err := strconv.Atoi(rawPid)
+	if err != nil || pid <= 0 {
+		return false
+	}
+
+	currentHostname, err := os.Hostname()
+	if err != nil || hostname != currentHostname || pid == os.Getpid() {
+		return false
+	}
+
+	// A process which exists but belongs to another user is still running.
+	err = syscall.Kill(pid, syscall.Signal(0))
+
+	return errors.Is(err, syscall.ESRCH)
+}
+
 // WorkingDir returns the unique name of the working directory.
 func (fs *FileSystem) WorkingDir() string {
 	return fs.workingDir
@@ -39,8 +79,9 @@ func (fs *FileSystem) NewDirPath() string {
 	return fmt.Sprintf("%s/%s", fs.WorkingDirPath(), uuid.NewString())
 }
 
-// MkdirAll creates a new unique directory inside the working directory and
-// returns its path. If the directory creation fails, an error is returned.
+// MkdirAll creates a new unique directory inside the working directory, which
+// it marks as the one of the current process, and returns its path. If the
+// directory creation fails, an error is returned.
 func (fs *FileSystem) MkdirAll() (string, error) {
 	path := fs.NewDirPath()
 
@@ -49,5 +90,22 @@ func (fs *FileSystem) MkdirAll() (string, error) {
 		return "", fmt.Errorf("create directory %s: %w", path, err)
 	}
 
+	// The marker tells the other Gotenberg processes of this host who owns
+	// the working directory, see IsOrphanedWorkingDir.
+	markerPath := filepath.Join(fs.WorkingDirPath(), workingDirMarker)
+
+	_, err = os.Stat(markerPath)
+	if errors.Is(err, os.ErrNotExist) {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return "", fmt.Errorf("get hostname: %w", err)
+		}
+
+		err = os.WriteFile(markerPath, []byte(fmt.Sprintf("%s\n%d\n", hostname, os.Getpid())), 0o600)
+		if err != nil {
+			return "", fmt.Errorf("create working directory marker: %w", err)
+		}
+	}
+
 	return path, nil
 }
@@ -48,8 +48,70 @@ func TestFileSystem_MkdirAll(t *testing.T) {
 		t.Errorf("expected directory '%s' to exist but it doesn't", newPath)
 	}
 
+	_, err = os.Stat(fmt.Sprintf("%s/%s", fs.WorkingDirPath(), workingDirMarker))
+	if err != nil {
+		t.Errorf("expected the working directory marker to exist but got: %v", err)
+	}
+
+	if IsOrphanedWorkingDir(fs.WorkingDirPath()) {
+		t.Error("expected the working directory of the current process not to be orphaned")
+	}
+
 	err = os.RemoveAll(fs.WorkingDirPath())
 	if err != nil {
 		t.Fatalf("expected no error while cleaning up but got: %v", err)
 	}
 }
+
+func TestIsOrphanedWorkingDir(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	// A PID above the maximum of Linux, i.e., of no process.
+	const deadPid = 1 << 23
+
+	for _, tc := range []struct {
+		scenario       string
+		marker         string
+		expectOrphaned bool
+	}{
+		{
+			scenario: "no marker",
+		},
+		{
+			scenario: "invalid marker",
+			marker:   "foo",
+		},
+		{
+			scenario: "current process",
+			marker:   fmt.Sprintf("%s\n%d\n", hostname, os.Getpid()),
+		},
+		{
+			scenario: "process of another host",
+			marker:   fmt.Sprintf("%s-foo\n%d\n", hostname, deadPid),
+		},
+		{
+			scenario:       "process not running anymore",
+			marker:         fmt.Sprintf("%s\n%d\n", hostname, deadPid),
+			expectOrphaned: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+
+			if tc.marker != "" {
+				err := os.WriteFile(fmt.Sprintf("%s/%s", dirPath, workingDirMarker), []byte(tc.marker), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			orphaned := IsOrphanedWorkingDir(dirPath)
+			if orphaned != tc.expectOrphaned {
+				t.Errorf("expected orphaned %t but got %t", tc.expectOrphaned, orphaned)
+			}
+		})
+	}
+}
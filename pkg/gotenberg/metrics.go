@@ -11,8 +11,17 @@ type Metric struct {
 	Description string
 
 	// Read returns the current value.
-	// Required.
+	// Required, unless Label is set.
 	Read func() float64
+
+	// Label is the name of the label of a metric with many values, e.g.,
+	// "route".
+	// Optional.
+	Label string
+
+	// ReadByLabel returns the current values, by value of the label.
+	// Required if Label is set.
+	ReadByLabel func() map[string]float64
 }
 
 // MetricsProvider is a module interface which provides a list of [Metric].
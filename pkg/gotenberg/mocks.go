@@ -2,6 +2,7 @@ package gotenberg
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -35,8 +36,22 @@ func (mod *ValidatorMock) Validate() error {
 
 // PdfEngineMock is a mock for the [PdfEngine] interface.
 type PdfEngineMock struct {
-	MergeMock   func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error
-	ConvertMock func(ctx context.Context, logger *zap.Logger, formats PdfFormats, inputPath, outputPath string) error
+	MergeMock            func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error
+	ConvertMock          func(ctx context.Context, logger *zap.Logger, formats PdfFormats, inputPath, outputPath string) error
+	DecryptMock          func(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error
+	ExtractTextMock      func(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error
+	OcrMock              func(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error
+	NormalizeMock        func(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error
+	SetOutlineMock       func(ctx context.Context, logger *zap.Logger, entries []PdfOutlineEntry, replace bool, inputPath, outputPath string) error
+	ReadOutlineMock      func(ctx context.Context, logger *zap.Logger, inputPath string) (PdfOutline, error)
+	RedactMock           func(ctx context.Context, logger *zap.Logger, redactions []PdfRedaction, inputPath, outputPath string) error
+	StampPageNumbersMock func(ctx context.Context, logger *zap.Logger, numbers PdfPageNumbers, inputPath, outputPath string) error
+	CropMock             func(ctx context.Context, logger *zap.Logger, crop PdfCrop, inputPath, outputPath string) error
+	ImportImagesMock     func(ctx context.Context, logger *zap.Logger, images []PdfImage, outputPath string) error
+	CheckMock            func(ctx context.Context, logger *zap.Logger, inputPath string) (PdfCheckReport, error)
+	RepairMock           func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error
+	RasterizeMock        func(ctx context.Context, logger *zap.Logger, raster PdfRaster, inputPath, outputPathPrefix string) ([]string, error)
+	SplitPagesMock       func(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error)
 }
 
 func (engine *PdfEngineMock) Merge(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
@@ -47,6 +62,62 @@ func (engine *PdfEngineMock) Convert(ctx context.Context, logger *zap.Logger, fo
 	return engine.ConvertMock(ctx, logger, formats, inputPath, outputPath)
 }
 
+func (engine *PdfEngineMock) Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error {
+	return engine.DecryptMock(ctx, logger, password, inputPath, outputPath)
+}
+
+func (engine *PdfEngineMock) ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+	return engine.ExtractTextMock(ctx, logger, firstPage, lastPage, inputPath, outputPath)
+}
+
+func (engine *PdfEngineMock) Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error {
+	return engine.OcrMock(ctx, logger, languages, inputPath, outputPath)
+}
+
+func (engine *PdfEngineMock) Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+	return engine.NormalizeMock(ctx, logger, date, inputPath, outputPath)
+}
+
+func (engine *PdfEngineMock) SetOutline(ctx context.Context, logger *zap.Logger, entries []PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+	return engine.SetOutlineMock(ctx, logger, entries, replace, inputPath, outputPath)
+}
+
+func (engine *PdfEngineMock) ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (PdfOutline, error) {
+	return engine.ReadOutlineMock(ctx, logger, inputPath)
+}
+
+func (engine *PdfEngineMock) Redact(ctx context.Context, logger *zap.Logger, redactions []PdfRedaction, inputPath, outputPath string) error {
+	return engine.RedactMock(ctx, logger, redactions, inputPath, outputPath)
+}
+
+func (engine *PdfEngineMock) StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers PdfPageNumbers, inputPath, outputPath string) error {
+	return engine.StampPageNumbersMock(ctx, logger, numbers, inputPath, outputPath)
+}
+
+func (engine *PdfEngineMock) Crop(ctx context.Context, logger *zap.Logger, crop PdfCrop, inputPath, outputPath string) error {
+	return engine.CropMock(ctx, logger, crop, inputPath, outputPath)
+}
+
+func (engine *PdfEngineMock) ImportImages(ctx context.Context, logger *zap.Logger, images []PdfImage, outputPath string) error {
+	return engine.ImportImagesMock(ctx, logger, images, outputPath)
+}
+
+func (engine *PdfEngineMock) Check(ctx context.Context, logger *zap.Logger, inputPath string) (PdfCheckReport, error) {
+	return engine.CheckMock(ctx, logger, inputPath)
+}
+
+func (engine *PdfEngineMock) Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	return engine.RepairMock(ctx, logger, inputPath, outputPath)
+}
+
+func (engine *PdfEngineMock) Rasterize(ctx context.Context, logger *zap.Logger, raster PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+	return engine.RasterizeMock(ctx, logger, raster, inputPath, outputPathPrefix)
+}
+
+func (engine *PdfEngineMock) SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	return engine.SplitPagesMock(ctx, logger, inputPath, outputPathPrefix)
+}
+
 // PdfEngineProviderMock is a mock for the [PdfEngineProvider] interface.
 type PdfEngineProviderMock struct {
 	PdfEngineMock func() (PdfEngine, error)
@@ -75,6 +146,16 @@ func (p *ProcessMock) Healthy(logger *zap.Logger) bool {
 	return p.HealthyMock(logger)
 }
 
+// RecyclableProcessMock is a mock for the [RecyclableProcess] interface.
+type RecyclableProcessMock struct {
+	ProcessMock
+	RestartReasonMock func(logger *zap.Logger) string
+}
+
+func (p *RecyclableProcessMock) RestartReason(logger *zap.Logger) string {
+	return p.RestartReasonMock(logger)
+}
+
 // ProcessSupervisorMock is a mock for the [ProcessSupervisor] interface.
 type ProcessSupervisorMock struct {
 	LaunchMock        func() error
@@ -134,6 +215,7 @@ var (
 	_ PdfEngine         = (*PdfEngineMock)(nil)
 	_ PdfEngineProvider = (*PdfEngineProviderMock)(nil)
 	_ Process           = (*ProcessMock)(nil)
+	_ RecyclableProcess = (*RecyclableProcessMock)(nil)
 	_ ProcessSupervisor = (*ProcessSupervisorMock)(nil)
 	_ LoggerProvider    = (*LoggerProviderMock)(nil)
 	_ MetricsProvider   = (*MetricsProviderMock)(nil)
@@ -3,6 +3,10 @@ package gotenberg
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -15,6 +19,26 @@ var (
 	// ErrPdfFormatNotSupported is returned when the Convert method of the
 	// PdfEngine interface does not support a requested PDF format conversion.
 	ErrPdfFormatNotSupported = errors.New("PDF format not supported")
+
+	// ErrPdfInvalidPassword is returned when the Decrypt method of the
+	// PdfEngine interface cannot open a PDF with the given password.
+	ErrPdfInvalidPassword = errors.New("invalid PDF password")
+
+	// ErrOcrLanguagesNotInstalled is returned when the Ocr method of the
+	// PdfEngine interface does not have the data of a requested language.
+	ErrOcrLanguagesNotInstalled = errors.New("OCR languages not installed")
+
+	// ErrPdfRedactionTextNotFound is returned when the Redact method of the
+	// PdfEngine interface does not find a requested text in a PDF.
+	ErrPdfRedactionTextNotFound = errors.New("redaction text not found")
+
+	// ErrPdfPageSizeNotSupported is returned when the ImportImages method of
+	// the PdfEngine interface does not know a requested page size.
+	ErrPdfPageSizeNotSupported = errors.New("page size not supported")
+
+	// ErrPdfNotRepairable is returned when the Repair method of the
+	// PdfEngine interface cannot fix a PDF.
+	ErrPdfNotRepairable = errors.New("PDF not repairable")
 )
 
 const (
@@ -53,6 +77,255 @@ type PdfFormats struct {
 	PdfUa bool
 }
 
+// PdfOutlineEntry is an entry of a PDF outline, also known as a bookmark.
+type PdfOutlineEntry struct {
+	// Title is the text of the entry.
+	Title string `json:"title"`
+
+	// Page is the target page number of the entry, starting from 1.
+	Page int `json:"page"`
+
+	// Children are the nested entries.
+	Children []PdfOutlineEntry `json:"children,omitempty"`
+}
+
+// PdfOutline is the outline of a PDF, along with its number of pages.
+type PdfOutline struct {
+	// PageCount is the number of pages of the PDF.
+	PageCount int
+
+	// Entries are the top-level entries of the outline.
+	Entries []PdfOutlineEntry
+}
+
+// PdfOutlineOutOfRangeError is returned when the SetOutline method of the
+// PdfEngine interface receives entries which target pages outside the PDF.
+type PdfOutlineOutOfRangeError struct {
+	// PageCount is the number of pages of the PDF.
+	PageCount int
+
+	// Entries are the entries out of range, e.g., "'Annex' (page 12)".
+	Entries []string
+}
+
+// Error implements the error interface.
+func (e *PdfOutlineOutOfRangeError) Error() string {
+	return fmt.Sprintf("outline entries out of range (%d pages): %s", e.PageCount, strings.Join(e.Entries, ", "))
+}
+
+// PdfRedaction is an area of a PDF to redact, either a rectangle or every
+// occurrence of a text.
+type PdfRedaction struct {
+	// Page is the page number of the area, starting from 1. For a text, 0
+	// means all pages.
+	Page int `json:"page"`
+
+	// X and Y are the coordinates, in points, of the top-left corner of the
+	// rectangle, from the top-left corner of the page.
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+
+	// Width and Height are the dimensions, in points, of the rectangle.
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+
+	// Text, if set, replaces the rectangle with the areas of its occurrences.
+	Text string `json:"text,omitempty"`
+}
+
+// PdfRedactionOutOfBoundsError is returned when the Redact method of the
+// PdfEngine interface receives areas which are not within their page.
+type PdfRedactionOutOfBoundsError struct {
+	// Entries are the areas out of bounds, e.g., "page 2: [0 0 700 20] not
+	// within [0 0 612 792]".
+	Entries []string
+}
+
+// Error implements the error interface.
+func (e *PdfRedactionOutOfBoundsError) Error() string {
+	return fmt.Sprintf("redaction areas out of bounds: %s", strings.Join(e.Entries, ", "))
+}
+
+// PdfPageRange is a range of pages, starting from 1.
+type PdfPageRange struct {
+	// First is the first page of the range.
+	First int `json:"first"`
+
+	// Last is the last page of the range, or 0 for up to the last page of the
+	// PDF.
+	Last int `json:"last"`
+}
+
+// Contains tells if a page is within the range.
+func (r PdfPageRange) Contains(page int) bool {
+	return page >= r.First && (r.Last == 0 || page <= r.Last)
+}
+
+// PdfPageNumbers describes the page numbers to stamp on the pages of a PDF.
+type PdfPageNumbers struct {
+	// Format is the text of a page number. The "{page}" placeholder is
+	// replaced by the number of the page, and "{total}" by the number of the
+	// last numbered page.
+	Format string
+
+	// Position is where to stamp the page numbers, either "top-left",
+	// "top-center", "top-right", "bottom-left", "bottom-center" or
+	// "bottom-right".
+	Position string
+
+	// StartAt is the number of the first numbered page.
+	StartAt int
+
+	// ExcludedPages are the pages without number. They do not count in the
+	// numbering, e.g., the second page of a PDF with its cover page excluded
+	// has the number StartAt.
+	ExcludedPages []PdfPageRange
+}
+
+// Text returns the text of a page number.
+func (n PdfPageNumbers) Text(page, total int) string {
+	return strings.NewReplacer(
+		"{page}", strconv.Itoa(page),
+		"{total}", strconv.Itoa(total),
+	).Replace(n.Format)
+}
+
+// Excluded tells if a page has no number.
+func (n PdfPageNumbers) Excluded(page int) bool {
+	for _, r := range n.ExcludedPages {
+		if r.Contains(page) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PdfCropBox is the area of a page to keep.
+type PdfCropBox struct {
+	// Page is the page number of the area, starting from 1.
+	Page int `json:"page"`
+
+	// X and Y are the coordinates, in points, of the top-left corner of the
+	// area, from the top-left corner of the page.
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+
+	// Width and Height are the dimensions, in points, of the area.
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// PdfCrop describes how to crop the pages of a PDF, either with explicit
+// areas, by trimming their whitespace margins, or by removing a uniform
+// margin.
+type PdfCrop struct {
+	// Boxes are the areas to keep, at most one per page. The other pages are
+	// not cropped.
+	Boxes []PdfCropBox `json:"boxes,omitempty"`
+
+	// AutoTrim trims the whitespace margins of the pages.
+	AutoTrim bool `json:"autoTrim"`
+
+	// Margin is the margin, in points, removed from each edge of the pages.
+	// With AutoTrim, it is the whitespace kept around the content instead.
+	Margin float64 `json:"margin"`
+
+	// Pages limits AutoTrim and Margin to some pages. Empty means all pages.
+	Pages []PdfPageRange `json:"pages,omitempty"`
+}
+
+// PdfCropOutOfBoundsError is returned when the Crop method of the PdfEngine
+// interface receives areas which are not within the media box of their page.
+type PdfCropOutOfBoundsError struct {
+	// Entries are the areas out of bounds, e.g., "page 2: [0 0 700 20] not
+	// within [0 0 612 792]".
+	Entries []string
+}
+
+// Error implements the error interface.
+func (e *PdfCropOutOfBoundsError) Error() string {
+	return fmt.Sprintf("crop areas out of bounds: %s", strings.Join(e.Entries, ", "))
+}
+
+const (
+	// PdfImageFitContain scales an image to fit its page, keeping its aspect
+	// ratio, and centers it.
+	PdfImageFitContain string = "contain"
+
+	// PdfImageFitNone centers an image on its page without scaling it, one
+	// pixel being one point.
+	PdfImageFitNone string = "none"
+)
+
+// PdfImage is an image to import as a page of a PDF.
+type PdfImage struct {
+	// Path is the path of the image.
+	Path string
+
+	// PageSize is the paper size of the page, e.g., "A4" or "Letter". Empty
+	// means the page takes the dimensions of the image, which then fills it.
+	PageSize string
+
+	// Fit is how the image fits a page with a paper size, either
+	// [PdfImageFitContain] or [PdfImageFitNone]. Empty means
+	// [PdfImageFitContain].
+	Fit string
+}
+
+const (
+	// PdfRasterPng renders the pages of a PDF as PNG images.
+	PdfRasterPng string = "png"
+
+	// PdfRasterJpeg renders the pages of a PDF as JPEG images.
+	PdfRasterJpeg string = "jpeg"
+)
+
+// PdfRaster gathers the options to render the pages of a PDF as images.
+type PdfRaster struct {
+	// Format is the format of the images, either [PdfRasterPng] or
+	// [PdfRasterJpeg].
+	Format string
+
+	// Dpi is the resolution of the images.
+	Dpi int
+}
+
+const (
+	// PdfCheckValid means a PDF has neither errors nor warnings.
+	PdfCheckValid string = "valid"
+
+	// PdfCheckValidWithWarnings means a PDF has no errors, but some
+	// recoverable issues, e.g., a damaged cross-reference table.
+	PdfCheckValidWithWarnings string = "valid_with_warnings"
+
+	// PdfCheckInvalid means a PDF has errors.
+	PdfCheckInvalid string = "invalid"
+)
+
+// PdfCheckReport is the result of the check of the structure of a PDF.
+type PdfCheckReport struct {
+	// Errors are the issues which make the PDF invalid.
+	Errors []string
+
+	// Warnings are the recoverable issues of the PDF.
+	Warnings []string
+}
+
+// Status returns either [PdfCheckValid], [PdfCheckValidWithWarnings] or
+// [PdfCheckInvalid].
+func (report PdfCheckReport) Status() string {
+	if len(report.Errors) > 0 {
+		return PdfCheckInvalid
+	}
+
+	if len(report.Warnings) > 0 {
+		return PdfCheckValidWithWarnings
+	}
+
+	return PdfCheckValid
+}
+
 // PdfEngine provides an interface for operations on PDFs. Implementations
 // can utilize various tools like PDFtk, or implement functionality directly in
 // Go.
@@ -64,6 +337,79 @@ type PdfEngine interface {
 	// Convert transforms a given PDF to the specified formats defined in
 	// PdfFormats. If no format, it does nothing.
 	Convert(ctx context.Context, logger *zap.Logger, formats PdfFormats, inputPath, outputPath string) error
+
+	// Decrypt removes the encryption of a given PDF, opening it with the
+	// given password. Implementations must not log the password.
+	Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error
+
+	// ExtractText writes the UTF-8 text of a given PDF to the output path,
+	// with pages separated by a form feed character. The firstPage and
+	// lastPage arguments limit the extraction to a range of pages; 0 means
+	// no limit. A PDF without text (e.g., a scanned document) results in
+	// empty pages.
+	ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error
+
+	// Ocr adds a searchable text layer to a given PDF, using the given
+	// languages (e.g., "eng"), without altering its appearance. Pages which
+	// already contain text are left untouched.
+	Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error
+
+	// Normalize rewrites a given PDF so that identical inputs result in
+	// byte-identical outputs: the creation and modification dates are set
+	// to the given date, and the file identifier is derived from the
+	// content.
+	Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error
+
+	// SetOutline writes the given outline to a given PDF. If replace is
+	// false, the entries are merged with the existing outline, if any. It
+	// returns a [PdfOutlineOutOfRangeError] if entries target pages outside
+	// the PDF.
+	SetOutline(ctx context.Context, logger *zap.Logger, entries []PdfOutlineEntry, replace bool, inputPath, outputPath string) error
+
+	// ReadOutline returns the outline of a given PDF, which is empty if the
+	// PDF has none.
+	ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (PdfOutline, error)
+
+	// Redact removes the content (i.e., text, images and vector graphics)
+	// under the given areas of a given PDF and paints them black. Unlike a
+	// visual cover, the removed content cannot be recovered from the
+	// resulting PDF. It returns a [PdfRedactionOutOfBoundsError] if areas
+	// are not within their page, and [ErrPdfRedactionTextNotFound] if a text
+	// has no occurrence.
+	Redact(ctx context.Context, logger *zap.Logger, redactions []PdfRedaction, inputPath, outputPath string) error
+
+	// StampPageNumbers writes the given page numbers on top of the pages of a
+	// given PDF.
+	StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers PdfPageNumbers, inputPath, outputPath string) error
+
+	// Crop sets the crop box of the pages of a given PDF. It returns a
+	// [PdfCropOutOfBoundsError] if areas are not within the media box of
+	// their page.
+	Crop(ctx context.Context, logger *zap.Logger, crop PdfCrop, inputPath, outputPath string) error
+
+	// ImportImages creates a PDF with one page per image, in the given
+	// order. It returns [ErrPdfPageSizeNotSupported] if a page size is
+	// unknown.
+	ImportImages(ctx context.Context, logger *zap.Logger, images []PdfImage, outputPath string) error
+
+	// Check verifies the structure of a given PDF. An invalid PDF is not an
+	// error: the issues are in the report.
+	Check(ctx context.Context, logger *zap.Logger, inputPath string) (PdfCheckReport, error)
+
+	// Repair rewrites a given PDF, fixing its recoverable issues (e.g., a
+	// damaged cross-reference table). It returns [ErrPdfNotRepairable] if
+	// the PDF cannot be fixed.
+	Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error
+
+	// Rasterize renders each page of a given PDF as an image. The images
+	// share the given path prefix, and are returned in the order of the
+	// pages.
+	Rasterize(ctx context.Context, logger *zap.Logger, raster PdfRaster, inputPath, outputPathPrefix string) ([]string, error)
+
+	// SplitPages writes each page of a given PDF to its own PDF. The PDFs
+	// share the given path prefix, and are returned in the order of the
+	// pages.
+	SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error)
 }
 
 // PdfEngineProvider offers an interface to instantiate a [PdfEngine].
@@ -33,6 +33,16 @@ type Process interface {
 	Healthy(logger *zap.Logger) bool
 }
 
+// RecyclableProcess is a [Process] which may ask the [ProcessSupervisor] to
+// restart it even though it is healthy, e.g., after a given uptime.
+type RecyclableProcess interface {
+	Process
+
+	// RestartReason returns the reason why the process should restart before
+	// handling the next task, or an empty string if it should not.
+	RestartReason(logger *zap.Logger) string
+}
+
 // ProcessSupervisor provides methods to manage a [Process], including
 // starting, stopping, and ensuring its health.
 //
@@ -211,6 +221,21 @@ func (s *processSupervisor) Run(ctx context.Context, logger *zap.Logger, task fu
 					}
 				}
 
+				recyclable, ok := s.process.(RecyclableProcess)
+				if ok {
+					reason := recyclable.RestartReason(s.logger)
+					if reason != "" {
+						// The lock guarantees that no task is running.
+						s.logger.Info(fmt.Sprintf("%s, restarting...", reason))
+						err := s.runWithDeadline(ctx, func() error {
+							return s.restart()
+						})
+						if err != nil {
+							return fmt.Errorf("process restart before task: %w", err)
+						}
+					}
+				}
+
 				// Note: no error wrapping because it leaks on Chromium console exceptions output.
 				return s.runWithDeadline(ctx, task)
 			case <-ctx.Done():
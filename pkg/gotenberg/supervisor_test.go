@@ -242,6 +242,7 @@ func TestProcessSupervisor_Run(t *testing.T) {
 		startError           error
 		processHealthy       bool
 		maxReqLimit          int64
+		restartReason        string
 		tasksToRun           int
 		taskError            error
 		expectError          bool
@@ -335,6 +336,19 @@ func TestProcessSupervisor_Run(t *testing.T) {
 			expectedHealthyCalls: 2,
 			expectedStopCalls:    1,
 		},
+		{
+			scenario:             "run task with recyclable process causing restart",
+			initiallyStarted:     true,
+			isRestarting:         false,
+			processHealthy:       true,
+			maxReqLimit:          0,
+			restartReason:        "uptime exceeded",
+			tasksToRun:           1,
+			expectError:          false,
+			expectedStartCalls:   1,
+			expectedHealthyCalls: 1,
+			expectedStopCalls:    1,
+		},
 		{
 			scenario:             "task error",
 			initiallyStarted:     true,
@@ -372,7 +386,17 @@ func TestProcessSupervisor_Run(t *testing.T) {
 				},
 			}
 
-			ps := NewProcessSupervisor(logger, process, tc.maxReqLimit).(*processSupervisor)
+			var supervised Process = process
+			if tc.restartReason != "" {
+				supervised = &RecyclableProcessMock{
+					ProcessMock: *process,
+					RestartReasonMock: func(logger *zap.Logger) string {
+						return tc.restartReason
+					},
+				}
+			}
+
+			ps := NewProcessSupervisor(logger, supervised, tc.maxReqLimit).(*processSupervisor)
 			if tc.initiallyStarted {
 				ps.firstStart.Store(true)
 			}
@@ -0,0 +1,55 @@
+package gotenberg
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation name of the spans created by Gotenberg.
+const tracerName = "github.com/gotenberg/gotenberg/v8"
+
+// StartSpan starts a span for an operation of an engine, e.g., a Chromium
+// conversion or a PDF engine merge. The span is a child of the span carried
+// by the given context, if any. Unless a module registers a tracer provider,
+// the span is a no-op.
+//
+//	ctx, span := gotenberg.StartSpan(ctx, "chromium.pdf", "chromium", 1)
+//	err := doSomething(ctx)
+//	gotenberg.EndSpan(span, outputPath, err)
+func StartSpan(ctx context.Context, name, engine string, inputCount int) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			attribute.String("gotenberg.engine", engine),
+			attribute.Int("gotenberg.input_count", inputCount),
+		),
+	)
+}
+
+// EndSpan records either the error of an operation or the size of its
+// output, then ends the span. Operations without a single output file, e.g.,
+// a PDF check, give an empty output path, so that no size is recorded.
+func EndSpan(span trace.Span, outputPath string, err error) {
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		return
+	}
+
+	if outputPath == "" {
+		return
+	}
+
+	stat, statErr := os.Stat(outputPath)
+	if statErr == nil {
+		span.SetAttributes(attribute.Int64("gotenberg.output_size", stat.Size()))
+	}
+}
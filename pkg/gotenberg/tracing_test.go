@@ -0,0 +1,85 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartSpan_EndSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	outputPath := filepath.Join(t.TempDir(), "foo.pdf")
+	err := os.WriteFile(outputPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario         string
+		outputPath       string
+		err              error
+		expectStatus     codes.Code
+		expectOutputSize bool
+	}{
+		{
+			scenario:         "success",
+			outputPath:       outputPath,
+			err:              nil,
+			expectStatus:     codes.Unset,
+			expectOutputSize: true,
+		},
+		{
+			scenario:         "error",
+			outputPath:       outputPath,
+			err:              errors.New("foo"),
+			expectStatus:     codes.Error,
+			expectOutputSize: false,
+		},
+		{
+			scenario:         "success without output path",
+			outputPath:       "",
+			err:              nil,
+			expectStatus:     codes.Unset,
+			expectOutputSize: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			_, span := StartSpan(context.Background(), "foo", "bar", 2)
+			EndSpan(span, tc.outputPath, tc.err)
+
+			spans := recorder.Ended()
+			ended := spans[len(spans)-1]
+
+			if ended.Status().Code != tc.expectStatus {
+				t.Errorf("expected status '%s' but got '%s'", tc.expectStatus, ended.Status().Code)
+			}
+
+			attributes := make(map[string]int64)
+			for _, attr := range ended.Attributes() {
+				attributes[string(attr.Key)] = attr.Value.AsInt64()
+			}
+
+			if attributes["gotenberg.input_count"] != 2 {
+				t.Errorf("expected input count 2 but got %d", attributes["gotenberg.input_count"])
+			}
+
+			size, ok := attributes["gotenberg.output_size"]
+			if tc.expectOutputSize && size != 3 {
+				t.Errorf("expected output size 3 but got %d", size)
+			}
+
+			if !tc.expectOutputSize && ok {
+				t.Error("expected no output size")
+			}
+		})
+	}
+}
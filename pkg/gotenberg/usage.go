@@ -0,0 +1,304 @@
+package gotenberg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// clockTicksPerSecond is the unit of the CPU times in the /proc filesystem,
+// i.e., USER_HZ, which is 100 on all the architectures Linux supports.
+const clockTicksPerSecond = 100
+
+// usageSampleInterval is the interval between two samples of the memory of a
+// process tree.
+const usageSampleInterval = time.Duration(100) * time.Millisecond
+
+// ProcessIdentifier is an optional interface of a [Process] which tells the
+// PID of its root unix process, so that the resource usage of its process
+// tree may be measured.
+type ProcessIdentifier interface {
+	// Pid returns the PID of the root unix process, or 0 if the process is
+	// not running.
+	Pid() int
+}
+
+// ProcessUsage is the resource usage of a process tree during a task.
+type ProcessUsage struct {
+	// CpuTime is the user and system CPU time of the process tree.
+	CpuTime time.Duration
+
+	// PeakRss is the highest resident set size, in bytes, of the process
+	// tree.
+	PeakRss int64
+}
+
+// processStat gathers the fields of a /proc/[pid]/stat file we are
+// interested in.
+type processStat struct {
+	ppid     int
+	cpuTicks int64
+	rssPages int64
+}
+
+// readProcessStats reads the stat files of all the unix processes. It relies
+// on the /proc filesystem.
+func readProcessStats() (map[int]processStat, error) {
+	statPaths, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil {
+		return nil, fmt.Errorf("list processes: %w", err)
+	}
+
+	stats := make(map[int]processStat, len(statPaths))
+	for _, statPath := range statPaths {
+		pid, err := strconv.Atoi(filepath.Base(filepath.Dir(statPath)))
+		if err != nil {
+			continue
+		}
+
+		b, err := os.ReadFile(statPath)
+		if err != nil {
+			// The process has likely exited in the meantime.
+			continue
+		}
+
+		// The second field, i.e., the executable name, may contain spaces;
+		// the fields we are interested in come after it.
+		stat := string(b)
+		fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+		if len(fields) < 22 {
+			continue
+		}
+
+		// See https://man7.org/linux/man-pages/man5/proc_pid_stat.5.html.
+		// The CPU times include the ones of the children the process has
+		// waited for, e.g., the exited renderers of Chromium.
+		var (
+			values [6]int64
+			parsed = true
+		)
+		for i, index := range []int{1, 11, 12, 13, 14, 21} {
+			values[i], err = strconv.ParseInt(fields[index], 10, 64)
+			if err != nil {
+				parsed = false
+				break
+			}
+		}
+
+		if !parsed {
+			continue
+		}
+
+		stats[pid] = processStat{
+			ppid:     int(values[0]),
+			cpuTicks: values[1] + values[2] + values[3] + values[4],
+			rssPages: values[5],
+		}
+	}
+
+	return stats, nil
+}
+
+// processTreeStat returns the CPU time, in clock ticks, and the resident set
+// size, in bytes, of the process tree with the given root PID.
+func processTreeStat(pid int) (int64, int64, error) {
+	stats, err := readProcessStats()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if _, ok := stats[pid]; !ok {
+		return 0, 0, fmt.Errorf("process %d not found", pid)
+	}
+
+	children := make(map[int][]int, len(stats))
+	for childPid, stat := range stats {
+		children[stat.ppid] = append(children[stat.ppid], childPid)
+	}
+
+	var cpuTicks, rssPages int64
+	queue := []int{pid}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		cpuTicks += stats[current].cpuTicks
+		rssPages += stats[current].rssPages
+		queue = append(queue, children[current]...)
+	}
+
+	return cpuTicks, rssPages * int64(os.Getpagesize()), nil
+}
+
+// MeasureProcessTree runs a task and returns the resource usage of the
+// process tree with the given root PID in the meantime, alongside the error
+// of the task. The measure is a best effort: if the process tree is shared,
+// e.g., a long-running Chromium, the usage includes its background work and
+// its idle memory, and if the /proc filesystem is not available, the usage
+// is empty.
+func MeasureProcessTree(pid int, task func() error) (ProcessUsage, error) {
+	if pid <= 0 {
+		return ProcessUsage{}, task()
+	}
+
+	startTicks, peakRss, err := processTreeStat(pid)
+	if err != nil {
+		return ProcessUsage{}, task()
+	}
+
+	var (
+		mu   sync.Mutex
+		done = make(chan struct{})
+		wg   sync.WaitGroup
+	)
+
+	updatePeak := func(rss int64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if rss > peakRss {
+			peakRss = rss
+		}
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(usageSampleInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_, rss, err := processTreeStat(pid)
+				if err == nil {
+					updatePeak(rss)
+				}
+			}
+		}
+	}()
+
+	taskErr := task()
+
+	close(done)
+	wg.Wait()
+
+	usage := ProcessUsage{PeakRss: peakRss}
+
+	endTicks, rss, err := processTreeStat(pid)
+	if err == nil {
+		if rss > usage.PeakRss {
+			usage.PeakRss = rss
+		}
+
+		if endTicks > startTicks {
+			usage.CpuTime = time.Duration(endTicks-startTicks) * time.Second / clockTicksPerSecond
+		}
+	}
+
+	return usage, taskErr
+}
+
+type routeContextKey struct{}
+
+// WithRoute returns a copy of the context with the path of the route which
+// handles the request, so that the engines may attribute their resource
+// usage to this route.
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeContextKey{}, route)
+}
+
+// RouteFromContext returns the path of the route set by [WithRoute], or an
+// empty string.
+func RouteFromContext(ctx context.Context) string {
+	route, _ := ctx.Value(routeContextKey{}).(string)
+	return route
+}
+
+// UsageRecorder accumulates the resource usage of the processes of an
+// engine, by route.
+type UsageRecorder struct {
+	mu         sync.RWMutex
+	cpuSeconds map[string]float64
+	peakRss    map[string]float64
+}
+
+// NewUsageRecorder creates a [UsageRecorder].
+func NewUsageRecorder() *UsageRecorder {
+	return &UsageRecorder{
+		cpuSeconds: make(map[string]float64),
+		peakRss:    make(map[string]float64),
+	}
+}
+
+// Measure runs a task thanks to [MeasureProcessTree] if the process
+// implements [ProcessIdentifier], then logs the resource usage and records
+// it for the route of the context, if any.
+func (recorder *UsageRecorder) Measure(ctx context.Context, logger *zap.Logger, process Process, task func() error) error {
+	identifier, ok := process.(ProcessIdentifier)
+	if !ok {
+		return task()
+	}
+
+	usage, err := MeasureProcessTree(identifier.Pid(), task)
+	if usage == (ProcessUsage{}) {
+		return err
+	}
+
+	logger.Info(
+		"process usage",
+		zap.Int64("cpu_time", int64(usage.CpuTime)),
+		zap.String("cpu_time_human", usage.CpuTime.String()),
+		zap.Int64("peak_rss", usage.PeakRss),
+	)
+
+	route := RouteFromContext(ctx)
+	if route == "" {
+		return err
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	recorder.cpuSeconds[route] += usage.CpuTime.Seconds()
+	recorder.peakRss[route] = float64(usage.PeakRss)
+
+	return err
+}
+
+// CpuSeconds returns the cumulative CPU time, in seconds, by route.
+func (recorder *UsageRecorder) CpuSeconds() map[string]float64 {
+	recorder.mu.RLock()
+	defer recorder.mu.RUnlock()
+
+	values := make(map[string]float64, len(recorder.cpuSeconds))
+	for route, value := range recorder.cpuSeconds {
+		values[route] = value
+	}
+
+	return values
+}
+
+// PeakRss returns the peak resident set size, in bytes, of the last task, by
+// route.
+func (recorder *UsageRecorder) PeakRss() map[string]float64 {
+	recorder.mu.RLock()
+	defer recorder.mu.RUnlock()
+
+	values := make(map[string]float64, len(recorder.peakRss))
+	for route, value := range recorder.peakRss {
+		values[route] = value
+	}
+
+	return values
+}
@@ -0,0 +1,129 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type identifiedProcessMock struct {
+	ProcessMock
+	pid int
+}
+
+func (p *identifiedProcessMock) Pid() int {
+	return p.pid
+}
+
+func TestMeasureProcessTree(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the /proc filesystem is only available on Linux")
+	}
+
+	for _, tc := range []struct {
+		scenario        string
+		pid             int
+		task            func() error
+		expectError     bool
+		expectEmptyRss  bool
+		expectEmptyTime bool
+	}{
+		{
+			scenario:        "no PID",
+			pid:             0,
+			task:            func() error { return nil },
+			expectEmptyRss:  true,
+			expectEmptyTime: true,
+		},
+		{
+			scenario:        "non-existing process",
+			pid:             -1,
+			task:            func() error { return errors.New("foo") },
+			expectError:     true,
+			expectEmptyRss:  true,
+			expectEmptyTime: true,
+		},
+		{
+			scenario: "current process",
+			pid:      os.Getpid(),
+			task: func() error {
+				// Burn some CPU time.
+				deadline := time.Now().Add(time.Duration(200) * time.Millisecond)
+				for time.Now().Before(deadline) {
+				}
+
+				return nil
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			usage, err := MeasureProcessTree(tc.pid, tc.task)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectEmptyRss != (usage.PeakRss == 0) {
+				t.Errorf("expected empty peak RSS to be %t but got %d", tc.expectEmptyRss, usage.PeakRss)
+			}
+
+			if tc.expectEmptyTime != (usage.CpuTime == 0) {
+				t.Errorf("expected empty CPU time to be %t but got %s", tc.expectEmptyTime, usage.CpuTime)
+			}
+		})
+	}
+}
+
+func TestUsageRecorder_Measure(t *testing.T) {
+	recorder := NewUsageRecorder()
+	ctx := WithRoute(context.Background(), "/forms/foo")
+
+	err := recorder.Measure(ctx, zap.NewNop(), new(ProcessMock), func() error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(recorder.CpuSeconds()) != 0 {
+		t.Errorf("expected no usage for a process without PID but got: %+v", recorder.CpuSeconds())
+	}
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	err = recorder.Measure(ctx, zap.NewNop(), &identifiedProcessMock{pid: os.Getpid()}, func() error {
+		return errors.New("foo")
+	})
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	if _, ok := recorder.CpuSeconds()["/forms/foo"]; !ok {
+		t.Errorf("expected CPU time for route '/forms/foo' but got: %+v", recorder.CpuSeconds())
+	}
+
+	if recorder.PeakRss()["/forms/foo"] == 0 {
+		t.Errorf("expected peak RSS for route '/forms/foo' but got: %+v", recorder.PeakRss())
+	}
+}
+
+func TestRouteFromContext(t *testing.T) {
+	if route := RouteFromContext(context.Background()); route != "" {
+		t.Errorf("expected no route but got '%s'", route)
+	}
+
+	if route := RouteFromContext(WithRoute(context.Background(), "/forms/foo")); route != "/forms/foo" {
+		t.Errorf("expected '/forms/foo' but got '%s'", route)
+	}
+}
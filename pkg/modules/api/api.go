@@ -13,6 +13,8 @@ import (
 
 	"github.com/alexliesenfeld/health"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/bytes"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -35,12 +37,22 @@ type Api struct {
 	rootPath                  string
 	traceHeader               string
 	disableHealthCheckLogging bool
+	disableLogLevelHeader     bool
+	enableLogCapture          bool
+	enablePdfMetadata         bool
+	jsonErrors                bool
+	cors                      corsOptions
+	inputLimits               inputLimits
+	routeInputLimits          map[string]inputLimits
+	downloader                *downloader
+	drainer                   *drainer
 
 	routes              []Route
 	externalMiddlewares []Middleware
 	healthChecks        []health.CheckerOption
+	features            map[string]map[string]interface{}
 	readyFn             []func() error
-	fs                  *gotenberg.FileSystem
+	storage             storage
 	logger              *zap.Logger
 	srv                 *echo.Echo
 }
@@ -68,6 +80,12 @@ type Route struct {
 	// Optional.
 	DisableLogging bool
 
+	// DisableCache tells that the output of the route depends on remote or
+	// session state, e.g., a live web page, so that identical requests may
+	// not give the same output. See [Context.Cacheable].
+	// Optional.
+	DisableCache bool
+
 	// Handler is the function which handles the request.
 	// Required.
 	Handler echo.HandlerFunc
@@ -164,6 +182,26 @@ func (a *Api) Descriptor() gotenberg.ModuleDescriptor {
 			fs.String("api-root-path", "/", "Set the root path of the API - for service discovery via URL paths")
 			fs.String("api-trace-header", "Gotenberg-Trace", "Set the header name to use for identifying requests")
 			fs.Bool("api-disable-health-check-logging", false, "Disable health check logging")
+			fs.Bool("api-disable-log-level-header", false, "Disable the ability to set the log level of a request with the Gotenberg-Log-Level header")
+			fs.Bool("api-enable-log-capture", false, "Enable the ability to get the logs of a request in the response with the Gotenberg-Log-Capture header")
+			fs.Bool("api-enable-pdf-metadata-headers", false, "Add the Gotenberg-Page-Count and Gotenberg-Page-Sizes headers to the responses with a PDF")
+			fs.Bool("api-json-errors", false, "Return the errors as JSON objects with a machine-readable code - otherwise, only to the requests with the Accept: application/json header")
+			fs.StringSlice("api-cors-allow-origins", make([]string, 0), "Set the origins allowed to make cross-origin requests - empty means CORS is disabled, * allows any origin")
+			fs.StringSlice("api-cors-allow-methods", []string{http.MethodGet, http.MethodPost, http.MethodOptions}, "Set the methods allowed in cross-origin requests")
+			fs.StringSlice("api-cors-allow-headers", make([]string, 0), "Set the headers allowed in cross-origin requests - empty means the headers requested by the client are allowed")
+			fs.StringSlice("api-cors-expose-headers", []string{"Content-Disposition", "Gotenberg-Trace"}, "Set the response headers browsers may expose to cross-origin clients")
+			fs.Bool("api-cors-allow-credentials", false, "Allow cross-origin requests with credentials - cannot be combined with the * origin")
+			fs.Duration("api-cors-max-age", time.Duration(0), "Set how long browsers may cache preflight responses - 0 means no caching hint")
+			fs.String("api-max-file-size", "0B", "Set the maximum size of each uploaded or downloaded file (e.g., 10MB) - 0B means no limit")
+			fs.String("api-max-total-file-size", "0B", "Set the maximum size of all the uploaded or downloaded files of a request (e.g., 50MB) - 0B means no limit")
+			fs.Int("api-max-pdf-pages", 0, "Set the maximum number of pages of each uploaded PDF - 0 means no limit")
+			fs.String("api-min-free-disk-space", "0B", "Set the minimum free space of the disk of the temporary directory (e.g., 1GB) below which the multipart/form-data requests get a 503 Service Unavailable response - 0B means no check")
+			fs.Duration("api-temp-dir-ttl", time.Duration(0), "Set the age after which the orphaned working directories of the requests, e.g., after a crash, are removed, alongside the ones of the previous processes of this host - 0 means never, otherwise it must be greater than the API timeout")
+			fs.Bool("api-disable-download-from", false, "Disable the ability to download files from URLs with the downloadFrom form field")
+			fs.String("api-download-from-allow-list", "", "Set the allowed URLs for the downloadFrom form field using a regular expression")
+			fs.String("api-download-from-deny-list", "", "Set the denied URLs for the downloadFrom form field using a regular expression")
+			fs.Bool("api-download-from-allow-private-ips", false, "Allow the downloadFrom form field to target loopback, private and shared IP addresses - link-local and cloud metadata addresses are always denied")
+			fs.StringSlice("api-input-limits-per-route", make([]string, 0), "Override the input limits for a route, e.g., /forms/chromium/screenshot/url:max-file-size=1MB - the limit is either max-file-size, max-total-file-size or max-pdf-pages")
 
 			return fs
 		}(),
@@ -180,6 +218,64 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 	a.rootPath = flags.MustString("api-root-path")
 	a.traceHeader = flags.MustString("api-trace-header")
 	a.disableHealthCheckLogging = flags.MustBool("api-disable-health-check-logging")
+	a.disableLogLevelHeader = flags.MustBool("api-disable-log-level-header")
+	a.enableLogCapture = flags.MustBool("api-enable-log-capture")
+	a.enablePdfMetadata = flags.MustBool("api-enable-pdf-metadata-headers")
+	a.jsonErrors = flags.MustBool("api-json-errors")
+	a.cors = corsOptions{
+		allowOrigins:     flags.MustStringSlice("api-cors-allow-origins"),
+		allowMethods:     flags.MustStringSlice("api-cors-allow-methods"),
+		allowHeaders:     flags.MustStringSlice("api-cors-allow-headers"),
+		exposeHeaders:    flags.MustStringSlice("api-cors-expose-headers"),
+		allowCredentials: flags.MustBool("api-cors-allow-credentials"),
+		maxAge:           flags.MustDuration("api-cors-max-age"),
+	}
+
+	// Input limits.
+	maxFileSize, err := bytes.Parse(flags.MustHumanReadableBytesString("api-max-file-size"))
+	if err != nil {
+		return fmt.Errorf("parse max file size: %w", err)
+	}
+
+	maxTotalFileSize, err := bytes.Parse(flags.MustHumanReadableBytesString("api-max-total-file-size"))
+	if err != nil {
+		return fmt.Errorf("parse max total file size: %w", err)
+	}
+
+	a.inputLimits = inputLimits{
+		maxFileSize:      maxFileSize,
+		maxTotalFileSize: maxTotalFileSize,
+		maxPdfPages:      flags.MustInt("api-max-pdf-pages"),
+	}
+
+	a.routeInputLimits, err = parseRouteInputLimits(a.inputLimits, flags.MustStringSlice("api-input-limits-per-route"))
+	if err != nil {
+		return fmt.Errorf("parse input limits per route: %w", err)
+	}
+
+	// Storage.
+	minFreeSpace, err := bytes.Parse(flags.MustHumanReadableBytesString("api-min-free-disk-space"))
+	if err != nil {
+		return fmt.Errorf("parse min free disk space: %w", err)
+	}
+
+	a.storage = storage{
+		minFreeSpace: minFreeSpace,
+		ttl:          flags.MustDuration("api-temp-dir-ttl"),
+	}
+
+	// Download from.
+	if !flags.MustBool("api-disable-download-from") {
+		a.downloader = newDownloader(
+			flags.MustRegexp("api-download-from-allow-list"),
+			flags.MustRegexp("api-download-from-deny-list"),
+			flags.MustBool("api-download-from-allow-private-ips"),
+		)
+	}
+
+	// The page count of the PDFs relies on pdfcpu, which must not look for
+	// its configuration file (see the pdfcpu module).
+	pdfcpuConfig.ConfigPath = "disable"
 
 	// Port from env?
 	portEnvVar := flags.MustString("api-port-from-env")
@@ -268,6 +364,26 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 		a.readyFn = append(a.readyFn, healthChecker.Ready)
 	}
 
+	// Get capabilities from modules.
+	mods, err = ctx.Modules(new(CapabilitiesProvider))
+	if err != nil {
+		return fmt.Errorf("get capabilities providers: %w", err)
+	}
+
+	a.features = make(map[string]map[string]interface{}, len(mods))
+	for _, mod := range mods {
+		features, err := mod.(CapabilitiesProvider).Capabilities()
+		if err != nil {
+			return fmt.Errorf("get capabilities: %w", err)
+		}
+
+		if features == nil {
+			continue
+		}
+
+		a.features[mod.(gotenberg.Module).Descriptor().ID] = features
+	}
+
 	// Logger.
 	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
 	if err != nil {
@@ -282,7 +398,8 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 	a.logger = logger
 
 	// File system.
-	a.fs = gotenberg.NewFileSystem()
+	a.storage.fs = gotenberg.NewFileSystem()
+	a.storage.logger = logger
 
 	return nil
 }
@@ -315,12 +432,72 @@ func (a *Api) Validate() error {
 		)
 	}
 
+	if len(a.cors.allowOrigins) > 0 {
+		for _, origin := range a.cors.allowOrigins {
+			if origin == "*" && a.cors.allowCredentials {
+				err = multierr.Append(err,
+					errors.New("CORS allowed origins cannot contain * when credentials are allowed"),
+				)
+			}
+
+			if strings.TrimSpace(origin) == "" {
+				err = multierr.Append(err,
+					errors.New("CORS allowed origins must not contain empty values"),
+				)
+			}
+		}
+
+		if len(a.cors.allowMethods) == 0 {
+			err = multierr.Append(err,
+				errors.New("CORS allowed methods must not be empty"),
+			)
+		}
+
+		if a.cors.maxAge < 0 {
+			err = multierr.Append(err,
+				errors.New("CORS max age must be positive"),
+			)
+		}
+	}
+
+	if a.storage.ttl < 0 {
+		err = multierr.Append(err,
+			errors.New("temp dir TTL must be positive"),
+		)
+	}
+
+	if a.storage.ttl > 0 && a.storage.ttl <= a.timeout {
+		err = multierr.Append(err,
+			errors.New("temp dir TTL must be greater than the timeout"),
+		)
+	}
+
+	err = multierr.Append(err, a.inputLimits.validate())
+	for path, limits := range a.routeInputLimits {
+		err = multierr.Append(err, limits.validate())
+
+		exists := false
+		for _, route := range a.routes {
+			if route.Path == path && route.IsMultipart {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			err = multierr.Append(err,
+				fmt.Errorf("input limits for '%s', which is not a multipart/form-data route", path),
+			)
+		}
+	}
+
 	if err != nil {
 		return err
 	}
 
-	routesMap := make(map[string]string, len(a.routes)+1)
+	routesMap := make(map[string]string, len(a.routes)+2)
 	routesMap["/health"] = "/health"
+	routesMap["/capabilities"] = "/capabilities"
 
 	for _, route := range a.routes {
 		if route.Path == "" {
@@ -368,7 +545,11 @@ func (a *Api) Start() error {
 	a.srv.Server.IdleTimeout = a.timeout
 	// See https://github.com/gotenberg/gotenberg/issues/396.
 	a.srv.Server.WriteTimeout = a.timeout + a.timeout
-	a.srv.HTTPErrorHandler = httpErrorHandler()
+	a.srv.HTTPErrorHandler = httpErrorHandler(a.jsonErrors)
+	a.drainer = newDrainer()
+
+	// The capabilities describe the routes before their preparation.
+	capabilities := a.capabilities()
 
 	// Let's prepare the modules' routes.
 	var disableLoggingForPaths []string
@@ -391,9 +572,15 @@ func (a *Api) Start() error {
 		latencyMiddleware(),
 		rootPathMiddleware(a.rootPath),
 		traceMiddleware(a.traceHeader),
-		loggerMiddleware(a.logger, disableLoggingForPaths),
+		loggerMiddleware(a.logger, disableLoggingForPaths, !a.disableLogLevelHeader, a.enableLogCapture),
 	)
 
+	// CORS is handled before routing, so that preflight requests get an
+	// answer without reaching the routes' handlers.
+	if len(a.cors.allowOrigins) > 0 {
+		a.srv.Pre(corsMiddleware(a.cors))
+	}
+
 	// Add the modules' middlewares in their respective stacks.
 	var externalMultipartMiddlewares []Middleware
 	for _, externalMiddleware := range a.externalMiddlewares {
@@ -414,7 +601,12 @@ func (a *Api) Start() error {
 		var middlewares []echo.MiddlewareFunc
 
 		if route.IsMultipart {
-			middlewares = append(middlewares, contextMiddleware(a.fs, a.timeout))
+			limits, ok := a.routeInputLimits[fmt.Sprintf("/%s", route.Path)]
+			if !ok {
+				limits = a.inputLimits
+			}
+
+			middlewares = append(middlewares, contextMiddleware(&a.storage, a.timeout, limits, a.downloader, a.drainer, a.enablePdfMetadata, !route.DisableCache))
 
 			for _, externalMultipartMiddleware := range externalMultipartMiddlewares {
 				middlewares = append(middlewares, externalMultipartMiddleware.Handler)
@@ -435,13 +627,39 @@ func (a *Api) Start() error {
 	a.srv.GET(
 		fmt.Sprintf("%s%s", a.rootPath, "health"),
 		func() echo.HandlerFunc {
-			checks := append(a.healthChecks, health.WithTimeout(a.timeout))
+			checks := a.healthChecks
+			if a.timeout > 0 {
+				// A zero timeout would fail every check.
+				checks = append(checks, health.WithTimeout(a.timeout))
+			}
+
+			checks = append(
+				checks,
+				// A draining server is not ready for new requests anymore.
+				health.WithCheck(health.Check{
+					Name: "api",
+					Check: func(_ context.Context) error {
+						if a.drainer.isDraining() {
+							return errors.New("shutting down")
+						}
+
+						return nil
+					},
+				}),
+			)
 			checker := health.NewChecker(checks...)
 			return echo.WrapHandler(health.NewHandler(checker))
 		}(),
 		hardTimeoutMiddleware(hardTimeout),
 	)
 
+	// Clients may discover what this instance supports.
+	a.srv.GET(
+		fmt.Sprintf("%s%s", a.rootPath, "capabilities"),
+		capabilitiesHandler(capabilities),
+		hardTimeoutMiddleware(hardTimeout),
+	)
+
 	// Wait for all modules to be ready.
 	ctx, cancel := context.WithTimeout(context.Background(), a.startTimeout)
 	defer cancel()
@@ -456,6 +674,9 @@ func (a *Api) Start() error {
 		return fmt.Errorf("waiting for modules readiness: %w", err)
 	}
 
+	// Remove the orphaned working directories of the requests.
+	a.storage.start()
+
 	// As the following code is blocking, run it in a goroutine.
 	go func() {
 		server := &http2.Server{}
@@ -473,15 +694,68 @@ func (a *Api) StartupMessage() string {
 	return fmt.Sprintf("server listening on port %d", a.port)
 }
 
-// Stop stops the HTTP server.
+// Stop stops the HTTP server. It first rejects the new multipart/form-data
+// requests and waits for the in-flight ones, the asynchronous ones included,
+// until the end of the grace period. The remaining requests are then aborted,
+// so that they fail with a 503 Service Unavailable status, e.g., on their
+// webhook error URL.
 func (a *Api) Stop(ctx context.Context) error {
-	return a.srv.Shutdown(ctx)
+	defer a.storage.stop()
+
+	drained, aborted := a.drainer.drain(ctx)
+	a.logger.Info(fmt.Sprintf("graceful shutdown: %d in-flight request(s) drained, %d aborted", drained, aborted))
+
+	if ctx.Err() == nil {
+		return a.srv.Shutdown(ctx)
+	}
+
+	// The grace period is over: let the connections of the aborted requests
+	// a little time to close.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), abortTimeout)
+	defer cancel()
+
+	return a.srv.Shutdown(shutdownCtx)
+}
+
+// Metrics returns the metrics.
+func (a *Api) Metrics() ([]gotenberg.Metric, error) {
+	return []gotenberg.Metric{
+		{
+			Name:        "api_temp_dirs_count",
+			Description: "Current number of working directories of the requests and of the previous processes of this host.",
+			Read: func() float64 {
+				count, _ := a.storage.usage()
+				return float64(count)
+			},
+		},
+		{
+			Name:        "api_temp_dirs_bytes",
+			Description: "Current number of bytes in use by the working directories of the requests and of the previous processes of this host.",
+			Read: func() float64 {
+				_, size := a.storage.usage()
+				return float64(size)
+			},
+		},
+		{
+			Name:        "api_free_disk_space_bytes",
+			Description: "Current free space, in bytes, of the disk of the temporary directory.",
+			Read: func() float64 {
+				free, err := a.storage.freeSpace()
+				if err != nil {
+					return 0
+				}
+
+				return float64(free)
+			},
+		},
+	}, nil
 }
 
 // Interface guards.
 var (
-	_ gotenberg.Module      = (*Api)(nil)
-	_ gotenberg.Provisioner = (*Api)(nil)
-	_ gotenberg.Validator   = (*Api)(nil)
-	_ gotenberg.App         = (*Api)(nil)
+	_ gotenberg.Module          = (*Api)(nil)
+	_ gotenberg.Provisioner     = (*Api)(nil)
+	_ gotenberg.Validator       = (*Api)(nil)
+	_ gotenberg.App             = (*Api)(nil)
+	_ gotenberg.MetricsProvider = (*Api)(nil)
 )
@@ -258,6 +258,30 @@ func TestApi_Provision(t *testing.T) {
 			}(),
 			expectError: true,
 		},
+		{
+			scenario: "cannot retrieve capabilities from capabilities provider",
+			ctx: func() *gotenberg.Context {
+				mod := &struct {
+					gotenberg.ModuleMock
+					CapabilitiesProviderMock
+				}{}
+				mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "foo", New: func() gotenberg.Module { return mod }}
+				}
+				mod.CapabilitiesMock = func() (map[string]interface{}, error) {
+					return nil, errors.New("foo")
+				}
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(Api).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{
+						mod.Descriptor(),
+					},
+				)
+			}(),
+			expectError: true,
+		},
 		{
 			scenario: "no logger provider",
 			ctx: func() *gotenberg.Context {
@@ -438,10 +462,140 @@ func TestApi_Validate(t *testing.T) {
 		port        int
 		rootPath    string
 		traceHeader string
+		cors        corsOptions
+		limits      inputLimits
+		routeLimits map[string]inputLimits
+		timeout     time.Duration
+		tempDirTtl  time.Duration
 		routes      []Route
 		middlewares []Middleware
 		expectError bool
 	}{
+		{
+			scenario:    "CORS: * origin with credentials",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			cors: corsOptions{
+				allowOrigins:     []string{"*"},
+				allowMethods:     []string{http.MethodPost},
+				allowCredentials: true,
+			},
+			expectError: true,
+		},
+		{
+			scenario:    "CORS: empty origin",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			cors: corsOptions{
+				allowOrigins: []string{"https://foo.com", " "},
+				allowMethods: []string{http.MethodPost},
+			},
+			expectError: true,
+		},
+		{
+			scenario:    "CORS: empty methods",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			cors: corsOptions{
+				allowOrigins: []string{"https://foo.com"},
+			},
+			expectError: true,
+		},
+		{
+			scenario:    "CORS: negative max age",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			cors: corsOptions{
+				allowOrigins: []string{"https://foo.com"},
+				allowMethods: []string{http.MethodPost},
+				maxAge:       -time.Second,
+			},
+			expectError: true,
+		},
+		{
+			scenario:    "CORS: valid options",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			cors: corsOptions{
+				allowOrigins:     []string{"https://foo.com"},
+				allowMethods:     []string{http.MethodPost},
+				allowCredentials: true,
+				maxAge:           time.Hour,
+			},
+			expectError: false,
+		},
+		{
+			scenario:    "negative input limits",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			limits:      inputLimits{maxFileSize: -1},
+			expectError: true,
+		},
+		{
+			scenario:    "input limits for a non-existing route",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			routeLimits: map[string]inputLimits{"/forms/foo": {maxPdfPages: 1}},
+			routes: []Route{
+				{
+					Method:      http.MethodPost,
+					Path:        "/forms/bar",
+					IsMultipart: true,
+					Handler:     func(_ echo.Context) error { return nil },
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario:    "valid input limits",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			limits:      inputLimits{maxFileSize: 10, maxTotalFileSize: 20, maxPdfPages: 30},
+			routeLimits: map[string]inputLimits{"/forms/foo": {maxPdfPages: 1}},
+			routes: []Route{
+				{
+					Method:      http.MethodPost,
+					Path:        "/forms/foo",
+					IsMultipart: true,
+					Handler:     func(_ echo.Context) error { return nil },
+				},
+			},
+			expectError: false,
+		},
+		{
+			scenario:    "negative temp dir TTL",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			tempDirTtl:  time.Duration(-1) * time.Second,
+			expectError: true,
+		},
+		{
+			scenario:    "temp dir TTL not greater than the timeout",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			timeout:     time.Duration(30) * time.Second,
+			tempDirTtl:  time.Duration(30) * time.Second,
+			expectError: true,
+		},
+		{
+			scenario:    "valid temp dir TTL",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			timeout:     time.Duration(30) * time.Second,
+			tempDirTtl:  time.Duration(1) * time.Hour,
+			expectError: false,
+		},
 		{
 			scenario:    "invalid port (< 1)",
 			port:        0,
@@ -576,6 +730,21 @@ func TestApi_Validate(t *testing.T) {
 			middlewares: nil,
 			expectError: true,
 		},
+		{
+			scenario:    "invalid route: reserved path",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			routes: []Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/capabilities",
+					Handler: func(_ echo.Context) error { return nil },
+				},
+			},
+			middlewares: nil,
+			expectError: true,
+		},
 		{
 			scenario:    "invalid middleware: nil handler",
 			port:        10,
@@ -627,6 +796,11 @@ func TestApi_Validate(t *testing.T) {
 				port:                tc.port,
 				rootPath:            tc.rootPath,
 				traceHeader:         tc.traceHeader,
+				cors:                tc.cors,
+				inputLimits:         tc.limits,
+				routeInputLimits:    tc.routeLimits,
+				timeout:             tc.timeout,
+				storage:             storage{ttl: tc.tempDirTtl},
 				routes:              tc.routes,
 				externalMiddlewares: tc.middlewares,
 			}
@@ -736,7 +910,7 @@ func TestApi_Start(t *testing.T) {
 				},
 			}
 			mod.readyFn = tc.readyFn
-			mod.fs = gotenberg.NewFileSystem()
+			mod.storage.fs = gotenberg.NewFileSystem()
 			mod.logger = zap.NewNop()
 
 			err := mod.Start()
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/labstack/gommon/bytes"
+)
+
+const (
+	// maxArchiveSize is the maximum total size of the extracted files of an
+	// archive if there is no maximum total file size.
+	maxArchiveSize int64 = 512 << 20
+
+	// maxArchiveEntries is the maximum number of entries of an archive.
+	maxArchiveEntries = 10000
+)
+
+// errArchiveTooLarge happens if the extracted files of an archive exceed the
+// maximum total file size.
+var errArchiveTooLarge = errors.New("archive too large")
+
+// ExtractZip extracts a ZIP archive into a new directory of the context's
+// working directory, and returns the path of this directory. It returns an
+// [HttpError] if the file is not a ZIP archive, if an entry would land
+// outside this directory (i.e., a zip slip), if it has too many entries, or
+// if the extracted files exceed the maximum total file size - 512MB if there
+// is no maximum.
+func (ctx *Context) ExtractZip(archivePath string) (string, error) {
+	filename := filepath.Base(archivePath)
+
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return "", WrapError(
+			fmt.Errorf("open ZIP archive: %w", err),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The file '%s' is not a valid ZIP archive", filename)),
+		)
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	if len(reader.File) > maxArchiveEntries {
+		return "", WrapError(
+			fmt.Errorf("extract '%s': %d entries: %w", filename, len(reader.File), errArchiveTooLarge),
+			NewSentinelHttpError(
+				http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("The archive '%s' has more than %d entries", filename, maxArchiveEntries),
+			).WithCode("TOO_MANY_ARCHIVE_ENTRIES"),
+		)
+	}
+
+	maxSize := maxArchiveSize
+	if ctx.limits.maxTotalFileSize > 0 {
+		maxSize = ctx.limits.maxTotalFileSize
+	}
+
+	dirPath := ctx.GeneratePath("")
+	err = os.MkdirAll(dirPath, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("create archive directory: %w", err)
+	}
+
+	var total int64
+	for _, file := range reader.File {
+		target := filepath.Join(dirPath, file.Name)
+		if !strings.HasPrefix(target, dirPath+string(os.PathSeparator)) || file.Mode()&os.ModeSymlink != 0 {
+			return "", WrapError(
+				fmt.Errorf("entry '%s' of '%s' escapes the archive directory", file.Name, filename),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The archive '%s' has an invalid entry '%s'", filename, file.Name)),
+			)
+		}
+
+		if file.FileInfo().IsDir() {
+			err = os.MkdirAll(target, 0o755)
+			if err != nil {
+				return "", fmt.Errorf("create archive directory: %w", err)
+			}
+
+			continue
+		}
+
+		written, err := extractZipFile(file, target, maxSize-total)
+		if errors.Is(err, errArchiveTooLarge) {
+			return "", WrapError(
+				fmt.Errorf("extract '%s': %w", filename, err),
+				NewSentinelHttpError(
+					http.StatusRequestEntityTooLarge,
+					fmt.Sprintf("The extracted files of the archive '%s' exceed the maximum total file size of %s", filename, bytes.Format(maxSize)),
+				).WithCode("FILES_TOO_LARGE"),
+			)
+		}
+
+		if err != nil {
+			return "", WrapError(
+				fmt.Errorf("extract '%s': %w", filename, err),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The archive '%s' cannot be extracted", filename)),
+			)
+		}
+
+		total += written
+	}
+
+	ctx.Log().Debug(fmt.Sprintf("'%s' extracted in '%s' (%d bytes)", filename, dirPath, total))
+
+	return dirPath, nil
+}
+
+// extractZipFile writes a file of a ZIP archive to the target path, and
+// returns its size. It returns [errArchiveTooLarge] as soon as the file
+// exceeds the limit. The sizes in the headers of the archive are only
+// trusted to reject the file early.
+func extractZipFile(file *zip.File, target string, limit int64) (int64, error) {
+	if file.UncompressedSize64 > uint64(limit) {
+		return 0, errArchiveTooLarge
+	}
+
+	err := os.MkdirAll(filepath.Dir(target), 0o755)
+	if err != nil {
+		return 0, fmt.Errorf("create directory: %w", err)
+	}
+
+	in, err := file.Open()
+	if err != nil {
+		return 0, fmt.Errorf("open entry: %w", err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("create file: %w", err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	written, err := io.Copy(out, io.LimitReader(in, limit+1))
+	if err != nil {
+		return 0, fmt.Errorf("write file: %w", err)
+	}
+
+	if written > limit {
+		return 0, errArchiveTooLarge
+	}
+
+	return written, nil
+}
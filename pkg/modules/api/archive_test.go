@@ -0,0 +1,185 @@
+package api
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestContext_ExtractZip(t *testing.T) {
+	writeZip := func(path string, files map[string]string) {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		w := zip.NewWriter(f)
+		for name, content := range files {
+			entry, err := w.Create(name)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			_, err = entry.Write([]byte(content))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	// A small archive whose single entry is larger than the default maximum
+	// size once extracted.
+	writeZipBomb := func(path string) {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		w := zip.NewWriter(f)
+		entry, err := w.Create("index.html")
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		chunk := make([]byte, 1<<20)
+		for written := int64(0); written <= maxArchiveSize; written += int64(len(chunk)) {
+			_, err = entry.Write(chunk)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	tooManyFiles := make(map[string]string, maxArchiveEntries+1)
+	for i := 0; i <= maxArchiveEntries; i++ {
+		tooManyFiles[fmt.Sprintf("assets/%d.css", i)] = ""
+	}
+
+	for _, tc := range []struct {
+		scenario         string
+		files            map[string]string
+		notZip           bool
+		zipBomb          bool
+		maxTotalFileSize int64
+		expectFiles      []string
+		expectHttpStatus int
+	}{
+		{
+			scenario:         "not a ZIP archive",
+			notZip:           true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "zip slip",
+			files: map[string]string{
+				"../evil.html": "<html></html>",
+			},
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "extracted files too large",
+			files: map[string]string{
+				"index.html":       "<html></html>",
+				"assets/style.css": "body { color: red; }",
+			},
+			maxTotalFileSize: 20,
+			expectHttpStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario:         "extracted files too large (default maximum)",
+			zipBomb:          true,
+			expectHttpStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario:         "too many entries",
+			files:            tooManyFiles,
+			expectHttpStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario: "success",
+			files: map[string]string{
+				"index.html":       "<html></html>",
+				"assets/style.css": "body { color: red; }",
+			},
+			maxTotalFileSize: 100,
+			expectFiles:      []string{"index.html", "assets/style.css"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			archivePath := fmt.Sprintf("%s/archive.zip", dirPath)
+
+			if tc.notZip {
+				err := os.WriteFile(archivePath, []byte("foo"), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			} else if tc.zipBomb {
+				writeZipBomb(archivePath)
+			} else {
+				writeZip(archivePath, tc.files)
+			}
+
+			ctx := &Context{
+				dirPath: dirPath,
+				limits:  inputLimits{maxTotalFileSize: tc.maxTotalFileSize},
+				logger:  zap.NewNop(),
+			}
+
+			extractedPath, err := ctx.ExtractZip(archivePath)
+
+			if tc.expectHttpStatus != 0 {
+				var httpErr HttpError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("expected an HTTP error but got: %v", err)
+				}
+
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+
+				_, err = os.Stat(fmt.Sprintf("%s/evil.html", dirPath))
+				if err == nil {
+					t.Error("expected no file outside of the archive directory")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			for _, filename := range tc.expectFiles {
+				_, err = os.Stat(fmt.Sprintf("%s/%s", extractedPath, filename))
+				if err != nil {
+					t.Errorf("expected file '%s' in the archive directory but got: %v", filename, err)
+				}
+			}
+		})
+	}
+}
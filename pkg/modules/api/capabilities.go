@@ -0,0 +1,124 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// CapabilitiesProvider is a module interface which describes what a module
+// supports, e.g., its input extensions. The [Api] exposes these capabilities,
+// alongside the enabled routes and the configured limits, so that clients
+// may validate their requests before submitting them.
+type CapabilitiesProvider interface {
+	// Capabilities returns the capabilities of the module. A nil map means
+	// the module has nothing to expose, e.g., if its routes are disabled.
+	Capabilities() (map[string]interface{}, error)
+}
+
+// capabilities is the body of the capabilities route. The registered
+// modules are the ones built into the binary, whether their flags disable
+// them or not; the features only describe the modules whose routes are
+// enabled.
+type capabilities struct {
+	RegisteredModules []string                          `json:"registeredModules"`
+	Routes            []capabilitiesRoute               `json:"routes"`
+	Limits            capabilitiesLimits                `json:"limits"`
+	Features          map[string]map[string]interface{} `json:"features"`
+}
+
+// capabilitiesRoute describes an enabled route.
+type capabilitiesRoute struct {
+	Method    string              `json:"method"`
+	Path      string              `json:"path"`
+	Multipart bool                `json:"multipart"`
+	Limits    *capabilitiesLimits `json:"limits,omitempty"`
+}
+
+// capabilitiesLimits describes the limits of the requests. Sizes are in
+// bytes; a zero value means no limit.
+type capabilitiesLimits struct {
+	Timeout          string `json:"timeout,omitempty"`
+	MaxFileSize      int64  `json:"maxFileSize"`
+	MaxTotalFileSize int64  `json:"maxTotalFileSize"`
+	MaxPdfPages      int    `json:"maxPdfPages"`
+}
+
+// newCapabilitiesLimits creates a [capabilitiesLimits] from the given input
+// limits.
+func newCapabilitiesLimits(limits inputLimits) capabilitiesLimits {
+	return capabilitiesLimits{
+		MaxFileSize:      limits.maxFileSize,
+		MaxTotalFileSize: limits.maxTotalFileSize,
+		MaxPdfPages:      limits.maxPdfPages,
+	}
+}
+
+// capabilities returns the capabilities of the [Api], built from the
+// registered modules, the enabled routes and the configured limits. The
+// paths of the routes must still start with a slash.
+func (a *Api) capabilities() capabilities {
+	descriptors := gotenberg.GetModuleDescriptors()
+	modules := make([]string, len(descriptors))
+	for i, descriptor := range descriptors {
+		modules[i] = descriptor.ID
+	}
+
+	sort.Strings(modules)
+
+	routes := make([]capabilitiesRoute, len(a.routes))
+	for i, route := range a.routes {
+		routes[i] = capabilitiesRoute{
+			Method:    route.Method,
+			Path:      fmt.Sprintf("%s%s", a.rootPath, strings.TrimPrefix(route.Path, "/")),
+			Multipart: route.IsMultipart,
+		}
+
+		if !route.IsMultipart {
+			continue
+		}
+
+		limits, ok := a.routeInputLimits[route.Path]
+		if !ok {
+			limits = a.inputLimits
+		}
+
+		routeLimits := newCapabilitiesLimits(limits)
+		routes[i].Limits = &routeLimits
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+
+		return routes[i].Path < routes[j].Path
+	})
+
+	limits := newCapabilitiesLimits(a.inputLimits)
+	limits.Timeout = a.timeout.String()
+
+	features := a.features
+	if features == nil {
+		features = make(map[string]map[string]interface{})
+	}
+
+	return capabilities{
+		RegisteredModules: modules,
+		Routes:            routes,
+		Limits:            limits,
+		Features:          features,
+	}
+}
+
+// capabilitiesHandler returns the handler of the capabilities route.
+func capabilitiesHandler(body capabilities) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, body)
+	}
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestApi_capabilities(t *testing.T) {
+	a := &Api{
+		rootPath: "/foo/",
+		timeout:  time.Duration(30) * time.Second,
+		inputLimits: inputLimits{
+			maxFileSize:      1000,
+			maxTotalFileSize: 2000,
+			maxPdfPages:      10,
+		},
+		routeInputLimits: map[string]inputLimits{
+			"/forms/bar": {
+				maxFileSize: 500,
+			},
+		},
+		routes: []Route{
+			{
+				Method:      http.MethodPost,
+				Path:        "/forms/baz",
+				IsMultipart: true,
+			},
+			{
+				Method: http.MethodDelete,
+				Path:   "/bar",
+			},
+			{
+				Method:      http.MethodPost,
+				Path:        "/forms/bar",
+				IsMultipart: true,
+			},
+		},
+		features: map[string]map[string]interface{}{
+			"qux": {"extensions": []string{".qux"}},
+		},
+	}
+
+	actual := a.capabilities()
+
+	expectRoutes := []capabilitiesRoute{
+		{
+			Method: http.MethodDelete,
+			Path:   "/foo/bar",
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/foo/forms/bar",
+			Multipart: true,
+			Limits: &capabilitiesLimits{
+				MaxFileSize: 500,
+			},
+		},
+		{
+			Method:    http.MethodPost,
+			Path:      "/foo/forms/baz",
+			Multipart: true,
+			Limits: &capabilitiesLimits{
+				MaxFileSize:      1000,
+				MaxTotalFileSize: 2000,
+				MaxPdfPages:      10,
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(actual.Routes, expectRoutes) {
+		t.Errorf("expected routes %+v but got: %+v", expectRoutes, actual.Routes)
+	}
+
+	expectLimits := capabilitiesLimits{
+		Timeout:          "30s",
+		MaxFileSize:      1000,
+		MaxTotalFileSize: 2000,
+		MaxPdfPages:      10,
+	}
+
+	if actual.Limits != expectLimits {
+		t.Errorf("expected limits %+v but got: %+v", expectLimits, actual.Limits)
+	}
+
+	if !reflect.DeepEqual(actual.Features, a.features) {
+		t.Errorf("expected features %+v but got: %+v", a.features, actual.Features)
+	}
+}
+
+func TestCapabilitiesHandler(t *testing.T) {
+	a := &Api{
+		rootPath: "/",
+		timeout:  time.Duration(30) * time.Second,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/capabilities", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	err := capabilitiesHandler(a.capabilities())(c)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d but got %d", http.StatusOK, rec.Code)
+	}
+
+	var body map[string]interface{}
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, key := range []string{"registeredModules", "routes", "limits", "features"} {
+		if _, ok := body[key]; !ok {
+			t.Errorf("expected key '%s' in %+v", key, body)
+		}
+	}
+}
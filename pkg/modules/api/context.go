@@ -3,6 +3,9 @@ package api
 import (
 	"compress/flate"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,13 +13,18 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/mholt/archiver/v3"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/text/unicode/norm"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
@@ -34,30 +42,63 @@ var (
 
 // Context is the request context for a "multipart/form-data" requests.
 type Context struct {
-	dirPath string
-	values  map[string][]string
-	files   map[string]string
+	dirPath   string
+	values    map[string][]string
+	files     map[string]string
+	filenames []string
 
 	outputPaths []string
 
-	cancelled bool
-	logger    *zap.Logger
-	echoCtx   echo.Context
+	limits              inputLimits
+	downloader          *downloader
+	downloadedFilenames []string
+
+	validateOnly bool
+	forms        []*FormData
+
+	cancelled   bool
+	abort       context.CancelFunc
+	aborted     atomic.Bool
+	pdfMetadata bool
+	cacheable   bool
+	trace       string
+	logger      *zap.Logger
+	echoCtx     echo.Context
 	context.Context
 }
 
+// maxFormValuesSize is the maximum size, in bytes, of the form fields of a
+// "multipart/form-data" request, files excluded.
+const maxFormValuesSize = int64(10 << 20)
+
 // newContext returns a [Context] by parsing a "multipart/form-data" request.
-func newContext(echoCtx echo.Context, logger *zap.Logger, fs *gotenberg.FileSystem, timeout time.Duration) (*Context, context.CancelFunc, error) {
-	processCtx, processCancel := context.WithTimeout(context.Background(), timeout)
+func newContext(echoCtx echo.Context, logger *zap.Logger, fs *gotenberg.FileSystem, timeout time.Duration, limits inputLimits, downloader *downloader) (*Context, context.CancelFunc, error) {
+	// The process context keeps the values of the request context (e.g., a
+	// tracing span), but not its cancellation: an asynchronous process must
+	// outlive the request. It also carries the route, so that the engines
+	// may attribute their resource usage to it.
+	processCtx, processCancel := context.WithTimeout(gotenberg.WithRoute(context.WithoutCancel(echoCtx.Request().Context()), echoCtx.Path()), timeout)
+
+	// See the trace middleware.
+	trace, _ := echoCtx.Get("trace").(string)
 
 	ctx := &Context{
 		outputPaths: make([]string, 0),
+		limits:      limits,
+		downloader:  downloader,
 		cancelled:   false,
+		trace:       trace,
 		logger:      logger,
 		echoCtx:     echoCtx,
 		Context:     processCtx,
 	}
 
+	// A shutdown aborts the request once its grace period is over.
+	ctx.abort = func() {
+		ctx.aborted.Store(true)
+		processCancel()
+	}
+
 	// A custom cancel function which removes the context's working directory
 	// when called.
 	cancel := func() context.CancelFunc {
@@ -84,31 +125,25 @@ func newContext(echoCtx echo.Context, logger *zap.Logger, fs *gotenberg.FileSyst
 		}
 	}()
 
-	form, err := echoCtx.MultipartForm()
+	// The parts of the body are streamed, so that the uploaded files go
+	// straight to the working directory, whatever their size.
+	reader, err := echoCtx.Request().MultipartReader()
 	if err != nil {
-
 		if errors.Is(err, http.ErrNotMultipart) {
 			return nil, cancel, WrapError(
-				fmt.Errorf("get multipart form: %w", err),
+				fmt.Errorf("get multipart reader: %w", err),
 				NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: want 'multipart/form-data'"),
 			)
 		}
 
 		if errors.Is(err, http.ErrMissingBoundary) {
 			return nil, cancel, WrapError(
-				fmt.Errorf("get multipart form: %w", err),
+				fmt.Errorf("get multipart reader: %w", err),
 				NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: no boundary"),
 			)
 		}
 
-		if strings.Contains(err.Error(), io.EOF.Error()) {
-			return nil, cancel, WrapError(
-				fmt.Errorf("get multipart form: %w", err),
-				NewSentinelHttpError(http.StatusBadRequest, "Malformed body: it does not match the 'Content-Type' header boundaries"),
-			)
-		}
-
-		return nil, cancel, fmt.Errorf("get multipart form: %w", err)
+		return nil, cancel, fmt.Errorf("get multipart reader: %w", err)
 	}
 
 	dirPath, err := fs.MkdirAll()
@@ -117,64 +152,155 @@ func newContext(echoCtx echo.Context, logger *zap.Logger, fs *gotenberg.FileSyst
 	}
 
 	ctx.dirPath = dirPath
-	ctx.values = form.Value
+	ctx.values = make(map[string][]string)
 	ctx.files = make(map[string]string)
 
-	copyToDisk := func(fh *multipart.FileHeader) error {
-		in, err := fh.Open()
+	// On error, the cancel function removes the partial files.
+	uploadedSize, err := ctx.readParts(reader)
+	if err != nil {
+		return ctx, cancel, err
+	}
+
+	if len(ctx.values["validateOnly"]) > 0 && ctx.values["validateOnly"][0] != "" {
+		ctx.validateOnly, err = strconv.ParseBool(ctx.values["validateOnly"][0])
 		if err != nil {
-			return fmt.Errorf("open multipart file: %w", err)
+			return ctx, cancel, WrapError(
+				fmt.Errorf("parse validateOnly: %w", err),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: form field 'validateOnly' is invalid (got '%s', resulting to %s)", ctx.values["validateOnly"][0], err)),
+			)
 		}
+	}
+
+	err = ctx.downloadFrom(uploadedSize)
+	if err != nil {
+		return ctx, cancel, fmt.Errorf("download from: %w", err)
+	}
+
+	err = limits.checkPdfPages(ctx.files)
+	if err != nil {
+		return ctx, cancel, err
+	}
 
-		defer func() {
-			err := in.Close()
+	ctx.Log().Debug(fmt.Sprintf("form fields: %+v", redactValues(ctx.values)))
+	ctx.Log().Debug(fmt.Sprintf("form files: %+v", ctx.files))
+
+	return ctx, cancel, err
+}
+
+// readParts streams the parts of a "multipart/form-data" body: it writes the
+// files into the context's working directory, checking the input limits on
+// the fly, and keeps the other form fields in memory. It returns the total
+// size of the files.
+func (ctx *Context) readParts(reader *multipart.Reader) (int64, error) {
+	var (
+		total      int64
+		valuesSize int64
+	)
+
+	malformedBodyOrErr := func(err error) error {
+		if strings.Contains(err.Error(), io.EOF.Error()) {
+			return WrapError(
+				fmt.Errorf("read multipart body: %w", err),
+				NewSentinelHttpError(http.StatusBadRequest, "Malformed body: it does not match the 'Content-Type' header boundaries"),
+			)
+		}
+
+		return fmt.Errorf("read multipart body: %w", err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		// Only an unwrapped io.EOF means the end of the body; a wrapped one
+		// means a truncated body.
+		if err == io.EOF {
+			return total, nil
+		}
+
+		if err != nil {
+			return 0, malformedBodyOrErr(err)
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		if part.FileName() == "" {
+			// Like the standard library, we cap the size of the form fields
+			// kept in memory.
+			b, err := io.ReadAll(io.LimitReader(part, maxFormValuesSize-valuesSize+1))
 			if err != nil {
-				logger.Error(fmt.Sprintf("close file header: %s", err))
+				return 0, malformedBodyOrErr(err)
 			}
-		}()
+
+			valuesSize += int64(len(b))
+			if valuesSize > maxFormValuesSize {
+				return 0, WrapError(
+					fmt.Errorf("form fields exceed %d bytes", maxFormValuesSize),
+					NewSentinelHttpError(http.StatusRequestEntityTooLarge, "The form fields are too large"),
+				)
+			}
+
+			ctx.values[name] = append(ctx.values[name], string(b))
+
+			continue
+		}
 
 		// Avoid directory traversal and make sure filename characters are
 		// normalized.
 		// See: https://github.com/gotenberg/gotenberg/issues/662.
-		filename := norm.NFC.String(filepath.Base(fh.Filename))
+		filename := norm.NFC.String(filepath.Base(part.FileName()))
 		path := fmt.Sprintf("%s/%s", ctx.dirPath, filename)
 
-		out, err := os.Create(path)
+		size, err := ctx.writePart(part, path, ctx.limits.fileSizeBudget(total))
 		if err != nil {
-			return fmt.Errorf("create local file: %w", err)
+			return 0, malformedBodyOrErr(err)
 		}
 
-		defer func() {
-			err := out.Close()
-			if err != nil {
-				logger.Error(fmt.Sprintf("close local file: %s", err))
-			}
-		}()
+		total += size
 
-		_, err = io.Copy(out, in)
+		err = ctx.limits.checkFileSize(filename, size, total)
 		if err != nil {
-			return fmt.Errorf("copy multipart file to local file: %w", err)
+			return 0, err
 		}
 
-		ctx.files[filename] = path
+		// A file uploaded twice keeps its first position.
+		_, exists := ctx.files[filename]
+		if !exists {
+			ctx.filenames = append(ctx.filenames, filename)
+		}
 
-		return nil
+		ctx.files[filename] = path
 	}
+}
 
-	for _, files := range form.File {
-		for _, fh := range files {
-			err = copyToDisk(fh)
+// writePart copies a file of a "multipart/form-data" body into the given
+// path. It stops one byte after the budget, if any, so that the caller may
+// tell the file is too large without reading it fully.
+func (ctx *Context) writePart(part *multipart.Part, path string, budget int64) (int64, error) {
+	out, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("create local file: %w", err)
+	}
 
-			if err != nil {
-				return ctx, cancel, fmt.Errorf("copy to disk: %w", err)
-			}
+	defer func() {
+		err := out.Close()
+		if err != nil {
+			ctx.logger.Error(fmt.Sprintf("close local file: %s", err))
 		}
+	}()
+
+	in := io.Reader(part)
+	if budget >= 0 {
+		in = io.LimitReader(part, budget+1)
 	}
 
-	ctx.Log().Debug(fmt.Sprintf("form fields: %+v", ctx.values))
-	ctx.Log().Debug(fmt.Sprintf("form files: %+v", ctx.files))
+	size, err := io.Copy(out, in)
+	if err != nil {
+		return 0, fmt.Errorf("copy multipart file to local file: %w", err)
+	}
 
-	return ctx, cancel, err
+	return size, nil
 }
 
 // Request returns the [http.Request].
@@ -184,11 +310,144 @@ func (ctx *Context) Request() *http.Request {
 
 // FormData return a [FormData].
 func (ctx *Context) FormData() *FormData {
-	return &FormData{
+	form := &FormData{
 		values: ctx.values,
 		files:  ctx.files,
 		errors: nil,
 	}
+
+	ctx.forms = append(ctx.forms, form)
+
+	return form
+}
+
+// ValidateOnly tells if the client only wants to validate its request, thanks
+// to the "validateOnly" form field. If true, a route handler should return
+// right after its validations, without processing anything.
+func (ctx *Context) ValidateOnly() bool {
+	return ctx.validateOnly
+}
+
+// validationReport returns the parsed form fields, defaults included, and
+// the filenames of the request.
+func (ctx *Context) validationReport() map[string]interface{} {
+	fields := make(map[string]interface{})
+	for _, form := range ctx.forms {
+		for key, value := range form.parsedValues() {
+			fields[key] = value
+		}
+	}
+
+	filenames := make([]string, 0, len(ctx.files))
+	for filename := range ctx.files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	return map[string]interface{}{
+		"formFields": fields,
+		"files":      filenames,
+	}
+}
+
+// DownloadedFilenames returns the filenames of the files downloaded thanks to
+// the "downloadFrom" form field. Those files are also part of the form data,
+// like the uploaded ones.
+func (ctx *Context) DownloadedFilenames() []string {
+	return ctx.downloadedFilenames
+}
+
+// downloadFrom downloads concurrently the files of the "downloadFrom" form
+// field into the context's working directory. This field is a JSON array of
+// entries like {"url": "https://...", "extraHttpHeaders": {"X-Foo": "bar"}}.
+// The downloaded files are then handled like the uploaded ones, and count
+// toward the same input limits.
+func (ctx *Context) downloadFrom(uploadedSize int64) error {
+	value := ""
+	if len(ctx.values["downloadFrom"]) > 0 {
+		value = ctx.values["downloadFrom"][0]
+	}
+
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	if ctx.downloader == nil {
+		return WrapError(
+			errors.New("download from is disabled"),
+			NewSentinelHttpError(http.StatusBadRequest, "Invalid 'downloadFrom' form field: this feature is disabled"),
+		)
+	}
+
+	var entries []downloadFromEntry
+	err := json.Unmarshal([]byte(value), &entries)
+	if err != nil {
+		return WrapError(
+			fmt.Errorf("unmarshal download from: %w", err),
+			NewSentinelHttpError(http.StatusBadRequest, "Invalid 'downloadFrom' form field value: expected a JSON array of objects with an 'url' and optional 'extraHttpHeaders'"),
+		)
+	}
+
+	// The filenames are only known once downloaded.
+	downloadPaths := make([]string, len(entries))
+	filenames := make([]string, len(entries))
+
+	total := new(atomic.Int64)
+	total.Store(uploadedSize)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(maxConcurrentDownloads)
+
+	for i, entry := range entries {
+		i, entry := i, entry
+		downloadPaths[i] = ctx.GeneratePath("")
+
+		eg.Go(func() error {
+			filename, err := ctx.downloader.download(egCtx, entry, downloadPaths[i], ctx.limits, total)
+			if err != nil {
+				return err
+			}
+
+			filenames[i] = filename
+
+			return nil
+		})
+	}
+
+	err = eg.Wait()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(filenames))
+	for i, filename := range filenames {
+		_, exists := ctx.files[filename]
+		if exists || seen[filename] {
+			return WrapError(
+				fmt.Errorf("file '%s' from '%s' already exists", filename, entries[i].Url),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'downloadFrom' form field value: the file '%s' from '%s' conflicts with another file", filename, entries[i].Url)),
+			)
+		}
+
+		seen[filename] = true
+	}
+
+	for i, filename := range filenames {
+		path := fmt.Sprintf("%s/%s", ctx.dirPath, filename)
+
+		err = os.Rename(downloadPaths[i], path)
+		if err != nil {
+			return fmt.Errorf("rename downloaded file: %w", err)
+		}
+
+		ctx.files[filename] = path
+		ctx.filenames = append(ctx.filenames, filename)
+		ctx.downloadedFilenames = append(ctx.downloadedFilenames, filename)
+
+		ctx.Log().Debug(fmt.Sprintf("'%s' downloaded from '%s'", filename, entries[i].Url))
+	}
+
+	return nil
 }
 
 // GeneratePath generates a path within the context's working directory. It
@@ -197,6 +456,32 @@ func (ctx *Context) GeneratePath(extension string) string {
 	return fmt.Sprintf("%s/%s%s", ctx.dirPath, uuid.New(), extension)
 }
 
+// InUploadOrder returns the given paths of form data files in the order the
+// client uploaded them, the downloaded files last. The paths of other files
+// keep their relative order, after them.
+func (ctx *Context) InUploadOrder(paths []string) []string {
+	ranks := make(map[string]int, len(ctx.filenames))
+	for i, filename := range ctx.filenames {
+		ranks[filename] = i
+	}
+
+	rank := func(path string) int {
+		i, ok := ranks[filepath.Base(path)]
+		if !ok {
+			return len(ranks)
+		}
+
+		return i
+	}
+
+	orderedPaths := slices.Clone(paths)
+	slices.SortStableFunc(orderedPaths, func(a, b string) int {
+		return rank(a) - rank(b)
+	})
+
+	return orderedPaths
+}
+
 // AddOutputPaths adds the given paths. Those paths will be used later to build
 // the output file.
 func (ctx *Context) AddOutputPaths(paths ...string) error {
@@ -215,6 +500,112 @@ func (ctx *Context) AddOutputPaths(paths ...string) error {
 	return nil
 }
 
+// OutputPaths returns the paths added so far with [Context.AddOutputPaths].
+func (ctx *Context) OutputPaths() []string {
+	return ctx.outputPaths
+}
+
+// Cacheable tells if identical requests give the same output, i.e., if the
+// output of the route only depends on the form fields and the files of the
+// request, see [Route.DisableCache].
+func (ctx *Context) Cacheable() bool {
+	return ctx.cacheable
+}
+
+// Fingerprint returns a hash of the route path, the form fields and the
+// content of the files of the request, in upload order. Two requests with the
+// same fingerprint should give the same output, whatever the order of their
+// form fields.
+func (ctx *Context) Fingerprint() (string, error) {
+	hash := sha256.New()
+
+	write := func(s string) {
+		// The length prefix avoids the collisions between, e.g., ["ab", "c"]
+		// and ["a", "bc"].
+		_, _ = fmt.Fprintf(hash, "%d:%s", len(s), s)
+	}
+
+	write(ctx.Request().URL.Path)
+
+	keys := make([]string, 0, len(ctx.values))
+	for key := range ctx.values {
+		// This form field does not change the output.
+		if key == "validateOnly" {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		write(key)
+
+		values := ctx.values[key]
+		write(strconv.Itoa(len(values)))
+
+		for _, value := range values {
+			write(value)
+		}
+	}
+
+	filenames := make([]string, 0, len(ctx.files))
+	for filename := range ctx.files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	// The merged outputs follow the upload order, which therefore belongs to
+	// the fingerprint.
+	for _, filename := range ctx.InUploadOrder(filenames) {
+		write(filename)
+
+		err := func() error {
+			file, err := os.Open(ctx.files[filename])
+			if err != nil {
+				return fmt.Errorf("open file '%s': %w", filename, err)
+			}
+
+			defer func() {
+				_ = file.Close()
+			}()
+
+			fileHash := sha256.New()
+			_, err = io.Copy(fileHash, file)
+			if err != nil {
+				return fmt.Errorf("hash file '%s': %w", filename, err)
+			}
+
+			write(hex.EncodeToString(fileHash.Sum(nil)))
+
+			return nil
+		}()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ShutdownError returns an [HttpError] with the SHUTTING_DOWN code if the
+// given error stems from the abortion of the request by a shutdown, i.e.,
+// once its grace period is over. It returns the other errors as is, even the
+// ones of a context cancelled for another reason.
+func (ctx *Context) ShutdownError(err error) error {
+	if !ctx.aborted.Load() || !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return shuttingDownError(err)
+}
+
+// Trace returns the identifier of the request. The [zap.Logger] of the
+// context already logs it.
+func (ctx *Context) Trace() string {
+	return ctx.trace
+}
+
 // Log returns the context [zap.Logger].
 func (ctx *Context) Log() *zap.Logger {
 	return ctx.logger
@@ -2,7 +2,9 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -22,6 +24,7 @@ func TestNewContext(t *testing.T) {
 	for _, tc := range []struct {
 		scenario         string
 		request          *http.Request
+		limits           inputLimits
 		expectError      bool
 		expectHttpError  bool
 		expectHttpStatus int
@@ -67,6 +70,28 @@ func TestNewContext(t *testing.T) {
 			expectHttpError:  true,
 			expectHttpStatus: http.StatusBadRequest,
 		},
+		{
+			scenario: "truncated body",
+			request: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				part, err := writer.CreateFormFile("foo.txt", "foo.txt")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				_, err = part.Write([]byte("foo"))
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				// No closing boundary.
+				req := httptest.NewRequest(http.MethodPost, "/", body)
+				req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+				return req
+			}(),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
 		{
 			scenario: "success",
 			request: func() *http.Request {
@@ -97,10 +122,129 @@ func TestNewContext(t *testing.T) {
 			expectError:     false,
 			expectHttpError: false,
 		},
+		{
+			scenario: "file exceeds the maximum file size",
+			request: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				defer func() {
+					err := writer.Close()
+					if err != nil {
+						t.Fatalf("expected no error but got: %v", err)
+					}
+				}()
+				part, err := writer.CreateFormFile("files", "foo.txt")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				_, err = part.Write([]byte("foo"))
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				req := httptest.NewRequest(http.MethodPost, "/", body)
+				req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+				return req
+			}(),
+			limits:           inputLimits{maxFileSize: 2},
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario: "files exceed the maximum total file size",
+			request: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				defer func() {
+					err := writer.Close()
+					if err != nil {
+						t.Fatalf("expected no error but got: %v", err)
+					}
+				}()
+				part, err := writer.CreateFormFile("files", "foo.txt")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				_, err = part.Write([]byte("foo"))
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				part, err = writer.CreateFormFile("files", "bar.txt")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				_, err = part.Write([]byte("bar"))
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				req := httptest.NewRequest(http.MethodPost, "/", body)
+				req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+				return req
+			}(),
+			limits:           inputLimits{maxFileSize: 3, maxTotalFileSize: 5},
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario: "PDF exceeds the maximum number of pages",
+			request: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				defer func() {
+					err := writer.Close()
+					if err != nil {
+						t.Fatalf("expected no error but got: %v", err)
+					}
+				}()
+				part, err := writer.CreateFormFile("files", "foo.pdf")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				_, err = part.Write(newTestPdf(3))
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				req := httptest.NewRequest(http.MethodPost, "/", body)
+				req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+				return req
+			}(),
+			limits:           inputLimits{maxPdfPages: 2},
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "success with input limits",
+			request: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+				defer func() {
+					err := writer.Close()
+					if err != nil {
+						t.Fatalf("expected no error but got: %v", err)
+					}
+				}()
+				part, err := writer.CreateFormFile("files", "foo.pdf")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				_, err = part.Write(newTestPdf(2))
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				req := httptest.NewRequest(http.MethodPost, "/", body)
+				req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+				return req
+			}(),
+			limits:          inputLimits{maxFileSize: 10000, maxTotalFileSize: 10000, maxPdfPages: 2},
+			expectError:     false,
+			expectHttpError: false,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			handler := func(c echo.Context) error {
-				_, cancel, err := newContext(c, zap.NewNop(), gotenberg.NewFileSystem(), time.Duration(10)*time.Second)
+				_, cancel, err := newContext(c, zap.NewNop(), gotenberg.NewFileSystem(), time.Duration(10)*time.Second, tc.limits, nil)
 				defer cancel()
 				// Context already cancelled.
 				defer cancel()
@@ -185,6 +329,60 @@ func TestContext_FormData(t *testing.T) {
 	}
 }
 
+func TestContext_validationReport(t *testing.T) {
+	ctx := &Context{
+		values: map[string][]string{
+			"foo":           {"true"},
+			"bar":           {"1s"},
+			"custom":        {"baz"},
+			"userPassword":  {"secret"},
+			"proxyUsername": {"secret"},
+			"passwords":     {"secret"},
+		},
+		files: map[string]string{
+			"foo.txt": "/foo.txt",
+			"bar.txt": "/bar.txt",
+		},
+	}
+
+	var (
+		foo, qux                    bool
+		bar                         time.Duration
+		userPassword, ownerPassword string
+		passwords                   []string
+	)
+
+	ctx.FormData().
+		Bool("foo", &foo, false).
+		Duration("bar", &bar, 0).
+		Custom("custom", func(value string) error { return nil }).
+		Custom("empty", func(value string) error { return nil }).
+		String("userPassword", &userPassword, "").
+		String("ownerPassword", &ownerPassword, "").
+		Custom("proxyUsername", func(value string) error { return nil }).
+		Strings("passwords", &passwords)
+	ctx.FormData().Bool("qux", &qux, true)
+
+	actual := ctx.validationReport()
+	expect := map[string]interface{}{
+		"formFields": map[string]interface{}{
+			"foo":           true,
+			"bar":           "1s",
+			"custom":        "baz",
+			"qux":           true,
+			"userPassword":  redacted,
+			"ownerPassword": "",
+			"proxyUsername": redacted,
+			"passwords":     []string{redacted},
+		},
+		"files": []string{"bar.txt", "foo.txt"},
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+}
+
 func TestContext_GeneratePath(t *testing.T) {
 	ctx := &Context{
 		dirPath: "/foo",
@@ -197,6 +395,19 @@ func TestContext_GeneratePath(t *testing.T) {
 	}
 }
 
+func TestContext_InUploadOrder(t *testing.T) {
+	ctx := &Context{
+		filenames: []string{"b.html", "c.html", "a.html"},
+	}
+
+	actual := ctx.InUploadOrder([]string{"/foo/a.html", "/foo/z.html", "/foo/b.html", "/foo/y.html", "/foo/c.html"})
+	expect := []string{"/foo/b.html", "/foo/c.html", "/foo/a.html", "/foo/z.html", "/foo/y.html"}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+}
+
 func TestContext_AddOutputPaths(t *testing.T) {
 	for _, tc := range []struct {
 		scenario    string
@@ -244,6 +455,168 @@ func TestContext_AddOutputPaths(t *testing.T) {
 	}
 }
 
+func TestContext_Fingerprint(t *testing.T) {
+	dirPath := t.TempDir()
+
+	fooPath := dirPath + "/foo.txt"
+	err := os.WriteFile(fooPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	barPath := dirPath + "/bar.txt"
+	err = os.WriteFile(barPath, []byte("bar"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	fingerprint := func(path string, values map[string][]string, files map[string]string) string {
+		ctx := &Context{
+			values:  values,
+			files:   files,
+			echoCtx: echo.New().NewContext(httptest.NewRequest(http.MethodPost, path, nil), httptest.NewRecorder()),
+		}
+
+		hash, err := ctx.Fingerprint()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return hash
+	}
+
+	uploadOrderFingerprint := func(filenames []string) string {
+		ctx := &Context{
+			files:     map[string]string{"foo.txt": fooPath, "bar.txt": barPath},
+			filenames: filenames,
+			echoCtx:   echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/forms/foo", nil), httptest.NewRecorder()),
+		}
+
+		hash, err := ctx.Fingerprint()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return hash
+	}
+
+	expect := fingerprint("/forms/foo", map[string][]string{"foo": {"bar"}}, map[string]string{"foo.txt": fooPath})
+
+	for _, tc := range []struct {
+		scenario    string
+		path        string
+		values      map[string][]string
+		files       map[string]string
+		expectEqual bool
+	}{
+		{
+			scenario:    "same request",
+			path:        "/forms/foo",
+			values:      map[string][]string{"foo": {"bar"}},
+			files:       map[string]string{"foo.txt": fooPath},
+			expectEqual: true,
+		},
+		{
+			scenario:    "validateOnly form field",
+			path:        "/forms/foo",
+			values:      map[string][]string{"foo": {"bar"}, "validateOnly": {"true"}},
+			files:       map[string]string{"foo.txt": fooPath},
+			expectEqual: true,
+		},
+		{
+			scenario:    "different route",
+			path:        "/forms/bar",
+			values:      map[string][]string{"foo": {"bar"}},
+			files:       map[string]string{"foo.txt": fooPath},
+			expectEqual: false,
+		},
+		{
+			scenario:    "different form field",
+			path:        "/forms/foo",
+			values:      map[string][]string{"foo": {"baz"}},
+			files:       map[string]string{"foo.txt": fooPath},
+			expectEqual: false,
+		},
+		{
+			scenario:    "different file content",
+			path:        "/forms/foo",
+			values:      map[string][]string{"foo": {"bar"}},
+			files:       map[string]string{"foo.txt": barPath},
+			expectEqual: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := fingerprint(tc.path, tc.values, tc.files)
+
+			if tc.expectEqual && actual != expect {
+				t.Errorf("expected fingerprint '%s' but got '%s'", expect, actual)
+			}
+
+			if !tc.expectEqual && actual == expect {
+				t.Errorf("expected a fingerprint different from '%s'", expect)
+			}
+		})
+	}
+
+	// The merged outputs follow the upload order.
+	fooFirst := uploadOrderFingerprint([]string{"foo.txt", "bar.txt"})
+	barFirst := uploadOrderFingerprint([]string{"bar.txt", "foo.txt"})
+	if fooFirst == barFirst {
+		t.Errorf("expected different fingerprints for different upload orders, but got '%s'", fooFirst)
+	}
+
+	if uploadOrderFingerprint([]string{"foo.txt", "bar.txt"}) != fooFirst {
+		t.Error("expected the same fingerprint for the same upload order")
+	}
+
+	ctx := &Context{
+		files:   map[string]string{"foo.txt": dirPath + "/missing.txt"},
+		echoCtx: echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/forms/foo", nil), httptest.NewRecorder()),
+	}
+
+	_, err = ctx.Fingerprint()
+	if err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestContext_ShutdownError(t *testing.T) {
+	for _, tc := range []struct {
+		scenario   string
+		aborted    bool
+		err        error
+		expectCode string
+	}{
+		{
+			scenario:   "context cancelled by a shutdown",
+			aborted:    true,
+			err:        fmt.Errorf("foo: %w", context.Canceled),
+			expectCode: "SHUTTING_DOWN",
+		},
+		{
+			scenario:   "context cancelled for another reason",
+			err:        fmt.Errorf("foo: %w", context.Canceled),
+			expectCode: "SERVICE_UNAVAILABLE",
+		},
+		{
+			scenario:   "other error during a shutdown",
+			aborted:    true,
+			err:        errors.New("foo"),
+			expectCode: "INTERNAL_SERVER_ERROR",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := new(Context)
+			ctx.aborted.Store(tc.aborted)
+
+			code := ErrorCode(ctx.ShutdownError(tc.err))
+			if code != tc.expectCode {
+				t.Errorf("expected code '%s' but got '%s'", tc.expectCode, code)
+			}
+		})
+	}
+}
+
 func TestContext_Log(t *testing.T) {
 	expect := zap.NewNop()
 	ctx := Context{logger: expect}
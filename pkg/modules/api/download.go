@@ -0,0 +1,339 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/labstack/gommon/bytes"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// errForbiddenAddress happens when a download targets a URL or an IP
+	// address the [downloader] must not connect to.
+	errForbiddenAddress = errors.New("forbidden address")
+
+	// errTotalSizeExceeded happens when the files of a request exceed the
+	// maximum total size.
+	errTotalSizeExceeded = errors.New("total size exceeded")
+)
+
+// maxConcurrentDownloads is the maximum number of files a request downloads
+// at the same time.
+const maxConcurrentDownloads = 8
+
+// metadataAddrs are the addresses of the cloud metadata endpoints which are
+// not link-local.
+var metadataAddrs = []netip.Addr{
+	netip.MustParseAddr("100.100.100.200"), // Alibaba Cloud.
+	netip.MustParseAddr("fd00:ec2::254"),   // AWS, IPv6.
+}
+
+var (
+	// sharedPrefix is the shared address space of carrier-grade NATs, which
+	// also holds the Alibaba Cloud metadata endpoints. It is private to the
+	// network of the provider.
+	sharedPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+	// nat64Prefix is the well-known prefix of NAT64, which embeds an IPv4
+	// address in its last 32 bits.
+	nat64Prefix = netip.MustParsePrefix("64:ff9b::/96")
+)
+
+// downloadFromEntry is an entry of the "downloadFrom" form field.
+type downloadFromEntry struct {
+	Url              string            `json:"url"`
+	ExtraHttpHeaders map[string]string `json:"extraHttpHeaders"`
+}
+
+// downloader fetches the remote files of the "downloadFrom" form field. It
+// guards against server-side request forgery: the URLs must match the allowed
+// / denied lists, and it never connects to link-local or metadata addresses,
+// nor to loopback, private or shared addresses unless allowed.
+type downloader struct {
+	allowList       *regexp.Regexp
+	denyList        *regexp.Regexp
+	allowPrivateIps bool
+	client          *http.Client
+}
+
+func newDownloader(allowList, denyList *regexp.Regexp, allowPrivateIps bool) *downloader {
+	d := &downloader{
+		allowList:       allowList,
+		denyList:        denyList,
+		allowPrivateIps: allowPrivateIps,
+	}
+
+	d.client = &http.Client{
+		Transport: NewGuardedTransport(allowPrivateIps),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+
+			return d.checkUrl(req.URL)
+		},
+	}
+
+	return d
+}
+
+// checkUrl returns an error if the URL is not an HTTP(S) URL or if it does not
+// match the allowed / denied lists.
+func (d *downloader) checkUrl(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("'%s' is not an HTTP(S) URL: %w", u, errForbiddenAddress)
+	}
+
+	if !d.allowList.MatchString(u.String()) {
+		return fmt.Errorf("'%s' does not match the expression from the allowed list: %w", u, errForbiddenAddress)
+	}
+
+	if d.denyList.String() != "" && d.denyList.MatchString(u.String()) {
+		return fmt.Errorf("'%s' matches the expression from the denied list: %w", u, errForbiddenAddress)
+	}
+
+	return nil
+}
+
+// checkAddress returns an error if the downloader must not connect to the
+// given "ip:port" address.
+func (d *downloader) checkAddress(address string) error {
+	return CheckAddress(address, d.allowPrivateIps)
+}
+
+// NewGuardedTransport returns an [http.Transport] which never connects to
+// link-local or cloud metadata addresses, nor to loopback, private or shared
+// addresses unless allowed. The check of the IP addresses happens when
+// dialing, i.e., after the DNS resolution, so that a hostname cannot point to
+// a forbidden address. A proxy would hide the actual address, hence none.
+func NewGuardedTransport(allowPrivateIps bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: time.Duration(30) * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			return CheckAddress(address, allowPrivateIps)
+		},
+	}
+
+	return &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   time.Duration(10) * time.Second,
+		ExpectContinueTimeout: time.Duration(1) * time.Second,
+	}
+}
+
+// CheckAddress returns an error if an outgoing request must not connect to
+// the given "ip:port" address, i.e., a link-local, multicast, unspecified or
+// cloud metadata address, or a loopback, private or shared address unless
+// allowed. A NAT64 address gets the checks of the IPv4 address it embeds.
+func CheckAddress(address string, allowPrivateIps bool) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("split host and port of '%s': %w", address, err)
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("parse IP address '%s': %w", host, err)
+	}
+
+	addr = addr.Unmap()
+	if nat64Prefix.Contains(addr) {
+		b := addr.As16()
+		addr = netip.AddrFrom4([4]byte{b[12], b[13], b[14], b[15]})
+	}
+
+	if addr.IsUnspecified() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return fmt.Errorf("'%s' is a link-local, multicast or unspecified address: %w", addr, errForbiddenAddress)
+	}
+
+	for _, metadataAddr := range metadataAddrs {
+		if addr == metadataAddr {
+			return fmt.Errorf("'%s' is a cloud metadata address: %w", addr, errForbiddenAddress)
+		}
+	}
+
+	if !allowPrivateIps && (addr.IsLoopback() || addr.IsPrivate() || sharedPrefix.Contains(addr)) {
+		return fmt.Errorf("'%s' is a loopback, private or shared address: %w", addr, errForbiddenAddress)
+	}
+
+	return nil
+}
+
+// download fetches the file of the given entry to the given path. The
+// maximum file size applies to the file, while the maximum total file size
+// applies to the given total, shared by all the files of the request. It
+// returns the filename of the downloaded file.
+func (d *downloader) download(ctx context.Context, entry downloadFromEntry, outputPath string, limits inputLimits, total *atomic.Int64) (string, error) {
+	u, err := url.Parse(entry.Url)
+	if err != nil {
+		return "", WrapError(
+			fmt.Errorf("parse URL '%s': %w", entry.Url, err),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'downloadFrom' form field value: '%s' is not a valid URL", entry.Url)),
+		)
+	}
+
+	err = d.checkUrl(u)
+	if err != nil {
+		return "", WrapError(
+			err,
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'downloadFrom' form field value: '%s' does not match the authorized URLs", entry.Url)),
+		)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+
+	for key, value := range entry.ExtraHttpHeaders {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("download from '%s': %w", entry.Url, ctx.Err())
+		}
+
+		if errors.Is(err, errForbiddenAddress) {
+			return "", WrapError(
+				fmt.Errorf("download from '%s': %w", entry.Url, err),
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'downloadFrom' form field value: '%s' does not match the authorized URLs", entry.Url)),
+			)
+		}
+
+		return "", WrapError(
+			fmt.Errorf("download from '%s': %w", entry.Url, err),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Unable to download the file from '%s'", entry.Url)),
+		)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", WrapError(
+			fmt.Errorf("download from '%s': unexpected status code %d", entry.Url, resp.StatusCode),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Unable to download the file from '%s': status code %d", entry.Url, resp.StatusCode)),
+		)
+	}
+
+	tooLargeErr := func(size int64) error {
+		return WrapError(
+			fmt.Errorf("file from '%s' of at least %d bytes exceeds the maximum file size of %d bytes", entry.Url, size, limits.maxFileSize),
+			NewSentinelHttpError(
+				http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("The file from '%s' exceeds the maximum file size of %s", entry.Url, bytes.Format(limits.maxFileSize)),
+			),
+		)
+	}
+
+	totalTooLargeErr := func() error {
+		return WrapError(
+			fmt.Errorf("file from '%s': files exceed the maximum total file size of %d bytes", entry.Url, limits.maxTotalFileSize),
+			NewSentinelHttpError(
+				http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("The file from '%s' makes the files exceed the maximum total file size of %s", entry.Url, bytes.Format(limits.maxTotalFileSize)),
+			),
+		)
+	}
+
+	if limits.maxFileSize > 0 && resp.ContentLength > limits.maxFileSize {
+		return "", tooLargeErr(resp.ContentLength)
+	}
+
+	if limits.maxTotalFileSize > 0 && total.Load()+resp.ContentLength > limits.maxTotalFileSize {
+		return "", totalTooLargeErr()
+	}
+
+	filename := downloadFilename(u, resp.Header.Get("Content-Disposition"))
+	if filename == "" {
+		return "", WrapError(
+			fmt.Errorf("no filename for '%s'", entry.Url),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Unable to determine the filename of the file from '%s'", entry.Url)),
+		)
+	}
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return "", fmt.Errorf("create local file: %w", err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	// The Content-Length header is not mandatory, nor trustworthy.
+	body := io.Reader(resp.Body)
+	if limits.maxFileSize > 0 {
+		body = io.LimitReader(resp.Body, limits.maxFileSize+1)
+	}
+
+	written, err := io.Copy(&totalSizeWriter{w: out, total: total, max: limits.maxTotalFileSize}, body)
+	if errors.Is(err, errTotalSizeExceeded) {
+		return "", totalTooLargeErr()
+	}
+	if err != nil {
+		return "", fmt.Errorf("copy response body from '%s' to local file: %w", entry.Url, err)
+	}
+
+	if limits.maxFileSize > 0 && written > limits.maxFileSize {
+		return "", tooLargeErr(written)
+	}
+
+	return filename, nil
+}
+
+// downloadFilename returns the filename of a download, either from the
+// Content-Disposition header or from the URL path. As for the uploaded files,
+// it avoids directory traversal and normalizes the characters.
+func downloadFilename(u *url.URL, contentDisposition string) string {
+	filename := path.Base(u.Path)
+
+	_, params, err := mime.ParseMediaType(contentDisposition)
+	if err == nil && params["filename"] != "" {
+		filename = params["filename"]
+	}
+
+	filename = norm.NFC.String(filepath.Base(filename))
+	if filename == "." || filename == "/" || filename == ".." {
+		return ""
+	}
+
+	return filename
+}
+
+// totalSizeWriter counts the bytes written by all the files of a request, and
+// fails once they exceed the maximum total size.
+type totalSizeWriter struct {
+	w     io.Writer
+	total *atomic.Int64
+	max   int64
+}
+
+// Write implements [io.Writer].
+func (w *totalSizeWriter) Write(p []byte) (int, error) {
+	if w.total.Add(int64(len(p))) > w.max && w.max > 0 {
+		return 0, errTotalSizeExceeded
+	}
+
+	return w.w.Write(p)
+}
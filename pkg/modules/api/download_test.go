@@ -0,0 +1,506 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestDownloader_CheckAddress(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		address         string
+		allowPrivateIps bool
+		expectError     bool
+	}{
+		{
+			scenario:    "public IPv4 address",
+			address:     "93.184.216.34:443",
+			expectError: false,
+		},
+		{
+			scenario:    "public IPv6 address",
+			address:     "[2606:2800:220:1:248:1893:25c8:1946]:443",
+			expectError: false,
+		},
+		{
+			scenario:    "cloud metadata address",
+			address:     "169.254.169.254:80",
+			expectError: true,
+		},
+		{
+			scenario:        "cloud metadata address with private IPs allowed",
+			address:         "169.254.169.254:80",
+			allowPrivateIps: true,
+			expectError:     true,
+		},
+		{
+			scenario:        "IPv6 cloud metadata address with private IPs allowed",
+			address:         "[fd00:ec2::254]:80",
+			allowPrivateIps: true,
+			expectError:     true,
+		},
+		{
+			scenario:    "IPv4-mapped IPv6 link-local address",
+			address:     "[::ffff:169.254.169.254]:80",
+			expectError: true,
+		},
+		{
+			scenario:    "unspecified address",
+			address:     "0.0.0.0:80",
+			expectError: true,
+		},
+		{
+			scenario:    "loopback address",
+			address:     "127.0.0.1:80",
+			expectError: true,
+		},
+		{
+			scenario:    "private address",
+			address:     "10.0.0.1:80",
+			expectError: true,
+		},
+		{
+			scenario:        "private address with private IPs allowed",
+			address:         "10.0.0.1:80",
+			allowPrivateIps: true,
+			expectError:     false,
+		},
+		{
+			scenario:    "shared address",
+			address:     "100.100.100.1:80",
+			expectError: true,
+		},
+		{
+			scenario:        "shared address with private IPs allowed",
+			address:         "100.64.0.1:80",
+			allowPrivateIps: true,
+			expectError:     false,
+		},
+		{
+			scenario:        "cloud metadata address in the shared address space with private IPs allowed",
+			address:         "100.100.100.200:80",
+			allowPrivateIps: true,
+			expectError:     true,
+		},
+		{
+			scenario:        "NAT64 link-local address with private IPs allowed",
+			address:         "[64:ff9b::a9fe:a9fe]:80",
+			allowPrivateIps: true,
+			expectError:     true,
+		},
+		{
+			scenario:    "NAT64 private address",
+			address:     "[64:ff9b::10.0.0.1]:80",
+			expectError: true,
+		},
+		{
+			scenario:    "NAT64 public address",
+			address:     "[64:ff9b::93.184.216.34]:443",
+			expectError: false,
+		},
+		{
+			scenario:    "invalid address",
+			address:     "foo",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			d := newDownloader(regexp.MustCompile(""), regexp.MustCompile(""), tc.allowPrivateIps)
+			err := d.checkAddress(tc.address)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestDownloader_Download(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/document.docx":
+			if r.Header.Get("Authorization") != "Bearer foo" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte("foo"))
+		case "/download":
+			w.Header().Set("Content-Disposition", `attachment; filename="../../report.xlsx"`)
+			_, _ = w.Write([]byte("foo"))
+		case "/chunked.docx":
+			_, _ = w.Write([]byte("foo"))
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte("bar"))
+		case "/redirect.docx":
+			http.Redirect(w, r, "/forbidden.docx", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		scenario         string
+		entry            downloadFromEntry
+		allowList        string
+		denyList         string
+		allowPrivateIps  bool
+		limits           inputLimits
+		expectFilename   string
+		expectHttpStatus int
+	}{
+		{
+			scenario:         "not an HTTP URL",
+			entry:            downloadFromEntry{Url: "file:///etc/passwd"},
+			allowPrivateIps:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "URL not allowed",
+			entry:            downloadFromEntry{Url: fmt.Sprintf("%s/document.docx", srv.URL)},
+			allowList:        "^https://example.com",
+			allowPrivateIps:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "URL denied",
+			entry:            downloadFromEntry{Url: fmt.Sprintf("%s/document.docx", srv.URL)},
+			denyList:         "document",
+			allowPrivateIps:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "redirect to a denied URL",
+			entry:            downloadFromEntry{Url: fmt.Sprintf("%s/redirect.docx", srv.URL)},
+			denyList:         "forbidden",
+			allowPrivateIps:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "loopback address",
+			entry:            downloadFromEntry{Url: fmt.Sprintf("%s/document.docx", srv.URL)},
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "unexpected status code",
+			entry:            downloadFromEntry{Url: fmt.Sprintf("%s/document.docx", srv.URL)},
+			allowPrivateIps:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "file too large",
+			entry: downloadFromEntry{
+				Url:              fmt.Sprintf("%s/document.docx", srv.URL),
+				ExtraHttpHeaders: map[string]string{"Authorization": "Bearer foo"},
+			},
+			allowPrivateIps:  true,
+			limits:           inputLimits{maxFileSize: 2},
+			expectHttpStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario:         "file too large without Content-Length",
+			entry:            downloadFromEntry{Url: fmt.Sprintf("%s/chunked.docx", srv.URL)},
+			allowPrivateIps:  true,
+			limits:           inputLimits{maxFileSize: 4},
+			expectHttpStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario:         "files too large without Content-Length",
+			entry:            downloadFromEntry{Url: fmt.Sprintf("%s/chunked.docx", srv.URL)},
+			allowPrivateIps:  true,
+			limits:           inputLimits{maxTotalFileSize: 4},
+			expectHttpStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario: "success",
+			entry: downloadFromEntry{
+				Url:              fmt.Sprintf("%s/document.docx", srv.URL),
+				ExtraHttpHeaders: map[string]string{"Authorization": "Bearer foo"},
+			},
+			allowPrivateIps: true,
+			limits:          inputLimits{maxFileSize: 3, maxTotalFileSize: 3},
+			expectFilename:  "document.docx",
+		},
+		{
+			scenario:        "success with Content-Disposition header",
+			entry:           downloadFromEntry{Url: fmt.Sprintf("%s/download", srv.URL)},
+			allowPrivateIps: true,
+			expectFilename:  "report.xlsx",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			d := newDownloader(regexp.MustCompile(tc.allowList), regexp.MustCompile(tc.denyList), tc.allowPrivateIps)
+
+			dirPath, err := gotenberg.NewFileSystem().MkdirAll()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			defer func() {
+				err := os.RemoveAll(dirPath)
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			filename, err := d.download(context.Background(), tc.entry, fmt.Sprintf("%s/download", dirPath), tc.limits, new(atomic.Int64))
+
+			if tc.expectHttpStatus != 0 {
+				var httpErr HttpError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("expected an HttpError but got: %v", err)
+				}
+
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if filename != tc.expectFilename {
+				t.Errorf("expected filename '%s' but got '%s'", tc.expectFilename, filename)
+			}
+		})
+	}
+}
+
+func TestDownloadFilename(t *testing.T) {
+	for _, tc := range []struct {
+		scenario           string
+		rawUrl             string
+		contentDisposition string
+		expect             string
+	}{
+		{
+			scenario: "filename from URL",
+			rawUrl:   "https://example.com/foo/document.docx?bar=baz",
+			expect:   "document.docx",
+		},
+		{
+			scenario:           "filename from Content-Disposition header",
+			rawUrl:             "https://example.com/download",
+			contentDisposition: `attachment; filename="document.docx"`,
+			expect:             "document.docx",
+		},
+		{
+			scenario:           "directory traversal",
+			rawUrl:             "https://example.com/download",
+			contentDisposition: `attachment; filename="../../document.docx"`,
+			expect:             "document.docx",
+		},
+		{
+			scenario: "no filename",
+			rawUrl:   "https://example.com/",
+			expect:   "",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			u, err := url.Parse(tc.rawUrl)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			actual := downloadFilename(u, tc.contentDisposition)
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestContext_DownloadFrom(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/not-found.docx" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("foo"))
+	}))
+	defer srv.Close()
+
+	for _, tc := range []struct {
+		scenario         string
+		values           map[string][]string
+		files            map[string]string
+		uploadedSize     int64
+		limits           inputLimits
+		downloader       *downloader
+		expectFilenames  []string
+		expectError      bool
+		expectHttpError  bool
+		expectHttpStatus int
+	}{
+		{
+			scenario:        "no downloadFrom form field",
+			downloader:      nil,
+			expectFilenames: nil,
+		},
+		{
+			scenario: "downloadFrom disabled",
+			values: map[string][]string{
+				"downloadFrom": {fmt.Sprintf(`[{"url":"%s/document.docx"}]`, srv.URL)},
+			},
+			downloader:       nil,
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "invalid downloadFrom form field",
+			values: map[string][]string{
+				"downloadFrom": {"foo"},
+			},
+			downloader:       newDownloader(regexp.MustCompile(""), regexp.MustCompile(""), true),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "conflict with an uploaded file",
+			values: map[string][]string{
+				"downloadFrom": {fmt.Sprintf(`[{"url":"%s/document.docx"}]`, srv.URL)},
+			},
+			files: map[string]string{
+				"document.docx": "/document.docx",
+			},
+			downloader:       newDownloader(regexp.MustCompile(""), regexp.MustCompile(""), true),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "conflict between downloaded files",
+			values: map[string][]string{
+				"downloadFrom": {fmt.Sprintf(`[{"url":"%s/document.docx"},{"url":"%s/foo/document.docx"}]`, srv.URL, srv.URL)},
+			},
+			downloader:       newDownloader(regexp.MustCompile(""), regexp.MustCompile(""), true),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "uploaded and downloaded files too large",
+			values: map[string][]string{
+				"downloadFrom": {fmt.Sprintf(`[{"url":"%s/document.docx"}]`, srv.URL)},
+			},
+			uploadedSize:     8,
+			limits:           inputLimits{maxTotalFileSize: 10},
+			downloader:       newDownloader(regexp.MustCompile(""), regexp.MustCompile(""), true),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario: "failing download among many",
+			values: map[string][]string{
+				"downloadFrom": {fmt.Sprintf(`[{"url":"%s/document.docx"},{"url":"%s/not-found.docx"}]`, srv.URL, srv.URL)},
+			},
+			downloader:       newDownloader(regexp.MustCompile(""), regexp.MustCompile(""), true),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "success",
+			values: map[string][]string{
+				"downloadFrom": {fmt.Sprintf(`[{"url":"%s/document.docx"},{"url":"%s/sheet.xlsx"}]`, srv.URL, srv.URL)},
+			},
+			uploadedSize:    4,
+			limits:          inputLimits{maxFileSize: 3, maxTotalFileSize: 10},
+			downloader:      newDownloader(regexp.MustCompile(""), regexp.MustCompile(""), true),
+			expectFilenames: []string{"document.docx", "sheet.xlsx"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath, err := gotenberg.NewFileSystem().MkdirAll()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			defer func() {
+				err := os.RemoveAll(dirPath)
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			files := make(map[string]string)
+			for filename, path := range tc.files {
+				files[filename] = path
+			}
+
+			ctx := &Context{
+				dirPath:    dirPath,
+				values:     tc.values,
+				files:      files,
+				limits:     tc.limits,
+				downloader: tc.downloader,
+				logger:     zap.NewNop(),
+				Context:    context.Background(),
+			}
+
+			err = ctx.downloadFrom(tc.uploadedSize)
+			filenames := ctx.DownloadedFilenames()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr HttpError
+			isHttpErr := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpErr {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if tc.expectHttpError && isHttpErr {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if len(filenames) != len(tc.expectFilenames) {
+				t.Fatalf("expected %d filenames but got %d", len(tc.expectFilenames), len(filenames))
+			}
+
+			for _, filename := range tc.expectFilenames {
+				path, ok := ctx.files[filename]
+				if !ok {
+					t.Fatalf("expected file '%s' in the context", filename)
+				}
+
+				_, err = os.Stat(path)
+				if err != nil {
+					t.Errorf("expected file '%s' to exist but got: %v", path, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// abortTimeout is the duration given to the aborted requests, once the grace
+// period is over, to fail; e.g., to notify their webhook error URL.
+const abortTimeout = time.Duration(5) * time.Second
+
+// drainer keeps track of the in-flight multipart/form-data requests, the
+// asynchronous ones included, so that a shutdown may wait for them.
+type drainer struct {
+	draining bool
+	inFlight int64
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+
+	// ctx is done when the grace period is over. The in-flight requests are
+	// then aborted.
+	ctx   context.Context
+	abort context.CancelFunc
+}
+
+func newDrainer() *drainer {
+	ctx, abort := context.WithCancel(context.Background())
+
+	return &drainer{
+		ctx:   ctx,
+		abort: abort,
+	}
+}
+
+// track registers a new in-flight request, unless the server is draining.
+// The returned function must be called once the request is done; it is safe
+// to call it many times.
+func (d *drainer) track() (func(), bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.draining {
+		return nil, false
+	}
+
+	d.inFlight++
+	d.wg.Add(1)
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			d.mu.Lock()
+			d.inFlight--
+			d.mu.Unlock()
+
+			d.wg.Done()
+		})
+	}, true
+}
+
+// isDraining tells if the server does not accept new requests anymore.
+func (d *drainer) isDraining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.draining
+}
+
+// count returns the number of in-flight requests.
+func (d *drainer) count() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.inFlight
+}
+
+// drain stops the acceptance of new requests and waits for the in-flight ones
+// until the given context is done. It then aborts the remaining requests and
+// waits a little for them to fail. It returns the number of drained and
+// aborted requests.
+func (d *drainer) drain(ctx context.Context) (int64, int64) {
+	d.mu.Lock()
+	d.draining = true
+	total := d.inFlight
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return total, 0
+	case <-ctx.Done():
+	}
+
+	aborted := d.count()
+	d.abort()
+
+	select {
+	case <-done:
+	case <-time.After(abortTimeout):
+	}
+
+	return total - aborted, aborted
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDrainer_drain(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		requests      int
+		finish        bool
+		expectDrained int64
+		expectAborted int64
+	}{
+		{
+			scenario:      "no in-flight request",
+			requests:      0,
+			expectDrained: 0,
+			expectAborted: 0,
+		},
+		{
+			scenario:      "in-flight requests drained",
+			requests:      2,
+			finish:        true,
+			expectDrained: 2,
+			expectAborted: 0,
+		},
+		{
+			scenario:      "in-flight requests aborted",
+			requests:      2,
+			finish:        false,
+			expectDrained: 0,
+			expectAborted: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			d := newDrainer()
+
+			for i := 0; i < tc.requests; i++ {
+				done, ok := d.track()
+				if !ok {
+					t.Fatal("expected the request to be tracked")
+				}
+
+				go func() {
+					if tc.finish {
+						// Finish once the shutdown has started.
+						for !d.isDraining() {
+							time.Sleep(time.Millisecond)
+						}
+
+						done()
+						return
+					}
+
+					// An aborted request fails right away.
+					<-d.ctx.Done()
+					done()
+				}()
+			}
+
+			ctx := context.Background()
+			if !tc.finish {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, time.Duration(10)*time.Millisecond)
+				defer cancel()
+			}
+
+			drained, aborted := d.drain(ctx)
+
+			if drained != tc.expectDrained {
+				t.Errorf("expected %d drained requests but got %d", tc.expectDrained, drained)
+			}
+
+			if aborted != tc.expectAborted {
+				t.Errorf("expected %d aborted requests but got %d", tc.expectAborted, aborted)
+			}
+
+			if !d.isDraining() {
+				t.Error("expected the drainer to be draining")
+			}
+
+			_, ok := d.track()
+			if ok {
+				t.Error("expected a new request to be rejected")
+			}
+		})
+	}
+}
@@ -1,5 +1,16 @@
 package api
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
 // Credits: https://www.joeshaw.org/error-handling-in-go-http-applications.
 
 // HttpError is an interface allowing to retrieve the HTTP details of an error.
@@ -11,6 +22,7 @@ type HttpError interface {
 type SentinelHttpError struct {
 	status  int
 	message string
+	code    string
 }
 
 // NewSentinelHttpError creates a [SentinelHttpError]. The message will be sent
@@ -23,6 +35,14 @@ func NewSentinelHttpError(status int, message string) SentinelHttpError {
 	}
 }
 
+// WithCode returns a copy of the [SentinelHttpError] with a stable,
+// machine-readable code, e.g., "INVALID_FORM_DATA", which takes precedence
+// over the codes registered with [MustRegisterErrorCode].
+func (err SentinelHttpError) WithCode(code string) SentinelHttpError {
+	err.code = code
+	return err
+}
+
 // Error returns the message.
 func (err SentinelHttpError) Error() string {
 	return err.message
@@ -68,6 +88,106 @@ func WrapError(err error, sentinel SentinelHttpError) error {
 	}
 }
 
+// errorCodeMatcher links the errors it matches to a machine-readable code.
+type errorCodeMatcher struct {
+	match func(err error) bool
+	code  string
+}
+
+var (
+	errorCodesMu sync.RWMutex
+	errorCodes   = []errorCodeMatcher{
+		{match: isError(context.DeadlineExceeded), code: "TIMEOUT"},
+		{match: isError(gotenberg.ErrPdfEngineMethodNotSupported), code: "PDF_ENGINE_METHOD_NOT_SUPPORTED"},
+		{match: isError(gotenberg.ErrPdfFormatNotSupported), code: "PDF_FORMAT_NOT_SUPPORTED"},
+		{match: isError(gotenberg.ErrPdfInvalidPassword), code: "INVALID_PDF_PASSWORD"},
+		{match: isError(gotenberg.ErrOcrLanguagesNotInstalled), code: "OCR_LANGUAGES_NOT_INSTALLED"},
+		{match: isError(gotenberg.ErrPdfRedactionTextNotFound), code: "REDACTION_TEXT_NOT_FOUND"},
+		{match: func(err error) bool {
+			var target *gotenberg.PdfOutlineOutOfRangeError
+			return errors.As(err, &target)
+		}, code: "OUTLINE_OUT_OF_RANGE"},
+		{match: func(err error) bool {
+			var target *gotenberg.PdfRedactionOutOfBoundsError
+			return errors.As(err, &target)
+		}, code: "REDACTION_OUT_OF_BOUNDS"},
+		{match: func(err error) bool {
+			var target *gotenberg.PdfCropOutOfBoundsError
+			return errors.As(err, &target)
+		}, code: "CROP_OUT_OF_BOUNDS"},
+	}
+)
+
+func isError(target error) func(err error) bool {
+	return func(err error) bool {
+		return errors.Is(err, target)
+	}
+}
+
+// MustRegisterErrorCode links a sentinel error to a stable, machine-readable
+// code, e.g., "MALFORMED_PAGE_RANGES", so that the JSON error responses of
+// the errors wrapping it carry this code. Modules usually call it in their
+// init function. It panics if the code is empty or already registered.
+//
+//	func init() {
+//	  api.MustRegisterErrorCode(ErrFoo, "FOO")
+//	}
+func MustRegisterErrorCode(err error, code string) {
+	if err == nil || code == "" {
+		panic("error and code must not be empty")
+	}
+
+	errorCodesMu.Lock()
+	defer errorCodesMu.Unlock()
+
+	for _, errorCode := range errorCodes {
+		if errorCode.code == code {
+			panic(fmt.Sprintf("error code '%s' already registered", code))
+		}
+	}
+
+	errorCodes = append(errorCodes, errorCodeMatcher{match: isError(err), code: code})
+}
+
+// ErrorCode returns the machine-readable code of an error, i.e., either the
+// code of its [SentinelHttpError], the code registered for the error it
+// wraps, or a code derived from its HTTP status, e.g., "BAD_REQUEST".
+func ErrorCode(err error) string {
+	status, _ := ParseError(err)
+
+	var wrapped sentinelWrappedError
+	if errors.As(err, &wrapped) {
+		if wrapped.sentinel.code != "" {
+			return wrapped.sentinel.code
+		}
+
+		// The sentinel hides the cause of the error, see Is.
+		err = wrapped.error
+	}
+
+	var sentinel SentinelHttpError
+	if errors.As(err, &sentinel) && sentinel.code != "" {
+		return sentinel.code
+	}
+
+	errorCodesMu.RLock()
+	defer errorCodesMu.RUnlock()
+
+	for _, errorCode := range errorCodes {
+		if errorCode.match(err) {
+			return errorCode.code
+		}
+	}
+
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return '_'
+	}, strings.ToUpper(http.StatusText(status)))
+}
+
 // Interface guards.
 var (
 	_ error     = (*SentinelHttpError)(nil)
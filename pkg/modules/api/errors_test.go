@@ -1,10 +1,16 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 )
 
 func TestNewSentinelHttpError(t *testing.T) {
@@ -106,3 +112,84 @@ func TestWrapError(t *testing.T) {
 		t.Errorf("expected %v but got %v", expect, actual)
 	}
 }
+
+func TestMustRegisterErrorCode(t *testing.T) {
+	errFoo := errors.New("foo")
+	MustRegisterErrorCode(errFoo, "TEST_FOO")
+
+	actual := ErrorCode(fmt.Errorf("bar: %w", errFoo))
+	if actual != "TEST_FOO" {
+		t.Errorf("expected 'TEST_FOO' but got '%s'", actual)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic but got none")
+		}
+	}()
+
+	MustRegisterErrorCode(errors.New("bar"), "TEST_FOO")
+}
+
+func TestErrorCode(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		err      error
+		expect   string
+	}{
+		{
+			scenario: "sentinel with a code",
+			err: WrapError(
+				fmt.Errorf("foo: %w", gotenberg.ErrPdfFormatNotSupported),
+				NewSentinelHttpError(http.StatusBadRequest, "foo").WithCode("FOO"),
+			),
+			expect: "FOO",
+		},
+		{
+			scenario: "sentinel wrapping a registered error",
+			err: WrapError(
+				fmt.Errorf("foo: %w", gotenberg.ErrPdfFormatNotSupported),
+				NewSentinelHttpError(http.StatusBadRequest, "foo"),
+			),
+			expect: "PDF_FORMAT_NOT_SUPPORTED",
+		},
+		{
+			scenario: "sentinel wrapping a registered error type",
+			err: WrapError(
+				fmt.Errorf("foo: %w", &gotenberg.PdfCropOutOfBoundsError{}),
+				NewSentinelHttpError(http.StatusBadRequest, "foo"),
+			),
+			expect: "CROP_OUT_OF_BOUNDS",
+		},
+		{
+			scenario: "sentinel wrapping an unknown error",
+			err: WrapError(
+				errors.New("foo"),
+				NewSentinelHttpError(http.StatusRequestEntityTooLarge, "foo"),
+			),
+			expect: "REQUEST_ENTITY_TOO_LARGE",
+		},
+		{
+			scenario: "echo error",
+			err:      echo.ErrNotFound,
+			expect:   "NOT_FOUND",
+		},
+		{
+			scenario: "cancelled context",
+			err:      fmt.Errorf("foo: %w", context.Canceled),
+			expect:   "SERVICE_UNAVAILABLE",
+		},
+		{
+			scenario: "unknown error",
+			err:      errors.New("foo"),
+			expect:   "INTERNAL_SERVER_ERROR",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := ErrorCode(tc.err)
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
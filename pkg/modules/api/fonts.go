@@ -0,0 +1,44 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// FontExtensions are the extensions of the font files a client may send
+// alongside its documents.
+var FontExtensions = []string{".ttf", ".otf"}
+
+// FormDataFonts binds the ".ttf" and ".otf" form files. It returns the fonts
+// to make available to the conversion, or nil if the client did not send
+// any. It populates an error for each file which is not a valid font.
+//
+//	fonts := api.FormDataFonts(ctx.FormData())
+func FormDataFonts(form *FormData) []gotenberg.Font {
+	var paths []string
+	form.Paths(FontExtensions, &paths)
+
+	if len(paths) == 0 {
+		return nil
+	}
+
+	fonts := make([]gotenberg.Font, 0, len(paths))
+	for _, path := range paths {
+		font, err := gotenberg.ReadFont(path)
+		if errors.Is(err, gotenberg.ErrInvalidFont) {
+			form.append(fmt.Errorf("form file '%s' is not a valid font", filepath.Base(path)))
+			continue
+		}
+		if err != nil {
+			form.append(fmt.Errorf("form file '%s': %w", filepath.Base(path), err))
+			continue
+		}
+
+		fonts = append(fonts, font)
+	}
+
+	return fonts
+}
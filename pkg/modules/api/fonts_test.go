@@ -0,0 +1,79 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestFormDataFonts(t *testing.T) {
+	dirPath := t.TempDir()
+
+	for filename, content := range map[string][]byte{
+		"regular.ttf": goregular.TTF,
+		"bold.TTF":    gobold.TTF,
+		"invalid.otf": []byte("foo"),
+	} {
+		err := os.WriteFile(filepath.Join(dirPath, filename), content, 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		files       []string
+		expectFonts []string
+		expectError bool
+	}{
+		{
+			scenario: "no fonts",
+			files:    []string{"document.docx"},
+		},
+		{
+			scenario:    "valid fonts",
+			files:       []string{"document.docx", "regular.ttf", "bold.TTF"},
+			expectFonts: []string{"Bold", "Regular"},
+		},
+		{
+			scenario:    "invalid font",
+			files:       []string{"regular.ttf", "invalid.otf"},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			form := &FormData{files: make(map[string]string)}
+			for _, filename := range tc.files {
+				form.files[filename] = filepath.Join(dirPath, filename)
+			}
+
+			fonts := FormDataFonts(form)
+			err := form.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if len(fonts) != len(tc.expectFonts) {
+				t.Fatalf("expected %d fonts but got %d", len(tc.expectFonts), len(fonts))
+			}
+
+			for i, font := range fonts {
+				if font.Family != "Go" || font.Style != tc.expectFonts[i] {
+					t.Errorf("expected font 'Go %s' but got '%s %s'", tc.expectFonts[i], font.Family, font.Style)
+				}
+			}
+		})
+	}
+}
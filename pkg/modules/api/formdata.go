@@ -1,7 +1,10 @@
 package api
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -20,6 +23,7 @@ import (
 type FormData struct {
 	values map[string][]string
 	files  map[string]string
+	parsed map[string]interface{}
 	errors error
 }
 
@@ -39,7 +43,7 @@ func (form *FormData) Validate() error {
 
 	return WrapError(
 		form.errors,
-		NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", form.errors)),
+		NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", form.errors)).WithCode("INVALID_FORM_DATA"),
 	)
 }
 
@@ -52,6 +56,25 @@ func (form *FormData) String(key string, target *string, defaultValue string) *F
 	return form.mustValue(key, target, defaultValue)
 }
 
+// Strings binds all the non-empty values of a form field, which may be
+// repeated, to a string slice variable, in order.
+//
+//	var foo []string
+//
+//	ctx.FormData().Strings("foo", &foo)
+func (form *FormData) Strings(key string, target *[]string) *FormData {
+	form.record(key, target)
+
+	*target = make([]string, 0)
+	for _, value := range form.values[key] {
+		if value != "" {
+			*target = append(*target, value)
+		}
+	}
+
+	return form
+}
+
 // MandatoryString binds a form field to a string variable. It populates
 // an error if the value is empty or the "key" does not exist.
 //
@@ -163,7 +186,14 @@ func (form *FormData) MandatoryDuration(key string, target *time.Duration) *Form
 //	})
 func (form *FormData) Custom(key string, assign func(value string) error) *FormData {
 	var value string
-	form.mustValue(key, &value, "")
+	val, ok := form.values[key]
+	if ok {
+		value = val[0]
+	}
+
+	if value != "" {
+		form.record(key, &value)
+	}
 
 	err := assign(value)
 	if err != nil {
@@ -277,16 +307,65 @@ func (form *FormData) Paths(extensions []string, target *[]string) *FormData {
 //	var paths []string
 //
 //	ctx.FormData().MandatoryPaths([]string{".txt"}, &paths)
+//
+// The content of each file must match its extension, e.g., a ".pdf" file must
+// not be a ZIP archive, unless the "trustExtension" form field is true.
 func (form *FormData) MandatoryPaths(extensions []string, target *[]string) *FormData {
-	form.paths(extensions, target)
+	form.mandatoryPaths(extensions, target)
 
 	if len(*target) > 0 {
-		return form
+		var trustExtension bool
+		form.Bool("trustExtension", &trustExtension, false)
+
+		if !trustExtension {
+			for _, path := range *target {
+				form.checkContentType(path)
+			}
+		}
 	}
 
-	form.append(
-		fmt.Errorf("no form file found for extensions: %v", extensions),
-	)
+	return form
+}
+
+// MandatoryPathsUnchecked is like [FormData.MandatoryPaths], but does not
+// check the content of the files against their extension. It is for the
+// callers which detect the actual format of the files themselves, e.g., the
+// LibreOffice routes, which import a document according to its content.
+//
+//	var paths []string
+//
+//	ctx.FormData().MandatoryPathsUnchecked([]string{".docx"}, &paths)
+func (form *FormData) MandatoryPathsUnchecked(extensions []string, target *[]string) *FormData {
+	return form.mandatoryPaths(extensions, target)
+}
+
+// OnlyExtensions populates an error for each form data file which has none
+// of the given file extensions.
+//
+//	ctx.FormData().OnlyExtensions([]string{".png", ".jpg"})
+func (form *FormData) OnlyExtensions(extensions []string) *FormData {
+	filenames := make([]string, 0, len(form.files))
+	for filename := range form.files {
+		filenames = append(filenames, filename)
+	}
+
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		allowed := false
+		for _, ext := range extensions {
+			if strings.ToLower(filepath.Ext(filename)) == normalizeExtension(ext) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			form.append(
+				fmt.Errorf("form file '%s' has an unexpected extension, expected one of %v", filename, extensions),
+			)
+		}
+	}
 
 	return form
 }
@@ -297,7 +376,7 @@ func (form *FormData) paths(extensions []string, target *[]string) *FormData {
 	for filename, path := range form.files {
 		for _, ext := range extensions {
 			// See https://github.com/gotenberg/gotenberg/issues/228.
-			if strings.ToLower(filepath.Ext(filename)) == ext {
+			if strings.ToLower(filepath.Ext(filename)) == normalizeExtension(ext) {
 				*target = append(*target, path)
 			}
 		}
@@ -309,15 +388,158 @@ func (form *FormData) paths(extensions []string, target *[]string) *FormData {
 	return form
 }
 
+// normalizeExtension returns a file extension in lower case, with its leading
+// dot.
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	return ext
+}
+
+// cfbContentType is the content type of the Compound File Binary containers,
+// e.g., the legacy binary documents or the password-protected OOXML
+// documents.
+const cfbContentType = "application/x-ole-storage"
+
+// expectedContentTypes are the content types a file may have according to its
+// extension. The other extensions are not checked, as their content cannot be
+// reliably sniffed (e.g., text formats).
+var expectedContentTypes = map[string][]string{
+	".pdf": {"application/pdf"},
+	// A password-protected OOXML document is an encrypted ZIP archive
+	// within a Compound File Binary container.
+	".docx": {"application/zip", cfbContentType},
+	".xlsx": {"application/zip", cfbContentType},
+	".pptx": {"application/zip", cfbContentType},
+	".odt":  {"application/zip"},
+	".ods":  {"application/zip"},
+	".odp":  {"application/zip"},
+	".odg":  {"application/zip"},
+	".epub": {"application/zip"},
+	".zip":  {"application/zip"},
+	".png":  {"image/png"},
+	".jpg":  {"image/jpeg"},
+	".jpeg": {"image/jpeg"},
+	".gif":  {"image/gif"},
+	".bmp":  {"image/bmp"},
+	".webp": {"image/webp"},
+}
+
+// checkContentType populates an error if the content of a file does not
+// match its extension.
+func (form *FormData) checkContentType(path string) {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	claimed, ok := expectedContentTypes[ext]
+	if !ok {
+		return
+	}
+
+	detected, err := detectContentType(path)
+	if err != nil {
+		// The engines report the unreadable files.
+		return
+	}
+
+	for _, contentType := range claimed {
+		if detected == contentType {
+			return
+		}
+	}
+
+	form.append(
+		fmt.Errorf("form file '%s' is invalid (claimed type '%s' from its extension, detected type '%s')", filepath.Base(path), strings.Join(claimed, "' or '"), detected),
+	)
+}
+
+// detectContentType sniffs the content type of a file.
+func detectContentType(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	head := make([]byte, 1024)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	head = head[:n]
+
+	// The PDF readers accept some bytes before the PDF header.
+	if bytes.Contains(head, []byte("%PDF-")) {
+		return "application/pdf", nil
+	}
+
+	if bytes.HasPrefix(head, []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")) {
+		return cfbContentType, nil
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+
+	return contentType, nil
+}
+
 // append adds an error to the list of errors.
 func (form *FormData) append(err error) {
 	form.errors = multierr.Append(form.errors, err)
 }
 
+// record keeps track of a bound form field, so that the parsed value is
+// available once bound.
+func (form *FormData) record(key string, target interface{}) {
+	if form.parsed == nil {
+		form.parsed = make(map[string]interface{})
+	}
+
+	form.parsed[key] = target
+}
+
+// parsedValues returns the values of the bound form fields, defaults
+// included. The values of the sensitive form fields, e.g., the passwords, are
+// redacted, as for the logs.
+func (form *FormData) parsedValues() map[string]interface{} {
+	values := make(map[string]interface{}, len(form.parsed))
+
+	for key, target := range form.parsed {
+		switch t := (target).(type) {
+		case *string:
+			values[key] = *t
+			if *t != "" && isSensitiveKey(key) {
+				values[key] = redacted
+			}
+		case *[]string:
+			values[key] = *t
+			if len(*t) > 0 && isSensitiveKey(key) {
+				values[key] = []string{redacted}
+			}
+		case *bool:
+			values[key] = *t
+		case *int:
+			values[key] = *t
+		case *float64:
+			values[key] = *t
+		case *time.Duration:
+			values[key] = t.String()
+		}
+	}
+
+	return values
+}
+
 // mustValue binds the target interface with a form field. If the value is
 // empty or the "key" does not exist, it binds the default value. Currently,
 // only the string, bool, int, float64 and time.Duration types are bindable.
 func (form *FormData) mustValue(key string, target interface{}, defaultValue interface{}) *FormData {
+	form.record(key, target)
+
 	val, ok := form.values[key]
 
 	if !ok || val[0] == "" {
@@ -347,6 +569,8 @@ func (form *FormData) mustValue(key string, target interface{}, defaultValue int
 // Currently, only the string, bool, int, float64 and time.Duration types are
 // bindable.
 func (form *FormData) mustMandatoryField(key string, target interface{}) *FormData {
+	form.record(key, target)
+
 	val, ok := form.values[key]
 
 	if !ok || val[0] == "" {
@@ -406,6 +630,23 @@ func (form *FormData) path(filename string, target *string) *FormData {
 	return form
 }
 
+// mandatoryPaths binds the absolute paths of form data files, according to a
+// list of file extensions, to a string slice variable. It populates an error
+// if there is no file for given file extensions.
+func (form *FormData) mandatoryPaths(extensions []string, target *[]string) *FormData {
+	form.paths(extensions, target)
+
+	if len(*target) > 0 {
+		return form
+	}
+
+	form.append(
+		fmt.Errorf("no form file found for extensions: %v", extensions),
+	)
+
+	return form
+}
+
 // mandatoryPath binds the absolute path of a form data file to a string
 // variable. It populates an error if the file does not exist.
 func (form *FormData) mandatoryPath(filename string, target *string) *FormData {
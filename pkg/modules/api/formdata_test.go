@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"os"
 	"reflect"
 	"testing"
 	"time"
@@ -103,6 +104,47 @@ func TestFormData_String(t *testing.T) {
 	}
 }
 
+func TestFormData_Strings(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		form     *FormData
+		expect   []string
+	}{
+		{
+			scenario: "key does not exist",
+			form:     &FormData{},
+			expect:   []string{},
+		},
+		{
+			scenario: "key does exist with many values",
+			form: &FormData{
+				values: map[string][]string{
+					"foo": {
+						"foo",
+						"",
+						"bar",
+					},
+				},
+			},
+			expect: []string{"foo", "bar"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var actual []string
+
+			tc.form.Strings("foo", &actual)
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got: %+v", tc.expect, actual)
+			}
+
+			if tc.form.errors != nil {
+				t.Errorf("expected no error but got: %v", tc.form.errors)
+			}
+		})
+	}
+}
+
 func TestFormData_MandatoryString(t *testing.T) {
 	for _, tc := range []struct {
 		scenario    string
@@ -1305,7 +1347,77 @@ func TestFormData_Paths(t *testing.T) {
 	}
 }
 
+func TestFormData_OnlyExtensions(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		form        *FormData
+		extensions  []string
+		expectError bool
+	}{
+		{
+			scenario:    "no file",
+			form:        &FormData{},
+			extensions:  []string{".png"},
+			expectError: false,
+		},
+		{
+			scenario: "files with given file extensions",
+			form: &FormData{
+				files: map[string]string{
+					"foo.png": "/foo.png",
+					"bar.JPG": "/bar.JPG",
+				},
+			},
+			extensions:  []string{".png", ".jpg"},
+			expectError: false,
+		},
+		{
+			scenario: "file with another file extension",
+			form: &FormData{
+				files: map[string]string{
+					"foo.png": "/foo.png",
+					"foo.pdf": "/foo.pdf",
+				},
+			},
+			extensions:  []string{".png", ".jpg"},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.form.OnlyExtensions(tc.extensions)
+
+			if tc.expectError && tc.form.errors == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && tc.form.errors != nil {
+				t.Fatalf("expected no error but got: %v", tc.form.errors)
+			}
+		})
+	}
+}
+
 func TestFormData_MandatoryPaths(t *testing.T) {
+	dirPath := t.TempDir()
+
+	pdfPath := dirPath + "/a.pdf"
+	err := os.WriteFile(pdfPath, []byte("%PDF-1.7\n%%EOF"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	zipPath := dirPath + "/b.pdf"
+	err = os.WriteFile(zipPath, []byte("PK\x03\x04foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	cfbPath := dirPath + "/c.docx"
+	err = os.WriteFile(cfbPath, []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
 	for _, tc := range []struct {
 		scenario    string
 		form        *FormData
@@ -1352,6 +1464,70 @@ func TestFormData_MandatoryPaths(t *testing.T) {
 			expectCount: 2,
 			expectError: false,
 		},
+		{
+			scenario: "extensions normalized",
+			form: &FormData{
+				files: map[string]string{
+					"a.pdf": "/a.pdf",
+				},
+			},
+			extensions:  []string{"PDF"},
+			expect:      []string{"/a.pdf"},
+			expectCount: 1,
+			expectError: false,
+		},
+		{
+			scenario: "content matches extension",
+			form: &FormData{
+				files: map[string]string{
+					"a.pdf": pdfPath,
+				},
+			},
+			extensions:  []string{".pdf"},
+			expect:      []string{pdfPath},
+			expectCount: 1,
+			expectError: false,
+		},
+		{
+			scenario: "content does not match extension",
+			form: &FormData{
+				files: map[string]string{
+					"a.pdf": pdfPath,
+					"b.pdf": zipPath,
+				},
+			},
+			extensions:  []string{".pdf"},
+			expect:      []string{pdfPath, zipPath},
+			expectCount: 2,
+			expectError: true,
+		},
+		{
+			scenario: "password-protected OOXML document",
+			form: &FormData{
+				files: map[string]string{
+					"c.docx": cfbPath,
+				},
+			},
+			extensions:  []string{".docx"},
+			expect:      []string{cfbPath},
+			expectCount: 1,
+			expectError: false,
+		},
+		{
+			scenario: "content does not match extension, but trustExtension",
+			form: &FormData{
+				values: map[string][]string{
+					"trustExtension": {"true"},
+				},
+				files: map[string]string{
+					"b.pdf": zipPath,
+				},
+			},
+			extensions:  []string{".pdf"},
+			expect:      []string{zipPath},
+			expectCount: 1,
+			expectError: false,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			var actual []string
@@ -1377,6 +1553,56 @@ func TestFormData_MandatoryPaths(t *testing.T) {
 	}
 }
 
+func TestFormData_MandatoryPathsUnchecked(t *testing.T) {
+	zipPath := t.TempDir() + "/b.pdf"
+	err := os.WriteFile(zipPath, []byte("PK\x03\x04foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		form        *FormData
+		expect      []string
+		expectError bool
+	}{
+		{
+			scenario:    "missing mandatory files",
+			form:        &FormData{},
+			expect:      nil,
+			expectError: true,
+		},
+		{
+			scenario: "content does not match extension",
+			form: &FormData{
+				files: map[string]string{
+					"b.pdf": zipPath,
+				},
+			},
+			expect:      []string{zipPath},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var actual []string
+
+			tc.form.MandatoryPathsUnchecked([]string{".pdf"}, &actual)
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %v but got: %v", tc.expect, actual)
+			}
+
+			if tc.expectError && tc.form.errors == nil {
+				t.Fatal("expected error but got none", tc.form.errors)
+			}
+
+			if !tc.expectError && tc.form.errors != nil {
+				t.Fatalf("expected no error but got: %v", tc.form.errors)
+			}
+		})
+	}
+}
+
 func TestFormData_append(t *testing.T) {
 	form := &FormData{}
 	form.append(errors.New("foo"))
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/gommon/bytes"
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// inputLimits gathers the limits which apply to the files of a
+// "multipart/form-data" request. A zero value means no limit.
+type inputLimits struct {
+	maxFileSize      int64
+	maxTotalFileSize int64
+	maxPdfPages      int
+}
+
+// parseRouteInputLimits parses the per-route overrides of the given default
+// limits. An entry has the form "/forms/chromium/screenshot/url:max-file-size=1MB",
+// where the limit is either max-file-size, max-total-file-size or
+// max-pdf-pages. Many entries may target the same route.
+func parseRouteInputLimits(defaults inputLimits, entries []string) (map[string]inputLimits, error) {
+	routeLimits := make(map[string]inputLimits)
+
+	for _, entry := range entries {
+		path, limit, ok := strings.Cut(entry, ":")
+		if !ok || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("input limits entry '%s': expected '/route/path:limit=value'", entry)
+		}
+
+		name, value, ok := strings.Cut(limit, "=")
+		if !ok {
+			return nil, fmt.Errorf("input limits entry '%s': expected '/route/path:limit=value'", entry)
+		}
+
+		limits, ok := routeLimits[path]
+		if !ok {
+			limits = defaults
+		}
+
+		var err error
+		switch name {
+		case "max-file-size":
+			limits.maxFileSize, err = bytes.Parse(value)
+		case "max-total-file-size":
+			limits.maxTotalFileSize, err = bytes.Parse(value)
+		case "max-pdf-pages":
+			limits.maxPdfPages, err = strconv.Atoi(value)
+		default:
+			err = errors.New("unknown limit, expected either 'max-file-size', 'max-total-file-size' or 'max-pdf-pages'")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("input limits entry '%s': %w", entry, err)
+		}
+
+		routeLimits[path] = limits
+	}
+
+	return routeLimits, nil
+}
+
+// validate checks that the limits are not negative.
+func (l inputLimits) validate() error {
+	if l.maxFileSize < 0 || l.maxTotalFileSize < 0 || l.maxPdfPages < 0 {
+		return errors.New("input limits must be positive")
+	}
+
+	return nil
+}
+
+// fileSizeBudget returns how many bytes an uploaded file may have, given the
+// total size of the previous files, or -1 if there is no limit.
+func (l inputLimits) fileSizeBudget(total int64) int64 {
+	budget := int64(-1)
+
+	if l.maxFileSize > 0 {
+		budget = l.maxFileSize
+	}
+
+	if l.maxTotalFileSize > 0 && (budget < 0 || l.maxTotalFileSize-total < budget) {
+		budget = max(l.maxTotalFileSize-total, 0)
+	}
+
+	return budget
+}
+
+// checkFileSize returns an [HttpError] if an uploaded file exceeds the
+// maximum file size, or if the total size of the uploaded files, this one
+// included, exceeds the maximum total file size.
+func (l inputLimits) checkFileSize(filename string, size, total int64) error {
+	if l.maxFileSize > 0 && size > l.maxFileSize {
+		return WrapError(
+			fmt.Errorf("file '%s' of %d bytes exceeds the maximum file size of %d bytes", filename, size, l.maxFileSize),
+			NewSentinelHttpError(
+				http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("The file '%s' exceeds the maximum file size of %s", filepath.Base(filename), bytes.Format(l.maxFileSize)),
+			).WithCode("FILE_TOO_LARGE"),
+		)
+	}
+
+	if l.maxTotalFileSize > 0 && total > l.maxTotalFileSize {
+		return WrapError(
+			fmt.Errorf("files of %d bytes exceed the maximum total file size of %d bytes", total, l.maxTotalFileSize),
+			NewSentinelHttpError(
+				http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("The files exceed the maximum total file size of %s", bytes.Format(l.maxTotalFileSize)),
+			).WithCode("FILES_TOO_LARGE"),
+		)
+	}
+
+	return nil
+}
+
+// checkPdfPages returns an [HttpError] if an uploaded PDF has more pages than
+// the maximum. It only reads the cross-reference table of the PDFs; a PDF
+// which cannot be read is left to the PDF engines.
+func (l inputLimits) checkPdfPages(files map[string]string) error {
+	if l.maxPdfPages == 0 {
+		return nil
+	}
+
+	for filename, path := range files {
+		if strings.ToLower(filepath.Ext(filename)) != ".pdf" {
+			continue
+		}
+
+		pageCount, err := pdfPageCount(path)
+		if err != nil {
+			continue
+		}
+
+		if pageCount > l.maxPdfPages {
+			return WrapError(
+				fmt.Errorf("PDF '%s' of %d pages exceeds the maximum of %d pages", filename, pageCount, l.maxPdfPages),
+				NewSentinelHttpError(
+					http.StatusBadRequest,
+					fmt.Sprintf("The PDF '%s' has %d pages, which exceeds the maximum of %d pages", filename, pageCount, l.maxPdfPages),
+				).WithCode("TOO_MANY_PDF_PAGES"),
+			)
+		}
+	}
+
+	return nil
+}
+
+func pdfPageCount(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("open PDF: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	ctx, err := pdfcpuAPI.ReadContext(f, pdfcpuConfig.NewDefaultConfiguration())
+	if err != nil {
+		return 0, fmt.Errorf("read PDF: %w", err)
+	}
+
+	err = ctx.EnsurePageCount()
+	if err != nil {
+		return 0, fmt.Errorf("count pages: %w", err)
+	}
+
+	return ctx.PageCount, nil
+}
@@ -0,0 +1,295 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// newTestPdf returns a minimal PDF with the given number of blank pages.
+func newTestPdf(pages int) []byte {
+	var objects []string
+	kids := ""
+	for i := 0; i < pages; i++ {
+		kids += fmt.Sprintf("%d 0 R ", i+3)
+	}
+
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", kids, pages))
+	for i := 0; i < pages; i++ {
+		objects = append(objects, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>")
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		buf.WriteString(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", i+1, object))
+	}
+
+	xref := buf.Len()
+	buf.WriteString(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(objects)+1))
+	for _, offset := range offsets {
+		buf.WriteString(fmt.Sprintf("%010d 00000 n \n", offset))
+	}
+	buf.WriteString(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref))
+
+	return buf.Bytes()
+}
+
+func TestParseRouteInputLimits(t *testing.T) {
+	defaults := inputLimits{maxFileSize: 10, maxTotalFileSize: 20, maxPdfPages: 30}
+
+	for _, tc := range []struct {
+		scenario     string
+		entries      []string
+		expectLimits map[string]inputLimits
+		expectError  bool
+	}{
+		{
+			scenario:     "no entries",
+			expectLimits: map[string]inputLimits{},
+		},
+		{
+			scenario:    "missing route path",
+			entries:     []string{"max-file-size=1MB"},
+			expectError: true,
+		},
+		{
+			scenario:    "missing limit value",
+			entries:     []string{"/forms/chromium/screenshot/url:max-file-size"},
+			expectError: true,
+		},
+		{
+			scenario:    "unknown limit",
+			entries:     []string{"/forms/chromium/screenshot/url:foo=1"},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid size",
+			entries:     []string{"/forms/chromium/screenshot/url:max-file-size=foo"},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid number of pages",
+			entries:     []string{"/forms/pdfengines/merge:max-pdf-pages=foo"},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			entries: []string{
+				"/forms/chromium/screenshot/url:max-file-size=1KB",
+				"/forms/chromium/screenshot/url:max-total-file-size=2KB",
+				"/forms/pdfengines/merge:max-pdf-pages=100",
+			},
+			expectLimits: map[string]inputLimits{
+				"/forms/chromium/screenshot/url": {maxFileSize: 1000, maxTotalFileSize: 2000, maxPdfPages: 30},
+				"/forms/pdfengines/merge":        {maxFileSize: 10, maxTotalFileSize: 20, maxPdfPages: 100},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			limits, err := parseRouteInputLimits(defaults, tc.entries)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && !reflect.DeepEqual(limits, tc.expectLimits) {
+				t.Errorf("expected %+v but got: %+v", tc.expectLimits, limits)
+			}
+		})
+	}
+}
+
+func TestInputLimits_fileSizeBudget(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		limits   inputLimits
+		total    int64
+		expect   int64
+	}{
+		{
+			scenario: "no limits",
+			expect:   -1,
+		},
+		{
+			scenario: "maximum file size",
+			limits:   inputLimits{maxFileSize: 10},
+			total:    100,
+			expect:   10,
+		},
+		{
+			scenario: "maximum total file size",
+			limits:   inputLimits{maxTotalFileSize: 100},
+			total:    95,
+			expect:   5,
+		},
+		{
+			scenario: "maximum total file size already reached",
+			limits:   inputLimits{maxFileSize: 10, maxTotalFileSize: 100},
+			total:    110,
+			expect:   0,
+		},
+		{
+			scenario: "both limits",
+			limits:   inputLimits{maxFileSize: 10, maxTotalFileSize: 100},
+			total:    20,
+			expect:   10,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := tc.limits.fileSizeBudget(tc.total)
+			if actual != tc.expect {
+				t.Errorf("expected %d but got %d", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestInputLimits_checkFileSize(t *testing.T) {
+	for _, tc := range []struct {
+		scenario   string
+		limits     inputLimits
+		size       int64
+		total      int64
+		expectCode string
+	}{
+		{
+			scenario: "no limits",
+			size:     100,
+			total:    1000,
+		},
+		{
+			scenario:   "file too large",
+			limits:     inputLimits{maxFileSize: 10},
+			size:       11,
+			total:      11,
+			expectCode: "FILE_TOO_LARGE",
+		},
+		{
+			scenario:   "files too large",
+			limits:     inputLimits{maxFileSize: 10, maxTotalFileSize: 15},
+			size:       10,
+			total:      20,
+			expectCode: "FILES_TOO_LARGE",
+		},
+		{
+			scenario: "within limits",
+			limits:   inputLimits{maxFileSize: 10, maxTotalFileSize: 20},
+			size:     10,
+			total:    20,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.limits.checkFileSize("foo.pdf", tc.size, tc.total)
+
+			if tc.expectCode == "" {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return
+			}
+
+			var httpErr HttpError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected an HTTP error but got: %v", err)
+			}
+
+			status, _ := httpErr.HttpError()
+			if status != http.StatusRequestEntityTooLarge {
+				t.Errorf("expected %d as HTTP status code but got %d", http.StatusRequestEntityTooLarge, status)
+			}
+
+			code := ErrorCode(err)
+			if code != tc.expectCode {
+				t.Errorf("expected code '%s' but got '%s'", tc.expectCode, code)
+			}
+		})
+	}
+}
+
+func TestInputLimits_checkPdfPages(t *testing.T) {
+	dirPath := t.TempDir()
+
+	pdfPath := fmt.Sprintf("%s/foo.pdf", dirPath)
+	err := os.WriteFile(pdfPath, newTestPdf(3), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	corruptedPath := fmt.Sprintf("%s/bar.pdf", dirPath)
+	err = os.WriteFile(corruptedPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		limits      inputLimits
+		files       map[string]string
+		expectError bool
+	}{
+		{
+			scenario: "no limit",
+			limits:   inputLimits{},
+			files:    map[string]string{"foo.pdf": pdfPath},
+		},
+		{
+			scenario: "not a PDF",
+			limits:   inputLimits{maxPdfPages: 1},
+			files:    map[string]string{"foo.txt": pdfPath},
+		},
+		{
+			scenario: "corrupted PDF",
+			limits:   inputLimits{maxPdfPages: 1},
+			files:    map[string]string{"bar.pdf": corruptedPath},
+		},
+		{
+			scenario: "PDF within the limit",
+			limits:   inputLimits{maxPdfPages: 3},
+			files:    map[string]string{"foo.pdf": pdfPath},
+		},
+		{
+			scenario:    "PDF exceeds the limit",
+			limits:      inputLimits{maxPdfPages: 2},
+			files:       map[string]string{"foo.pdf": pdfPath},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.limits.checkPdfPages(tc.files)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			var httpErr HttpError
+			if tc.expectError && !errors.As(err, &httpErr) {
+				t.Fatalf("expected an HTTP error but got: %v", err)
+			}
+
+			if tc.expectError {
+				status, _ := httpErr.HttpError()
+				if status != http.StatusBadRequest {
+					t.Errorf("expected %d as HTTP status code but got %d", http.StatusBadRequest, status)
+				}
+			}
+		})
+	}
+}
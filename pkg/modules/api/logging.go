@@ -0,0 +1,138 @@
+package api
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// redacted replaces the value of a sensitive field in the logs and in the
+// validation reports.
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the substrings of the form fields' keys which values are
+// never logged, whatever the log level, nor echoed in the validation reports.
+var sensitiveKeys = []string{
+	"password",
+	"username",
+	"cookie",
+	"header",
+	"token",
+	"secret",
+	"authorization",
+	"downloadfrom",
+}
+
+// redactValues returns a copy of the form fields' values, with the values of
+// the sensitive fields redacted.
+func redactValues(values map[string][]string) map[string][]string {
+	redactedValues := make(map[string][]string, len(values))
+
+	for key, value := range values {
+		if isSensitiveKey(key) {
+			redactedValues[key] = []string{redacted}
+			continue
+		}
+
+		redactedValues[key] = value
+	}
+
+	return redactedValues
+}
+
+// isSensitiveKey tells if a form field key may hold a secret.
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, sensitiveKey := range sensitiveKeys {
+		if strings.Contains(key, sensitiveKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// levelCore overrides the level of a [zapcore.Core], so that a request may
+// have a more or less verbose logger than the other ones.
+type levelCore struct {
+	zapcore.Core
+	level zapcore.Level
+}
+
+// Enabled implements [zapcore.LevelEnabler].
+func (c levelCore) Enabled(level zapcore.Level) bool {
+	return c.level.Enabled(level)
+}
+
+// With implements [zapcore.Core].
+func (c levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return levelCore{
+		Core:  c.Core.With(fields),
+		level: c.level,
+	}
+}
+
+// Check implements [zapcore.Core]. The underlying core writes the entry
+// without checking its own level.
+func (c levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+
+	return ce
+}
+
+// withLevel returns a logger with the given level.
+func withLevel(logger *zap.Logger, level zapcore.Level) *zap.Logger {
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return levelCore{
+			Core:  core,
+			level: level,
+		}
+	}))
+}
+
+// logCapture keeps the log entries of a request in memory.
+type logCapture struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements [zapcore.WriteSyncer].
+func (capture *logCapture) Write(p []byte) (int, error) {
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+
+	return capture.buf.Write(p)
+}
+
+// Sync implements [zapcore.WriteSyncer].
+func (capture *logCapture) Sync() error {
+	return nil
+}
+
+// Bytes returns a copy of the captured log entries.
+func (capture *logCapture) Bytes() []byte {
+	capture.mu.Lock()
+	defer capture.mu.Unlock()
+
+	return bytes.Clone(capture.buf.Bytes())
+}
+
+// withCapture returns a logger which also writes its entries, as JSON, to
+// the given capture.
+func withCapture(logger *zap.Logger, capture *logCapture, level zapcore.Level) *zap.Logger {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(
+			core,
+			zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), capture, level),
+		)
+	}))
+}
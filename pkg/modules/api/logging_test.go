@@ -0,0 +1,35 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactValues(t *testing.T) {
+	values := map[string][]string{
+		"landscape":        {"true"},
+		"userPassword":     {"foo"},
+		"proxyUsername":    {"foo"},
+		"cookies":          {"[]"},
+		"extraHttpHeaders": {"{}"},
+		"downloadFrom":     {"[]"},
+	}
+
+	actual := redactValues(values)
+	expect := map[string][]string{
+		"landscape":        {"true"},
+		"userPassword":     {redacted},
+		"proxyUsername":    {redacted},
+		"cookies":          {redacted},
+		"extraHttpHeaders": {redacted},
+		"downloadFrom":     {redacted},
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got: %+v", expect, actual)
+	}
+
+	if values["userPassword"][0] != "foo" {
+		t.Error("expected the original values to be left untouched")
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ManifestEntry is the status of the processing of a file, in the manifest
+// of the "continueOnError" form field.
+type ManifestEntry struct {
+	Filename string `json:"filename"`
+	Status   string `json:"status"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// WriteManifest writes, as "manifest.json", the status of the processing of
+// each file, in order, and returns its path. The messages of the errors are
+// the ones of the HTTP responses, so that nothing sensitive leaks.
+func WriteManifest(ctx *Context, inputPaths []string, errs []error) (string, error) {
+	entries := make([]ManifestEntry, len(inputPaths))
+	for i, inputPath := range inputPaths {
+		entries[i] = ManifestEntry{
+			Filename: filepath.Base(inputPath),
+			Status:   "success",
+		}
+
+		if errs[i] != nil {
+			_, message := ParseError(errs[i])
+
+			entries[i].Status = "error"
+			entries[i].Code = ErrorCode(errs[i])
+			entries[i].Message = message
+		}
+	}
+
+	content, err := json.MarshalIndent(struct {
+		Files []ManifestEntry `json:"files"`
+	}{Files: entries}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	// The manifest keeps its filename in the response.
+	dirPath := ctx.GeneratePath("")
+
+	err = os.MkdirAll(dirPath, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("create directory: %w", err)
+	}
+
+	manifestPath := filepath.Join(dirPath, "manifest.json")
+
+	err = os.WriteFile(manifestPath, content, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write manifest: %w", err)
+	}
+
+	return manifestPath, nil
+}
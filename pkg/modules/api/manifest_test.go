@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteManifest(t *testing.T) {
+	ctx := &ContextMock{Context: new(Context)}
+	ctx.SetDirPath(t.TempDir())
+
+	manifestPath, err := WriteManifest(
+		ctx.Context,
+		[]string{"/document.pdf", "/document2.pdf", "/document3.pdf"},
+		[]error{
+			nil,
+			WrapError(errors.New("foo"), NewSentinelHttpError(http.StatusBadRequest, "The password does not open the PDF 'document2.pdf'").WithCode("INVALID_PDF_PASSWORD")),
+			errors.New("foo"),
+		},
+	)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if filepath.Base(manifestPath) != "manifest.json" {
+		t.Errorf("expected 'manifest.json' but got '%s'", filepath.Base(manifestPath))
+	}
+
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var actual struct {
+		Files []ManifestEntry `json:"files"`
+	}
+
+	err = json.Unmarshal(content, &actual)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := []ManifestEntry{
+		{Filename: "document.pdf", Status: "success"},
+		{Filename: "document2.pdf", Status: "error", Code: "INVALID_PDF_PASSWORD", Message: "The password does not open the PDF 'document2.pdf'"},
+		{Filename: "document3.pdf", Status: "error", Code: "INTERNAL_SERVER_ERROR", Message: "Internal Server Error"},
+	}
+
+	if !reflect.DeepEqual(actual.Files, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual.Files)
+	}
+}
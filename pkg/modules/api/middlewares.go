@@ -5,14 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
-
-	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"go.uber.org/zap/zapcore"
 )
 
 // ErrAsyncProcess happens when a handler or middleware handles a request in an
@@ -32,6 +33,13 @@ func ParseError(err error) (int, string) {
 		return http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable)
 	}
 
+	// The process context of a request is cancelled before its end by a
+	// shutdown, see [Context.ShutdownError], or by the failure of an
+	// underlying process.
+	if errors.Is(err, context.Canceled) {
+		return http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable)
+	}
+
 	var httpErr HttpError
 	if errors.As(err, &httpErr) {
 		return httpErr.HttpError()
@@ -42,11 +50,43 @@ func ParseError(err error) (int, string) {
 }
 
 // httpErrorHandler is the centralized HTTP error handler. It parses the error,
-// returns a response as "text/plain; charset=UTF-8".
-func httpErrorHandler() echo.HTTPErrorHandler {
+// returns a response as "text/plain; charset=UTF-8". The response ends with
+// the request identifier, so that users may correlate it with the logs.
+//
+// If the request accepts "application/json", or if jsonByDefault is true, the
+// response is a JSON object with a stable, machine-readable code instead (see
+// [ErrorCode]). The HTTP status is the same.
+func httpErrorHandler(jsonByDefault bool) echo.HTTPErrorHandler {
 	return func(err error, c echo.Context) {
 		logger := c.Get("logger").(*zap.Logger)
 		status, message := ParseError(err)
+		trace, _ := c.Get("trace").(string)
+
+		if jsonByDefault || acceptsJson(c.Request().Header.Get(echo.HeaderAccept)) {
+			body := struct {
+				Code    string            `json:"code"`
+				Message string            `json:"message"`
+				Details map[string]string `json:"details,omitempty"`
+			}{
+				Code:    ErrorCode(err),
+				Message: message,
+			}
+
+			if trace != "" {
+				body.Details = map[string]string{"trace": trace}
+			}
+
+			err = c.JSON(status, body)
+			if err != nil {
+				logger.Error(fmt.Sprintf("send error response: %s", err.Error()))
+			}
+
+			return
+		}
+
+		if trace != "" {
+			message = fmt.Sprintf("%s\nTrace: %s", message, trace)
+		}
 
 		c.Response().Header().Add(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
 
@@ -57,6 +97,19 @@ func httpErrorHandler() echo.HTTPErrorHandler {
 	}
 }
 
+// acceptsJson tells if an Accept header value explicitly lists the
+// "application/json" media type.
+func acceptsJson(accept string) bool {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(mediaRange, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), echo.MIMEApplicationJSON) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // latencyMiddleware sets the start time in the [echo.Context] under
 // "startTime". Its value will be used later to calculate a request latency.
 //
@@ -106,10 +159,11 @@ func rootPathMiddleware(rootPath string) echo.MiddlewareFunc {
 func traceMiddleware(header string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			// Get or create the request identifier.
+			// Get or create the request identifier. As it ends up in the
+			// logs and the responses, we do not honor odd values.
 			trace := c.Request().Header.Get(header)
 
-			if trace == "" {
+			if !isValidTrace(trace) {
 				trace = uuid.New().String()
 			}
 
@@ -123,11 +177,29 @@ func traceMiddleware(header string) echo.MiddlewareFunc {
 	}
 }
 
+// isValidTrace tells if a request identifier is not empty, is at most 128
+// characters long, and only contains printable ASCII characters.
+func isValidTrace(trace string) bool {
+	if trace == "" || len(trace) > 128 {
+		return false
+	}
+
+	for _, r := range trace {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
 // loggerMiddleware sets the logger in the [echo.Context] under "logger" and
-// logs a synchronous request result.
+// logs a synchronous request result. If allowed, the "Gotenberg-Log-Level"
+// header sets the level of the request logger, and the "Gotenberg-Log-Capture"
+// header keeps its entries in the [echo.Context] under "logCapture".
 //
 //	logger := c.Get("logger").(*zap.Logger)
-func loggerMiddleware(logger *zap.Logger, disableLoggingForPaths []string) echo.MiddlewareFunc {
+func loggerMiddleware(logger *zap.Logger, disableLoggingForPaths []string, enableLevelHeader, enableCapture bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			startTime := c.Get("startTime").(time.Time)
@@ -136,6 +208,29 @@ func loggerMiddleware(logger *zap.Logger, disableLoggingForPaths []string) echo.
 
 			// Create the request logger and add it to our locals.
 			reqLogger := logger.With(zap.String("trace", trace))
+
+			// The level only applies to the logger of this request, not to
+			// the ones of the concurrent requests.
+			captureLevel := zapcore.DebugLevel
+			levelHeader := c.Request().Header.Get("Gotenberg-Log-Level")
+			if enableLevelHeader && levelHeader != "" {
+				level, err := zapcore.ParseLevel(levelHeader)
+				if err != nil || level < zapcore.DebugLevel || level > zapcore.ErrorLevel {
+					reqLogger.Warn(fmt.Sprintf("ignore invalid 'Gotenberg-Log-Level' header value '%s'", levelHeader))
+				} else {
+					reqLogger = withLevel(reqLogger, level)
+					captureLevel = level
+				}
+			}
+
+			if enableCapture {
+				enabled, err := strconv.ParseBool(c.Request().Header.Get("Gotenberg-Log-Capture"))
+				if err == nil && enabled {
+					capture := new(logCapture)
+					reqLogger = withCapture(reqLogger, capture, captureLevel)
+					c.Set("logCapture", capture)
+				}
+			}
 			c.Set("logger", reqLogger.Named(func() string {
 				return strings.ReplaceAll(
 					strings.ReplaceAll(c.Request().URL.Path, rootPath, ""),
@@ -195,6 +290,15 @@ func loggerMiddleware(logger *zap.Logger, disableLoggingForPaths []string) echo.
 	}
 }
 
+// shuttingDownError wraps an error with the HTTP error of a server which is
+// shutting down.
+func shuttingDownError(err error) error {
+	return WrapError(
+		err,
+		NewSentinelHttpError(http.StatusServiceUnavailable, "Service Unavailable: the server is shutting down").WithCode("SHUTTING_DOWN"),
+	)
+}
+
 // contextMiddleware, a middleware for "multipart/form-data" requests, sets the
 // [Context] and related context.CancelFunc in the [echo.Context] under
 // "context" and "cancel". If the process is synchronous, it also handles the
@@ -202,14 +306,47 @@ func loggerMiddleware(logger *zap.Logger, disableLoggingForPaths []string) echo.
 //
 //	ctx := c.Get("context").(*api.Context)
 //	cancel := c.Get("cancel").(context.CancelFunc)
-func contextMiddleware(fs *gotenberg.FileSystem, timeout time.Duration) echo.MiddlewareFunc {
+func contextMiddleware(storage *storage, timeout time.Duration, limits inputLimits, downloader *downloader, drainer *drainer, pdfMetadata, cacheable bool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			logger := c.Get("logger").(*zap.Logger)
 
+			done, ok := drainer.track()
+			if !ok {
+				c.Response().Header().Set(echo.HeaderConnection, "close")
+
+				return shuttingDownError(errors.New("server is shutting down"))
+			}
+
+			// The files of the request would likely not fit on the disk.
+			err := storage.checkFreeSpace()
+			if err != nil {
+				done()
+
+				return err
+			}
+
 			// We create a context with a timeout so that underlying processes are
 			// able to stop early and handle correctly a timeout scenario.
-			ctx, cancel, err := newContext(c, logger, fs, timeout)
+			ctx, cancelCtx, err := newContext(c, logger, storage.fs, timeout, limits, downloader)
+
+			// Once the grace period of a shutdown is over, the processes of
+			// the remaining requests are aborted.
+			stopAbort := func() bool { return false }
+			if ctx != nil {
+				stopAbort = context.AfterFunc(drainer.ctx, ctx.abort)
+				ctx.pdfMetadata = pdfMetadata
+				ctx.cacheable = cacheable
+			}
+
+			// A request is done when its context is cancelled, which may
+			// happen asynchronously, e.g., with a webhook.
+			cancel := context.CancelFunc(func() {
+				stopAbort()
+				cancelCtx()
+				done()
+			})
+
 			if err != nil {
 				cancel()
 
@@ -231,7 +368,34 @@ func contextMiddleware(fs *gotenberg.FileSystem, timeout time.Duration) echo.Mid
 			defer cancel()
 
 			if err != nil {
-				return err
+				return ctx.ShutdownError(err)
+			}
+
+			// The client only wants to validate its request: the handler has
+			// not processed anything.
+			if ctx.validateOnly {
+				// Other middlewares, e.g., the idempotency one, must not
+				// mistake this response for a processed one.
+				c.Set("validateOnly", true)
+
+				return c.JSON(http.StatusOK, ctx.validationReport())
+			}
+
+			// The client wants the logs of its request alongside the output
+			// files.
+			capture, ok := c.Get("logCapture").(*logCapture)
+			if ok {
+				logPath := ctx.GeneratePath(".log")
+
+				err = os.WriteFile(logPath, capture.Bytes(), 0o600)
+				if err != nil {
+					return fmt.Errorf("write captured logs: %w", err)
+				}
+
+				err = ctx.AddOutputPaths(logPath)
+				if err != nil {
+					return fmt.Errorf("add captured logs: %w", err)
+				}
 			}
 
 			// No error, let's build the output file.
@@ -240,6 +404,11 @@ func contextMiddleware(fs *gotenberg.FileSystem, timeout time.Duration) echo.Mid
 				return fmt.Errorf("build output file: %w", err)
 			}
 
+			// Describe the resulting PDF, if the operator wishes so.
+			for header, value := range ctx.PdfMetadataHeaders(outputPath) {
+				c.Response().Header().Set(header, value)
+			}
+
 			// Send the output file.
 			err = c.Attachment(outputPath, ctx.OutputFilename(outputPath))
 			if err != nil {
@@ -292,3 +461,106 @@ func hardTimeoutMiddleware(hardTimeout time.Duration) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// corsOptions gathers the CORS settings of the [Api].
+type corsOptions struct {
+	allowOrigins     []string
+	allowMethods     []string
+	allowHeaders     []string
+	exposeHeaders    []string
+	allowCredentials bool
+	maxAge           time.Duration
+}
+
+// allowOrigin returns the value of the Access-Control-Allow-Origin header for
+// the given origin, or an empty string if the origin is not allowed.
+func (o corsOptions) allowOrigin(origin string) string {
+	for _, allowed := range o.allowOrigins {
+		if allowed == "*" {
+			if o.allowCredentials {
+				return origin
+			}
+
+			return "*"
+		}
+
+		if strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+
+	return ""
+}
+
+// corsMiddleware handles Cross-Origin Resource Sharing. Preflight requests
+// are answered directly with a 204 status code, so they never reach the
+// routes' handlers (i.e., no conversion is triggered).
+func corsMiddleware(options corsOptions) echo.MiddlewareFunc {
+	allowMethods := strings.Join(options.allowMethods, ",")
+	allowHeaders := strings.Join(options.allowHeaders, ",")
+	exposeHeaders := strings.Join(options.exposeHeaders, ",")
+	maxAge := strconv.Itoa(int(options.maxAge.Seconds()))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			header := c.Response().Header()
+			origin := req.Header.Get(echo.HeaderOrigin)
+			preflight := req.Method == http.MethodOptions && req.Header.Get(echo.HeaderAccessControlRequestMethod) != ""
+
+			header.Add(echo.HeaderVary, echo.HeaderOrigin)
+
+			if origin == "" {
+				// Not a cross-origin request.
+				return next(c)
+			}
+
+			allowOrigin := options.allowOrigin(origin)
+
+			if !preflight {
+				if allowOrigin != "" {
+					header.Set(echo.HeaderAccessControlAllowOrigin, allowOrigin)
+
+					if options.allowCredentials {
+						header.Set(echo.HeaderAccessControlAllowCredentials, "true")
+					}
+
+					if exposeHeaders != "" {
+						header.Set(echo.HeaderAccessControlExposeHeaders, exposeHeaders)
+					}
+				}
+
+				// Call the next middleware in the chain.
+				return next(c)
+			}
+
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestMethod)
+			header.Add(echo.HeaderVary, echo.HeaderAccessControlRequestHeaders)
+
+			if allowOrigin == "" {
+				// The browser will block the actual request, as the
+				// response does not contain the CORS headers.
+				return c.NoContent(http.StatusNoContent)
+			}
+
+			header.Set(echo.HeaderAccessControlAllowOrigin, allowOrigin)
+			header.Set(echo.HeaderAccessControlAllowMethods, allowMethods)
+
+			if options.allowCredentials {
+				header.Set(echo.HeaderAccessControlAllowCredentials, "true")
+			}
+
+			if allowHeaders != "" {
+				header.Set(echo.HeaderAccessControlAllowHeaders, allowHeaders)
+			} else if requestHeaders := req.Header.Get(echo.HeaderAccessControlRequestHeaders); requestHeaders != "" {
+				header.Set(echo.HeaderAccessControlAllowHeaders, requestHeaders)
+			}
+
+			if options.maxAge > 0 {
+				header.Set(echo.HeaderAccessControlMaxAge, maxAge)
+			}
+
+			return c.NoContent(http.StatusNoContent)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -33,6 +35,11 @@ func TestParseError(t *testing.T) {
 			expectStatus:  http.StatusServiceUnavailable,
 			expectMessage: http.StatusText(http.StatusServiceUnavailable),
 		},
+		{
+			err:           context.Canceled,
+			expectStatus:  http.StatusServiceUnavailable,
+			expectMessage: http.StatusText(http.StatusServiceUnavailable),
+		},
 		{
 			err: WrapError(
 				errors.New("foo"),
@@ -56,9 +63,13 @@ func TestParseError(t *testing.T) {
 
 func TestHttpErrorHandler(t *testing.T) {
 	for i, tc := range []struct {
-		err           error
-		expectStatus  int
-		expectMessage string
+		err               error
+		trace             string
+		accept            string
+		jsonByDefault     bool
+		expectStatus      int
+		expectContentType string
+		expectMessage     string
 	}{
 		{
 			err:           echo.ErrInternalServerError,
@@ -78,6 +89,33 @@ func TestHttpErrorHandler(t *testing.T) {
 			expectStatus:  http.StatusBadRequest,
 			expectMessage: "foo",
 		},
+		{
+			err: WrapError(
+				errors.New("foo"),
+				NewSentinelHttpError(http.StatusBadRequest, "foo"),
+			),
+			trace:         "bar",
+			expectStatus:  http.StatusBadRequest,
+			expectMessage: "foo\nTrace: bar",
+		},
+		{
+			err: WrapError(
+				fmt.Errorf("foo: %w", gotenberg.ErrPdfFormatNotSupported),
+				NewSentinelHttpError(http.StatusBadRequest, "foo"),
+			),
+			trace:             "bar",
+			accept:            "text/html, application/json;q=0.9",
+			expectStatus:      http.StatusBadRequest,
+			expectContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectMessage:     `{"code":"PDF_FORMAT_NOT_SUPPORTED","message":"foo","details":{"trace":"bar"}}` + "\n",
+		},
+		{
+			err:               context.DeadlineExceeded,
+			jsonByDefault:     true,
+			expectStatus:      http.StatusServiceUnavailable,
+			expectContentType: echo.MIMEApplicationJSONCharsetUTF8,
+			expectMessage:     `{"code":"TIMEOUT","message":"Service Unavailable"}` + "\n",
+		},
 	} {
 		recorder := httptest.NewRecorder()
 		request := httptest.NewRequest(http.MethodGet, "/foo", nil)
@@ -89,12 +127,25 @@ func TestHttpErrorHandler(t *testing.T) {
 		c := srv.NewContext(request, recorder)
 		c.Set("logger", zap.NewNop())
 
-		handler := httpErrorHandler()
+		if tc.trace != "" {
+			c.Set("trace", tc.trace)
+		}
+
+		if tc.accept != "" {
+			request.Header.Set(echo.HeaderAccept, tc.accept)
+		}
+
+		handler := httpErrorHandler(tc.jsonByDefault)
 		handler(tc.err, c)
 
+		expectContentType := tc.expectContentType
+		if expectContentType == "" {
+			expectContentType = echo.MIMETextPlainCharsetUTF8
+		}
+
 		contentType := recorder.Header().Get(echo.HeaderContentType)
-		if contentType != echo.MIMETextPlainCharsetUTF8 {
-			t.Errorf("test %d: expected %s '%s' but got '%s'", i, echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8, contentType)
+		if contentType != expectContentType {
+			t.Errorf("test %d: expected %s '%s' but got '%s'", i, echo.HeaderContentType, expectContentType, contentType)
 		}
 
 		// Note: we cannot test the trace header in the response here, as it is set in the trace middleware.
@@ -164,7 +215,8 @@ func TestRootPathMiddleware(t *testing.T) {
 
 func TestTraceMiddleware(t *testing.T) {
 	for i, tc := range []struct {
-		trace string
+		trace         string
+		expectInvalid bool
 	}{
 		{
 			trace: "foo",
@@ -172,6 +224,14 @@ func TestTraceMiddleware(t *testing.T) {
 		{
 			trace: "",
 		},
+		{
+			trace:         "foo\nbar",
+			expectInvalid: true,
+		},
+		{
+			trace:         strings.Repeat("a", 129),
+			expectInvalid: true,
+		},
 	} {
 		recorder := httptest.NewRecorder()
 		request := httptest.NewRequest(http.MethodGet, "/foo", nil)
@@ -201,21 +261,21 @@ func TestTraceMiddleware(t *testing.T) {
 			t.Errorf("test %d: expected non empty trace in context", i)
 		}
 
-		if tc.trace != "" && trace != tc.trace {
+		if tc.trace != "" && !tc.expectInvalid && trace != tc.trace {
 			t.Errorf("test %d: expected context trace '%s' but got '%s'", i, tc.trace, trace)
 		}
 
-		if tc.trace == "" && trace == tc.trace {
+		if (tc.trace == "" || tc.expectInvalid) && trace == tc.trace {
 			t.Errorf("test %d: expected context trace different from '%s' but got '%s'", i, tc.trace, trace)
 		}
 
 		responseTrace := recorder.Header().Get("Gotenberg-Trace")
 
-		if tc.trace != "" && responseTrace != tc.trace {
+		if tc.trace != "" && !tc.expectInvalid && responseTrace != tc.trace {
 			t.Errorf("test %d: expected header trace '%s' but got '%s'", i, tc.trace, responseTrace)
 		}
 
-		if tc.trace == "" && responseTrace == tc.trace {
+		if (tc.trace == "" || tc.expectInvalid) && responseTrace == tc.trace {
 			t.Errorf("test %d: expected header trace different from '%s' but got '%s'", i, tc.trace, responseTrace)
 		}
 	}
@@ -223,9 +283,11 @@ func TestTraceMiddleware(t *testing.T) {
 
 func TestLoggerMiddleware(t *testing.T) {
 	for i, tc := range []struct {
-		request     *http.Request
-		next        echo.HandlerFunc
-		skipLogging bool
+		request         *http.Request
+		next            echo.HandlerFunc
+		skipLogging     bool
+		expectCapture   string
+		expectNoCapture string
 	}{
 		{
 			request: httptest.NewRequest(http.MethodGet, "/", nil),
@@ -252,6 +314,40 @@ func TestLoggerMiddleware(t *testing.T) {
 				}
 			}(),
 		},
+		{
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Gotenberg-Log-Level", "debug")
+				req.Header.Set("Gotenberg-Log-Capture", "true")
+
+				return req
+			}(),
+			next: func() echo.HandlerFunc {
+				return func(c echo.Context) error {
+					c.Get("logger").(*zap.Logger).Debug("bar")
+					return nil
+				}
+			}(),
+			expectCapture: "bar",
+		},
+		{
+			request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Gotenberg-Log-Level", "error")
+				req.Header.Set("Gotenberg-Log-Capture", "true")
+
+				return req
+			}(),
+			next: func() echo.HandlerFunc {
+				return func(c echo.Context) error {
+					c.Get("logger").(*zap.Logger).Debug("bar")
+					c.Get("logger").(*zap.Logger).Error("baz")
+					return nil
+				}
+			}(),
+			expectCapture:   "baz",
+			expectNoCapture: "bar",
+		},
 	} {
 		recorder := httptest.NewRecorder()
 
@@ -269,15 +365,37 @@ func TestLoggerMiddleware(t *testing.T) {
 			disableLoggingForPaths = append(disableLoggingForPaths, tc.request.RequestURI)
 		}
 
-		err := loggerMiddleware(zap.NewNop(), disableLoggingForPaths)(tc.next)(c)
+		err := loggerMiddleware(zap.NewNop(), disableLoggingForPaths, true, true)(tc.next)(c)
 		if err != nil {
 			t.Errorf("test %d: expected no error but got: %v", i, err)
 		}
+
+		capture, ok := c.Get("logCapture").(*logCapture)
+		if tc.expectCapture == "" {
+			if ok {
+				t.Errorf("test %d: expected no log capture", i)
+			}
+
+			continue
+		}
+
+		if !ok {
+			t.Fatalf("test %d: expected a log capture", i)
+		}
+
+		captured := string(capture.Bytes())
+		if !strings.Contains(captured, tc.expectCapture) {
+			t.Errorf("test %d: expected '%s' in the captured logs but got: %s", i, tc.expectCapture, captured)
+		}
+
+		if tc.expectNoCapture != "" && strings.Contains(captured, tc.expectNoCapture) {
+			t.Errorf("test %d: expected no '%s' in the captured logs but got: %s", i, tc.expectNoCapture, captured)
+		}
 	}
 }
 
 func TestContextMiddleware(t *testing.T) {
-	buildMultipartFormDataRequest := func() *http.Request {
+	buildMultipartFormDataRequest := func(validateOnly ...string) *http.Request {
 		body := &bytes.Buffer{}
 		writer := multipart.NewWriter(body)
 
@@ -293,6 +411,13 @@ func TestContextMiddleware(t *testing.T) {
 			t.Fatalf("expected no error but got: %v", err)
 		}
 
+		for _, value := range validateOnly {
+			err = writer.WriteField("validateOnly", value)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		}
+
 		req := httptest.NewRequest(http.MethodPost, "/", body)
 		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
 
@@ -301,12 +426,24 @@ func TestContextMiddleware(t *testing.T) {
 
 	for i, tc := range []struct {
 		request           *http.Request
+		draining          bool
+		minFreeSpace      int64
 		next              echo.HandlerFunc
 		expectErr         bool
 		expectStatus      int
 		expectContentType string
 		expectFilename    string
 	}{
+		{
+			request:   buildMultipartFormDataRequest(),
+			draining:  true,
+			expectErr: true,
+		},
+		{
+			request:      buildMultipartFormDataRequest(),
+			minFreeSpace: math.MaxInt64,
+			expectErr:    true,
+		},
 		{
 			request:   httptest.NewRequest(http.MethodGet, "/", nil),
 			expectErr: true,
@@ -375,6 +512,23 @@ func TestContextMiddleware(t *testing.T) {
 			expectStatus:      http.StatusOK,
 			expectContentType: "application/zip",
 		},
+		{
+			request:   buildMultipartFormDataRequest("foo"),
+			expectErr: true,
+		},
+		{
+			request: buildMultipartFormDataRequest("true"),
+			next: func() echo.HandlerFunc {
+				return func(c echo.Context) error {
+					var foo string
+					ctx := c.Get("context").(*Context)
+
+					return ctx.FormData().String("foo", &foo, "").Validate()
+				}
+			}(),
+			expectStatus:      http.StatusOK,
+			expectContentType: echo.MIMEApplicationJSONCharsetUTF8,
+		},
 	} {
 		recorder := httptest.NewRecorder()
 
@@ -387,7 +541,26 @@ func TestContextMiddleware(t *testing.T) {
 		c.Set("trace", "foo")
 		c.Set("startTime", time.Now())
 
-		err := contextMiddleware(gotenberg.NewFileSystem(), time.Duration(10)*time.Second)(tc.next)(c)
+		drainer := newDrainer()
+		if tc.draining {
+			drainer.drain(context.Background())
+		}
+
+		err := contextMiddleware(&storage{fs: gotenberg.NewFileSystem(), minFreeSpace: tc.minFreeSpace, logger: zap.NewNop()}, time.Duration(10)*time.Second, inputLimits{}, nil, drainer, false, true)(tc.next)(c)
+
+		// An asynchronous request is in flight until its context is
+		// cancelled.
+		if tc.expectStatus == http.StatusNoContent {
+			if drainer.count() != 1 {
+				t.Errorf("test %d: expected one in-flight request but got %d", i, drainer.count())
+			}
+
+			c.Get("cancel").(context.CancelFunc)()
+		}
+
+		if drainer.count() != 0 {
+			t.Errorf("test %d: expected no in-flight request but got %d", i, drainer.count())
+		}
 
 		if tc.expectErr && err == nil {
 			t.Errorf("test %d: expected error but got: %v", i, err)
@@ -502,3 +675,199 @@ func TestHardTimeoutMiddleware(t *testing.T) {
 		}
 	}
 }
+
+func TestCorsMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario              string
+		options               corsOptions
+		method                string
+		headers               map[string]string
+		expectNextCalled      bool
+		expectHttpStatus      int
+		expectResponseHeaders map[string]string
+	}{
+		{
+			scenario: "no origin",
+			options: corsOptions{
+				allowOrigins: []string{"https://foo.com"},
+			},
+			method:           http.MethodPost,
+			expectNextCalled: true,
+			expectHttpStatus: http.StatusOK,
+			expectResponseHeaders: map[string]string{
+				echo.HeaderAccessControlAllowOrigin: "",
+				echo.HeaderVary:                     echo.HeaderOrigin,
+			},
+		},
+		{
+			scenario: "origin not allowed",
+			options: corsOptions{
+				allowOrigins: []string{"https://foo.com"},
+			},
+			method: http.MethodPost,
+			headers: map[string]string{
+				echo.HeaderOrigin: "https://bar.com",
+			},
+			expectNextCalled: true,
+			expectHttpStatus: http.StatusOK,
+			expectResponseHeaders: map[string]string{
+				echo.HeaderAccessControlAllowOrigin: "",
+			},
+		},
+		{
+			scenario: "allowed origin",
+			options: corsOptions{
+				allowOrigins:     []string{"https://foo.com"},
+				exposeHeaders:    []string{"Content-Disposition"},
+				allowCredentials: true,
+			},
+			method: http.MethodPost,
+			headers: map[string]string{
+				echo.HeaderOrigin: "https://FOO.com",
+			},
+			expectNextCalled: true,
+			expectHttpStatus: http.StatusOK,
+			expectResponseHeaders: map[string]string{
+				echo.HeaderAccessControlAllowOrigin:      "https://FOO.com",
+				echo.HeaderAccessControlAllowCredentials: "true",
+				echo.HeaderAccessControlExposeHeaders:    "Content-Disposition",
+			},
+		},
+		{
+			scenario: "any origin",
+			options: corsOptions{
+				allowOrigins: []string{"*"},
+			},
+			method: http.MethodPost,
+			headers: map[string]string{
+				echo.HeaderOrigin: "https://foo.com",
+			},
+			expectNextCalled: true,
+			expectHttpStatus: http.StatusOK,
+			expectResponseHeaders: map[string]string{
+				echo.HeaderAccessControlAllowOrigin: "*",
+			},
+		},
+		{
+			scenario: "OPTIONS request without Access-Control-Request-Method header",
+			options: corsOptions{
+				allowOrigins: []string{"*"},
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				echo.HeaderOrigin: "https://foo.com",
+			},
+			expectNextCalled: true,
+			expectHttpStatus: http.StatusOK,
+			expectResponseHeaders: map[string]string{
+				echo.HeaderAccessControlAllowOrigin:  "*",
+				echo.HeaderAccessControlAllowMethods: "",
+			},
+		},
+		{
+			scenario: "preflight request with origin not allowed",
+			options: corsOptions{
+				allowOrigins: []string{"https://foo.com"},
+				allowMethods: []string{http.MethodPost},
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				echo.HeaderOrigin:                     "https://bar.com",
+				echo.HeaderAccessControlRequestMethod: http.MethodPost,
+			},
+			expectNextCalled: false,
+			expectHttpStatus: http.StatusNoContent,
+			expectResponseHeaders: map[string]string{
+				echo.HeaderAccessControlAllowOrigin:  "",
+				echo.HeaderAccessControlAllowMethods: "",
+			},
+		},
+		{
+			scenario: "preflight request with configured headers",
+			options: corsOptions{
+				allowOrigins:     []string{"https://foo.com"},
+				allowMethods:     []string{http.MethodGet, http.MethodPost},
+				allowHeaders:     []string{"Gotenberg-Trace", "Authorization"},
+				allowCredentials: true,
+				maxAge:           time.Duration(10) * time.Minute,
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				echo.HeaderOrigin:                      "https://foo.com",
+				echo.HeaderAccessControlRequestMethod:  http.MethodPost,
+				echo.HeaderAccessControlRequestHeaders: "X-Foo",
+			},
+			expectNextCalled: false,
+			expectHttpStatus: http.StatusNoContent,
+			expectResponseHeaders: map[string]string{
+				echo.HeaderAccessControlAllowOrigin:      "https://foo.com",
+				echo.HeaderAccessControlAllowMethods:     "GET,POST",
+				echo.HeaderAccessControlAllowHeaders:     "Gotenberg-Trace,Authorization",
+				echo.HeaderAccessControlAllowCredentials: "true",
+				echo.HeaderAccessControlMaxAge:           "600",
+			},
+		},
+		{
+			scenario: "preflight request with reflected headers",
+			options: corsOptions{
+				allowOrigins: []string{"*"},
+				allowMethods: []string{http.MethodPost},
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				echo.HeaderOrigin:                      "https://foo.com",
+				echo.HeaderAccessControlRequestMethod:  http.MethodPost,
+				echo.HeaderAccessControlRequestHeaders: "X-Foo",
+			},
+			expectNextCalled: false,
+			expectHttpStatus: http.StatusNoContent,
+			expectResponseHeaders: map[string]string{
+				echo.HeaderAccessControlAllowOrigin:  "*",
+				echo.HeaderAccessControlAllowMethods: "POST",
+				echo.HeaderAccessControlAllowHeaders: "X-Foo",
+				echo.HeaderAccessControlMaxAge:       "",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(tc.method, "/forms/foo", nil)
+
+			for key, value := range tc.headers {
+				request.Header.Set(key, value)
+			}
+
+			srv := echo.New()
+			srv.HideBanner = true
+			srv.HidePort = true
+
+			c := srv.NewContext(request, recorder)
+
+			var nextCalled bool
+			err := corsMiddleware(tc.options)(
+				func(c echo.Context) error {
+					nextCalled = true
+					return c.NoContent(http.StatusOK)
+				},
+			)(c)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectNextCalled != nextCalled {
+				t.Errorf("expected next handler called to be %t but got %t", tc.expectNextCalled, nextCalled)
+			}
+
+			if tc.expectHttpStatus != recorder.Code {
+				t.Errorf("expected HTTP status %d but got %d", tc.expectHttpStatus, recorder.Code)
+			}
+
+			for key, expect := range tc.expectResponseHeaders {
+				actual := recorder.Header().Get(key)
+				if expect != actual {
+					t.Errorf("expected header '%s' to be '%s' but got '%s'", key, expect, actual)
+				}
+			}
+		})
+	}
+}
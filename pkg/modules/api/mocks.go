@@ -52,20 +52,46 @@ func (ctx *ContextMock) SetFiles(files map[string]string) {
 	ctx.files = files
 }
 
-// SetCancelled sets if the context is cancelled or not.
+// SetUploadOrder sets the filenames of the files in the order the client
+// uploaded them.
 //
 //	ctx := &api.ContextMock{Context: &api.Context{}}
-//	ctx.SetCancelled(true)
-func (ctx *ContextMock) SetCancelled(cancelled bool) {
-	ctx.cancelled = cancelled
+//	ctx.SetUploadOrder([]string{"foo.html", "bar.html"})
+func (ctx *ContextMock) SetUploadOrder(filenames []string) {
+	ctx.filenames = filenames
+}
+
+// SetDownloadedFilenames sets the filenames of the files downloaded thanks
+// to the "downloadFrom" form field.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetDownloadedFilenames([]string{"foo.docx"})
+func (ctx *ContextMock) SetDownloadedFilenames(filenames []string) {
+	ctx.downloadedFilenames = filenames
+}
+
+// SetValidateOnly sets if the client only wants to validate its request.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetValidateOnly(true)
+func (ctx *ContextMock) SetValidateOnly(validateOnly bool) {
+	ctx.validateOnly = validateOnly
+}
+
+// SetCacheable sets if identical requests give the same output.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetCacheable(true)
+func (ctx *ContextMock) SetCacheable(cacheable bool) {
+	ctx.cacheable = cacheable
 }
 
-// OutputPaths returns the registered output paths.
+// SetCancelled sets if the context is cancelled or not.
 //
 //	ctx := &api.ContextMock{Context: &api.Context{}}
-//	outputPaths := ctx.OutputPaths()
-func (ctx *ContextMock) OutputPaths() []string {
-	return ctx.outputPaths
+//	ctx.SetCancelled(true)
+func (ctx *ContextMock) SetCancelled(cancelled bool) {
+	ctx.cancelled = cancelled
 }
 
 // SetLogger sets the logger.
@@ -76,6 +102,14 @@ func (ctx *ContextMock) SetLogger(logger *zap.Logger) {
 	ctx.logger = logger
 }
 
+// SetTrace sets the request identifier.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetTrace("foo")
+func (ctx *ContextMock) SetTrace(trace string) {
+	ctx.trace = trace
+}
+
 // SetEchoContext sets the echo.Context.
 //
 //	ctx := &api.ContextMock{Context: &api.Context{}}
@@ -116,9 +150,20 @@ func (mod *HealthCheckerMock) Ready() error {
 	return mod.ReadyMock()
 }
 
+// CapabilitiesProviderMock is a mock for the [CapabilitiesProvider]
+// interface.
+type CapabilitiesProviderMock struct {
+	CapabilitiesMock func() (map[string]interface{}, error)
+}
+
+func (provider *CapabilitiesProviderMock) Capabilities() (map[string]interface{}, error) {
+	return provider.CapabilitiesMock()
+}
+
 // Interface guards.
 var (
-	_ Router             = (*RouterMock)(nil)
-	_ MiddlewareProvider = (*MiddlewareProviderMock)(nil)
-	_ HealthChecker      = (*HealthCheckerMock)(nil)
+	_ Router               = (*RouterMock)(nil)
+	_ MiddlewareProvider   = (*MiddlewareProviderMock)(nil)
+	_ HealthChecker        = (*HealthCheckerMock)(nil)
+	_ CapabilitiesProvider = (*CapabilitiesProviderMock)(nil)
 )
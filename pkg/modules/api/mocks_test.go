@@ -65,6 +65,29 @@ func TestContextMock_SetFiles(t *testing.T) {
 	}
 }
 
+func TestContextMock_SetDownloadedFilenames(t *testing.T) {
+	mock := &ContextMock{&Context{}}
+	mock.SetDownloadedFilenames([]string{"foo"})
+
+	actual := mock.DownloadedFilenames()
+	expect := []string{"foo"}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got: %+v", expect, actual)
+	}
+}
+
+func TestContextMock_SetValidateOnly(t *testing.T) {
+	mock := &ContextMock{&Context{}}
+	mock.SetValidateOnly(true)
+
+	actual := mock.ValidateOnly()
+
+	if !actual {
+		t.Errorf("expected %t but got %t", true, actual)
+	}
+}
+
 func TestContextMock_SetCancelled(t *testing.T) {
 	mock := &ContextMock{&Context{}}
 	mock.SetCancelled(true)
@@ -163,3 +186,16 @@ func TestHealthCheckerMock(t *testing.T) {
 		t.Errorf("expected no error from HealthCheckerMock.Ready, but got: %v", err)
 	}
 }
+
+func TestCapabilitiesProviderMock(t *testing.T) {
+	mock := &CapabilitiesProviderMock{
+		CapabilitiesMock: func() (map[string]interface{}, error) {
+			return nil, nil
+		},
+	}
+
+	_, err := mock.Capabilities()
+	if err != nil {
+		t.Errorf("expected no error from CapabilitiesProviderMock.Capabilities, but got: %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DocumentNames returns the filenames of the documents without their
+// extension, to name the files which derive from them. The documents with
+// the same name but another extension, e.g., "report.docx" and
+// "report.xlsx", keep their extension so that their files do not collide.
+func DocumentNames(inputPaths []string) []string {
+	names := make([]string, len(inputPaths))
+	used := make(map[string]bool, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		filename := filepath.Base(inputPath)
+
+		names[i] = strings.TrimSuffix(filename, filepath.Ext(filename))
+		if used[names[i]] {
+			names[i] = filename
+		}
+
+		used[names[i]] = true
+	}
+
+	return names
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocumentNames(t *testing.T) {
+	actual := DocumentNames([]string{"/foo/report.docx", "/foo/report.xlsx", "/foo/summary.pdf", "/foo/summary.PDF"})
+	expect := []string{"report", "report.xlsx", "summary", "summary.PDF"}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+}
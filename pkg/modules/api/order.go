@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// FormDataOrder binds the "order" form field, i.e., a JSON array of
+// filenames. It returns the filenames, or an empty slice if the client did
+// not provide the form field.
+//
+//	order := api.FormDataOrder(ctx.FormData())
+func FormDataOrder(form *FormData) *[]string {
+	order := make([]string, 0)
+
+	form.Custom("order", func(value string) error {
+		if value == "" {
+			return nil
+		}
+
+		err := json.Unmarshal([]byte(value), &order)
+		if err != nil {
+			return fmt.Errorf("wrong value, expected a JSON array of filenames: %w", err)
+		}
+
+		return nil
+	})
+
+	return &order
+}
+
+// OrderPaths returns the paths in the order of the given filenames, which
+// must then list each path exactly once. If there are no filenames, it
+// returns the paths as is.
+func OrderPaths(paths, order []string) ([]string, error) {
+	if len(order) == 0 {
+		return paths, nil
+	}
+
+	if len(order) != len(paths) {
+		return nil, fmt.Errorf("the 'order' form field lists %d file(s), expected %d", len(order), len(paths))
+	}
+
+	pathsByFilename := make(map[string]string, len(paths))
+	for _, path := range paths {
+		pathsByFilename[filepath.Base(path)] = path
+	}
+
+	seen := make(map[string]bool, len(order))
+	orderedPaths := make([]string, len(order))
+
+	for i, filename := range order {
+		path, ok := pathsByFilename[filename]
+		if !ok {
+			return nil, fmt.Errorf("the 'order' form field references the unknown file '%s'", filename)
+		}
+
+		if seen[filename] {
+			return nil, fmt.Errorf("the 'order' form field lists the file '%s' more than once", filename)
+		}
+
+		seen[filename] = true
+		orderedPaths[i] = path
+	}
+
+	return orderedPaths, nil
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormDataOrder(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		form        *FormData
+		expect      []string
+		expectError bool
+	}{
+		{
+			scenario: "order not set",
+			form:     &FormData{},
+			expect:   []string{},
+		},
+		{
+			scenario: "invalid order",
+			form: &FormData{
+				values: map[string][]string{
+					"order": {"foo"},
+				},
+			},
+			expect:      []string{},
+			expectError: true,
+		},
+		{
+			scenario: "order set",
+			form: &FormData{
+				values: map[string][]string{
+					"order": {`["b.pdf","a.docx"]`},
+				},
+			},
+			expect: []string{"b.pdf", "a.docx"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := FormDataOrder(tc.form)
+			err := tc.form.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(*actual, tc.expect) {
+				t.Errorf("expected %+v but got: %+v", tc.expect, *actual)
+			}
+		})
+	}
+}
+
+func TestOrderPaths(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		paths       []string
+		order       []string
+		expect      []string
+		expectError bool
+	}{
+		{
+			scenario: "no order",
+			paths:    []string{"/a.docx", "/b.pdf"},
+			expect:   []string{"/a.docx", "/b.pdf"},
+		},
+		{
+			scenario:    "missing file",
+			paths:       []string{"/a.docx", "/b.pdf"},
+			order:       []string{"b.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "unknown file",
+			paths:       []string{"/a.docx", "/b.pdf"},
+			order:       []string{"b.pdf", "c.docx"},
+			expectError: true,
+		},
+		{
+			scenario:    "duplicated file",
+			paths:       []string{"/a.docx", "/b.pdf"},
+			order:       []string{"b.pdf", "b.pdf"},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			paths:    []string{"/a.docx", "/b.pdf"},
+			order:    []string{"b.pdf", "a.docx"},
+			expect:   []string{"/b.pdf", "/a.docx"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := OrderPaths(tc.paths, tc.order)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got: %+v", tc.expect, actual)
+			}
+		})
+	}
+}
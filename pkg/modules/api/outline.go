@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// MergePdfsWithOutline merges the given PDFs thanks to the given
+// [gotenberg.PdfEngine] and writes an outline with a top-level entry per PDF,
+// named after the given titles and targeting the first page of the PDF. The
+// existing outline of each PDF is nested beneath its entry. Duplicated titles
+// are suffixed with their occurrence, e.g., "report.pdf (2)". It returns the
+// path of the resulting PDF.
+func MergePdfsWithOutline(ctx *Context, engine gotenberg.PdfEngine, inputPaths, titles []string) (string, error) {
+	entries := make([]gotenberg.PdfOutlineEntry, len(inputPaths))
+	occurrences := make(map[string]int)
+	page := 1
+
+	for i, inputPath := range inputPaths {
+		outline, err := engine.ReadOutline(ctx, ctx.Log(), inputPath)
+		if err != nil {
+			return "", fmt.Errorf("read PDF outline: %w", err)
+		}
+
+		title := titles[i]
+		occurrences[title]++
+		if occurrences[title] > 1 {
+			title = fmt.Sprintf("%s (%d)", title, occurrences[title])
+		}
+
+		entries[i] = gotenberg.PdfOutlineEntry{
+			Title:    title,
+			Page:     page,
+			Children: shiftOutlineEntries(outline.Entries, page-1),
+		}
+
+		page += outline.PageCount
+	}
+
+	mergeOutputPath := ctx.GeneratePath(".pdf")
+	err := engine.Merge(ctx, ctx.Log(), inputPaths, mergeOutputPath)
+	if err != nil {
+		return "", fmt.Errorf("merge PDFs: %w", err)
+	}
+
+	outputPath := ctx.GeneratePath(".pdf")
+	err = engine.SetOutline(ctx, ctx.Log(), entries, true, mergeOutputPath, outputPath)
+	if err != nil {
+		return "", fmt.Errorf("set PDF outline: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// shiftOutlineEntries returns a copy of the given entries, with their pages
+// shifted by the given offset.
+func shiftOutlineEntries(entries []gotenberg.PdfOutlineEntry, offset int) []gotenberg.PdfOutlineEntry {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	shifted := make([]gotenberg.PdfOutlineEntry, len(entries))
+	for i, entry := range entries {
+		shifted[i] = gotenberg.PdfOutlineEntry{
+			Title:    entry.Title,
+			Page:     entry.Page + offset,
+			Children: shiftOutlineEntries(entry.Children, offset),
+		}
+	}
+
+	return shifted
+}
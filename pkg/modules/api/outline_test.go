@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestMergePdfsWithOutline(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		engine        *gotenberg.PdfEngineMock
+		expectError   bool
+		expectEntries []gotenberg.PdfOutlineEntry
+	}{
+		{
+			scenario: "error from ReadOutline",
+			engine: &gotenberg.PdfEngineMock{
+				ReadOutlineMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+					return gotenberg.PdfOutline{}, errors.New("foo")
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "error from Merge",
+			engine: &gotenberg.PdfEngineMock{
+				ReadOutlineMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+					return gotenberg.PdfOutline{PageCount: 1}, nil
+				},
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "error from SetOutline",
+			engine: &gotenberg.PdfEngineMock{
+				ReadOutlineMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+					return gotenberg.PdfOutline{PageCount: 1}, nil
+				},
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+				SetOutlineMock: func(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			engine: &gotenberg.PdfEngineMock{
+				ReadOutlineMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+					if inputPath == "/bar.pdf" {
+						return gotenberg.PdfOutline{
+							PageCount: 3,
+							Entries: []gotenberg.PdfOutlineEntry{
+								{Title: "Chapter", Page: 2, Children: []gotenberg.PdfOutlineEntry{{Title: "Section", Page: 3}}},
+							},
+						}, nil
+					}
+
+					return gotenberg.PdfOutline{PageCount: 2}, nil
+				},
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+			},
+			expectError: false,
+			expectEntries: []gotenberg.PdfOutlineEntry{
+				{Title: "foo.pdf", Page: 1},
+				{Title: "bar.pdf", Page: 3, Children: []gotenberg.PdfOutlineEntry{
+					{Title: "Chapter", Page: 4, Children: []gotenberg.PdfOutlineEntry{{Title: "Section", Page: 5}}},
+				}},
+				{Title: "foo.pdf (2)", Page: 6},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.SetLogger(zap.NewNop())
+
+			var actualEntries []gotenberg.PdfOutlineEntry
+			if tc.engine.SetOutlineMock == nil {
+				tc.engine.SetOutlineMock = func(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+					actualEntries = entries
+					return nil
+				}
+			}
+
+			_, err := MergePdfsWithOutline(ctx.Context, tc.engine, []string{"/foo.pdf", "/bar.pdf", "/baz.pdf"}, []string{"foo.pdf", "bar.pdf", "foo.pdf"})
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actualEntries, tc.expectEntries) {
+				t.Errorf("expected entries %+v but got %+v", tc.expectEntries, actualEntries)
+			}
+		})
+	}
+}
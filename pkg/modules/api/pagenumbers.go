@@ -0,0 +1,132 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// pageNumberPositions are the allowed values of the "pageNumberPosition" form
+// field.
+var pageNumberPositions = []string{"top-left", "top-center", "top-right", "bottom-left", "bottom-center", "bottom-right"}
+
+// FormDataPageNumbers binds the "pageNumbers", "pageNumberFormat",
+// "pageNumberPosition", "pageNumberStartAt" and "pageNumberExclude" form
+// fields. It returns the page numbers to stamp on the resulting PDFs, or nil
+// if the client did not ask for page numbers.
+//
+//	numbers := api.FormDataPageNumbers(ctx.FormData())
+func FormDataPageNumbers(form *FormData) *gotenberg.PdfPageNumbers {
+	var pageNumbers bool
+
+	numbers := gotenberg.PdfPageNumbers{
+		Format:   "Page {page} of {total}",
+		Position: "bottom-center",
+		StartAt:  1,
+	}
+
+	form.
+		Bool("pageNumbers", &pageNumbers, false).
+		Custom("pageNumberFormat", func(value string) error {
+			if value == "" {
+				return nil
+			}
+
+			if !strings.Contains(value, "{page}") {
+				return errors.New("value does not contain the '{page}' placeholder")
+			}
+
+			numbers.Format = value
+
+			return nil
+		}).
+		Custom("pageNumberPosition", func(value string) error {
+			if value == "" {
+				return nil
+			}
+
+			for _, position := range pageNumberPositions {
+				if value == position {
+					numbers.Position = value
+					return nil
+				}
+			}
+
+			return fmt.Errorf("wrong value, expected one of '%s'", strings.Join(pageNumberPositions, "', '"))
+		}).
+		Int("pageNumberStartAt", &numbers.StartAt, 1).
+		Custom("pageNumberExclude", func(value string) error {
+			ranges, err := ParsePageRanges(value)
+			if err != nil {
+				return err
+			}
+
+			numbers.ExcludedPages = ranges
+
+			return nil
+		})
+
+	if !pageNumbers {
+		return nil
+	}
+
+	return &numbers
+}
+
+// ParsePageRanges parses comma-separated pages and ranges of pages, e.g.,
+// "1,3-4,10-", the last one meaning from page 10 up to the last page.
+func ParsePageRanges(value string) ([]gotenberg.PdfPageRange, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var ranges []gotenberg.PdfPageRange
+	for _, part := range strings.Split(value, ",") {
+		first, last, found := strings.Cut(strings.TrimSpace(part), "-")
+
+		r := gotenberg.PdfPageRange{}
+
+		var err error
+		r.First, err = strconv.Atoi(strings.TrimSpace(first))
+		if err != nil || r.First < 1 {
+			return nil, fmt.Errorf("wrong page range '%s', expected either a page number (e.g., '1') or a range of pages (e.g., '2-5' or '10-')", part)
+		}
+
+		r.Last = r.First
+		if found {
+			r.Last = 0
+
+			if strings.TrimSpace(last) != "" {
+				r.Last, err = strconv.Atoi(strings.TrimSpace(last))
+				if err != nil || r.Last < r.First {
+					return nil, fmt.Errorf("wrong page range '%s', expected either a page number (e.g., '1') or a range of pages (e.g., '2-5' or '10-')", part)
+				}
+			}
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges, nil
+}
+
+// StampPageNumbers writes the given page numbers on the given PDFs thanks to
+// the given [gotenberg.PdfEngine]. It returns the paths of the resulting
+// PDFs, in the same order.
+func StampPageNumbers(ctx *Context, engine gotenberg.PdfEngine, numbers gotenberg.PdfPageNumbers, inputPaths ...string) ([]string, error) {
+	outputPaths := make([]string, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		outputPaths[i] = ctx.GeneratePath(".pdf")
+
+		err := engine.StampPageNumbers(ctx, ctx.Log(), numbers, inputPath, outputPaths[i])
+		if err != nil {
+			return nil, fmt.Errorf("stamp page numbers: %w", err)
+		}
+	}
+
+	return outputPaths, nil
+}
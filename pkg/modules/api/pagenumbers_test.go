@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestFormDataPageNumbers(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		form        *FormData
+		expect      *gotenberg.PdfPageNumbers
+		expectError bool
+	}{
+		{
+			scenario: "pageNumbers not set",
+			form:     &FormData{},
+			expect:   nil,
+		},
+		{
+			scenario: "pageNumbers set, fallback to the defaults",
+			form: &FormData{
+				values: map[string][]string{
+					"pageNumbers": {"true"},
+				},
+			},
+			expect: &gotenberg.PdfPageNumbers{
+				Format:   "Page {page} of {total}",
+				Position: "bottom-center",
+				StartAt:  1,
+			},
+		},
+		{
+			scenario: "pageNumbers set with all options",
+			form: &FormData{
+				values: map[string][]string{
+					"pageNumbers":        {"true"},
+					"pageNumberFormat":   {"{page}/{total}"},
+					"pageNumberPosition": {"top-right"},
+					"pageNumberStartAt":  {"3"},
+					"pageNumberExclude":  {"1, 3-4,10-"},
+				},
+			},
+			expect: &gotenberg.PdfPageNumbers{
+				Format:        "{page}/{total}",
+				Position:      "top-right",
+				StartAt:       3,
+				ExcludedPages: []gotenberg.PdfPageRange{{First: 1, Last: 1}, {First: 3, Last: 4}, {First: 10}},
+			},
+		},
+		{
+			scenario: "pageNumberFormat without page placeholder",
+			form: &FormData{
+				values: map[string][]string{
+					"pageNumbers":      {"true"},
+					"pageNumberFormat": {"Page"},
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "invalid pageNumberPosition",
+			form: &FormData{
+				values: map[string][]string{
+					"pageNumbers":        {"true"},
+					"pageNumberPosition": {"center"},
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "invalid pageNumberStartAt",
+			form: &FormData{
+				values: map[string][]string{
+					"pageNumbers":       {"true"},
+					"pageNumberStartAt": {"foo"},
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "invalid page in pageNumberExclude",
+			form: &FormData{
+				values: map[string][]string{
+					"pageNumbers":       {"true"},
+					"pageNumberExclude": {"0"},
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "invalid range in pageNumberExclude",
+			form: &FormData{
+				values: map[string][]string{
+					"pageNumbers":       {"true"},
+					"pageNumberExclude": {"4-2"},
+				},
+			},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := FormDataPageNumbers(tc.form)
+			err := tc.form.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if !reflect.DeepEqual(tc.expect, actual) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestStampPageNumbers(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		engine            gotenberg.PdfEngine
+		expectError       bool
+		expectOutputPaths int
+	}{
+		{
+			scenario: "error from PDF engine",
+			engine: &gotenberg.PdfEngineMock{
+				StampPageNumbersMock: func(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			engine: &gotenberg.PdfEngineMock{
+				StampPageNumbersMock: func(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectOutputPaths: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.SetLogger(zap.NewNop())
+
+			outputPaths, err := StampPageNumbers(ctx.Context, tc.engine, gotenberg.PdfPageNumbers{}, "/foo.pdf", "/bar.pdf")
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(outputPaths) != tc.expectOutputPaths {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPaths, len(outputPaths))
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	pdfcpuTypes "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// maxPageSizesHeaderLength is the maximum length of the Gotenberg-Page-Sizes
+// header value. Beyond, e.g., with thousands of pages of many sizes, the
+// header is left out.
+const maxPageSizesHeaderLength = 4096
+
+// PdfMetadataHeaders returns the headers describing the PDF of a response,
+// i.e., Gotenberg-Page-Count and Gotenberg-Page-Sizes, if the operator
+// enables this feature. It returns nil if the output file is not a PDF,
+// e.g., a ZIP archive, or if it cannot be read.
+func (ctx *Context) PdfMetadataHeaders(outputPath string) map[string]string {
+	if !ctx.pdfMetadata || strings.ToLower(filepath.Ext(outputPath)) != ".pdf" {
+		return nil
+	}
+
+	dims, err := pdfPageDims(outputPath)
+	if err != nil {
+		ctx.Log().Debug(fmt.Sprintf("skip PDF metadata headers: %s", err))
+		return nil
+	}
+
+	headers := map[string]string{
+		"Gotenberg-Page-Count": strconv.Itoa(len(dims)),
+	}
+
+	sizes := formatPageSizes(dims)
+	if len(sizes) > maxPageSizesHeaderLength {
+		ctx.Log().Debug(fmt.Sprintf("skip Gotenberg-Page-Sizes header: %d characters exceed the maximum of %d", len(sizes), maxPageSizesHeaderLength))
+		return headers
+	}
+
+	headers["Gotenberg-Page-Sizes"] = sizes
+
+	return headers
+}
+
+// formatPageSizes formats the sizes of the pages, in points, as ranges of
+// consecutive pages with the same size, e.g., "1-3:612x792,4:792x612".
+func formatPageSizes(dims []pdfcpuTypes.Dim) string {
+	format := func(value float64) string {
+		return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+	}
+
+	var ranges []string
+	for first := 0; first < len(dims); {
+		last := first
+		for last+1 < len(dims) && dims[last+1] == dims[first] {
+			last++
+		}
+
+		pages := strconv.Itoa(first + 1)
+		if last > first {
+			pages = fmt.Sprintf("%d-%d", first+1, last+1)
+		}
+
+		ranges = append(ranges, fmt.Sprintf("%s:%sx%s", pages, format(dims[first].Width), format(dims[first].Height)))
+		first = last + 1
+	}
+
+	return strings.Join(ranges, ",")
+}
+
+// pdfPageDims returns the dimensions of the pages of a PDF, rotation
+// included.
+func pdfPageDims(path string) ([]pdfcpuTypes.Dim, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open PDF: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	ctx, err := pdfcpuAPI.ReadContext(f, pdfcpuConfig.NewDefaultConfiguration())
+	if err != nil {
+		return nil, fmt.Errorf("read PDF: %w", err)
+	}
+
+	err = ctx.EnsurePageCount()
+	if err != nil {
+		return nil, fmt.Errorf("count pages: %w", err)
+	}
+
+	dims, err := ctx.PageDims()
+	if err != nil {
+		return nil, fmt.Errorf("read page dimensions: %w", err)
+	}
+
+	return dims, nil
+}
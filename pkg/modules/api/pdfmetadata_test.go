@@ -0,0 +1,113 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	pdfcpuTypes "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+func TestContext_PdfMetadataHeaders(t *testing.T) {
+	dirPath := t.TempDir()
+
+	pdfPath := fmt.Sprintf("%s/foo.pdf", dirPath)
+	err := os.WriteFile(pdfPath, newTestPdf(3), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	corruptedPath := fmt.Sprintf("%s/bar.pdf", dirPath)
+	err = os.WriteFile(corruptedPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario      string
+		pdfMetadata   bool
+		outputPath    string
+		expectHeaders map[string]string
+	}{
+		{
+			scenario:      "feature disabled",
+			pdfMetadata:   false,
+			outputPath:    pdfPath,
+			expectHeaders: nil,
+		},
+		{
+			scenario:      "not a PDF",
+			pdfMetadata:   true,
+			outputPath:    fmt.Sprintf("%s/foo.zip", dirPath),
+			expectHeaders: nil,
+		},
+		{
+			scenario:      "cannot read PDF",
+			pdfMetadata:   true,
+			outputPath:    corruptedPath,
+			expectHeaders: nil,
+		},
+		{
+			scenario:    "success",
+			pdfMetadata: true,
+			outputPath:  pdfPath,
+			expectHeaders: map[string]string{
+				"Gotenberg-Page-Count": "3",
+				"Gotenberg-Page-Sizes": "1-3:612x792",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &Context{
+				pdfMetadata: tc.pdfMetadata,
+				logger:      zap.NewNop(),
+			}
+
+			headers := ctx.PdfMetadataHeaders(tc.outputPath)
+
+			if !reflect.DeepEqual(headers, tc.expectHeaders) {
+				t.Errorf("expected %+v but got: %+v", tc.expectHeaders, headers)
+			}
+		})
+	}
+}
+
+func TestFormatPageSizes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		dims        []pdfcpuTypes.Dim
+		expectSizes string
+	}{
+		{
+			scenario:    "no pages",
+			dims:        nil,
+			expectSizes: "",
+		},
+		{
+			scenario:    "single page",
+			dims:        []pdfcpuTypes.Dim{{Width: 595.276, Height: 841.89}},
+			expectSizes: "1:595.28x841.89",
+		},
+		{
+			scenario: "many sizes",
+			dims: []pdfcpuTypes.Dim{
+				{Width: 612, Height: 792},
+				{Width: 612, Height: 792},
+				{Width: 792, Height: 612},
+				{Width: 612, Height: 792},
+				{Width: 612, Height: 792},
+			},
+			expectSizes: "1-2:612x792,3:792x612,4-5:612x792",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			sizes := formatPageSizes(tc.dims)
+
+			if sizes != tc.expectSizes {
+				t.Errorf("expected '%s' but got '%s'", tc.expectSizes, sizes)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// FormDataReproducible binds the "reproducible" and "sourceDateEpoch" form
+// fields. It returns the date to normalize the resulting PDFs with, or nil if
+// the client did not ask for reproducible PDFs. The date defaults to the Unix
+// epoch.
+//
+//	date := api.FormDataReproducible(ctx.FormData())
+func FormDataReproducible(form *FormData) *time.Time {
+	var (
+		reproducible bool
+		date         time.Time
+	)
+
+	form.
+		Bool("reproducible", &reproducible, false).
+		Custom("sourceDateEpoch", func(value string) error {
+			if value == "" {
+				date = time.Unix(0, 0).UTC()
+				return nil
+			}
+
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+
+			if seconds < 0 {
+				return errors.New("value is negative")
+			}
+
+			date = time.Unix(seconds, 0).UTC()
+
+			return nil
+		})
+
+	if !reproducible {
+		return nil
+	}
+
+	return &date
+}
+
+// ValidateReproducible returns an [HttpError] if the client asks for both
+// reproducible PDFs and PDF formats. PDF/A and PDF/UA require XMP metadata,
+// whose dates and document identifiers the normalization does not rewrite.
+func ValidateReproducible(reproducible *time.Time, pdfFormats gotenberg.PdfFormats) error {
+	if reproducible == nil {
+		return nil
+	}
+
+	var field string
+	switch {
+	case pdfFormats.PdfA != "":
+		field = "pdfa"
+	case pdfFormats.PdfUa:
+		field = "pdfua"
+	default:
+		return nil
+	}
+
+	return WrapError(
+		fmt.Errorf("got both 'reproducible' and '%s' form fields", field),
+		NewSentinelHttpError(
+			http.StatusBadRequest,
+			fmt.Sprintf("Both 'reproducible' and '%s' form fields are provided, while the XMP metadata of PDF/A and PDF/UA documents cannot be made reproducible", field),
+		),
+	)
+}
+
+// NormalizePdfs makes the given PDFs reproducible thanks to the given
+// [gotenberg.PdfEngine]. It returns the paths of the normalized PDFs, in the
+// same order.
+func NormalizePdfs(ctx *Context, engine gotenberg.PdfEngine, date time.Time, inputPaths ...string) ([]string, error) {
+	outputPaths := make([]string, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		outputPaths[i] = ctx.GeneratePath(".pdf")
+
+		err := engine.Normalize(ctx, ctx.Log(), date, inputPath, outputPaths[i])
+		if err != nil {
+			return nil, fmt.Errorf("normalize PDF: %w", err)
+		}
+	}
+
+	return outputPaths, nil
+}
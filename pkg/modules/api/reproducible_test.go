@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestFormDataReproducible(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		form        *FormData
+		expect      *time.Time
+		expectError bool
+	}{
+		{
+			scenario: "reproducible not set",
+			form:     &FormData{},
+			expect:   nil,
+		},
+		{
+			scenario: "reproducible set, fallback to the Unix epoch",
+			form: &FormData{
+				values: map[string][]string{
+					"reproducible": {"true"},
+				},
+			},
+			expect: func() *time.Time {
+				date := time.Unix(0, 0).UTC()
+				return &date
+			}(),
+		},
+		{
+			scenario: "reproducible set with sourceDateEpoch",
+			form: &FormData{
+				values: map[string][]string{
+					"reproducible":    {"true"},
+					"sourceDateEpoch": {"1704067200"},
+				},
+			},
+			expect: func() *time.Time {
+				date := time.Unix(1704067200, 0).UTC()
+				return &date
+			}(),
+		},
+		{
+			scenario: "invalid sourceDateEpoch",
+			form: &FormData{
+				values: map[string][]string{
+					"reproducible":    {"true"},
+					"sourceDateEpoch": {"foo"},
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "negative sourceDateEpoch",
+			form: &FormData{
+				values: map[string][]string{
+					"reproducible":    {"true"},
+					"sourceDateEpoch": {"-1"},
+				},
+			},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := FormDataReproducible(tc.form)
+			err := tc.form.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if (tc.expect == nil) != (actual == nil) || (tc.expect != nil && !tc.expect.Equal(*actual)) {
+				t.Errorf("expected %v but got %v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestValidateReproducible(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		reproducible *time.Time
+		pdfFormats   gotenberg.PdfFormats
+		expectError  bool
+	}{
+		{
+			scenario:   "reproducible not set",
+			pdfFormats: gotenberg.PdfFormats{PdfA: gotenberg.PdfA3b, PdfUa: true},
+		},
+		{
+			scenario:     "reproducible without PDF formats",
+			reproducible: &time.Time{},
+		},
+		{
+			scenario:     "reproducible with PDF/A",
+			reproducible: &time.Time{},
+			pdfFormats:   gotenberg.PdfFormats{PdfA: gotenberg.PdfA3b},
+			expectError:  true,
+		},
+		{
+			scenario:     "reproducible with PDF/UA",
+			reproducible: &time.Time{},
+			pdfFormats:   gotenberg.PdfFormats{PdfUa: true},
+			expectError:  true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := ValidateReproducible(tc.reproducible, tc.pdfFormats)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr HttpError
+			if tc.expectError && !errors.As(err, &httpErr) {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestNormalizePdfs(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		engine            gotenberg.PdfEngine
+		expectError       bool
+		expectOutputPaths int
+	}{
+		{
+			scenario: "error from PDF engine",
+			engine: &gotenberg.PdfEngineMock{
+				NormalizeMock: func(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			engine: &gotenberg.PdfEngineMock{
+				NormalizeMock: func(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectOutputPaths: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.SetLogger(zap.NewNop())
+
+			outputPaths, err := NormalizePdfs(ctx.Context, tc.engine, time.Unix(0, 0), "/foo.pdf", "/bar.pdf")
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(outputPaths) != tc.expectOutputPaths {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPaths, len(outputPaths))
+			}
+		})
+	}
+}
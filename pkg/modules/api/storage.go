@@ -0,0 +1,242 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/labstack/gommon/bytes"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+const (
+	// maxCollectInterval is the maximum interval between two removals of the
+	// orphaned working directories.
+	maxCollectInterval = time.Duration(1) * time.Minute
+
+	// usageCacheDuration is how long the usage of the working directories is
+	// cached, as the metrics may be read every second.
+	usageCacheDuration = time.Duration(10) * time.Second
+)
+
+// storage watches the working directories of the requests: it rejects the
+// requests when the disk is nearly full, and removes the orphaned working
+// directories, e.g., the ones of the requests which have crashed.
+type storage struct {
+	fs           *gotenberg.FileSystem
+	minFreeSpace int64
+	ttl          time.Duration
+	logger       *zap.Logger
+
+	ticker *time.Ticker
+	done   chan struct{}
+
+	usageMu    sync.Mutex
+	usageTime  time.Time
+	usageCount int
+	usageSize  int64
+}
+
+// freeSpace returns the available space, in bytes, of the disk of the
+// temporary directory.
+func (s *storage) freeSpace() (int64, error) {
+	var stat syscall.Statfs_t
+
+	err := syscall.Statfs(os.TempDir(), &stat)
+	if err != nil {
+		return 0, fmt.Errorf("get file system statistics: %w", err)
+	}
+
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
+
+// checkFreeSpace returns an error if the available space of the disk is below
+// the minimum. It does nothing if there is no minimum or if the available
+// space is unknown.
+func (s *storage) checkFreeSpace() error {
+	if s.minFreeSpace <= 0 {
+		return nil
+	}
+
+	free, err := s.freeSpace()
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("check free disk space: %s", err))
+
+		return nil
+	}
+
+	if free >= s.minFreeSpace {
+		return nil
+	}
+
+	return WrapError(
+		fmt.Errorf("free disk space %s is below the minimum %s", bytes.Format(free), bytes.Format(s.minFreeSpace)),
+		NewSentinelHttpError(http.StatusServiceUnavailable, "Service Unavailable: not enough disk space, please retry later").WithCode("INSUFFICIENT_DISK_SPACE"),
+	)
+}
+
+// orphanedWorkingDirPaths returns the paths of the working directories in
+// the temporary directory of the Gotenberg processes of this host which are
+// not running anymore, e.g., after a crash.
+func (s *storage) orphanedWorkingDirPaths() []string {
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(os.TempDir(), entry.Name())
+		if gotenberg.IsOrphanedWorkingDir(path) {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// walkFiles calls fn for each file and directory of the given tree, ignoring
+// the ones which disappear in the meantime.
+func walkFiles(root string, fn func(d fs.DirEntry, info fs.FileInfo)) {
+	_ = filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The owner has likely removed the file in the meantime.
+			return nil
+		}
+
+		info, err := d.Info()
+		if err == nil {
+			fn(d, info)
+		}
+
+		return nil
+	})
+}
+
+// usage returns the number of working directories, i.e., the ones of the
+// requests and the orphaned ones, and the bytes in use by their files. As it
+// walks all their files, the result is cached for a little while.
+func (s *storage) usage() (int, int64) {
+	s.usageMu.Lock()
+	defer s.usageMu.Unlock()
+
+	if !s.usageTime.IsZero() && time.Since(s.usageTime) < usageCacheDuration {
+		return s.usageCount, s.usageSize
+	}
+
+	var paths []string
+
+	entries, err := os.ReadDir(s.fs.WorkingDirPath())
+	if err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				paths = append(paths, filepath.Join(s.fs.WorkingDirPath(), entry.Name()))
+			}
+		}
+	}
+
+	paths = append(paths, s.orphanedWorkingDirPaths()...)
+
+	var size int64
+
+	for _, path := range paths {
+		walkFiles(path, func(d fs.DirEntry, info fs.FileInfo) {
+			if !d.IsDir() {
+				size += info.Size()
+			}
+		})
+	}
+
+	s.usageTime = time.Now()
+	s.usageCount = len(paths)
+	s.usageSize = size
+
+	return s.usageCount, s.usageSize
+}
+
+// collect removes the working directories of the requests which have not
+// been modified for longer than the TTL, and the orphaned working
+// directories of the previous processes of this host.
+func (s *storage) collect() {
+	expirationTime := time.Now().Add(-s.ttl)
+
+	entries, err := os.ReadDir(s.fs.WorkingDirPath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.logger.Error(fmt.Sprintf("read working directory: %s", err))
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(expirationTime) {
+			continue
+		}
+
+		s.remove(filepath.Join(s.fs.WorkingDirPath(), entry.Name()))
+	}
+
+	// The processes which owned these working directories are gone.
+	for _, path := range s.orphanedWorkingDirPaths() {
+		s.remove(path)
+	}
+}
+
+// remove removes an orphaned working directory.
+func (s *storage) remove(path string) {
+	err := os.RemoveAll(path)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("remove orphaned working directory: %s", err))
+
+		return
+	}
+
+	s.logger.Info(fmt.Sprintf("orphaned working directory '%s' removed", path))
+}
+
+// start removes the orphaned working directories periodically, if there is
+// a TTL.
+func (s *storage) start() {
+	if s.ttl <= 0 {
+		return
+	}
+
+	s.ticker = time.NewTicker(min(s.ttl, maxCollectInterval))
+	s.done = make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-s.ticker.C:
+				s.collect()
+			}
+		}
+	}()
+}
+
+// stop stops the periodic removal of the orphaned working directories.
+func (s *storage) stop() {
+	if s.ticker == nil {
+		return
+	}
+
+	s.ticker.Stop()
+	close(s.done)
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestStorage_checkFreeSpace(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		minFreeSpace     int64
+		expectHttpStatus int
+	}{
+		{
+			scenario: "no minimum",
+		},
+		{
+			scenario:     "enough free space",
+			minFreeSpace: 1,
+		},
+		{
+			scenario:         "not enough free space",
+			minFreeSpace:     math.MaxInt64,
+			expectHttpStatus: http.StatusServiceUnavailable,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := &storage{
+				fs:           gotenberg.NewFileSystem(),
+				minFreeSpace: tc.minFreeSpace,
+				logger:       zap.NewNop(),
+			}
+
+			err := s.checkFreeSpace()
+
+			if tc.expectHttpStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return
+			}
+
+			var httpErr HttpError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected an HTTP error but got: %v", err)
+			}
+
+			status, _ := httpErr.HttpError()
+			if status != tc.expectHttpStatus {
+				t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+			}
+		})
+	}
+}
+
+func TestStorage_collect(t *testing.T) {
+	// Isolates the working directories from the other processes.
+	t.Setenv("TMPDIR", t.TempDir())
+
+	s := &storage{
+		fs:     gotenberg.NewFileSystem(),
+		ttl:    time.Duration(1) * time.Hour,
+		logger: zap.NewNop(),
+	}
+
+	defer func() {
+		err := os.RemoveAll(s.fs.WorkingDirPath())
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}()
+
+	// No working directory yet.
+	s.collect()
+
+	orphanedPath, err := s.fs.MkdirAll()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = os.WriteFile(fmt.Sprintf("%s/foo.pdf", orphanedPath), []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expired := time.Now().Add(-time.Duration(2) * time.Hour)
+	err = os.Chtimes(orphanedPath, expired, expired)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	activePath, err := s.fs.MkdirAll()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	count, size := s.usage()
+	if count != 2 {
+		t.Errorf("expected 2 working directories but got %d", count)
+	}
+
+	if size != 3 {
+		t.Errorf("expected 3 bytes in use but got %d", size)
+	}
+
+	s.collect()
+
+	_, err = os.Stat(orphanedPath)
+	if !os.IsNotExist(err) {
+		t.Errorf("expected orphaned working directory '%s' to be removed but got: %v", orphanedPath, err)
+	}
+
+	_, err = os.Stat(activePath)
+	if err != nil {
+		t.Errorf("expected working directory '%s' to be kept but got: %v", activePath, err)
+	}
+}
+
+func TestStorage_collectOrphanedWorkingDirs(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	s := &storage{
+		fs:     gotenberg.NewFileSystem(),
+		ttl:    time.Duration(1) * time.Hour,
+		logger: zap.NewNop(),
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	// A PID above the maximum of Linux, i.e., of no process.
+	const deadPid = 1 << 23
+
+	mkdir := func(marker string) string {
+		path := fmt.Sprintf("%s/%s", os.TempDir(), uuid.NewString())
+
+		err := os.MkdirAll(fmt.Sprintf("%s/foo", path), 0o755)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		err = os.WriteFile(fmt.Sprintf("%s/foo/foo.pdf", path), []byte("foo"), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if marker != "" {
+			err = os.WriteFile(fmt.Sprintf("%s/.gotenberg", path), []byte(marker), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		}
+
+		return path
+	}
+
+	orphanedPath := mkdir(fmt.Sprintf("%s\n%d\n", hostname, deadPid))
+	runningPath := mkdir(fmt.Sprintf("%s\n%d\n", hostname, os.Getpid()))
+	otherHostPath := mkdir(fmt.Sprintf("%s-foo\n%d\n", hostname, deadPid))
+	otherAppPath := mkdir("")
+
+	count, size := s.usage()
+	if count != 1 {
+		t.Errorf("expected 1 working directory but got %d", count)
+	}
+
+	if size != 3+int64(len(fmt.Sprintf("%s\n%d\n", hostname, deadPid))) {
+		t.Errorf("expected the bytes of the orphaned working directory but got %d", size)
+	}
+
+	s.collect()
+
+	_, err = os.Stat(orphanedPath)
+	if !os.IsNotExist(err) {
+		t.Errorf("expected orphaned working directory '%s' to be removed but got: %v", orphanedPath, err)
+	}
+
+	for _, path := range []string{runningPath, otherHostPath, otherAppPath} {
+		_, err = os.Stat(path)
+		if err != nil {
+			t.Errorf("expected directory '%s' to be kept but got: %v", path, err)
+		}
+	}
+}
+
+func TestStorage_usageCache(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	s := &storage{
+		fs:     gotenberg.NewFileSystem(),
+		logger: zap.NewNop(),
+	}
+
+	count, _ := s.usage()
+	if count != 0 {
+		t.Errorf("expected no working directory but got %d", count)
+	}
+
+	_, err := s.fs.MkdirAll()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	count, _ = s.usage()
+	if count != 0 {
+		t.Errorf("expected the cached usage but got %d working directories", count)
+	}
+
+	s.usageTime = time.Now().Add(-usageCacheDuration)
+
+	count, _ = s.usage()
+	if count != 1 {
+		t.Errorf("expected 1 working directory but got %d", count)
+	}
+}
+
+func TestStorage_start(t *testing.T) {
+	s := &storage{
+		fs:     gotenberg.NewFileSystem(),
+		logger: zap.NewNop(),
+	}
+
+	// No TTL, nothing to start nor to stop.
+	s.start()
+	s.stop()
+
+	if s.ticker != nil {
+		t.Error("expected no ticker")
+	}
+
+	s.ttl = time.Duration(1) * time.Hour
+	s.start()
+	s.stop()
+
+	if s.ticker == nil {
+		t.Error("expected a ticker")
+	}
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Auth))
+}
+
+// Auth is a module which provides a middleware for rejecting requests
+// without a valid API key.
+type Auth struct {
+	enable      bool
+	header      string
+	keys        [][sha256.Size]byte
+	exemptPaths []string
+}
+
+// Descriptor returns an [Auth]'s module descriptor.
+func (mod *Auth) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "auth",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("auth", flag.ExitOnError)
+			fs.Bool("auth-enable", false, "Enable the authentication of requests thanks to API keys")
+			fs.String("auth-header", "Authorization", "Set the header with the API key - the Authorization header expects the Bearer scheme")
+			fs.StringSlice("auth-keys", make([]string, 0), "Set the accepted API keys")
+			fs.String("auth-keys-from-env", "", "Set the environment variable with the accepted API keys, separated by commas - added to the keys from the flag")
+			fs.StringSlice("auth-exempt-paths", []string{"/health"}, "Set the paths which do not require an API key (e.g., /health, /prometheus/metrics)")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Auth) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Auth) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("auth-enable")
+	mod.header = flags.MustString("auth-header")
+	mod.exemptPaths = flags.MustStringSlice("auth-exempt-paths")
+
+	keys := flags.MustStringSlice("auth-keys")
+
+	// Keys from env?
+	keysEnvVar := flags.MustString("auth-keys-from-env")
+	if keysEnvVar != "" {
+		val, ok := os.LookupEnv(keysEnvVar)
+		if !ok {
+			return fmt.Errorf("environment variable '%s' does not exist", keysEnvVar)
+		}
+
+		keys = append(keys, strings.Split(val, ",")...)
+	}
+
+	// We only keep the hashes of the keys, so that comparisons in constant
+	// time do not depend on the length of the keys.
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		mod.keys = append(mod.keys, sha256.Sum256([]byte(key)))
+	}
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Auth) Validate() error {
+	if !mod.enable {
+		return nil
+	}
+
+	var err error
+
+	if strings.TrimSpace(mod.header) == "" {
+		err = multierr.Append(err,
+			errors.New("header must not be empty"),
+		)
+	}
+
+	if len(mod.keys) == 0 {
+		err = multierr.Append(err,
+			errors.New("at least one API key must be provided"),
+		)
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware.
+func (mod *Auth) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		authMiddleware(mod),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*Auth)(nil)
+	_ gotenberg.Provisioner  = (*Auth)(nil)
+	_ gotenberg.Validator    = (*Auth)(nil)
+	_ api.MiddlewareProvider = (*Auth)(nil)
+)
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestAuth_Descriptor(t *testing.T) {
+	descriptor := new(Auth).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Auth))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestAuth_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		args        []string
+		setEnv      func()
+		expectKeys  int
+		expectError bool
+	}{
+		{
+			scenario:   "default flags",
+			expectKeys: 0,
+		},
+		{
+			scenario:   "keys from flag",
+			args:       []string{"--auth-keys=foo,bar"},
+			expectKeys: 2,
+		},
+		{
+			scenario:    "non-existing environment variable",
+			args:        []string{"--auth-keys-from-env=AUTH_TEST_NOT_SET"},
+			expectError: true,
+		},
+		{
+			scenario: "keys from flag and environment variable",
+			args:     []string{"--auth-keys=foo", "--auth-keys-from-env=AUTH_TEST_KEYS"},
+			setEnv: func() {
+				t.Setenv("AUTH_TEST_KEYS", "bar, baz,")
+			},
+			expectKeys: 3,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			if tc.setEnv != nil {
+				tc.setEnv()
+			}
+
+			fs := new(Auth).Descriptor().FlagSet
+			err := fs.Parse(tc.args)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			mod := new(Auth)
+			err = mod.Provision(gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil))
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if len(mod.keys) != tc.expectKeys {
+				t.Errorf("expected %d keys but got %d", tc.expectKeys, len(mod.keys))
+			}
+		})
+	}
+
+}
+
+func TestAuth_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Auth
+		expectError bool
+	}{
+		{
+			scenario:    "disabled",
+			mod:         &Auth{enable: false},
+			expectError: false,
+		},
+		{
+			scenario:    "empty header and no keys",
+			mod:         &Auth{enable: true, header: " "},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			mod: &Auth{enable: true, header: "Authorization", keys: [][sha256.Size]byte{
+				sha256.Sum256([]byte("foo")),
+			}},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestAuth_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		enable            bool
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "authentication disabled",
+			enable:            false,
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "authentication enabled",
+			enable:            true,
+			expectMiddlewares: 1,
+		},
+	} {
+		mod := &Auth{enable: tc.enable, header: "Authorization"}
+
+		middlewares, err := mod.Middlewares()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if tc.expectMiddlewares != len(middlewares) {
+			t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package auth provides a module which adds a middleware for authenticating
+// requests thanks to API keys.
+package auth
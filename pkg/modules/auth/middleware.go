@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func authMiddleware(mod *Auth) api.Middleware {
+	bearer := strings.EqualFold(mod.header, echo.HeaderAuthorization)
+
+	return api.Middleware{
+		// The authentication must happen before any other middleware.
+		Priority: api.VeryHighPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					// c.Path() contains the root path of the API, which the
+					// exempted paths do not.
+					rootPath, ok := c.Get("rootPath").(string)
+					if !ok {
+						rootPath = "/"
+					}
+
+					path := "/" + strings.TrimPrefix(c.Path(), rootPath)
+					if slices.Contains(mod.exemptPaths, path) {
+						return next(c)
+					}
+
+					key := c.Request().Header.Get(mod.header)
+					if bearer {
+						scheme, token, ok := strings.Cut(key, " ")
+						if !ok || !strings.EqualFold(scheme, "Bearer") {
+							key = ""
+						} else {
+							key = strings.TrimSpace(token)
+						}
+					}
+
+					var err error
+					if key == "" {
+						err = errors.New("missing API key")
+					} else if !mod.isValid(key) {
+						err = errors.New("invalid API key")
+					}
+
+					if err == nil {
+						// The other middlewares may trust this header from
+						// now on, e.g., to identify the client.
+						c.Set("authHeader", mod.header)
+
+						return next(c)
+					}
+
+					if bearer {
+						c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+					}
+
+					return api.WrapError(
+						err,
+						api.NewSentinelHttpError(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized)),
+					)
+				}
+			}
+		}(),
+	}
+}
+
+// isValid tells if the given key matches one of the accepted keys. It
+// compares the key with every accepted key in constant time.
+func (mod *Auth) isValid(key string) bool {
+	hash := sha256.Sum256([]byte(key))
+
+	valid := 0
+	for _, accepted := range mod.keys {
+		valid |= subtle.ConstantTimeCompare(hash[:], accepted[:])
+	}
+
+	return valid == 1
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	keys := [][sha256.Size]byte{
+		sha256.Sum256([]byte("foo")),
+		sha256.Sum256([]byte("bar")),
+	}
+
+	for _, tc := range []struct {
+		scenario              string
+		mod                   *Auth
+		rootPath              string
+		path                  string
+		headers               map[string]string
+		expectHttpStatus      int
+		expectWwwAuthenticate string
+	}{
+		{
+			scenario:         "exempted path",
+			mod:              &Auth{header: "Authorization", keys: keys, exemptPaths: []string{"/health"}},
+			rootPath:         "/root/",
+			path:             "/root/health",
+			expectHttpStatus: 0,
+		},
+		{
+			scenario:              "path ending with an exempted path",
+			mod:                   &Auth{header: "Authorization", keys: keys, exemptPaths: []string{"/url"}},
+			rootPath:              "/root/",
+			path:                  "/root/forms/chromium/convert/url",
+			expectHttpStatus:      http.StatusUnauthorized,
+			expectWwwAuthenticate: "Bearer",
+		},
+		{
+			scenario:              "missing Authorization header",
+			mod:                   &Auth{header: "Authorization", keys: keys},
+			path:                  "/forms/foo",
+			expectHttpStatus:      http.StatusUnauthorized,
+			expectWwwAuthenticate: "Bearer",
+		},
+		{
+			scenario:              "wrong scheme",
+			mod:                   &Auth{header: "Authorization", keys: keys},
+			path:                  "/forms/foo",
+			headers:               map[string]string{"Authorization": "Basic foo"},
+			expectHttpStatus:      http.StatusUnauthorized,
+			expectWwwAuthenticate: "Bearer",
+		},
+		{
+			scenario:              "invalid bearer token",
+			mod:                   &Auth{header: "Authorization", keys: keys},
+			path:                  "/forms/foo",
+			headers:               map[string]string{"Authorization": "Bearer baz"},
+			expectHttpStatus:      http.StatusUnauthorized,
+			expectWwwAuthenticate: "Bearer",
+		},
+		{
+			scenario:         "valid bearer token",
+			mod:              &Auth{header: "Authorization", keys: keys},
+			path:             "/forms/foo",
+			headers:          map[string]string{"Authorization": "Bearer bar"},
+			expectHttpStatus: 0,
+		},
+		{
+			scenario:         "invalid API key",
+			mod:              &Auth{header: "X-Api-Key", keys: keys},
+			path:             "/forms/foo",
+			headers:          map[string]string{"X-Api-Key": "baz"},
+			expectHttpStatus: http.StatusUnauthorized,
+		},
+		{
+			scenario:         "valid API key",
+			mod:              &Auth{header: "X-Api-Key", keys: keys},
+			path:             "/forms/foo",
+			headers:          map[string]string{"X-Api-Key": "foo"},
+			expectHttpStatus: 0,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			c.SetPath(tc.path)
+			if tc.rootPath != "" {
+				c.Set("rootPath", tc.rootPath)
+			}
+
+			err := authMiddleware(tc.mod).Handler(func(c echo.Context) error {
+				return nil
+			})(c)
+
+			if tc.expectHttpStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return
+			}
+
+			var httpErr api.HttpError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected an HTTP error but got: %v", err)
+			}
+
+			status, _ := httpErr.HttpError()
+			if status != tc.expectHttpStatus {
+				t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+			}
+
+			wwwAuthenticate := c.Response().Header().Get(echo.HeaderWWWAuthenticate)
+			if wwwAuthenticate != tc.expectWwwAuthenticate {
+				t.Errorf("expected '%s' as WWW-Authenticate header but got '%s'", tc.expectWwwAuthenticate, wwwAuthenticate)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Cache))
+}
+
+const (
+	memoryBackend     = "memory"
+	filesystemBackend = "filesystem"
+)
+
+// Cache is a module which provides a middleware for caching the outputs of
+// the multipart/form-data routes.
+type Cache struct {
+	enable     bool
+	backend    string
+	ttl        time.Duration
+	maxEntries int
+	store      Store
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// Descriptor returns a [Cache]'s module descriptor.
+func (mod *Cache) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "cache",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("cache", flag.ExitOnError)
+			fs.Bool("cache-enable", false, "Enable the cache of the outputs of the multipart/form-data routes")
+			fs.String("cache-backend", memoryBackend, "Set the backend of the cache - memory or filesystem")
+			fs.Duration("cache-ttl", time.Duration(1)*time.Hour, "Set the duration during which an output is returned to the identical requests")
+			fs.Int("cache-max-entries", 100, "Set the maximum number of entries of the memory backend - the oldest entries are evicted first")
+			fs.String("cache-dir", "", "Set the directory of the filesystem backend - a temporary directory by default")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Cache) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Cache) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("cache-enable")
+	mod.backend = flags.MustString("cache-backend")
+	mod.ttl = flags.MustDuration("cache-ttl")
+	mod.maxEntries = flags.MustInt("cache-max-entries")
+
+	if !mod.enable {
+		return nil
+	}
+
+	providers, err := ctx.Modules(new(StoreProvider))
+	if err != nil {
+		return fmt.Errorf("get store providers: %w", err)
+	}
+
+	switch len(providers) {
+	case 0:
+		switch mod.backend {
+		case memoryBackend:
+			mod.store = newMemoryStore(mod.ttl, mod.maxEntries)
+		case filesystemBackend:
+			dirPath := flags.MustString("cache-dir")
+			if dirPath == "" {
+				dirPath = gotenberg.NewFileSystem().NewDirPath()
+			}
+
+			mod.store = newFileStore(dirPath, mod.ttl)
+		}
+	case 1:
+		mod.store, err = providers[0].(StoreProvider).CacheStore()
+		if err != nil {
+			return fmt.Errorf("get store: %w", err)
+		}
+	default:
+		return errors.New("expected at most one cache store provider")
+	}
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Cache) Validate() error {
+	if !mod.enable {
+		return nil
+	}
+
+	var err error
+
+	if !slices.Contains([]string{memoryBackend, filesystemBackend}, mod.backend) {
+		err = multierr.Append(err,
+			fmt.Errorf("backend must be either %s or %s", memoryBackend, filesystemBackend),
+		)
+	}
+
+	if mod.ttl <= 0 {
+		err = multierr.Append(err,
+			errors.New("TTL must be more than 0"),
+		)
+	}
+
+	if mod.maxEntries < 1 {
+		err = multierr.Append(err,
+			errors.New("max entries must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware.
+func (mod *Cache) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		cacheMiddleware(mod),
+	}, nil
+}
+
+// Metrics returns the metrics.
+func (mod *Cache) Metrics() ([]gotenberg.Metric, error) {
+	return []gotenberg.Metric{
+		{
+			Name:        "cache_hits_count",
+			Description: "Current number of requests served from the cache.",
+			Read: func() float64 {
+				return float64(mod.hits.Load())
+			},
+		},
+		{
+			Name:        "cache_misses_count",
+			Description: "Current number of cacheable requests not found in the cache.",
+			Read: func() float64 {
+				return float64(mod.misses.Load())
+			},
+		},
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module          = (*Cache)(nil)
+	_ gotenberg.Provisioner     = (*Cache)(nil)
+	_ gotenberg.Validator       = (*Cache)(nil)
+	_ gotenberg.MetricsProvider = (*Cache)(nil)
+	_ api.MiddlewareProvider    = (*Cache)(nil)
+)
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestCache_Descriptor(t *testing.T) {
+	descriptor := new(Cache).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Cache))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestCache_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		args        []string
+		expectStore reflect.Type
+	}{
+		{
+			scenario:    "default flags",
+			expectStore: nil,
+		},
+		{
+			scenario:    "memory backend",
+			args:        []string{"--cache-enable=true"},
+			expectStore: reflect.TypeOf(new(memoryStore)),
+		},
+		{
+			scenario:    "filesystem backend",
+			args:        []string{"--cache-enable=true", "--cache-backend=filesystem", "--cache-dir=/foo"},
+			expectStore: reflect.TypeOf(new(fileStore)),
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			fs := new(Cache).Descriptor().FlagSet
+			err := fs.Parse(tc.args)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			mod := new(Cache)
+			err = mod.Provision(gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectStore == nil {
+				if mod.store != nil {
+					t.Error("expected no store")
+				}
+
+				return
+			}
+
+			actual := reflect.TypeOf(mod.store)
+			if actual != tc.expectStore {
+				t.Errorf("expected '%s' but got '%s'", tc.expectStore, actual)
+			}
+		})
+	}
+}
+
+func TestCache_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Cache
+		expectError bool
+	}{
+		{
+			scenario:    "disabled",
+			mod:         &Cache{enable: false},
+			expectError: false,
+		},
+		{
+			scenario:    "invalid backend, TTL and max entries",
+			mod:         &Cache{enable: true, backend: "foo", ttl: 0, maxEntries: 0},
+			expectError: true,
+		},
+		{
+			scenario:    "success",
+			mod:         &Cache{enable: true, backend: memoryBackend, ttl: time.Hour, maxEntries: 1},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestCache_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		enable            bool
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "cache disabled",
+			enable:            false,
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "cache enabled",
+			enable:            true,
+			expectMiddlewares: 1,
+		},
+	} {
+		mod := &Cache{enable: tc.enable}
+
+		middlewares, err := mod.Middlewares()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if tc.expectMiddlewares != len(middlewares) {
+			t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+		}
+	}
+}
+
+func TestCache_Metrics(t *testing.T) {
+	mod := new(Cache)
+	mod.hits.Add(2)
+	mod.misses.Add(1)
+
+	metrics, err := mod.Metrics()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(metrics) != 2 {
+		t.Fatalf("expected %d metrics but got %d", 2, len(metrics))
+	}
+
+	if metrics[0].Read() != 2 {
+		t.Errorf("expected %d hits but got %f", 2, metrics[0].Read())
+	}
+
+	if metrics[1].Read() != 1 {
+		t.Errorf("expected %d misses but got %f", 1, metrics[1].Read())
+	}
+}
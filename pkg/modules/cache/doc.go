@@ -0,0 +1,11 @@
+// Package cache provides a module which caches the outputs of the
+// multipart/form-data routes. The key of an entry is a hash of the route path,
+// the form fields and the content of the files of a request, so that the same
+// conversion runs only once per TTL. The cache is disabled by default. The
+// routes whose output depends on more than the request, e.g., the conversion
+// of a live web page, are never cached.
+//
+// A client may bypass the cache thanks to the "Cache-Control: no-store"
+// header. A cache hit still goes through the webhook middleware, if any, so
+// that the output is delivered to the webhook URL.
+package cache
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func cacheMiddleware(mod *Cache) api.Middleware {
+	return api.Middleware{
+		// The webhook middleware wraps this one, so that a cache hit is
+		// delivered to the webhook URL too.
+		Stack: api.MultipartStack,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					if noStore(c.Request().Header.Get(echo.HeaderCacheControl)) {
+						return next(c)
+					}
+
+					ctx := c.Get("context").(*api.Context)
+
+					// A validation does not have any output, while the
+					// output of some routes depends on more than the request,
+					// e.g., a live web page.
+					if ctx.ValidateOnly() || !ctx.Cacheable() {
+						return next(c)
+					}
+
+					key, err := ctx.Fingerprint()
+					if err != nil {
+						return fmt.Errorf("compute cache key: %w", err)
+					}
+
+					// The generated path does not exist yet, so that it is a
+					// new directory within the context's working directory.
+					entry, ok, err := mod.store.Load(key, ctx.GeneratePath(""))
+					if err != nil {
+						ctx.Log().Error(fmt.Sprintf("load cache entry: %s", err))
+					}
+
+					if ok && err == nil {
+						mod.hits.Add(1)
+						ctx.Log().Debug("cache hit, skip the processing")
+
+						header := c.Response().Header()
+						for name, values := range entry.Header {
+							header[name] = slices.Clone(values)
+						}
+
+						return ctx.AddOutputPaths(entry.Paths...)
+					}
+
+					mod.misses.Add(1)
+
+					// The headers set so far come from the other
+					// middlewares, e.g., the trace.
+					before := c.Response().Header().Clone()
+
+					err = next(c)
+					if err != nil {
+						return err
+					}
+
+					outputPaths := ctx.OutputPaths()
+					if len(outputPaths) == 0 {
+						return nil
+					}
+
+					// The output is ready: a failure only means that the next
+					// identical request will run again.
+					err = mod.store.Save(key, Entry{
+						Paths:  outputPaths,
+						Header: handlerHeader(before, c.Response().Header()),
+					})
+					if err != nil {
+						ctx.Log().Error(fmt.Sprintf("save cache entry: %s", err))
+					}
+
+					return nil
+				}
+			}
+		}(),
+	}
+}
+
+// handlerHeader returns the headers the route handler set on its response,
+// i.e., the ones which differ from the headers before the handler ran.
+func handlerHeader(before, after http.Header) http.Header {
+	header := make(http.Header)
+	for name, values := range after {
+		if !slices.Equal(before[name], values) {
+			header[name] = slices.Clone(values)
+		}
+	}
+
+	return header
+}
+
+// noStore tells if a "Cache-Control" header has the "no-store" directive.
+func noStore(cacheControl string) bool {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestCacheMiddleware(t *testing.T) {
+	type request struct {
+		values       map[string][]string
+		cacheControl string
+		validateOnly bool
+		notCacheable bool
+	}
+
+	foo := map[string][]string{"foo": {"bar"}}
+
+	for _, tc := range []struct {
+		scenario     string
+		requests     []request
+		next         echo.HandlerFunc
+		expectCalls  int
+		expectHits   int64
+		expectMisses int64
+		expectError  bool
+	}{
+		{
+			scenario:     "cache hit",
+			requests:     []request{{values: foo}, {values: foo}},
+			expectCalls:  1,
+			expectHits:   1,
+			expectMisses: 1,
+		},
+		{
+			scenario:     "different form fields",
+			requests:     []request{{values: foo}, {values: map[string][]string{"foo": {"baz"}}}},
+			expectCalls:  2,
+			expectHits:   0,
+			expectMisses: 2,
+		},
+		{
+			scenario:     "Cache-Control: no-store",
+			requests:     []request{{values: foo}, {values: foo, cacheControl: "max-age=0, No-Store"}},
+			expectCalls:  2,
+			expectHits:   0,
+			expectMisses: 1,
+		},
+		{
+			scenario:     "validate only",
+			requests:     []request{{values: foo, validateOnly: true}, {values: foo, validateOnly: true}},
+			expectCalls:  2,
+			expectHits:   0,
+			expectMisses: 0,
+		},
+		{
+			scenario:     "route not cacheable",
+			requests:     []request{{values: foo, notCacheable: true}, {values: foo, notCacheable: true}},
+			expectCalls:  2,
+			expectHits:   0,
+			expectMisses: 0,
+		},
+		{
+			scenario: "failed request not stored",
+			requests: []request{{values: foo}, {values: foo}},
+			next: func(c echo.Context) error {
+				return errors.New("foo")
+			},
+			expectCalls:  2,
+			expectHits:   0,
+			expectMisses: 2,
+			expectError:  true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Cache{
+				store: newMemoryStore(time.Hour, 10),
+			}
+
+			calls := 0
+			next := func(c echo.Context) error {
+				calls++
+
+				if tc.next != nil {
+					return tc.next(c)
+				}
+
+				// Like the navigation information of a Chromium conversion.
+				c.Response().Header().Set("Gotenberg-Final-Url", "https://example.com")
+
+				ctx := c.Get("context").(*api.Context)
+				outputPath := ctx.GeneratePath(".pdf")
+
+				err := os.WriteFile(outputPath, []byte("foo"), 0o600)
+				if err != nil {
+					return err
+				}
+
+				return ctx.AddOutputPaths(outputPath)
+			}
+
+			srv := echo.New()
+			handler := cacheMiddleware(mod).Handler(next)
+
+			for _, r := range tc.requests {
+				dirPath := t.TempDir()
+
+				inputPath := filepath.Join(dirPath, "input.html")
+				err := os.WriteFile(inputPath, []byte("<html></html>"), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				req := httptest.NewRequest(http.MethodPost, "/forms/foo", nil)
+				if r.cacheControl != "" {
+					req.Header.Set(echo.HeaderCacheControl, r.cacheControl)
+				}
+
+				c := srv.NewContext(req, httptest.NewRecorder())
+				c.Response().Header().Set("Gotenberg-Trace", "foo")
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(dirPath)
+				ctx.SetValues(r.values)
+				ctx.SetFiles(map[string]string{"input.html": inputPath})
+				ctx.SetValidateOnly(r.validateOnly)
+				ctx.SetCacheable(!r.notCacheable)
+				ctx.SetLogger(zap.NewNop())
+				ctx.SetEchoContext(c)
+				c.Set("context", ctx.Context)
+
+				err = handler(c)
+
+				if tc.expectError && err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				if !tc.expectError && err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				if !tc.expectError && !r.validateOnly && len(ctx.OutputPaths()) != 1 {
+					t.Errorf("expected one output path but got %v", ctx.OutputPaths())
+				}
+
+				if !tc.expectError && !r.validateOnly && c.Response().Header().Get("Gotenberg-Final-Url") != "https://example.com" {
+					t.Errorf("expected the 'Gotenberg-Final-Url' header but got %+v", c.Response().Header())
+				}
+			}
+
+			if calls != tc.expectCalls {
+				t.Errorf("expected %d calls but got %d", tc.expectCalls, calls)
+			}
+
+			if mod.hits.Load() != tc.expectHits {
+				t.Errorf("expected %d hits but got %d", tc.expectHits, mod.hits.Load())
+			}
+
+			if mod.misses.Load() != tc.expectMisses {
+				t.Errorf("expected %d misses but got %d", tc.expectMisses, mod.misses.Load())
+			}
+		})
+	}
+}
+
+func TestHandlerHeader(t *testing.T) {
+	before := http.Header{
+		"Gotenberg-Trace": {"foo"},
+		"Vary":            {"Accept"},
+	}
+
+	after := http.Header{
+		"Gotenberg-Trace":     {"foo"},
+		"Vary":                {"Accept", "Accept-Encoding"},
+		"Gotenberg-Final-Url": {"https://example.com"},
+	}
+
+	actual := handlerHeader(before, after)
+	expect := http.Header{
+		"Vary":                {"Accept", "Accept-Encoding"},
+		"Gotenberg-Final-Url": {"https://example.com"},
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+}
+
+func TestNoStore(t *testing.T) {
+	for _, tc := range []struct {
+		cacheControl string
+		expect       bool
+	}{
+		{cacheControl: "", expect: false},
+		{cacheControl: "no-cache", expect: false},
+		{cacheControl: "no-store", expect: true},
+		{cacheControl: "max-age=0, no-store", expect: true},
+	} {
+		actual := noStore(tc.cacheControl)
+		if actual != tc.expect {
+			t.Errorf("expected %t for '%s' but got %t", tc.expect, tc.cacheControl, actual)
+		}
+	}
+}
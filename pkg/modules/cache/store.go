@@ -0,0 +1,381 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entry is the outputs of a request, with the headers the route handler set
+// on its response, e.g., the navigation information of a Chromium
+// conversion.
+type Entry struct {
+	// Paths are the paths of the outputs.
+	Paths []string
+
+	// Header are the HTTP headers the route handler set.
+	Header http.Header
+}
+
+// Store persists the outputs of the requests. Its methods must be safe for
+// concurrent use.
+type Store interface {
+	// Load copies the outputs of a key into the given directory, which does
+	// not exist yet, and returns their entry. It returns false if the key has
+	// no entry, or an expired one.
+	Load(key, dirPath string) (Entry, bool, error)
+
+	// Save stores the outputs of a key, replacing the previous ones if any.
+	// The filenames of the outputs are kept.
+	Save(key string, entry Entry) error
+}
+
+// StoreProvider is a module interface which provides a [Store] to the [Cache]
+// module, e.g., to share the outputs between many instances. Without such a
+// module, the "cache-backend" flag selects a built-in [Store].
+type StoreProvider interface {
+	CacheStore() (Store, error)
+}
+
+// memoryFile is an output kept in memory.
+type memoryFile struct {
+	name    string
+	content []byte
+}
+
+// memoryEntry is the outputs of a key kept in memory.
+type memoryEntry struct {
+	files     []memoryFile
+	header    http.Header
+	createdAt time.Time
+}
+
+// memoryStore keeps the outputs in memory, up to a maximum number of entries;
+// it is therefore local to an instance.
+type memoryStore struct {
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]memoryEntry
+	now        func() time.Time
+	mu         sync.Mutex
+}
+
+func newMemoryStore(ttl time.Duration, maxEntries int) *memoryStore {
+	return &memoryStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]memoryEntry),
+		now:        time.Now,
+	}
+}
+
+// Load implements [Store].
+func (s *memoryStore) Load(key, dirPath string) (Entry, bool, error) {
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	if ok && s.now().Sub(entry.createdAt) > s.ttl {
+		delete(s.entries, key)
+		ok = false
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return Entry{}, false, nil
+	}
+
+	err := os.MkdirAll(dirPath, 0o755)
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("create directory: %w", err)
+	}
+
+	paths := make([]string, len(entry.files))
+	for i, file := range entry.files {
+		paths[i] = filepath.Join(dirPath, file.name)
+
+		err = os.WriteFile(paths[i], file.content, 0o600)
+		if err != nil {
+			return Entry{}, false, fmt.Errorf("write file '%s': %w", file.name, err)
+		}
+	}
+
+	return Entry{Paths: paths, Header: entry.header.Clone()}, true, nil
+}
+
+// Save implements [Store].
+func (s *memoryStore) Save(key string, e Entry) error {
+	entry := memoryEntry{
+		files:  make([]memoryFile, len(e.Paths)),
+		header: e.Header.Clone(),
+	}
+
+	for i, path := range e.Paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read file '%s': %w", path, err)
+		}
+
+		entry.files[i] = memoryFile{
+			name:    filepath.Base(path),
+			content: content,
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry.createdAt = now
+
+	for k, e := range s.entries {
+		if now.Sub(e.createdAt) > s.ttl {
+			delete(s.entries, k)
+		}
+	}
+
+	_, exists := s.entries[key]
+	for !exists && len(s.entries) >= s.maxEntries {
+		s.evictOldest()
+	}
+
+	s.entries[key] = entry
+
+	return nil
+}
+
+// evictOldest removes the oldest entry. The caller must hold the lock.
+func (s *memoryStore) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+
+	for key, entry := range s.entries {
+		if oldestKey == "" || entry.createdAt.Before(oldest) {
+			oldestKey = key
+			oldest = entry.createdAt
+		}
+	}
+
+	delete(s.entries, oldestKey)
+}
+
+// fileStore keeps the outputs on the file system, one directory per key,
+// with the outputs in a "files" directory and the headers in a
+// "header.json" file.
+type fileStore struct {
+	dirPath   string
+	ttl       time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+func newFileStore(dirPath string, ttl time.Duration) *fileStore {
+	return &fileStore{
+		dirPath:   dirPath,
+		ttl:       ttl,
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// path returns the directory of a key. The key is hashed, so that it cannot
+// escape the directory of the store.
+func (s *fileStore) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+
+	return filepath.Join(s.dirPath, hex.EncodeToString(hash[:]))
+}
+
+// Load implements [Store].
+func (s *fileStore) Load(key, dirPath string) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+
+	entryPath := s.path(key)
+
+	info, err := os.Stat(entryPath)
+	if os.IsNotExist(err) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("stat entry directory: %w", err)
+	}
+
+	if s.now().Sub(info.ModTime()) > s.ttl {
+		return Entry{}, false, nil
+	}
+
+	var header http.Header
+
+	b, err := os.ReadFile(filepath.Join(entryPath, "header.json"))
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("read header: %w", err)
+	}
+
+	err = json.Unmarshal(b, &header)
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("unmarshal header: %w", err)
+	}
+
+	filesPath := filepath.Join(entryPath, "files")
+
+	entries, err := os.ReadDir(filesPath)
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("read entry directory: %w", err)
+	}
+
+	err = os.MkdirAll(dirPath, 0o755)
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("create directory: %w", err)
+	}
+
+	paths := make([]string, len(entries))
+	for i, entry := range entries {
+		paths[i] = filepath.Join(dirPath, entry.Name())
+
+		err = copyFile(filepath.Join(filesPath, entry.Name()), paths[i])
+		if err != nil {
+			return Entry{}, false, err
+		}
+	}
+
+	return Entry{Paths: paths, Header: header}, true, nil
+}
+
+// Save implements [Store].
+func (s *fileStore) Save(key string, entry Entry) error {
+	err := os.MkdirAll(s.dirPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("create store directory: %w", err)
+	}
+
+	// The outputs are first copied into a temporary directory, which is then
+	// renamed, so that a concurrent load never gets a partial entry.
+	tmpPath := filepath.Join(s.dirPath, fmt.Sprintf(".%s", uuid.NewString()))
+
+	err = os.Mkdir(tmpPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("create temporary directory: %w", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(tmpPath)
+	}()
+
+	b, err := json.Marshal(entry.Header)
+	if err != nil {
+		return fmt.Errorf("marshal header: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(tmpPath, "header.json"), b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	filesPath := filepath.Join(tmpPath, "files")
+
+	err = os.Mkdir(filesPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("create files directory: %w", err)
+	}
+
+	for _, path := range entry.Paths {
+		err = copyFile(path, filepath.Join(filesPath, filepath.Base(path)))
+		if err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entryPath := s.path(key)
+
+	err = os.RemoveAll(entryPath)
+	if err != nil {
+		return fmt.Errorf("remove previous entry: %w", err)
+	}
+
+	err = os.Rename(tmpPath, entryPath)
+	if err != nil {
+		return fmt.Errorf("rename temporary directory: %w", err)
+	}
+
+	return nil
+}
+
+// sweep removes, at most once per minute, the expired entries. The caller
+// must hold the lock.
+func (s *fileStore) sweep() {
+	now := s.now()
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+
+	s.lastSweep = now
+
+	entries, err := os.ReadDir(s.dirPath)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		// Skip the temporary directories of the in-progress saves.
+		if !entry.IsDir() || entry.Name()[0] == '.' {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) <= s.ttl {
+			continue
+		}
+
+		_ = os.RemoveAll(filepath.Join(s.dirPath, entry.Name()))
+	}
+}
+
+// copyFile copies the content of a file to a new file.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open file '%s': %w", src, err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create file '%s': %w", dst, err)
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return fmt.Errorf("copy file '%s': %w", src, err)
+	}
+
+	err = out.Close()
+	if err != nil {
+		return fmt.Errorf("close file '%s': %w", dst, err)
+	}
+
+	return nil
+}
+
+// Interface guards.
+var (
+	_ Store = (*memoryStore)(nil)
+	_ Store = (*fileStore)(nil)
+)
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestStores(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		newStore func(dirPath string, now func() time.Time) Store
+	}{
+		{
+			scenario: "memory store",
+			newStore: func(_ string, now func() time.Time) Store {
+				s := newMemoryStore(time.Hour, 10)
+				s.now = now
+
+				return s
+			},
+		},
+		{
+			scenario: "file store",
+			newStore: func(dirPath string, now func() time.Time) Store {
+				s := newFileStore(dirPath, time.Hour)
+				s.now = now
+
+				return s
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			fs := gotenberg.NewFileSystem()
+			dirPath := fs.NewDirPath()
+
+			defer func() {
+				err := os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			err := os.MkdirAll(dirPath, 0o755)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			outputPath := filepath.Join(dirPath, "foo.pdf")
+			err = os.WriteFile(outputPath, []byte("foo"), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			now := time.Now()
+			store := tc.newStore(filepath.Join(dirPath, "store"), func() time.Time { return now })
+
+			_, ok, err := store.Load("foo", filepath.Join(dirPath, "miss"))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if ok {
+				t.Fatal("expected a cache miss")
+			}
+
+			header := http.Header{"Gotenberg-Final-Url": {"https://example.com"}}
+
+			err = store.Save("foo", Entry{Paths: []string{outputPath}, Header: header})
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			entry, ok, err := store.Load("foo", filepath.Join(dirPath, "hit"))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			paths := entry.Paths
+			if !ok || len(paths) != 1 {
+				t.Fatalf("expected a cache hit with one path but got %t and %v", ok, paths)
+			}
+
+			if !reflect.DeepEqual(entry.Header, header) {
+				t.Errorf("expected header %+v but got %+v", header, entry.Header)
+			}
+
+			if filepath.Base(paths[0]) != "foo.pdf" {
+				t.Errorf("expected filename '%s' but got '%s'", "foo.pdf", filepath.Base(paths[0]))
+			}
+
+			b, err := os.ReadFile(paths[0])
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if string(b) != "foo" {
+				t.Errorf("expected content '%s' but got '%s'", "foo", string(b))
+			}
+
+			now = now.Add(2 * time.Hour)
+
+			_, ok, err = store.Load("foo", filepath.Join(dirPath, "expired"))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if ok {
+				t.Error("expected an expired entry")
+			}
+		})
+	}
+}
+
+func TestMemoryStore_Eviction(t *testing.T) {
+	dirPath := t.TempDir()
+
+	outputPath := filepath.Join(dirPath, "foo.pdf")
+	err := os.WriteFile(outputPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	now := time.Now()
+	store := newMemoryStore(time.Hour, 1)
+	store.now = func() time.Time { return now }
+
+	err = store.Save("foo", Entry{Paths: []string{outputPath}})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	now = now.Add(time.Second)
+
+	err = store.Save("bar", Entry{Paths: []string{outputPath}})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if _, ok := store.entries["foo"]; ok {
+		t.Error("expected the oldest entry to be evicted")
+	}
+
+	if _, ok := store.entries["bar"]; !ok {
+		t.Error("expected the newest entry to be kept")
+	}
+}
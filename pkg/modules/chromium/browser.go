@@ -2,10 +2,13 @@ package chromium
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -14,6 +17,7 @@ import (
 	"github.com/chromedp/cdproto/fetch"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/target"
 	"github.com/chromedp/chromedp"
 	"go.uber.org/zap"
 
@@ -39,11 +43,24 @@ type browserArguments struct {
 	wsUrlReadTimeout         time.Duration
 
 	// Tasks specific.
-	allowList         *regexp.Regexp
-	denyList          *regexp.Regexp
-	clearCache        bool
-	clearCookies      bool
-	disableJavaScript bool
+	proxyServersAllowList            *regexp.Regexp
+	clientCertificatesDirPath        string
+	clientCertificateMaxResponseSize int64
+	allowList                        *regexp.Regexp
+	denyList                         *regexp.Regexp
+	clearCache                       bool
+	clearCookies                     bool
+	disableJavaScript                bool
+	waitForFontsTimeout              time.Duration
+	navigationTimeout                time.Duration
+	networkIdleTimeout               time.Duration
+	maxScreenshotHeight              int64
+
+	// Post-processing specific.
+	avifencBinPath string
+
+	// Sessions specific; nil if not allowed.
+	sessions *sessionStore
 }
 
 type chromiumBrowser struct {
@@ -214,48 +231,162 @@ func (b *chromiumBrowser) Healthy(logger *zap.Logger) bool {
 	return true
 }
 
+// Pid returns the PID of the Chromium browser process, or 0 if not started.
+func (b *chromiumBrowser) Pid() int {
+	if !b.isStarted.Load() {
+		return 0
+	}
+
+	b.ctxMu.RLock()
+	defer b.ctxMu.RUnlock()
+
+	chromedpCtx := chromedp.FromContext(b.ctx)
+	if chromedpCtx == nil || chromedpCtx.Browser == nil || chromedpCtx.Browser.Process() == nil {
+		return 0
+	}
+
+	return chromedpCtx.Browser.Process().Pid
+}
+
 func (b *chromiumBrowser) pdf(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+	navigationTimeout, networkIdleTimeout, err := b.navigationTimeouts(options.Options)
+	if err != nil {
+		return err
+	}
+
 	// Note: no error wrapping because it leaks on errors we want to display to
 	// the end user.
 	return b.do(ctx, logger, url, options.Options, chromedp.Tasks{
 		network.Enable(),
-		fetch.Enable(),
+		fetch.Enable().WithHandleAuthRequests(handleAuthRequests(options.Options)),
 		runtime.Enable(),
 		clearCacheActionFunc(logger, b.arguments.clearCache),
 		clearCookiesActionFunc(logger, b.arguments.clearCookies),
 		disableJavaScriptActionFunc(logger, b.arguments.disableJavaScript),
 		extraHttpHeadersActionFunc(logger, options.ExtraHttpHeaders),
-		navigateActionFunc(logger, url, options.SkipNetworkIdleEvent),
+		emulateTimezoneActionFunc(logger, options.Timezone),
+		emulateLocaleActionFunc(logger, options.Locale),
+		restoreSessionActionFunc(logger, b.arguments.sessions, options.Session),
+		setCookiesActionFunc(logger, options.Cookies),
+		emulateNetworkConditionsActionFunc(logger, options.NetworkConditions),
+		navigateActionFunc(logger, url, options.SkipNetworkIdleEvent, navigationTimeout, networkIdleTimeout),
 		hideDefaultWhiteBackgroundActionFunc(logger, options.OmitBackground, options.PrintBackground),
 		forceExactColorsActionFunc(),
-		emulateMediaTypeActionFunc(logger, options.EmulatedMediaType),
+		emulateMediaActionFunc(logger, options.EmulatedMediaType, options.ColorScheme),
+		extraCssActionFunc(logger, options.ExtraCss),
 		waitDelayBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitDelay),
 		waitForExpressionBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForExpression),
+		waitForSelectorBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForSelector),
+		waitForFontsBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForFonts, b.arguments.waitForFontsTimeout),
 		// PDF specific.
 		printToPdfActionFunc(logger, outputPath, options),
+		saveSessionActionFunc(logger, b.arguments.sessions, options.Session),
 	})
 }
 
 func (b *chromiumBrowser) screenshot(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+	capturePath := outputPath
+	if options.Format == "avif" {
+		if b.arguments.avifencBinPath == "" {
+			return ErrAvifEncoderNotAvailable
+		}
+
+		// Chromium cannot capture AVIF images: we capture a PNG image first,
+		// then transcode it.
+		capturePath = fmt.Sprintf("%s.png", strings.TrimSuffix(outputPath, filepath.Ext(outputPath)))
+	}
+
+	navigationTimeout, networkIdleTimeout, err := b.navigationTimeouts(options.Options)
+	if err != nil {
+		return err
+	}
+
 	// Note: no error wrapping because it leaks on errors we want to display to
 	// the end user.
-	return b.do(ctx, logger, url, options.Options, chromedp.Tasks{
+	err = b.do(ctx, logger, url, options.Options, chromedp.Tasks{
 		network.Enable(),
-		fetch.Enable(),
+		fetch.Enable().WithHandleAuthRequests(handleAuthRequests(options.Options)),
 		runtime.Enable(),
 		clearCacheActionFunc(logger, b.arguments.clearCache),
 		clearCookiesActionFunc(logger, b.arguments.clearCookies),
 		disableJavaScriptActionFunc(logger, b.arguments.disableJavaScript),
 		extraHttpHeadersActionFunc(logger, options.ExtraHttpHeaders),
-		navigateActionFunc(logger, url, options.SkipNetworkIdleEvent),
+		emulateTimezoneActionFunc(logger, options.Timezone),
+		emulateLocaleActionFunc(logger, options.Locale),
+		restoreSessionActionFunc(logger, b.arguments.sessions, options.Session),
+		setCookiesActionFunc(logger, options.Cookies),
+		emulateNetworkConditionsActionFunc(logger, options.NetworkConditions),
+		navigateActionFunc(logger, url, options.SkipNetworkIdleEvent, navigationTimeout, networkIdleTimeout),
 		hideDefaultWhiteBackgroundActionFunc(logger, options.OmitBackground, true),
 		forceExactColorsActionFunc(),
-		emulateMediaTypeActionFunc(logger, options.EmulatedMediaType),
+		emulateMediaActionFunc(logger, options.EmulatedMediaType, options.ColorScheme),
+		extraCssActionFunc(logger, options.ExtraCss),
 		waitDelayBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitDelay),
 		waitForExpressionBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForExpression),
+		waitForSelectorBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForSelector),
+		waitForFontsBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForFonts, b.arguments.waitForFontsTimeout),
 		// Screenshot specific.
-		captureScreenshotActionFunc(logger, outputPath, options),
+		captureScreenshotActionFunc(logger, capturePath, options, b.arguments.disableJavaScript, b.arguments.maxScreenshotHeight),
+		saveSessionActionFunc(logger, b.arguments.sessions, options.Session),
 	})
+	if err != nil || capturePath == outputPath {
+		return err
+	}
+
+	err = transcodeToAvif(ctx, logger, b.arguments.avifencBinPath, options.Quality, capturePath, outputPath)
+	if err != nil {
+		return fmt.Errorf("transcode screenshot to AVIF: %w", err)
+	}
+
+	return nil
+}
+
+// navigationTimeouts returns the navigation and network idle timeouts of a
+// conversion: the ones of the options, if any, or the ones of the instance,
+// which are also their maximums. 0 means no timeout but the one of the
+// request.
+func (b *chromiumBrowser) navigationTimeouts(options Options) (time.Duration, time.Duration, error) {
+	resolve := func(name string, requested, instance time.Duration) (time.Duration, error) {
+		if requested <= 0 {
+			return instance, nil
+		}
+
+		if instance > 0 && requested > instance {
+			return 0, fmt.Errorf("%s of %s, more than %s: %w", name, requested, instance, ErrTimeoutAboveMaximum)
+		}
+
+		return requested, nil
+	}
+
+	navigationTimeout, err := resolve("navigation timeout", options.NavigationTimeout, b.arguments.navigationTimeout)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	networkIdleTimeout, err := resolve("network idle timeout", options.NetworkIdleTimeout, b.arguments.networkIdleTimeout)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return navigationTimeout, networkIdleTimeout, nil
+}
+
+// transcodeToAvif transcodes a PNG image to AVIF thanks to avifenc. The
+// quality, from range [0..100], maps to the AV1 quantizer range [63..0].
+func transcodeToAvif(ctx context.Context, logger *zap.Logger, binPath string, quality int, inputPath, outputPath string) error {
+	quantizer := strconv.Itoa((100 - quality) * 63 / 100)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, binPath, "--min", quantizer, "--max", quantizer, inputPath, outputPath)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return fmt.Errorf("transcode with avifenc: %w", err)
+	}
+
+	return nil
 }
 
 func (b *chromiumBrowser) do(ctx context.Context, logger *zap.Logger, url string, options Options, tasks chromedp.Tasks) error {
@@ -277,18 +408,108 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *zap.Logger, url string
 		return errors.New("context has no deadline")
 	}
 
+	var taskCtxOpts []chromedp.ContextOption
+
+	if options.Session != "" {
+		if b.arguments.sessions == nil {
+			return ErrSessionsNotAllowed
+		}
+
+		// The requests of a session run one at a time.
+		unlock := b.arguments.sessions.lock(options.Session)
+		defer unlock()
+	}
+
+	var browserContextOpts []chromedp.CreateBrowserContextOption
+
+	if options.Proxy != nil {
+		if b.arguments.proxyServersAllowList == nil || b.arguments.proxyServersAllowList.String() == "" {
+			return fmt.Errorf("proxy server '%s': %w", options.Proxy.Server, ErrProxyServerNotAllowed)
+		}
+
+		if !b.arguments.proxyServersAllowList.MatchString(options.Proxy.Server) {
+			return fmt.Errorf("'%s' does not match the expression from the allowed proxy servers list: %w", options.Proxy.Server, ErrProxyServerNotAllowed)
+		}
+
+		// Chromium sets the proxy server per browser context.
+		proxy := *options.Proxy
+		browserContextOpts = append(browserContextOpts, func(p *target.CreateBrowserContextParams) *target.CreateBrowserContextParams {
+			p = p.WithProxyServer(proxy.Server)
+			if proxy.BypassList != "" {
+				p = p.WithProxyBypassList(proxy.BypassList)
+			}
+
+			return p
+		})
+	}
+
+	clientCertificate := options.ClientCertificate
+	if options.ClientCertificateName != "" {
+		if b.arguments.clientCertificatesDirPath == "" {
+			return fmt.Errorf("client certificate '%s': no mounted client certificates: %w", options.ClientCertificateName, ErrClientCertificateNotFound)
+		}
+
+		certificate, err := tls.LoadX509KeyPair(
+			filepath.Join(b.arguments.clientCertificatesDirPath, fmt.Sprintf("%s.crt", options.ClientCertificateName)),
+			filepath.Join(b.arguments.clientCertificatesDirPath, fmt.Sprintf("%s.key", options.ClientCertificateName)),
+		)
+		if err != nil {
+			return fmt.Errorf("load client certificate '%s': %v: %w", options.ClientCertificateName, err, ErrClientCertificateNotFound)
+		}
+
+		clientCertificate = &certificate
+	}
+
+	var clientTls *clientTlsFetcher
+	if clientCertificate != nil {
+		var err error
+		// The fetcher goes where Chromium would: through the proxy server
+		// of the request, or else through the one of the operator.
+		proxy := options.Proxy
+		if proxy == nil && b.arguments.proxyServer != "" {
+			proxy = &Proxy{Server: b.arguments.proxyServer}
+		}
+
+		clientTls, err = newClientTlsFetcher(url, *clientCertificate, clientTlsOptions{
+			ignoreCertificateErrors: b.arguments.ignoreCertificateErrors,
+			proxy:                   proxy,
+			hostResolverRules:       b.arguments.hostResolverRules,
+			httpAuth:                options.HttpAuth,
+			maxResponseSize:         b.arguments.clientCertificateMaxResponseSize,
+		})
+		if err != nil {
+			return fmt.Errorf("create client TLS fetcher: %w", err)
+		}
+	}
+
+	// The requests of a session, with cookies or with a proxy server run
+	// each one in its own browser context: their cookies and local storage
+	// never leak into other requests.
+	if options.Session != "" || len(options.Cookies) > 0 || options.Proxy != nil {
+		taskCtxOpts = append(taskCtxOpts, chromedp.WithNewBrowserContext(browserContextOpts...))
+	}
+
 	b.ctxMu.RLock()
 	defer b.ctxMu.RUnlock()
 
 	timeoutCtx, timeoutCancel := context.WithTimeout(b.ctx, time.Until(deadline))
 	defer timeoutCancel()
 
-	taskCtx, taskCancel := chromedp.NewContext(timeoutCtx)
+	taskCtx, taskCancel := chromedp.NewContext(timeoutCtx, taskCtxOpts...)
 	defer taskCancel()
 
 	// We validate all others requests against our allow / deny lists.
 	// If a request does not pass the validation, we make it fail.
-	listenForEventRequestPaused(taskCtx, logger, b.arguments.allowList, b.arguments.denyList)
+	listenForEventRequestPaused(taskCtx, logger, b.arguments.allowList, b.arguments.denyList, options.ExtraHttpHeaders, options.ScopedExtraHttpHeaders, clientTls)
+
+	if handleAuthRequests(options) {
+		var proxyAuth *HttpAuth
+		if options.Proxy != nil {
+			proxyAuth = options.Proxy.Auth
+		}
+
+		listenForEventAuthRequired(taskCtx, logger, url, options.HttpAuth, proxyAuth)
+	}
 
 	var (
 		invalidHttpStatusCode   error
@@ -300,6 +521,24 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *zap.Logger, url string
 		listenForEventResponseReceived(taskCtx, logger, url, options.FailOnHttpStatusCodes, &invalidHttpStatusCode, &invalidHttpStatusCodeMu)
 	}
 
+	var (
+		invalidResourceHttpStatusCode   error
+		invalidResourceHttpStatusCodeMu sync.RWMutex
+	)
+
+	if len(options.FailOnResourceHttpStatusCodes) != 0 {
+		listenForEventResponseReceivedForResources(taskCtx, logger, url, options.FailOnResourceHttpStatusCodes, &invalidResourceHttpStatusCode, &invalidResourceHttpStatusCodeMu)
+	}
+
+	var (
+		resourceLoadingFailed   error
+		resourceLoadingFailedMu sync.RWMutex
+	)
+
+	if options.FailOnResourceLoadingFailed {
+		listenForEventLoadingFailed(taskCtx, logger, url, &resourceLoadingFailed, &resourceLoadingFailedMu)
+	}
+
 	var (
 		consoleExceptions   error
 		consoleExceptionsMu sync.RWMutex
@@ -310,7 +549,23 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *zap.Logger, url string
 		listenForEventExceptionThrown(taskCtx, logger, &consoleExceptions, &consoleExceptionsMu)
 	}
 
+	var (
+		navigationInfo   NavigationInfo
+		navigationInfoMu sync.RWMutex
+	)
+
+	if options.NavigationInfo != nil {
+		listenForEventNavigation(taskCtx, logger, &navigationInfo, &navigationInfoMu)
+	}
+
 	err := chromedp.Run(taskCtx, tasks...)
+
+	// The fetcher fails the main page if it cannot answer its
+	// authentication challenge, so that its error comes first.
+	if clientTls != nil && clientTls.err() != nil {
+		return clientTls.err()
+	}
+
 	if err != nil {
 		errMessage := err.Error()
 
@@ -318,7 +573,7 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *zap.Logger, url string
 			return ErrInvalidPrinterSettings
 		}
 
-		if strings.Contains(errMessage, "Page range syntax error") {
+		if strings.Contains(errMessage, "Page range syntax error") || strings.Contains(errMessage, "Page range exceeds page count") {
 			return ErrPageRangesSyntaxError
 		}
 
@@ -329,6 +584,12 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *zap.Logger, url string
 		return fmt.Errorf("handle tasks: %w", err)
 	}
 
+	if options.NavigationInfo != nil {
+		navigationInfoMu.RLock()
+		*options.NavigationInfo = navigationInfo
+		navigationInfoMu.RUnlock()
+	}
+
 	// See https://github.com/gotenberg/gotenberg/issues/613.
 	invalidHttpStatusCodeMu.RLock()
 	defer invalidHttpStatusCodeMu.RUnlock()
@@ -337,6 +598,20 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *zap.Logger, url string
 		return fmt.Errorf("%v: %w", invalidHttpStatusCode, ErrInvalidHttpStatusCode)
 	}
 
+	invalidResourceHttpStatusCodeMu.RLock()
+	defer invalidResourceHttpStatusCodeMu.RUnlock()
+
+	if invalidResourceHttpStatusCode != nil {
+		return fmt.Errorf("%v: %w", invalidResourceHttpStatusCode, ErrInvalidResourceHttpStatusCode)
+	}
+
+	resourceLoadingFailedMu.RLock()
+	defer resourceLoadingFailedMu.RUnlock()
+
+	if resourceLoadingFailed != nil {
+		return fmt.Errorf("%v: %w", resourceLoadingFailed, ErrResourceLoadingFailed)
+	}
+
 	// See https://github.com/gotenberg/gotenberg/issues/262.
 	consoleExceptionsMu.RLock()
 	defer consoleExceptionsMu.RUnlock()
@@ -348,8 +623,15 @@ func (b *chromiumBrowser) do(ctx context.Context, logger *zap.Logger, url string
 	return nil
 }
 
+// handleAuthRequests tells if the conversion answers the authentication
+// challenges, either of the main page or of the proxy server.
+func handleAuthRequests(options Options) bool {
+	return options.HttpAuth != nil || (options.Proxy != nil && options.Proxy.Auth != nil)
+}
+
 // Interface guards.
 var (
-	_ gotenberg.Process = (*chromiumBrowser)(nil)
-	_ browser           = (*chromiumBrowser)(nil)
+	_ gotenberg.Process           = (*chromiumBrowser)(nil)
+	_ gotenberg.ProcessIdentifier = (*chromiumBrowser)(nil)
+	_ browser                     = (*chromiumBrowser)(nil)
 )
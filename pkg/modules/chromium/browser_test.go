@@ -756,6 +756,110 @@ func TestChromiumBrowser_pdf(t *testing.T) {
 				"emulate media type 'screen'",
 			},
 		},
+		{
+			scenario: "ErrInvalidColorScheme",
+			browser: newChromiumBrowser(
+				browserArguments{
+					binPath:          os.Getenv("CHROMIUM_BIN_PATH"),
+					wsUrlReadTimeout: 5 * time.Second,
+					allowList:        regexp.MustCompile(""),
+					denyList:         regexp.MustCompile(""),
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/index.html", fs.WorkingDirPath()), []byte("<h1>ErrInvalidColorScheme</h1>"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options: PdfOptions{
+				Options: Options{ColorScheme: "foo"},
+			},
+			noDeadline:    false,
+			start:         true,
+			expectError:   true,
+			expectedError: ErrInvalidColorScheme,
+		},
+		{
+			scenario: "emulate a color scheme",
+			browser: newChromiumBrowser(
+				browserArguments{
+					binPath:          os.Getenv("CHROMIUM_BIN_PATH"),
+					wsUrlReadTimeout: 5 * time.Second,
+					allowList:        regexp.MustCompile(""),
+					denyList:         regexp.MustCompile(""),
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/index.html", fs.WorkingDirPath()), []byte("<style>@media (prefers-color-scheme: dark) { body { background: black; color: white } }</style><p>Dark color scheme</p>"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options: PdfOptions{
+				Options: Options{ColorScheme: "dark"},
+			},
+			noDeadline:  false,
+			start:       true,
+			expectError: false,
+			expectedLogEntries: []string{
+				"emulate color scheme 'dark'",
+			},
+		},
+		{
+			scenario: "emulate a timezone and a locale",
+			browser: newChromiumBrowser(
+				browserArguments{
+					binPath:          os.Getenv("CHROMIUM_BIN_PATH"),
+					wsUrlReadTimeout: 5 * time.Second,
+					allowList:        regexp.MustCompile(""),
+					denyList:         regexp.MustCompile(""),
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/index.html", fs.WorkingDirPath()), []byte("<p id=\"date\"></p><script>document.getElementById(\"date\").textContent = new Date().toLocaleString()</script>"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options: PdfOptions{
+				Options: Options{Timezone: "Europe/Paris", Locale: "fr-FR"},
+			},
+			noDeadline:  false,
+			start:       true,
+			expectError: false,
+			expectedLogEntries: []string{
+				"emulate timezone 'Europe/Paris'",
+				"emulate locale 'fr-FR'",
+			},
+		},
 		{
 			scenario: "wait delay: context done",
 			browser: newChromiumBrowser(
@@ -953,6 +1057,87 @@ func TestChromiumBrowser_pdf(t *testing.T) {
 				"wait until 'window.globalVar === 'ready'' is true before print",
 			},
 		},
+		{
+			scenario: "ErrInvalidSelector",
+			browser: newChromiumBrowser(
+				browserArguments{
+					binPath:          os.Getenv("CHROMIUM_BIN_PATH"),
+					wsUrlReadTimeout: 5 * time.Second,
+					allowList:        regexp.MustCompile(""),
+					denyList:         regexp.MustCompile(""),
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/index.html", fs.WorkingDirPath()), []byte("<h1>ErrInvalidSelector</h1>"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options: PdfOptions{
+				Options: Options{WaitForSelector: "#"},
+			},
+			noDeadline:  false,
+			start:       true,
+			expectError: true,
+			expectedLogEntries: []string{
+				"wait until an element matches '#' before print",
+			},
+		},
+		{
+			scenario: "wait for selector",
+			browser: newChromiumBrowser(
+				browserArguments{
+					binPath:          os.Getenv("CHROMIUM_BIN_PATH"),
+					wsUrlReadTimeout: 5 * time.Second,
+					allowList:        regexp.MustCompile(""),
+					denyList:         regexp.MustCompile(""),
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				html := `
+<script type="application/javascript">
+    const delay = ms => new Promise(res => setTimeout(res, ms))
+    delay(2000).then(() => {
+        const div = document.createElement('div')
+        div.id = 'ready'
+        document.body.appendChild(div)
+    })
+</script>
+`
+
+				err = os.WriteFile(fmt.Sprintf("%s/index.html", fs.WorkingDirPath()), []byte(html), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options: PdfOptions{
+				Options: Options{WaitForSelector: "#ready"},
+			},
+			noDeadline:  false,
+			start:       true,
+			expectError: false,
+			expectedLogEntries: []string{
+				"wait until an element matches '#ready' before print",
+			},
+		},
 		{
 			scenario: "custom header and footer",
 			browser: newChromiumBrowser(
@@ -1102,6 +1287,46 @@ func TestChromiumBrowser_pdf(t *testing.T) {
 				"no custom header nor footer",
 			},
 		},
+		{
+			scenario: "success (CSS page sizes)",
+			browser: newChromiumBrowser(
+				browserArguments{
+					binPath:          os.Getenv("CHROMIUM_BIN_PATH"),
+					wsUrlReadTimeout: 5 * time.Second,
+					allowList:        regexp.MustCompile(""),
+					denyList:         regexp.MustCompile(""),
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				b, err := os.ReadFile("/tests/test/testdata/chromium/html/sample11/index.html")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/index.html", fs.WorkingDirPath()), b, 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.PreferCssPageSize = true
+
+				return options
+			}(),
+			noDeadline:  false,
+			start:       true,
+			expectError: false,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			core, recorded := observer.New(zapcore.DebugLevel)
@@ -1853,6 +2078,87 @@ func TestChromiumBrowser_screenshot(t *testing.T) {
 				"wait until 'window.globalVar === 'ready'' is true before print",
 			},
 		},
+		{
+			scenario: "ErrInvalidSelector",
+			browser: newChromiumBrowser(
+				browserArguments{
+					binPath:          os.Getenv("CHROMIUM_BIN_PATH"),
+					wsUrlReadTimeout: 5 * time.Second,
+					allowList:        regexp.MustCompile(""),
+					denyList:         regexp.MustCompile(""),
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/index.html", fs.WorkingDirPath()), []byte("<h1>ErrInvalidSelector</h1>"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options: ScreenshotOptions{
+				Options: Options{WaitForSelector: "#"},
+			},
+			noDeadline:  false,
+			start:       true,
+			expectError: true,
+			expectedLogEntries: []string{
+				"wait until an element matches '#' before print",
+			},
+		},
+		{
+			scenario: "wait for selector",
+			browser: newChromiumBrowser(
+				browserArguments{
+					binPath:          os.Getenv("CHROMIUM_BIN_PATH"),
+					wsUrlReadTimeout: 5 * time.Second,
+					allowList:        regexp.MustCompile(""),
+					denyList:         regexp.MustCompile(""),
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				html := `
+<script type="application/javascript">
+    const delay = ms => new Promise(res => setTimeout(res, ms))
+    delay(2000).then(() => {
+        const div = document.createElement('div')
+        div.id = 'ready'
+        document.body.appendChild(div)
+    })
+</script>
+`
+
+				err = os.WriteFile(fmt.Sprintf("%s/index.html", fs.WorkingDirPath()), []byte(html), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options: ScreenshotOptions{
+				Options: Options{WaitForSelector: "#ready"},
+			},
+			noDeadline:  false,
+			start:       true,
+			expectError: false,
+			expectedLogEntries: []string{
+				"wait until an element matches '#ready' before print",
+			},
+		},
 		{
 			scenario: "success (default options)",
 			browser: newChromiumBrowser(
@@ -2013,3 +2319,87 @@ func TestChromiumBrowser_screenshot(t *testing.T) {
 		})
 	}
 }
+
+func TestChromiumBrowser_navigationTimeouts(t *testing.T) {
+	for _, tc := range []struct {
+		scenario                 string
+		arguments                browserArguments
+		options                  Options
+		expectNavigationTimeout  time.Duration
+		expectNetworkIdleTimeout time.Duration
+		expectError              bool
+	}{
+		{
+			scenario:                 "no timeouts",
+			expectNavigationTimeout:  0,
+			expectNetworkIdleTimeout: 0,
+		},
+		{
+			scenario: "timeouts of the instance",
+			arguments: browserArguments{
+				navigationTimeout:  time.Duration(10) * time.Second,
+				networkIdleTimeout: time.Duration(5) * time.Second,
+			},
+			expectNavigationTimeout:  time.Duration(10) * time.Second,
+			expectNetworkIdleTimeout: time.Duration(5) * time.Second,
+		},
+		{
+			scenario: "timeouts of the options",
+			arguments: browserArguments{
+				navigationTimeout: time.Duration(10) * time.Second,
+			},
+			options: Options{
+				NavigationTimeout:  time.Duration(3) * time.Second,
+				NetworkIdleTimeout: time.Duration(1) * time.Minute,
+			},
+			expectNavigationTimeout:  time.Duration(3) * time.Second,
+			expectNetworkIdleTimeout: time.Duration(1) * time.Minute,
+		},
+		{
+			scenario: "navigation timeout above maximum",
+			arguments: browserArguments{
+				navigationTimeout: time.Duration(10) * time.Second,
+			},
+			options: Options{
+				NavigationTimeout: time.Duration(11) * time.Second,
+			},
+			expectError: true,
+		},
+		{
+			scenario: "network idle timeout above maximum",
+			arguments: browserArguments{
+				networkIdleTimeout: time.Duration(5) * time.Second,
+			},
+			options: Options{
+				NetworkIdleTimeout: time.Duration(6) * time.Second,
+			},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			b := &chromiumBrowser{arguments: tc.arguments}
+
+			navigationTimeout, networkIdleTimeout, err := b.navigationTimeouts(tc.options)
+
+			if tc.expectError {
+				if !errors.Is(err, ErrTimeoutAboveMaximum) {
+					t.Fatalf("expected error %v but got: %v", ErrTimeoutAboveMaximum, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if navigationTimeout != tc.expectNavigationTimeout {
+				t.Errorf("expected navigation timeout %s but got %s", tc.expectNavigationTimeout, navigationTimeout)
+			}
+
+			if networkIdleTimeout != tc.expectNetworkIdleTimeout {
+				t.Errorf("expected network idle timeout %s but got %s", tc.expectNetworkIdleTimeout, networkIdleTimeout)
+			}
+		})
+	}
+}
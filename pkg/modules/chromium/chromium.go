@@ -2,12 +2,15 @@ package chromium
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"time"
 
 	"github.com/alexliesenfeld/health"
+	"github.com/labstack/gommon/bytes"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/zap"
 
@@ -17,6 +20,32 @@ import (
 
 func init() {
 	gotenberg.MustRegisterModule(new(Chromium))
+
+	api.MustRegisterErrorCode(ErrUrlNotAuthorized, "URL_NOT_AUTHORIZED")
+	api.MustRegisterErrorCode(ErrInvalidEmulatedMediaType, "INVALID_EMULATED_MEDIA_TYPE")
+	api.MustRegisterErrorCode(ErrInvalidColorScheme, "INVALID_COLOR_SCHEME")
+	api.MustRegisterErrorCode(ErrInvalidEvaluationExpression, "INVALID_EVALUATION_EXPRESSION")
+	api.MustRegisterErrorCode(ErrInvalidSelector, "INVALID_SELECTOR")
+	api.MustRegisterErrorCode(ErrElementNotFound, "ELEMENT_NOT_FOUND")
+	api.MustRegisterErrorCode(ErrRpccMessageTooLarge, "CHROMIUM_MESSAGE_TOO_LARGE")
+	api.MustRegisterErrorCode(ErrInvalidHttpStatusCode, "INVALID_HTTP_STATUS_CODE")
+	api.MustRegisterErrorCode(ErrInvalidResourceHttpStatusCode, "INVALID_RESOURCE_HTTP_STATUS_CODE")
+	api.MustRegisterErrorCode(ErrResourceLoadingFailed, "RESOURCE_LOADING_FAILED")
+	api.MustRegisterErrorCode(ErrConsoleExceptions, "CONSOLE_EXCEPTIONS")
+	api.MustRegisterErrorCode(ErrSessionsNotAllowed, "SESSIONS_NOT_ALLOWED")
+	api.MustRegisterErrorCode(ErrProxyServerNotAllowed, "PROXY_SERVER_NOT_ALLOWED")
+	api.MustRegisterErrorCode(ErrClientCertificateNotFound, "CLIENT_CERTIFICATE_NOT_FOUND")
+	api.MustRegisterErrorCode(ErrUnsupportedHttpAuthScheme, "UNSUPPORTED_HTTP_AUTH_SCHEME")
+	api.MustRegisterErrorCode(ErrNetworkOffline, "NETWORK_OFFLINE")
+	api.MustRegisterErrorCode(ErrNavigationTimeout, "NAVIGATION_TIMEOUT")
+	api.MustRegisterErrorCode(ErrNetworkIdleTimeout, "NETWORK_IDLE_TIMEOUT")
+	api.MustRegisterErrorCode(ErrTimeoutAboveMaximum, "TIMEOUT_ABOVE_MAXIMUM")
+	api.MustRegisterErrorCode(ErrOmitBackgroundWithoutPrintBackground, "OMIT_BACKGROUND_WITHOUT_PRINT_BACKGROUND")
+	api.MustRegisterErrorCode(ErrInvalidPrinterSettings, "INVALID_PRINTER_SETTINGS")
+	api.MustRegisterErrorCode(ErrAvifEncoderNotAvailable, "AVIF_ENCODER_NOT_AVAILABLE")
+	api.MustRegisterErrorCode(ErrScreenshotHeightExceeded, "SCREENSHOT_HEIGHT_EXCEEDED")
+	api.MustRegisterErrorCode(ErrFullPageWebpTooTall, "FULL_PAGE_WEBP_TOO_TALL")
+	api.MustRegisterErrorCode(ErrPageRangesSyntaxError, "PAGE_RANGES_SYNTAX_ERROR")
 }
 
 var (
@@ -28,10 +57,21 @@ var (
 	// "screen" nor "print". Empty value are allowed though.
 	ErrInvalidEmulatedMediaType = errors.New("invalid emulated media type")
 
+	// ErrInvalidColorScheme happens if the color scheme is not "light" nor
+	// "dark".
+	ErrInvalidColorScheme = errors.New("invalid color scheme")
+
 	// ErrInvalidEvaluationExpression happens if an evaluation expression
 	// returns an exception or undefined.
 	ErrInvalidEvaluationExpression = errors.New("invalid evaluation expression")
 
+	// ErrInvalidSelector happens if a CSS selector is not valid.
+	ErrInvalidSelector = errors.New("invalid selector")
+
+	// ErrElementNotFound happens if the selector of the element to capture is
+	// not valid or if no visible element matches it.
+	ErrElementNotFound = errors.New("element not found")
+
 	// ErrRpccMessageTooLarge happens when the messages received by
 	// ChromeDevTools are larger than 100 MB.
 	ErrRpccMessageTooLarge = errors.New("rpcc message too large")
@@ -40,11 +80,56 @@ var (
 	// matches with one of the entry in [Options.FailOnHttpStatusCodes].
 	ErrInvalidHttpStatusCode = errors.New("invalid HTTP status code")
 
+	// ErrInvalidResourceHttpStatusCode happens when the status code from one
+	// or more resources matches with one of the entry in
+	// [Options.FailOnResourceHttpStatusCodes].
+	ErrInvalidResourceHttpStatusCode = errors.New("invalid resource HTTP status code")
+
+	// ErrResourceLoadingFailed happens when one or more resources fail to
+	// load (e.g., aborted requests). It also happens only if the
+	// [Options.FailOnResourceLoadingFailed] is set to true.
+	ErrResourceLoadingFailed = errors.New("resource loading failed")
+
 	// ErrConsoleExceptions happens when there are exceptions in the Chromium
 	// console. It also happens only if the [Options.FailOnConsoleExceptions]
 	// is set to true.
 	ErrConsoleExceptions = errors.New("console exceptions")
 
+	// ErrSessionsNotAllowed happens if [Options.Session] is set while the
+	// sessions are not allowed.
+	ErrSessionsNotAllowed = errors.New("sessions not allowed")
+
+	// ErrProxyServerNotAllowed happens if [Options.Proxy] is set while the
+	// per-request proxy servers are not allowed, or if the proxy server does
+	// not match the allowed ones.
+	ErrProxyServerNotAllowed = errors.New("proxy server not allowed")
+
+	// ErrClientCertificateNotFound happens if [Options.ClientCertificateName]
+	// does not match a mounted client certificate, or if there are no
+	// mounted client certificates.
+	ErrClientCertificateNotFound = errors.New("client certificate not found")
+
+	// ErrUnsupportedHttpAuthScheme happens if the main page, requested with
+	// a client certificate and [Options.HttpAuth], asks for the credentials
+	// with another scheme than Basic.
+	ErrUnsupportedHttpAuthScheme = errors.New("unsupported HTTP authentication scheme")
+
+	// ErrNetworkOffline happens if the main page requires the network while
+	// [Options.NetworkConditions] emulates an offline network.
+	ErrNetworkOffline = errors.New("network offline")
+
+	// ErrNavigationTimeout happens if the main page does not reach its load
+	// event within the navigation timeout.
+	ErrNavigationTimeout = errors.New("navigation timeout")
+
+	// ErrNetworkIdleTimeout happens if the main page, once loaded, does not
+	// reach the "networkIdle" event within the network idle timeout.
+	ErrNetworkIdleTimeout = errors.New("network idle timeout")
+
+	// ErrTimeoutAboveMaximum happens if [Options.NavigationTimeout] or
+	// [Options.NetworkIdleTimeout] exceeds the maximum set by the operator.
+	ErrTimeoutAboveMaximum = errors.New("timeout above maximum")
+
 	// PDF specific.
 
 	// ErrOmitBackgroundWithoutPrintBackground happens if
@@ -55,8 +140,20 @@ var (
 	// aberrant values.
 	ErrInvalidPrinterSettings = errors.New("invalid printer settings")
 
+	// ErrAvifEncoderNotAvailable happens if an AVIF screenshot is requested
+	// while the AVIFENC_BIN_PATH environment variable is not set.
+	ErrAvifEncoderNotAvailable = errors.New("AVIF encoder not available")
+
+	// ErrScreenshotHeightExceeded happens if the page of a full-page
+	// screenshot is taller than the maximum screenshot height.
+	ErrScreenshotHeightExceeded = errors.New("screenshot height exceeded")
+
+	// ErrFullPageWebpTooTall happens if a full-page WebP screenshot requires
+	// stitching many captures, as WebP images cannot be encoded afterward.
+	ErrFullPageWebpTooTall = errors.New("full-page WebP screenshot too tall")
+
 	// ErrPageRangesSyntaxError happens if the PdfOptions have an invalid page
-	// ranges.
+	// ranges, or ranges which exceed the page count.
 	ErrPageRangesSyntaxError = errors.New("page ranges syntax error")
 )
 
@@ -70,6 +167,7 @@ type Chromium struct {
 	logger     *zap.Logger
 	browser    browser
 	supervisor gotenberg.ProcessSupervisor
+	usage      *gotenberg.UsageRecorder
 	engine     gotenberg.PdfEngine
 }
 
@@ -82,11 +180,34 @@ type Options struct {
 	// Optional.
 	SkipNetworkIdleEvent bool
 
+	// NavigationTimeout is the maximum duration for the main page to reach
+	// its load event. It cannot exceed the navigation timeout of the
+	// instance, which applies if 0.
+	// Optional.
+	NavigationTimeout time.Duration
+
+	// NetworkIdleTimeout is the maximum duration to wait for the
+	// "networkIdle" event once the main page has loaded. It cannot exceed the
+	// network idle timeout of the instance, which applies if 0.
+	// Optional.
+	NetworkIdleTimeout time.Duration
+
 	// FailOnHttpStatusCodes sets if the conversion should fail if the status
 	// code from the main page matches with one of its entries.
 	// Optional.
 	FailOnHttpStatusCodes []int64
 
+	// FailOnResourceHttpStatusCodes sets if the conversion should fail if the
+	// status code from at least one resource (e.g., an image, a stylesheet)
+	// matches with one of its entries.
+	// Optional.
+	FailOnResourceHttpStatusCodes []int64
+
+	// FailOnResourceLoadingFailed sets if the conversion should fail if at
+	// least one resource fails to load, e.g., an aborted request.
+	// Optional.
+	FailOnResourceLoadingFailed bool
+
 	// FailOnConsoleExceptions sets if the conversion should fail if there are
 	// exceptions in the Chromium console.
 	// Optional.
@@ -107,34 +228,241 @@ type Options struct {
 	// Optional.
 	WaitForExpression string
 
+	// WaitForSelector is the CSS selector of the element to wait for before
+	// converting an HTML document.
+	// Optional.
+	WaitForSelector string
+
+	// WaitForFonts sets if the conversion should wait for the fonts of the
+	// page to be loaded, i.e., for document.fonts.ready, up to a timeout set
+	// by the operator.
+	// Optional.
+	WaitForFonts bool
+
 	// ExtraHttpHeaders are the HTTP headers to send by Chromium while loading
 	// the HTML document.
 	// Optional.
 	ExtraHttpHeaders map[string]string
 
+	// ScopedExtraHttpHeaders are the HTTP headers to send by Chromium only
+	// with the requests which URL matches their scope, e.g., an
+	// "Authorization" header for the target origin but not for the
+	// third-party assets.
+	// Optional.
+	ScopedExtraHttpHeaders []ScopedHttpHeader
+
 	// EmulatedMediaType is the media type to emulate, either "screen" or
 	// "print".
 	// Optional.
 	EmulatedMediaType string
 
+	// ColorScheme is the value of the "prefers-color-scheme" media feature
+	// to emulate, either "light" or "dark".
+	// Optional.
+	ColorScheme string
+
+	// ExtraCss are the stylesheets to inject, in order, once the page has
+	// loaded. They apply on top of the page's own styles, regardless of its
+	// Content Security Policy.
+	// Optional.
+	ExtraCss []string
+
 	// OmitBackground hides default white background and allows generating PDFs
 	// with transparency.
 	// Optional.
 	OmitBackground bool
+
+	// Timezone is the IANA timezone to emulate, e.g., "Europe/Paris". It
+	// affects the JavaScript Date and Intl APIs.
+	// Optional.
+	Timezone string
+
+	// Locale is the BCP 47 language tag to emulate, e.g., "fr-FR". It affects
+	// the JavaScript Intl API, navigator.language, and the Accept-Language
+	// HTTP header.
+	// Optional.
+	Locale string
+
+	// NavigationInfo, if not nil, receives the final URL and the HTTP status
+	// of the main page once the conversion succeeds. It does not affect the
+	// output.
+	// Optional.
+	NavigationInfo *NavigationInfo
+
+	// Session is the name of a session to reuse, if any. The conversion
+	// starts with the cookies and the local storage of the session, and
+	// saves them back once it succeeds.
+	// Optional.
+	Session string
+
+	// NetworkConditions, if not nil, are the network conditions to emulate
+	// while loading the page, e.g., to reproduce a timing-dependent layout.
+	// Optional.
+	NetworkConditions *NetworkConditions
+
+	// Cookies are the cookies to set before loading the page, e.g., the
+	// session cookie of an authenticated page. The conversion runs in its
+	// own browser context, so that they never leak into other requests.
+	// Optional.
+	Cookies []Cookie
+
+	// HttpAuth, if not nil, are the credentials to answer the HTTP
+	// authentication challenges, e.g., Basic, of the origin of the main
+	// page.
+	// Optional.
+	HttpAuth *HttpAuth
+
+	// Proxy, if not nil, is the outbound proxy server of the request,
+	// instead of the one of Chromium. The conversion runs in its own browser
+	// context.
+	// Optional.
+	Proxy *Proxy
+
+	// ClientCertificate, if not nil, is the TLS client certificate to
+	// present to the origin of the main page, e.g., an internal URL
+	// protected by mutual TLS.
+	// Optional.
+	ClientCertificate *tls.Certificate
+
+	// ClientCertificateName is the name of a mounted TLS client certificate
+	// to present to the origin of the main page, instead of
+	// [Options.ClientCertificate].
+	// Optional.
+	ClientCertificateName string
+}
+
+// Proxy gathers the settings of an outbound proxy server.
+type Proxy struct {
+	// Server is the proxy server, e.g., "http://proxy.example.com:3128" or
+	// "socks5://proxy.example.com:1080".
+	Server string
+
+	// BypassList is the semicolon-separated list of the hosts which do not
+	// go through the proxy server, e.g., "localhost;*.internal".
+	BypassList string
+
+	// Auth, if not nil, are the credentials to answer the authentication
+	// challenges of the proxy server.
+	Auth *HttpAuth
+}
+
+// HttpAuth gathers the credentials of an HTTP authentication.
+type HttpAuth struct {
+	// Username is the username of the credentials.
+	Username string
+
+	// Password is the password of the credentials.
+	Password string
+}
+
+// ScopedHttpHeader is an HTTP header to send only with the requests which
+// URL matches its scope.
+type ScopedHttpHeader struct {
+	// Name is the name of the header.
+	Name string
+
+	// Value is the value of the header.
+	Value string
+
+	// Scope is the regular expression the URL of a request has to match.
+	Scope *regexp.Regexp
+}
+
+// Cookie is a cookie to set before loading the page.
+type Cookie struct {
+	// Name is the name of the cookie.
+	Name string `json:"name"`
+
+	// Value is the value of the cookie.
+	Value string `json:"value"`
+
+	// Domain is the domain of the cookie, e.g., "example.com".
+	Domain string `json:"domain"`
+
+	// Path is the path of the cookie. Empty equals "/".
+	Path string `json:"path"`
+
+	// Secure restricts the cookie to the HTTPS requests.
+	Secure bool `json:"secure"`
+
+	// HttpOnly hides the cookie from the scripts of the page.
+	HttpOnly bool `json:"httpOnly"`
+
+	// SameSite is either "Strict", "Lax" or "None". Empty lets Chromium
+	// decide.
+	SameSite string `json:"sameSite"`
+}
+
+// NetworkConditions gathers the network conditions to emulate.
+type NetworkConditions struct {
+	// Offline emulates a network disconnection.
+	Offline bool `json:"offline"`
+
+	// Latency is the minimum latency of the requests, in milliseconds.
+	Latency float64 `json:"latency"`
+
+	// DownloadThroughput is the maximum download throughput, in bytes per
+	// second. 0 means no throttling.
+	DownloadThroughput float64 `json:"downloadThroughput"`
+
+	// UploadThroughput is the maximum upload throughput, in bytes per
+	// second. 0 means no throttling.
+	UploadThroughput float64 `json:"uploadThroughput"`
+}
+
+// NavigationInfo gathers metadata about the navigation to the main page.
+type NavigationInfo struct {
+	// FinalUrl is the URL of the main page after the redirections.
+	FinalUrl string `json:"finalUrl"`
+
+	// HttpStatus is the HTTP status code of the main page.
+	HttpStatus int64 `json:"httpStatus"`
+
+	// RedirectChain lists the redirections which led to the final URL, in
+	// order.
+	RedirectChain []NavigationRedirect `json:"redirectChain"`
+}
+
+// NavigationRedirect is a redirection of the main page.
+type NavigationRedirect struct {
+	// Url is the redirected URL.
+	Url string `json:"url"`
+
+	// HttpStatus is the HTTP status code of the redirection, e.g., 301.
+	HttpStatus int64 `json:"httpStatus"`
 }
 
 // DefaultOptions returns the default values for Options.
 func DefaultOptions() Options {
 	return Options{
-		SkipNetworkIdleEvent:    false,
-		FailOnHttpStatusCodes:   []int64{499, 599},
-		FailOnConsoleExceptions: false,
-		WaitDelay:               0,
-		WaitWindowStatus:        "",
-		WaitForExpression:       "",
-		ExtraHttpHeaders:        nil,
-		EmulatedMediaType:       "",
-		OmitBackground:          false,
+		SkipNetworkIdleEvent:          false,
+		NavigationTimeout:             0,
+		NetworkIdleTimeout:            0,
+		FailOnHttpStatusCodes:         []int64{499, 599},
+		FailOnResourceHttpStatusCodes: nil,
+		FailOnResourceLoadingFailed:   false,
+		FailOnConsoleExceptions:       false,
+		WaitDelay:                     0,
+		WaitWindowStatus:              "",
+		WaitForExpression:             "",
+		WaitForSelector:               "",
+		WaitForFonts:                  true,
+		ExtraHttpHeaders:              nil,
+		ScopedExtraHttpHeaders:        nil,
+		EmulatedMediaType:             "",
+		ColorScheme:                   "",
+		ExtraCss:                      nil,
+		OmitBackground:                false,
+		Timezone:                      "",
+		Locale:                        "",
+		NavigationInfo:                nil,
+		Session:                       "",
+		NetworkConditions:             nil,
+		Cookies:                       nil,
+		HttpAuth:                      nil,
+		Proxy:                         nil,
+		ClientCertificate:             nil,
+		ClientCertificateName:         "",
 	}
 }
 
@@ -146,11 +474,14 @@ type PdfOptions struct {
 	// Optional.
 	Landscape bool
 
-	// PrintBackground prints the background graphics.
+	// PrintBackground prints the background graphics. Chromium does not
+	// print them by default.
 	// Optional.
 	PrintBackground bool
 
-	// Scale is the scale of the page rendering.
+	// Scale is the scale of the page rendering, between 0.1 and 2.0. It
+	// applies to the content, so that it also composes with the CSS page
+	// size if PreferCssPageSize is set.
 	// Optional.
 	Scale float64
 
@@ -200,8 +531,9 @@ type PdfOptions struct {
 	// Optional.
 	FooterTemplate string
 
-	// PreferCssPageSize defines whether to prefer page size as defined by CSS.
-	// If false, the content will be scaled to fit the paper size.
+	// PreferCssPageSize defines whether to prefer page size as defined by CSS,
+	// i.e., the @page rules, named pages included, over the paper size. If
+	// false, the content will be scaled to fit the paper size.
 	// Optional.
 	PreferCssPageSize bool
 }
@@ -231,12 +563,14 @@ func DefaultPdfOptions() PdfOptions {
 type ScreenshotOptions struct {
 	Options
 
-	// Format is the image compression format, either "png" or "jpeg" or
-	// "webp".
+	// Format is the image compression format, either "png", "jpeg", "webp"
+	// or "avif". As Chromium cannot capture AVIF images, an AVIF screenshot
+	// is a PNG screenshot transcoded afterward.
 	// Optional.
 	Format string
 
-	// Quality is the compression quality from range [0..100] (jpeg only).
+	// Quality is the compression quality from range [0..100] (lossy formats
+	// only, i.e., jpeg, webp and avif).
 	// Optional.
 	Quality int
 
@@ -244,6 +578,33 @@ type ScreenshotOptions struct {
 	// not for resulting size.
 	// Optional.
 	OptimizeForSpeed bool
+
+	// FullPage captures the entire scrollable page instead of the viewport.
+	// The page is first scrolled to its end, so that its lazy-loaded content
+	// loads. A page taller than the maximum capture size is captured in many
+	// parts stitched vertically, with its fixed and sticky elements rendered
+	// only once.
+	// Optional.
+	FullPage bool
+
+	// Selector is the CSS selector of the element to capture, instead of the
+	// viewport. The capture is the bounding box of the first matching
+	// element.
+	// Optional.
+	Selector string
+
+	// Clip is the area of the page to capture, instead of the viewport.
+	// Optional.
+	Clip *ScreenshotClip
+}
+
+// ScreenshotClip is an area of the page, in CSS pixels, relative to the top
+// left corner of the page.
+type ScreenshotClip struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
 }
 
 // DefaultScreenshotOptions returns the default values for ScreenshotOptions.
@@ -253,6 +614,9 @@ func DefaultScreenshotOptions() ScreenshotOptions {
 		Format:           "png",
 		Quality:          100,
 		OptimizeForSpeed: false,
+		FullPage:         false,
+		Selector:         "",
+		Clip:             nil,
 	}
 }
 
@@ -289,11 +653,21 @@ func (mod *Chromium) Descriptor() gotenberg.ModuleDescriptor {
 			fs.Bool("chromium-allow-file-access-from-files", false, "Allow file:// URIs to read other file:// URIs")
 			fs.String("chromium-host-resolver-rules", "", "Set custom mappings to the host resolver")
 			fs.String("chromium-proxy-server", "", "Set the outbound proxy server; this switch only affects HTTP and HTTPS requests")
+			fs.String("chromium-proxy-servers-allow-list", "", "Allow the requests to set their own outbound proxy server, if it matches this regular expression. Empty disables this feature - security sensitive")
+			fs.String("chromium-client-certificates-dir", "", "Set the directory of the TLS client certificates the requests may refer to by name, as <name>.crt and <name>.key PEM files")
+			fs.String("chromium-client-certificate-max-response-size", "16MB", "Set the maximum size of a response of the origin of the main page when a request presents a client certificate, e.g., 16MB")
 			fs.String("chromium-allow-list", "", "Set the allowed URLs for Chromium using a regular expression")
 			fs.String("chromium-deny-list", "^file:///[^tmp].*", "Set the denied URLs for Chromium using a regular expression")
 			fs.Bool("chromium-clear-cache", false, "Clear Chromium cache between each conversion")
 			fs.Bool("chromium-clear-cookies", false, "Clear Chromium cookies between each conversion")
 			fs.Bool("chromium-disable-javascript", false, "Disable JavaScript")
+			fs.Duration("chromium-wait-for-fonts-timeout", time.Duration(5)*time.Second, "Set the maximum duration to wait for the fonts to be loaded before a conversion proceeds anyway")
+			fs.Duration("chromium-navigation-timeout", 0, "Set the default and maximum duration for a page to reach its load event. Set to 0 to only rely on the API timeout")
+			fs.Duration("chromium-network-idle-timeout", 0, "Set the default and maximum duration to wait for the network to be idle once a page has loaded. Set to 0 to only rely on the API timeout")
+			fs.Int64("chromium-max-screenshot-height", 32768, "Set the maximum height, in pixels, of a full-page screenshot")
+			fs.Bool("chromium-allow-sessions", false, "Allow the requests to persist and reuse named sessions, i.e., the cookies and the local storage of Chromium - security sensitive")
+			fs.Duration("chromium-session-ttl", time.Duration(1)*time.Hour, "Set the duration after which an unused session expires. Set to 0 to disable this feature")
+			fs.String("chromium-sessions-dir", "", "Set the directory where the sessions are stored - a temporary directory by default")
 			fs.Bool("chromium-disable-routes", false, "Disable the routes")
 
 			return fs
@@ -313,6 +687,24 @@ func (mod *Chromium) Provision(ctx *gotenberg.Context) error {
 		return errors.New("CHROMIUM_BIN_PATH environment variable is not set")
 	}
 
+	// Optional: without it, AVIF screenshots are not available.
+	avifencBinPath := os.Getenv("AVIFENC_BIN_PATH")
+
+	var sessions *sessionStore
+	if flags.MustBool("chromium-allow-sessions") {
+		sessionsDirPath := flags.MustString("chromium-sessions-dir")
+		if sessionsDirPath == "" {
+			sessionsDirPath = gotenberg.NewFileSystem().NewDirPath()
+		}
+
+		sessions = newSessionStore(sessionsDirPath, flags.MustDuration("chromium-session-ttl"))
+	}
+
+	clientCertificateMaxResponseSize, err := bytes.Parse(flags.MustHumanReadableBytesString("chromium-client-certificate-max-response-size"))
+	if err != nil {
+		return fmt.Errorf("parse client certificate max response size: %w", err)
+	}
+
 	mod.args = browserArguments{
 		binPath:                  binPath,
 		incognito:                flags.MustBool("chromium-incognito"),
@@ -324,11 +716,20 @@ func (mod *Chromium) Provision(ctx *gotenberg.Context) error {
 		proxyServer:              flags.MustString("chromium-proxy-server"),
 		wsUrlReadTimeout:         flags.MustDuration("chromium-start-timeout"),
 
-		allowList:         flags.MustRegexp("chromium-allow-list"),
-		denyList:          flags.MustRegexp("chromium-deny-list"),
-		clearCache:        flags.MustBool("chromium-clear-cache"),
-		clearCookies:      flags.MustBool("chromium-clear-cookies"),
-		disableJavaScript: flags.MustBool("chromium-disable-javascript"),
+		proxyServersAllowList:            flags.MustRegexp("chromium-proxy-servers-allow-list"),
+		clientCertificatesDirPath:        flags.MustString("chromium-client-certificates-dir"),
+		clientCertificateMaxResponseSize: clientCertificateMaxResponseSize,
+		allowList:                        flags.MustRegexp("chromium-allow-list"),
+		denyList:                         flags.MustRegexp("chromium-deny-list"),
+		clearCache:                       flags.MustBool("chromium-clear-cache"),
+		clearCookies:                     flags.MustBool("chromium-clear-cookies"),
+		disableJavaScript:                flags.MustBool("chromium-disable-javascript"),
+		waitForFontsTimeout:              flags.MustDuration("chromium-wait-for-fonts-timeout"),
+		navigationTimeout:                flags.MustDuration("chromium-navigation-timeout"),
+		networkIdleTimeout:               flags.MustDuration("chromium-network-idle-timeout"),
+		maxScreenshotHeight:              flags.MustInt64("chromium-max-screenshot-height"),
+		avifencBinPath:                   avifencBinPath,
+		sessions:                         sessions,
 	}
 
 	// Logger.
@@ -345,6 +746,7 @@ func (mod *Chromium) Provision(ctx *gotenberg.Context) error {
 	// Process.
 	mod.browser = newChromiumBrowser(mod.args)
 	mod.supervisor = gotenberg.NewProcessSupervisor(mod.logger, mod.browser, flags.MustInt64("chromium-restart-after"))
+	mod.usage = gotenberg.NewUsageRecorder()
 
 	// PDF Engine.
 	provider, err := ctx.Module(new(gotenberg.PdfEngineProvider))
@@ -367,12 +769,40 @@ func (mod *Chromium) Validate() error {
 		return fmt.Errorf("chromium binary path does not exist: %w", err)
 	}
 
+	if mod.args.sessions != nil && mod.args.sessions.ttl < 0 {
+		return errors.New("chromium session TTL must be positive")
+	}
+
+	if mod.args.navigationTimeout < 0 || mod.args.networkIdleTimeout < 0 {
+		return errors.New("chromium navigation and network idle timeouts must be positive")
+	}
+
+	if mod.args.maxScreenshotHeight < 1 {
+		return errors.New("chromium max screenshot height must be more than 0")
+	}
+
+	if mod.args.clientCertificateMaxResponseSize < 1 {
+		return errors.New("chromium client certificate max response size must be more than 0")
+	}
+
+	if mod.args.avifencBinPath != "" {
+		_, err = os.Stat(mod.args.avifencBinPath)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("avifenc binary path does not exist: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// Start does nothing if auto-start is not enabled. Otherwise, it starts a
-// browser instance.
+// Start removes the expired sessions periodically, if any. Then, it does
+// nothing if auto-start is not enabled. Otherwise, it starts a browser
+// instance.
 func (mod *Chromium) Start() error {
+	if mod.args.sessions != nil {
+		mod.args.sessions.start(mod.logger)
+	}
+
 	if !mod.autoStart {
 		return nil
 	}
@@ -402,6 +832,10 @@ func (mod *Chromium) Stop(ctx context.Context) error {
 
 	<-ctx.Done()
 
+	if mod.args.sessions != nil {
+		mod.args.sessions.stop()
+	}
+
 	err := mod.supervisor.Shutdown()
 	if err == nil {
 		return nil
@@ -427,6 +861,18 @@ func (mod *Chromium) Metrics() ([]gotenberg.Metric, error) {
 				return float64(mod.supervisor.RestartsCount())
 			},
 		},
+		{
+			Name:        "chromium_cpu_seconds",
+			Description: "Cumulative CPU time, in seconds, of the Chromium processes during the conversions, by route.",
+			Label:       "route",
+			ReadByLabel: mod.usage.CpuSeconds,
+		},
+		{
+			Name:        "chromium_peak_memory_bytes",
+			Description: "Peak resident memory, in bytes, of the Chromium processes during the last conversion, by route.",
+			Label:       "route",
+			ReadByLabel: mod.usage.PeakRss,
+		},
 	}, nil
 }
 
@@ -486,13 +932,42 @@ func (mod *Chromium) Routes() ([]api.Route, error) {
 		return nil, nil
 	}
 
-	return []api.Route{
+	routes := []api.Route{
 		convertUrlRoute(mod, mod.engine),
 		screenshotUrlRoute(mod),
 		convertHtmlRoute(mod, mod.engine),
 		screenshotHtmlRoute(mod),
 		convertMarkdownRoute(mod, mod.engine),
 		screenshotMarkdownRoute(mod),
+	}
+
+	if mod.args.sessions != nil {
+		routes = append(routes, deleteSessionRoute(mod.args.sessions))
+	}
+
+	return routes, nil
+}
+
+// Capabilities returns the input extensions, i.e., the documents, the
+// archives and the fonts, and the screenshot formats of the routes. As the
+// supervisor runs one conversion at a time, the concurrency is always 1.
+func (mod *Chromium) Capabilities() (map[string]interface{}, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	screenshotFormats := []string{"png", "jpeg", "webp"}
+	if mod.args.avifencBinPath != "" {
+		screenshotFormats = append(screenshotFormats, "avif")
+	}
+
+	extensions := append([]string{".html", ".md"}, htmlArchiveExtensions...)
+	extensions = append(extensions, api.FontExtensions...)
+
+	return map[string]interface{}{
+		"extensions":        extensions,
+		"screenshotFormats": screenshotFormats,
+		"maxConcurrency":    1,
 	}, nil
 }
 
@@ -500,17 +975,29 @@ func (mod *Chromium) Routes() ([]api.Route, error) {
 func (mod *Chromium) Pdf(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
 	// Note: no error wrapping because it leaks on errors we want to display to
 	// the end user.
-	return mod.supervisor.Run(ctx, logger, func() error {
-		return mod.browser.pdf(ctx, logger, url, outputPath, options)
+	ctx, span := gotenberg.StartSpan(ctx, "chromium.pdf", "chromium", 1)
+	err := mod.supervisor.Run(ctx, logger, func() error {
+		return mod.usage.Measure(ctx, logger, mod.browser, func() error {
+			return mod.browser.pdf(ctx, logger, url, outputPath, options)
+		})
 	})
+	gotenberg.EndSpan(span, outputPath, err)
+
+	return err
 }
 
 func (mod *Chromium) Screenshot(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
 	// Note: no error wrapping because it leaks on errors we want to display to
 	// the end user.
-	return mod.supervisor.Run(ctx, logger, func() error {
-		return mod.browser.screenshot(ctx, logger, url, outputPath, options)
+	ctx, span := gotenberg.StartSpan(ctx, "chromium.screenshot", "chromium", 1)
+	err := mod.supervisor.Run(ctx, logger, func() error {
+		return mod.usage.Measure(ctx, logger, mod.browser, func() error {
+			return mod.browser.screenshot(ctx, logger, url, outputPath, options)
+		})
 	})
+	gotenberg.EndSpan(span, outputPath, err)
+
+	return err
 }
 
 // Interface guards.
@@ -522,6 +1009,7 @@ var (
 	_ gotenberg.MetricsProvider = (*Chromium)(nil)
 	_ api.HealthChecker         = (*Chromium)(nil)
 	_ api.Router                = (*Chromium)(nil)
+	_ api.CapabilitiesProvider  = (*Chromium)(nil)
 	_ Api                       = (*Chromium)(nil)
 	_ Provider                  = (*Chromium)(nil)
 )
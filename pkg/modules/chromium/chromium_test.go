@@ -177,9 +177,13 @@ func TestChromium_Provision(t *testing.T) {
 
 func TestChromium_Validate(t *testing.T) {
 	for _, tc := range []struct {
-		scenario    string
-		binPath     string
-		expectError bool
+		scenario            string
+		binPath             string
+		sessions            *sessionStore
+		navigationTimeout   time.Duration
+		maxScreenshotHeight int64
+		maxResponseSize     int64
+		expectError         bool
 	}{
 		{
 			scenario:    "empty bin path",
@@ -192,15 +196,56 @@ func TestChromium_Validate(t *testing.T) {
 			expectError: true,
 		},
 		{
-			scenario:    "validate success",
+			scenario:    "negative session TTL",
 			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
-			expectError: false,
+			sessions:    newSessionStore("/tmp", -time.Second),
+			expectError: true,
+		},
+		{
+			scenario:            "negative navigation timeout",
+			binPath:             os.Getenv("CHROMIUM_BIN_PATH"),
+			navigationTimeout:   -time.Second,
+			maxScreenshotHeight: 32768,
+			expectError:         true,
+		},
+		{
+			scenario:            "invalid max screenshot height",
+			binPath:             os.Getenv("CHROMIUM_BIN_PATH"),
+			maxScreenshotHeight: 0,
+			expectError:         true,
+		},
+		{
+			scenario:            "invalid client certificate max response size",
+			binPath:             os.Getenv("CHROMIUM_BIN_PATH"),
+			maxScreenshotHeight: 32768,
+			maxResponseSize:     0,
+			expectError:         true,
+		},
+		{
+			scenario:            "validate success",
+			binPath:             os.Getenv("CHROMIUM_BIN_PATH"),
+			maxScreenshotHeight: 32768,
+			maxResponseSize:     16 << 20,
+			expectError:         false,
+		},
+		{
+			scenario:            "validate success with sessions",
+			binPath:             os.Getenv("CHROMIUM_BIN_PATH"),
+			sessions:            newSessionStore("/tmp", time.Hour),
+			maxScreenshotHeight: 32768,
+			maxResponseSize:     16 << 20,
+			expectError:         false,
 		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			mod := new(Chromium)
 			mod.args = browserArguments{
-				binPath: tc.binPath,
+				binPath:             tc.binPath,
+				sessions:            tc.sessions,
+				navigationTimeout:   tc.navigationTimeout,
+				maxScreenshotHeight: tc.maxScreenshotHeight,
+
+				clientCertificateMaxResponseSize: tc.maxResponseSize,
 			}
 			err := mod.Validate()
 
@@ -328,14 +373,15 @@ func TestChromium_Metrics(t *testing.T) {
 			return 0
 		},
 	}
+	mod.usage = gotenberg.NewUsageRecorder()
 
 	metrics, err := mod.Metrics()
 	if err != nil {
 		t.Fatalf("expected no error but got: %v", err)
 	}
 
-	if len(metrics) != 2 {
-		t.Fatalf("expected %d metrics, but got %d", 2, len(metrics))
+	if len(metrics) != 4 {
+		t.Fatalf("expected %d metrics, but got %d", 4, len(metrics))
 	}
 
 	actual := metrics[0].Read()
@@ -347,6 +393,12 @@ func TestChromium_Metrics(t *testing.T) {
 	if actual != float64(0) {
 		t.Errorf("expected %f for chromium_restarts_count, but got %f", float64(0), actual)
 	}
+
+	for _, metric := range metrics[2:] {
+		if len(metric.ReadByLabel()) != 0 {
+			t.Errorf("expected no value for %s, but got %+v", metric.Name, metric.ReadByLabel())
+		}
+	}
 }
 
 func TestChromium_Checks(t *testing.T) {
@@ -458,12 +510,19 @@ func TestChromium_Routes(t *testing.T) {
 		scenario      string
 		expectRoutes  int
 		disableRoutes bool
+		sessions      *sessionStore
 	}{
 		{
 			scenario:      "routes not disabled",
 			expectRoutes:  6,
 			disableRoutes: false,
 		},
+		{
+			scenario:      "routes not disabled with sessions",
+			expectRoutes:  7,
+			disableRoutes: false,
+			sessions:      newSessionStore("/tmp", time.Hour),
+		},
 		{
 			scenario:      "routes disabled",
 			expectRoutes:  0,
@@ -473,6 +532,7 @@ func TestChromium_Routes(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			mod := new(Chromium)
 			mod.disableRoutes = tc.disableRoutes
+			mod.args.sessions = tc.sessions
 
 			routes, err := mod.Routes()
 			if err != nil {
@@ -486,6 +546,59 @@ func TestChromium_Routes(t *testing.T) {
 	}
 }
 
+func TestChromium_Capabilities(t *testing.T) {
+	for _, tc := range []struct {
+		scenario                string
+		disableRoutes           bool
+		avifencBinPath          string
+		expectScreenshotFormats []string
+	}{
+		{
+			scenario:                "routes not disabled",
+			disableRoutes:           false,
+			expectScreenshotFormats: []string{"png", "jpeg", "webp"},
+		},
+		{
+			scenario:                "routes not disabled with avifenc",
+			disableRoutes:           false,
+			avifencBinPath:          "/usr/bin/avifenc",
+			expectScreenshotFormats: []string{"png", "jpeg", "webp", "avif"},
+		},
+		{
+			scenario:                "routes disabled",
+			disableRoutes:           true,
+			expectScreenshotFormats: nil,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Chromium)
+			mod.disableRoutes = tc.disableRoutes
+			mod.args.avifencBinPath = tc.avifencBinPath
+
+			capabilities, err := mod.Capabilities()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectScreenshotFormats == nil {
+				if capabilities != nil {
+					t.Errorf("expected no capabilities but got: %+v", capabilities)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(capabilities["screenshotFormats"], tc.expectScreenshotFormats) {
+				t.Errorf("expected screenshot formats %+v but got: %+v", tc.expectScreenshotFormats, capabilities["screenshotFormats"])
+			}
+
+			expectExtensions := []string{".html", ".md", ".zip", ".mhtml", ".mht", ".ttf", ".otf"}
+			if !reflect.DeepEqual(capabilities["extensions"], expectExtensions) {
+				t.Errorf("expected extensions %+v but got: %+v", expectExtensions, capabilities["extensions"])
+			}
+		})
+	}
+}
+
 func TestChromium_Pdf(t *testing.T) {
 	for _, tc := range []struct {
 		scenario    string
@@ -0,0 +1,528 @@
+package chromium
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+)
+
+// clientTlsFetcher sends the requests to the origin of the main page on
+// behalf of Chromium, presenting a TLS client certificate, as the DevTools
+// protocol does not allow Chromium to present one itself. The other origins
+// never see the certificate.
+type clientTlsFetcher struct {
+	origin          string
+	client          *http.Client
+	httpAuth        *HttpAuth
+	maxResponseSize int64
+
+	// failure is the error, if any, the conversion returns once done,
+	// e.g., an authentication scheme the fetcher does not support.
+	failure   error
+	failureMu sync.RWMutex
+}
+
+// clientTlsOptions gathers the settings of Chromium a [clientTlsFetcher]
+// follows, so that its requests go where those of Chromium would.
+type clientTlsOptions struct {
+	ignoreCertificateErrors bool
+
+	// proxy is the effective proxy server, i.e., the one of the request or
+	// else the one of the operator, if any.
+	proxy *Proxy
+
+	// hostResolverRules are the rules of the --host-resolver-rules switch
+	// of Chromium.
+	hostResolverRules string
+
+	// httpAuth, if not nil, are the credentials to answer the Basic
+	// authentication challenges of the origin.
+	httpAuth *HttpAuth
+
+	// maxResponseSize is the maximum size of a response body. As the body
+	// goes to Chromium base64-encoded in a single DevTools message, it
+	// should stay well below the maximum size of those messages.
+	maxResponseSize int64
+}
+
+// newClientTlsFetcher returns a [clientTlsFetcher] for the origin of the
+// given URL.
+func newClientTlsFetcher(url string, certificate tls.Certificate, options clientTlsOptions) (*clientTlsFetcher, error) {
+	origin, err := urlOrigin(url)
+	if err != nil {
+		return nil, fmt.Errorf("origin of '%s': %w", url, err)
+	}
+
+	rules, err := parseHostResolverRules(options.hostResolverRules)
+	if err != nil {
+		return nil, fmt.Errorf("parse host resolver rules: %w", err)
+	}
+
+	proxyUrl, err := clientTlsProxyUrl(options.proxy)
+	if err != nil {
+		return nil, fmt.Errorf("parse proxy server: %w", err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Like Chromium, the fetcher ignores the certificate errors of the
+	// server if the operator says so.
+	transport.TLSClientConfig = &tls.Config{
+		Certificates:       []tls.Certificate{certificate},
+		InsecureSkipVerify: options.ignoreCertificateErrors,
+	}
+
+	// Unlike the default transport, the fetcher never reads the proxy
+	// server from the environment, as Chromium does not.
+	transport.Proxy = nil
+	if proxyUrl != nil {
+		bypassList := options.proxy.BypassList
+		transport.Proxy = func(req *http.Request) (*neturl.URL, error) {
+			if bypassesProxy(bypassList, req.URL.Hostname()) {
+				return nil, nil
+			}
+
+			return proxyUrl, nil
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		mapped, err := rules.rewrite(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		return dialer.DialContext(ctx, network, mapped)
+	}
+
+	return &clientTlsFetcher{
+		origin: origin,
+		client: &http.Client{
+			Transport: transport,
+			// Chromium follows the redirections itself, so that each of
+			// them goes through the allowed and denied lists.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		httpAuth:        options.httpAuth,
+		maxResponseSize: options.maxResponseSize,
+	}, nil
+}
+
+// matches tells if a request is for the origin of the main page.
+func (f *clientTlsFetcher) matches(url string) bool {
+	origin, err := urlOrigin(url)
+	return err == nil && origin == f.origin
+}
+
+// fetchedResponse is a response of a request the [clientTlsFetcher] sent.
+type fetchedResponse struct {
+	statusCode int
+	headers    []*fetch.HeaderEntry
+	body       []byte
+}
+
+// do sends a request. The response body is decompressed, so that its
+// headers do not tell any content encoding.
+func (f *clientTlsFetcher) do(ctx context.Context, method, url string, headers map[string]string, body []byte) (fetchedResponse, error) {
+	newRequest := func() (*http.Request, error) {
+		var reqBody io.Reader = http.NoBody
+		if len(body) > 0 {
+			reqBody = bytes.NewReader(body)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+
+		for name, value := range headers {
+			// The HTTP client negotiates the compression, so that it
+			// decompresses the response body.
+			if strings.EqualFold(name, "Accept-Encoding") {
+				continue
+			}
+
+			req.Header.Set(name, value)
+		}
+
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return fetchedResponse{}, err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fetchedResponse{}, fmt.Errorf("send request: %w", err)
+	}
+
+	// As the fetcher fulfills the requests, Chromium never sees the
+	// authentication challenges of the origin: the fetcher answers them
+	// instead, once.
+	if f.answersChallenge(req, resp) {
+		_ = resp.Body.Close()
+
+		req, err = newRequest()
+		if err != nil {
+			return fetchedResponse{}, err
+		}
+
+		req.SetBasicAuth(f.httpAuth.Username, f.httpAuth.Password)
+
+		resp, err = f.client.Do(req)
+		if err != nil {
+			return fetchedResponse{}, fmt.Errorf("send request with credentials: %w", err)
+		}
+	} else if f.rejectsChallenge(req, resp) {
+		_ = resp.Body.Close()
+
+		return fetchedResponse{}, fmt.Errorf("'%s' asks for '%s': %w", url, resp.Header.Get("WWW-Authenticate"), ErrUnsupportedHttpAuthScheme)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, f.maxResponseSize+1))
+	if err != nil {
+		return fetchedResponse{}, fmt.Errorf("read response body: %w", err)
+	}
+
+	if int64(len(respBody)) > f.maxResponseSize {
+		return fetchedResponse{}, fmt.Errorf("response body larger than %d bytes", f.maxResponseSize)
+	}
+
+	var entries []*fetch.HeaderEntry
+	for name, values := range resp.Header {
+		for _, value := range values {
+			entries = append(entries, &fetch.HeaderEntry{Name: name, Value: value})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return fetchedResponse{
+		statusCode: resp.StatusCode,
+		headers:    entries,
+		body:       respBody,
+	}, nil
+}
+
+// answersChallenge tells if the fetcher answers the Basic authentication
+// challenge of a response with the credentials, if any. The other schemes,
+// e.g., Digest, are not supported.
+func (f *clientTlsFetcher) answersChallenge(req *http.Request, resp *http.Response) bool {
+	if !f.challenged(req, resp) {
+		return false
+	}
+
+	for _, challenge := range resp.Header.Values("WWW-Authenticate") {
+		if len(challenge) >= 5 && strings.EqualFold(challenge[:5], "basic") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rejectsChallenge tells if a response asks for the credentials with
+// another scheme than Basic only, which the fetcher cannot answer while
+// Chromium never sees the challenge.
+func (f *clientTlsFetcher) rejectsChallenge(req *http.Request, resp *http.Response) bool {
+	return f.challenged(req, resp) && resp.Header.Get("WWW-Authenticate") != "" && !f.answersChallenge(req, resp)
+}
+
+// challenged tells if a response is an authentication challenge the
+// credentials, if any, have to answer.
+func (f *clientTlsFetcher) challenged(req *http.Request, resp *http.Response) bool {
+	return f.httpAuth != nil && resp.StatusCode == http.StatusUnauthorized && req.Header.Get("Authorization") == ""
+}
+
+// err returns the error, if any, the conversion has to return.
+func (f *clientTlsFetcher) err() error {
+	f.failureMu.RLock()
+	defer f.failureMu.RUnlock()
+
+	return f.failure
+}
+
+// fulfill sends a paused request and fulfills it with the response. The
+// headers, if not nil, replace those of the request, see requestHeaders.
+// As the cookies of Chromium are not part of the headers of a paused
+// request, they are added from the browser context.
+func (f *clientTlsFetcher) fulfill(ctx context.Context, logger *zap.Logger, e *fetch.EventRequestPaused, headers []*fetch.HeaderEntry) {
+	cctx := chromedp.FromContext(ctx)
+	executorCtx := cdp.WithExecutor(ctx, cctx.Target)
+
+	requestHeaders := make(map[string]string)
+	if headers != nil {
+		for _, header := range headers {
+			requestHeaders[header.Name] = header.Value
+		}
+	} else {
+		for name, value := range e.Request.Headers {
+			requestHeaders[name] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	cookies, err := network.GetCookies().WithUrls([]string{e.Request.URL}).Do(executorCtx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("get cookies for '%s': %s", e.Request.URL, err))
+	}
+
+	if len(cookies) > 0 {
+		pairs := make([]string, len(cookies))
+		for i, cookie := range cookies {
+			pairs[i] = fmt.Sprintf("%s=%s", cookie.Name, cookie.Value)
+		}
+
+		requestHeaders["Cookie"] = strings.Join(pairs, "; ")
+	}
+
+	var body []byte
+	for _, entry := range e.Request.PostDataEntries {
+		b, err := base64.StdEncoding.DecodeString(entry.Bytes)
+		if err != nil {
+			logger.Error(fmt.Sprintf("decode post data of '%s': %s", e.Request.URL, err))
+			continue
+		}
+
+		body = append(body, b...)
+	}
+
+	if body == nil && e.Request.PostData != "" {
+		body = []byte(e.Request.PostData)
+	}
+
+	logger.Debug(fmt.Sprintf("send '%s' with the client certificate", e.Request.URL))
+
+	resp, err := f.do(ctx, e.Request.Method, e.Request.URL, requestHeaders, body)
+	if err != nil {
+		logger.Error(fmt.Sprintf("send '%s' with the client certificate: %s", e.Request.URL, err))
+
+		if errors.Is(err, ErrUnsupportedHttpAuthScheme) {
+			f.failureMu.Lock()
+			if f.failure == nil {
+				f.failure = err
+			}
+			f.failureMu.Unlock()
+		}
+
+		err = fetch.FailRequest(e.RequestID, network.ErrorReasonConnectionFailed).Do(executorCtx)
+		if err != nil {
+			logger.Error(fmt.Sprintf("fail request: %s", err))
+		}
+
+		return
+	}
+
+	err = fetch.FulfillRequest(e.RequestID, int64(resp.statusCode)).
+		WithResponseHeaders(resp.headers).
+		WithResponsePhrase(http.StatusText(resp.statusCode)).
+		WithBody(base64.StdEncoding.EncodeToString(resp.body)).
+		Do(executorCtx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("fulfill request: %s", err))
+	}
+}
+
+// clientTlsProxyUrl returns the URL of a proxy server for the HTTP client of
+// a [clientTlsFetcher], or nil for a direct connection. Like Chromium, it
+// accepts a proxy server without scheme, i.e., an HTTP one, and the proxy
+// rules of the --proxy-server switch, in which case the first proxy server
+// for HTTPS is the one.
+func clientTlsProxyUrl(proxy *Proxy) (*neturl.URL, error) {
+	if proxy == nil || proxy.Server == "" {
+		return nil, nil
+	}
+
+	server := ""
+	for _, rule := range strings.Split(proxy.Server, ";") {
+		scheme, value, found := strings.Cut(strings.TrimSpace(rule), "=")
+		if !found {
+			value = scheme
+			scheme = ""
+		}
+
+		if scheme == "" && server == "" || scheme == "https" {
+			server = value
+		}
+	}
+
+	// The fallbacks, if any, are not supported.
+	server, _, _ = strings.Cut(server, ",")
+	server = strings.TrimSpace(server)
+
+	if server == "" || server == "direct://" {
+		return nil, nil
+	}
+
+	if !strings.Contains(server, "://") {
+		server = "http://" + server
+	}
+
+	u, err := neturl.Parse(server)
+	if err != nil {
+		return nil, fmt.Errorf("parse '%s': %w", server, err)
+	}
+
+	if !slices.Contains(clientTlsProxySchemes, u.Scheme) {
+		return nil, fmt.Errorf("'%s': unsupported scheme '%s'", server, u.Scheme)
+	}
+
+	if proxy.Auth != nil {
+		u.User = neturl.UserPassword(proxy.Auth.Username, proxy.Auth.Password)
+	}
+
+	return u, nil
+}
+
+// clientTlsProxySchemes are the schemes of the proxy servers a
+// [clientTlsFetcher] supports.
+var clientTlsProxySchemes = []string{"http", "https", "socks5"}
+
+// bypassesProxy tells if a host does not go through the proxy server,
+// according to a bypass list in the format of Chromium, e.g.,
+// "localhost;*.internal;10.0.0.0/8;<local>".
+func bypassesProxy(bypassList, host string) bool {
+	host = strings.ToLower(strings.Trim(host, "[]"))
+
+	for _, pattern := range strings.FieldsFunc(bypassList, func(r rune) bool { return r == ';' || r == ',' }) {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+
+		switch {
+		case pattern == "":
+			continue
+		case pattern == "<local>":
+			if !strings.Contains(host, ".") && !strings.Contains(host, ":") {
+				return true
+			}
+		case strings.Contains(pattern, "/"):
+			_, network, err := net.ParseCIDR(pattern)
+			ip := net.ParseIP(host)
+			if err == nil && ip != nil && network.Contains(ip) {
+				return true
+			}
+		case strings.HasPrefix(pattern, "."):
+			if strings.HasSuffix(host, pattern) {
+				return true
+			}
+		default:
+			matched, err := path.Match(pattern, host)
+			if err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hostResolverRule is a rule of the --host-resolver-rules switch of
+// Chromium, i.e., either "MAP <pattern> <replacement>" or "EXCLUDE
+// <pattern>".
+type hostResolverRule struct {
+	exclude     bool
+	pattern     string
+	replacement string
+}
+
+// hostResolverRules are the rules of the --host-resolver-rules switch of
+// Chromium.
+type hostResolverRules []hostResolverRule
+
+// parseHostResolverRules parses the comma-separated rules of the
+// --host-resolver-rules switch of Chromium.
+func parseHostResolverRules(value string) (hostResolverRules, error) {
+	var rules hostResolverRules
+
+	for _, rule := range strings.Split(value, ",") {
+		fields := strings.Fields(rule)
+
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) == 3 && strings.EqualFold(fields[0], "MAP"):
+			rules = append(rules, hostResolverRule{pattern: strings.ToLower(fields[1]), replacement: fields[2]})
+		case len(fields) == 2 && strings.EqualFold(fields[0], "EXCLUDE"):
+			rules = append(rules, hostResolverRule{exclude: true, pattern: strings.ToLower(fields[1])})
+		default:
+			return nil, fmt.Errorf("unsupported rule '%s'", strings.TrimSpace(rule))
+		}
+	}
+
+	return rules, nil
+}
+
+// rewrite returns the address to dial instead of the given one. Like
+// Chromium, an excluded host is never mapped, otherwise the first matching
+// rule maps it, keeping its port unless the replacement sets one.
+func (rules hostResolverRules) rewrite(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, nil
+	}
+
+	host = strings.ToLower(host)
+
+	matches := func(pattern string) bool {
+		for _, candidate := range []string{host, net.JoinHostPort(host, port)} {
+			matched, err := path.Match(pattern, candidate)
+			if err == nil && matched {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, rule := range rules {
+		if rule.exclude && matches(rule.pattern) {
+			return addr, nil
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.exclude || !matches(rule.pattern) {
+			continue
+		}
+
+		if rule.replacement == "~NOTFOUND" {
+			return "", fmt.Errorf("host '%s' not found, according to the host resolver rules", host)
+		}
+
+		_, _, err := net.SplitHostPort(rule.replacement)
+		if err == nil {
+			return rule.replacement, nil
+		}
+
+		return net.JoinHostPort(strings.Trim(rule.replacement, "[]"), port), nil
+	}
+
+	return addr, nil
+}
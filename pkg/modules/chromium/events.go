@@ -3,8 +3,10 @@ package chromium
 import (
 	"context"
 	"fmt"
+	neturl "net/url"
 	"regexp"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/chromedp/cdproto/cdp"
@@ -19,8 +21,10 @@ import (
 )
 
 // listenForEventRequestPaused listens for requests to check if they are
-// allowed or not.
-func listenForEventRequestPaused(ctx context.Context, logger *zap.Logger, allowList *regexp.Regexp, denyList *regexp.Regexp) {
+// allowed or not. It also adds the scoped extra HTTP headers to the requests
+// which URL matches their scope, and sends the requests to the origin of the
+// main page with the client certificate, if any.
+func listenForEventRequestPaused(ctx context.Context, logger *zap.Logger, allowList *regexp.Regexp, denyList *regexp.Regexp, extraHttpHeaders map[string]string, scopedExtraHttpHeaders []ScopedHttpHeader, clientTls *clientTlsFetcher) {
 	chromedp.ListenTarget(ctx, func(ev interface{}) {
 		switch e := ev.(type) {
 		case *fetch.EventRequestPaused:
@@ -42,7 +46,19 @@ func listenForEventRequestPaused(ctx context.Context, logger *zap.Logger, allowL
 				executorCtx := cdp.WithExecutor(ctx, cctx.Target)
 
 				if allow {
+					headers := requestHeaders(e.Request, extraHttpHeaders, scopedExtraHttpHeaders)
+
+					if clientTls != nil && clientTls.matches(e.Request.URL) {
+						clientTls.fulfill(ctx, logger, e, headers)
+						return
+					}
+
 					req := fetch.ContinueRequest(e.RequestID)
+					if headers != nil {
+						logger.Debug(fmt.Sprintf("scoped extra HTTP headers for '%s'", e.Request.URL))
+						req = req.WithHeaders(headers)
+					}
+
 					err := req.Do(executorCtx)
 					if err != nil {
 						logger.Error(fmt.Sprintf("continue request: %s", err))
@@ -61,17 +77,157 @@ func listenForEventRequestPaused(ctx context.Context, logger *zap.Logger, allowL
 	})
 }
 
+// listenForEventAuthRequired answers the HTTP authentication challenges of
+// the origin of the main page with its credentials, and those of the proxy
+// server with the proxy credentials, once per URL. The other challenges,
+// e.g., from a third-party asset or following wrong credentials, are
+// canceled, so that the credentials never leak to another origin and the
+// response is a 401 (or a 407).
+func listenForEventAuthRequired(ctx context.Context, logger *zap.Logger, url string, httpAuth *HttpAuth, proxyAuth *HttpAuth) {
+	origin, err := urlOrigin(url)
+	if err != nil {
+		logger.Error(fmt.Sprintf("origin of '%s': %s", url, err))
+	}
+
+	var (
+		answered   = make(map[string]bool)
+		answeredMu sync.Mutex
+	)
+
+	answerOnce := func(key string) bool {
+		answeredMu.Lock()
+		defer answeredMu.Unlock()
+
+		if answered[key] {
+			return false
+		}
+
+		answered[key] = true
+
+		return true
+	}
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch e := ev.(type) {
+		case *fetch.EventAuthRequired:
+			go func() {
+				var credentials *HttpAuth
+
+				switch {
+				case e.AuthChallenge.Source == fetch.AuthChallengeSourceProxy:
+					if proxyAuth != nil && answerOnce("proxy "+e.Request.URL) {
+						credentials = proxyAuth
+					}
+				case httpAuth != nil && origin != "" && e.AuthChallenge.Origin == origin:
+					if answerOnce(e.Request.URL) {
+						credentials = httpAuth
+					}
+				}
+
+				response := &fetch.AuthChallengeResponse{
+					Response: fetch.AuthChallengeResponseResponseCancelAuth,
+				}
+
+				if credentials != nil {
+					response = &fetch.AuthChallengeResponse{
+						Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+						Username: credentials.Username,
+						Password: credentials.Password,
+					}
+				}
+
+				logger.Debug(fmt.Sprintf("event EventAuthRequired fired for '%s' (%s %s): %s", e.Request.URL, e.AuthChallenge.Source, e.AuthChallenge.Scheme, response.Response))
+
+				cctx := chromedp.FromContext(ctx)
+				executorCtx := cdp.WithExecutor(ctx, cctx.Target)
+
+				err := fetch.ContinueWithAuth(e.RequestID, response).Do(executorCtx)
+				if err != nil {
+					logger.Error(fmt.Sprintf("continue with auth: %s", err))
+				}
+			}()
+		}
+	})
+}
+
+// urlOrigin returns the origin of a URL, as Chromium reports it, i.e.,
+// without the default port of its scheme.
+func urlOrigin(rawUrl string) (string, error) {
+	u, err := neturl.Parse(rawUrl)
+	if err != nil {
+		return "", fmt.Errorf("parse URL: %w", err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("'%s' is not an absolute URL", rawUrl)
+	}
+
+	host := u.Host
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		host = strings.TrimSuffix(u.Host, ":"+u.Port())
+	}
+
+	return fmt.Sprintf("%s://%s", u.Scheme, host), nil
+}
+
+// requestHeaders returns the headers of a request with the scoped extra HTTP
+// headers which match its URL, or nil if none does. As these headers replace
+// those of the request, they also hold the unscoped extra HTTP headers.
+func requestHeaders(request *network.Request, extraHttpHeaders map[string]string, scopedExtraHttpHeaders []ScopedHttpHeader) []*fetch.HeaderEntry {
+	var matches []ScopedHttpHeader
+	for _, header := range scopedExtraHttpHeaders {
+		if header.Scope.MatchString(request.URL) {
+			matches = append(matches, header)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil
+	}
+
+	headers := make(map[string]string, len(request.Headers)+len(extraHttpHeaders)+len(matches))
+	names := make(map[string]string)
+
+	set := func(name, value string) {
+		// The header names are case-insensitive.
+		key := strings.ToLower(name)
+		if previous, ok := names[key]; ok {
+			delete(headers, previous)
+		}
+
+		names[key] = name
+		headers[name] = value
+	}
+
+	for name, value := range request.Headers {
+		set(name, fmt.Sprintf("%v", value))
+	}
+
+	for name, value := range extraHttpHeaders {
+		set(name, value)
+	}
+
+	for _, header := range matches {
+		set(header.Name, header.Value)
+	}
+
+	entries := make([]*fetch.HeaderEntry, 0, len(headers))
+	for name, value := range headers {
+		entries = append(entries, &fetch.HeaderEntry{Name: name, Value: value})
+	}
+
+	slices.SortFunc(entries, func(a, b *fetch.HeaderEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return entries
+}
+
 // listenForEventResponseReceived listens for an invalid HTTP status code is
 // returned by the main page.
 // See https://github.com/gotenberg/gotenberg/issues/613.
 func listenForEventResponseReceived(ctx context.Context, logger *zap.Logger, url string, failOnHttpStatusCodes []int64, invalidHttpStatusCode *error, invalidHttpStatusCodeMu *sync.RWMutex) {
-	for _, code := range []int64{199, 299, 399, 499, 599} {
-		if slices.Contains(failOnHttpStatusCodes, code) {
-			for i := code - 99; i <= code; i++ {
-				failOnHttpStatusCodes = append(failOnHttpStatusCodes, i)
-			}
-		}
-	}
+	failOnHttpStatusCodes = expandHttpStatusCodes(failOnHttpStatusCodes)
 
 	chromedp.ListenTarget(ctx, func(ev interface{}) {
 		switch ev := ev.(type) {
@@ -80,7 +236,7 @@ func listenForEventResponseReceived(ctx context.Context, logger *zap.Logger, url
 				return
 			}
 
-			logger.Debug(fmt.Sprintf("event EventResponseReceived fired for main page: %+v", ev.Response))
+			logger.Debug(fmt.Sprintf("event EventResponseReceived fired for main page: %s (%d)", ev.Response.URL, ev.Response.Status))
 
 			if slices.Contains(failOnHttpStatusCodes, ev.Response.Status) {
 				invalidHttpStatusCodeMu.Lock()
@@ -92,6 +248,128 @@ func listenForEventResponseReceived(ctx context.Context, logger *zap.Logger, url
 	})
 }
 
+// listenForEventNavigation listens for the redirections and the response of
+// the main page, i.e., the first document request, and fills the given
+// navigation info accordingly.
+func listenForEventNavigation(ctx context.Context, logger *zap.Logger, navigationInfo *NavigationInfo, navigationInfoMu *sync.RWMutex) {
+	var mainRequestId network.RequestID
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		navigationInfoMu.Lock()
+		defer navigationInfoMu.Unlock()
+
+		switch ev := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			if mainRequestId == "" && ev.Type == network.ResourceTypeDocument {
+				mainRequestId = ev.RequestID
+			}
+
+			if ev.RequestID != mainRequestId || ev.RedirectResponse == nil {
+				return
+			}
+
+			logger.Debug(fmt.Sprintf("event EventRequestWillBeSent fired for main page redirection: %s (%d)", ev.RedirectResponse.URL, ev.RedirectResponse.Status))
+
+			navigationInfo.RedirectChain = append(navigationInfo.RedirectChain, NavigationRedirect{
+				Url:        ev.RedirectResponse.URL,
+				HttpStatus: ev.RedirectResponse.Status,
+			})
+		case *network.EventResponseReceived:
+			if ev.RequestID != mainRequestId {
+				return
+			}
+
+			navigationInfo.FinalUrl = ev.Response.URL
+			navigationInfo.HttpStatus = ev.Response.Status
+		}
+	})
+}
+
+// listenForEventResponseReceivedForResources listens for invalid HTTP status
+// codes returned by the resources of the main page (e.g., images,
+// stylesheets) and appends those resources to the given error pointer.
+func listenForEventResponseReceivedForResources(ctx context.Context, logger *zap.Logger, url string, failOnResourceHttpStatusCodes []int64, invalidResourceHttpStatusCode *error, invalidResourceHttpStatusCodeMu *sync.RWMutex) {
+	failOnResourceHttpStatusCodes = expandHttpStatusCodes(failOnResourceHttpStatusCodes)
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *network.EventResponseReceived:
+			if ev.Response.URL == url {
+				return
+			}
+
+			if slices.Contains(failOnResourceHttpStatusCodes, ev.Response.Status) {
+				logger.Debug(fmt.Sprintf("event EventResponseReceived fired for resource with invalid HTTP status code: %s (%d)", ev.Response.URL, ev.Response.Status))
+
+				invalidResourceHttpStatusCodeMu.Lock()
+				defer invalidResourceHttpStatusCodeMu.Unlock()
+
+				*invalidResourceHttpStatusCode = multierr.Append(
+					*invalidResourceHttpStatusCode,
+					fmt.Errorf("'%s' - %d: %s", ev.Response.URL, ev.Response.Status, ev.Response.StatusText),
+				)
+			}
+		}
+	})
+}
+
+// listenForEventLoadingFailed listens for resources which fail to load (e.g.,
+// aborted requests) and appends those resources to the given error pointer.
+// Requests denied by the allowed / denied lists are not taken into account,
+// as they are expected to fail.
+func listenForEventLoadingFailed(ctx context.Context, logger *zap.Logger, url string, resourceLoadingFailed *error, resourceLoadingFailedMu *sync.RWMutex) {
+	// The event LoadingFailed does not contain the URL of the resource.
+	var urls sync.Map
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			urls.Store(ev.RequestID, ev.Request.URL)
+		case *network.EventLoadingFailed:
+			logger.Debug(fmt.Sprintf("event EventLoadingFailed fired: %+v", ev))
+
+			if ev.ErrorText == "net::ERR_ACCESS_DENIED" {
+				return
+			}
+
+			resourceUrl := "unknown URL"
+			value, ok := urls.Load(ev.RequestID)
+			if ok {
+				resourceUrl = value.(string)
+			}
+
+			if resourceUrl == url {
+				// Chromium already fails the navigation.
+				return
+			}
+
+			resourceLoadingFailedMu.Lock()
+			defer resourceLoadingFailedMu.Unlock()
+
+			*resourceLoadingFailed = multierr.Append(
+				*resourceLoadingFailed,
+				fmt.Errorf("'%s' - %s", resourceUrl, ev.ErrorText),
+			)
+		}
+	})
+}
+
+// expandHttpStatusCodes adds the status codes of the ranges, i.e., X99 status
+// codes, to the given status codes. For instance, 499 means from 400 to 499.
+func expandHttpStatusCodes(codes []int64) []int64 {
+	expanded := slices.Clone(codes)
+
+	for _, code := range []int64{199, 299, 399, 499, 599} {
+		if slices.Contains(codes, code) {
+			for i := code - 99; i <= code; i++ {
+				expanded = append(expanded, i)
+			}
+		}
+	}
+
+	return expanded
+}
+
 // listenForEventExceptionThrown listens for exceptions in the console and
 // appends those exceptions to the given error pointer.
 // See https://github.com/gotenberg/gotenberg/issues/262.
@@ -157,30 +435,22 @@ func waitForEventLoadEventFired(ctx context.Context, logger *zap.Logger) func()
 	}
 }
 
-// waitForEventNetworkIdle waits until the event networkIdle is fired or the
-// context timeout.
-func waitForEventNetworkIdle(ctx context.Context, logger *zap.Logger) func() error {
-	return func() error {
-		ch := make(chan struct{})
-		cctx, cancel := context.WithCancel(ctx)
-		chromedp.ListenTarget(cctx, func(ev interface{}) {
-			switch e := ev.(type) {
-			case *page.EventLifecycleEvent:
-				if e.Name == "networkIdle" {
-					cancel()
-					close(ch)
-				}
+// listenForEventNetworkIdle returns a channel which is closed once the event
+// networkIdle is fired. The listener stops with the context.
+func listenForEventNetworkIdle(ctx context.Context) <-chan struct{} {
+	ch := make(chan struct{})
+	cctx, cancel := context.WithCancel(ctx)
+	chromedp.ListenTarget(cctx, func(ev interface{}) {
+		switch e := ev.(type) {
+		case *page.EventLifecycleEvent:
+			if e.Name == "networkIdle" {
+				cancel()
+				close(ch)
 			}
-		})
-
-		select {
-		case <-ch:
-			logger.Debug("event networkIdle fired")
-			return nil
-		case <-ctx.Done():
-			return fmt.Errorf("wait for event networkIdle: %w", ctx.Err())
 		}
-	}
+	})
+
+	return ch
 }
 
 // waitForEventLoadingFinished waits until the event LoadingFinished is fired
@@ -0,0 +1,109 @@
+package chromium
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+)
+
+func TestUrlOrigin(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		url          string
+		expectOrigin string
+		expectError  bool
+	}{
+		{
+			scenario:     "default port",
+			url:          "https://example.com:443/app?foo=bar",
+			expectOrigin: "https://example.com",
+		},
+		{
+			scenario:     "custom port",
+			url:          "http://example.com:8080/app",
+			expectOrigin: "http://example.com:8080",
+		},
+		{
+			scenario:    "relative URL",
+			url:         "foo",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			origin, err := urlOrigin(tc.url)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if origin != tc.expectOrigin {
+				t.Errorf("expected origin '%s' but got '%s'", tc.expectOrigin, origin)
+			}
+		})
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	scopedExtraHttpHeaders := []ScopedHttpHeader{
+		{
+			Name:  "Authorization",
+			Value: "Bearer token",
+			Scope: regexp.MustCompile(`^https://api\.example\.com/`),
+		},
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		request                *network.Request
+		extraHttpHeaders       map[string]string
+		scopedExtraHttpHeaders []ScopedHttpHeader
+		expectHeaders          []*fetch.HeaderEntry
+	}{
+		{
+			scenario: "no scoped extra HTTP headers",
+			request: &network.Request{
+				URL:     "https://api.example.com/data",
+				Headers: network.Headers{"Accept": "*/*"},
+			},
+			expectHeaders: nil,
+		},
+		{
+			scenario: "URL out of scope",
+			request: &network.Request{
+				URL:     "https://cdn.example.com/style.css",
+				Headers: network.Headers{"Accept": "*/*"},
+			},
+			scopedExtraHttpHeaders: scopedExtraHttpHeaders,
+			expectHeaders:          nil,
+		},
+		{
+			scenario: "URL in scope",
+			request: &network.Request{
+				URL:     "https://api.example.com/data",
+				Headers: network.Headers{"Accept": "*/*", "authorization": "Basic foo"},
+			},
+			extraHttpHeaders:       map[string]string{"X-Foo": "bar"},
+			scopedExtraHttpHeaders: scopedExtraHttpHeaders,
+			expectHeaders: []*fetch.HeaderEntry{
+				{Name: "Accept", Value: "*/*"},
+				{Name: "Authorization", Value: "Bearer token"},
+				{Name: "X-Foo", Value: "bar"},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := requestHeaders(tc.request, tc.extraHttpHeaders, tc.scopedExtraHttpHeaders)
+
+			if !reflect.DeepEqual(actual, tc.expectHeaders) {
+				t.Errorf("expected %+v but got: %+v", tc.expectHeaders, actual)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package chromium
 
 import (
 	"bytes"
+	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,38 +11,69 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata" // Validates the timezone form field regardless of the system.
 
 	"github.com/labstack/echo/v4"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/russross/blackfriday/v2"
 	"go.uber.org/multierr"
+	"golang.org/x/text/language"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
+// maxNetworkLatency is the maximum emulated network latency, in
+// milliseconds.
+const maxNetworkLatency = 60000
+
 // FormDataChromiumOptions creates [Options] from the form data. Fallback to
 // default value if the considered key is not present.
 func FormDataChromiumOptions(ctx *api.Context) (*api.FormData, Options) {
 	defaultOptions := DefaultOptions()
 
 	var (
-		skipNetworkIdleEvent    bool
-		failOnHttpStatusCodes   []int64
-		failOnConsoleExceptions bool
-		waitDelay               time.Duration
-		waitWindowStatus        string
-		waitForExpression       string
-		extraHttpHeaders        map[string]string
-		emulatedMediaType       string
-		omitBackground          bool
+		skipNetworkIdleEvent          bool
+		navigationTimeout             time.Duration
+		networkIdleTimeout            time.Duration
+		failOnHttpStatusCodes         []int64
+		failOnResourceHttpStatusCodes []int64
+		failOnResourceLoadingFailed   bool
+		failOnConsoleExceptions       bool
+		waitDelay                     time.Duration
+		waitWindowStatus              string
+		waitForExpression             string
+		waitForSelector               string
+		waitForFonts                  bool
+		extraHttpHeaders              map[string]string
+		scopedExtraHttpHeaders        []ScopedHttpHeader
+		emulatedMediaType             string
+		colorScheme                   string
+		extraCss                      []string
+		networkConditions             *NetworkConditions
+		cookies                       []Cookie
+		proxy                         *Proxy
+		proxyBypassList               string
+		proxyUsername                 string
+		proxyPassword                 string
+		omitBackground                bool
+		timezone                      string
+		locale                        string
 	)
 
 	form := ctx.FormData().
 		Bool("skipNetworkIdleEvent", &skipNetworkIdleEvent, defaultOptions.SkipNetworkIdleEvent).
+		Custom("navigationTimeout", func(value string) error {
+			return parseNavigationTimeout(value, defaultOptions.NavigationTimeout, &navigationTimeout)
+		}).
+		Custom("networkIdleTimeout", func(value string) error {
+			return parseNavigationTimeout(value, defaultOptions.NetworkIdleTimeout, &networkIdleTimeout)
+		}).
 		Custom("failOnHttpStatusCodes", func(value string) error {
 			if value == "" {
 				failOnHttpStatusCodes = defaultOptions.FailOnHttpStatusCodes
@@ -54,21 +87,76 @@ func FormDataChromiumOptions(ctx *api.Context) (*api.FormData, Options) {
 
 			return nil
 		}).
+		Custom("failOnResourceHttpStatusCodes", func(value string) error {
+			if value == "" {
+				failOnResourceHttpStatusCodes = defaultOptions.FailOnResourceHttpStatusCodes
+				return nil
+			}
+
+			err := json.Unmarshal([]byte(value), &failOnResourceHttpStatusCodes)
+			if err != nil {
+				return fmt.Errorf("unmarshal failOnResourceHttpStatusCodes: %w", err)
+			}
+
+			return nil
+		}).
+		Bool("failOnResourceLoadingFailed", &failOnResourceLoadingFailed, defaultOptions.FailOnResourceLoadingFailed).
 		Bool("failOnConsoleExceptions", &failOnConsoleExceptions, defaultOptions.FailOnConsoleExceptions).
 		Duration("waitDelay", &waitDelay, defaultOptions.WaitDelay).
 		String("waitWindowStatus", &waitWindowStatus, defaultOptions.WaitWindowStatus).
 		String("waitForExpression", &waitForExpression, defaultOptions.WaitForExpression).
+		String("waitForSelector", &waitForSelector, defaultOptions.WaitForSelector).
+		Bool("waitForFonts", &waitForFonts, defaultOptions.WaitForFonts).
 		Custom("extraHttpHeaders", func(value string) error {
 			if value == "" {
 				extraHttpHeaders = defaultOptions.ExtraHttpHeaders
 				return nil
 			}
 
-			err := json.Unmarshal([]byte(value), &extraHttpHeaders)
+			var headers map[string]string
+			err := json.Unmarshal([]byte(value), &headers)
 			if err != nil {
 				return fmt.Errorf("unmarshal extraHttpHeaders: %w", err)
 			}
 
+			// A header is only sent with the requests which URL matches its
+			// scope, if any, e.g., "Bearer token;scope=^https://api\\.example\\.com/".
+			unscoped := make(map[string]string)
+			var scoped []ScopedHttpHeader
+
+			for name, headerValue := range headers {
+				headerValue, scope, ok := strings.Cut(headerValue, ";scope=")
+				if !ok {
+					unscoped[name] = headerValue
+					continue
+				}
+
+				scope = strings.TrimSpace(scope)
+				if scope == "" {
+					return fmt.Errorf("header '%s' has an empty scope", name)
+				}
+
+				scopeRegexp, err := regexp.Compile(scope)
+				if err != nil {
+					return fmt.Errorf("header '%s' has an invalid scope: %w", name, err)
+				}
+
+				scoped = append(scoped, ScopedHttpHeader{
+					Name:  name,
+					Value: strings.TrimSpace(headerValue),
+					Scope: scopeRegexp,
+				})
+			}
+
+			slices.SortFunc(scoped, func(a, b ScopedHttpHeader) int {
+				return strings.Compare(a.Name, b.Name)
+			})
+
+			if len(unscoped) > 0 {
+				extraHttpHeaders = unscoped
+			}
+			scopedExtraHttpHeaders = scoped
+
 			return nil
 		}).
 		Custom("emulatedMediaType", func(value string) error {
@@ -85,23 +173,263 @@ func FormDataChromiumOptions(ctx *api.Context) (*api.FormData, Options) {
 
 			return nil
 		}).
-		Bool("omitBackground", &omitBackground, defaultOptions.OmitBackground)
+		Custom("colorScheme", func(value string) error {
+			if value == "" {
+				colorScheme = defaultOptions.ColorScheme
+				return nil
+			}
+
+			if value != "light" && value != "dark" {
+				return fmt.Errorf("wrong value, expected either 'light', 'dark' or empty")
+			}
+
+			colorScheme = value
+
+			return nil
+		}).
+		Bool("omitBackground", &omitBackground, defaultOptions.OmitBackground).
+		Custom("timezone", func(value string) error {
+			if value == "" {
+				timezone = defaultOptions.Timezone
+				return nil
+			}
+
+			// Note: "Local" is the timezone of the container, not an IANA
+			// name.
+			_, err := time.LoadLocation(value)
+			if err != nil || value == "Local" {
+				return fmt.Errorf("wrong value, expected an IANA timezone, e.g., 'Europe/Paris'")
+			}
+
+			timezone = value
+
+			return nil
+		}).
+		Custom("networkConditions", func(value string) error {
+			if value == "" {
+				networkConditions = defaultOptions.NetworkConditions
+				return nil
+			}
+
+			decoder := json.NewDecoder(strings.NewReader(value))
+			decoder.DisallowUnknownFields()
+
+			var conditions NetworkConditions
+			err := decoder.Decode(&conditions)
+			if err != nil {
+				return fmt.Errorf("unmarshal networkConditions: %w", err)
+			}
+
+			if conditions.Latency < 0 || conditions.Latency > maxNetworkLatency {
+				return fmt.Errorf("latency must be between 0 and %d milliseconds", maxNetworkLatency)
+			}
+
+			if conditions.DownloadThroughput < 0 || conditions.UploadThroughput < 0 {
+				return errors.New("throughputs must be positive, or 0 for no throttling")
+			}
+
+			networkConditions = &conditions
+
+			return nil
+		}).
+		String("proxyBypassList", &proxyBypassList, "").
+		String("proxyUsername", &proxyUsername, "").
+		String("proxyPassword", &proxyPassword, "").
+		Custom("proxyServer", func(value string) error {
+			// The secrets are bound first, so that the errors, which echo
+			// the values, only concern the proxy server.
+			if value == "" {
+				if proxyBypassList != "" || proxyUsername != "" || proxyPassword != "" {
+					return errors.New("proxyBypassList, proxyUsername and proxyPassword require proxyServer")
+				}
+
+				proxy = defaultOptions.Proxy
+				return nil
+			}
+
+			if strings.ContainsAny(value, " ;,") {
+				return errors.New("wrong value, expected a single proxy server, e.g., 'http://proxy.example.com:3128'")
+			}
+
+			if proxyUsername == "" && proxyPassword != "" {
+				return errors.New("proxyPassword requires proxyUsername")
+			}
+
+			proxy = &Proxy{
+				Server:     value,
+				BypassList: proxyBypassList,
+			}
+
+			if proxyUsername != "" {
+				proxy.Auth = &HttpAuth{Username: proxyUsername, Password: proxyPassword}
+			}
+
+			return nil
+		}).
+		Custom("cookies", func(value string) error {
+			if value == "" {
+				cookies = defaultOptions.Cookies
+				return nil
+			}
+
+			decoder := json.NewDecoder(strings.NewReader(value))
+			decoder.DisallowUnknownFields()
+
+			var values []Cookie
+			err := decoder.Decode(&values)
+			if err != nil {
+				return fmt.Errorf("unmarshal cookies: %w", err)
+			}
+
+			for i, cookie := range values {
+				if cookie.Name == "" {
+					return fmt.Errorf("cookie %d has no name", i)
+				}
+
+				if cookie.Domain == "" {
+					return fmt.Errorf("cookie '%s' has no domain", cookie.Name)
+				}
+
+				switch cookie.SameSite {
+				case "", "Strict", "Lax", "None":
+				default:
+					return fmt.Errorf("cookie '%s' has a wrong sameSite value, expected either 'Strict', 'Lax' or 'None'", cookie.Name)
+				}
+			}
+
+			cookies = values
+
+			return nil
+		}).
+		Custom("locale", func(value string) error {
+			if value == "" {
+				locale = defaultOptions.Locale
+				return nil
+			}
+
+			tag, err := language.Parse(value)
+			if err != nil {
+				return fmt.Errorf("wrong value, expected a BCP 47 language tag, e.g., 'fr-FR'")
+			}
+
+			locale = tag.String()
+
+			return nil
+		})
+
+	// A value of the repeatable extraCss form field is either a stylesheet,
+	// or the filename of an uploaded stylesheet.
+	var extraCssValues []string
+	form.Strings("extraCss", &extraCssValues)
+
+	extraCss = defaultOptions.ExtraCss
+	for _, value := range extraCssValues {
+		stylesheet := value
+		if strings.HasSuffix(strings.ToLower(value), ".css") && !strings.ContainsAny(value, "{}") {
+			stylesheet = ""
+			form.MandatoryContent(value, &stylesheet)
+		}
+
+		if stylesheet != "" {
+			extraCss = append(extraCss, stylesheet)
+		}
+	}
+
+	// The uploaded fonts are declared before the extra stylesheets, so that
+	// the latter may rely on them.
+	fonts := api.FormDataFonts(form)
+	if len(fonts) > 0 {
+		fontFaces := make([]string, len(fonts))
+		for i, font := range fonts {
+			var content string
+			form.MandatoryContent(filepath.Base(font.Path), &content)
+
+			fontFaces[i] = fontFace(font, content)
+		}
+
+		extraCss = append([]string{strings.Join(fontFaces, "\n")}, extraCss...)
+	}
 
 	options := Options{
-		SkipNetworkIdleEvent:    skipNetworkIdleEvent,
-		FailOnHttpStatusCodes:   failOnHttpStatusCodes,
-		FailOnConsoleExceptions: failOnConsoleExceptions,
-		WaitDelay:               waitDelay,
-		WaitWindowStatus:        waitWindowStatus,
-		WaitForExpression:       waitForExpression,
-		ExtraHttpHeaders:        extraHttpHeaders,
-		EmulatedMediaType:       emulatedMediaType,
-		OmitBackground:          omitBackground,
+		SkipNetworkIdleEvent:          skipNetworkIdleEvent,
+		NavigationTimeout:             navigationTimeout,
+		NetworkIdleTimeout:            networkIdleTimeout,
+		FailOnHttpStatusCodes:         failOnHttpStatusCodes,
+		FailOnResourceHttpStatusCodes: failOnResourceHttpStatusCodes,
+		FailOnResourceLoadingFailed:   failOnResourceLoadingFailed,
+		FailOnConsoleExceptions:       failOnConsoleExceptions,
+		WaitDelay:                     waitDelay,
+		WaitWindowStatus:              waitWindowStatus,
+		WaitForExpression:             waitForExpression,
+		WaitForSelector:               waitForSelector,
+		WaitForFonts:                  waitForFonts,
+		ExtraHttpHeaders:              extraHttpHeaders,
+		ScopedExtraHttpHeaders:        scopedExtraHttpHeaders,
+		EmulatedMediaType:             emulatedMediaType,
+		ColorScheme:                   colorScheme,
+		ExtraCss:                      extraCss,
+		NetworkConditions:             networkConditions,
+		Cookies:                       cookies,
+		Proxy:                         proxy,
+		OmitBackground:                omitBackground,
+		Timezone:                      timezone,
+		Locale:                        locale,
 	}
 
 	return form, options
 }
 
+// cssStringEscaper escapes the content of a CSS string.
+var cssStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\a `)
+
+// fontFace returns the @font-face rule of an uploaded font. Its content is
+// inlined as a data URL, so that the page may use it whatever its origin.
+func fontFace(font gotenberg.Font, content string) string {
+	mediaType, format := "font/ttf", "truetype"
+	if font.Format == gotenberg.FontOpenType {
+		mediaType, format = "font/otf", "opentype"
+	}
+
+	style := "normal"
+	if font.Italic {
+		style = "italic"
+	}
+
+	return fmt.Sprintf(
+		`@font-face { font-family: "%s"; src: url("data:%s;base64,%s") format("%s"); font-weight: %d; font-style: %s; }`,
+		cssStringEscaper.Replace(font.Family), mediaType, base64.StdEncoding.EncodeToString([]byte(content)), format, font.Weight, style,
+	)
+}
+
+// The scale range Chromium accepts.
+const (
+	minScale = 0.1
+	maxScale = 2.0
+)
+
+// parseNavigationTimeout parses the "navigationTimeout" and
+// "networkIdleTimeout" form fields values, i.e., positive durations. The
+// maximums are checked by the browser.
+func parseNavigationTimeout(value string, defaultValue time.Duration, target *time.Duration) error {
+	if value == "" {
+		*target = defaultValue
+		return nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	if timeout < 0 {
+		return errors.New("value is negative")
+	}
+
+	*target = timeout
+
+	return nil
+}
+
 // FormDataChromiumPdfOptions creates [PdfOptions] from the form data. Fallback to
 // default value if the considered key is not present.
 func FormDataChromiumPdfOptions(ctx *api.Context) (*api.FormData, PdfOptions) {
@@ -113,24 +441,129 @@ func FormDataChromiumPdfOptions(ctx *api.Context) (*api.FormData, PdfOptions) {
 		scale, paperWidth, paperHeight                   float64
 		marginTop, marginBottom, marginLeft, marginRight float64
 		pageRanges                                       string
+		ignoreInvalidPageRanges                          bool
 		headerTemplate, footerTemplate                   string
-		preferCssPageSize                                bool
+		preferCssPageSize, explicitPaperSize             bool
+		pageSetupCss                                     string
 	)
 
 	form.
 		Bool("landscape", &landscape, defaultPdfOptions.Landscape).
 		Bool("printBackground", &printBackground, defaultPdfOptions.PrintBackground).
-		Float64("scale", &scale, defaultPdfOptions.Scale).
-		Float64("paperWidth", &paperWidth, defaultPdfOptions.PaperWidth).
-		Float64("paperHeight", &paperHeight, defaultPdfOptions.PaperHeight).
+		Custom("scale", func(value string) error {
+			if value == "" {
+				scale = defaultPdfOptions.Scale
+				return nil
+			}
+
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+
+			if parsed < minScale || parsed > maxScale {
+				return fmt.Errorf("must be between %.1f and %.1f", minScale, maxScale)
+			}
+
+			scale = parsed
+
+			return nil
+		}).
+		Custom("paperWidth", func(value string) error {
+			if value == "" {
+				paperWidth = defaultPdfOptions.PaperWidth
+				return nil
+			}
+
+			width, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+
+			paperWidth = width
+			explicitPaperSize = true
+
+			return nil
+		}).
+		Custom("paperHeight", func(value string) error {
+			if value == "" {
+				paperHeight = defaultPdfOptions.PaperHeight
+				return nil
+			}
+
+			height, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+
+			paperHeight = height
+			explicitPaperSize = true
+
+			return nil
+		}).
 		Float64("marginTop", &marginTop, defaultPdfOptions.MarginTop).
 		Float64("marginBottom", &marginBottom, defaultPdfOptions.MarginBottom).
 		Float64("marginLeft", &marginLeft, defaultPdfOptions.MarginLeft).
 		Float64("marginRight", &marginRight, defaultPdfOptions.MarginRight).
-		String("nativePageRanges", &pageRanges, defaultPdfOptions.PageRanges).
+		Bool("ignoreInvalidPageRanges", &ignoreInvalidPageRanges, false).
+		Custom("nativePageRanges", func(value string) error {
+			if value == "" {
+				pageRanges = defaultPdfOptions.PageRanges
+				return nil
+			}
+
+			ranges, err := parsePageRanges(value, ignoreInvalidPageRanges)
+			if err != nil {
+				return err
+			}
+
+			pageRanges = ranges
+
+			return nil
+		}).
 		Content("header.html", &headerTemplate, defaultPdfOptions.HeaderTemplate).
 		Content("footer.html", &footerTemplate, defaultPdfOptions.FooterTemplate).
-		Bool("preferCssPageSize", &preferCssPageSize, defaultPdfOptions.PreferCssPageSize)
+		Custom("preferCssPageSize", func(value string) error {
+			if value == "" {
+				preferCssPageSize = defaultPdfOptions.PreferCssPageSize
+				return nil
+			}
+
+			prefer, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+
+			preferCssPageSize = prefer
+
+			return nil
+		}).
+		Custom("pageSetup", func(value string) error {
+			if value == "" {
+				return nil
+			}
+
+			stylesheet, err := pageSetupStylesheet(value)
+			if err != nil {
+				return err
+			}
+
+			pageSetupCss = stylesheet
+
+			return nil
+		})
+
+	// The margins of the page setup override the default ones, hence the CSS
+	// @page rules which come after the other stylesheets.
+	if pageSetupCss != "" {
+		options.ExtraCss = append(slices.Clone(options.ExtraCss), pageSetupCss)
+	}
+
+	// The CSS @page size rules take precedence over the explicit paper size,
+	// which then only applies to the pages without such rules.
+	if preferCssPageSize && explicitPaperSize {
+		ctx.Log().Warn("'preferCssPageSize' is set alongside 'paperWidth' or 'paperHeight'; the CSS @page size rules take precedence")
+	}
 
 	pdfOptions := PdfOptions{
 		Options:           options,
@@ -152,6 +585,129 @@ func FormDataChromiumPdfOptions(ctx *api.Context) (*api.FormData, PdfOptions) {
 	return form, pdfOptions
 }
 
+// pageSetup is an entry of the pageSetup form field, i.e., the margins, in
+// inches, of a page given by its index.
+type pageSetup struct {
+	Page         int      `json:"page"`
+	MarginTop    *float64 `json:"marginTop"`
+	MarginBottom *float64 `json:"marginBottom"`
+	MarginLeft   *float64 `json:"marginLeft"`
+	MarginRight  *float64 `json:"marginRight"`
+}
+
+// pageSetupStylesheet parses the JSON value of the pageSetup form field and
+// returns the CSS @page rules which apply its margins. Chromium does not
+// support the :nth() page selector, so that only the first page, i.e., the
+// :first page selector, may have its own margins; the other pages require
+// named pages, see preferCssPageSize.
+func pageSetupStylesheet(value string) (string, error) {
+	var setups []pageSetup
+
+	err := json.Unmarshal([]byte(value), &setups)
+	if err != nil {
+		return "", fmt.Errorf("unmarshal pageSetup: %w", err)
+	}
+
+	var stylesheet strings.Builder
+
+	for _, setup := range setups {
+		if setup.Page != 1 {
+			return "", fmt.Errorf("page %d: only the first page may have its own margins, as Chromium does not support the :nth() page selector; use named pages for the other ones", setup.Page)
+		}
+
+		stylesheet.WriteString("@page :first {")
+
+		for _, margin := range []struct {
+			property string
+			value    *float64
+		}{
+			{property: "margin-top", value: setup.MarginTop},
+			{property: "margin-bottom", value: setup.MarginBottom},
+			{property: "margin-left", value: setup.MarginLeft},
+			{property: "margin-right", value: setup.MarginRight},
+		} {
+			if margin.value == nil {
+				continue
+			}
+
+			if *margin.value < 0 {
+				return "", fmt.Errorf("page %d: %s must be positive", setup.Page, margin.property)
+			}
+
+			stylesheet.WriteString(fmt.Sprintf(" %s: %sin !important;", margin.property, strconv.FormatFloat(*margin.value, 'f', -1, 64)))
+		}
+
+		stylesheet.WriteString(" }\n")
+	}
+
+	return stylesheet.String(), nil
+}
+
+// parsePageRanges validates page ranges like '1-5, 8, 11-'. Like Chromium
+// used to do with its ignoreInvalidPageRanges option, it either rejects or
+// drops the ranges which parse but cannot match any page, e.g., '3-2'.
+func parsePageRanges(value string, ignoreInvalid bool) (string, error) {
+	var ranges []string
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			return "", fmt.Errorf("empty page range in '%s'", value)
+		}
+
+		start, end, isRange := strings.Cut(entry, "-")
+		start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+
+		parse := func(page string) (int, error) {
+			if page == "" {
+				return 0, nil
+			}
+
+			n, err := strconv.Atoi(page)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid page '%s' in page range '%s'", page, entry)
+			}
+
+			return n, nil
+		}
+
+		first, err := parse(start)
+		if err != nil {
+			return "", err
+		}
+
+		last, err := parse(end)
+		if err != nil {
+			return "", err
+		}
+
+		if !isRange {
+			if first == 0 {
+				return "", fmt.Errorf("invalid page range '%s'", entry)
+			}
+
+			ranges = append(ranges, entry)
+			continue
+		}
+
+		if first == 0 && last == 0 {
+			return "", fmt.Errorf("invalid page range '%s'", entry)
+		}
+
+		if first != 0 && last != 0 && first > last {
+			if ignoreInvalid {
+				continue
+			}
+
+			return "", fmt.Errorf("page range '%s' starts after its end", entry)
+		}
+
+		ranges = append(ranges, entry)
+	}
+
+	return strings.Join(ranges, ","), nil
+}
+
 // FormDataChromiumScreenshotOptions creates [ScreenshotOptions] from the form
 // data. Fallback to default value if the considered key is not present.
 func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, ScreenshotOptions) {
@@ -162,6 +718,9 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 		format           string
 		quality          int
 		optimizeForSpeed bool
+		fullPage         bool
+		selector         string
+		clip             *ScreenshotClip
 	)
 
 	form.
@@ -171,8 +730,8 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 				return nil
 			}
 
-			if value != "png" && value != "jpeg" && value != "webp" {
-				return fmt.Errorf("wrong value, expected either 'png', 'jpeg' or 'webp'")
+			if value != "png" && value != "jpeg" && value != "webp" && value != "avif" {
+				return fmt.Errorf("wrong value, expected either 'png', 'jpeg', 'webp' or 'avif'")
 			}
 
 			format = value
@@ -185,6 +744,10 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 				return nil
 			}
 
+			if format == "png" {
+				return errors.New("quality is only available for lossy formats, i.e., 'jpeg', 'webp' or 'avif'")
+			}
+
 			intValue, err := strconv.Atoi(value)
 			if err != nil {
 				return err
@@ -201,13 +764,59 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 			quality = intValue
 			return nil
 		}).
-		Bool("optimizeForSpeed", &optimizeForSpeed, defaultScreenshotOptions.OptimizeForSpeed)
+		Bool("optimizeForSpeed", &optimizeForSpeed, defaultScreenshotOptions.OptimizeForSpeed).
+		Bool("fullPage", &fullPage, defaultScreenshotOptions.FullPage).
+		Custom("selector", func(value string) error {
+			if value == "" {
+				selector = defaultScreenshotOptions.Selector
+				return nil
+			}
+
+			if fullPage {
+				return errors.New("selector and fullPage are mutually exclusive")
+			}
+
+			selector = value
+
+			return nil
+		}).
+		Custom("clip", func(value string) error {
+			if value == "" {
+				clip = defaultScreenshotOptions.Clip
+				return nil
+			}
+
+			if fullPage || selector != "" {
+				return errors.New("clip, fullPage and selector are mutually exclusive")
+			}
+
+			var area ScreenshotClip
+			err := json.Unmarshal([]byte(value), &area)
+			if err != nil {
+				return fmt.Errorf("unmarshal clip: %w", err)
+			}
+
+			if area.X < 0 || area.Y < 0 {
+				return errors.New("x and y must be positive")
+			}
+
+			if area.Width <= 0 || area.Height <= 0 {
+				return errors.New("width and height must be strictly positive")
+			}
+
+			clip = &area
+
+			return nil
+		})
 
 	screenshotOptions := ScreenshotOptions{
 		Options:          options,
 		Format:           format,
 		Quality:          quality,
 		OptimizeForSpeed: optimizeForSpeed,
+		FullPage:         fullPage,
+		Selector:         selector,
+		Clip:             clip,
 	}
 
 	return form, screenshotOptions
@@ -234,61 +843,276 @@ func FormDataChromiumPdfFormats(form *api.FormData) gotenberg.PdfFormats {
 // convertUrlRoute returns an [api.Route] which can convert a URL to PDF.
 func convertUrlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 	return api.Route{
-		Method:      http.MethodPost,
-		Path:        "/forms/chromium/convert/url",
-		IsMultipart: true,
+		Method:       http.MethodPost,
+		Path:         "/forms/chromium/convert/url",
+		IsMultipart:  true,
+		DisableCache: true,
 		Handler: func(c echo.Context) error {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumPdfOptions(ctx)
 			pdfFormats := FormDataChromiumPdfFormats(form)
+			reproducible := api.FormDataReproducible(form)
 
-			var url string
-			err := form.
+			var (
+				url                   string
+				captureNavigationInfo bool
+			)
+
+			form.
 				MandatoryString("url", &url).
-				Validate()
+				Bool("captureNavigationInfo", &captureNavigationInfo, false).
+				Custom("session", func(value string) error {
+					if value != "" && !sessionNameRegexp.MatchString(value) {
+						return errors.New("wrong value, expected up to 64 letters, digits, '_' or '-'")
+					}
+
+					options.Session = value
+
+					return nil
+				})
+
+			formDataChromiumHttpAuth(form, url, &options.Options)
+
+			err := formDataChromiumClientCertificate(form, &options.Options).Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
-			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options)
+			if captureNavigationInfo {
+				options.NavigationInfo = new(NavigationInfo)
+			}
+
+			err = convertUrl(ctx, chromium, engine, url, pdfFormats, reproducible, options)
 			if err != nil {
 				return fmt.Errorf("convert URL to PDF: %w", err)
 			}
 
+			if options.NavigationInfo != nil {
+				err = setNavigationInfoHeaders(c, *options.NavigationInfo)
+				if err != nil {
+					return fmt.Errorf("set navigation info headers: %w", err)
+				}
+			}
+
 			return nil
 		},
 	}
 }
 
+// formDataChromiumHttpAuth binds the authentication form fields of the URL
+// routes to the options. The credentials answer the HTTP authentication
+// challenges of the origin of the URL, while a bearer token is an
+// "Authorization" header scoped to this origin.
+func formDataChromiumHttpAuth(form *api.FormData, url string, options *Options) *api.FormData {
+	var password, bearerToken string
+
+	// The secrets are bound first, so that the errors, which echo the
+	// values, only concern the username.
+	return form.
+		String("authPassword", &password, "").
+		String("authBearerToken", &bearerToken, "").
+		Custom("authUsername", func(value string) error {
+			switch {
+			case value != "" && bearerToken != "":
+				return errors.New("authUsername and authBearerToken are mutually exclusive")
+			case value == "" && password != "":
+				return errors.New("authPassword requires authUsername")
+			case value != "":
+				options.HttpAuth = &HttpAuth{Username: value, Password: password}
+			case bearerToken != "":
+				origin, err := urlOrigin(url)
+				if err != nil {
+					return fmt.Errorf("authBearerToken requires an absolute URL: %w", err)
+				}
+
+				options.ScopedExtraHttpHeaders = append(options.ScopedExtraHttpHeaders, ScopedHttpHeader{
+					Name:  "Authorization",
+					Value: fmt.Sprintf("Bearer %s", bearerToken),
+					Scope: regexp.MustCompile(fmt.Sprintf("^%s/", regexp.QuoteMeta(origin))),
+				})
+			}
+
+			return nil
+		})
+}
+
+// formDataChromiumClientCertificate binds the client certificate form fields
+// of the URL routes to the options. The certificate is either uploaded, as
+// the "client.crt" and "client.key" PEM files, or mounted, by name.
+func formDataChromiumClientCertificate(form *api.FormData, options *Options) *api.FormData {
+	var certPem, keyPem string
+
+	return form.
+		Content("client.crt", &certPem, "").
+		Content("client.key", &keyPem, "").
+		Custom("clientCertificateName", func(value string) error {
+			switch {
+			case value != "" && (certPem != "" || keyPem != ""):
+				return errors.New("clientCertificateName and the client.crt / client.key files are mutually exclusive")
+			case value != "":
+				if !sessionNameRegexp.MatchString(value) {
+					return errors.New("wrong value, expected up to 64 letters, digits, '_' or '-'")
+				}
+
+				options.ClientCertificateName = value
+			case certPem != "" || keyPem != "":
+				if certPem == "" || keyPem == "" {
+					return errors.New("the client.crt and client.key files go together")
+				}
+
+				certificate, err := tls.X509KeyPair([]byte(certPem), []byte(keyPem))
+				if err != nil {
+					return fmt.Errorf("wrong client.crt / client.key files: %w", err)
+				}
+
+				options.ClientCertificate = &certificate
+			}
+
+			// The requests with the client certificate go through the
+			// proxy server of the request, if any, which must be one the
+			// HTTP client of Gotenberg supports.
+			if options.ClientCertificate != nil || options.ClientCertificateName != "" {
+				_, err := clientTlsProxyUrl(options.Proxy)
+				if err != nil {
+					return fmt.Errorf("proxyServer not supported with a client certificate: %w", err)
+				}
+			}
+
+			return nil
+		})
+}
+
 // screenshotUrlRoute returns an [api.Route] which can take a screenshot from a
 // URL.
 func screenshotUrlRoute(chromium Api) api.Route {
 	return api.Route{
-		Method:      http.MethodPost,
-		Path:        "/forms/chromium/screenshot/url",
-		IsMultipart: true,
+		Method:       http.MethodPost,
+		Path:         "/forms/chromium/screenshot/url",
+		IsMultipart:  true,
+		DisableCache: true,
 		Handler: func(c echo.Context) error {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumScreenshotOptions(ctx)
 
-			var url string
-			err := form.
-				MandatoryString("url", &url).
-				Validate()
+			var (
+				url                   string
+				captureNavigationInfo bool
+			)
+
+			form.
+				MandatoryString("url", &url).
+				Bool("captureNavigationInfo", &captureNavigationInfo, false).
+				Custom("session", func(value string) error {
+					if value != "" && !sessionNameRegexp.MatchString(value) {
+						return errors.New("wrong value, expected up to 64 letters, digits, '_' or '-'")
+					}
+
+					options.Session = value
+
+					return nil
+				})
+
+			formDataChromiumHttpAuth(form, url, &options.Options)
+
+			err := formDataChromiumClientCertificate(form, &options.Options).Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			if captureNavigationInfo {
+				options.NavigationInfo = new(NavigationInfo)
+			}
+
+			err = screenshotUrl(ctx, chromium, url, options)
+			if err != nil {
+				return fmt.Errorf("URL screenshot: %w", err)
+			}
+
+			if options.NavigationInfo != nil {
+				err = setNavigationInfoHeaders(c, *options.NavigationInfo)
+				if err != nil {
+					return fmt.Errorf("set navigation info headers: %w", err)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// deleteSessionRoute returns an [api.Route] which can invalidate a session.
+func deleteSessionRoute(sessions *sessionStore) api.Route {
+	return api.Route{
+		Method: http.MethodDelete,
+		Path:   "/chromium/sessions/:name",
+		Handler: func(c echo.Context) error {
+			name := c.Param("name")
+			if !sessionNameRegexp.MatchString(name) {
+				return api.WrapError(
+					fmt.Errorf("invalid session name '%s'", name),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Invalid session name: expected up to 64 letters, digits, '_' or '-'"),
+				)
+			}
+
+			removed, err := sessions.remove(name)
 			if err != nil {
-				return fmt.Errorf("validate form data: %w", err)
+				return fmt.Errorf("remove session: %w", err)
 			}
 
-			err = screenshotUrl(ctx, chromium, url, options)
-			if err != nil {
-				return fmt.Errorf("URL screenshot: %w", err)
+			if !removed {
+				return api.WrapError(
+					fmt.Errorf("session '%s' not found", name),
+					api.NewSentinelHttpError(http.StatusNotFound, fmt.Sprintf("The session '%s' does not exist or has expired", name)),
+				)
 			}
 
-			return nil
+			return c.NoContent(http.StatusNoContent)
 		},
 	}
 }
 
+// htmlArchiveExtensions are the extensions of the archives the HTML routes
+// accept instead of an index.html file.
+var htmlArchiveExtensions = []string{".zip", ".mhtml", ".mht"}
+
+// htmlInputPath returns the path of the HTML document to render: either the
+// index.html file, a MHTML web archive, which Chromium renders as is, or the
+// index.html file at the root of a ZIP archive, once extracted alongside its
+// assets.
+func htmlInputPath(ctx *api.Context, indexPath string, archivePaths []string) (string, error) {
+	if indexPath != "" {
+		return indexPath, nil
+	}
+
+	if len(archivePaths) != 1 {
+		return "", api.WrapError(
+			fmt.Errorf("expected either an index.html file or one archive, got %d archive(s)", len(archivePaths)),
+			api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: form file 'index.html' is required, or one ZIP or MHTML archive").WithCode("INVALID_FORM_DATA"),
+		)
+	}
+
+	archivePath := archivePaths[0]
+	if strings.ToLower(filepath.Ext(archivePath)) != ".zip" {
+		return archivePath, nil
+	}
+
+	dirPath, err := ctx.ExtractZip(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("extract ZIP archive: %w", err)
+	}
+
+	indexPath = filepath.Join(dirPath, "index.html")
+	_, err = os.Stat(indexPath)
+	if err != nil {
+		return "", api.WrapError(
+			fmt.Errorf("stat index.html of archive: %w", err),
+			api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The archive '%s' has no 'index.html' file at its root", filepath.Base(archivePath))).WithCode("INVALID_FORM_DATA"),
+		)
+	}
+
+	return indexPath, nil
+}
+
 // convertHtmlRoute returns an [api.Route] which can convert an HTML file to
 // PDF.
 func convertHtmlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
@@ -300,17 +1124,77 @@ func convertHtmlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumPdfOptions(ctx)
 			pdfFormats := FormDataChromiumPdfFormats(form)
+			reproducible := api.FormDataReproducible(form)
+			order := api.FormDataOrder(form)
 
-			var inputPath string
-			err := form.
-				MandatoryPath("index.html", &inputPath).
-				Validate()
+			var (
+				merge        bool
+				inputPath    string
+				inputPaths   []string
+				archivePaths []string
+			)
+
+			form.Bool("merge", &merge, false)
+
+			// With merge, every HTML file is a page to render, in upload
+			// order unless the "order" form field says otherwise; otherwise,
+			// only the index.html file is, unless it comes from an archive.
+			if merge {
+				form.MandatoryPaths([]string{".html"}, &inputPaths)
+			} else {
+				form.
+					Path("index.html", &inputPath).
+					Paths(htmlArchiveExtensions, &archivePaths)
+			}
+
+			err := form.Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
-			url := fmt.Sprintf("file://%s", inputPath)
-			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options)
+			if !merge {
+				inputPath, err = htmlInputPath(ctx, inputPath, archivePaths)
+				if err != nil {
+					return fmt.Errorf("get HTML input path: %w", err)
+				}
+			}
+
+			var urls []string
+			if merge {
+				var pagePaths []string
+				for _, path := range ctx.InUploadOrder(inputPaths) {
+					// The header and footer are templates, not pages.
+					filename := filepath.Base(path)
+					if filename == "header.html" || filename == "footer.html" {
+						continue
+					}
+
+					pagePaths = append(pagePaths, path)
+				}
+
+				pagePaths, err = api.OrderPaths(pagePaths, *order)
+				if err != nil {
+					return api.WrapError(
+						fmt.Errorf("order input paths: %w", err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode("INVALID_FORM_DATA"),
+					)
+				}
+
+				for _, path := range pagePaths {
+					urls = append(urls, fmt.Sprintf("file://%s", path))
+				}
+
+				if len(urls) == 0 {
+					return api.WrapError(
+						errors.New("no HTML file besides the header and footer"),
+						api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: no HTML file to render besides 'header.html' and 'footer.html'"),
+					)
+				}
+			} else {
+				urls = []string{fmt.Sprintf("file://%s", inputPath)}
+			}
+
+			err = convertUrls(ctx, chromium, engine, urls, pdfFormats, reproducible, options)
 			if err != nil {
 				return fmt.Errorf("convert HTML to PDF: %w", err)
 			}
@@ -331,14 +1215,24 @@ func screenshotHtmlRoute(chromium Api) api.Route {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumScreenshotOptions(ctx)
 
-			var inputPath string
+			var (
+				inputPath    string
+				archivePaths []string
+			)
+
 			err := form.
-				MandatoryPath("index.html", &inputPath).
+				Path("index.html", &inputPath).
+				Paths(htmlArchiveExtensions, &archivePaths).
 				Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
+			inputPath, err = htmlInputPath(ctx, inputPath, archivePaths)
+			if err != nil {
+				return fmt.Errorf("get HTML input path: %w", err)
+			}
+
 			url := fmt.Sprintf("file://%s", inputPath)
 			err = screenshotUrl(ctx, chromium, url, options)
 			if err != nil {
@@ -361,6 +1255,7 @@ func convertMarkdownRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumPdfOptions(ctx)
 			pdfFormats := FormDataChromiumPdfFormats(form)
+			reproducible := api.FormDataReproducible(form)
 
 			var (
 				inputPath     string
@@ -380,7 +1275,7 @@ func convertMarkdownRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 				return fmt.Errorf("transform markdown file(s) to HTML: %w", err)
 			}
 
-			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options)
+			err = convertUrl(ctx, chromium, engine, url, pdfFormats, reproducible, options)
 			if err != nil {
 				return fmt.Errorf("convert markdown to PDF: %w", err)
 			}
@@ -504,15 +1399,24 @@ func markdownToHtml(ctx *api.Context, inputPath string, markdownPaths []string)
 	return fmt.Sprintf("file://%s", inputPath), nil
 }
 
-func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url string, pdfFormats gotenberg.PdfFormats, options PdfOptions) error {
-	outputPath := ctx.GeneratePath(".pdf")
+func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url string, pdfFormats gotenberg.PdfFormats, reproducible *time.Time, options PdfOptions) error {
+	return convertUrls(ctx, chromium, engine, []string{url}, pdfFormats, reproducible, options)
+}
 
-	err := chromium.Pdf(ctx, ctx.Log(), url, outputPath, options)
-	err = handleChromiumError(err, url, options.Options)
+// convertUrls converts the URLs to PDF, in the given order, and merges the
+// resulting PDFs if there are more than one.
+func convertUrls(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, urls []string, pdfFormats gotenberg.PdfFormats, reproducible *time.Time, options PdfOptions) error {
+	err := api.ValidateReproducible(reproducible, pdfFormats)
 	if err != nil {
-		if errors.Is(err, ErrOmitBackgroundWithoutPrintBackground) {
+		return err
+	}
+
+	// The client only wants to validate its request, so that only the checks
+	// which do not require Chromium apply.
+	if ctx.ValidateOnly() {
+		if options.OmitBackground && !options.PrintBackground {
 			return api.WrapError(
-				err,
+				ErrOmitBackgroundWithoutPrintBackground,
 				api.NewSentinelHttpError(
 					http.StatusBadRequest,
 					"omitBackground requires printBackground set to true",
@@ -520,30 +1424,30 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 			)
 		}
 
-		if errors.Is(err, ErrInvalidPrinterSettings) {
-			return api.WrapError(
-				fmt.Errorf("convert to PDF: %w", err),
-				api.NewSentinelHttpError(
-					http.StatusBadRequest,
-					"Chromium does not handle the provided settings; please check for aberrant form values",
-				),
-			)
-		}
+		return nil
+	}
 
-		if errors.Is(err, ErrPageRangesSyntaxError) {
-			return api.WrapError(
-				fmt.Errorf("convert to PDF: %w", err),
-				api.NewSentinelHttpError(
-					http.StatusBadRequest,
-					fmt.Sprintf("Chromium does not handle the page ranges '%s' (nativePageRanges)", options.PageRanges),
-				),
-			)
+	outputPaths := make([]string, len(urls))
+	for i, url := range urls {
+		outputPath, err := printUrl(ctx, chromium, url, options)
+		if err != nil {
+			return err
 		}
 
-		return fmt.Errorf("convert to PDF: %w", err)
+		outputPaths[i] = outputPath
+	}
+
+	outputPath := outputPaths[0]
+	if len(outputPaths) > 1 {
+		outputPath = ctx.GeneratePath(".pdf")
+
+		err := engine.Merge(ctx, ctx.Log(), outputPaths, outputPath)
+		if err != nil {
+			return fmt.Errorf("merge PDFs: %w", err)
+		}
 	}
 
-	// So far so good, the URL has been converted to PDF.
+	// So far so good, the URLs have been converted to PDF.
 	// Now, let's check if the client want to convert the resulting PDF
 	// to specific formats.
 	zeroValued := gotenberg.PdfFormats{}
@@ -551,7 +1455,7 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 		convertInputPath := outputPath
 		convertOutputPath := ctx.GeneratePath(".pdf")
 
-		err = engine.Convert(ctx, ctx.Log(), pdfFormats, convertInputPath, convertOutputPath)
+		err := engine.Convert(ctx, ctx.Log(), pdfFormats, convertInputPath, convertOutputPath)
 
 		if err != nil {
 			if errors.Is(err, gotenberg.ErrPdfFormatNotSupported) {
@@ -571,6 +1475,16 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 		outputPath = convertOutputPath
 	}
 
+	if reproducible != nil {
+		outputPaths, err := api.NormalizePdfs(ctx, engine, *reproducible, outputPath)
+		if err != nil {
+			return fmt.Errorf("normalize PDF: %w", err)
+		}
+
+		// Important: the output path is now the normalized file.
+		outputPath = outputPaths[0]
+	}
+
 	err = ctx.AddOutputPaths(outputPath)
 	if err != nil {
 		return fmt.Errorf("add output path: %w", err)
@@ -579,13 +1493,89 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 	return nil
 }
 
+// printUrl converts a URL to PDF with Chromium.
+func printUrl(ctx *api.Context, chromium Api, url string, options PdfOptions) (string, error) {
+	outputPath := ctx.GeneratePath(".pdf")
+
+	err := chromium.Pdf(ctx, ctx.Log(), url, outputPath, options)
+	err = handleChromiumError(err, url, options.Options)
+	if err != nil {
+		if errors.Is(err, ErrOmitBackgroundWithoutPrintBackground) {
+			return "", api.WrapError(
+				err,
+				api.NewSentinelHttpError(
+					http.StatusBadRequest,
+					"omitBackground requires printBackground set to true",
+				),
+			)
+		}
+
+		if errors.Is(err, ErrInvalidPrinterSettings) {
+			return "", api.WrapError(
+				fmt.Errorf("convert to PDF: %w", err),
+				api.NewSentinelHttpError(
+					http.StatusBadRequest,
+					"Chromium does not handle the provided settings; please check for aberrant form values",
+				),
+			)
+		}
+
+		if errors.Is(err, ErrPageRangesSyntaxError) {
+			return "", api.WrapError(
+				fmt.Errorf("convert to PDF: %w", err),
+				api.NewSentinelHttpError(
+					http.StatusBadRequest,
+					fmt.Sprintf("Chromium does not handle the page ranges '%s' (nativePageRanges)", options.PageRanges),
+				),
+			)
+		}
+
+		return "", fmt.Errorf("convert to PDF: %w", err)
+	}
+
+	return outputPath, nil
+}
+
 func screenshotUrl(ctx *api.Context, chromium Api, url string, options ScreenshotOptions) error {
+	// The client only wants to validate its request.
+	if ctx.ValidateOnly() {
+		return nil
+	}
+
 	ext := fmt.Sprintf(".%s", options.Format)
 	outputPath := ctx.GeneratePath(ext)
 
 	err := chromium.Screenshot(ctx, ctx.Log(), url, outputPath, options)
 	err = handleChromiumError(err, url, options.Options)
 	if err != nil {
+		if errors.Is(err, ErrAvifEncoderNotAvailable) {
+			return api.WrapError(
+				fmt.Errorf("screenshot: %w", err),
+				api.NewSentinelHttpError(http.StatusBadRequest, "The 'avif' format is not available"),
+			)
+		}
+
+		if errors.Is(err, ErrScreenshotHeightExceeded) {
+			return api.WrapError(
+				fmt.Errorf("screenshot: %w", err),
+				api.NewSentinelHttpError(http.StatusBadRequest, "The captured area is taller than the maximum height of a screenshot"),
+			)
+		}
+
+		if errors.Is(err, ErrElementNotFound) {
+			return api.WrapError(
+				fmt.Errorf("screenshot: %w", err),
+				api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The selector '%s' is not valid or does not match any visible element", options.Selector)),
+			)
+		}
+
+		if errors.Is(err, ErrFullPageWebpTooTall) {
+			return api.WrapError(
+				fmt.Errorf("screenshot: %w", err),
+				api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The 'webp' format is not available for a full-page screenshot taller than %d pixels", screenshotTileHeight)),
+			)
+		}
+
 		return fmt.Errorf("screenshot: %w", err)
 	}
 
@@ -597,11 +1587,117 @@ func screenshotUrl(ctx *api.Context, chromium Api, url string, options Screensho
 	return nil
 }
 
+// setNavigationInfoHeaders adds the final URL, the HTTP status and the
+// redirect chain (as JSON) of the main page to the response headers.
+func setNavigationInfoHeaders(c echo.Context, navigationInfo NavigationInfo) error {
+	redirectChain := navigationInfo.RedirectChain
+	if redirectChain == nil {
+		redirectChain = []NavigationRedirect{}
+	}
+
+	b, err := json.Marshal(redirectChain)
+	if err != nil {
+		return fmt.Errorf("marshal redirect chain: %w", err)
+	}
+
+	header := c.Response().Header()
+	header.Set("Gotenberg-Final-Url", navigationInfo.FinalUrl)
+	header.Set("Gotenberg-Http-Status", strconv.FormatInt(navigationInfo.HttpStatus, 10))
+	header.Set("Gotenberg-Redirect-Chain", string(b))
+
+	return nil
+}
+
 func handleChromiumError(err error, url string, options Options) error {
 	if err == nil {
 		return nil
 	}
 
+	if errors.Is(err, ErrProxyServerNotAllowed) {
+		if options.Proxy == nil {
+			// Only a proxy server of the request may be denied.
+			return err
+		}
+
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusForbidden,
+				fmt.Sprintf("The proxy server '%s' is not allowed", options.Proxy.Server),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrClientCertificateNotFound) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("The client certificate '%s' does not exist", options.ClientCertificateName),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrUnsupportedHttpAuthScheme) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("The URL '%s' asks for an HTTP authentication scheme other than Basic, which is not supported with a client certificate", url),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrSessionsNotAllowed) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("The session '%s' cannot be used, as the sessions are not allowed", options.Session),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrNetworkOffline) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("'%s' cannot be loaded, as the emulated network is offline (networkConditions)", url),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrTimeoutAboveMaximum) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("Invalid form data: %s", strings.ReplaceAll(err.Error(), fmt.Sprintf(": %s", ErrTimeoutAboveMaximum.Error()), "")),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrNavigationTimeout) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusGatewayTimeout,
+				fmt.Sprintf("'%s' did not reach its load event in time (navigationTimeout)", url),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrNetworkIdleTimeout) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusGatewayTimeout,
+				fmt.Sprintf("'%s' loaded but its network did not become idle in time (networkIdleTimeout); consider the skipNetworkIdleEvent form field", url),
+			),
+		)
+	}
+
 	if errors.Is(err, ErrUrlNotAuthorized) {
 		return api.WrapError(
 			err,
@@ -629,6 +1725,16 @@ func handleChromiumError(err error, url string, options Options) error {
 		)
 	}
 
+	if errors.Is(err, ErrInvalidSelector) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("The selector '%s' (waitForSelector) is not a valid CSS selector", options.WaitForSelector),
+			),
+		)
+	}
+
 	if errors.Is(err, ErrInvalidHttpStatusCode) {
 		return api.WrapError(
 			err,
@@ -639,6 +1745,26 @@ func handleChromiumError(err error, url string, options Options) error {
 		)
 	}
 
+	if errors.Is(err, ErrInvalidResourceHttpStatusCode) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusConflict,
+				fmt.Sprintf("Invalid HTTP status code from resources:\n%s", strings.ReplaceAll(err.Error(), fmt.Sprintf(": %s", ErrInvalidResourceHttpStatusCode.Error()), "")),
+			),
+		)
+	}
+
+	if errors.Is(err, ErrResourceLoadingFailed) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusConflict,
+				fmt.Sprintf("Failed to load resources:\n%s", strings.ReplaceAll(err.Error(), fmt.Sprintf(": %s", ErrResourceLoadingFailed.Error()), "")),
+			),
+		)
+	}
+
 	if errors.Is(err, ErrConsoleExceptions) {
 		return api.WrapError(
 			err,
@@ -1,23 +1,43 @@
 package chromium
 
 import (
+	"archive/zip"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
+	"golang.org/x/image/font/gofont/goregular"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
 func TestFormDataChromiumOptions(t *testing.T) {
+	cssPath := fmt.Sprintf("%s/extra.css", t.TempDir())
+	err := os.WriteFile(cssPath, []byte("body { color: red; }"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	fontPath := fmt.Sprintf("%s/regular.ttf", t.TempDir())
+	err = os.WriteFile(fontPath, goregular.TTF, 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
 	for _, tc := range []struct {
 		scenario        string
 		ctx             *api.ContextMock
@@ -63,43 +83,65 @@ func TestFormDataChromiumOptions(t *testing.T) {
 			}(),
 		},
 		{
-			scenario: "invalid extraHttpHeaders form field",
+			scenario: "invalid navigationTimeout and networkIdleTimeout form fields",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"extraHttpHeaders": {
+					"navigationTimeout": {
 						"foo",
 					},
+					"networkIdleTimeout": {
+						"-1s",
+					},
 				})
 				return ctx
 			}(),
 			expectedOptions: DefaultOptions(),
 		},
 		{
-			scenario: "valid extraHttpHeaders form field",
+			scenario: "valid navigationTimeout and networkIdleTimeout form fields",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"extraHttpHeaders": {
-						`{"foo":"bar"}`,
+					"navigationTimeout": {
+						"10s",
+					},
+					"networkIdleTimeout": {
+						"2s",
 					},
 				})
 				return ctx
 			}(),
 			expectedOptions: func() Options {
 				options := DefaultOptions()
-				options.ExtraHttpHeaders = map[string]string{
-					"foo": "bar",
-				}
+				options.NavigationTimeout = time.Duration(10) * time.Second
+				options.NetworkIdleTimeout = time.Duration(2) * time.Second
 				return options
 			}(),
 		},
 		{
-			scenario: "invalid emulatedMediaType form field",
+			scenario: "waitForFonts form field set to false",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"emulatedMediaType": {
+					"waitForFonts": {
+						"false",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.WaitForFonts = false
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid failOnResourceHttpStatusCodes form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"failOnResourceHttpStatusCodes": {
 						"foo",
 					},
 				})
@@ -108,322 +150,1773 @@ func TestFormDataChromiumOptions(t *testing.T) {
 			expectedOptions: DefaultOptions(),
 		},
 		{
-			scenario: "valid emulatedMediaType form field",
+			scenario: "valid failOnResourceHttpStatusCodes and failOnResourceLoadingFailed form fields",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"emulatedMediaType": {
-						"screen",
+					"failOnResourceHttpStatusCodes": {
+						`[404,599]`,
+					},
+					"failOnResourceLoadingFailed": {
+						"true",
 					},
 				})
 				return ctx
 			}(),
 			expectedOptions: func() Options {
 				options := DefaultOptions()
-				options.EmulatedMediaType = "screen"
+				options.FailOnResourceHttpStatusCodes = []int64{404, 599}
+				options.FailOnResourceLoadingFailed = true
 				return options
 			}(),
 		},
-	} {
-		t.Run(tc.scenario, func(t *testing.T) {
-			tc.ctx.SetLogger(zap.NewNop())
-			_, actual := FormDataChromiumOptions(tc.ctx.Context)
-
-			if !reflect.DeepEqual(actual, tc.expectedOptions) {
-				t.Fatalf("expected %+v but got: %+v", tc.expectedOptions, actual)
-			}
-		})
-	}
-}
-
-func TestFormDataChromiumPdfOptions(t *testing.T) {
-	for _, tc := range []struct {
-		scenario        string
-		ctx             *api.ContextMock
-		expectedOptions PdfOptions
-	}{
-		{
-			scenario:        "no custom form fields",
-			ctx:             &api.ContextMock{Context: new(api.Context)},
-			expectedOptions: DefaultPdfOptions(),
-		},
 		{
-			scenario: "custom form fields (Options & PdfOptions)",
+			scenario: "invalid timezone form field",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"landscape": {
-						"true",
-					},
-					"emulatedMediaType": {
-						"screen",
+					"timezone": {
+						"Mars/Olympus_Mons",
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() PdfOptions {
-				options := DefaultPdfOptions()
-				options.Landscape = true
-				options.EmulatedMediaType = "screen"
-				return options
-			}(),
-		},
-	} {
-		t.Run(tc.scenario, func(t *testing.T) {
-			tc.ctx.SetLogger(zap.NewNop())
-			_, actual := FormDataChromiumPdfOptions(tc.ctx.Context)
-
-			if !reflect.DeepEqual(actual, tc.expectedOptions) {
-				t.Fatalf("expected %+v but got: %+v", tc.expectedOptions, actual)
-			}
-		})
-	}
-}
-
-func TestFormDataChromiumScreenshotOptions(t *testing.T) {
-	for _, tc := range []struct {
-		scenario        string
-		ctx             *api.ContextMock
-		expectedOptions ScreenshotOptions
-	}{
-		{
-			scenario:        "no custom form fields",
-			ctx:             &api.ContextMock{Context: new(api.Context)},
-			expectedOptions: DefaultScreenshotOptions(),
+			expectedOptions: DefaultOptions(),
 		},
 		{
-			scenario: "invalid format form field",
+			scenario: "invalid locale form field",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"format": {
-						"gif",
+					"locale": {
+						"not a locale",
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() ScreenshotOptions {
-				options := DefaultScreenshotOptions()
-				options.Format = ""
-				return options
-			}(),
+			expectedOptions: DefaultOptions(),
 		},
 		{
-			scenario: "valid png format form field",
+			scenario: "valid timezone and locale form fields",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"format": {
-						"png",
+					"timezone": {
+						"Europe/Paris",
+					},
+					"locale": {
+						"fr-FR",
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() ScreenshotOptions {
-				options := DefaultScreenshotOptions()
-				options.Format = "png"
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.Timezone = "Europe/Paris"
+				options.Locale = "fr-FR"
 				return options
 			}(),
 		},
 		{
-			scenario: "valid jpeg format form field",
+			scenario: "invalid extraHttpHeaders form field",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"format": {
-						"jpeg",
+					"extraHttpHeaders": {
+						"foo",
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() ScreenshotOptions {
-				options := DefaultScreenshotOptions()
-				options.Format = "jpeg"
-				return options
-			}(),
+			expectedOptions: DefaultOptions(),
 		},
 		{
-			scenario: "valid webp format form field",
+			scenario: "valid extraHttpHeaders form field",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"format": {
-						"webp",
+					"extraHttpHeaders": {
+						`{"foo":"bar"}`,
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() ScreenshotOptions {
-				options := DefaultScreenshotOptions()
-				options.Format = "webp"
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.ExtraHttpHeaders = map[string]string{
+					"foo": "bar",
+				}
 				return options
 			}(),
 		},
 		{
-			scenario: "invalid quality form field (not an integer)",
+			scenario: "invalid extraHttpHeaders form field (empty scope)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"quality": {
-						"foo",
+					"extraHttpHeaders": {
+						`{"foo":"bar;scope="}`,
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() ScreenshotOptions {
-				options := DefaultScreenshotOptions()
-				options.Quality = 0
-				return options
-			}(),
+			expectedOptions: DefaultOptions(),
 		},
 		{
-			scenario: "invalid quality form field (< 0)",
+			scenario: "invalid extraHttpHeaders form field (invalid scope)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"quality": {
-						"-1",
+					"extraHttpHeaders": {
+						`{"foo":"bar;scope=("}`,
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() ScreenshotOptions {
-				options := DefaultScreenshotOptions()
-				options.Quality = 0
-				return options
-			}(),
+			expectedOptions: DefaultOptions(),
 		},
 		{
-			scenario: "invalid quality form field (> 100)",
+			scenario: "valid extraHttpHeaders form field (scoped)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"quality": {
-						"101",
+					"extraHttpHeaders": {
+						`{"foo":"bar","Authorization":"Bearer token;scope=^https://api\\.example\\.com/"}`,
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() ScreenshotOptions {
-				options := DefaultScreenshotOptions()
-				options.Quality = 0
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.ExtraHttpHeaders = map[string]string{
+					"foo": "bar",
+				}
+				options.ScopedExtraHttpHeaders = []ScopedHttpHeader{
+					{
+						Name:  "Authorization",
+						Value: "Bearer token",
+						Scope: regexp.MustCompile(`^https://api\.example\.com/`),
+					},
+				}
 				return options
 			}(),
 		},
 		{
-			scenario: "valid quality form field",
+			scenario: "invalid emulatedMediaType form field",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"quality": {
-						"50",
+					"emulatedMediaType": {
+						"foo",
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() ScreenshotOptions {
-				options := DefaultScreenshotOptions()
-				options.Quality = 50
-				return options
-			}(),
+			expectedOptions: DefaultOptions(),
 		},
 		{
-			scenario: "custom form fields (Options & ScreenshotOptions)",
+			scenario: "valid emulatedMediaType form field",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"optimizeForSpeed": {
-						"true",
-					},
 					"emulatedMediaType": {
 						"screen",
 					},
 				})
 				return ctx
 			}(),
-			expectedOptions: func() ScreenshotOptions {
-				options := DefaultScreenshotOptions()
-				options.OptimizeForSpeed = true
+			expectedOptions: func() Options {
+				options := DefaultOptions()
 				options.EmulatedMediaType = "screen"
 				return options
 			}(),
 		},
-	} {
-		t.Run(tc.scenario, func(t *testing.T) {
-			tc.ctx.SetLogger(zap.NewNop())
-			_, actual := FormDataChromiumScreenshotOptions(tc.ctx.Context)
-
-			if !reflect.DeepEqual(actual, tc.expectedOptions) {
-				t.Fatalf("expected %+v but got: %+v", tc.expectedOptions, actual)
-			}
-		})
-	}
-}
-
-func TestFormDataChromiumPdfFormats(t *testing.T) {
-	for _, tc := range []struct {
-		scenario           string
-		ctx                *api.ContextMock
-		expectedPdfFormats gotenberg.PdfFormats
-	}{
-		{
-			scenario:           "no custom form fields",
-			ctx:                &api.ContextMock{Context: new(api.Context)},
-			expectedPdfFormats: gotenberg.PdfFormats{},
-		},
 		{
-			scenario: "pdfa and pdfua form fields",
+			scenario: "invalid colorScheme form field",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"pdfa": {
+					"colorScheme": {
 						"foo",
 					},
-					"pdfua": {
-						"true",
-					},
 				})
 				return ctx
 			}(),
-			expectedPdfFormats: gotenberg.PdfFormats{PdfA: "foo", PdfUa: true},
-		},
-	} {
-		t.Run(tc.scenario, func(t *testing.T) {
-			tc.ctx.SetLogger(zap.NewNop())
-			actual := FormDataChromiumPdfFormats(tc.ctx.Context.FormData())
-
-			if !reflect.DeepEqual(actual, tc.expectedPdfFormats) {
-				t.Fatalf("expected %+v but got: %+v", tc.expectedPdfFormats, actual)
-			}
-		})
-	}
-}
-
-func TestConvertUrlRoute(t *testing.T) {
-	for _, tc := range []struct {
-		scenario               string
-		ctx                    *api.ContextMock
-		api                    Api
-		expectError            bool
-		expectHttpError        bool
-		expectHttpStatus       int
-		expectOutputPathsCount int
-	}{
-		{
-			scenario:               "missing mandatory url form field",
-			ctx:                    &api.ContextMock{Context: new(api.Context)},
-			expectError:            true,
-			expectHttpError:        true,
-			expectHttpStatus:       http.StatusBadRequest,
-			expectOutputPathsCount: 0,
+			expectedOptions: DefaultOptions(),
 		},
 		{
-			scenario: "empty url form field",
+			scenario: "valid colorScheme form field",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetValues(map[string][]string{
-					"url": {
-						"",
+					"colorScheme": {
+						"dark",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.ColorScheme = "dark"
+				return options
+			}(),
+		},
+		{
+			scenario: "missing extraCss file",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"extraCss": {
+						"foo.css",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "valid extraCss form fields",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"extra.css": cssPath,
+				})
+				ctx.SetValues(map[string][]string{
+					"extraCss": {
+						"@page { size: A5; }",
+						"extra.css",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.ExtraCss = []string{
+					"@page { size: A5; }",
+					"body { color: red; }",
+				}
+				return options
+			}(),
+		},
+		{
+			scenario: "font files",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"extra.css":   cssPath,
+					"regular.ttf": fontPath,
+				})
+				ctx.SetValues(map[string][]string{
+					"extraCss": {
+						"extra.css",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.ExtraCss = []string{
+					`@font-face { font-family: "Go"; src: url("data:font/ttf;base64,` + base64.StdEncoding.EncodeToString(goregular.TTF) + `") format("truetype"); font-weight: 400; font-style: normal; }`,
+					"body { color: red; }",
+				}
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid networkConditions form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"networkConditions": {
+						`{"foo":true}`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "networkConditions form field with an out of range latency",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"networkConditions": {
+						`{"latency":-1}`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "networkConditions form field with a negative throughput",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"networkConditions": {
+						`{"downloadThroughput":-1}`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "valid networkConditions form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"networkConditions": {
+						`{"offline":false,"latency":200,"downloadThroughput":50000,"uploadThroughput":20000}`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.NetworkConditions = &NetworkConditions{
+					Latency:            200,
+					DownloadThroughput: 50000,
+					UploadThroughput:   20000,
+				}
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid proxyServer form field (list)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"proxyServer": {
+						"http://a:3128;http://b:3128",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "proxyBypassList form field without proxyServer",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"proxyBypassList": {
+						"localhost",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "proxyPassword form field without proxyUsername",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"proxyServer": {
+						"http://proxy.example.com:3128",
+					},
+					"proxyPassword": {
+						"secret",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "valid proxyServer form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"proxyServer": {
+						"http://proxy.example.com:3128",
+					},
+					"proxyBypassList": {
+						"localhost;*.internal",
+					},
+					"proxyUsername": {
+						"john",
+					},
+					"proxyPassword": {
+						"secret",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.Proxy = &Proxy{
+					Server:     "http://proxy.example.com:3128",
+					BypassList: "localhost;*.internal",
+					Auth:       &HttpAuth{Username: "john", Password: "secret"},
+				}
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid cookies form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"cookies": {
+						`{"name":"foo"}`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "cookies form field without a name",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"cookies": {
+						`[{"value":"bar","domain":"example.com"}]`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "cookies form field without a domain",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"cookies": {
+						`[{"name":"foo","value":"bar"}]`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "cookies form field with a wrong sameSite value",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"cookies": {
+						`[{"name":"foo","value":"bar","domain":"example.com","sameSite":"foo"}]`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultOptions(),
+		},
+		{
+			scenario: "valid cookies form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"cookies": {
+						`[{"name":"session","value":"secret","domain":"example.com","path":"/app","secure":true,"httpOnly":true,"sameSite":"Lax"}]`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() Options {
+				options := DefaultOptions()
+				options.Cookies = []Cookie{
+					{
+						Name:     "session",
+						Value:    "secret",
+						Domain:   "example.com",
+						Path:     "/app",
+						Secure:   true,
+						HttpOnly: true,
+						SameSite: "Lax",
+					},
+				}
+				return options
+			}(),
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			_, actual := FormDataChromiumOptions(tc.ctx.Context)
+
+			if !reflect.DeepEqual(actual, tc.expectedOptions) {
+				t.Fatalf("expected %+v but got: %+v", tc.expectedOptions, actual)
+			}
+		})
+	}
+}
+
+func TestFontFace(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		font     gotenberg.Font
+		expect   string
+	}{
+		{
+			scenario: "TrueType",
+			font:     gotenberg.Font{Format: gotenberg.FontTrueType, Family: "Corporate Sans", Weight: 600},
+			expect:   `@font-face { font-family: "Corporate Sans"; src: url("data:font/ttf;base64,Zm9v") format("truetype"); font-weight: 600; font-style: normal; }`,
+		},
+		{
+			scenario: "OpenType, italic",
+			font:     gotenberg.Font{Format: gotenberg.FontOpenType, Family: `Quote"d`, Weight: 400, Italic: true},
+			expect:   `@font-face { font-family: "Quote\"d"; src: url("data:font/otf;base64,Zm9v") format("opentype"); font-weight: 400; font-style: italic; }`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := fontFace(tc.font, "foo")
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestFormDataChromiumPdfOptions(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		ctx             *api.ContextMock
+		expectedOptions PdfOptions
+		expectError     bool
+	}{
+		{
+			scenario:        "no custom form fields",
+			ctx:             &api.ContextMock{Context: new(api.Context)},
+			expectedOptions: DefaultPdfOptions(),
+		},
+		{
+			scenario: "custom form fields (Options & PdfOptions)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"landscape": {
+						"true",
+					},
+					"emulatedMediaType": {
+						"screen",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.Landscape = true
+				options.EmulatedMediaType = "screen"
+				return options
+			}(),
+		},
+		{
+			scenario: "valid preferCssPageSize form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"preferCssPageSize": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.PreferCssPageSize = true
+				return options
+			}(),
+		},
+		{
+			scenario: "preferCssPageSize form field with explicit paper size",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"preferCssPageSize": {
+						"true",
+					},
+					"paperWidth": {
+						"11.7",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.PaperWidth = 11.7
+				options.PreferCssPageSize = true
+				return options
+			}(),
+			expectError: false,
+		},
+		{
+			scenario: "valid scale form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"scale": {
+						"0.5",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.Scale = 0.5
+				return options
+			}(),
+			expectError: false,
+		},
+		{
+			scenario: "scale form field out of range",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"scale": {
+						"2.5",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.Scale = 0
+				return options
+			}(),
+			expectError: true,
+		},
+		{
+			scenario: "scale form field with preferCssPageSize",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"scale": {
+						"1.5",
+					},
+					"preferCssPageSize": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.Scale = 1.5
+				options.PreferCssPageSize = true
+				return options
+			}(),
+			expectError: false,
+		},
+		{
+			scenario: "valid nativePageRanges form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"nativePageRanges": {
+						"1-5, 8, 11-",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.PageRanges = "1-5,8,11-"
+				return options
+			}(),
+			expectError: false,
+		},
+		{
+			scenario: "invalid nativePageRanges form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"nativePageRanges": {
+						"1-a",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				return options
+			}(),
+			expectError: true,
+		},
+		{
+			scenario: "nativePageRanges form field with a reversed range",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"nativePageRanges": {
+						"3-2",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				return options
+			}(),
+			expectError: true,
+		},
+		{
+			scenario: "ignoreInvalidPageRanges form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"nativePageRanges": {
+						"1, 3-2",
+					},
+					"ignoreInvalidPageRanges": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.PageRanges = "1"
+				return options
+			}(),
+			expectError: false,
+		},
+		{
+			scenario: "pageSetup form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"pageSetup": {
+						`[{"page": 1, "marginTop": 0, "marginLeft": 0.5}]`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.ExtraCss = []string{"@page :first { margin-top: 0in !important; margin-left: 0.5in !important; }\n"}
+				return options
+			}(),
+			expectError: false,
+		},
+		{
+			scenario: "invalid pageSetup form field (not the first page)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"pageSetup": {
+						`[{"page": 2, "marginTop": 0}]`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultPdfOptions(),
+			expectError:     true,
+		},
+		{
+			scenario: "invalid pageSetup form field (negative margin)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"pageSetup": {
+						`[{"page": 1, "marginTop": -1}]`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultPdfOptions(),
+			expectError:     true,
+		},
+		{
+			scenario: "invalid pageSetup form field (not JSON)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"pageSetup": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: DefaultPdfOptions(),
+			expectError:     true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			form, actual := FormDataChromiumPdfOptions(tc.ctx.Context)
+
+			if !reflect.DeepEqual(actual, tc.expectedOptions) {
+				t.Fatalf("expected %+v but got: %+v", tc.expectedOptions, actual)
+			}
+
+			err := form.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestFormDataChromiumScreenshotOptions(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		ctx             *api.ContextMock
+		expectedOptions ScreenshotOptions
+	}{
+		{
+			scenario:        "no custom form fields",
+			ctx:             &api.ContextMock{Context: new(api.Context)},
+			expectedOptions: DefaultScreenshotOptions(),
+		},
+		{
+			scenario: "invalid format form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"format": {
+						"gif",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Format = ""
+				return options
+			}(),
+		},
+		{
+			scenario: "valid png format form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"format": {
+						"png",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Format = "png"
+				return options
+			}(),
+		},
+		{
+			scenario: "valid jpeg format form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"format": {
+						"jpeg",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Format = "jpeg"
+				return options
+			}(),
+		},
+		{
+			scenario: "valid webp format form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"format": {
+						"webp",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Format = "webp"
+				return options
+			}(),
+		},
+		{
+			scenario: "valid avif format form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"format": {
+						"avif",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Format = "avif"
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid quality form field (not an integer)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"quality": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Quality = 0
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid quality form field (< 0)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"quality": {
+						"-1",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Quality = 0
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid quality form field (> 100)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"quality": {
+						"101",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Quality = 0
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid quality form field (png format)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"quality": {
+						"50",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Quality = 0
+				return options
+			}(),
+		},
+		{
+			scenario: "valid quality form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"format": {
+						"jpeg",
+					},
+					"quality": {
+						"50",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Format = "jpeg"
+				options.Quality = 50
+				return options
+			}(),
+		},
+		{
+			scenario: "valid quality form field (webp format)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"format": {
+						"webp",
+					},
+					"quality": {
+						"80",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Format = "webp"
+				options.Quality = 80
+				return options
+			}(),
+		},
+		{
+			scenario: "custom form fields (Options & ScreenshotOptions)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"optimizeForSpeed": {
+						"true",
+					},
+					"emulatedMediaType": {
+						"screen",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.OptimizeForSpeed = true
+				options.EmulatedMediaType = "screen"
+				return options
+			}(),
+		},
+		{
+			scenario: "full page",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"fullPage": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.FullPage = true
+				return options
+			}(),
+		},
+		{
+			scenario: "selector",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"selector": {
+						`#widget`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Selector = "#widget"
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid selector form field (with fullPage)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"fullPage": {
+						`true`,
+					},
+					"selector": {
+						`#widget`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.FullPage = true
+				return options
+			}(),
+		},
+		{
+			scenario: "clip",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"clip": {
+						`{"x":10,"y":20,"width":300,"height":200}`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Clip = &ScreenshotClip{X: 10, Y: 20, Width: 300, Height: 200}
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid clip form field (not JSON)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"clip": {
+						`foo`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid clip form field (negative coordinates)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"clip": {
+						`{"x":-1,"y":0,"width":300,"height":200}`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid clip form field (empty area)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"clip": {
+						`{"x":0,"y":0,"width":0,"height":200}`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				return options
+			}(),
+		},
+		{
+			scenario: "invalid clip form field (with selector)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"selector": {
+						`#widget`,
+					},
+					"clip": {
+						`{"x":0,"y":0,"width":300,"height":200}`,
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Selector = "#widget"
+				return options
+			}(),
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			_, actual := FormDataChromiumScreenshotOptions(tc.ctx.Context)
+
+			if !reflect.DeepEqual(actual, tc.expectedOptions) {
+				t.Fatalf("expected %+v but got: %+v", tc.expectedOptions, actual)
+			}
+		})
+	}
+}
+
+func TestFormDataChromiumPdfFormats(t *testing.T) {
+	for _, tc := range []struct {
+		scenario           string
+		ctx                *api.ContextMock
+		expectedPdfFormats gotenberg.PdfFormats
+	}{
+		{
+			scenario:           "no custom form fields",
+			ctx:                &api.ContextMock{Context: new(api.Context)},
+			expectedPdfFormats: gotenberg.PdfFormats{},
+		},
+		{
+			scenario: "pdfa and pdfua form fields",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"pdfa": {
+						"foo",
+					},
+					"pdfua": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			expectedPdfFormats: gotenberg.PdfFormats{PdfA: "foo", PdfUa: true},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			actual := FormDataChromiumPdfFormats(tc.ctx.Context.FormData())
+
+			if !reflect.DeepEqual(actual, tc.expectedPdfFormats) {
+				t.Fatalf("expected %+v but got: %+v", tc.expectedPdfFormats, actual)
+			}
+		})
+	}
+}
+
+func TestConvertUrlRoute(t *testing.T) {
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		api                    Api
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+		expectHeaders          map[string]string
+	}{
+		{
+			scenario:               "missing mandatory url form field",
+			ctx:                    &api.ContextMock{Context: new(api.Context)},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "empty url form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "preferCssPageSize form field with explicit paper size",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+					"preferCssPageSize": {
+						"true",
+					},
+					"paperHeight": {
+						"8.3",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				if !options.PreferCssPageSize {
+					return errors.New("expected preferCssPageSize to be set")
+				}
+
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "error from Chromium",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return errors.New("foo")
+			}},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid session form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+					"session": {
+						"../foo",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with session",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+					"session": {
+						"dashboard",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				if options.Session != "dashboard" {
+					return fmt.Errorf("expected session 'dashboard' but got '%s'", options.Session)
+				}
+
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid form data: authPassword without authUsername",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com",
+					},
+					"authPassword": {
+						"secret",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: authUsername and authBearerToken",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com",
+					},
+					"authUsername": {
+						"john",
+					},
+					"authBearerToken": {
+						"token",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: authBearerToken with a relative URL",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+					"authBearerToken": {
+						"token",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with HTTP authentication",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com:443/app",
+					},
+					"authUsername": {
+						"john",
+					},
+					"authPassword": {
+						"secret",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				if options.HttpAuth == nil || *options.HttpAuth != (HttpAuth{Username: "john", Password: "secret"}) {
+					return fmt.Errorf("expected HTTP authentication of 'john' but got %+v", options.HttpAuth)
+				}
+
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with bearer token",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com:443/app",
+					},
+					"authBearerToken": {
+						"token",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				if len(options.ScopedExtraHttpHeaders) != 1 {
+					return fmt.Errorf("expected one scoped extra HTTP header but got %d", len(options.ScopedExtraHttpHeaders))
+				}
+
+				header := options.ScopedExtraHttpHeaders[0]
+				if header.Name != "Authorization" || header.Value != "Bearer token" {
+					return fmt.Errorf("expected 'Authorization: Bearer token' but got '%s: %s'", header.Name, header.Value)
+				}
+
+				if !header.Scope.MatchString("https://example.com/app") || header.Scope.MatchString("https://example.com.evil/") || header.Scope.MatchString("https://cdn.example.com/") {
+					return fmt.Errorf("expected a scope limited to 'https://example.com' but got '%s'", header.Scope)
+				}
+
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid form data: clientCertificateName and client certificate files",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				writeTestCertificate(t, dirPath, "client")
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"client.crt": fmt.Sprintf("%s/client.crt", dirPath),
+					"client.key": fmt.Sprintf("%s/client.key", dirPath),
+				})
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com",
+					},
+					"clientCertificateName": {
+						"internal",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: client.crt without client.key",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				writeTestCertificate(t, dirPath, "client")
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"client.crt": fmt.Sprintf("%s/client.crt", dirPath),
+				})
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: client.crt and client.key do not match",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				writeTestCertificate(t, dirPath, "client")
+				writeTestCertificate(t, dirPath, "other")
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"client.crt": fmt.Sprintf("%s/client.crt", dirPath),
+					"client.key": fmt.Sprintf("%s/other.key", dirPath),
+				})
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: clientCertificateName",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com",
+					},
+					"clientCertificateName": {
+						"../internal",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: clientCertificateName and unsupported proxyServer",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com",
+					},
+					"clientCertificateName": {
+						"internal",
+					},
+					"proxyServer": {
+						"socks4://proxy.example.com:1080",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with client certificate files",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				writeTestCertificate(t, dirPath, "client")
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"client.crt": fmt.Sprintf("%s/client.crt", dirPath),
+					"client.key": fmt.Sprintf("%s/client.key", dirPath),
+				})
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				if options.ClientCertificate == nil || len(options.ClientCertificate.Certificate) == 0 {
+					return errors.New("expected a client certificate but got none")
+				}
+
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with clientCertificateName",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"https://example.com",
+					},
+					"clientCertificateName": {
+						"internal",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				if options.ClientCertificateName != "internal" {
+					return fmt.Errorf("expected client certificate 'internal' but got '%s'", options.ClientCertificateName)
+				}
+
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with navigation info",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+					"captureNavigationInfo": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				if options.NavigationInfo == nil {
+					return errors.New("navigation info is nil")
+				}
+
+				*options.NavigationInfo = NavigationInfo{
+					FinalUrl:   "https://example.com/final",
+					HttpStatus: 200,
+					RedirectChain: []NavigationRedirect{
+						{Url: "foo", HttpStatus: 301},
+					},
+				}
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectHeaders: map[string]string{
+				"Gotenberg-Final-Url":      "https://example.com/final",
+				"Gotenberg-Http-Status":    "200",
+				"Gotenberg-Redirect-Chain": `[{"url":"foo","httpStatus":301}]`,
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(nil, rec)
+			c.Set("context", tc.ctx.Context)
+
+			err := convertUrlRoute(tc.api, nil).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+
+			for key, expect := range tc.expectHeaders {
+				actual := rec.Header().Get(key)
+				if actual != expect {
+					t.Errorf("expected '%s' as '%s' header but got '%s'", expect, key, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestScreenshotUrlRoute(t *testing.T) {
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		api                    Api
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+		expectHeaders          map[string]string
+	}{
+		{
+			scenario:               "missing mandatory url form field",
+			ctx:                    &api.ContextMock{Context: new(api.Context)},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "empty url form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"",
 					},
 				})
 				return ctx
@@ -444,7 +1937,7 @@ func TestConvertUrlRoute(t *testing.T) {
 				})
 				return ctx
 			}(),
-			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
 				return errors.New("foo")
 			}},
 			expectError:            true,
@@ -462,20 +1955,102 @@ func TestConvertUrlRoute(t *testing.T) {
 				})
 				return ctx
 			}(),
-			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid session form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+					"session": {
+						"../foo",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with session",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+					"session": {
+						"dashboard",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				if options.Session != "dashboard" {
+					return fmt.Errorf("expected session 'dashboard' but got '%s'", options.Session)
+				}
+
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with navigation info",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"url": {
+						"foo",
+					},
+					"captureNavigationInfo": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				if options.NavigationInfo == nil {
+					return errors.New("navigation info is nil")
+				}
+
+				*options.NavigationInfo = NavigationInfo{
+					FinalUrl:   "https://example.com/final",
+					HttpStatus: 200,
+					RedirectChain: []NavigationRedirect{
+						{Url: "foo", HttpStatus: 301},
+					},
+				}
 				return nil
 			}},
 			expectError:            false,
 			expectHttpError:        false,
 			expectOutputPathsCount: 1,
+			expectHeaders: map[string]string{
+				"Gotenberg-Final-Url":      "https://example.com/final",
+				"Gotenberg-Http-Status":    "200",
+				"Gotenberg-Redirect-Chain": `[{"url":"foo","httpStatus":301}]`,
+			},
 		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			tc.ctx.SetLogger(zap.NewNop())
-			c := echo.New().NewContext(nil, nil)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(nil, rec)
 			c.Set("context", tc.ctx.Context)
 
-			err := convertUrlRoute(tc.api, nil).Handler(c)
+			err := screenshotUrlRoute(tc.api).Handler(c)
 
 			if tc.expectError && err == nil {
 				t.Fatal("expected error but got none", err)
@@ -506,22 +2081,60 @@ func TestConvertUrlRoute(t *testing.T) {
 			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
 				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
 			}
+
+			for key, expect := range tc.expectHeaders {
+				actual := rec.Header().Get(key)
+				if actual != expect {
+					t.Errorf("expected '%s' as '%s' header but got '%s'", expect, key, actual)
+				}
+			}
 		})
 	}
 }
 
-func TestScreenshotUrlRoute(t *testing.T) {
+// writeTestZip writes a ZIP archive with the given files and contents.
+func writeTestZip(t *testing.T, path string, files map[string]string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		_, err = entry.Write([]byte(content))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func TestConvertHtmlRoute(t *testing.T) {
 	for _, tc := range []struct {
 		scenario               string
 		ctx                    *api.ContextMock
 		api                    Api
+		engine                 gotenberg.PdfEngine
 		expectError            bool
 		expectHttpError        bool
 		expectHttpStatus       int
 		expectOutputPathsCount int
 	}{
 		{
-			scenario:               "missing mandatory url form field",
+			scenario:               "missing mandatory index.html form file",
 			ctx:                    &api.ContextMock{Context: new(api.Context)},
 			expectError:            true,
 			expectHttpError:        true,
@@ -529,33 +2142,156 @@ func TestScreenshotUrlRoute(t *testing.T) {
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "empty url form field",
+			scenario: "error from Chromium",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"index.html": "/index.html",
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return errors.New("foo")
+			}},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "merge without HTML files besides the header and footer",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				for _, filename := range []string{"header.html", "footer.html"} {
+					err := os.WriteFile(fmt.Sprintf("%s/%s", dirPath, filename), []byte("<html></html>"), 0o600)
+					if err != nil {
+						t.Fatalf("expected no error but got: %v", err)
+					}
+				}
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"header.html": fmt.Sprintf("%s/header.html", dirPath),
+					"footer.html": fmt.Sprintf("%s/footer.html", dirPath),
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ZIP archive without index.html",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				writeTestZip(t, fmt.Sprintf("%s/site.zip", dirPath), map[string]string{
+					"page.html": "<html></html>",
+				})
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(dirPath)
+				ctx.SetFiles(map[string]string{
+					"site.zip": fmt.Sprintf("%s/site.zip", dirPath),
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "many archives",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"site.zip":   "/site.zip",
+					"page.mhtml": "/page.mhtml",
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (ZIP archive)",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				writeTestZip(t, fmt.Sprintf("%s/site.zip", dirPath), map[string]string{
+					"index.html":       "<html></html>",
+					"assets/style.css": "body { color: red; }",
+				})
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(dirPath)
+				ctx.SetFiles(map[string]string{
+					"site.zip": fmt.Sprintf("%s/site.zip", dirPath),
+				})
+				return ctx
+			}(),
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				path := strings.TrimPrefix(url, "file://")
+				if filepath.Base(path) != "index.html" {
+					return fmt.Errorf("expected index.html but got '%s'", url)
+				}
+
+				_, err := os.Stat(filepath.Join(filepath.Dir(path), "assets", "style.css"))
+				if err != nil {
+					return fmt.Errorf("expected assets alongside index.html: %w", err)
+				}
+
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success (MHTML archive)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
-				ctx.SetValues(map[string][]string{
-					"url": {
-						"",
-					},
+				ctx.SetFiles(map[string]string{
+					"page.mhtml": "/page.mhtml",
 				})
 				return ctx
 			}(),
-			expectError:            true,
-			expectHttpError:        true,
-			expectHttpStatus:       http.StatusBadRequest,
-			expectOutputPathsCount: 0,
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				if url != "file:///page.mhtml" {
+					return fmt.Errorf("expected 'file:///page.mhtml' but got '%s'", url)
+				}
+
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
 		},
 		{
-			scenario: "error from Chromium",
+			scenario: "error from PDF engine (merge)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"page1.html": "/page1.html",
+					"page2.html": "/page2.html",
+				})
 				ctx.SetValues(map[string][]string{
-					"url": {
-						"foo",
+					"merge": {
+						"true",
 					},
 				})
 				return ctx
 			}(),
-			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return nil
+			}},
+			engine: &gotenberg.PdfEngineMock{MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
 				return errors.New("foo")
 			}},
 			expectError:            true,
@@ -563,96 +2299,141 @@ func TestScreenshotUrlRoute(t *testing.T) {
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "success",
+			scenario: "success (merge)",
 			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				err := os.WriteFile(fmt.Sprintf("%s/header.html", dirPath), []byte("<html></html>"), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
 				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"page1.html":  "/page1.html",
+					"page2.html":  "/page2.html",
+					"header.html": fmt.Sprintf("%s/header.html", dirPath),
+					"style.css":   "/style.css",
+				})
 				ctx.SetValues(map[string][]string{
-					"url": {
-						"foo",
+					"merge": {
+						"true",
 					},
 				})
 				return ctx
 			}(),
-			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				if strings.HasSuffix(url, "header.html") {
+					return errors.New("header.html is not a page")
+				}
+
+				return nil
+			}},
+			engine: &gotenberg.PdfEngineMock{MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+				if len(inputPaths) != 2 {
+					return fmt.Errorf("expected 2 PDFs but got %d", len(inputPaths))
+				}
+
 				return nil
 			}},
 			expectError:            false,
 			expectHttpError:        false,
 			expectOutputPathsCount: 1,
 		},
-	} {
-		t.Run(tc.scenario, func(t *testing.T) {
-			tc.ctx.SetLogger(zap.NewNop())
-			c := echo.New().NewContext(nil, nil)
-			c.Set("context", tc.ctx.Context)
-
-			err := screenshotUrlRoute(tc.api).Handler(c)
-
-			if tc.expectError && err == nil {
-				t.Fatal("expected error but got none", err)
-			}
-
-			if !tc.expectError && err != nil {
-				t.Fatalf("expected no error but got: %v", err)
-			}
-
-			var httpErr api.HttpError
-			isHttpError := errors.As(err, &httpErr)
-
-			if tc.expectHttpError && !isHttpError {
-				t.Errorf("expected an HTTP error but got: %v", err)
-			}
-
-			if !tc.expectHttpError && isHttpError {
-				t.Errorf("expected no HTTP error but got one: %v", httpErr)
-			}
-
-			if err != nil && tc.expectHttpError && isHttpError {
-				status, _ := httpErr.HttpError()
-				if status != tc.expectHttpStatus {
-					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
-				}
-			}
-
-			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
-				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
-			}
-		})
-	}
-}
-
-func TestConvertHtmlRoute(t *testing.T) {
-	for _, tc := range []struct {
-		scenario               string
-		ctx                    *api.ContextMock
-		api                    Api
-		expectError            bool
-		expectHttpError        bool
-		expectHttpStatus       int
-		expectOutputPathsCount int
-	}{
 		{
-			scenario:               "missing mandatory index.html form file",
-			ctx:                    &api.ContextMock{Context: new(api.Context)},
-			expectError:            true,
-			expectHttpError:        true,
-			expectHttpStatus:       http.StatusBadRequest,
-			expectOutputPathsCount: 0,
+			scenario: "success (merge in upload order)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"cover.html":    "/cover.html",
+					"chapter.html":  "/chapter.html",
+					"appendix.html": "/appendix.html",
+				})
+				ctx.SetUploadOrder([]string{"cover.html", "chapter.html", "appendix.html"})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			api: func() Api {
+				var urls []string
+				return &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+					urls = append(urls, url)
+					expect := []string{"file:///cover.html", "file:///chapter.html", "file:///appendix.html"}[:len(urls)]
+					if !reflect.DeepEqual(urls, expect) {
+						return fmt.Errorf("expected %+v but got %+v", expect, urls)
+					}
+
+					return nil
+				}}
+			}(),
+			engine: &gotenberg.PdfEngineMock{MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
 		},
 		{
-			scenario: "error from Chromium",
+			scenario: "success (merge with order form field)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"index.html": "/index.html",
+					"cover.html":   "/cover.html",
+					"chapter.html": "/chapter.html",
+				})
+				ctx.SetUploadOrder([]string{"chapter.html", "cover.html"})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"order": {
+						`["cover.html", "chapter.html"]`,
+					},
 				})
 				return ctx
 			}(),
-			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
-				return errors.New("foo")
+			api: func() Api {
+				var urls []string
+				return &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+					urls = append(urls, url)
+					expect := []string{"file:///cover.html", "file:///chapter.html"}[:len(urls)]
+					if !reflect.DeepEqual(urls, expect) {
+						return fmt.Errorf("expected %+v but got %+v", expect, urls)
+					}
+
+					return nil
+				}}
+			}(),
+			engine: &gotenberg.PdfEngineMock{MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+				return nil
 			}},
-			expectError:            true,
+			expectError:            false,
 			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid order form field (merge)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"cover.html":   "/cover.html",
+					"chapter.html": "/chapter.html",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"order": {
+						`["cover.html"]`,
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
 			expectOutputPathsCount: 0,
 		},
 		{
@@ -677,7 +2458,7 @@ func TestConvertHtmlRoute(t *testing.T) {
 			c := echo.New().NewContext(nil, nil)
 			c.Set("context", tc.ctx.Context)
 
-			err := convertHtmlRoute(tc.api, nil).Handler(c)
+			err := convertHtmlRoute(tc.api, tc.engine).Handler(c)
 
 			if tc.expectError && err == nil {
 				t.Fatal("expected error but got none", err)
@@ -1217,6 +2998,95 @@ func TestScreenshotMarkdownRoute(t *testing.T) {
 	}
 }
 
+func TestDeleteSessionRoute(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		name             string
+		save             bool
+		expectError      bool
+		expectHttpError  bool
+		expectHttpStatus int
+	}{
+		{
+			scenario:         "invalid session name",
+			name:             "../foo",
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "unknown session",
+			name:             "foo",
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusNotFound,
+		},
+		{
+			scenario:         "success",
+			name:             "foo",
+			save:             true,
+			expectError:      false,
+			expectHttpStatus: http.StatusNoContent,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			fs := gotenberg.NewFileSystem()
+			sessions := newSessionStore(fs.NewDirPath(), time.Hour)
+
+			defer func() {
+				err := os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			if tc.save {
+				err := sessions.save(tc.name, sessionState{})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodDelete, "/", nil), rec)
+			c.SetParamNames("name")
+			c.SetParamValues(tc.name)
+
+			err := deleteSessionRoute(sessions).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if err == nil && rec.Code != tc.expectHttpStatus {
+				t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, rec.Code)
+			}
+		})
+	}
+}
+
 func TestConvertUrl(t *testing.T) {
 	for _, tc := range []struct {
 		scenario               string
@@ -1224,6 +3094,7 @@ func TestConvertUrl(t *testing.T) {
 		api                    Api
 		engine                 gotenberg.PdfEngine
 		pdfFormats             gotenberg.PdfFormats
+		reproducible           *time.Time
 		options                PdfOptions
 		expectError            bool
 		expectHttpError        bool
@@ -1231,22 +3102,91 @@ func TestConvertUrl(t *testing.T) {
 		expectOutputPathsCount int
 	}{
 		{
-			scenario: "ErrUrlNotAuthorized",
+			scenario: "ErrUrlNotAuthorized",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrUrlNotAuthorized
+			}},
+			options:                DefaultPdfOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusForbidden,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrOmitBackgroundWithoutPrintBackground",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrOmitBackgroundWithoutPrintBackground
+			}},
+			options:                DefaultPdfOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrInvalidEvaluationExpression (without waitForExpression form field)",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrInvalidEvaluationExpression
+			}},
+			options:                DefaultPdfOptions(),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrInvalidEvaluationExpression (with waitForExpression form field)",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrInvalidEvaluationExpression
+			}},
+			options: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.WaitForExpression = "foo"
+
+				return options
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrInvalidSelector",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
 			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
-				return ErrUrlNotAuthorized
+				return ErrInvalidSelector
+			}},
+			options: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.WaitForSelector = "#"
+
+				return options
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrInvalidPrinterSettings",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrInvalidPrinterSettings
 			}},
 			options:                DefaultPdfOptions(),
 			expectError:            true,
 			expectHttpError:        true,
-			expectHttpStatus:       http.StatusForbidden,
+			expectHttpStatus:       http.StatusBadRequest,
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "ErrOmitBackgroundWithoutPrintBackground",
+			scenario: "ErrPageRangesSyntaxError",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
 			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
-				return ErrOmitBackgroundWithoutPrintBackground
+				return ErrPageRangesSyntaxError
 			}},
 			options:                DefaultPdfOptions(),
 			expectError:            true,
@@ -1255,25 +3195,79 @@ func TestConvertUrl(t *testing.T) {
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "ErrInvalidEvaluationExpression (without waitForExpression form field)",
+			scenario: "ErrInvalidHttpStatusCode",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
 			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
-				return ErrInvalidEvaluationExpression
+				return ErrInvalidHttpStatusCode
 			}},
 			options:                DefaultPdfOptions(),
 			expectError:            true,
-			expectHttpError:        false,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusConflict,
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "ErrInvalidEvaluationExpression (with waitForExpression form field)",
+			scenario: "ErrInvalidResourceHttpStatusCode",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
 			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
-				return ErrInvalidEvaluationExpression
+				return ErrInvalidResourceHttpStatusCode
+			}},
+			options:                DefaultPdfOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusConflict,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrResourceLoadingFailed",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrResourceLoadingFailed
+			}},
+			options:                DefaultPdfOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusConflict,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrConsoleExceptions",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrConsoleExceptions
+			}},
+			options:                DefaultPdfOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusConflict,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrProxyServerNotAllowed",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrProxyServerNotAllowed
 			}},
 			options: func() PdfOptions {
 				options := DefaultPdfOptions()
-				options.WaitForExpression = "foo"
+				options.Proxy = &Proxy{Server: "http://proxy.example.com:3128"}
+
+				return options
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusForbidden,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrClientCertificateNotFound",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrClientCertificateNotFound
+			}},
+			options: func() PdfOptions {
+				options := DefaultPdfOptions()
+				options.ClientCertificateName = "internal"
 
 				return options
 			}(),
@@ -1283,10 +3277,10 @@ func TestConvertUrl(t *testing.T) {
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "ErrInvalidPrinterSettings",
+			scenario: "ErrSessionsNotAllowed",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
 			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
-				return ErrInvalidPrinterSettings
+				return ErrSessionsNotAllowed
 			}},
 			options:                DefaultPdfOptions(),
 			expectError:            true,
@@ -1295,10 +3289,10 @@ func TestConvertUrl(t *testing.T) {
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "ErrPageRangesSyntaxError",
+			scenario: "ErrNetworkOffline",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
 			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
-				return ErrPageRangesSyntaxError
+				return ErrNetworkOffline
 			}},
 			options:                DefaultPdfOptions(),
 			expectError:            true,
@@ -1307,27 +3301,39 @@ func TestConvertUrl(t *testing.T) {
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "ErrInvalidHttpStatusCode",
+			scenario: "ErrTimeoutAboveMaximum",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
 			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
-				return ErrInvalidHttpStatusCode
+				return ErrTimeoutAboveMaximum
 			}},
 			options:                DefaultPdfOptions(),
 			expectError:            true,
 			expectHttpError:        true,
-			expectHttpStatus:       http.StatusConflict,
+			expectHttpStatus:       http.StatusBadRequest,
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "ErrConsoleExceptions",
+			scenario: "ErrNavigationTimeout",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
 			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
-				return ErrConsoleExceptions
+				return ErrNavigationTimeout
 			}},
 			options:                DefaultPdfOptions(),
 			expectError:            true,
 			expectHttpError:        true,
-			expectHttpStatus:       http.StatusConflict,
+			expectHttpStatus:       http.StatusGatewayTimeout,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrNetworkIdleTimeout",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return ErrNetworkIdleTimeout
+			}},
+			options:                DefaultPdfOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusGatewayTimeout,
 			expectOutputPathsCount: 0,
 		},
 		{
@@ -1413,10 +3419,51 @@ func TestConvertUrl(t *testing.T) {
 			expectHttpError:        false,
 			expectOutputPathsCount: 1,
 		},
+		{
+			scenario: "PDF engine normalize error",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return nil
+			}},
+			engine: &gotenberg.PdfEngineMock{NormalizeMock: func(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+				return errors.New("foo")
+			}},
+			reproducible:           &time.Time{},
+			options:                DefaultPdfOptions(),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "reproducible with PDF formats",
+			ctx:                    &api.ContextMock{Context: new(api.Context)},
+			pdfFormats:             gotenberg.PdfFormats{PdfA: gotenberg.PdfA3b},
+			reproducible:           &time.Time{},
+			options:                DefaultPdfOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with reproducible",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return nil
+			}},
+			engine: &gotenberg.PdfEngineMock{NormalizeMock: func(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+				return nil
+			}},
+			reproducible:           &time.Time{},
+			options:                DefaultPdfOptions(),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			tc.ctx.SetLogger(zap.NewNop())
-			err := convertUrl(tc.ctx.Context, tc.api, tc.engine, "", tc.pdfFormats, tc.options)
+			err := convertUrl(tc.ctx.Context, tc.api, tc.engine, "", tc.pdfFormats, tc.reproducible, tc.options)
 
 			if tc.expectError && err == nil {
 				t.Fatal("expected error but got none", err)
@@ -1502,6 +3549,81 @@ func TestScreenshotUrl(t *testing.T) {
 			expectHttpStatus:       http.StatusBadRequest,
 			expectOutputPathsCount: 0,
 		},
+		{
+			scenario: "ErrInvalidSelector",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrInvalidSelector
+			}},
+			options: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.WaitForSelector = "#"
+
+				return options
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrAvifEncoderNotAvailable",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrAvifEncoderNotAvailable
+			}},
+			options: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Format = "avif"
+
+				return options
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrScreenshotHeightExceeded",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrScreenshotHeightExceeded
+			}},
+			options:                DefaultScreenshotOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrElementNotFound",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrElementNotFound
+			}},
+			options: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Selector = "#widget"
+
+				return options
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrFullPageWebpTooTall",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrFullPageWebpTooTall
+			}},
+			options:                DefaultScreenshotOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
 		{
 			scenario: "ErrInvalidHttpStatusCode",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
@@ -1514,6 +3636,30 @@ func TestScreenshotUrl(t *testing.T) {
 			expectHttpStatus:       http.StatusConflict,
 			expectOutputPathsCount: 0,
 		},
+		{
+			scenario: "ErrInvalidResourceHttpStatusCode",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrInvalidResourceHttpStatusCode
+			}},
+			options:                DefaultScreenshotOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusConflict,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrResourceLoadingFailed",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrResourceLoadingFailed
+			}},
+			options:                DefaultScreenshotOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusConflict,
+			expectOutputPathsCount: 0,
+		},
 		{
 			scenario: "ErrConsoleExceptions",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
@@ -1526,6 +3672,52 @@ func TestScreenshotUrl(t *testing.T) {
 			expectHttpStatus:       http.StatusConflict,
 			expectOutputPathsCount: 0,
 		},
+		{
+			scenario: "ErrProxyServerNotAllowed",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrProxyServerNotAllowed
+			}},
+			options: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Proxy = &Proxy{Server: "http://proxy.example.com:3128"}
+
+				return options
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusForbidden,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrClientCertificateNotFound",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrClientCertificateNotFound
+			}},
+			options: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.ClientCertificateName = "internal"
+
+				return options
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrSessionsNotAllowed",
+			ctx:      &api.ContextMock{Context: new(api.Context)},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return ErrSessionsNotAllowed
+			}},
+			options:                DefaultScreenshotOptions(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
 		{
 			scenario: "error from Chromium",
 			ctx:      &api.ContextMock{Context: new(api.Context)},
@@ -0,0 +1,277 @@
+package chromium
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"go.uber.org/zap"
+)
+
+// maxSessionSweepInterval is the maximum interval between two removals of
+// the expired sessions.
+const maxSessionSweepInterval = time.Duration(1) * time.Minute
+
+// sessionNameRegexp matches the valid session names.
+var sessionNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// sessionState is the state of a named session, i.e., the cookies of its
+// browser context and the local storage of the origins it visited.
+type sessionState struct {
+	Cookies      []*network.CookieParam       `json:"cookies"`
+	LocalStorage map[string]map[string]string `json:"localStorage"`
+}
+
+// sessionLock is the lock of a session, with the number of requests which
+// hold or wait for it.
+type sessionLock struct {
+	sync.Mutex
+	refs int
+}
+
+// sessionStore persists the state of the named sessions, one file per
+// session. A session expires if it has not been used for the TTL.
+type sessionStore struct {
+	dirPath string
+	ttl     time.Duration
+
+	locksMu sync.Mutex
+	locks   map[string]*sessionLock
+
+	ticker *time.Ticker
+	done   chan struct{}
+}
+
+func newSessionStore(dirPath string, ttl time.Duration) *sessionStore {
+	return &sessionStore{
+		dirPath: dirPath,
+		ttl:     ttl,
+		locks:   make(map[string]*sessionLock),
+	}
+}
+
+// key returns the key of a session, i.e., the hash of its name.
+func (s *sessionStore) key(name string) string {
+	hash := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(hash[:])
+}
+
+// path returns the path of the file of a session. The name is hashed, so that
+// it cannot escape the directory of the store.
+func (s *sessionStore) path(name string) string {
+	return fmt.Sprintf("%s/%s.json", s.dirPath, s.key(name))
+}
+
+// lock serializes the requests of a session, as each one loads then saves its
+// state. It returns the function to unlock the session.
+func (s *sessionStore) lock(name string) func() {
+	return s.lockKey(s.key(name))
+}
+
+// lockKey locks a session by its key. The lock is forgotten once no request
+// holds nor waits for it, so that the locks do not pile up.
+func (s *sessionStore) lockKey(key string) func() {
+	s.locksMu.Lock()
+	l, ok := s.locks[key]
+	if !ok {
+		l = new(sessionLock)
+		s.locks[key] = l
+	}
+	l.refs++
+	s.locksMu.Unlock()
+
+	l.Lock()
+
+	return func() {
+		l.Unlock()
+
+		s.locksMu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(s.locks, key)
+		}
+		s.locksMu.Unlock()
+	}
+}
+
+// load returns the state of a session. An unknown or expired session has an
+// empty state.
+func (s *sessionStore) load(name string) (sessionState, error) {
+	path := s.path(name)
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sessionState{}, nil
+	}
+	if err != nil {
+		return sessionState{}, fmt.Errorf("stat session file: %w", err)
+	}
+
+	if s.ttl > 0 && time.Since(info.ModTime()) > s.ttl {
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return sessionState{}, fmt.Errorf("remove expired session file: %w", err)
+		}
+
+		return sessionState{}, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return sessionState{}, fmt.Errorf("read session file: %w", err)
+	}
+
+	var state sessionState
+	err = json.Unmarshal(b, &state)
+	if err != nil {
+		return sessionState{}, fmt.Errorf("unmarshal session state: %w", err)
+	}
+
+	return state, nil
+}
+
+// save writes the state of a session, which also extends its TTL.
+func (s *sessionStore) save(name string, state sessionState) error {
+	err := os.MkdirAll(s.dirPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("create sessions directory: %w", err)
+	}
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal session state: %w", err)
+	}
+
+	// A partially written file would lose the session.
+	path := s.path(name)
+	tmpPath := fmt.Sprintf("%s.tmp", path)
+
+	err = os.WriteFile(tmpPath, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write session file: %w", err)
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return fmt.Errorf("rename session file: %w", err)
+	}
+
+	return nil
+}
+
+// remove invalidates a session. It returns false if the session is unknown
+// or expired.
+func (s *sessionStore) remove(name string) (bool, error) {
+	unlock := s.lock(name)
+	defer unlock()
+
+	path := s.path(name)
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("stat session file: %w", err)
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		return false, fmt.Errorf("remove session file: %w", err)
+	}
+
+	return s.ttl == 0 || time.Since(info.ModTime()) <= s.ttl, nil
+}
+
+// sweep removes the files of the expired sessions, which would otherwise
+// only be removed when a request uses them again.
+func (s *sessionStore) sweep() error {
+	entries, err := os.ReadDir(s.dirPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read sessions directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		key, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		err = func() error {
+			// A request may use the session in the meantime.
+			unlock := s.lockKey(key)
+			defer unlock()
+
+			path := fmt.Sprintf("%s/%s", s.dirPath, entry.Name())
+
+			info, err := os.Stat(path)
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("stat session file: %w", err)
+			}
+
+			if time.Since(info.ModTime()) <= s.ttl {
+				return nil
+			}
+
+			err = os.Remove(path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("remove expired session file: %w", err)
+			}
+
+			return nil
+		}()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// start removes the expired sessions periodically, if there is a TTL.
+func (s *sessionStore) start(logger *zap.Logger) {
+	if s.ttl <= 0 {
+		return
+	}
+
+	s.ticker = time.NewTicker(min(s.ttl, maxSessionSweepInterval))
+	s.done = make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-s.done:
+				return
+			case <-s.ticker.C:
+				err := s.sweep()
+				if err != nil {
+					logger.Error(fmt.Sprintf("remove expired sessions: %s", err))
+				}
+			}
+		}
+	}()
+}
+
+// stop stops the periodic removal of the expired sessions.
+func (s *sessionStore) stop() {
+	if s.ticker == nil {
+		return
+	}
+
+	s.ticker.Stop()
+	close(s.done)
+}
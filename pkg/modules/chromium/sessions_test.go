@@ -0,0 +1,243 @@
+package chromium
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestSessionStore_Path(t *testing.T) {
+	store := newSessionStore("/foo", time.Hour)
+
+	if store.path("bar") == store.path("baz") {
+		t.Error("expected different paths for different sessions")
+	}
+
+	if store.path("../../etc/passwd") == "/foo/../../etc/passwd.json" {
+		t.Error("expected a hashed path")
+	}
+}
+
+func TestSessionStore_LoadSave(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ttl         time.Duration
+		age         time.Duration
+		state       *sessionState
+		expectState sessionState
+	}{
+		{
+			scenario:    "unknown session",
+			ttl:         time.Hour,
+			expectState: sessionState{},
+		},
+		{
+			scenario: "known session",
+			ttl:      time.Hour,
+			state: &sessionState{
+				Cookies:      []*network.CookieParam{{Name: "foo", Value: "bar", Domain: "example.com"}},
+				LocalStorage: map[string]map[string]string{"https://example.com": {"foo": "bar"}},
+			},
+			expectState: sessionState{
+				Cookies:      []*network.CookieParam{{Name: "foo", Value: "bar", Domain: "example.com"}},
+				LocalStorage: map[string]map[string]string{"https://example.com": {"foo": "bar"}},
+			},
+		},
+		{
+			scenario:    "expired session",
+			ttl:         time.Hour,
+			age:         2 * time.Hour,
+			state:       &sessionState{Cookies: []*network.CookieParam{{Name: "foo", Value: "bar", Domain: "example.com"}}},
+			expectState: sessionState{},
+		},
+		{
+			scenario: "session without TTL",
+			ttl:      0,
+			age:      2 * time.Hour,
+			state:    &sessionState{Cookies: []*network.CookieParam{{Name: "foo", Value: "bar", Domain: "example.com"}}},
+			expectState: sessionState{
+				Cookies: []*network.CookieParam{{Name: "foo", Value: "bar", Domain: "example.com"}},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			fs := gotenberg.NewFileSystem()
+			store := newSessionStore(fs.NewDirPath(), tc.ttl)
+
+			defer func() {
+				err := os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			if tc.state != nil {
+				err := store.save("foo", *tc.state)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				if tc.age > 0 {
+					modTime := time.Now().Add(-tc.age)
+					err = os.Chtimes(store.path("foo"), modTime, modTime)
+					if err != nil {
+						t.Fatalf("expected no error but got: %v", err)
+					}
+				}
+			}
+
+			state, err := store.load("foo")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(state, tc.expectState) {
+				t.Errorf("expected %+v but got: %+v", tc.expectState, state)
+			}
+		})
+	}
+}
+
+func TestSessionStore_Remove(t *testing.T) {
+	fs := gotenberg.NewFileSystem()
+	store := newSessionStore(fs.NewDirPath(), time.Hour)
+
+	defer func() {
+		err := os.RemoveAll(fs.WorkingDirPath())
+		if err != nil {
+			t.Fatalf("expected no error while cleaning up but got: %v", err)
+		}
+	}()
+
+	removed, err := store.remove("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if removed {
+		t.Error("expected unknown session not to be removed")
+	}
+
+	err = store.save("foo", sessionState{})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	removed, err = store.remove("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !removed {
+		t.Error("expected session to be removed")
+	}
+
+	_, err = os.Stat(store.path("foo"))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected session file to be removed, but got: %v", err)
+	}
+}
+
+func TestSessionStore_Lock(t *testing.T) {
+	store := newSessionStore("/foo", time.Hour)
+	unlock := store.lock("foo")
+
+	locked := make(chan struct{})
+	go func() {
+		defer store.lock("foo")()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("expected the session to be locked")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Other sessions are not locked.
+	store.lock("bar")()
+
+	unlock()
+
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Fatal("expected the session to be unlocked")
+	}
+}
+
+func TestSessionStore_LockForgotten(t *testing.T) {
+	store := newSessionStore("/foo", time.Hour)
+
+	unlock := store.lock("foo")
+	if len(store.locks) != 1 {
+		t.Errorf("expected 1 lock, but got %d", len(store.locks))
+	}
+
+	unlock()
+	if len(store.locks) != 0 {
+		t.Errorf("expected the unused lock to be forgotten, but got %d locks", len(store.locks))
+	}
+}
+
+func TestSessionStore_Sweep(t *testing.T) {
+	store := newSessionStore(t.TempDir(), time.Hour)
+
+	for _, name := range []string{"expired", "active"} {
+		err := store.save(name, sessionState{})
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	expired := time.Now().Add(-2 * time.Hour)
+	err := os.Chtimes(store.path("expired"), expired, expired)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = store.sweep()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(store.path("expired"))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected expired session file to be removed, but got: %v", err)
+	}
+
+	_, err = os.Stat(store.path("active"))
+	if err != nil {
+		t.Errorf("expected active session file to be kept, but got: %v", err)
+	}
+
+	if len(store.locks) != 0 {
+		t.Errorf("expected no locks, but got %d", len(store.locks))
+	}
+}
+
+func TestSessionStore_StartStop(t *testing.T) {
+	store := newSessionStore(t.TempDir(), 0)
+
+	// No TTL, nothing to start nor to stop.
+	store.start(zap.NewNop())
+	store.stop()
+
+	if store.ticker != nil {
+		t.Error("expected no ticker")
+	}
+
+	store.ttl = time.Hour
+	store.start(zap.NewNop())
+	store.stop()
+
+	if store.ticker == nil {
+		t.Error("expected a ticker")
+	}
+}
@@ -2,15 +2,29 @@ package chromium
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
 	"os"
+	"strings"
 	"time"
 
+	cdpbrowser "github.com/chromedp/cdproto/browser"
 	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/css"
+	"github.com/chromedp/cdproto/dom"
 	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/cdproto/storage"
 	"github.com/chromedp/chromedp"
 	"go.uber.org/zap"
 )
@@ -92,45 +106,263 @@ func printToPdfActionFunc(logger *zap.Logger, outputPath string, options PdfOpti
 	}
 }
 
-func captureScreenshotActionFunc(logger *zap.Logger, outputPath string, options ScreenshotOptions) chromedp.ActionFunc {
+// screenshotTileHeight is the maximum height, in pixels, of a single capture.
+// Chromium cannot capture a surface taller than 16384 pixels.
+const screenshotTileHeight = 8192
+
+// scrollToEndScript scrolls the page by steps of the viewport's height, so
+// that its lazy-loaded content loads, then scrolls back to the top.
+const scrollToEndScript = `
+(async (maxHeight) => {
+  const delay = (ms) => new Promise((resolve) => setTimeout(resolve, ms));
+  const step = Math.max(window.innerHeight, 100);
+  for (let y = 0; y < document.documentElement.scrollHeight && y < maxHeight; y += step) {
+    window.scrollTo(0, y);
+    await delay(100);
+  }
+  window.scrollTo(0, 0);
+  await delay(100);
+  return true;
+})(%d)
+`
+
+// unstickScript renders the fixed and sticky elements at their place in the
+// document, so that they do not repeat in each part of a stitched screenshot.
+const unstickScript = `
+(() => {
+  for (const element of document.querySelectorAll('*')) {
+    const position = window.getComputedStyle(element).position;
+    if (position === 'fixed') {
+      element.style.setProperty('position', 'absolute', 'important');
+    } else if (position === 'sticky') {
+      element.style.setProperty('position', 'relative', 'important');
+    }
+  }
+  return true;
+})()
+`
+
+func captureScreenshotActionFunc(logger *zap.Logger, outputPath string, options ScreenshotOptions, disableJavaScript bool, maxHeight int64) chromedp.ActionFunc {
 	return func(ctx context.Context) error {
-		captureScreenshot := page.CaptureScreenshot().
-			WithCaptureBeyondViewport(true).
-			WithFromSurface(true).
-			WithOptimizeForSpeed(options.OptimizeForSpeed).
-			WithFormat(page.CaptureScreenshotFormat(options.Format))
+		format := options.Format
+		if format == "avif" {
+			// The PNG screenshot is transcoded to AVIF afterward.
+			format = "png"
+		}
 
-		if options.Format == "jpeg" {
-			captureScreenshot = captureScreenshot.
-				WithQuality(int64(options.Quality))
+		newCaptureScreenshot := func(format string) *page.CaptureScreenshotParams {
+			captureScreenshot := page.CaptureScreenshot().
+				WithCaptureBeyondViewport(true).
+				WithFromSurface(true).
+				WithOptimizeForSpeed(options.OptimizeForSpeed).
+				WithFormat(page.CaptureScreenshotFormat(format))
+
+			if format == "jpeg" || format == "webp" {
+				captureScreenshot = captureScreenshot.
+					WithQuality(int64(options.Quality))
+			}
+
+			return captureScreenshot
 		}
 
-		logger.Debug(fmt.Sprintf("capture screenshot with: %+v", captureScreenshot))
+		if !options.FullPage && options.Selector == "" && options.Clip == nil {
+			captureScreenshot := newCaptureScreenshot(format)
+			logger.Debug(fmt.Sprintf("capture screenshot with: %+v", captureScreenshot))
 
-		buffer, err := captureScreenshot.Do(ctx)
-		if err != nil {
-			return fmt.Errorf("capture screenshot: %w", err)
+			buffer, err := captureScreenshot.Do(ctx)
+			if err != nil {
+				return fmt.Errorf("capture screenshot: %w", err)
+			}
+
+			return writeScreenshot(logger, outputPath, buffer)
 		}
 
-		file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY, 0o600)
-		if err != nil {
-			return fmt.Errorf("open output path: %w", err)
+		var (
+			area ScreenshotClip
+			err  error
+		)
+
+		switch {
+		case options.Selector != "":
+			area, err = elementArea(ctx, options.Selector)
+			if err != nil {
+				return err
+			}
+
+			logger.Debug(fmt.Sprintf("element '%s' at %+v", options.Selector, area))
+		case options.Clip != nil:
+			area = *options.Clip
+		default:
+			if disableJavaScript {
+				logger.Debug("JavaScript disabled, skipping scroll for lazy-loaded content")
+			} else {
+				logger.Debug("scroll to the end of the page for lazy-loaded content")
+
+				var scrolled bool
+				err := chromedp.Evaluate(fmt.Sprintf(scrollToEndScript, maxHeight), &scrolled, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+					return p.WithAwaitPromise(true)
+				}).Do(ctx)
+				if err != nil {
+					return fmt.Errorf("scroll to the end of the page: %w", err)
+				}
+			}
+
+			_, _, _, _, _, contentSize, err := page.GetLayoutMetrics().Do(ctx)
+			if err != nil {
+				return fmt.Errorf("get layout metrics: %w", err)
+			}
+
+			area = ScreenshotClip{Width: math.Ceil(contentSize.Width), Height: math.Ceil(contentSize.Height)}
+
+			logger.Debug(fmt.Sprintf("full page of %dx%d pixels", int64(area.Width), int64(area.Height)))
 		}
 
-		defer func() {
-			err = file.Close()
+		width := int64(math.Ceil(area.Width))
+		height := int64(math.Ceil(area.Height))
+
+		if height > maxHeight {
+			return fmt.Errorf("height of %d pixels, more than %d pixels: %w", height, maxHeight, ErrScreenshotHeightExceeded)
+		}
+
+		clip := func(y, h int64) *page.Viewport {
+			return &page.Viewport{
+				X:      area.X,
+				Y:      area.Y + float64(y),
+				Width:  float64(width),
+				Height: float64(h),
+				Scale:  1,
+			}
+		}
+
+		if height <= screenshotTileHeight {
+			buffer, err := newCaptureScreenshot(format).WithClip(clip(0, height)).Do(ctx)
 			if err != nil {
-				logger.Error(fmt.Sprintf("close output path: %s", err))
+				return fmt.Errorf("capture screenshot: %w", err)
 			}
-		}()
 
-		_, err = file.Write(buffer)
+			return writeScreenshot(logger, outputPath, buffer)
+		}
+
+		// Go cannot encode WebP images.
+		if format == "webp" {
+			return fmt.Errorf("page height of %d pixels, more than %d pixels: %w", height, screenshotTileHeight, ErrFullPageWebpTooTall)
+		}
+
+		if !disableJavaScript {
+			var unstuck bool
+			err = chromedp.Evaluate(unstickScript, &unstuck).Do(ctx)
+			if err != nil {
+				return fmt.Errorf("unstick fixed and sticky elements: %w", err)
+			}
+		}
+
+		var canvas *image.RGBA
+		for y := int64(0); y < height; y += screenshotTileHeight {
+			h := min(screenshotTileHeight, height-y)
+
+			logger.Debug(fmt.Sprintf("capture screenshot part from %d to %d pixels", y, y+h))
+
+			buffer, err := newCaptureScreenshot("png").WithClip(clip(y, h)).Do(ctx)
+			if err != nil {
+				return fmt.Errorf("capture screenshot part: %w", err)
+			}
+
+			part, err := png.Decode(bytes.NewReader(buffer))
+			if err != nil {
+				return fmt.Errorf("decode screenshot part: %w", err)
+			}
+
+			// The parts may have more pixels than their clip, according to
+			// the device scale factor.
+			ratio := float64(part.Bounds().Dx()) / float64(width)
+			if canvas == nil {
+				canvas = image.NewRGBA(image.Rect(0, 0, part.Bounds().Dx(), int(math.Ceil(float64(height)*ratio))))
+			}
+
+			offset := int(math.Round(float64(y) * ratio))
+			draw.Draw(canvas, part.Bounds().Add(image.Pt(0, offset)), part, part.Bounds().Min, draw.Src)
+		}
+
+		var stitched bytes.Buffer
+		if format == "jpeg" {
+			err = jpeg.Encode(&stitched, canvas, &jpeg.Options{Quality: options.Quality})
+		} else {
+			err = png.Encode(&stitched, canvas)
+		}
 		if err != nil {
-			return fmt.Errorf("write result to output path: %w", err)
+			return fmt.Errorf("encode stitched screenshot: %w", err)
 		}
 
-		return nil
+		return writeScreenshot(logger, outputPath, stitched.Bytes())
+	}
+}
+
+// elementArea returns the area of the border box of the first element which
+// matches the selector, relative to the top left corner of the page. It uses
+// the DOM domain, so that it works even if JavaScript is disabled.
+func elementArea(ctx context.Context, selector string) (ScreenshotClip, error) {
+	root, err := dom.GetDocument().Do(ctx)
+	if err != nil {
+		return ScreenshotClip{}, fmt.Errorf("get document: %w", err)
+	}
+
+	nodeId, err := dom.QuerySelector(root.NodeID, selector).Do(ctx)
+	if err != nil {
+		return ScreenshotClip{}, fmt.Errorf("query selector '%s': %v: %w", selector, err, ErrElementNotFound)
+	}
+
+	if nodeId == 0 {
+		return ScreenshotClip{}, fmt.Errorf("no element matches '%s': %w", selector, ErrElementNotFound)
+	}
+
+	// An element which is not rendered, e.g., with "display: none", has no
+	// box model.
+	boxModel, err := dom.GetBoxModel().WithNodeID(nodeId).Do(ctx)
+	if err != nil {
+		return ScreenshotClip{}, fmt.Errorf("get box model of '%s': %v: %w", selector, err, ErrElementNotFound)
+	}
+
+	// The quad is relative to the viewport.
+	_, _, _, _, visualViewport, _, err := page.GetLayoutMetrics().Do(ctx)
+	if err != nil {
+		return ScreenshotClip{}, fmt.Errorf("get layout metrics: %w", err)
+	}
+
+	quad := boxModel.Border
+	minX, maxX := math.Min(math.Min(quad[0], quad[2]), math.Min(quad[4], quad[6])), math.Max(math.Max(quad[0], quad[2]), math.Max(quad[4], quad[6]))
+	minY, maxY := math.Min(math.Min(quad[1], quad[3]), math.Min(quad[5], quad[7])), math.Max(math.Max(quad[1], quad[3]), math.Max(quad[5], quad[7]))
+
+	if maxX-minX <= 0 || maxY-minY <= 0 {
+		return ScreenshotClip{}, fmt.Errorf("element '%s' is empty: %w", selector, ErrElementNotFound)
+	}
+
+	return ScreenshotClip{
+		X:      minX + visualViewport.PageX,
+		Y:      minY + visualViewport.PageY,
+		Width:  maxX - minX,
+		Height: maxY - minY,
+	}, nil
+}
+
+func writeScreenshot(logger *zap.Logger, outputPath string, buffer []byte) error {
+	file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open output path: %w", err)
+	}
+
+	defer func() {
+		err = file.Close()
+		if err != nil {
+			logger.Error(fmt.Sprintf("close output path: %s", err))
+		}
+	}()
+
+	_, err = file.Write(buffer)
+	if err != nil {
+		return fmt.Errorf("write result to output path: %w", err)
 	}
+
+	return nil
 }
 
 func clearCacheActionFunc(logger *zap.Logger, clear bool) chromedp.ActionFunc {
@@ -197,13 +429,16 @@ func extraHttpHeadersActionFunc(logger *zap.Logger, extraHttpHeaders map[string]
 			return nil
 		}
 
-		logger.Debug(fmt.Sprintf("extra HTTP headers: %+v", extraHttpHeaders))
-
+		// The values may hold credentials, so that only the names are logged.
+		names := make([]string, 0, len(extraHttpHeaders))
 		headers := make(network.Headers, len(extraHttpHeaders))
 		for key, value := range extraHttpHeaders {
+			names = append(names, key)
 			headers[key] = value
 		}
 
+		logger.Debug(fmt.Sprintf("extra HTTP headers: %s", strings.Join(names, ", ")))
+
 		err := network.SetExtraHTTPHeaders(headers).Do(ctx)
 		if err == nil {
 			return nil
@@ -213,38 +448,120 @@ func extraHttpHeadersActionFunc(logger *zap.Logger, extraHttpHeaders map[string]
 	}
 }
 
-func navigateActionFunc(logger *zap.Logger, url string, skipNetworkIdleEvent bool) chromedp.ActionFunc {
+func emulateNetworkConditionsActionFunc(logger *zap.Logger, conditions *NetworkConditions) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if conditions == nil {
+			logger.Debug("no emulated network conditions")
+			return nil
+		}
+
+		logger.Debug(fmt.Sprintf("emulate network conditions: %+v", *conditions))
+
+		// Chromium disables the throttling with -1.
+		throughput := func(value float64) float64 {
+			if value == 0 {
+				return -1
+			}
+
+			return value
+		}
+
+		err := network.EmulateNetworkConditions(
+			conditions.Offline,
+			conditions.Latency,
+			throughput(conditions.DownloadThroughput),
+			throughput(conditions.UploadThroughput),
+		).Do(ctx)
+		if err == nil {
+			return nil
+		}
+
+		return fmt.Errorf("emulate network conditions: %w", err)
+	}
+}
+
+func navigateActionFunc(logger *zap.Logger, url string, skipNetworkIdleEvent bool, navigationTimeout, networkIdleTimeout time.Duration) chromedp.ActionFunc {
 	return func(ctx context.Context) error {
 		logger.Debug(fmt.Sprintf("navigate to '%s'", url))
 
-		_, _, _, err := page.Navigate(url).Do(ctx)
+		var networkIdle <-chan struct{}
+		if !skipNetworkIdleEvent {
+			// The event may be fired right after the load event: we listen
+			// for it beforehand.
+			listenCtx, listenCancel := context.WithCancel(ctx)
+			defer listenCancel()
+
+			networkIdle = listenForEventNetworkIdle(listenCtx)
+		}
+
+		navigationCtx, navigationCancel := withOptionalTimeout(ctx, navigationTimeout)
+		defer navigationCancel()
+
+		_, _, errorText, err := page.Navigate(url).Do(navigationCtx)
 		if err != nil {
+			if isTimeoutExceeded(ctx, navigationCtx) {
+				return fmt.Errorf("navigate to '%s' within %s: %w", url, navigationTimeout, ErrNavigationTimeout)
+			}
+
 			return fmt.Errorf("navigate to '%s': %w", url, err)
 		}
 
-		waitFunc := []func() error{
-			waitForEventDomContentEventFired(ctx, logger),
-			waitForEventLoadEventFired(ctx, logger),
-			waitForEventLoadingFinished(ctx, logger),
+		// Otherwise, Chromium renders its own error page.
+		if errorText == "net::ERR_INTERNET_DISCONNECTED" {
+			return fmt.Errorf("navigate to '%s': %w", url, ErrNetworkOffline)
 		}
 
-		if !skipNetworkIdleEvent {
-			waitFunc = append(waitFunc, waitForEventNetworkIdle(ctx, logger))
-		} else {
+		err = runBatch(
+			navigationCtx,
+			waitForEventDomContentEventFired(navigationCtx, logger),
+			waitForEventLoadEventFired(navigationCtx, logger),
+			waitForEventLoadingFinished(navigationCtx, logger),
+		)
+		if err != nil {
+			if isTimeoutExceeded(ctx, navigationCtx) {
+				return fmt.Errorf("load '%s' within %s: %w", url, navigationTimeout, ErrNavigationTimeout)
+			}
+
+			return fmt.Errorf("wait for events: %w", err)
+		}
+
+		if skipNetworkIdleEvent {
 			logger.Debug("skipping network idle event")
+			return nil
 		}
 
-		err = runBatch(
-			ctx,
-			waitFunc...,
-		)
+		idleCtx, idleCancel := withOptionalTimeout(ctx, networkIdleTimeout)
+		defer idleCancel()
 
-		if err == nil {
+		select {
+		case <-networkIdle:
+			logger.Debug("event networkIdle fired")
 			return nil
+		case <-idleCtx.Done():
+			if isTimeoutExceeded(ctx, idleCtx) {
+				return fmt.Errorf("wait for event networkIdle within %s: %w", networkIdleTimeout, ErrNetworkIdleTimeout)
+			}
+
+			return fmt.Errorf("wait for events: wait for event networkIdle: %w", idleCtx.Err())
 		}
+	}
+}
 
-		return fmt.Errorf("wait for events: %w", err)
+// withOptionalTimeout returns a copy of the context with the given timeout,
+// unless it is 0.
+func withOptionalTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// isTimeoutExceeded tells if a context derived thanks to
+// [withOptionalTimeout] is done because of its own timeout, while its parent
+// is not done.
+func isTimeoutExceeded(parent, ctx context.Context) bool {
+	return parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
 }
 
 func hideDefaultWhiteBackgroundActionFunc(logger *zap.Logger, omitBackground, printBackground bool) chromedp.ActionFunc {
@@ -306,26 +623,134 @@ func forceExactColorsActionFunc() chromedp.ActionFunc {
 	}
 }
 
-func emulateMediaTypeActionFunc(logger *zap.Logger, mediaType string) chromedp.ActionFunc {
+func emulateMediaActionFunc(logger *zap.Logger, mediaType, colorScheme string) chromedp.ActionFunc {
 	return func(ctx context.Context) error {
-		if mediaType == "" {
-			logger.Debug("no emulated media type")
+		if mediaType == "" && colorScheme == "" {
+			logger.Debug("no emulated media type nor color scheme")
 			return nil
 		}
 
-		if mediaType != "screen" && mediaType != "print" {
+		if mediaType != "" && mediaType != "screen" && mediaType != "print" {
 			return fmt.Errorf("validate emulated media type '%s': %w", mediaType, ErrInvalidEmulatedMediaType)
 		}
 
-		logger.Debug(fmt.Sprintf("emulate media type '%s'", mediaType))
+		if colorScheme != "" && colorScheme != "light" && colorScheme != "dark" {
+			return fmt.Errorf("validate color scheme '%s': %w", colorScheme, ErrInvalidColorScheme)
+		}
 
+		// A call replaces the media and the features of the previous one,
+		// hence both in a single call.
 		emulatedMedia := emulation.SetEmulatedMedia()
-		err := emulatedMedia.WithMedia(mediaType).Do(ctx)
+
+		if mediaType != "" {
+			logger.Debug(fmt.Sprintf("emulate media type '%s'", mediaType))
+			emulatedMedia = emulatedMedia.WithMedia(mediaType)
+		}
+
+		if colorScheme != "" {
+			logger.Debug(fmt.Sprintf("emulate color scheme '%s'", colorScheme))
+			emulatedMedia = emulatedMedia.WithFeatures([]*emulation.MediaFeature{
+				{Name: "prefers-color-scheme", Value: colorScheme},
+			})
+		}
+
+		err := emulatedMedia.Do(ctx)
+		if err == nil {
+			return nil
+		}
+
+		return fmt.Errorf("emulate media type '%s' and color scheme '%s': %w", mediaType, colorScheme, err)
+	}
+}
+
+func extraCssActionFunc(logger *zap.Logger, stylesheets []string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if len(stylesheets) == 0 {
+			logger.Debug("no extra CSS")
+			return nil
+		}
+
+		logger.Debug(fmt.Sprintf("inject %d extra stylesheet(s)", len(stylesheets)))
+
+		// Unlike a <style> element added with JavaScript, a stylesheet
+		// created through the CSS domain is not subject to the page's
+		// Content Security Policy, and works even if JavaScript is disabled.
+		frameTree, err := page.GetFrameTree().Do(ctx)
+		if err != nil {
+			return fmt.Errorf("get frame tree: %w", err)
+		}
+
+		err = dom.Enable().Do(ctx)
+		if err != nil {
+			return fmt.Errorf("enable DOM domain: %w", err)
+		}
+
+		err = css.Enable().Do(ctx)
+		if err != nil {
+			return fmt.Errorf("enable CSS domain: %w", err)
+		}
+
+		for i, stylesheet := range stylesheets {
+			styleSheetId, err := css.CreateStyleSheet(frameTree.Frame.ID).Do(ctx)
+			if err != nil {
+				return fmt.Errorf("create extra stylesheet %d: %w", i+1, err)
+			}
+
+			_, err = css.SetStyleSheetText(styleSheetId, stylesheet).Do(ctx)
+			if err != nil {
+				return fmt.Errorf("set text of extra stylesheet %d: %w", i+1, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+func emulateTimezoneActionFunc(logger *zap.Logger, timezone string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if timezone == "" {
+			logger.Debug("no emulated timezone")
+			return nil
+		}
+
+		logger.Debug(fmt.Sprintf("emulate timezone '%s'", timezone))
+
+		err := emulation.SetTimezoneOverride(timezone).Do(ctx)
 		if err == nil {
 			return nil
 		}
 
-		return fmt.Errorf("emulate media type '%s': %w", mediaType, err)
+		return fmt.Errorf("emulate timezone '%s': %w", timezone, err)
+	}
+}
+
+func emulateLocaleActionFunc(logger *zap.Logger, locale string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if locale == "" {
+			logger.Debug("no emulated locale")
+			return nil
+		}
+
+		logger.Debug(fmt.Sprintf("emulate locale '%s'", locale))
+
+		err := emulation.SetLocaleOverride().WithLocale(locale).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("emulate locale '%s': %w", locale, err)
+		}
+
+		// The user agent override is the only way to set the Accept-Language
+		// HTTP header and navigator.language; we keep the current user agent.
+		_, _, _, userAgent, _, err := cdpbrowser.GetVersion().Do(ctx)
+		if err != nil {
+			return fmt.Errorf("get user agent: %w", err)
+		}
+
+		err = emulation.SetUserAgentOverride(userAgent).WithAcceptLanguage(locale).Do(ctx)
+		if err == nil {
+			return nil
+		}
+
+		return fmt.Errorf("set Accept-Language '%s': %w", locale, err)
 	}
 }
 
@@ -395,3 +820,285 @@ func waitForExpressionBeforePrintActionFunc(logger *zap.Logger, disableJavaScrip
 		}
 	}
 }
+
+func waitForSelectorBeforePrintActionFunc(logger *zap.Logger, disableJavaScript bool, selector string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if disableJavaScript {
+			logger.Debug("JavaScript disabled, skipping wait selector")
+			return nil
+		}
+
+		if selector == "" {
+			logger.Debug("no wait selector")
+			return nil
+		}
+
+		quotedSelector, err := json.Marshal(selector)
+		if err != nil {
+			return fmt.Errorf("quote selector: %w", err)
+		}
+
+		// document.querySelector throws a SyntaxError if the selector is not
+		// valid; we wait until an element matches it or until the context is
+		// done.
+		expression := fmt.Sprintf("document.querySelector(%s) !== null", quotedSelector)
+		logger.Debug(fmt.Sprintf("wait until an element matches '%s' before print", selector))
+		ticker := time.NewTicker(time.Duration(100) * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("context done while waiting for '%s': %w", selector, ctx.Err())
+			case <-ticker.C:
+				var ok bool
+				evaluate := chromedp.Evaluate(expression, &ok)
+
+				err := evaluate.Do(ctx)
+				if err != nil {
+					return fmt.Errorf("evaluate: %v: %w", err, ErrInvalidSelector)
+				}
+
+				if ok {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func waitForFontsBeforePrintActionFunc(logger *zap.Logger, disableJavaScript, waitForFonts bool, timeout time.Duration) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if disableJavaScript {
+			logger.Debug("JavaScript disabled, skipping wait for fonts")
+			return nil
+		}
+
+		if !waitForFonts {
+			logger.Debug("no wait for fonts")
+			return nil
+		}
+
+		logger.Debug(fmt.Sprintf("wait up to '%s' for the fonts to be loaded before print", timeout))
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		var ready bool
+		evaluate := chromedp.Evaluate("document.fonts.ready.then(() => true)", &ready, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithAwaitPromise(true)
+		})
+
+		err := evaluate.Do(timeoutCtx)
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("context done while waiting for fonts: %w", ctx.Err())
+		}
+
+		// The fonts are a nice-to-have: a fallback font is better than no
+		// output.
+		if errors.Is(err, context.DeadlineExceeded) {
+			logger.Warn(fmt.Sprintf("fonts not loaded after '%s', proceeding anyway", timeout))
+			return nil
+		}
+
+		return fmt.Errorf("wait for fonts: %w", err)
+	}
+}
+
+func setCookiesActionFunc(logger *zap.Logger, cookies []Cookie) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if len(cookies) == 0 {
+			logger.Debug("no cookies to set")
+			return nil
+		}
+
+		// The values of the cookies may be secrets: we only log their names.
+		params := make([]*network.CookieParam, len(cookies))
+		names := make([]string, len(cookies))
+
+		for i, cookie := range cookies {
+			path := cookie.Path
+			if path == "" {
+				path = "/"
+			}
+
+			params[i] = &network.CookieParam{
+				Name:     cookie.Name,
+				Value:    cookie.Value,
+				Domain:   cookie.Domain,
+				Path:     path,
+				Secure:   cookie.Secure,
+				HTTPOnly: cookie.HttpOnly,
+				SameSite: network.CookieSameSite(cookie.SameSite),
+			}
+			names[i] = cookie.Name
+		}
+
+		logger.Debug(fmt.Sprintf("set cookies %s", strings.Join(names, ", ")))
+
+		// Like the cookies of a session, they belong to the browser context
+		// of the request.
+		c := chromedp.FromContext(ctx)
+
+		err := storage.SetCookies(params).
+			WithBrowserContextID(c.BrowserContextID).
+			Do(cdp.WithExecutor(ctx, c.Browser))
+		if err != nil {
+			return fmt.Errorf("set cookies: %w", err)
+		}
+
+		return nil
+	}
+}
+
+func restoreSessionActionFunc(logger *zap.Logger, sessions *sessionStore, name string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if name == "" {
+			logger.Debug("no session to restore")
+			return nil
+		}
+
+		state, err := sessions.load(name)
+		if err != nil {
+			return fmt.Errorf("load session '%s': %w", name, err)
+		}
+
+		logger.Debug(fmt.Sprintf("restore session '%s' with %d cookie(s) and the local storage of %d origin(s)", name, len(state.Cookies), len(state.LocalStorage)))
+
+		if len(state.Cookies) > 0 {
+			// The cookies belong to the browser context of the session.
+			c := chromedp.FromContext(ctx)
+
+			err = storage.SetCookies(state.Cookies).
+				WithBrowserContextID(c.BrowserContextID).
+				Do(cdp.WithExecutor(ctx, c.Browser))
+			if err != nil {
+				return fmt.Errorf("restore session cookies: %w", err)
+			}
+		}
+
+		if len(state.LocalStorage) == 0 {
+			return nil
+		}
+
+		localStorage, err := json.Marshal(state.LocalStorage)
+		if err != nil {
+			return fmt.Errorf("marshal session local storage: %w", err)
+		}
+
+		// The items are set before the scripts of each document of a known
+		// origin, unless the page already set them during this request.
+		script := fmt.Sprintf(`
+(() => {
+	const items = %s[location.origin];
+	if (!items) {
+		return;
+	}
+
+	try {
+		for (const [key, value] of Object.entries(items)) {
+			if (localStorage.getItem(key) === null) {
+				localStorage.setItem(key, value);
+			}
+		}
+	} catch (e) {}
+})();
+`, localStorage)
+
+		_, err = page.AddScriptToEvaluateOnNewDocument(script).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("restore session local storage: %w", err)
+		}
+
+		return nil
+	}
+}
+
+func saveSessionActionFunc(logger *zap.Logger, sessions *sessionStore, name string) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		if name == "" {
+			logger.Debug("no session to save")
+			return nil
+		}
+
+		// The local storage of the origins not visited during this request
+		// is kept as is.
+		state, err := sessions.load(name)
+		if err != nil {
+			return fmt.Errorf("load session '%s': %w", name, err)
+		}
+
+		c := chromedp.FromContext(ctx)
+
+		cookies, err := storage.GetCookies().
+			WithBrowserContextID(c.BrowserContextID).
+			Do(cdp.WithExecutor(ctx, c.Browser))
+		if err != nil {
+			return fmt.Errorf("get session cookies: %w", err)
+		}
+
+		state.Cookies = make([]*network.CookieParam, len(cookies))
+		for i, cookie := range cookies {
+			state.Cookies[i] = &network.CookieParam{
+				Name:         cookie.Name,
+				Value:        cookie.Value,
+				Domain:       cookie.Domain,
+				Path:         cookie.Path,
+				Secure:       cookie.Secure,
+				HTTPOnly:     cookie.HTTPOnly,
+				SameSite:     cookie.SameSite,
+				Priority:     cookie.Priority,
+				SourceScheme: cookie.SourceScheme,
+				SourcePort:   cookie.SourcePort,
+				PartitionKey: cookie.PartitionKey,
+			}
+
+			if !cookie.Session {
+				expires := cdp.TimeSinceEpoch(time.Unix(0, int64(cookie.Expires*float64(time.Second))))
+				state.Cookies[i].Expires = &expires
+			}
+		}
+
+		var current struct {
+			Origin string            `json:"origin"`
+			Items  map[string]string `json:"items"`
+		}
+
+		script := `
+(() => {
+	try {
+		return { origin: location.origin, items: Object.assign({}, localStorage) };
+	} catch (e) {
+		return { origin: 'null', items: {} };
+	}
+})();
+`
+
+		err = chromedp.Evaluate(script, &current).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("get session local storage: %w", err)
+		}
+
+		// An opaque origin (e.g., about:blank) has no local storage to keep.
+		if current.Origin != "" && current.Origin != "null" {
+			if state.LocalStorage == nil {
+				state.LocalStorage = make(map[string]map[string]string)
+			}
+
+			state.LocalStorage[current.Origin] = current.Items
+		}
+
+		logger.Debug(fmt.Sprintf("save session '%s' with %d cookie(s) and the local storage of %d origin(s)", name, len(state.Cookies), len(state.LocalStorage)))
+
+		err = sessions.save(name, state)
+		if err != nil {
+			return fmt.Errorf("save session '%s': %w", name, err)
+		}
+
+		return nil
+	}
+}
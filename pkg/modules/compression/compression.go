@@ -0,0 +1,131 @@
+package compression
+
+import (
+	"compress/flate"
+	"errors"
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Compression))
+}
+
+// Compression is a module which provides a middleware for compressing the
+// responses of the API.
+type Compression struct {
+	enable              bool
+	minSize             int
+	level               int
+	excludeContentTypes []string
+}
+
+// Descriptor returns a [Compression]'s module descriptor.
+func (mod *Compression) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "compression",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("compression", flag.ExitOnError)
+			fs.Bool("compression-enable", false, "Enable the gzip/deflate compression of the responses")
+			fs.Int("compression-min-size", 1024, "Set the minimum size, in bytes, of a response to compress")
+			fs.Int("compression-level", flate.DefaultCompression, "Set the compression level, from 1 (best speed) to 9 (best compression) - -1 means the default level")
+			fs.StringSlice("compression-exclude-content-types", []string{"application/pdf", "application/zip"}, "Set the content types which should not be compressed (e.g., image/*)")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Compression) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Compression) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("compression-enable")
+	mod.minSize = flags.MustInt("compression-min-size")
+	mod.level = flags.MustInt("compression-level")
+
+	for _, contentType := range flags.MustStringSlice("compression-exclude-content-types") {
+		mod.excludeContentTypes = append(mod.excludeContentTypes, strings.ToLower(strings.TrimSpace(contentType)))
+	}
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Compression) Validate() error {
+	if !mod.enable {
+		return nil
+	}
+
+	var err error
+
+	if mod.minSize < 0 {
+		err = multierr.Append(err,
+			errors.New("minimum size must be positive"),
+		)
+	}
+
+	if mod.level != flate.DefaultCompression && (mod.level < flate.BestSpeed || mod.level > flate.BestCompression) {
+		err = multierr.Append(err,
+			fmt.Errorf("level must be either %d or between %d and %d", flate.DefaultCompression, flate.BestSpeed, flate.BestCompression),
+		)
+	}
+
+	for _, contentType := range mod.excludeContentTypes {
+		if contentType == "" {
+			err = multierr.Append(err,
+				errors.New("excluded content types must not contain empty values"),
+			)
+		}
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware.
+func (mod *Compression) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		compressionMiddleware(mod),
+	}, nil
+}
+
+// isExcluded tells if a content type should not be compressed. An excluded
+// content type may end with a wildcard (e.g., image/*).
+func (mod *Compression) isExcluded(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	for _, excluded := range mod.excludeContentTypes {
+		if prefix, ok := strings.CutSuffix(excluded, "*"); ok {
+			if strings.HasPrefix(mediaType, prefix) {
+				return true
+			}
+
+			continue
+		}
+
+		if mediaType == excluded {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*Compression)(nil)
+	_ gotenberg.Provisioner  = (*Compression)(nil)
+	_ gotenberg.Validator    = (*Compression)(nil)
+	_ api.MiddlewareProvider = (*Compression)(nil)
+)
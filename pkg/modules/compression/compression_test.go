@@ -0,0 +1,134 @@
+package compression
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestCompression_Descriptor(t *testing.T) {
+	descriptor := new(Compression).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Compression))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestCompression_Provision(t *testing.T) {
+	fs := new(Compression).Descriptor().FlagSet
+	err := fs.Parse([]string{"--compression-exclude-content-types= Application/PDF ,image/*"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	mod := new(Compression)
+	err = mod.Provision(gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := []string{"application/pdf", "image/*"}
+	if !reflect.DeepEqual(mod.excludeContentTypes, expect) {
+		t.Errorf("expected %+v but got %+v", expect, mod.excludeContentTypes)
+	}
+}
+
+func TestCompression_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Compression
+		expectError bool
+	}{
+		{
+			scenario:    "disabled",
+			mod:         &Compression{enable: false, minSize: -1, level: 42},
+			expectError: false,
+		},
+		{
+			scenario:    "invalid minimum size",
+			mod:         &Compression{enable: true, minSize: -1, level: -1},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid level",
+			mod:         &Compression{enable: true, minSize: 1024, level: 10},
+			expectError: true,
+		},
+		{
+			scenario:    "empty excluded content type",
+			mod:         &Compression{enable: true, minSize: 1024, level: -1, excludeContentTypes: []string{""}},
+			expectError: true,
+		},
+		{
+			scenario:    "success",
+			mod:         &Compression{enable: true, minSize: 0, level: 9, excludeContentTypes: []string{"application/pdf"}},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestCompression_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		enable            bool
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "compression disabled",
+			enable:            false,
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "compression enabled",
+			enable:            true,
+			expectMiddlewares: 1,
+		},
+	} {
+		mod := &Compression{enable: tc.enable}
+
+		middlewares, err := mod.Middlewares()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if tc.expectMiddlewares != len(middlewares) {
+			t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+		}
+	}
+}
+
+func TestCompression_isExcluded(t *testing.T) {
+	mod := &Compression{excludeContentTypes: []string{"application/pdf", "image/*"}}
+
+	for _, tc := range []struct {
+		contentType string
+		expect      bool
+	}{
+		{contentType: "application/pdf", expect: true},
+		{contentType: "Application/PDF; charset=binary", expect: true},
+		{contentType: "image/png", expect: true},
+		{contentType: "application/json", expect: false},
+		{contentType: "", expect: false},
+	} {
+		actual := mod.isExcluded(tc.contentType)
+		if actual != tc.expect {
+			t.Errorf("expected %t for '%s' but got %t", tc.expect, tc.contentType, actual)
+		}
+	}
+}
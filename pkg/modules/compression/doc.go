@@ -0,0 +1,4 @@
+// Package compression provides a module which compresses the responses of the
+// API with either gzip or deflate, according to the Accept-Encoding header of
+// the requests.
+package compression
@@ -0,0 +1,316 @@
+package compression
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func compressionMiddleware(mod *Compression) api.Middleware {
+	return api.Middleware{
+		Priority: api.HighPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					req := c.Request()
+					c.Response().Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+					// A compressed body does not match the byte ranges of the
+					// original content, and a HEAD request has no body.
+					if req.Method == http.MethodHead || req.Header.Get("Range") != "" {
+						return next(c)
+					}
+
+					encoding := negotiateEncoding(req.Header.Get(echo.HeaderAcceptEncoding))
+					if encoding == "" {
+						return next(c)
+					}
+
+					original := c.Response().Writer
+					writer := &compressWriter{
+						ResponseWriter: original,
+						mod:            mod,
+						encoding:       encoding,
+					}
+					c.Response().Writer = writer
+
+					defer func() {
+						err := writer.close()
+						if err != nil {
+							logger := c.Get("logger").(*zap.Logger)
+							logger.Error(fmt.Sprintf("close compressed response: %s", err.Error()))
+						}
+
+						// The error handler, if any, writes its response
+						// without compression.
+						c.Response().Writer = original
+					}()
+
+					return next(c)
+				}
+			}
+		}(),
+	}
+}
+
+// negotiateEncoding returns the encoding to use according to the given
+// Accept-Encoding header value, i.e., gzip or deflate. It returns an empty
+// string if none of them is acceptable.
+func negotiateEncoding(acceptEncoding string) string {
+	var (
+		gzipQ    float64 = -1
+		deflateQ float64 = -1
+		anyQ     float64 = -1
+	)
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		key, value, ok := strings.Cut(strings.TrimSpace(params), "=")
+		if ok && strings.TrimSpace(key) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		switch name {
+		case "gzip":
+			gzipQ = q
+		case "deflate":
+			deflateQ = q
+		case "*":
+			anyQ = q
+		}
+	}
+
+	if gzipQ < 0 {
+		gzipQ = anyQ
+	}
+
+	if deflateQ < 0 {
+		deflateQ = anyQ
+	}
+
+	switch {
+	case gzipQ > 0 && gzipQ >= deflateQ:
+		return "gzip"
+	case deflateQ > 0:
+		return "deflate"
+	default:
+		return ""
+	}
+}
+
+// compressWriter is an [http.ResponseWriter] which compresses the body of a
+// response if its content type is not excluded and its size reaches the
+// minimum size. Until then, it buffers the status code and the body, so that
+// the headers (e.g., Content-Length) are only sent once the decision is made.
+type compressWriter struct {
+	http.ResponseWriter
+	mod      *Compression
+	encoding string
+
+	status     int
+	buf        []byte
+	decided    bool
+	compressed bool
+	encoder    io.WriteCloser
+}
+
+// WriteHeader records the status code. It only sends the headers right away
+// for responses without a body.
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	if code == http.StatusNoContent || code == http.StatusNotModified || code < http.StatusOK {
+		w.decided = true
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	w.status = code
+}
+
+// Write buffers the body until the decision to compress it or not is made.
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.compressed {
+			return w.encoder.Write(b)
+		}
+
+		return w.ResponseWriter.Write(b)
+	}
+
+	header := w.Header()
+	if header.Get(echo.HeaderContentType) == "" {
+		// Otherwise, the standard library would detect the content type of
+		// the compressed body.
+		header.Set(echo.HeaderContentType, http.DetectContentType(append(w.buf, b...)))
+	}
+
+	if !w.eligible() {
+		err := w.passthrough()
+		if err != nil {
+			return 0, err
+		}
+
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.mod.minSize {
+		return len(b), nil
+	}
+
+	err := w.startCompression()
+	if err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Flush makes the decision to compress the body or not, regardless of the
+// minimum size, and flushes the data written so far.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		var err error
+		if w.eligible() && len(w.buf) > 0 {
+			err = w.startCompression()
+		} else {
+			err = w.passthrough()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+
+	if w.compressed {
+		flusher, ok := w.encoder.(interface{ Flush() error })
+		if ok {
+			_ = flusher.Flush()
+		}
+	}
+
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the original [http.ResponseWriter].
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// eligible tells if the response may be compressed according to its headers.
+func (w *compressWriter) eligible() bool {
+	header := w.Header()
+
+	if header.Get(echo.HeaderContentEncoding) != "" {
+		return false
+	}
+
+	if w.mod.isExcluded(header.Get(echo.HeaderContentType)) {
+		return false
+	}
+
+	contentLength := header.Get(echo.HeaderContentLength)
+	if contentLength != "" {
+		length, err := strconv.Atoi(contentLength)
+		if err == nil && length < w.mod.minSize {
+			return false
+		}
+	}
+
+	return true
+}
+
+// passthrough sends the headers and the buffered body as is.
+func (w *compressWriter) passthrough() error {
+	w.decided = true
+
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	_, err := w.ResponseWriter.Write(w.buf)
+	w.buf = nil
+
+	return err
+}
+
+// startCompression sends the headers of a compressed response and compresses
+// the buffered body.
+func (w *compressWriter) startCompression() error {
+	w.decided = true
+	w.compressed = true
+
+	header := w.Header()
+	header.Set(echo.HeaderContentEncoding, w.encoding)
+	// The size of the compressed body is unknown; the response is chunked.
+	header.Del(echo.HeaderContentLength)
+	header.Del("Accept-Ranges")
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	var err error
+	if w.encoding == "gzip" {
+		w.encoder, err = gzip.NewWriterLevel(w.ResponseWriter, w.mod.level)
+	} else {
+		// The "deflate" content coding is the zlib format (RFC 1950), not
+		// the raw deflate one (RFC 1951).
+		w.encoder, err = zlib.NewWriterLevel(w.ResponseWriter, w.mod.level)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.encoder.Write(w.buf)
+	w.buf = nil
+
+	return err
+}
+
+// close sends what remains of the response. A body smaller than the minimum
+// size is sent as is.
+func (w *compressWriter) close() error {
+	if !w.decided {
+		if w.status == 0 && len(w.buf) == 0 {
+			// Nothing has been written.
+			return nil
+		}
+
+		return w.passthrough()
+	}
+
+	if w.compressed {
+		return w.encoder.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+func TestCompressionMiddleware(t *testing.T) {
+	largeText := strings.Repeat("foo", 1024)
+
+	for _, tc := range []struct {
+		scenario              string
+		method                string
+		headers               map[string]string
+		next                  echo.HandlerFunc
+		expectError           bool
+		expectContentEncoding string
+		expectContentLength   string
+		expectBody            string
+	}{
+		{
+			scenario: "no Accept-Encoding header",
+			method:   http.MethodGet,
+			next: func(c echo.Context) error {
+				return c.String(http.StatusOK, largeText)
+			},
+			expectContentEncoding: "",
+			expectBody:            largeText,
+		},
+		{
+			scenario: "gzip",
+			method:   http.MethodGet,
+			headers: map[string]string{
+				echo.HeaderAcceptEncoding: "deflate;q=0.5, gzip",
+			},
+			next: func(c echo.Context) error {
+				return c.String(http.StatusOK, largeText)
+			},
+			expectContentEncoding: "gzip",
+			expectBody:            largeText,
+		},
+		{
+			scenario: "deflate",
+			method:   http.MethodGet,
+			headers: map[string]string{
+				echo.HeaderAcceptEncoding: "deflate, gzip;q=0",
+			},
+			next: func(c echo.Context) error {
+				return c.String(http.StatusOK, largeText)
+			},
+			expectContentEncoding: "deflate",
+			expectBody:            largeText,
+		},
+		{
+			scenario: "body smaller than the minimum size",
+			method:   http.MethodGet,
+			headers: map[string]string{
+				echo.HeaderAcceptEncoding: "gzip",
+			},
+			next: func(c echo.Context) error {
+				return c.String(http.StatusOK, "foo")
+			},
+			expectContentEncoding: "",
+			expectBody:            "foo",
+		},
+		{
+			scenario: "known Content-Length smaller than the minimum size",
+			method:   http.MethodGet,
+			headers: map[string]string{
+				echo.HeaderAcceptEncoding: "gzip",
+			},
+			next: func(c echo.Context) error {
+				c.Response().Header().Set(echo.HeaderContentLength, "3")
+				return c.String(http.StatusOK, "foo")
+			},
+			expectContentEncoding: "",
+			expectContentLength:   "3",
+			expectBody:            "foo",
+		},
+		{
+			scenario: "known Content-Length larger than the minimum size",
+			method:   http.MethodGet,
+			headers: map[string]string{
+				echo.HeaderAcceptEncoding: "gzip",
+			},
+			next: func(c echo.Context) error {
+				c.Response().Header().Set(echo.HeaderContentLength, strconv.Itoa(len(largeText)))
+				return c.String(http.StatusOK, largeText)
+			},
+			expectContentEncoding: "gzip",
+			expectContentLength:   "",
+			expectBody:            largeText,
+		},
+		{
+			scenario: "excluded content type",
+			method:   http.MethodGet,
+			headers: map[string]string{
+				echo.HeaderAcceptEncoding: "gzip",
+			},
+			next: func(c echo.Context) error {
+				return c.Blob(http.StatusOK, "application/pdf", []byte(largeText))
+			},
+			expectContentEncoding: "",
+			expectBody:            largeText,
+		},
+		{
+			scenario: "range request",
+			method:   http.MethodGet,
+			headers: map[string]string{
+				echo.HeaderAcceptEncoding: "gzip",
+				"Range":                   "bytes=0-10",
+			},
+			next: func(c echo.Context) error {
+				return c.String(http.StatusOK, largeText)
+			},
+			expectContentEncoding: "",
+			expectBody:            largeText,
+		},
+		{
+			scenario: "streamed response",
+			method:   http.MethodGet,
+			headers: map[string]string{
+				echo.HeaderAcceptEncoding: "gzip",
+			},
+			next: func(c echo.Context) error {
+				c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlain)
+				c.Response().WriteHeader(http.StatusOK)
+
+				for i := 0; i < 3; i++ {
+					_, err := c.Response().Write([]byte("foo"))
+					if err != nil {
+						return err
+					}
+					c.Response().Flush()
+				}
+
+				return nil
+			},
+			expectContentEncoding: "gzip",
+			expectBody:            "foofoofoo",
+		},
+		{
+			scenario: "error from next",
+			method:   http.MethodGet,
+			headers: map[string]string{
+				echo.HeaderAcceptEncoding: "gzip",
+			},
+			next: func(c echo.Context) error {
+				return errors.New("foo")
+			},
+			expectError:           true,
+			expectContentEncoding: "",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Compression{
+				enable:              true,
+				minSize:             1024,
+				level:               flate.DefaultCompression,
+				excludeContentTypes: []string{"application/pdf", "application/zip"},
+			}
+
+			srv := echo.New()
+			req := httptest.NewRequest(tc.method, "/forms/foo", nil)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			c := srv.NewContext(req, recorder)
+			c.Set("logger", zap.NewNop())
+
+			err := compressionMiddleware(mod).Handler(tc.next)(c)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if c.Response().Writer != recorder {
+				t.Error("expected original response writer to be restored")
+			}
+
+			contentEncoding := recorder.Header().Get(echo.HeaderContentEncoding)
+			if contentEncoding != tc.expectContentEncoding {
+				t.Errorf("expected Content-Encoding '%s' but got '%s'", tc.expectContentEncoding, contentEncoding)
+			}
+
+			contentLength := recorder.Header().Get(echo.HeaderContentLength)
+			if contentLength != tc.expectContentLength {
+				t.Errorf("expected Content-Length '%s' but got '%s'", tc.expectContentLength, contentLength)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			var reader io.Reader = bytes.NewReader(recorder.Body.Bytes())
+			switch contentEncoding {
+			case "gzip":
+				reader, err = gzip.NewReader(reader)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			case "deflate":
+				reader, err = zlib.NewReader(reader)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			body, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if string(body) != tc.expectBody {
+				t.Errorf("expected body '%.20s...' but got '%.20s...'", tc.expectBody, string(body))
+			}
+		})
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		acceptEncoding string
+		expect         string
+	}{
+		{acceptEncoding: "", expect: ""},
+		{acceptEncoding: "identity", expect: ""},
+		{acceptEncoding: "gzip", expect: "gzip"},
+		{acceptEncoding: "GZIP, deflate", expect: "gzip"},
+		{acceptEncoding: "gzip;q=0.5, deflate", expect: "deflate"},
+		{acceptEncoding: "gzip;q=0, deflate;q=0", expect: ""},
+		{acceptEncoding: "*", expect: "gzip"},
+		{acceptEncoding: "gzip;q=0, *;q=0.1", expect: "deflate"},
+		{acceptEncoding: "br, gzip;q=foo", expect: ""},
+	} {
+		actual := negotiateEncoding(tc.acceptEncoding)
+		if actual != tc.expect {
+			t.Errorf("expected '%s' for '%s' but got '%s'", tc.expect, tc.acceptEncoding, actual)
+		}
+	}
+}
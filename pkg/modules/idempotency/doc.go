@@ -0,0 +1,11 @@
+// Package idempotency provides a module which deduplicates the retried
+// requests to the multipart/form-data routes thanks to an idempotency key
+// header. The first request with a given key runs normally, and its response
+// is stored for a TTL. The next requests with the same key get the stored
+// response, or a 409 Conflict if the first request is still in flight.
+//
+// The scope of a key is the key only: neither the route nor the body of the
+// request are part of it. A client must therefore use a new, unguessable key
+// (e.g., a UUID) for each logical request. Only the successful responses are
+// stored, so that a client may retry a failed request with the same key.
+package idempotency
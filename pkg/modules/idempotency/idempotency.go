@@ -0,0 +1,123 @@
+package idempotency
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Idempotency))
+	api.MustRegisterErrorCode(ErrInFlight, "IDEMPOTENCY_KEY_IN_FLIGHT")
+}
+
+// Idempotency is a module which provides a middleware for deduplicating the
+// retried requests to the multipart/form-data routes.
+type Idempotency struct {
+	enable bool
+	header string
+	ttl    time.Duration
+	store  Store
+}
+
+// Descriptor returns an [Idempotency]'s module descriptor.
+func (mod *Idempotency) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "idempotency",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("idempotency", flag.ExitOnError)
+			fs.Bool("idempotency-enable", false, "Enable the deduplication of the requests with an idempotency key")
+			fs.String("idempotency-header", "Idempotency-Key", "Set the header with the idempotency key of a request")
+			fs.Duration("idempotency-ttl", time.Duration(1)*time.Hour, "Set the duration during which the response of a request is returned to the requests with the same idempotency key")
+			fs.String("idempotency-dir", "", "Set the directory where the responses are stored - a temporary directory by default")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Idempotency) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Idempotency) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("idempotency-enable")
+	mod.header = flags.MustString("idempotency-header")
+	mod.ttl = flags.MustDuration("idempotency-ttl")
+
+	if !mod.enable {
+		return nil
+	}
+
+	providers, err := ctx.Modules(new(StoreProvider))
+	if err != nil {
+		return fmt.Errorf("get store providers: %w", err)
+	}
+
+	switch len(providers) {
+	case 0:
+		dirPath := flags.MustString("idempotency-dir")
+		if dirPath == "" {
+			dirPath = gotenberg.NewFileSystem().NewDirPath()
+		}
+
+		mod.store = newFileStore(dirPath, mod.ttl)
+	case 1:
+		mod.store, err = providers[0].(StoreProvider).IdempotencyStore()
+		if err != nil {
+			return fmt.Errorf("get store: %w", err)
+		}
+	default:
+		return errors.New("expected at most one idempotency store provider")
+	}
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Idempotency) Validate() error {
+	if !mod.enable {
+		return nil
+	}
+
+	var err error
+
+	if strings.TrimSpace(mod.header) == "" {
+		err = multierr.Append(err,
+			errors.New("header must not be empty"),
+		)
+	}
+
+	if mod.ttl <= 0 {
+		err = multierr.Append(err,
+			errors.New("TTL must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware.
+func (mod *Idempotency) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		idempotencyMiddleware(mod),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*Idempotency)(nil)
+	_ gotenberg.Provisioner  = (*Idempotency)(nil)
+	_ gotenberg.Validator    = (*Idempotency)(nil)
+	_ api.MiddlewareProvider = (*Idempotency)(nil)
+)
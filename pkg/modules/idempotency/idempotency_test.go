@@ -0,0 +1,134 @@
+package idempotency
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestIdempotency_Descriptor(t *testing.T) {
+	descriptor := new(Idempotency).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Idempotency))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestIdempotency_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		expectStore bool
+	}{
+		{
+			scenario: "default flags",
+			ctx: gotenberg.NewContext(
+				gotenberg.ParsedFlags{
+					FlagSet: new(Idempotency).Descriptor().FlagSet,
+				},
+				nil,
+			),
+			expectStore: false,
+		},
+		{
+			scenario: "enabled",
+			ctx: func() *gotenberg.Context {
+				fs := new(Idempotency).Descriptor().FlagSet
+				err := fs.Parse([]string{"--idempotency-enable=true", "--idempotency-dir=/foo"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+			}(),
+			expectStore: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Idempotency)
+			err := mod.Provision(tc.ctx)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectStore && mod.store == nil {
+				t.Error("expected a store")
+			}
+
+			if !tc.expectStore && mod.store != nil {
+				t.Error("expected no store")
+			}
+		})
+	}
+}
+
+func TestIdempotency_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Idempotency
+		expectError bool
+	}{
+		{
+			scenario:    "disabled",
+			mod:         &Idempotency{enable: false},
+			expectError: false,
+		},
+		{
+			scenario:    "invalid header and TTL",
+			mod:         &Idempotency{enable: true, header: " ", ttl: 0},
+			expectError: true,
+		},
+		{
+			scenario:    "success",
+			mod:         &Idempotency{enable: true, header: "Idempotency-Key", ttl: time.Hour},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestIdempotency_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		enable            bool
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "idempotency disabled",
+			enable:            false,
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "idempotency enabled",
+			enable:            true,
+			expectMiddlewares: 1,
+		},
+	} {
+		mod := &Idempotency{enable: tc.enable, header: "Idempotency-Key", ttl: time.Hour}
+
+		middlewares, err := mod.Middlewares()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if tc.expectMiddlewares != len(middlewares) {
+			t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package idempotency
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// maxKeyLength is the maximum length of an idempotency key.
+const maxKeyLength = 255
+
+// replayedHeader tells the client that the response is a stored one.
+const replayedHeader = "Idempotent-Replayed"
+
+// volatileHeaders are the headers which are specific to a response, and are
+// therefore not stored.
+var volatileHeaders = []string{
+	echo.HeaderContentEncoding,
+	echo.HeaderContentLength,
+	echo.HeaderVary,
+}
+
+func idempotencyMiddleware(mod *Idempotency) api.Middleware {
+	return api.Middleware{
+		Priority: api.MediumPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					// c.Path() contains the root path of the API, so that we
+					// look for the multipart/form-data routes prefix.
+					if !strings.Contains(c.Path(), "/forms/") {
+						return next(c)
+					}
+
+					key := c.Request().Header.Get(mod.header)
+					if key == "" {
+						return next(c)
+					}
+
+					if len(key) > maxKeyLength {
+						return api.WrapError(
+							fmt.Errorf("idempotency key of %d characters", len(key)),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf("Invalid '%s' header value: it must not exceed %d characters", mod.header, maxKeyLength),
+							),
+						)
+					}
+
+					logger := c.Get("logger").(*zap.Logger)
+
+					stored, err := mod.store.Reserve(key)
+					if errors.Is(err, ErrInFlight) {
+						return api.WrapError(
+							fmt.Errorf("reserve idempotency key: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusConflict,
+								fmt.Sprintf("A request with the same '%s' header value is still in progress", mod.header),
+							),
+						)
+					}
+					if err != nil {
+						return fmt.Errorf("reserve idempotency key: %w", err)
+					}
+
+					if stored != nil {
+						logger.Debug("replay the stored response of the idempotency key")
+
+						return replay(c, stored)
+					}
+
+					return record(c, mod.store, key, logger, next)
+				}
+			}
+		}(),
+	}
+}
+
+// replay sends a stored response.
+func replay(c echo.Context, stored *Response) error {
+	defer func() {
+		_ = stored.Body.Close()
+	}()
+
+	header := c.Response().Header()
+	for name, values := range stored.Header {
+		// Keep the headers of the current request, e.g., its trace.
+		if header.Get(name) != "" {
+			continue
+		}
+
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+
+	header.Set(replayedHeader, "true")
+	c.Response().WriteHeader(stored.StatusCode)
+
+	_, err := io.Copy(c.Response(), stored.Body)
+	if err != nil {
+		return fmt.Errorf("copy stored response: %w", err)
+	}
+
+	return nil
+}
+
+// record calls the next handler while copying its response to a temporary
+// file. It stores the response if successful, or releases the key otherwise.
+func record(c echo.Context, store Store, key string, logger *zap.Logger, next echo.HandlerFunc) error {
+	released := false
+	release := func() {
+		if released {
+			return
+		}
+
+		released = true
+
+		err := store.Release(key)
+		if err != nil {
+			logger.Error(fmt.Sprintf("release idempotency key: %s", err))
+		}
+	}
+
+	// A panic must not leave the key in flight until the TTL.
+	defer release()
+
+	file, err := os.CreateTemp("", "gotenberg-idempotency-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+
+	defer func() {
+		_ = file.Close()
+
+		err := os.Remove(file.Name())
+		if err != nil {
+			logger.Error(fmt.Sprintf("remove temporary file: %s", err))
+		}
+	}()
+
+	original := c.Response().Writer
+	recorder := &responseRecorder{ResponseWriter: original, file: file}
+	c.Response().Writer = recorder
+
+	err = next(c)
+
+	c.Response().Writer = original
+
+	// A validation does not process the request, so that a retry must not
+	// get its response.
+	validateOnly, _ := c.Get("validateOnly").(bool)
+
+	status := c.Response().Status
+	if err != nil || validateOnly || !c.Response().Committed || status < 200 || status > 299 {
+		return err
+	}
+
+	if recorder.err != nil {
+		logger.Error(fmt.Sprintf("record response: %s", recorder.err))
+		return nil
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		logger.Error(fmt.Sprintf("rewind recorded response: %s", err))
+		return nil
+	}
+
+	header := c.Response().Header().Clone()
+	for _, name := range volatileHeaders {
+		header.Del(name)
+	}
+
+	traceHeader, ok := c.Get("traceHeader").(string)
+	if ok {
+		header.Del(traceHeader)
+	}
+
+	// The response has already been sent: a failure only means that a retry
+	// will run again.
+	released = true
+
+	err = store.Save(key, status, header, file)
+	if err != nil {
+		logger.Error(fmt.Sprintf("store response: %s", err))
+	}
+
+	return nil
+}
+
+// responseRecorder copies the body of a response to a file.
+type responseRecorder struct {
+	http.ResponseWriter
+	file *os.File
+	err  error
+}
+
+// Write implements [http.ResponseWriter].
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.err == nil {
+		_, r.err = r.file.Write(b)
+	}
+
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush implements [http.Flusher].
+func (r *responseRecorder) Flush() {
+	flusher, ok := r.ResponseWriter.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the original [http.ResponseWriter].
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
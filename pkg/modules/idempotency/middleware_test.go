@@ -0,0 +1,187 @@
+package idempotency
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		path             string
+		keys             []string
+		inFlight         string
+		next             echo.HandlerFunc
+		expectCalls      int
+		expectBody       string
+		expectReplayed   bool
+		expectHttpStatus int
+	}{
+		{
+			scenario:    "not a multipart/form-data route",
+			path:        "/health",
+			keys:        []string{"foo", "foo"},
+			expectCalls: 2,
+			expectBody:  "2",
+		},
+		{
+			scenario:    "no idempotency key",
+			path:        "/forms/foo",
+			keys:        []string{"", ""},
+			expectCalls: 2,
+			expectBody:  "2",
+		},
+		{
+			scenario:         "idempotency key too long",
+			path:             "/forms/foo",
+			keys:             []string{strings.Repeat("a", maxKeyLength+1)},
+			expectCalls:      0,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "idempotency key in flight",
+			path:             "/forms/foo",
+			keys:             []string{"foo"},
+			inFlight:         "foo",
+			expectCalls:      0,
+			expectHttpStatus: http.StatusConflict,
+		},
+		{
+			scenario:       "replay the stored response",
+			path:           "/forms/foo",
+			keys:           []string{"foo", "foo"},
+			expectCalls:    1,
+			expectBody:     "1",
+			expectReplayed: true,
+		},
+		{
+			scenario:    "different idempotency keys",
+			path:        "/forms/foo",
+			keys:        []string{"foo", "bar"},
+			expectCalls: 2,
+			expectBody:  "2",
+		},
+		{
+			scenario: "failed request not stored",
+			path:     "/forms/foo",
+			keys:     []string{"foo", "foo"},
+			next: func(c echo.Context) error {
+				return errors.New("foo")
+			},
+			expectCalls:      2,
+			expectHttpStatus: http.StatusInternalServerError,
+		},
+		{
+			scenario: "validation not stored",
+			path:     "/forms/foo",
+			keys:     []string{"foo", "foo"},
+			next: func(c echo.Context) error {
+				c.Set("validateOnly", true)
+
+				return c.String(http.StatusOK, "validated")
+			},
+			expectCalls: 2,
+			expectBody:  "validated",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			fs := gotenberg.NewFileSystem()
+			mod := &Idempotency{
+				header: "Idempotency-Key",
+				ttl:    time.Hour,
+				store:  newFileStore(fs.NewDirPath(), time.Hour),
+			}
+
+			defer func() {
+				err := os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			if tc.inFlight != "" {
+				_, err := mod.store.Reserve(tc.inFlight)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			calls := 0
+			next := func(c echo.Context) error {
+				calls++
+
+				if tc.next != nil {
+					return tc.next(c)
+				}
+
+				c.Response().Header().Set("Gotenberg-Trace", "bar")
+
+				return c.String(http.StatusOK, strconv.Itoa(calls))
+			}
+
+			srv := echo.New()
+			handler := idempotencyMiddleware(mod).Handler(next)
+
+			var (
+				err error
+				rec *httptest.ResponseRecorder
+			)
+
+			for _, key := range tc.keys {
+				req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+				if key != "" {
+					req.Header.Set("Idempotency-Key", key)
+				}
+
+				rec = httptest.NewRecorder()
+				c := srv.NewContext(req, rec)
+				c.SetPath(tc.path)
+				c.Set("logger", zap.NewNop())
+				c.Set("traceHeader", "Gotenberg-Trace")
+
+				err = handler(c)
+			}
+
+			if calls != tc.expectCalls {
+				t.Errorf("expected %d calls but got %d", tc.expectCalls, calls)
+			}
+
+			if tc.expectHttpStatus != 0 {
+				status, _ := api.ParseError(err)
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if rec.Body.String() != tc.expectBody {
+				t.Errorf("expected body '%s' but got '%s'", tc.expectBody, rec.Body.String())
+			}
+
+			if rec.Header().Get("Gotenberg-Trace") != "" && tc.expectReplayed {
+				t.Error("expected the trace header not to be replayed")
+			}
+
+			if tc.expectReplayed && rec.Header().Get(replayedHeader) != "true" {
+				t.Errorf("expected the '%s' header", replayedHeader)
+			}
+		})
+	}
+}
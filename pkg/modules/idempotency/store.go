@@ -0,0 +1,229 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInFlight happens when a request with the same idempotency key is still
+// being processed.
+var ErrInFlight = errors.New("request with the same idempotency key in flight")
+
+// Response is a stored response.
+type Response struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Header are the HTTP headers of the response.
+	Header http.Header
+
+	// Body is the content of the response. The caller must close it.
+	Body io.ReadCloser
+}
+
+// Store persists the responses of the requests with an idempotency key. Its
+// methods must be safe for concurrent use.
+type Store interface {
+	// Reserve marks a key as in flight. If the key has a stored response, it
+	// returns it instead. If the key is already in flight, it returns
+	// [ErrInFlight].
+	Reserve(key string) (*Response, error)
+
+	// Save stores the response of an in-flight key, which is no longer in
+	// flight.
+	Save(key string, statusCode int, header http.Header, body io.Reader) error
+
+	// Release frees an in-flight key without response, so that a retry runs
+	// normally.
+	Release(key string) error
+}
+
+// StoreProvider is a module interface which provides a [Store] to the
+// [Idempotency] module, e.g., to share the responses between many instances.
+// Without such a module, the responses are stored on the file system.
+type StoreProvider interface {
+	IdempotencyStore() (Store, error)
+}
+
+// fileStore is the default [Store]. It stores the responses on the file
+// system, one metadata and one body file per key, and keeps the in-flight keys
+// in memory; it is therefore local to an instance.
+type fileStore struct {
+	dirPath   string
+	ttl       time.Duration
+	inFlight  map[string]struct{}
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+// fileStoreMetadata is the content of a metadata file.
+type fileStoreMetadata struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+}
+
+func newFileStore(dirPath string, ttl time.Duration) *fileStore {
+	return &fileStore{
+		dirPath:   dirPath,
+		ttl:       ttl,
+		inFlight:  make(map[string]struct{}),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// paths returns the paths of the metadata and body files of a key. The key is
+// hashed, so that it cannot escape the directory of the store.
+func (s *fileStore) paths(key string) (string, string) {
+	hash := sha256.Sum256([]byte(key))
+	prefix := fmt.Sprintf("%s/%s", s.dirPath, hex.EncodeToString(hash[:]))
+
+	return fmt.Sprintf("%s.json", prefix), fmt.Sprintf("%s.body", prefix)
+}
+
+// Reserve implements [Store].
+func (s *fileStore) Reserve(key string) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inFlight[key]; ok {
+		return nil, ErrInFlight
+	}
+
+	s.sweep()
+
+	metadataPath, bodyPath := s.paths(key)
+
+	info, err := os.Stat(metadataPath)
+	if err == nil && s.now().Sub(info.ModTime()) <= s.ttl {
+		b, err := os.ReadFile(metadataPath)
+		if err != nil {
+			return nil, fmt.Errorf("read metadata file: %w", err)
+		}
+
+		var metadata fileStoreMetadata
+		err = json.Unmarshal(b, &metadata)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal metadata: %w", err)
+		}
+
+		body, err := os.Open(bodyPath)
+		if err != nil {
+			return nil, fmt.Errorf("open body file: %w", err)
+		}
+
+		return &Response{
+			StatusCode: metadata.StatusCode,
+			Header:     metadata.Header,
+			Body:       body,
+		}, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("stat metadata file: %w", err)
+	}
+
+	s.inFlight[key] = struct{}{}
+
+	return nil, nil
+}
+
+// Save implements [Store].
+func (s *fileStore) Save(key string, statusCode int, header http.Header, body io.Reader) error {
+	defer func() {
+		s.mu.Lock()
+		delete(s.inFlight, key)
+		s.mu.Unlock()
+	}()
+
+	err := os.MkdirAll(s.dirPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("create store directory: %w", err)
+	}
+
+	metadataPath, bodyPath := s.paths(key)
+
+	out, err := os.OpenFile(bodyPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create body file: %w", err)
+	}
+
+	_, err = io.Copy(out, body)
+	if err != nil {
+		_ = out.Close()
+		return fmt.Errorf("write body file: %w", err)
+	}
+
+	err = out.Close()
+	if err != nil {
+		return fmt.Errorf("close body file: %w", err)
+	}
+
+	b, err := json.Marshal(fileStoreMetadata{StatusCode: statusCode, Header: header})
+	if err != nil {
+		return fmt.Errorf("marshal metadata: %w", err)
+	}
+
+	// The metadata file comes last, as it tells that the response is stored.
+	err = os.WriteFile(metadataPath, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write metadata file: %w", err)
+	}
+
+	return nil
+}
+
+// Release implements [Store].
+func (s *fileStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.inFlight, key)
+
+	return nil
+}
+
+// sweep removes, at most once per minute, the expired responses. The caller
+// must hold the lock.
+func (s *fileStore) sweep() {
+	now := s.now()
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+
+	s.lastSweep = now
+
+	entries, err := os.ReadDir(s.dirPath)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) <= s.ttl {
+			continue
+		}
+
+		prefix := fmt.Sprintf("%s/%s", s.dirPath, strings.TrimSuffix(entry.Name(), ".json"))
+		_ = os.Remove(fmt.Sprintf("%s.json", prefix))
+		_ = os.Remove(fmt.Sprintf("%s.body", prefix))
+	}
+}
+
+// Interface guards.
+var (
+	_ Store = (*fileStore)(nil)
+)
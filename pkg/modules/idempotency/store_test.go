@@ -0,0 +1,103 @@
+package idempotency
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestFileStore(t *testing.T) {
+	fs := gotenberg.NewFileSystem()
+	store := newFileStore(fs.NewDirPath(), time.Hour)
+
+	defer func() {
+		err := os.RemoveAll(fs.WorkingDirPath())
+		if err != nil {
+			t.Fatalf("expected no error while cleaning up but got: %v", err)
+		}
+	}()
+
+	// Unknown key.
+	resp, err := store.Reserve("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if resp != nil {
+		t.Fatal("expected no stored response")
+	}
+
+	// In flight.
+	_, err = store.Reserve("foo")
+	if !errors.Is(err, ErrInFlight) {
+		t.Fatalf("expected ErrInFlight but got: %v", err)
+	}
+
+	// Released.
+	err = store.Release("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = store.Reserve("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	// Saved.
+	err = store.Save("foo", http.StatusOK, http.Header{"Content-Type": {"application/pdf"}}, strings.NewReader("bar"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	resp, err = store.Reserve("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if resp == nil {
+		t.Fatal("expected a stored response")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/pdf" || string(body) != "bar" {
+		t.Errorf("expected the saved response but got %d, %+v, '%s'", resp.StatusCode, resp.Header, body)
+	}
+
+	// Expired.
+	store.now = func() time.Time {
+		return time.Now().Add(2 * time.Hour)
+	}
+
+	resp, err = store.Reserve("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if resp != nil {
+		t.Fatal("expected no stored response once expired")
+	}
+
+	metadataPath, bodyPath := store.paths("foo")
+	for _, path := range []string{metadataPath, bodyPath} {
+		_, err = os.Stat(path)
+		if !os.IsNotExist(err) {
+			t.Errorf("expected '%s' to be swept, but got: %v", path, err)
+		}
+	}
+}
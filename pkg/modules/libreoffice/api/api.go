@@ -5,12 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexliesenfeld/health"
+	"github.com/labstack/gommon/bytes"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
@@ -18,6 +25,22 @@ import (
 
 func init() {
 	gotenberg.MustRegisterModule(new(Api))
+
+	api.MustRegisterErrorCode(ErrInvalidPdfFormats, "INVALID_PDF_FORMATS")
+	api.MustRegisterErrorCode(ErrMalformedPageRanges, "MALFORMED_PAGE_RANGES")
+	api.MustRegisterErrorCode(ErrInvalidFilterData, "INVALID_FILTER_DATA")
+	api.MustRegisterErrorCode(ErrInvalidMaxImageResolution, "INVALID_MAX_IMAGE_RESOLUTION")
+	api.MustRegisterErrorCode(ErrInvalidJpegQuality, "INVALID_JPEG_QUALITY")
+	api.MustRegisterErrorCode(ErrTrackedChangesNotSupported, "TRACKED_CHANGES_NOT_SUPPORTED")
+	api.MustRegisterErrorCode(ErrInvalidPdfVersion, "INVALID_PDF_VERSION")
+	api.MustRegisterErrorCode(ErrSinglePageNotSupported, "SINGLE_PAGE_NOT_SUPPORTED")
+	api.MustRegisterErrorCode(ErrInvalidOutputFormat, "INVALID_OUTPUT_FORMAT")
+	api.MustRegisterErrorCode(ErrInvalidPassword, "INVALID_PASSWORD")
+	api.MustRegisterErrorCode(ErrCorruptDocument, "CORRUPT_DOCUMENT")
+	api.MustRegisterErrorCode(ErrFormatMismatch, "FORMAT_MISMATCH")
+	api.MustRegisterErrorCode(ErrZipEntryTooLarge, "ZIP_ENTRY_TOO_LARGE")
+	api.MustRegisterErrorCode(ErrInvalidSpreadsheetOptions, "INVALID_SPREADSHEET_OPTIONS")
+	api.MustRegisterErrorCode(ErrInvalidCsvOptions, "INVALID_CSV_OPTIONS")
 }
 
 var (
@@ -28,6 +51,249 @@ var (
 	// ErrMalformedPageRanges happens if the page ranges option cannot be
 	// interpreted by LibreOffice.
 	ErrMalformedPageRanges = errors.New("page ranges are malformed")
+
+	// ErrInvalidFilterData happens if the filter data cannot be handled by
+	// the LibreOffice PDF export filter.
+	ErrInvalidFilterData = errors.New("invalid filter data")
+
+	// ErrInvalidMaxImageResolution happens if the maximum image resolution is
+	// not one of the DPI presets of LibreOffice.
+	ErrInvalidMaxImageResolution = errors.New("invalid max image resolution")
+
+	// ErrInvalidJpegQuality happens if the JPEG quality is not between 1 and
+	// 100.
+	ErrInvalidJpegQuality = errors.New("invalid JPEG quality")
+
+	// ErrInvalidPdfVersion happens if the PDF version is not supported by
+	// LibreOffice or conflicts with the PDF/A conformance level.
+	ErrInvalidPdfVersion = errors.New("invalid PDF version")
+
+	// ErrSinglePageNotSupported happens if the single page option is set for
+	// a document the PDF export filter cannot render on a single page.
+	ErrSinglePageNotSupported = errors.New("single page not supported")
+
+	// ErrInvalidOutputFormat happens if the output format does not match any
+	// export filter of LibreOffice.
+	ErrInvalidOutputFormat = errors.New("invalid output format")
+
+	// ErrInvalidPassword happens if LibreOffice cannot open a document with
+	// the given password.
+	ErrInvalidPassword = errors.New("invalid password")
+
+	// ErrCorruptDocument happens if LibreOffice cannot load a document,
+	// even though it supports its format, e.g., because it is truncated.
+	ErrCorruptDocument = errors.New("corrupt document")
+)
+
+// OutputFormats are the formats, other than PDF, LibreOffice is able to
+// export documents to. Each one matches an export filter, which only applies
+// to the documents of the same kind, e.g., xlsx to spreadsheets.
+var OutputFormats = []string{
+	"csv",
+	"doc",
+	"docx",
+	"epub",
+	"html",
+	"odg",
+	"odp",
+	"ods",
+	"odt",
+	"ppt",
+	"pptx",
+	"rtf",
+	"txt",
+	"xls",
+	"xlsx",
+}
+
+// ValidateOutputFormat checks that LibreOffice is able to export documents
+// to a format. An empty format is always valid, as it stands for PDF.
+func ValidateOutputFormat(format string) error {
+	if format == "" || format == "pdf" || slices.Contains(OutputFormats, format) {
+		return nil
+	}
+
+	return fmt.Errorf("output format '%s' is not one of '%s': %w", format, strings.Join(OutputFormats, "', '"), ErrInvalidOutputFormat)
+}
+
+// PdfInitialViews are the panes a PDF viewer may show when opening a PDF,
+// alongside its pages. Their indexes are the values of the InitialView
+// property of the PDF export filter.
+var PdfInitialViews = []string{
+	"none",
+	"outline",
+	"thumbnails",
+}
+
+// PdfMagnifications are the zoom levels a PDF viewer may apply when opening
+// a PDF. Their indexes are the values of the Magnification property of the
+// PDF export filter.
+var PdfMagnifications = []string{
+	"default",
+	"fitPage",
+	"fitWidth",
+	"fitVisible",
+}
+
+// PdfPageLayouts are the layouts a PDF viewer may arrange the pages of a PDF
+// with. Their indexes are the values of the PageLayout property of the PDF
+// export filter.
+var PdfPageLayouts = []string{
+	"default",
+	"singlePage",
+	"oneColumn",
+	"twoColumns",
+}
+
+// PdfFormsTypes are the formats the interactive form fields of a PDF may
+// submit their data with. Their indexes are the values of the FormsType
+// property of the PDF export filter.
+var PdfFormsTypes = []string{
+	"fdf",
+	"pdf",
+	"html",
+	"xml",
+}
+
+// pdfVersions maps the PDF versions to the values of the SelectPdfVersion
+// property of the PDF export filter.
+var pdfVersions = map[string]int{
+	"1.5": 15,
+	"1.6": 16,
+	"1.7": 17,
+	"2.0": 20,
+}
+
+// pdfABaseVersions are the PDF versions the PDF/A conformance levels are
+// based on.
+var pdfABaseVersions = map[string]string{
+	gotenberg.PdfA1b: "1.4",
+	gotenberg.PdfA2b: "1.7",
+	gotenberg.PdfA3b: "1.7",
+}
+
+// ValidatePdfVersion checks that a PDF version is supported by LibreOffice
+// and, if there is a PDF/A conformance level, that the level is based on this
+// version. An empty version is always valid.
+func ValidatePdfVersion(version string, formats gotenberg.PdfFormats) error {
+	if version == "" {
+		return nil
+	}
+
+	if _, ok := pdfVersions[version]; !ok {
+		return fmt.Errorf("PDF version '%s' is not one of '1.5', '1.6', '1.7' or '2.0': %w", version, ErrInvalidPdfVersion)
+	}
+
+	if formats.PdfA == "" {
+		return nil
+	}
+
+	baseVersion, ok := pdfABaseVersions[formats.PdfA]
+	if ok && baseVersion != version {
+		return fmt.Errorf("PDF version '%s' is not compatible with %s, which is based on PDF %s: %w", version, formats.PdfA, baseVersion, ErrInvalidPdfVersion)
+	}
+
+	return nil
+}
+
+// singlePageExtensions are the extensions of the spreadsheet documents, the
+// only ones the PDF export filter renders on a single page.
+var singlePageExtensions = []string{
+	".csv",
+	".dbf",
+	".dif",
+	".fods",
+	".ods",
+	".ots",
+	".pxl",
+	".sdc",
+	".slk",
+	".stc",
+	".sxc",
+	".uos",
+	".xls",
+	".xlt",
+	".xlsx",
+	".xltx",
+}
+
+// SupportsSinglePage tells if LibreOffice is able to render the document
+// with the given filename on a single page, i.e., if it is a spreadsheet.
+func SupportsSinglePage(filename string) bool {
+	return slices.Contains(singlePageExtensions, strings.ToLower(filepath.Ext(filename)))
+}
+
+// textExtensions are the extensions of the text documents, which the "txt"
+// export filter applies to.
+var textExtensions = []string{
+	".bib",
+	".doc",
+	".docx",
+	".dotx",
+	".epub",
+	".fb2",
+	".fodt",
+	".html",
+	".lrf",
+	".ltx",
+	".odt",
+	".ott",
+	".pdb",
+	".psw",
+	".rtf",
+	".sdw",
+	".stw",
+	".sxw",
+	".txt",
+	".uot",
+	".vor",
+	".wps",
+	".xhtml",
+	".xml",
+}
+
+// TextFormat returns the output format which exports the text of the
+// document with the given filename: "txt" for the text documents, "csv" for
+// the spreadsheets, or an empty string for the other documents, e.g., the
+// presentations, as LibreOffice has no text export filter for them.
+func TextFormat(filename string) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	switch {
+	case slices.Contains(textExtensions, ext):
+		return "txt"
+	case slices.Contains(singlePageExtensions, ext):
+		return "csv"
+	default:
+		return ""
+	}
+}
+
+// ebookImportFilters are the import filters of the e-book formats which
+// LibreOffice does not reliably detect by itself.
+var ebookImportFilters = map[string]string{
+	".fb2": "FictionBook 2",
+	".lrf": "BroadBand eBook",
+}
+
+// importFilter returns the import filter of the options or, if none, the
+// import filter of the e-book with the given path, if any.
+func importFilter(inputPath string, options Options) string {
+	if options.ImportFilter != "" {
+		return options.ImportFilter
+	}
+
+	return ebookImportFilters[strings.ToLower(filepath.Ext(inputPath))]
+}
+
+const (
+	// ExportFormFieldsInteractive exports the form fields of a document as
+	// AcroForm fields, which remain fillable in the resulting PDF.
+	ExportFormFieldsInteractive string = "interactive"
+
+	// ExportFormFieldsFlattened exports the form fields of a document as
+	// static content.
+	ExportFormFieldsFlattened string = "flattened"
 )
 
 // Api is a module which provides a [Uno] to interact with LibreOffice.
@@ -35,7 +301,22 @@ type Api struct {
 	autoStart bool
 	args      libreOfficeArguments
 
-	logger      *zap.Logger
+	logger    *zap.Logger
+	instances []*libreOfficeInstance
+	idle      chan *libreOfficeInstance
+	queueSize atomic.Int64
+	usage     *gotenberg.UsageRecorder
+
+	// recycles tracks the instances which restart in the background. Once
+	// stopping, no instance restarts anymore.
+	recyclesMu sync.Mutex
+	recycles   sync.WaitGroup
+	stopping   bool
+}
+
+// libreOfficeInstance is a LibreOffice process with its supervisor. Each
+// instance of the pool handles one conversion at a time.
+type libreOfficeInstance struct {
 	libreOffice libreOffice
 	supervisor  gotenberg.ProcessSupervisor
 }
@@ -59,20 +340,273 @@ type Options struct {
 	// spreadsheets with many columns.
 	HTMLformat bool
 
+	// HtmlBundle is how to package the HTML output with its resources, e.g.,
+	// its images, either [HtmlBundleInline] or [HtmlBundleZip]. Empty equals
+	// [HtmlBundleInline]. Only used by [Uno.Html].
+	// Optional.
+	HtmlBundle string
+
+	// OutputFormat is the format, other than PDF, to export the document to.
+	// It must be one of [OutputFormats]. Only used by [Uno.Export].
+	OutputFormat string
+
+	// Password allows to open an encrypted document. It is never logged.
+	// Optional.
+	Password string
+
 	// Optionally set the import filter to use.
 	ImportFilter string
 
 	// Optionally add import filter options.
 	ImportOptions string
+
+	// ExportComments allows to export the comments of the document.
+	// Optional.
+	ExportComments bool
+
+	// ExportCommentsInMargin allows to render the exported comments in the
+	// page margin instead of as PDF annotations.
+	// Optional.
+	ExportCommentsInMargin bool
+
+	// ExportNotesPages allows to export the speaker notes of the
+	// presentations as additional pages, after the slides. It does nothing
+	// for the other documents.
+	// Optional.
+	ExportNotesPages bool
+
+	// ExportHiddenSlides allows to export the hidden slides of the
+	// presentations. It does nothing for the other documents.
+	// Optional.
+	ExportHiddenSlides bool
+
+	// ReduceImageResolution allows to downsample the images of the document
+	// to MaxImageResolution.
+	// Optional.
+	ReduceImageResolution bool
+
+	// MaxImageResolution is the resolution, in DPI, to downsample the images
+	// to. Either 75, 150, 300, 600 or 1200.
+	// Optional.
+	MaxImageResolution int
+
+	// JpegQuality is the quality, from 1 to 100, of the JPEG compression of
+	// the images. Zero keeps the default of LibreOffice, i.e., 90.
+	// Optional.
+	JpegQuality int
+
+	// LosslessImageCompression allows to compress the images without loss
+	// of quality, instead of with JPEG. JpegQuality does not apply then.
+	// Optional.
+	LosslessImageCompression bool
+
+	// UserPassword encrypts the resulting PDF, which then requires this
+	// password to be opened. It is never logged.
+	// Optional.
+	UserPassword string
+
+	// OwnerPassword restricts the permissions of the resulting PDF to
+	// AllowPrinting, AllowCopying and AllowModifying; this password lifts
+	// the restrictions. It is never logged.
+	// Optional.
+	OwnerPassword string
+
+	// AllowPrinting allows to print the resulting PDF, in high resolution.
+	// It only applies with OwnerPassword.
+	// Optional.
+	AllowPrinting bool
+
+	// AllowCopying allows to copy the content of the resulting PDF. It only
+	// applies with OwnerPassword.
+	// Optional.
+	AllowCopying bool
+
+	// AllowModifying allows to modify the resulting PDF, except for
+	// extracting its pages. It only applies with OwnerPassword.
+	// Optional.
+	AllowModifying bool
+
+	// PdfVersion is the version of the resulting PDF, either 1.5, 1.6, 1.7
+	// or 2.0. It must match the version the PDF/A conformance level, if any,
+	// is based on.
+	// Optional.
+	PdfVersion string
+
+	// ExportFormFields sets how to export the form fields of the document,
+	// either [ExportFormFieldsInteractive] or [ExportFormFieldsFlattened].
+	// Empty keeps the default of LibreOffice, i.e., interactive fields.
+	// Optional.
+	ExportFormFields string
+
+	// FormsType is the format the interactive form fields of the resulting
+	// PDF submit their data with, i.e., one of [PdfFormsTypes]. It implies
+	// [ExportFormFieldsInteractive]. Empty keeps the default of LibreOffice,
+	// i.e., fdf.
+	// Optional.
+	FormsType string
+
+	// SkipBookmarks allows not to export the headings of the document as
+	// PDF bookmarks.
+	// Optional.
+	SkipBookmarks bool
+
+	// InitialView is the pane a PDF viewer shows when opening the resulting
+	// PDF, i.e., one of [PdfInitialViews]. Empty keeps the default of
+	// LibreOffice, i.e., none.
+	// Optional.
+	InitialView string
+
+	// Magnification is the zoom level a PDF viewer applies when opening the
+	// resulting PDF, i.e., one of [PdfMagnifications]. Empty keeps the
+	// default of LibreOffice.
+	// Optional.
+	Magnification string
+
+	// PageLayout is the layout a PDF viewer arranges the pages of the
+	// resulting PDF with, i.e., one of [PdfPageLayouts]. Empty keeps the
+	// default of LibreOffice.
+	// Optional.
+	PageLayout string
+
+	// ExportBookmarksToPdfDestination allows to export the bookmarks of the
+	// document as named destinations, so that links may target them.
+	// Optional.
+	ExportBookmarksToPdfDestination bool
+
+	// ExportLinksRelativeFsys allows to export the links to other files as
+	// relative to the file system.
+	// Optional.
+	ExportLinksRelativeFsys bool
+
+	// SinglePage renders each sheet of a spreadsheet on a single page which
+	// grows to fit its content. Other documents return
+	// [ErrSinglePageNotSupported].
+	// Optional.
+	SinglePage bool
+
+	// Spreadsheet gathers the print options of the spreadsheets. They only
+	// apply to the XLSX and ODS documents.
+	// Optional.
+	Spreadsheet SpreadsheetOptions
+
+	// TrackedChanges sets how to render the tracked changes of a text
+	// document, e.g., DOCX, DOC, ODT or RTF, either [TrackedChangesShow],
+	// [TrackedChangesAccept] or [TrackedChangesReject], as LibreOffice does.
+	// Empty renders them according to the state the document was saved in.
+	// Other documents return [ErrTrackedChangesNotSupported].
+	// Optional.
+	TrackedChanges string
+
+	// Fonts are the fonts to install for the conversion, so that the
+	// document renders with them even if they are not installed system-wide.
+	// As LibreOffice only reads its font directories when it starts, the
+	// conversion runs on a dedicated LibreOffice instance.
+	// Optional.
+	Fonts []gotenberg.Font
+
+	// Locale is the BCP 47 language tag, e.g., "de-DE", of the locale
+	// setting of LibreOffice for the conversion, so that the fields and the
+	// number and date formats of the document, e.g., those of a spreadsheet,
+	// do not render according to the locale of the container. It is also the
+	// default language of the documents; the text with an explicit language
+	// keeps it. The conversion runs on a dedicated LibreOffice instance.
+	// Optional.
+	Locale string
+
+	// Timezone is the IANA timezone, e.g., "Europe/Berlin", LibreOffice
+	// evaluates the current date and time in for the conversion, e.g., the
+	// date fields and the NOW function of the spreadsheets. The conversion
+	// runs on a dedicated LibreOffice instance.
+	// Optional.
+	Timezone string
+
+	// UpdateIndexes allows to refresh the indexes and fields of the document,
+	// e.g., the tables of contents, the page numbers and the cross-references,
+	// before the export. It does nothing for the documents without such
+	// indexes, e.g., spreadsheets.
+	// Optional.
+	UpdateIndexes bool
+
+	// MacroSecurity tells if LibreOffice executes the macros of the document,
+	// one of [MacroSecurities]. Empty equals [MacroSecurityDisable].
+	// Optional.
+	MacroSecurity string
+
+	// AllowExternalLinks allows LibreOffice to update the links of the
+	// document to external resources, e.g., linked images or sheets, while
+	// loading it. Otherwise, the document renders with their cached content.
+	// Optional.
+	AllowExternalLinks bool
+
+	// RepairMode allows LibreOffice to attempt to repair a document it
+	// cannot load, i.e., a conversion which fails with [ErrCorruptDocument]
+	// runs again with the RepairPackage load property.
+	// Optional.
+	RepairMode bool
+
+	// repairPackage sets the RepairPackage load property, see RepairMode.
+	repairPackage bool
+
+	// FilterData allows to set the properties of the PDF export filter. The
+	// dedicated options, like PageRanges, take precedence over it.
+	// Optional.
+	FilterData map[string]interface{}
+
+	// ExportFilterOptions is the options string of the export filter, passed
+	// as is, e.g., "44,34,UTF8" for the CSV export filter.
+	// Optional.
+	ExportFilterOptions string
+
+	// Csv gathers the options of the CSV export. If set, they take
+	// precedence over ExportFilterOptions. Only used by [Uno.Export] with
+	// the "csv" output format.
+	// Optional.
+	Csv CsvOptions
+}
+
+// dedicated tells if the options apply to a whole LibreOffice instance rather
+// than to a document, e.g., the fonts, so that the conversion requires a
+// LibreOffice instance of its own.
+func (o Options) dedicated() bool {
+	return len(o.Fonts) > 0 || o.Locale != "" || o.Timezone != ""
+}
+
+// redacted returns a copy of the options which is safe to log.
+func (o Options) redacted() Options {
+	if o.Password != "" {
+		o.Password = "[REDACTED]"
+	}
+
+	if o.UserPassword != "" {
+		o.UserPassword = "[REDACTED]"
+	}
+
+	if o.OwnerPassword != "" {
+		o.OwnerPassword = "[REDACTED]"
+	}
+
+	return o
 }
 
 // Uno is an abstraction on top of the Universal Network Objects API.
 type Uno interface {
 	Pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
 	Html(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	Export(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	Probe(ctx context.Context, logger *zap.Logger, inputPath string) (DocumentProperties, error)
 	Extensions() []string
 }
 
+// ConcurrentUno is a [Uno] which handles several conversions at a time,
+// e.g., thanks to several LibreOffice instances.
+type ConcurrentUno interface {
+	Uno
+
+	// Concurrency returns the number of conversions the [Uno] handles at a
+	// time.
+	Concurrency() int
+}
+
 // Provider is a module interface which exposes a method for creating a
 // [Uno] for other modules.
 //
@@ -91,8 +625,12 @@ func (a *Api) Descriptor() gotenberg.ModuleDescriptor {
 		FlagSet: func() *flag.FlagSet {
 			fs := flag.NewFlagSet("api", flag.ExitOnError)
 			fs.Int64("libreoffice-restart-after", 10, "Number of conversions after which LibreOffice will automatically restart. Set to 0 to disable this feature")
+			fs.Duration("libreoffice-restart-after-duration", 0, "Duration after which LibreOffice will automatically restart, once the current conversions are done. Set to 0 to disable this feature")
+			fs.String("libreoffice-max-memory", "0B", "Resident memory size (e.g., 1GB) above which LibreOffice will automatically restart, once the current conversions are done. Set to 0B to disable this feature")
 			fs.Bool("libreoffice-auto-start", false, "Automatically launch LibreOffice upon initialization if set to true; otherwise, LibreOffice will start at the time of the first conversion")
 			fs.Duration("libreoffice-start-timeout", time.Duration(20)*time.Second, "Maximum duration to wait for LibreOffice to start or restart")
+			fs.String("libreoffice-user-profile-template", "", "Directory with the files to copy into the user profile of each LibreOffice instance, e.g., a registrymodifications.xcu file with default fonts or macro security settings")
+			fs.Int("libreoffice-pool-size", 1, "Number of LibreOffice instances in the pool, each handling one conversion at a time")
 
 			return fs
 		}(),
@@ -104,6 +642,12 @@ func (a *Api) Descriptor() gotenberg.ModuleDescriptor {
 func (a *Api) Provision(ctx *gotenberg.Context) error {
 	flags := ctx.ParsedFlags()
 	a.autoStart = flags.MustBool("libreoffice-auto-start")
+	poolSize := flags.MustInt("libreoffice-pool-size")
+
+	maxMemory, err := bytes.Parse(flags.MustString("libreoffice-max-memory"))
+	if err != nil {
+		return fmt.Errorf("parse LibreOffice max memory: %w", err)
+	}
 
 	libreOfficeBinPath, ok := os.LookupEnv("LIBREOFFICE_BIN_PATH")
 	if !ok {
@@ -115,10 +659,20 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 		return errors.New("UNOCONVERTER_BIN_PATH environment variable is not set")
 	}
 
+	unoDocumentBinPath, ok := os.LookupEnv("UNODOCUMENT_BIN_PATH")
+	if !ok {
+		return errors.New("UNODOCUMENT_BIN_PATH environment variable is not set")
+	}
+
 	a.args = libreOfficeArguments{
-		binPath:      libreOfficeBinPath,
-		unoBinPath:   unoBinPath,
-		startTimeout: flags.MustDuration("libreoffice-start-timeout"),
+		binPath:              libreOfficeBinPath,
+		unoBinPath:           unoBinPath,
+		unoDocumentBinPath:   unoDocumentBinPath,
+		startTimeout:         flags.MustDuration("libreoffice-start-timeout"),
+		restartAfterDuration: flags.MustDuration("libreoffice-restart-after-duration"),
+		maxMemory:            maxMemory,
+
+		userProfileTemplateDirPath: flags.MustString("libreoffice-user-profile-template"),
 	}
 
 	// Logger.
@@ -132,9 +686,18 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 	}
 	a.logger = logger.Named("libreoffice")
 
-	// Process.
-	a.libreOffice = newLibreOfficeProcess(a.args)
-	a.supervisor = gotenberg.NewProcessSupervisor(a.logger, a.libreOffice, flags.MustInt64("libreoffice-restart-after"))
+	// Processes.
+	instances := make([]*libreOfficeInstance, max(poolSize, 0))
+	for i := range instances {
+		process := newLibreOfficeProcess(a.args)
+		instances[i] = &libreOfficeInstance{
+			libreOffice: process,
+			supervisor:  gotenberg.NewProcessSupervisor(a.logger, process, flags.MustInt64("libreoffice-restart-after")),
+		}
+	}
+
+	a.setInstances(instances...)
+	a.usage = gotenberg.NewUsageRecorder()
 
 	return nil
 }
@@ -153,17 +716,51 @@ func (a *Api) Validate() error {
 		err = multierr.Append(err, fmt.Errorf("unoconverter binary path does not exist: %w", statErr))
 	}
 
+	_, statErr = os.Stat(a.args.unoDocumentBinPath)
+	if os.IsNotExist(statErr) {
+		err = multierr.Append(err, fmt.Errorf("unodocument binary path does not exist: %w", statErr))
+	}
+
+	if a.args.restartAfterDuration < 0 {
+		err = multierr.Append(err, errors.New("LibreOffice restart after duration must be positive"))
+	}
+
+	if a.args.maxMemory < 0 {
+		err = multierr.Append(err, errors.New("LibreOffice max memory must be positive"))
+	}
+
+	if a.args.userProfileTemplateDirPath != "" {
+		info, statErr := os.Stat(a.args.userProfileTemplateDirPath)
+		if statErr != nil {
+			err = multierr.Append(err, fmt.Errorf("LibreOffice user profile template: %w", statErr))
+		} else if !info.IsDir() {
+			err = multierr.Append(err, errors.New("LibreOffice user profile template must be a directory"))
+		}
+	}
+
+	if len(a.instances) < 1 {
+		err = multierr.Append(err, errors.New("LibreOffice pool size must be at least 1"))
+	}
+
 	return err
 }
 
-// Start does nothing if auto-start is not enabled. Otherwise, it starts a
-// LibreOffice instance.
+// Start does nothing if auto-start is not enabled. Otherwise, it warms up the
+// pool, i.e., it starts all the LibreOffice instances concurrently.
 func (a *Api) Start() error {
 	if !a.autoStart {
 		return nil
 	}
 
-	err := a.supervisor.Launch()
+	eg := new(errgroup.Group)
+	for _, instance := range a.instances {
+		instance := instance
+		eg.Go(func() error {
+			return instance.supervisor.Launch()
+		})
+	}
+
+	err := eg.Wait()
 	if err != nil {
 		return fmt.Errorf("launch supervisor: %w", err)
 	}
@@ -188,7 +785,19 @@ func (a *Api) Stop(ctx context.Context) error {
 
 	<-ctx.Done()
 
-	err := a.supervisor.Shutdown()
+	// An instance which restarts in the background would otherwise run
+	// again after its shutdown.
+	a.recyclesMu.Lock()
+	a.stopping = true
+	a.recyclesMu.Unlock()
+
+	a.recycles.Wait()
+
+	var err error
+	for _, instance := range a.instances {
+		err = multierr.Append(err, instance.supervisor.Shutdown())
+	}
+
 	if err == nil {
 		return nil
 	}
@@ -203,27 +812,67 @@ func (a *Api) Metrics() ([]gotenberg.Metric, error) {
 			Name:        "libreoffice_requests_queue_size",
 			Description: "Current number of LibreOffice conversion requests waiting to be treated.",
 			Read: func() float64 {
-				return float64(a.supervisor.ReqQueueSize())
+				queueSize := a.queueSize.Load()
+				for _, instance := range a.instances {
+					queueSize += instance.supervisor.ReqQueueSize()
+				}
+
+				return float64(queueSize)
 			},
 		},
 		{
 			Name:        "libreoffice_restarts_count",
 			Description: "Current number of LibreOffice restarts.",
 			Read: func() float64 {
-				return float64(a.supervisor.RestartsCount())
+				var restartsCount int64
+				for _, instance := range a.instances {
+					restartsCount += instance.supervisor.RestartsCount()
+				}
+
+				return float64(restartsCount)
+			},
+		},
+		{
+			Name:        "libreoffice_pool_idle_instances",
+			Description: "Current number of LibreOffice instances of the pool waiting for a conversion.",
+			Read: func() float64 {
+				return float64(len(a.idle))
 			},
 		},
+		{
+			Name:        "libreoffice_memory_bytes",
+			Description: "Current resident memory, in bytes, of the LibreOffice processes.",
+			Read: func() float64 {
+				return float64(a.memory())
+			},
+		},
+		{
+			Name:        "libreoffice_cpu_seconds",
+			Description: "Cumulative CPU time, in seconds, of the LibreOffice processes during the conversions, by route.",
+			Label:       "route",
+			ReadByLabel: a.usage.CpuSeconds,
+		},
+		{
+			Name:        "libreoffice_peak_memory_bytes",
+			Description: "Peak resident memory, in bytes, of the LibreOffice processes during the last conversion, by route.",
+			Label:       "route",
+			ReadByLabel: a.usage.PeakRss,
+		},
 	}, nil
 }
 
-// Checks adds a health check that verifies if LibreOffice is healthy.
+// Checks adds a health check that verifies if LibreOffice is healthy. The
+// pool stays healthy as long as one of its instances is, as an unhealthy
+// instance restarts before handling another conversion.
 func (a *Api) Checks() ([]health.CheckerOption, error) {
 	return []health.CheckerOption{
 		health.WithCheck(health.Check{
 			Name: "libreoffice",
 			Check: func(_ context.Context) error {
-				if a.supervisor.Healthy() {
-					return nil
+				for _, instance := range a.instances {
+					if instance.supervisor.Healthy() {
+						return nil
+					}
 				}
 
 				return errors.New("LibreOffice is unhealthy")
@@ -249,7 +898,11 @@ func (a *Api) Ready() error {
 			ticker.Stop()
 			return fmt.Errorf("context done while waiting for LibreOffice to be ready: %w", ctx.Err())
 		case <-ticker.C:
-			ok := a.libreOffice.Healthy(a.logger)
+			ok := true
+			for _, instance := range a.instances {
+				ok = ok && instance.libreOffice.Healthy(a.logger)
+			}
+
 			if ok {
 				ticker.Stop()
 				return nil
@@ -267,16 +920,264 @@ func (a *Api) LibreOffice() (Uno, error) {
 
 // Pdf converts a document to PDF.
 func (a *Api) Pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
-	return a.supervisor.Run(ctx, logger, func() error {
-		return a.libreOffice.pdf(ctx, logger, inputPath, outputPath, options)
+	ctx, span := gotenberg.StartSpan(ctx, "libreoffice.pdf", "libreoffice", 1)
+	err := a.run(ctx, logger, options, func(libreOffice libreOffice) error {
+		return withRepair(logger, options, func(options Options) error {
+			return libreOffice.pdf(ctx, logger, inputPath, outputPath, options)
+		})
 	})
+	gotenberg.EndSpan(span, outputPath, err)
+
+	return err
 }
 
 // Html converts a document to PDF.
 func (a *Api) Html(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
-	return a.supervisor.Run(ctx, logger, func() error {
-		return a.libreOffice.html(ctx, logger, inputPath, outputPath, options)
+	ctx, span := gotenberg.StartSpan(ctx, "libreoffice.html", "libreoffice", 1)
+	err := a.run(ctx, logger, options, func(libreOffice libreOffice) error {
+		return withRepair(logger, options, func(options Options) error {
+			return libreOffice.html(ctx, logger, inputPath, outputPath, options)
+		})
+	})
+	gotenberg.EndSpan(span, outputPath, err)
+
+	return err
+}
+
+// Export converts a document to the output format of the options.
+func (a *Api) Export(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
+	ctx, span := gotenberg.StartSpan(ctx, "libreoffice.export", "libreoffice", 1)
+	err := a.run(ctx, logger, options, func(libreOffice libreOffice) error {
+		return withRepair(logger, options, func(options Options) error {
+			return libreOffice.export(ctx, logger, inputPath, outputPath, options)
+		})
+	})
+	gotenberg.EndSpan(span, outputPath, err)
+
+	return err
+}
+
+// Probe returns the properties of a document, see [ProbeDocument]. If its
+// metadata do not tell its counts, e.g., for the legacy binary formats,
+// LibreOffice lays the document out to count its pages, slides or sheets. A
+// document LibreOffice cannot load, e.g., an encrypted one, keeps unknown
+// counts.
+func (a *Api) Probe(ctx context.Context, logger *zap.Logger, inputPath string) (DocumentProperties, error) {
+	properties, err := ProbeDocument(inputPath)
+	if err != nil {
+		return DocumentProperties{}, err
+	}
+
+	if properties.Pages != 0 || properties.Slides != 0 || properties.Sheets != 0 {
+		return properties, nil
+	}
+
+	ctx, span := gotenberg.StartSpan(ctx, "libreoffice.probe", "libreoffice", 1)
+	var statistics documentStatistics
+	err = a.run(ctx, logger, Options{}, func(libreOffice libreOffice) error {
+		var err error
+		statistics, err = libreOffice.statistics(ctx, logger, inputPath, Options{})
+		return err
 	})
+	gotenberg.EndSpan(span, inputPath, err)
+
+	if errors.Is(err, ErrCorruptDocument) || errors.Is(err, errUnoDocumentNotSupported) {
+		logger.Debug(fmt.Sprintf("no statistics for '%s': %v", filepath.Base(inputPath), err))
+		return properties, nil
+	}
+
+	if err != nil {
+		return DocumentProperties{}, fmt.Errorf("get statistics: %w", err)
+	}
+
+	properties.Pages = statistics.Pages
+	properties.Slides = statistics.Slides
+	properties.Sheets = statistics.Sheets
+
+	return properties, nil
+}
+
+// withRepair runs a conversion. If LibreOffice cannot load the document and
+// the repair mode is enabled, it runs the conversion again, with LibreOffice
+// attempting to repair the document.
+func withRepair(logger *zap.Logger, options Options, convert func(options Options) error) error {
+	err := convert(options)
+	if !options.RepairMode || !errors.Is(err, ErrCorruptDocument) {
+		return err
+	}
+
+	logger.Debug("cannot load the document, attempting to repair it...")
+	options.repairPackage = true
+
+	return convert(options)
+}
+
+// Concurrency returns the size of the LibreOffice pool.
+func (a *Api) Concurrency() int {
+	return len(a.instances)
+}
+
+// setInstances sets the LibreOffice instances, all idle.
+func (a *Api) setInstances(instances ...*libreOfficeInstance) {
+	a.instances = instances
+	a.idle = make(chan *libreOfficeInstance, len(instances))
+
+	for _, instance := range instances {
+		a.idle <- instance
+	}
+}
+
+// run waits for an idle LibreOffice instance, then runs a task with it
+// thanks to its supervisor. If the options require a LibreOffice instance of
+// their own, the task runs with a dedicated one instead, see runDedicated.
+func (a *Api) run(ctx context.Context, logger *zap.Logger, options Options, task func(libreOffice libreOffice) error) error {
+	var instance *libreOfficeInstance
+
+	a.queueSize.Add(1)
+	select {
+	case instance = <-a.idle:
+		a.queueSize.Add(-1)
+	case <-ctx.Done():
+		a.queueSize.Add(-1)
+		return fmt.Errorf("acquire LibreOffice instance: %w", ctx.Err())
+	}
+
+	defer a.release(instance)
+
+	if options.dedicated() {
+		return a.runDedicated(ctx, logger, options, task)
+	}
+
+	// Note: no error wrapping, like the supervisor.
+	return instance.supervisor.Run(ctx, logger, func() error {
+		return a.usage.Measure(ctx, logger, instance.libreOffice, func() error {
+			return task(instance.libreOffice)
+		})
+	})
+}
+
+// runDedicated runs a task with a LibreOffice instance which starts with the
+// options which apply to a whole instance, e.g., the fonts, and stops right
+// after. The caller holds an instance of the pool in the meantime, so that
+// the number of concurrent conversions stays the same.
+func (a *Api) runDedicated(ctx context.Context, logger *zap.Logger, options Options, task func(libreOffice libreOffice) error) error {
+	if options.Timezone != "" {
+		_, err := time.LoadLocation(options.Timezone)
+		if err != nil {
+			return fmt.Errorf("load timezone '%s': %w", options.Timezone, err)
+		}
+	}
+
+	args := a.args
+	args.fonts = options.Fonts
+	args.locale = options.Locale
+	args.timezone = options.Timezone
+
+	process := newLibreOfficeProcess(args)
+
+	logger.Debug("starting a dedicated LibreOffice instance...")
+
+	err := process.Start(logger)
+	if err != nil {
+		return fmt.Errorf("start dedicated LibreOffice instance: %w", err)
+	}
+
+	defer func() {
+		err := process.Stop(logger)
+		if err != nil {
+			logger.Error(fmt.Sprintf("stop dedicated LibreOffice instance: %v", err))
+		}
+	}()
+
+	return a.usage.Measure(ctx, logger, process, func() error {
+		return task(process)
+	})
+}
+
+// release returns a LibreOffice instance to the pool. An instance which has
+// to restart after its task, e.g., because LibreOffice crashed during the
+// conversion or uses too much memory, first restarts in the background, so
+// that the next conversions go to the other instances in the meantime.
+func (a *Api) release(instance *libreOfficeInstance) {
+	reason := a.recycleReason(instance)
+	if reason == "" {
+		a.idle <- instance
+		return
+	}
+
+	a.recyclesMu.Lock()
+	defer a.recyclesMu.Unlock()
+
+	if a.stopping {
+		// The instance shuts down anyway.
+		a.idle <- instance
+		return
+	}
+
+	a.recycles.Add(1)
+	go func() {
+		defer func() {
+			a.idle <- instance
+			a.recycles.Done()
+		}()
+
+		a.logger.Info(fmt.Sprintf("%s, restarting it before returning it to the pool...", reason))
+
+		ctx, cancel := context.WithTimeout(context.Background(), a.args.startTimeout)
+		defer cancel()
+
+		// The supervisor restarts an unhealthy or recyclable process before
+		// any task.
+		err := instance.supervisor.Run(ctx, a.logger, func() error {
+			return nil
+		})
+		if err != nil {
+			a.logger.Error(fmt.Sprintf("restart LibreOffice instance: %s", err))
+		}
+	}()
+}
+
+// recycleReason returns why a LibreOffice instance has to restart before
+// handling another conversion, or an empty string if it does not.
+func (a *Api) recycleReason(instance *libreOfficeInstance) string {
+	if !instance.supervisor.Healthy() {
+		return "LibreOffice instance is unhealthy"
+	}
+
+	recyclable, ok := instance.libreOffice.(gotenberg.RecyclableProcess)
+	if !ok {
+		return ""
+	}
+
+	return recyclable.RestartReason(a.logger)
+}
+
+// memory returns the resident memory size, in bytes, of the LibreOffice
+// instances of the pool which are running.
+func (a *Api) memory() int64 {
+	var memory int64
+	for _, instance := range a.instances {
+		identifier, ok := instance.libreOffice.(gotenberg.ProcessIdentifier)
+		if !ok {
+			continue
+		}
+
+		pid := identifier.Pid()
+		if pid == 0 {
+			continue
+		}
+
+		// The process group ID is the PID of the command, see gotenberg.Command.
+		instanceMemory, err := processGroupMemory(pid)
+		if err != nil {
+			a.logger.Debug(fmt.Sprintf("get LibreOffice memory usage: %v", err))
+			continue
+		}
+
+		memory += instanceMemory
+	}
+
+	return memory
 }
 
 // Extensions returns the file extensions available for conversions.
@@ -304,6 +1205,8 @@ func (a *Api) Extensions() []string {
 		".vor",
 		".wps",
 		".epub",
+		".fb2",
+		".lrf",
 		".png",
 		".bmp",
 		".emf",
@@ -374,5 +1277,6 @@ var (
 	_ gotenberg.MetricsProvider = (*Api)(nil)
 	_ api.HealthChecker         = (*Api)(nil)
 	_ Uno                       = (*Api)(nil)
+	_ ConcurrentUno             = (*Api)(nil)
 	_ Provider                  = (*Api)(nil)
 )
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -68,6 +69,24 @@ func TestApi_Provision(t *testing.T) {
 			}(),
 			expectError: true,
 		},
+		{
+			scenario: "invalid LibreOffice max memory",
+			ctx: func() *gotenberg.Context {
+				fs := new(Api).Descriptor().FlagSet
+				err := fs.Parse([]string{"--libreoffice-max-memory=foo"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: fs,
+					},
+					[]gotenberg.ModuleDescriptor{},
+				)
+			}(),
+			expectError: true,
+		},
 		{
 			scenario: "provision success",
 			ctx: func() *gotenberg.Context {
@@ -110,48 +129,114 @@ func TestApi_Provision(t *testing.T) {
 
 func TestApi_Validate(t *testing.T) {
 	for _, tc := range []struct {
-		scenario    string
-		binPath     string
-		unoBinPath  string
-		expectError bool
+		scenario             string
+		binPath              string
+		unoBinPath           string
+		unoDocumentBinPath   string
+		restartAfterDuration time.Duration
+		maxMemory            int64
+		userProfileTemplate  string
+		instances            int
+		expectError          bool
 	}{
 		{
-			scenario:    "empty LibreOffice bin path",
-			binPath:     "",
-			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
-			expectError: true,
+			scenario:           "empty LibreOffice bin path",
+			binPath:            "",
+			unoBinPath:         os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoDocumentBinPath: os.Getenv("UNODOCUMENT_BIN_PATH"),
+			expectError:        true,
 		},
 		{
-			scenario:    "LibreOffice bin path does not exist",
-			binPath:     "/foo",
-			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
-			expectError: true,
+			scenario:           "LibreOffice bin path does not exist",
+			binPath:            "/foo",
+			unoBinPath:         os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoDocumentBinPath: os.Getenv("UNODOCUMENT_BIN_PATH"),
+			expectError:        true,
 		},
 		{
-			scenario:    "empty uno bin path",
-			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
-			unoBinPath:  "",
-			expectError: true,
+			scenario:           "empty uno bin path",
+			binPath:            os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:         "",
+			unoDocumentBinPath: os.Getenv("UNODOCUMENT_BIN_PATH"),
+			expectError:        true,
 		},
 		{
-			scenario:    "uno bin path does not exist",
-			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
-			unoBinPath:  "/foo",
-			expectError: true,
+			scenario:           "uno bin path does not exist",
+			binPath:            os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:         "/foo",
+			unoDocumentBinPath: os.Getenv("UNODOCUMENT_BIN_PATH"),
+			expectError:        true,
 		},
 		{
-			scenario:    "validate success",
-			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
-			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
-			expectError: false,
+			scenario:           "unodocument bin path does not exist",
+			binPath:            os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:         os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoDocumentBinPath: "/foo",
+			expectError:        true,
+		},
+		{
+			scenario:             "negative restart after duration",
+			binPath:              os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:           os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoDocumentBinPath:   os.Getenv("UNODOCUMENT_BIN_PATH"),
+			restartAfterDuration: -time.Second,
+			expectError:          true,
+		},
+		{
+			scenario:           "negative max memory",
+			binPath:            os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:         os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoDocumentBinPath: os.Getenv("UNODOCUMENT_BIN_PATH"),
+			maxMemory:          -1,
+			expectError:        true,
+		},
+		{
+			scenario:            "LibreOffice user profile template does not exist",
+			binPath:             os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:          os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoDocumentBinPath:  os.Getenv("UNODOCUMENT_BIN_PATH"),
+			userProfileTemplate: "/foo",
+			instances:           1,
+			expectError:         true,
+		},
+		{
+			scenario:            "LibreOffice user profile template is not a directory",
+			binPath:             os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:          os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoDocumentBinPath:  os.Getenv("UNODOCUMENT_BIN_PATH"),
+			userProfileTemplate: "/etc/passwd",
+			instances:           1,
+			expectError:         true,
+		},
+		{
+			scenario:           "empty LibreOffice pool",
+			binPath:            os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:         os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoDocumentBinPath: os.Getenv("UNODOCUMENT_BIN_PATH"),
+			instances:          0,
+			expectError:        true,
+		},
+		{
+			scenario:           "validate success",
+			binPath:            os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:         os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoDocumentBinPath: os.Getenv("UNODOCUMENT_BIN_PATH"),
+			instances:          2,
+			expectError:        false,
 		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			a := new(Api)
 			a.args = libreOfficeArguments{
-				binPath:    tc.binPath,
-				unoBinPath: tc.unoBinPath,
+				binPath:              tc.binPath,
+				unoBinPath:           tc.unoBinPath,
+				unoDocumentBinPath:   tc.unoDocumentBinPath,
+				restartAfterDuration: tc.restartAfterDuration,
+				maxMemory:            tc.maxMemory,
+
+				userProfileTemplateDirPath: tc.userProfileTemplate,
 			}
+			a.setInstances(make([]*libreOfficeInstance, tc.instances)...)
 			err := a.Validate()
 
 			if !tc.expectError && err != nil {
@@ -197,7 +282,7 @@ func TestApi_Start(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			a := new(Api)
 			a.autoStart = tc.autoStart
-			a.supervisor = tc.supervisor
+			a.setInstances(&libreOfficeInstance{supervisor: tc.supervisor})
 
 			err := a.Start()
 
@@ -250,7 +335,7 @@ func TestApi_Stop(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			a := new(Api)
 			a.logger = zap.NewNop()
-			a.supervisor = tc.supervisor
+			a.setInstances(&libreOfficeInstance{supervisor: tc.supervisor})
 
 			ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
 			cancel()
@@ -268,61 +353,189 @@ func TestApi_Stop(t *testing.T) {
 	}
 }
 
-func TestApi_Metrics(t *testing.T) {
+func TestApi_StopWaitsForRecycles(t *testing.T) {
+	var (
+		restarting = make(chan struct{})
+		restart    = make(chan struct{})
+		restarted  atomic.Bool
+	)
+
 	a := new(Api)
-	a.supervisor = &gotenberg.ProcessSupervisorMock{
-		ReqQueueSizeMock: func() int64 {
-			return 10
+	a.logger = zap.NewNop()
+	a.args.startTimeout = time.Duration(5) * time.Second
+	a.setInstances(&libreOfficeInstance{
+		supervisor: &gotenberg.ProcessSupervisorMock{
+			HealthyMock: func() bool {
+				return false
+			},
+			RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+				close(restarting)
+				<-restart
+				restarted.Store(true)
+				return task()
+			},
+			ShutdownMock: func() error {
+				if !restarted.Load() {
+					return errors.New("shutdown during the restart")
+				}
+
+				return nil
+			},
 		},
-		RestartsCountMock: func() int64 {
-			return 0
+	})
+
+	a.release(<-a.idle)
+	<-restarting
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	go func() {
+		time.Sleep(time.Duration(100) * time.Millisecond)
+		close(restart)
+	}()
+
+	err := a.Stop(ctx)
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+}
+
+func TestApi_releaseWhileStopping(t *testing.T) {
+	a := new(Api)
+	a.logger = zap.NewNop()
+	a.setInstances(&libreOfficeInstance{
+		supervisor: &gotenberg.ProcessSupervisorMock{
+			HealthyMock: func() bool {
+				return false
+			},
+			RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+				t.Error("expected no restart once stopping")
+				return nil
+			},
+			ShutdownMock: func() error {
+				return nil
+			},
 		},
+	})
+
+	instance := <-a.idle
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := a.Stop(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
 	}
 
+	a.release(instance)
+
+	if len(a.idle) != 1 {
+		t.Errorf("expected 1 idle instance, but got %d", len(a.idle))
+	}
+}
+
+func TestApi_Metrics(t *testing.T) {
+	a := new(Api)
+	a.setInstances(
+		&libreOfficeInstance{supervisor: &gotenberg.ProcessSupervisorMock{
+			ReqQueueSizeMock: func() int64 {
+				return 10
+			},
+			RestartsCountMock: func() int64 {
+				return 0
+			},
+		}},
+		&libreOfficeInstance{supervisor: &gotenberg.ProcessSupervisorMock{
+			ReqQueueSizeMock: func() int64 {
+				return 2
+			},
+			RestartsCountMock: func() int64 {
+				return 1
+			},
+		}},
+	)
+	a.usage = gotenberg.NewUsageRecorder()
+
 	metrics, err := a.Metrics()
 	if err != nil {
 		t.Fatalf("expected no error but got: %v", err)
 	}
 
-	if len(metrics) != 2 {
-		t.Fatalf("expected %d metrics, but got %d", 2, len(metrics))
+	if len(metrics) != 6 {
+		t.Fatalf("expected %d metrics, but got %d", 6, len(metrics))
 	}
 
 	actual := metrics[0].Read()
-	if actual != float64(10) {
-		t.Errorf("expected %f for libreoffice_requests_queue_size, but got %f", float64(10), actual)
+	if actual != float64(12) {
+		t.Errorf("expected %f for libreoffice_requests_queue_size, but got %f", float64(12), actual)
 	}
 
 	actual = metrics[1].Read()
+	if actual != float64(1) {
+		t.Errorf("expected %f for libreoffice_restarts_count, but got %f", float64(1), actual)
+	}
+
+	actual = metrics[2].Read()
+	if actual != float64(2) {
+		t.Errorf("expected %f for libreoffice_pool_idle_instances, but got %f", float64(2), actual)
+	}
+
+	actual = metrics[3].Read()
 	if actual != float64(0) {
-		t.Errorf("expected %f for libreoffice_restarts_count, but got %f", float64(0), actual)
+		t.Errorf("expected %f for libreoffice_memory_bytes, but got %f", float64(0), actual)
+	}
+
+	for _, metric := range metrics[4:] {
+		if len(metric.ReadByLabel()) != 0 {
+			t.Errorf("expected no value for %s, but got %+v", metric.Name, metric.ReadByLabel())
+		}
 	}
 }
 
 func TestApi_Checks(t *testing.T) {
+	healthy := &gotenberg.ProcessSupervisorMock{HealthyMock: func() bool {
+		return true
+	}}
+	unhealthy := &gotenberg.ProcessSupervisorMock{HealthyMock: func() bool {
+		return false
+	}}
+
 	for _, tc := range []struct {
 		scenario                 string
-		supervisor               gotenberg.ProcessSupervisor
+		supervisors              []gotenberg.ProcessSupervisor
 		expectAvailabilityStatus health.AvailabilityStatus
 	}{
 		{
-			scenario: "healthy module",
-			supervisor: &gotenberg.ProcessSupervisorMock{HealthyMock: func() bool {
-				return true
-			}},
+			scenario:                 "healthy module",
+			supervisors:              []gotenberg.ProcessSupervisor{healthy},
 			expectAvailabilityStatus: health.StatusUp,
 		},
 		{
-			scenario: "unhealthy module",
-			supervisor: &gotenberg.ProcessSupervisorMock{HealthyMock: func() bool {
-				return false
-			}},
+			scenario:                 "unhealthy module",
+			supervisors:              []gotenberg.ProcessSupervisor{unhealthy},
+			expectAvailabilityStatus: health.StatusDown,
+		},
+		{
+			scenario:                 "one unhealthy instance in the pool",
+			supervisors:              []gotenberg.ProcessSupervisor{unhealthy, healthy},
+			expectAvailabilityStatus: health.StatusUp,
+		},
+		{
+			scenario:                 "only unhealthy instances in the pool",
+			supervisors:              []gotenberg.ProcessSupervisor{unhealthy, unhealthy},
 			expectAvailabilityStatus: health.StatusDown,
 		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
+			instances := make([]*libreOfficeInstance, len(tc.supervisors))
+			for i, supervisor := range tc.supervisors {
+				instances[i] = &libreOfficeInstance{supervisor: supervisor}
+			}
+
 			a := new(Api)
-			a.supervisor = tc.supervisor
+			a.setInstances(instances...)
 
 			checks, err := a.Checks()
 			if err != nil {
@@ -379,7 +592,7 @@ func TestChromium_Ready(t *testing.T) {
 			a := new(Api)
 			a.autoStart = tc.autoStart
 			a.args = libreOfficeArguments{startTimeout: tc.startTimeout}
-			a.libreOffice = tc.libreOffice
+			a.setInstances(&libreOfficeInstance{libreOffice: tc.libreOffice})
 
 			err := a.Ready()
 
@@ -427,10 +640,17 @@ func TestApi_Pdf(t *testing.T) {
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			a := new(Api)
-			a.supervisor = &gotenberg.ProcessSupervisorMock{RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
-				return task()
-			}}
-			a.libreOffice = tc.libreOffice
+			a.setInstances(&libreOfficeInstance{
+				libreOffice: tc.libreOffice,
+				supervisor: &gotenberg.ProcessSupervisorMock{
+					HealthyMock: func() bool {
+						return true
+					},
+					RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+						return task()
+					},
+				},
+			})
 
 			err := a.Pdf(context.Background(), zap.NewNop(), "", "", Options{})
 
@@ -445,14 +665,486 @@ func TestApi_Pdf(t *testing.T) {
 	}
 }
 
+func TestApi_Export(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		libreOffice libreOffice
+		expectError bool
+	}{
+		{
+			scenario: "export task success",
+			libreOffice: &libreOfficeMock{exportMock: func(ctx context.Context, logger *zap.Logger, input, outputPath string, options Options) error {
+				return nil
+			}},
+			expectError: false,
+		},
+		{
+			scenario: "export task error",
+			libreOffice: &libreOfficeMock{exportMock: func(ctx context.Context, logger *zap.Logger, input, outputPath string, options Options) error {
+				return errors.New("export task error")
+			}},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			a := new(Api)
+			a.setInstances(&libreOfficeInstance{
+				libreOffice: tc.libreOffice,
+				supervisor: &gotenberg.ProcessSupervisorMock{
+					HealthyMock: func() bool {
+						return true
+					},
+					RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+						return task()
+					},
+				},
+			})
+
+			err := a.Export(context.Background(), zap.NewNop(), "", "", Options{OutputFormat: "docx"})
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestWithRepair(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		options       Options
+		errs          []error
+		expectError   error
+		expectRepairs []bool
+	}{
+		{
+			scenario:      "success",
+			options:       Options{RepairMode: true},
+			errs:          []error{nil},
+			expectRepairs: []bool{false},
+		},
+		{
+			scenario:      "corrupt document without repair mode",
+			options:       Options{},
+			errs:          []error{ErrCorruptDocument},
+			expectError:   ErrCorruptDocument,
+			expectRepairs: []bool{false},
+		},
+		{
+			scenario:      "other error with repair mode",
+			options:       Options{RepairMode: true},
+			errs:          []error{ErrInvalidPassword},
+			expectError:   ErrInvalidPassword,
+			expectRepairs: []bool{false},
+		},
+		{
+			scenario:      "repaired document",
+			options:       Options{RepairMode: true},
+			errs:          []error{ErrCorruptDocument, nil},
+			expectRepairs: []bool{false, true},
+		},
+		{
+			scenario:      "unrepairable document",
+			options:       Options{RepairMode: true},
+			errs:          []error{ErrCorruptDocument, ErrCorruptDocument},
+			expectError:   ErrCorruptDocument,
+			expectRepairs: []bool{false, true},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var repairs []bool
+
+			err := withRepair(zap.NewNop(), tc.options, func(options Options) error {
+				repairs = append(repairs, options.repairPackage)
+				return tc.errs[len(repairs)-1]
+			})
+
+			if !errors.Is(err, tc.expectError) {
+				t.Errorf("expected error %v but got: %v", tc.expectError, err)
+			}
+
+			if !reflect.DeepEqual(repairs, tc.expectRepairs) {
+				t.Errorf("expected repairs %+v but got %+v", tc.expectRepairs, repairs)
+			}
+		})
+	}
+}
+
+func TestApi_Concurrency(t *testing.T) {
+	a := new(Api)
+	a.setInstances(new(libreOfficeInstance), new(libreOfficeInstance))
+
+	if a.Concurrency() != 2 {
+		t.Errorf("expected a concurrency of 2, but got %d", a.Concurrency())
+	}
+}
+
+func TestApi_run(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	a := new(Api)
+	a.setInstances(&libreOfficeInstance{
+		libreOffice: &libreOfficeMock{},
+		supervisor: &gotenberg.ProcessSupervisorMock{
+			HealthyMock: func() bool {
+				return true
+			},
+			RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+				return task()
+			},
+		},
+	})
+
+	go func() {
+		_ = a.run(context.Background(), zap.NewNop(), Options{}, func(libreOffice libreOffice) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+
+	<-started
+
+	// The only instance is busy.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100)*time.Millisecond)
+	defer cancel()
+
+	err := a.run(ctx, zap.NewNop(), Options{}, func(libreOffice libreOffice) error {
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v but got: %v", context.DeadlineExceeded, err)
+	}
+
+	close(release)
+
+	err = a.run(context.Background(), zap.NewNop(), Options{}, func(libreOffice libreOffice) error {
+		return nil
+	})
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+}
+
+func TestApi_runDedicated(t *testing.T) {
+	a := new(Api)
+	a.args = libreOfficeArguments{
+		binPath:      "/foo",
+		startTimeout: time.Duration(5) * time.Second,
+	}
+	a.setInstances(&libreOfficeInstance{
+		libreOffice: &libreOfficeMock{},
+		supervisor: &gotenberg.ProcessSupervisorMock{
+			HealthyMock: func() bool {
+				return true
+			},
+			RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+				return task()
+			},
+		},
+	})
+
+	var called bool
+	err := a.run(context.Background(), zap.NewNop(), Options{Fonts: []gotenberg.Font{{Path: "/foo.ttf"}}}, func(libreOffice libreOffice) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Error("expected error but got none")
+	}
+
+	if called {
+		t.Error("expected the task not to run with the instance of the pool")
+	}
+
+	// The instance of the pool is idle again.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(100)*time.Millisecond)
+	defer cancel()
+
+	err = a.run(ctx, zap.NewNop(), Options{}, func(libreOffice libreOffice) error {
+		return nil
+	})
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+}
+
+func TestApi_release(t *testing.T) {
+	var healthy atomic.Bool
+	restarted := make(chan struct{})
+
+	a := new(Api)
+	a.logger = zap.NewNop()
+	a.args.startTimeout = time.Duration(5) * time.Second
+	a.setInstances(&libreOfficeInstance{
+		supervisor: &gotenberg.ProcessSupervisorMock{
+			HealthyMock: func() bool {
+				return healthy.Load()
+			},
+			RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+				healthy.Store(true)
+				close(restarted)
+				return task()
+			},
+		},
+	})
+
+	instance := <-a.idle
+	a.release(instance)
+
+	select {
+	case <-restarted:
+	case <-time.After(time.Duration(5) * time.Second):
+		t.Fatal("expected the unhealthy instance to restart")
+	}
+
+	select {
+	case <-a.idle:
+	case <-time.After(time.Duration(5) * time.Second):
+		t.Fatal("expected the instance to return to the pool")
+	}
+
+	// A healthy instance returns to the pool right away.
+	a.release(instance)
+
+	if len(a.idle) != 1 {
+		t.Errorf("expected 1 idle instance, but got %d", len(a.idle))
+	}
+}
+
+func TestApi_recycleReason(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		libreOffice  libreOffice
+		healthy      bool
+		expectReason string
+	}{
+		{
+			scenario:     "unhealthy instance",
+			libreOffice:  &libreOfficeMock{},
+			healthy:      false,
+			expectReason: "LibreOffice instance is unhealthy",
+		},
+		{
+			scenario:     "healthy instance",
+			libreOffice:  &libreOfficeMock{},
+			healthy:      true,
+			expectReason: "",
+		},
+		{
+			scenario: "recyclable instance",
+			libreOffice: &recyclableLibreOfficeMock{restartReasonMock: func(logger *zap.Logger) string {
+				return "LibreOffice uses too much memory"
+			}},
+			healthy:      true,
+			expectReason: "LibreOffice uses too much memory",
+		},
+		{
+			scenario: "recyclable instance without restart reason",
+			libreOffice: &recyclableLibreOfficeMock{restartReasonMock: func(logger *zap.Logger) string {
+				return ""
+			}},
+			healthy:      true,
+			expectReason: "",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			a := new(Api)
+			a.logger = zap.NewNop()
+
+			reason := a.recycleReason(&libreOfficeInstance{
+				libreOffice: tc.libreOffice,
+				supervisor: &gotenberg.ProcessSupervisorMock{HealthyMock: func() bool {
+					return tc.healthy
+				}},
+			})
+
+			if reason != tc.expectReason {
+				t.Errorf("expected '%s' but got '%s'", tc.expectReason, reason)
+			}
+		})
+	}
+}
+
 func TestApi_Extensions(t *testing.T) {
 	a := new(Api)
 	extensions := a.Extensions()
 
 	actual := len(extensions)
-	expect := 79
+	expect := 81
 
 	if actual != expect {
 		t.Errorf("expected %d extensions, but got %d", expect, actual)
 	}
 }
+
+func TestValidatePdfVersion(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		version     string
+		formats     gotenberg.PdfFormats
+		expectError bool
+	}{
+		{
+			scenario: "no PDF version",
+			formats:  gotenberg.PdfFormats{PdfA: gotenberg.PdfA1b},
+		},
+		{
+			scenario: "PDF version without PDF/A",
+			version:  "2.0",
+		},
+		{
+			scenario: "PDF version matching PDF/A",
+			version:  "1.7",
+			formats:  gotenberg.PdfFormats{PdfA: gotenberg.PdfA3b},
+		},
+		{
+			scenario:    "unsupported PDF version",
+			version:     "1.4",
+			expectError: true,
+		},
+		{
+			scenario:    "PDF version conflicting with PDF/A",
+			version:     "2.0",
+			formats:     gotenberg.PdfFormats{PdfA: gotenberg.PdfA1b},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := ValidatePdfVersion(tc.version, tc.formats)
+
+			if tc.expectError && !errors.Is(err, ErrInvalidPdfVersion) {
+				t.Fatalf("expected error %v but got: %v", ErrInvalidPdfVersion, err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestOptions_redacted(t *testing.T) {
+	options := Options{Password: "foo", Landscape: true}
+
+	redacted := options.redacted()
+	if redacted.Password == "foo" {
+		t.Error("expected the password to be redacted")
+	}
+
+	if !redacted.Landscape {
+		t.Error("expected the other options to be kept")
+	}
+
+	if options.Password != "foo" {
+		t.Error("expected the options to be left untouched")
+	}
+
+	if (Options{}).redacted().Password != "" {
+		t.Error("expected an empty password to stay empty")
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, tc := range []struct {
+		format      string
+		expectError bool
+	}{
+		{format: ""},
+		{format: "pdf"},
+		{format: "docx"},
+		{format: "xlsx"},
+		{format: "DOCX", expectError: true},
+		{format: "foo", expectError: true},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			err := ValidateOutputFormat(tc.format)
+
+			if tc.expectError && !errors.Is(err, ErrInvalidOutputFormat) {
+				t.Fatalf("expected error %v but got: %v", ErrInvalidOutputFormat, err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestSupportsSinglePage(t *testing.T) {
+	for _, tc := range []struct {
+		filename string
+		expect   bool
+	}{
+		{filename: "sheet.xlsx", expect: true},
+		{filename: "SHEET.ODS", expect: true},
+		{filename: "document.docx", expect: false},
+		{filename: "slides.pptx", expect: false},
+	} {
+		t.Run(tc.filename, func(t *testing.T) {
+			actual := SupportsSinglePage(tc.filename)
+			if actual != tc.expect {
+				t.Errorf("expected %t but got %t", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestTextFormat(t *testing.T) {
+	for _, tc := range []struct {
+		filename string
+		expect   string
+	}{
+		{filename: "document.DOCX", expect: "txt"},
+		{filename: "document.odt", expect: "txt"},
+		{filename: "sheet.xlsx", expect: "csv"},
+		{filename: "sheet.ods", expect: "csv"},
+		{filename: "slides.pptx", expect: ""},
+		{filename: "document.pdf", expect: ""},
+	} {
+		t.Run(tc.filename, func(t *testing.T) {
+			actual := TextFormat(tc.filename)
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestImportFilter(t *testing.T) {
+	for _, tc := range []struct {
+		scenario  string
+		inputPath string
+		options   Options
+		expect    string
+	}{
+		{
+			scenario:  "no import filter",
+			inputPath: "/document.docx",
+			expect:    "",
+		},
+		{
+			scenario:  "e-book import filter",
+			inputPath: "/BOOK.FB2",
+			expect:    "FictionBook 2",
+		},
+		{
+			scenario:  "import filter from options",
+			inputPath: "/book.fb2",
+			options:   Options{ImportFilter: "foo"},
+			expect:    "foo",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := importFilter(tc.inputPath, tc.options)
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// maxZipEntrySize is the maximum size, once uncompressed, of an entry of a
+// document read into memory, e.g., its main XML part.
+const maxZipEntrySize int64 = 128 << 20
+
+// ErrZipEntryTooLarge happens if an entry of a document read into memory
+// exceeds the maximum size once uncompressed, e.g., a ZIP bomb.
+var ErrZipEntryTooLarge = errors.New("ZIP entry too large")
+
+// rewriteZip writes a copy of a ZIP archive, e.g., an OOXML or ODF document,
+// with the entries matched by match given to prepare, which may modify them
+// in place or add new ones.
+func rewriteZip(inputPath, outputPath string, match func(name string) bool, prepare func(files map[string][]byte) error) error {
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	files := make(map[string][]byte)
+	for _, file := range reader.File {
+		if !match(file.Name) {
+			continue
+		}
+
+		content, err := readZipFile(file)
+		if err != nil {
+			return fmt.Errorf("read '%s': %w", file.Name, err)
+		}
+
+		files[file.Name] = content
+	}
+
+	err = prepare(files)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	writer := zip.NewWriter(out)
+
+	// The entries keep their order and compression method, e.g., the
+	// "mimetype" entry of the ODF documents stays first and uncompressed.
+	existing := make(map[string]bool, len(reader.File))
+	for _, file := range reader.File {
+		existing[file.Name] = true
+		header := file.FileHeader
+
+		content, ok := files[file.Name]
+		if !ok {
+			err = writer.Copy(file)
+			if err != nil {
+				return fmt.Errorf("copy '%s': %w", file.Name, err)
+			}
+
+			continue
+		}
+
+		w, err := writer.CreateHeader(&header)
+		if err != nil {
+			return fmt.Errorf("create '%s': %w", file.Name, err)
+		}
+
+		_, err = w.Write(content)
+		if err != nil {
+			return fmt.Errorf("write '%s': %w", file.Name, err)
+		}
+	}
+
+	// The new entries come last, sorted by name.
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if !existing[name] {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		w, err := writer.Create(name)
+		if err != nil {
+			return fmt.Errorf("create '%s': %w", name, err)
+		}
+
+		_, err = w.Write(files[name])
+		if err != nil {
+			return fmt.Errorf("write '%s': %w", name, err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return nil
+}
+
+// readZipFile returns the content of an entry of a ZIP archive. It returns
+// [ErrZipEntryTooLarge] if the entry exceeds the maximum size once
+// uncompressed; the size in the header of the archive is only trusted to
+// reject the entry early.
+func readZipFile(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > uint64(maxZipEntrySize) {
+		return nil, fmt.Errorf("'%s' exceeds %d bytes: %w", file.Name, maxZipEntrySize, ErrZipEntryTooLarge)
+	}
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	content, err := io.ReadAll(io.LimitReader(rc, maxZipEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(content)) > maxZipEntrySize {
+		return nil, fmt.Errorf("'%s' exceeds %d bytes: %w", file.Name, maxZipEntrySize, ErrZipEntryTooLarge)
+	}
+
+	return content, nil
+}
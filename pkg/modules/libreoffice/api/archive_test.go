@@ -0,0 +1,96 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// zeroReader is an endless stream of zeros, which compresses very well,
+// like a ZIP bomb.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestReadZipFile(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		size        int64
+		expectError error
+	}{
+		{
+			scenario: "small entry",
+			size:     1024,
+		},
+		{
+			scenario:    "entry too large",
+			size:        maxZipEntrySize + 1,
+			expectError: ErrZipEntryTooLarge,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "archive.zip")
+
+			f, err := os.Create(path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			writer := zip.NewWriter(f)
+
+			w, err := writer.Create("content.xml")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			_, err = io.CopyN(w, zeroReader{}, tc.size)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = writer.Close()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = f.Close()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			reader, err := zip.OpenReader(path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			defer func() {
+				_ = reader.Close()
+			}()
+
+			content, err := readZipFile(reader.File[0])
+
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected error %v but got: %v", tc.expectError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !bytes.Equal(content, make([]byte, tc.size)) {
+				t.Errorf("expected %d zeros but got %d bytes", tc.size, len(content))
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CsvAllSheets exports each sheet of a spreadsheet to its own CSV file.
+const CsvAllSheets = -1
+
+// ErrInvalidCsvOptions happens if the CSV options cannot be handled by the
+// LibreOffice CSV export filter.
+var ErrInvalidCsvOptions = errors.New("invalid CSV options")
+
+// CsvEncodings are the encodings of the CSV output, with their LibreOffice
+// text encoding identifiers.
+var CsvEncodings = map[string]int{
+	"utf-8":        76,
+	"utf-16":       65535,
+	"iso-8859-1":   12,
+	"iso-8859-15":  22,
+	"windows-1252": 1,
+}
+
+// CsvOptions gathers the options of the CSV export of the spreadsheets.
+type CsvOptions struct {
+	// Delimiter is the field delimiter. Zero equals a comma.
+	// Optional.
+	Delimiter rune
+
+	// Quote is the text delimiter. Zero equals a double quote.
+	// Optional.
+	Quote rune
+
+	// Encoding is one of [CsvEncodings]. Empty equals "utf-8".
+	// Optional.
+	Encoding string
+
+	// QuoteAll quotes all the text cells, not only those which contain the
+	// delimiter, the text delimiter or a line break.
+	// Optional.
+	QuoteAll bool
+
+	// Sheet is the 1-based index of the sheet to export, or [CsvAllSheets]
+	// to export each sheet to its own file, see [CsvSheetPaths]. Zero equals
+	// the first sheet.
+	// Optional.
+	Sheet int
+}
+
+// IsSet tells if at least one option is set.
+func (o CsvOptions) IsSet() bool {
+	return o.Delimiter != 0 || o.Quote != 0 || o.Encoding != "" || o.QuoteAll || o.Sheet != 0
+}
+
+// ValidateCsvOptions checks that the CSV export filter is able to handle the
+// given options.
+func ValidateCsvOptions(options CsvOptions) error {
+	_, err := options.filterOptions()
+	return err
+}
+
+// filterOptions returns the options string of the CSV export filter. The
+// cells are saved as shown, i.e., with their number formats.
+func (o CsvOptions) filterOptions() (string, error) {
+	delimiter := o.Delimiter
+	if delimiter == 0 {
+		delimiter = ','
+	}
+
+	quote := o.Quote
+	if quote == 0 {
+		quote = '"'
+	}
+
+	// The filter options only take UTF-16 code units.
+	for _, char := range []rune{delimiter, quote} {
+		if char > 0xFFFF || char == '\n' || char == '\r' {
+			return "", fmt.Errorf("character %q: %w", char, ErrInvalidCsvOptions)
+		}
+	}
+
+	if delimiter == quote {
+		return "", fmt.Errorf("same delimiter and text delimiter %q: %w", delimiter, ErrInvalidCsvOptions)
+	}
+
+	encoding := o.Encoding
+	if encoding == "" {
+		encoding = "utf-8"
+	}
+
+	charset, ok := CsvEncodings[strings.ToLower(encoding)]
+	if !ok {
+		return "", fmt.Errorf("encoding '%s': %w", o.Encoding, ErrInvalidCsvOptions)
+	}
+
+	if o.Sheet < CsvAllSheets {
+		return "", fmt.Errorf("sheet %d: %w", o.Sheet, ErrInvalidCsvOptions)
+	}
+
+	// See https://help.libreoffice.org/latest/en-US/text/shared/guide/csv_params.html.
+	tokens := []string{
+		strconv.Itoa(int(delimiter)),
+		strconv.Itoa(int(quote)),
+		strconv.Itoa(charset),
+		"1",
+		"",
+		"0",
+		strconv.FormatBool(o.QuoteAll),
+		"true",
+		"true",
+		"false",
+		"false",
+		strconv.Itoa(o.Sheet),
+	}
+
+	return strings.Join(tokens, ","), nil
+}
+
+// CsvSheetPaths returns the paths of the CSV files of each sheet after
+// an export with [CsvAllSheets]. LibreOffice names them after the output
+// path, with the name of the sheet as suffix.
+func CsvSheetPaths(outputPath string) ([]string, error) {
+	ext := filepath.Ext(outputPath)
+
+	paths, err := filepath.Glob(strings.TrimSuffix(outputPath, ext) + "-*" + ext)
+	if err != nil {
+		return nil, fmt.Errorf("glob sheet paths: %w", err)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCsvOptions_filterOptions(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		options     CsvOptions
+		expectError error
+		expect      string
+	}{
+		{
+			scenario: "defaults",
+			options:  CsvOptions{},
+			expect:   "44,34,76,1,,0,false,true,true,false,false,0",
+		},
+		{
+			scenario: "all options",
+			options:  CsvOptions{Delimiter: '\t', Quote: '\'', Encoding: "Windows-1252", QuoteAll: true, Sheet: 3},
+			expect:   "9,39,1,1,,0,true,true,true,false,false,3",
+		},
+		{
+			scenario: "each sheet",
+			options:  CsvOptions{Delimiter: ';', Sheet: CsvAllSheets},
+			expect:   "59,34,76,1,,0,false,true,true,false,false,-1",
+		},
+		{
+			scenario:    "same delimiter and text delimiter",
+			options:     CsvOptions{Delimiter: '"'},
+			expectError: ErrInvalidCsvOptions,
+		},
+		{
+			scenario:    "line break as delimiter",
+			options:     CsvOptions{Delimiter: '\n'},
+			expectError: ErrInvalidCsvOptions,
+		},
+		{
+			scenario:    "character outside the BMP",
+			options:     CsvOptions{Quote: '😀'},
+			expectError: ErrInvalidCsvOptions,
+		},
+		{
+			scenario:    "unknown encoding",
+			options:     CsvOptions{Encoding: "ebcdic"},
+			expectError: ErrInvalidCsvOptions,
+		},
+		{
+			scenario:    "invalid sheet",
+			options:     CsvOptions{Sheet: -2},
+			expectError: ErrInvalidCsvOptions,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := tc.options.filterOptions()
+
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected error %v but got: %v", tc.expectError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestCsvSheetPaths(t *testing.T) {
+	dirPath := t.TempDir()
+
+	for _, name := range []string{"output-Sales.csv", "output-Costs 2024.csv", "other-Sales.csv", "output.txt"} {
+		err := os.WriteFile(filepath.Join(dirPath, name), []byte("a,b"), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	actual, err := CsvSheetPaths(filepath.Join(dirPath, "output.csv"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := []string{
+		filepath.Join(dirPath, "output-Costs 2024.csv"),
+		filepath.Join(dirPath, "output-Sales.csv"),
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+}
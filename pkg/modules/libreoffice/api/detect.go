@@ -0,0 +1,303 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// ErrFormatMismatch happens if the content of a document is another kind of
+// document than its extension tells, e.g., a spreadsheet named
+// "report.docx".
+var ErrFormatMismatch = errors.New("format mismatch")
+
+// formatKinds are the kinds of the formats LibreOffice imports, by format.
+var formatKinds = map[string]string{
+	"doc":  "text",
+	"docx": "text",
+	"odt":  "text",
+	"rtf":  "text",
+	"xls":  "spreadsheet",
+	"xlsx": "spreadsheet",
+	"ods":  "spreadsheet",
+	"ppt":  "presentation",
+	"pptx": "presentation",
+	"odp":  "presentation",
+	"odg":  "drawing",
+	"vsd":  "drawing",
+	"pdf":  "pdf",
+}
+
+// formatVariants are the formats which share the structure of another one,
+// e.g., the templates or the macro-enabled documents, by extension.
+var formatVariants = map[string]string{
+	"docm": "docx",
+	"dotx": "docx",
+	"dotm": "docx",
+	"xlsm": "xlsx",
+	"xltx": "xlsx",
+	"xltm": "xlsx",
+	"pptm": "pptx",
+	"potx": "pptx",
+	"potm": "pptx",
+	"ppsx": "pptx",
+	"ppsm": "pptx",
+	"ott":  "odt",
+	"odm":  "odt",
+	"ots":  "ods",
+	"otp":  "odp",
+	"otg":  "odg",
+	"dot":  "doc",
+	"xlt":  "xls",
+	"pot":  "ppt",
+	"pps":  "ppt",
+}
+
+// formatImportFilters are the LibreOffice import filters, by format.
+var formatImportFilters = map[string]string{
+	"doc":  "MS Word 97",
+	"docx": "MS Word 2007 XML",
+	"odt":  "writer8",
+	"rtf":  "Rich Text Format",
+	"xls":  "MS Excel 97",
+	"xlsx": "Calc MS Excel 2007 XML",
+	"ods":  "calc8",
+	"ppt":  "MS PowerPoint 97",
+	"pptx": "Impress MS PowerPoint 2007 XML",
+	"odp":  "impress8",
+	"odg":  "draw8",
+}
+
+// cfbStreamFormats are the formats of the legacy binary documents, by the
+// name of their main stream.
+var cfbStreamFormats = map[string]string{
+	"WordDocument":        "doc",
+	"Workbook":            "xls",
+	"Book":                "xls",
+	"PowerPoint Document": "ppt",
+	"VisioDocument":       "vsd",
+}
+
+// DetectFormat returns the format of a document according to its content,
+// e.g., "docx" or "rtf", whatever its extension. The variants, e.g., the
+// templates, have the format they share their structure with, e.g., "docx"
+// for a DOTX document. It returns an empty string if the content does not
+// tell, e.g., for the text formats.
+func DetectFormat(inputPath string) (string, error) {
+	header, err := readHeader(inputPath, 8)
+	if err != nil {
+		return "", fmt.Errorf("read header: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("%PDF-")):
+		return "pdf", nil
+	case bytes.HasPrefix(header, []byte(`{\rtf`)):
+		return "rtf", nil
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		return zipFormat(inputPath), nil
+	case bytes.HasPrefix(header, []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")):
+		names, err := cfbStreamNames(inputPath)
+		if err != nil {
+			return "", nil
+		}
+
+		for _, name := range names {
+			if format, ok := cfbStreamFormats[name]; ok {
+				return format, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// DetectImportFilter checks the content of a document against its
+// extension. If the content is another format of the same kind, e.g., an RTF
+// document named "report.doc", it returns the import filter of the actual
+// format, so that LibreOffice does not garble it. If the content is another
+// kind of document, e.g., a spreadsheet named "report.docx", it returns
+// [ErrFormatMismatch]. Otherwise, it returns an empty string.
+func DetectImportFilter(inputPath string) (string, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(inputPath)), ".")
+	if variant, ok := formatVariants[ext]; ok {
+		ext = variant
+	}
+
+	claimedKind, ok := formatKinds[ext]
+	if !ok {
+		return "", nil
+	}
+
+	detected, err := DetectFormat(inputPath)
+	if err != nil {
+		return "", err
+	}
+
+	if detected == "" || detected == ext {
+		return "", nil
+	}
+
+	detectedKind, ok := formatKinds[detected]
+	if !ok || detectedKind != claimedKind {
+		return "", fmt.Errorf("'%s' is a %s document: %w", filepath.Base(inputPath), strings.ToUpper(detected), ErrFormatMismatch)
+	}
+
+	return formatImportFilters[detected], nil
+}
+
+// maxMimetypeSize is the maximum size of the "mimetype" entry of an ODF
+// document.
+const maxMimetypeSize = 256
+
+// zipFormat returns the format of an OOXML or ODF document, or "zip" for
+// any other ZIP archive.
+func zipFormat(inputPath string) string {
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return "zip"
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	for _, file := range reader.File {
+		switch file.Name {
+		case "mimetype":
+			// The longest ODF media type is well below this size; a larger
+			// entry is not an ODF document, and may be a ZIP bomb.
+			if file.UncompressedSize64 > maxMimetypeSize {
+				return "zip"
+			}
+
+			rc, err := file.Open()
+			if err != nil {
+				return "zip"
+			}
+
+			content, err := io.ReadAll(io.LimitReader(rc, maxMimetypeSize))
+			_ = rc.Close()
+			if err != nil {
+				return "zip"
+			}
+
+			format, ok := odfFormats[strings.TrimSpace(string(content))]
+			if !ok {
+				return "zip"
+			}
+
+			if variant, ok := formatVariants[format]; ok {
+				return variant
+			}
+
+			return format
+		case "word/document.xml":
+			return "docx"
+		case "xl/workbook.xml":
+			return "xlsx"
+		case "ppt/presentation.xml":
+			return "pptx"
+		}
+	}
+
+	return "zip"
+}
+
+// cfbStreamNames returns the names of the entries of a Compound File Binary
+// document, i.e., a legacy binary document, by walking its directory. The
+// walk stops at the first inconsistency, e.g., a truncated file; the names
+// read so far are returned.
+func cfbStreamNames(inputPath string) ([]string, error) {
+	file, err := os.Open(inputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, 512)
+
+	_, err = file.ReadAt(header, 0)
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	// See https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-cfb.
+	sectorShift := binary.LittleEndian.Uint16(header[30:])
+	if sectorShift < 9 || sectorShift > 12 {
+		return nil, fmt.Errorf("sector shift %d", sectorShift)
+	}
+
+	sectorSize := 1 << sectorShift
+	fatSectorsCount := binary.LittleEndian.Uint32(header[44:])
+	dirSector := binary.LittleEndian.Uint32(header[48:])
+
+	// The first 109 FAT sectors are listed in the header, which is enough
+	// for the directory of most documents.
+	var fatSectors []uint32
+	for i := uint32(0); i < fatSectorsCount && i < 109; i++ {
+		fatSectors = append(fatSectors, binary.LittleEndian.Uint32(header[76+4*i:]))
+	}
+
+	readSector := func(sector uint32) ([]byte, error) {
+		buf := make([]byte, sectorSize)
+
+		_, err := file.ReadAt(buf, int64(sector+1)<<sectorShift)
+		if err != nil {
+			return nil, err
+		}
+
+		return buf, nil
+	}
+
+	const maxSector = 0xFFFFFFFA
+	entriesPerFatSector := uint32(sectorSize / 4)
+
+	var names []string
+	for walked := 0; dirSector < maxSector && walked < 1024; walked++ {
+		buf, err := readSector(dirSector)
+		if err != nil {
+			break
+		}
+
+		for offset := 0; offset+128 <= sectorSize; offset += 128 {
+			entry := buf[offset : offset+128]
+
+			nameLength := int(binary.LittleEndian.Uint16(entry[64:]))
+			if nameLength < 2 || nameLength > 64 {
+				continue
+			}
+
+			units := make([]uint16, nameLength/2-1)
+			for i := range units {
+				units[i] = binary.LittleEndian.Uint16(entry[2*i:])
+			}
+
+			names = append(names, string(utf16.Decode(units)))
+		}
+
+		fatIndex := dirSector / entriesPerFatSector
+		if int(fatIndex) >= len(fatSectors) {
+			break
+		}
+
+		fat, err := readSector(fatSectors[fatIndex])
+		if err != nil {
+			break
+		}
+
+		dirSector = binary.LittleEndian.Uint32(fat[4*(dirSector%entriesPerFatSector):])
+	}
+
+	return names, nil
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// writeCfb writes a Compound File Binary document, with 512 bytes sectors,
+// whose directory has the given entries.
+func writeCfb(t *testing.T, path string, names []string) {
+	content := make([]byte, 512*3)
+
+	// Header.
+	copy(content, "\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")
+	binary.LittleEndian.PutUint16(content[30:], 9)
+	binary.LittleEndian.PutUint32(content[44:], 1)
+	binary.LittleEndian.PutUint32(content[48:], 1)
+	for i := 0; i < 109; i++ {
+		binary.LittleEndian.PutUint32(content[76+4*i:], 0xFFFFFFFF)
+	}
+	binary.LittleEndian.PutUint32(content[76:], 0)
+
+	// Sector 0: the FAT, in which the directory is a single sector.
+	for i := 0; i < 128; i++ {
+		binary.LittleEndian.PutUint32(content[512+4*i:], 0xFFFFFFFF)
+	}
+	binary.LittleEndian.PutUint32(content[512:], 0xFFFFFFFD)
+	binary.LittleEndian.PutUint32(content[512+4:], 0xFFFFFFFE)
+
+	// Sector 1: the directory.
+	for i, name := range append([]string{"Root Entry"}, names...) {
+		entry := content[1024+128*i : 1024+128*(i+1)]
+
+		units := utf16.Encode([]rune(name))
+		for j, unit := range units {
+			binary.LittleEndian.PutUint16(entry[2*j:], unit)
+		}
+		binary.LittleEndian.PutUint16(entry[64:], uint16(2*len(units)+2))
+	}
+
+	err := os.WriteFile(path, content, 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func TestDetectImportFilter(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		filename     string
+		entries      [][2]string
+		cfb          []string
+		content      string
+		expectFormat string
+		expectFilter string
+		expectError  error
+	}{
+		{
+			scenario:     "DOCX document",
+			filename:     "document.docx",
+			entries:      [][2]string{{"[Content_Types].xml", `<Types/>`}, {"word/document.xml", `<w:document/>`}},
+			expectFormat: "docx",
+		},
+		{
+			scenario:     "DOTM document",
+			filename:     "template.dotm",
+			entries:      [][2]string{{"[Content_Types].xml", `<Types/>`}, {"word/document.xml", `<w:document/>`}},
+			expectFormat: "docx",
+		},
+		{
+			scenario:     "OTS document",
+			filename:     "template.ots",
+			entries:      [][2]string{{"mimetype", "application/vnd.oasis.opendocument.spreadsheet-template"}},
+			expectFormat: "ods",
+		},
+		{
+			scenario:     "DOC document",
+			filename:     "document.doc",
+			cfb:          []string{"\x01CompObj", "WordDocument", "1Table"},
+			expectFormat: "doc",
+		},
+		{
+			scenario:     "encrypted DOCX document",
+			filename:     "document.docx",
+			cfb:          []string{"EncryptionInfo", "EncryptedPackage"},
+			expectFormat: "",
+		},
+		{
+			scenario:     "RTF document named DOC",
+			filename:     "letter.doc",
+			content:      `{\rtf1\ansi Hello}`,
+			expectFormat: "rtf",
+			expectFilter: "Rich Text Format",
+		},
+		{
+			scenario:     "DOCX document named DOC",
+			filename:     "letter.DOC",
+			entries:      [][2]string{{"[Content_Types].xml", `<Types/>`}, {"word/document.xml", `<w:document/>`}},
+			expectFormat: "docx",
+			expectFilter: "MS Word 2007 XML",
+		},
+		{
+			scenario:     "XLS document named XLSX",
+			filename:     "sheet.xlsx",
+			cfb:          []string{"Workbook"},
+			expectFormat: "xls",
+			expectFilter: "MS Excel 97",
+		},
+		{
+			scenario:     "XLSX document named DOCX",
+			filename:     "sheet.docx",
+			entries:      [][2]string{{"[Content_Types].xml", `<Types/>`}, {"xl/workbook.xml", `<workbook/>`}},
+			expectFormat: "xlsx",
+			expectError:  ErrFormatMismatch,
+		},
+		{
+			scenario:     "ZIP archive named PPTX",
+			filename:     "slides.pptx",
+			entries:      [][2]string{{"foo.txt", "foo"}},
+			expectFormat: "zip",
+			expectError:  ErrFormatMismatch,
+		},
+		{
+			scenario:     "oversized mimetype named ODT",
+			filename:     "document.odt",
+			entries:      [][2]string{{"mimetype", "application/vnd.oasis.opendocument.text" + strings.Repeat(" ", 1<<20)}},
+			expectFormat: "zip",
+			expectError:  ErrFormatMismatch,
+		},
+		{
+			scenario:     "PDF named ODG",
+			filename:     "drawing.odg",
+			content:      "%PDF-1.7",
+			expectFormat: "pdf",
+			expectError:  ErrFormatMismatch,
+		},
+		{
+			scenario:     "text document",
+			filename:     "document.txt",
+			content:      "foo",
+			expectFormat: "",
+		},
+		{
+			scenario:     "unknown extension",
+			filename:     "document.foo",
+			content:      `{\rtf1\ansi Hello}`,
+			expectFormat: "rtf",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			inputPath := filepath.Join(t.TempDir(), tc.filename)
+
+			switch {
+			case tc.entries != nil:
+				writeZip(t, inputPath, tc.entries)
+			case tc.cfb != nil:
+				writeCfb(t, inputPath, tc.cfb)
+			default:
+				err := os.WriteFile(inputPath, []byte(tc.content), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			format, err := DetectFormat(inputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if format != tc.expectFormat {
+				t.Errorf("expected format '%s' but got '%s'", tc.expectFormat, format)
+			}
+
+			filter, err := DetectImportFilter(inputPath)
+
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected error %v but got: %v", tc.expectError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if filter != tc.expectFilter {
+				t.Errorf("expected filter '%s' but got '%s'", tc.expectFilter, filter)
+			}
+		})
+	}
+}
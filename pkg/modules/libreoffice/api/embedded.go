@@ -0,0 +1,310 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// EmbeddedObjectOle is an OLE object, e.g., a file attached to a
+	// document, which the conversion only renders as its preview image.
+	EmbeddedObjectOle string = "ole"
+
+	// EmbeddedObjectDocument is a document embedded in another one, e.g., a
+	// spreadsheet in a text document.
+	EmbeddedObjectDocument string = "document"
+
+	// EmbeddedObjectMedia is an audio or video file, which a PDF does not
+	// play.
+	EmbeddedObjectMedia string = "media"
+)
+
+// EmbeddedObject is an object embedded in a document.
+type EmbeddedObject struct {
+	// Name is the path of the object within the document.
+	Name string `json:"name"`
+
+	// Type is either [EmbeddedObjectOle], [EmbeddedObjectDocument] or
+	// [EmbeddedObjectMedia].
+	Type string `json:"type"`
+
+	// MediaType is the media type the document records for the object, if
+	// any.
+	MediaType string `json:"mediaType,omitempty"`
+
+	// Size is the uncompressed size of the object, in bytes.
+	Size int64 `json:"size"`
+}
+
+// Filename returns a filename for the object once extracted, see
+// [ExtractEmbeddedObject].
+func (o EmbeddedObject) Filename() string {
+	filename := path.Base(o.Name)
+
+	if o.Type == EmbeddedObjectDocument && path.Ext(filename) == "" {
+		if format, ok := odfFormats[o.MediaType]; ok {
+			filename += "." + format
+		}
+	}
+
+	return filename
+}
+
+// mediaExtensions are the extensions of the audio and video files.
+var mediaExtensions = map[string]bool{
+	".aac": true, ".avi": true, ".m4a": true, ".m4v": true, ".mkv": true,
+	".mov": true, ".mp3": true, ".mp4": true, ".mpeg": true, ".mpg": true,
+	".ogg": true, ".wav": true, ".webm": true, ".wma": true, ".wmv": true,
+}
+
+var (
+	ooxmlEmbeddingRegexp    = regexp.MustCompile(`^(?:word|xl|ppt)/embeddings/[^/]+$`)
+	ooxmlMediaRegexp        = regexp.MustCompile(`^(?:word|xl|ppt)/media/[^/]+$`)
+	odfObjectRegexp         = regexp.MustCompile(`^(Object [^/]+)(/.*)?$`)
+	contentTypeRegexp       = regexp.MustCompile(`<(?:Default|Override)\s[^>]*>`)
+	manifestFileEntryRegexp = regexp.MustCompile(`<manifest:file-entry\s[^>]*>`)
+)
+
+// odfChartMediaType is the media type of the charts, which the ODF documents
+// store as embedded objects, but the conversion renders.
+const odfChartMediaType = "application/vnd.oasis.opendocument.chart"
+
+// EmbeddedObjects returns the objects embedded in an OOXML or ODF document,
+// in the order of the document. The conversion does not carry them over,
+// e.g., an attached file becomes a preview image. Other documents, e.g., the
+// legacy binary formats, have no embedded objects as far as this function
+// is concerned.
+func EmbeddedObjects(inputPath string) ([]EmbeddedObject, error) {
+	header, err := readHeader(inputPath, 4)
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	if !bytes.Equal(header, []byte("PK\x03\x04")) {
+		return nil, nil
+	}
+
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("open '%s': %w", filepath.Base(inputPath), ErrCorruptDocument)
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	if _, ok := files["mimetype"]; ok {
+		return odfEmbeddedObjects(reader.File, files), nil
+	}
+
+	return ooxmlEmbeddedObjects(reader.File, files), nil
+}
+
+// ooxmlEmbeddedObjects returns the objects embedded in an OOXML document.
+// The OLE objects are the binary parts of the embeddings folder, while the
+// other parts are documents, e.g., the XLSX of an embedded spreadsheet.
+func ooxmlEmbeddedObjects(entries []*zip.File, files map[string]*zip.File) []EmbeddedObject {
+	mediaTypes := make(map[string]string)
+
+	if file, ok := files["[Content_Types].xml"]; ok {
+		content, err := readZipFile(file)
+		if err == nil {
+			for _, tag := range contentTypeRegexp.FindAllString(string(content), -1) {
+				contentType, _ := xmlAttr(tag, "ContentType")
+				if extension, ok := xmlAttr(tag, "Extension"); ok {
+					mediaTypes["."+strings.ToLower(extension)] = contentType
+				}
+				if partName, ok := xmlAttr(tag, "PartName"); ok {
+					mediaTypes[strings.TrimPrefix(partName, "/")] = contentType
+				}
+			}
+		}
+	}
+
+	mediaType := func(name string) string {
+		if mediaType, ok := mediaTypes[name]; ok {
+			return mediaType
+		}
+
+		return mediaTypes[strings.ToLower(path.Ext(name))]
+	}
+
+	var objects []EmbeddedObject
+	for _, entry := range entries {
+		ext := strings.ToLower(path.Ext(entry.Name))
+
+		switch {
+		case ooxmlEmbeddingRegexp.MatchString(entry.Name):
+			objectType := EmbeddedObjectDocument
+			if ext == ".bin" {
+				objectType = EmbeddedObjectOle
+			}
+
+			objects = append(objects, EmbeddedObject{Name: entry.Name, Type: objectType, MediaType: mediaType(entry.Name), Size: int64(entry.UncompressedSize64)})
+		case ooxmlMediaRegexp.MatchString(entry.Name) && mediaExtensions[ext]:
+			objects = append(objects, EmbeddedObject{Name: entry.Name, Type: EmbeddedObjectMedia, MediaType: mediaType(entry.Name), Size: int64(entry.UncompressedSize64)})
+		}
+	}
+
+	return objects
+}
+
+// odfEmbeddedObjects returns the objects embedded in an ODF document. The
+// OLE objects are the "Object N" files, while the embedded documents are
+// the "Object N" folders, but for the charts.
+func odfEmbeddedObjects(entries []*zip.File, files map[string]*zip.File) []EmbeddedObject {
+	mediaTypes := make(map[string]string)
+
+	if file, ok := files["META-INF/manifest.xml"]; ok {
+		content, err := readZipFile(file)
+		if err == nil {
+			for _, tag := range manifestFileEntryRegexp.FindAllString(string(content), -1) {
+				fullPath, _ := xmlAttr(tag, "manifest:full-path")
+				mediaType, _ := xmlAttr(tag, "manifest:media-type")
+				mediaTypes[strings.TrimSuffix(fullPath, "/")] = mediaType
+			}
+		}
+	}
+
+	var objects []EmbeddedObject
+	indexes := make(map[string]int)
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name, "Media/") && mediaExtensions[strings.ToLower(path.Ext(entry.Name))] {
+			objects = append(objects, EmbeddedObject{Name: entry.Name, Type: EmbeddedObjectMedia, MediaType: mediaTypes[entry.Name], Size: int64(entry.UncompressedSize64)})
+			continue
+		}
+
+		matches := odfObjectRegexp.FindStringSubmatch(entry.Name)
+		if matches == nil {
+			continue
+		}
+
+		name, mediaType := matches[1], mediaTypes[matches[1]]
+
+		if matches[2] == "" {
+			objects = append(objects, EmbeddedObject{Name: name, Type: EmbeddedObjectOle, MediaType: mediaType, Size: int64(entry.UncompressedSize64)})
+			continue
+		}
+
+		if mediaType == odfChartMediaType {
+			continue
+		}
+
+		i, ok := indexes[name]
+		if !ok {
+			i = len(objects)
+			indexes[name] = i
+			objects = append(objects, EmbeddedObject{Name: name, Type: EmbeddedObjectDocument, MediaType: mediaType})
+		}
+
+		objects[i].Size += int64(entry.UncompressedSize64)
+	}
+
+	return objects
+}
+
+// ExtractEmbeddedObject writes an object embedded in a document to the
+// output path. A document embedded in an ODF document is a folder, which is
+// repackaged as a standalone ODF document.
+func ExtractEmbeddedObject(inputPath string, object EmbeddedObject, outputPath string) error {
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return fmt.Errorf("open '%s': %w", filepath.Base(inputPath), ErrCorruptDocument)
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	for _, file := range reader.File {
+		if file.Name != object.Name {
+			continue
+		}
+
+		content, err := readZipFile(file)
+		if err != nil {
+			return fmt.Errorf("read '%s': %w", file.Name, err)
+		}
+
+		err = os.WriteFile(outputPath, content, 0o600)
+		if err != nil {
+			return fmt.Errorf("write '%s': %w", file.Name, err)
+		}
+
+		return nil
+	}
+
+	prefix := object.Name + "/"
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	writer := zip.NewWriter(out)
+
+	// Like any ODF document, the "mimetype" entry comes first and
+	// uncompressed.
+	w, err := writer.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("create 'mimetype': %w", err)
+	}
+
+	_, err = w.Write([]byte(object.MediaType))
+	if err != nil {
+		return fmt.Errorf("write 'mimetype': %w", err)
+	}
+
+	found := false
+	for _, file := range reader.File {
+		if !strings.HasPrefix(file.Name, prefix) || file.Name == prefix {
+			continue
+		}
+
+		found = true
+		header := file.FileHeader
+		header.Name = strings.TrimPrefix(file.Name, prefix)
+
+		content, err := readZipFile(file)
+		if err != nil {
+			return fmt.Errorf("read '%s': %w", file.Name, err)
+		}
+
+		w, err := writer.CreateHeader(&header)
+		if err != nil {
+			return fmt.Errorf("create '%s': %w", header.Name, err)
+		}
+
+		_, err = w.Write(content)
+		if err != nil {
+			return fmt.Errorf("write '%s': %w", header.Name, err)
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("embedded object '%s' not found", object.Name)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return nil
+}
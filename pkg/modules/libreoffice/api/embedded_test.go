@@ -0,0 +1,174 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEmbeddedObjects(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		filename string
+		entries  [][2]string
+		content  string
+		expect   []EmbeddedObject
+	}{
+		{
+			scenario: "DOCX document",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"[Content_Types].xml", `<Types><Default Extension="bin" ContentType="application/vnd.openxmlformats-officedocument.oleObject"/><Default Extension="mp4" ContentType="video/mp4"/><Override PartName="/word/embeddings/Microsoft_Excel_Worksheet.xlsx" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"/></Types>`},
+				{"word/document.xml", `<w:document/>`},
+				{"word/embeddings/oleObject1.bin", "ole"},
+				{"word/embeddings/Microsoft_Excel_Worksheet.xlsx", "xlsx"},
+				{"word/media/image1.png", "png"},
+				{"word/media/media1.mp4", "mp4"},
+			},
+			expect: []EmbeddedObject{
+				{Name: "word/embeddings/oleObject1.bin", Type: EmbeddedObjectOle, MediaType: "application/vnd.openxmlformats-officedocument.oleObject", Size: 3},
+				{Name: "word/embeddings/Microsoft_Excel_Worksheet.xlsx", Type: EmbeddedObjectDocument, MediaType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Size: 4},
+				{Name: "word/media/media1.mp4", Type: EmbeddedObjectMedia, MediaType: "video/mp4", Size: 3},
+			},
+		},
+		{
+			scenario: "ODT document",
+			filename: "document.odt",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.text"},
+				{"META-INF/manifest.xml", `<manifest:manifest><manifest:file-entry manifest:full-path="Object 1/" manifest:media-type="application/vnd.oasis.opendocument.spreadsheet"/><manifest:file-entry manifest:full-path="Object 2" manifest:media-type="application/vnd.sun.star.oleobject"/><manifest:file-entry manifest:full-path="Object 3/" manifest:media-type="application/vnd.oasis.opendocument.chart"/></manifest:manifest>`},
+				{"content.xml", `<office:document-content/>`},
+				{"Object 1/content.xml", "content"},
+				{"Object 1/styles.xml", "styles"},
+				{"Object 2", "ole"},
+				{"Object 3/content.xml", "chart"},
+				{"ObjectReplacements/Object 1", "preview"},
+				{"Media/video.webm", "webm"},
+			},
+			expect: []EmbeddedObject{
+				{Name: "Object 1", Type: EmbeddedObjectDocument, MediaType: "application/vnd.oasis.opendocument.spreadsheet", Size: 13},
+				{Name: "Object 2", Type: EmbeddedObjectOle, MediaType: "application/vnd.sun.star.oleobject", Size: 3},
+				{Name: "Media/video.webm", Type: EmbeddedObjectMedia, Size: 4},
+			},
+		},
+		{
+			scenario: "no embedded objects",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"[Content_Types].xml", `<Types/>`},
+				{"word/document.xml", `<w:document/>`},
+			},
+		},
+		{
+			scenario: "legacy document",
+			filename: "document.doc",
+			content:  "\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			inputPath := filepath.Join(t.TempDir(), tc.filename)
+
+			if tc.entries != nil {
+				writeZip(t, inputPath, tc.entries)
+			} else {
+				err := os.WriteFile(inputPath, []byte(tc.content), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			actual, err := EmbeddedObjects(inputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestExtractEmbeddedObject(t *testing.T) {
+	inputPath := filepath.Join(t.TempDir(), "document.odt")
+	writeZip(t, inputPath, [][2]string{
+		{"mimetype", "application/vnd.oasis.opendocument.text"},
+		{"content.xml", `<office:document-content/>`},
+		{"Object 1/content.xml", "content"},
+		{"Object 1/styles.xml", "styles"},
+		{"Object 2", "ole"},
+	})
+
+	for _, tc := range []struct {
+		scenario       string
+		object         EmbeddedObject
+		expectFilename string
+		expectError    bool
+		expect         string
+		expectEntries  map[string]string
+	}{
+		{
+			scenario:       "OLE object",
+			object:         EmbeddedObject{Name: "Object 2", Type: EmbeddedObjectOle},
+			expectFilename: "Object 2",
+			expect:         "ole",
+		},
+		{
+			scenario:       "embedded ODF document",
+			object:         EmbeddedObject{Name: "Object 1", Type: EmbeddedObjectDocument, MediaType: "application/vnd.oasis.opendocument.spreadsheet"},
+			expectFilename: "Object 1.ods",
+			expectEntries: map[string]string{
+				"mimetype":    "application/vnd.oasis.opendocument.spreadsheet",
+				"content.xml": "content",
+				"styles.xml":  "styles",
+			},
+		},
+		{
+			scenario:       "unknown object",
+			object:         EmbeddedObject{Name: "Object 3", Type: EmbeddedObjectDocument},
+			expectFilename: "Object 3",
+			expectError:    true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			if tc.object.Filename() != tc.expectFilename {
+				t.Errorf("expected filename '%s' but got '%s'", tc.expectFilename, tc.object.Filename())
+			}
+
+			outputPath := filepath.Join(t.TempDir(), tc.object.Filename())
+
+			err := ExtractEmbeddedObject(inputPath, tc.object, outputPath)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if tc.expectEntries != nil {
+				actual := readZip(t, outputPath)
+				if !reflect.DeepEqual(actual, tc.expectEntries) {
+					t.Errorf("expected %+v but got %+v", tc.expectEntries, actual)
+				}
+
+				return
+			}
+
+			actual, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if string(actual) != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+type filterDataKind int
+
+const (
+	filterDataBool filterDataKind = iota
+	filterDataInt
+	filterDataString
+)
+
+func (kind filterDataKind) String() string {
+	switch kind {
+	case filterDataBool:
+		return "boolean"
+	case filterDataInt:
+		return "integer"
+	default:
+		return "string"
+	}
+}
+
+// pdfExportFilterData gathers the known properties of the LibreOffice PDF
+// export filter, alongside their types.
+//
+// See https://help.libreoffice.org/latest/en-US/text/shared/guide/pdf_params.html.
+var pdfExportFilterData = map[string]filterDataKind{
+	"AllowDuplicateFieldNames":              filterDataBool,
+	"CenterWindow":                          filterDataBool,
+	"Changes":                               filterDataInt,
+	"ConvertOOoTargetToPDFTarget":           filterDataBool,
+	"DisplayPDFDocumentTitle":               filterDataBool,
+	"EmbedStandardFonts":                    filterDataBool,
+	"EnableCopyingOfContent":                filterDataBool,
+	"EnableTextAccessForAccessibilityTools": filterDataBool,
+	"ExportBookmarks":                       filterDataBool,
+	"ExportBookmarksToPDFDestination":       filterDataBool,
+	"ExportFormFields":                      filterDataBool,
+	"ExportHiddenSlides":                    filterDataBool,
+	"ExportLinksRelativeFsys":               filterDataBool,
+	"ExportNotes":                           filterDataBool,
+	"ExportNotesInMargin":                   filterDataBool,
+	"ExportNotesPages":                      filterDataBool,
+	"ExportOnlyNotesPages":                  filterDataBool,
+	"ExportPlaceholders":                    filterDataBool,
+	"FirstPageOnLeft":                       filterDataBool,
+	"FormsType":                             filterDataInt,
+	"HideViewerMenubar":                     filterDataBool,
+	"HideViewerToolbar":                     filterDataBool,
+	"HideViewerWindowControls":              filterDataBool,
+	"InitialPage":                           filterDataInt,
+	"InitialView":                           filterDataInt,
+	"IsAddStream":                           filterDataBool,
+	"IsSkipEmptyPages":                      filterDataBool,
+	"Magnification":                         filterDataInt,
+	"MaxImageResolution":                    filterDataInt,
+	"OpenBookmarkLevels":                    filterDataInt,
+	"OpenInFullScreenMode":                  filterDataBool,
+	"PageLayout":                            filterDataInt,
+	"PageRange":                             filterDataString,
+	"PDFViewSelection":                      filterDataInt,
+	"Printing":                              filterDataInt,
+	"Quality":                               filterDataInt,
+	"ReduceImageResolution":                 filterDataBool,
+	"ResizeWindowToInitialPage":             filterDataBool,
+	"SelectPdfVersion":                      filterDataInt,
+	"SinglePageSheets":                      filterDataBool,
+	"UseLosslessCompression":                filterDataBool,
+	"UseTaggedPDF":                          filterDataBool,
+	"UseTransitionEffects":                  filterDataBool,
+	"Watermark":                             filterDataString,
+	"Zoom":                                  filterDataInt,
+}
+
+// ParseFilterData parses a JSON object into LibreOffice PDF export filter
+// data. It returns an [ErrInvalidFilterData] error if a known property does
+// not have the expected type, or if a property is unknown and allowUnknown is
+// false. Values of unknown properties must be either booleans, integers or
+// strings.
+func ParseFilterData(value string, allowUnknown bool) (map[string]interface{}, error) {
+	var filterData map[string]interface{}
+
+	err := json.Unmarshal([]byte(value), &filterData)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal filter data: %w", err)
+	}
+
+	// Sort the keys so that errors are deterministic.
+	keys := make([]string, 0, len(filterData))
+	for key := range filterData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		kind, ok := pdfExportFilterData[key]
+		if !ok && !allowUnknown {
+			return nil, fmt.Errorf("unknown property '%s': %w", key, ErrInvalidFilterData)
+		}
+
+		value, ok := filterDataValue(filterData[key])
+		if !ok {
+			return nil, fmt.Errorf("property '%s' is neither a boolean, an integer nor a string: %w", key, ErrInvalidFilterData)
+		}
+
+		actualKind := filterDataString
+		switch value.(type) {
+		case bool:
+			actualKind = filterDataBool
+		case int:
+			actualKind = filterDataInt
+		}
+
+		_, known := pdfExportFilterData[key]
+		if known && actualKind != kind {
+			return nil, fmt.Errorf("property '%s' must be of type %s, got %s: %w", key, kind, actualKind, ErrInvalidFilterData)
+		}
+
+		filterData[key] = value
+	}
+
+	return filterData, nil
+}
+
+// filterDataValue converts a JSON value to either a bool, an int or a string.
+func filterDataValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case bool, string:
+		return v, true
+	case float64:
+		if v != math.Trunc(v) {
+			return nil, false
+		}
+
+		return int(v), true
+	default:
+		return nil, false
+	}
+}
+
+// filterDataArgs returns the "--export" arguments of the given filter data,
+// sorted by key.
+func filterDataArgs(filterData map[string]interface{}) []string {
+	keys := make([]string, 0, len(filterData))
+	for key := range filterData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var args []string
+	for _, key := range keys {
+		var value string
+
+		switch v := filterData[key].(type) {
+		case bool:
+			value = strconv.FormatBool(v)
+		case int:
+			value = strconv.Itoa(v)
+		default:
+			value = fmt.Sprintf("%v", v)
+		}
+
+		args = append(args, "--export", fmt.Sprintf("%s=%s", key, value))
+	}
+
+	return args
+}
+
+// exportFilterOptionsArgs returns the unoconverter arguments which set the
+// options string of the export filter, if any.
+func exportFilterOptionsArgs(options string) []string {
+	if options == "" {
+		return nil
+	}
+
+	return []string{"--export", fmt.Sprintf("FilterOptions=%s", options)}
+}
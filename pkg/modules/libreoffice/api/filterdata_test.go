@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseFilterData(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		value            string
+		allowUnknown     bool
+		expectFilterData map[string]interface{}
+		expectError      bool
+		expectInvalid    bool
+	}{
+		{
+			scenario:    "invalid JSON",
+			value:       "foo",
+			expectError: true,
+		},
+		{
+			scenario:      "unknown property",
+			value:         `{"Foo":true}`,
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario:      "wrong type for a known property",
+			value:         `{"ExportFormFields":"true"}`,
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario:      "float for an integer property",
+			value:         `{"Quality":90.5}`,
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario:      "unsupported type for an unknown property",
+			value:         `{"Foo":["bar"]}`,
+			allowUnknown:  true,
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario: "success",
+			value:    `{"ExportFormFields":false,"Quality":90,"Watermark":"Draft"}`,
+			expectFilterData: map[string]interface{}{
+				"ExportFormFields": false,
+				"Quality":          90,
+				"Watermark":        "Draft",
+			},
+		},
+		{
+			scenario:     "success with unknown properties",
+			value:        `{"Foo":"bar","Baz":1}`,
+			allowUnknown: true,
+			expectFilterData: map[string]interface{}{
+				"Foo": "bar",
+				"Baz": 1,
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			filterData, err := ParseFilterData(tc.value, tc.allowUnknown)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectInvalid && !errors.Is(err, ErrInvalidFilterData) {
+				t.Errorf("expected error %v but got: %v", ErrInvalidFilterData, err)
+			}
+
+			if !tc.expectError && !reflect.DeepEqual(filterData, tc.expectFilterData) {
+				t.Errorf("expected %+v but got: %+v", tc.expectFilterData, filterData)
+			}
+		})
+	}
+}
+
+func TestFilterDataArgs(t *testing.T) {
+	actual := filterDataArgs(map[string]interface{}{
+		"Quality":          90,
+		"ExportFormFields": false,
+		"Watermark":        "Draft",
+	})
+
+	expect := []string{
+		"--export", "ExportFormFields=false",
+		"--export", "Quality=90",
+		"--export", "Watermark=Draft",
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got: %+v", expect, actual)
+	}
+}
+
+func TestExportFilterOptionsArgs(t *testing.T) {
+	if args := exportFilterOptionsArgs(""); args != nil {
+		t.Errorf("expected no arguments but got: %+v", args)
+	}
+
+	actual := exportFilterOptionsArgs("44,34,UTF8")
+	expect := []string{"--export", "FilterOptions=44,34,UTF8"}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got: %+v", expect, actual)
+	}
+}
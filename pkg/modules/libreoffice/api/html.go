@@ -0,0 +1,209 @@
+package api
+
+import (
+	"archive/zip"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// HtmlBundleInline inlines the resources of an HTML export, e.g., its
+	// images, as data URLs, so that it is a single self-contained file.
+	HtmlBundleInline string = "inline"
+
+	// HtmlBundleZip returns a ZIP archive with an HTML export, named
+	// "index.html", alongside its resources.
+	HtmlBundleZip string = "zip"
+)
+
+// HtmlBundles are the ways to package an HTML export with its resources.
+var HtmlBundles = []string{HtmlBundleInline, HtmlBundleZip}
+
+// htmlResourceRegexp matches the references of an HTML file to other files,
+// i.e., its src and href attributes, and the url() of its styles.
+var htmlResourceRegexp = regexp.MustCompile(`(?i)(\b(?:src|href)\s*=\s*)(?:"([^"]*)"|'([^']*)')|(\burl\(\s*)(?:"([^"]*)"|'([^']*)'|([^'")\s]*))(\s*\))`)
+
+// htmlResources returns the files an HTML file references, keyed by their
+// reference, among the files of its directory. Other references, e.g., to
+// URLs or to files outside its directory, are left aside.
+func htmlResources(htmlPath, html string) map[string]string {
+	dirPath := filepath.Dir(htmlPath)
+	resources := make(map[string]string)
+
+	for _, match := range htmlResourceRegexp.FindAllStringSubmatch(html, -1) {
+		var reference string
+		for _, group := range []int{2, 3, 5, 6, 7} {
+			if match[group] != "" {
+				reference = match[group]
+				break
+			}
+		}
+
+		if _, ok := resources[reference]; ok {
+			continue
+		}
+
+		path, ok := htmlResourcePath(dirPath, reference)
+		if !ok || path == htmlPath {
+			continue
+		}
+
+		resources[reference] = path
+	}
+
+	return resources
+}
+
+// htmlResourcePath returns the path of the file a relative reference of an
+// HTML file targets, if it exists within the given directory.
+func htmlResourcePath(dirPath, reference string) (string, bool) {
+	if reference == "" || strings.HasPrefix(reference, "#") || strings.HasPrefix(reference, "/") {
+		return "", false
+	}
+
+	u, err := url.Parse(reference)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return "", false
+	}
+
+	path := filepath.Join(dirPath, filepath.FromSlash(u.Path))
+	if !strings.HasPrefix(path, dirPath+string(filepath.Separator)) {
+		return "", false
+	}
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+
+	return path, true
+}
+
+// inlineHtmlResources writes a copy of an HTML file with the files it
+// references inlined as data URLs.
+func inlineHtmlResources(htmlPath, outputPath string) error {
+	content, err := os.ReadFile(htmlPath)
+	if err != nil {
+		return fmt.Errorf("read HTML: %w", err)
+	}
+
+	html := string(content)
+
+	dataUrls := make(map[string]string)
+	for reference, path := range htmlResources(htmlPath, html) {
+		resource, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read resource '%s': %w", reference, err)
+		}
+
+		mediaType := mime.TypeByExtension(filepath.Ext(path))
+		if mediaType == "" {
+			mediaType = http.DetectContentType(resource)
+		}
+
+		dataUrls[reference] = fmt.Sprintf("data:%s;base64,%s", mediaType, base64.StdEncoding.EncodeToString(resource))
+	}
+
+	html = htmlResourceRegexp.ReplaceAllStringFunc(html, func(reference string) string {
+		match := htmlResourceRegexp.FindStringSubmatch(reference)
+
+		if match[1] != "" {
+			value := match[2] + match[3]
+			if dataUrl, ok := dataUrls[value]; ok {
+				return match[1] + `"` + dataUrl + `"`
+			}
+
+			return reference
+		}
+
+		value := match[5] + match[6] + match[7]
+		if dataUrl, ok := dataUrls[value]; ok {
+			return match[4] + `"` + dataUrl + `"` + match[8]
+		}
+
+		return reference
+	})
+
+	err = os.WriteFile(outputPath, []byte(html), 0o600)
+	if err != nil {
+		return fmt.Errorf("write HTML: %w", err)
+	}
+
+	return nil
+}
+
+// zipHtmlResources writes a ZIP archive with an HTML file, named
+// "index.html", and the files it references, under their reference.
+func zipHtmlResources(htmlPath, outputPath string) error {
+	content, err := os.ReadFile(htmlPath)
+	if err != nil {
+		return fmt.Errorf("read HTML: %w", err)
+	}
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	writer := zip.NewWriter(out)
+
+	w, err := writer.Create("index.html")
+	if err != nil {
+		return fmt.Errorf("create 'index.html': %w", err)
+	}
+
+	_, err = w.Write(content)
+	if err != nil {
+		return fmt.Errorf("write 'index.html': %w", err)
+	}
+
+	dirPath := filepath.Dir(htmlPath)
+	written := map[string]bool{"index.html": true}
+
+	for _, path := range htmlResources(htmlPath, string(content)) {
+		name, err := filepath.Rel(dirPath, path)
+		if err != nil {
+			return fmt.Errorf("get resource name: %w", err)
+		}
+
+		name = filepath.ToSlash(name)
+		if written[name] {
+			continue
+		}
+
+		written[name] = true
+
+		resource, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read resource '%s': %w", name, err)
+		}
+
+		w, err := writer.Create(name)
+		if err != nil {
+			return fmt.Errorf("create '%s': %w", name, err)
+		}
+
+		_, err = w.Write(resource)
+		if err != nil {
+			return fmt.Errorf("write '%s': %w", name, err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeHtmlExport writes an HTML export and its resources, as LibreOffice
+// would, and returns the path of the HTML file.
+func writeHtmlExport(t *testing.T) string {
+	dirPath := filepath.Join(t.TempDir(), "export")
+
+	for name, content := range map[string]string{
+		"index.html": `<html><head><style>body { background: url(index_html_bg.gif); }</style></head><body>` +
+			`<img src="index_html_1.png"/><img alt="" src='img%20two.jpg'/><img src="index_html_1.png"/>` +
+			`<a href="#top">Top</a><a href="https://example.com/">Link</a><img src="../secret.png"/><img src="missing.png"/>` +
+			`</body></html>`,
+		"index_html_1.png":  "png",
+		"img two.jpg":       "jpg",
+		"index_html_bg.gif": "gif",
+		"unreferenced.odt":  "odt",
+		"../secret.png":     "secret",
+	} {
+		path := filepath.Join(dirPath, name)
+
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		err = os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	return filepath.Join(dirPath, "index.html")
+}
+
+func TestInlineHtmlResources(t *testing.T) {
+	htmlPath := writeHtmlExport(t)
+	outputPath := filepath.Join(t.TempDir(), "output.html")
+
+	err := inlineHtmlResources(htmlPath, outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	actual, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := `<html><head><style>body { background: url("data:image/gif;base64,Z2lm"); }</style></head><body>` +
+		`<img src="data:image/png;base64,cG5n"/><img alt="" src="data:image/jpeg;base64,anBn"/><img src="data:image/png;base64,cG5n"/>` +
+		`<a href="#top">Top</a><a href="https://example.com/">Link</a><img src="../secret.png"/><img src="missing.png"/>` +
+		`</body></html>`
+
+	if string(actual) != expect {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expect, actual)
+	}
+}
+
+func TestZipHtmlResources(t *testing.T) {
+	htmlPath := writeHtmlExport(t)
+	outputPath := filepath.Join(t.TempDir(), "output.zip")
+
+	err := zipHtmlResources(htmlPath, outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	html, err := os.ReadFile(htmlPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	actual := readZip(t, outputPath)
+	expect := map[string]string{
+		"index.html":        string(html),
+		"index_html_1.png":  "png",
+		"img two.jpg":       "jpg",
+		"index_html_bg.gif": "gif",
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+}
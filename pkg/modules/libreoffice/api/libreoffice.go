@@ -2,15 +2,14 @@ package api
 
 import (
 	"context"
-	b64 "encoding/base64"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"net"
 	"os"
 	"path/filepath"
-	"regexp"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -24,19 +23,37 @@ import (
 type libreOffice interface {
 	gotenberg.Process
 	html(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	export(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
 	pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	statistics(ctx context.Context, logger *zap.Logger, inputPath string, options Options) (documentStatistics, error)
 }
 
 type libreOfficeArguments struct {
-	binPath      string
-	unoBinPath   string
-	startTimeout time.Duration
+	binPath              string
+	unoBinPath           string
+	unoDocumentBinPath   string
+	startTimeout         time.Duration
+	restartAfterDuration time.Duration
+	maxMemory            int64
+
+	// userProfileTemplateDirPath is the directory with the files to copy into
+	// the user profile of each LibreOffice instance, if any.
+	userProfileTemplateDirPath string
+
+	// fonts, locale and timezone are the settings of a dedicated LibreOffice
+	// instance, i.e., one which starts for a single conversion: the fonts to
+	// install in its user profile, its locale setting and the timezone of
+	// its process.
+	fonts    []gotenberg.Font
+	locale   string
+	timezone string
 }
 
 type libreOfficeProcess struct {
 	socketPort         int
 	userProfileDirPath string
 	cmd                *gotenberg.Cmd
+	startedAt          time.Time
 	cfgMu              sync.RWMutex
 	isStarted          atomic.Bool
 
@@ -65,6 +82,17 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 	}
 
 	userProfileDirPath := p.fs.NewDirPath()
+
+	err = p.setUpUserProfile(filepath.Join(userProfileDirPath, "user"))
+	if err != nil {
+		removeErr := os.RemoveAll(userProfileDirPath)
+		if removeErr != nil {
+			logger.Error(fmt.Sprintf("remove LibreOffice's user profile directory: %v", removeErr))
+		}
+
+		return fmt.Errorf("set up user profile: %w", err)
+	}
+
 	args := []string{
 		"--headless",
 		"--invisible",
@@ -80,11 +108,20 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 	ctx, cancel := context.WithTimeout(context.Background(), p.arguments.startTimeout)
 	defer cancel()
 
+	// LibreOffice evaluates the current date and time, e.g., the fields and
+	// the NOW function, in the timezone of its process.
+	var env []string
+	if p.arguments.timezone != "" {
+		env = append(env, fmt.Sprintf("TZ=%s", p.arguments.timezone))
+	}
+
 	cmd, err := gotenberg.CommandContext(ctx, logger, p.arguments.binPath, args...)
 	if err != nil {
 		return fmt.Errorf("create LibreOffice command: %w", err)
 	}
 
+	cmd.SetEnv(env...)
+
 	// For whatever reason, LibreOffice requires a first start before being
 	// able to run as a daemon.
 	exitCode, err := cmd.Exec()
@@ -96,6 +133,7 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 
 	// Second start (daemon).
 	cmd = gotenberg.Command(logger, p.arguments.binPath, args...)
+	cmd.SetEnv(env...)
 
 	err = cmd.Start()
 	if err != nil {
@@ -146,6 +184,7 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 			p.socketPort = port
 			p.userProfileDirPath = userProfileDirPath
 			p.cmd = cmd
+			p.startedAt = time.Now()
 			p.isStarted.Store(true)
 
 			return
@@ -185,6 +224,34 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 	}
 }
 
+// setUpUserProfile writes the files of the "user" directory of the user
+// installation of LibreOffice, which keeps its settings there and merges them
+// with the defaults upon first start.
+func (p *libreOfficeProcess) setUpUserProfile(userDirPath string) error {
+	if p.arguments.userProfileTemplateDirPath != "" {
+		err := copyUserProfileTemplate(p.arguments.userProfileTemplateDirPath, userDirPath)
+		if err != nil {
+			return fmt.Errorf("copy user profile template: %w", err)
+		}
+	}
+
+	if len(p.arguments.fonts) > 0 {
+		err := copyUserProfileFonts(userDirPath, p.arguments.fonts)
+		if err != nil {
+			return fmt.Errorf("copy fonts: %w", err)
+		}
+	}
+
+	if p.arguments.locale != "" {
+		err := setUserProfileLocale(userDirPath, p.arguments.locale)
+		if err != nil {
+			return fmt.Errorf("set locale: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (p *libreOfficeProcess) Stop(logger *zap.Logger) error {
 	if !p.isStarted.Load() {
 		// No big deal? Like calling cancel twice.
@@ -221,11 +288,24 @@ func (p *libreOfficeProcess) Stop(logger *zap.Logger) error {
 	p.socketPort = 0
 	p.userProfileDirPath = ""
 	p.cmd = nil
+	p.startedAt = time.Time{}
 	p.isStarted.Store(false)
 
 	return nil
 }
 
+// Pid returns the PID of the LibreOffice process, or 0 if not started.
+func (p *libreOfficeProcess) Pid() int {
+	p.cfgMu.RLock()
+	defer p.cfgMu.RUnlock()
+
+	if p.cmd == nil {
+		return 0
+	}
+
+	return p.cmd.Pid()
+}
+
 func (p *libreOfficeProcess) Healthy(logger *zap.Logger) bool {
 	// Good to know: the supervisor does not call this method if no first start
 	// or if the process is restarting.
@@ -251,11 +331,47 @@ func (p *libreOfficeProcess) Healthy(logger *zap.Logger) bool {
 	return false
 }
 
+func (p *libreOfficeProcess) RestartReason(logger *zap.Logger) string {
+	if !p.isStarted.Load() {
+		return ""
+	}
+
+	p.cfgMu.RLock()
+	defer p.cfgMu.RUnlock()
+
+	if p.arguments.restartAfterDuration > 0 {
+		uptime := time.Since(p.startedAt)
+		if uptime >= p.arguments.restartAfterDuration {
+			return fmt.Sprintf("LibreOffice has been running for %s, more than %s", uptime.Round(time.Second), p.arguments.restartAfterDuration)
+		}
+	}
+
+	if p.arguments.maxMemory > 0 && p.cmd != nil {
+		// The process group ID is the PID of the command, see gotenberg.Command.
+		memory, err := processGroupMemory(p.cmd.Pid())
+		if err != nil {
+			logger.Error(fmt.Sprintf("get LibreOffice memory usage: %v", err))
+			return ""
+		}
+
+		if memory > p.arguments.maxMemory {
+			return fmt.Sprintf("LibreOffice uses %d bytes of memory, more than %d bytes", memory, p.arguments.maxMemory)
+		}
+	}
+
+	return ""
+}
+
 func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
 	if !p.isStarted.Load() {
 		return errors.New("LibreOffice not started, cannot handle PDF conversion")
 	}
 
+	inputPath, options, err := p.prepareDocument(ctx, logger, inputPath, outputPath, options)
+	if err != nil {
+		return err
+	}
+
 	args := []string{
 		"--no-launch",
 		"--format",
@@ -264,12 +380,18 @@ func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputP
 
 	args = append(args, "--port", fmt.Sprintf("%d", p.socketPort))
 
-	if options.ImportFilter != "" {
-		args = append(args, "--import-filter-name", options.ImportFilter)
+	filter := importFilter(inputPath, options)
+	if filter != "" {
+		args = append(args, "--import-filter-name", filter)
 	}
 	if options.ImportOptions != "" {
 		args = append(args, "--import", options.ImportOptions)
 	}
+	if options.Password != "" {
+		args = append(args, "--password", options.Password)
+	}
+
+	args = append(args, loadPropertiesArgs(options)...)
 
 	checkedEntry := logger.Check(zap.DebugLevel, "check for debug level before setting high verbosity")
 	if checkedEntry != nil {
@@ -280,31 +402,177 @@ func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputP
 		args = append(args, "--printer", "PaperOrientation=landscape")
 	}
 
+	// unoconverter refreshes the indexes and fields of the documents which
+	// have some, unless told otherwise. As it may be time-consuming, it is
+	// opt-in.
+	if !options.UpdateIndexes {
+		args = append(args, "--disable-update-indexes")
+	}
+
+	// The dedicated options take precedence over the filter data.
+	filterData := make(map[string]interface{}, len(options.FilterData))
+	for key, value := range options.FilterData {
+		filterData[key] = value
+	}
+
 	if options.PageRanges != "" {
-		args = append(args, "--export", fmt.Sprintf("PageRange=%s", options.PageRanges))
+		filterData["PageRange"] = options.PageRanges
+	}
+
+	err = ValidatePdfVersion(options.PdfVersion, options.PdfFormats)
+	if err != nil {
+		return err
 	}
 
 	switch options.PdfFormats.PdfA {
 	case "":
+		if options.PdfVersion != "" {
+			filterData["SelectPdfVersion"] = pdfVersions[options.PdfVersion]
+		}
 	case gotenberg.PdfA1b:
-		args = append(args, "--export", "SelectPdfVersion=1")
+		filterData["SelectPdfVersion"] = 1
 	case gotenberg.PdfA2b:
-		args = append(args, "--export", "SelectPdfVersion=2")
+		filterData["SelectPdfVersion"] = 2
 	case gotenberg.PdfA3b:
-		args = append(args, "--export", "SelectPdfVersion=3")
+		filterData["SelectPdfVersion"] = 3
 	default:
 		return ErrInvalidPdfFormats
 	}
 
+	if options.ExportComments {
+		if options.ExportCommentsInMargin {
+			filterData["ExportNotesInMargin"] = true
+		} else {
+			filterData["ExportNotes"] = true
+		}
+	}
+
+	if options.ExportNotesPages {
+		filterData["ExportNotesPages"] = true
+	}
+
+	if options.ExportHiddenSlides {
+		filterData["ExportHiddenSlides"] = true
+	}
+
+	switch options.ExportFormFields {
+	case "":
+		// The submit format only makes sense for interactive fields.
+		if options.FormsType != "" {
+			filterData["ExportFormFields"] = true
+		}
+	case ExportFormFieldsInteractive:
+		filterData["ExportFormFields"] = true
+	case ExportFormFieldsFlattened:
+		if options.FormsType != "" {
+			return fmt.Errorf("forms type '%s' with flattened form fields", options.FormsType)
+		}
+
+		filterData["ExportFormFields"] = false
+	default:
+		return fmt.Errorf("export form fields '%s' is not one of '%s' or '%s'", options.ExportFormFields, ExportFormFieldsInteractive, ExportFormFieldsFlattened)
+	}
+
+	if options.SkipBookmarks {
+		filterData["ExportBookmarks"] = false
+	}
+
+	// The named values map to their indexes.
+	for _, named := range []struct {
+		property string
+		value    string
+		values   []string
+	}{
+		{property: "InitialView", value: options.InitialView, values: PdfInitialViews},
+		{property: "Magnification", value: options.Magnification, values: PdfMagnifications},
+		{property: "PageLayout", value: options.PageLayout, values: PdfPageLayouts},
+		{property: "FormsType", value: options.FormsType, values: PdfFormsTypes},
+	} {
+		if named.value == "" {
+			continue
+		}
+
+		index := slices.Index(named.values, named.value)
+		if index < 0 {
+			return fmt.Errorf("%s '%s' is not one of '%s'", named.property, named.value, strings.Join(named.values, "', '"))
+		}
+
+		filterData[named.property] = index
+	}
+
+	if options.ExportBookmarksToPdfDestination {
+		filterData["ExportBookmarksToPDFDestination"] = true
+	}
+
+	if options.ExportLinksRelativeFsys {
+		filterData["ExportLinksRelativeFsys"] = true
+	}
+
+	if options.ReduceImageResolution || options.MaxImageResolution != 0 {
+		switch options.MaxImageResolution {
+		case 75, 150, 300, 600, 1200:
+		default:
+			return fmt.Errorf("max image resolution %d: %w", options.MaxImageResolution, ErrInvalidMaxImageResolution)
+		}
+
+		if options.ReduceImageResolution {
+			filterData["ReduceImageResolution"] = true
+			filterData["MaxImageResolution"] = options.MaxImageResolution
+		}
+	}
+
+	if options.JpegQuality != 0 {
+		if options.JpegQuality < 1 || options.JpegQuality > 100 {
+			return fmt.Errorf("JPEG quality %d: %w", options.JpegQuality, ErrInvalidJpegQuality)
+		}
+
+		filterData["Quality"] = options.JpegQuality
+	}
+
+	if options.LosslessImageCompression {
+		filterData["UseLosslessCompression"] = true
+	}
+
+	if options.UserPassword != "" {
+		filterData["EncryptFile"] = true
+		filterData["DocumentOpenPassword"] = options.UserPassword
+	}
+
+	if options.OwnerPassword != "" {
+		filterData["RestrictPermissions"] = true
+		filterData["PermissionPassword"] = options.OwnerPassword
+		filterData["EnableCopyingOfContent"] = options.AllowCopying
+
+		// Either not permitted or high resolution.
+		filterData["Printing"] = 0
+		if options.AllowPrinting {
+			filterData["Printing"] = 2
+		}
+
+		// Either not permitted or any except extracting pages.
+		filterData["Changes"] = 0
+		if options.AllowModifying {
+			filterData["Changes"] = 4
+		}
+	}
+
 	if options.PdfFormats.PdfUa {
-		args = append(
-			args,
-			"--export", "EnableTextAccessForAccessibilityTools=true",
-			"--export", "UseTaggedPDF=true",
-		)
+		filterData["EnableTextAccessForAccessibilityTools"] = true
+		filterData["UseTaggedPDF"] = true
+	}
+
+	if options.SinglePage {
+		if !SupportsSinglePage(inputPath) {
+			return fmt.Errorf("single page for '%s': %w", filepath.Base(inputPath), ErrSinglePageNotSupported)
+		}
+
+		filterData["SinglePageSheets"] = true
 	}
 
-	inputPath, err := nonBasicLatinCharactersGuard(logger, inputPath)
+	args = append(args, filterDataArgs(filterData)...)
+	args = append(args, exportFilterOptionsArgs(options.ExportFilterOptions)...)
+
+	inputPath, err = nonBasicLatinCharactersGuard(logger, inputPath)
 	if err != nil {
 		return fmt.Errorf("non-basic latin characters guard: %w", err)
 	}
@@ -316,7 +584,7 @@ func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputP
 		return fmt.Errorf("create uno command: %w", err)
 	}
 
-	logger.Debug(fmt.Sprintf("print to PDF with: %+v", options))
+	logger.Debug(fmt.Sprintf("print to PDF with: %+v", options.redacted()))
 
 	exitCode, err := cmd.Exec()
 	if err == nil {
@@ -330,6 +598,14 @@ func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputP
 		return ErrMalformedPageRanges
 	}
 
+	if exitCode == 6 && options.Password != "" {
+		return ErrInvalidPassword
+	}
+
+	if exitCode == 6 {
+		return ErrCorruptDocument
+	}
+
 	// Possible errors:
 	// 1. LibreOffice failed for some reason.
 	// 2. Context done.
@@ -341,31 +617,98 @@ func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputP
 }
 
 func (p *libreOfficeProcess) html(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
+	options.OutputFormat = "html"
+
+	// LibreOffice writes the resources of the HTML file, e.g., its images,
+	// next to it; a dedicated directory tells them apart from the other
+	// files of the request.
+	dirPath := filepath.Join(filepath.Dir(outputPath), uuid.NewString())
+
+	err := os.Mkdir(dirPath, 0o755)
+	if err != nil {
+		return fmt.Errorf("create HTML directory: %w", err)
+	}
+
+	htmlPath := filepath.Join(dirPath, "index.html")
+
+	err = p.export(ctx, logger, inputPath, htmlPath, options)
+	if err != nil {
+		return err
+	}
+
+	switch options.HtmlBundle {
+	case "", HtmlBundleInline:
+		err = inlineHtmlResources(htmlPath, outputPath)
+	case HtmlBundleZip:
+		err = zipHtmlResources(htmlPath, outputPath)
+	default:
+		err = fmt.Errorf("HTML bundle '%s' is not one of '%s'", options.HtmlBundle, strings.Join(HtmlBundles, "', '"))
+	}
+
+	if err != nil {
+		return fmt.Errorf("bundle HTML: %w", err)
+	}
+
+	return nil
+}
+
+func (p *libreOfficeProcess) export(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
 	if !p.isStarted.Load() {
-		return errors.New("LibreOffice not started, cannot handle HTML conversion")
+		return fmt.Errorf("LibreOffice not started, cannot handle %s conversion", strings.ToUpper(options.OutputFormat))
+	}
+
+	if !slices.Contains(OutputFormats, options.OutputFormat) {
+		return fmt.Errorf("output format '%s': %w", options.OutputFormat, ErrInvalidOutputFormat)
+	}
+
+	inputPath, options, err := p.prepareDocument(ctx, logger, inputPath, outputPath, options)
+	if err != nil {
+		return err
 	}
 
 	args := []string{
 		"--no-launch",
 		"--format",
-		"html",
+		options.OutputFormat,
 	}
 
 	args = append(args, "--port", fmt.Sprintf("%d", p.socketPort))
 
-	if options.ImportFilter != "" {
-		args = append(args, "--import-filter-name", options.ImportFilter)
+	filter := importFilter(inputPath, options)
+	if filter != "" {
+		args = append(args, "--import-filter-name", filter)
 	}
 	if options.ImportOptions != "" {
 		args = append(args, "--import", options.ImportOptions)
 	}
+	if options.Password != "" {
+		args = append(args, "--password", options.Password)
+	}
+
+	args = append(args, loadPropertiesArgs(options)...)
 
 	checkedEntry := logger.Check(zap.DebugLevel, "check for debug level before setting high verbosity")
 	if checkedEntry != nil {
 		args = append(args, "-vvv")
 	}
 
-	inputPath, err := nonBasicLatinCharactersGuard(logger, inputPath)
+	// See the PDF conversion.
+	if !options.UpdateIndexes {
+		args = append(args, "--disable-update-indexes")
+	}
+
+	exportFilterOptions := options.ExportFilterOptions
+	if options.OutputFormat == "csv" && options.Csv.IsSet() {
+		exportFilterOptions, err = options.Csv.filterOptions()
+		if err != nil {
+			return err
+		}
+	}
+
+	args = append(args, filterDataArgs(options.FilterData)...)
+	args = append(args, exportFilterOptionsArgs(exportFilterOptions)...)
+
+	inputPath, err = nonBasicLatinCharactersGuard(logger, inputPath)
 	if err != nil {
 		return fmt.Errorf("non-basic latin characters guard: %w", err)
 	}
@@ -377,61 +720,70 @@ func (p *libreOfficeProcess) html(ctx context.Context, logger *zap.Logger, input
 		return fmt.Errorf("create uno command: %w", err)
 	}
 
-	logger.Debug(fmt.Sprintf("print to PDF with: %+v", options))
+	logger.Debug(fmt.Sprintf("export to %s with: %+v", options.OutputFormat, options.redacted()))
 
 	exitCode, err := cmd.Exec()
 	if err == nil {
-		replaceHtmlImageWithEmbeddedBase64(outputPath, logger)
 		return nil
 	}
 
-	// LibreOffice's errors are not explicit.
-	// That's why we have to make an educated guess according to the exit code
-	// and given inputs.
-	if exitCode == 5 && options.PageRanges != "" {
-		return ErrMalformedPageRanges
+	// See the PDF conversion.
+	if exitCode == 6 && options.Password != "" {
+		return ErrInvalidPassword
+	}
+
+	if exitCode == 6 {
+		return ErrCorruptDocument
 	}
 
 	// Possible errors:
-	// 1. LibreOffice failed for some reason.
+	// 1. LibreOffice failed for some reason, e.g., the export filter does
+	// not apply to this kind of document.
 	// 2. Context done.
 	//
 	// On the second scenario, LibreOffice might not have time to remove some
 	// of its temporary files, as it has been killed without warning. The
 	// garbage collector will delete them for us (if the module is loaded).
-	return fmt.Errorf("convert to PDF: %w", err)
+	return fmt.Errorf("convert to %s: %w", options.OutputFormat, err)
 }
 
-// ** Onna Custom base64 image inlining for HTML conversions **
-func replaceHtmlImageWithEmbeddedBase64(outputPath string, logger *zap.Logger) error {
-	raw, err := os.ReadFile(outputPath)
-	html := string(raw)
-	if err != nil {
-		logger.Error("Unable to read output html file at " + outputPath)
-		return nil
+// prepareDocument writes, next to the output path, a copy of the document
+// with the options which belong to the document rather than to the export
+// applied, e.g., the print options of the spreadsheets. It returns the path
+// of the copy, or the input path if there is no such option, alongside the
+// options to convert it with.
+func (p *libreOfficeProcess) prepareDocument(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) (string, Options, error) {
+	preparedPath := func(ext string) string {
+		return filepath.Join(filepath.Dir(outputPath), fmt.Sprintf("%s%s", uuid.NewString(), ext))
 	}
-	imgRe := regexp.MustCompile(`img\s+src="([^/"]+)"`)
-	matches := imgRe.FindAllStringSubmatch(html, -1)
-	for _, value := range matches {
-		fullPath := filepath.Join(filepath.Dir(outputPath), value[1])
-		if _, err := os.Stat(fullPath); err == nil {
-			logger.Info("Found an image to embed: " + fullPath)
-			imageData, err := os.ReadFile(fullPath)
-			if err == nil {
-				encoded := b64.StdEncoding.EncodeToString(imageData)
-				html = imgRe.ReplaceAllString(html, "img src=\"data:image/"+ filepath.Ext(value[1])+";base64,"+encoded + "\"")
-				logger.Info("Embedded the image that was in: " + value[1])
-			}
+
+	if options.Spreadsheet.IsSet() {
+		path := preparedPath(filepath.Ext(inputPath))
+
+		err := prepareSpreadsheet(inputPath, path, options.Spreadsheet)
+		if err != nil {
+			return "", options, fmt.Errorf("prepare spreadsheet: %w", err)
 		}
+
+		inputPath = path
 	}
-	// Rewrite output file with the modified document.
-	err = ioutil.WriteFile(outputPath+".embedded", []byte(html), 0644)
-	if err == nil {
-		logger.Info("Wrote the embedded version of the html file")
-		_ = os.Rename(outputPath+".embedded", outputPath)
-		return nil
+
+	if options.TrackedChanges != "" {
+		path := preparedPath(".odt")
+
+		err := p.prepareTrackedChanges(ctx, logger, inputPath, path, options)
+		if err != nil {
+			return "", options, fmt.Errorf("prepare tracked changes: %w", err)
+		}
+
+		// The copy is an ODT document LibreOffice wrote, without password.
+		inputPath = path
+		options.ImportFilter = ""
+		options.ImportOptions = ""
+		options.Password = ""
 	}
-	return err
+
+	return inputPath, options, nil
 }
 
 // LibreOffice cannot convert a file with a name containing non-basic Latin
@@ -495,6 +847,8 @@ func nonBasicLatinCharactersGuard(logger *zap.Logger, inputPath string) (string,
 
 // Interface guards.
 var (
-	_ gotenberg.Process = (*libreOfficeProcess)(nil)
-	_ libreOffice       = (*libreOfficeProcess)(nil)
+	_ gotenberg.Process           = (*libreOfficeProcess)(nil)
+	_ gotenberg.RecyclableProcess = (*libreOfficeProcess)(nil)
+	_ gotenberg.ProcessIdentifier = (*libreOfficeProcess)(nil)
+	_ libreOffice                 = (*libreOfficeProcess)(nil)
 )
@@ -1,9 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"image"
+	"image/png"
+	"math/rand"
 	"os"
 	"testing"
 	"time"
@@ -211,6 +216,90 @@ func TestLibreOfficeProcess_Healthy(t *testing.T) {
 	}
 }
 
+func TestLibreOfficeProcess_RestartReason(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		libreOffice   *libreOfficeProcess
+		expectRestart bool
+	}{
+		{
+			scenario: "LibreOffice not started",
+			libreOffice: func() *libreOfficeProcess {
+				p := new(libreOfficeProcess)
+				p.arguments.restartAfterDuration = time.Nanosecond
+				p.isStarted.Store(false)
+				return p
+			}(),
+			expectRestart: false,
+		},
+		{
+			scenario: "no restart policy",
+			libreOffice: func() *libreOfficeProcess {
+				p := new(libreOfficeProcess)
+				p.startedAt = time.Now().Add(-time.Hour)
+				p.isStarted.Store(true)
+				return p
+			}(),
+			expectRestart: false,
+		},
+		{
+			scenario: "restart after duration not reached",
+			libreOffice: func() *libreOfficeProcess {
+				p := new(libreOfficeProcess)
+				p.arguments.restartAfterDuration = time.Hour
+				p.startedAt = time.Now()
+				p.isStarted.Store(true)
+				return p
+			}(),
+			expectRestart: false,
+		},
+		{
+			scenario: "restart after duration reached",
+			libreOffice: func() *libreOfficeProcess {
+				p := new(libreOfficeProcess)
+				p.arguments.restartAfterDuration = time.Minute
+				p.startedAt = time.Now().Add(-time.Hour)
+				p.isStarted.Store(true)
+				return p
+			}(),
+			expectRestart: true,
+		},
+		{
+			scenario: "max memory exceeded",
+			libreOffice: func() *libreOfficeProcess {
+				cmd := gotenberg.Command(zap.NewNop(), "sleep", "60")
+				err := cmd.Start()
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				t.Cleanup(func() {
+					_ = cmd.Kill()
+				})
+
+				p := new(libreOfficeProcess)
+				p.arguments.maxMemory = 1
+				p.cmd = cmd
+				p.startedAt = time.Now()
+				p.isStarted.Store(true)
+				return p
+			}(),
+			expectRestart: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			reason := tc.libreOffice.RestartReason(zap.NewNop())
+
+			if tc.expectRestart && reason == "" {
+				t.Fatal("expected a restart reason but got none")
+			}
+
+			if !tc.expectRestart && reason != "" {
+				t.Fatalf("expected no restart reason but got: %s", reason)
+			}
+		})
+	}
+}
+
 func TestLibreOfficeProcess_pdf(t *testing.T) {
 	for _, tc := range []struct {
 		scenario      string
@@ -249,6 +338,65 @@ func TestLibreOfficeProcess_pdf(t *testing.T) {
 			expectError:   true,
 			expectedError: ErrInvalidPdfFormats,
 		},
+		{
+			scenario: "ErrInvalidMaxImageResolution",
+			libreOffice: func() libreOffice {
+				p := new(libreOfficeProcess)
+				p.socketPort = 12345
+				p.isStarted.Store(true)
+				return p
+			}(),
+			fs:            gotenberg.NewFileSystem(),
+			options:       Options{ReduceImageResolution: true, MaxImageResolution: 42},
+			cancelledCtx:  false,
+			start:         false,
+			expectError:   true,
+			expectedError: ErrInvalidMaxImageResolution,
+		},
+		{
+			scenario: "invalid export form fields",
+			libreOffice: func() libreOffice {
+				p := new(libreOfficeProcess)
+				p.socketPort = 12345
+				p.isStarted.Store(true)
+				return p
+			}(),
+			fs:           gotenberg.NewFileSystem(),
+			options:      Options{ExportFormFields: "foo"},
+			cancelledCtx: false,
+			start:        false,
+			expectError:  true,
+		},
+		{
+			scenario: "ErrSinglePageNotSupported",
+			libreOffice: func() libreOffice {
+				p := new(libreOfficeProcess)
+				p.socketPort = 12345
+				p.isStarted.Store(true)
+				return p
+			}(),
+			fs:            gotenberg.NewFileSystem(),
+			options:       Options{SinglePage: true},
+			cancelledCtx:  false,
+			start:         false,
+			expectError:   true,
+			expectedError: ErrSinglePageNotSupported,
+		},
+		{
+			scenario: "ErrInvalidPdfVersion",
+			libreOffice: func() libreOffice {
+				p := new(libreOfficeProcess)
+				p.socketPort = 12345
+				p.isStarted.Store(true)
+				return p
+			}(),
+			fs:            gotenberg.NewFileSystem(),
+			options:       Options{PdfVersion: "2.0", PdfFormats: gotenberg.PdfFormats{PdfA: gotenberg.PdfA1b}},
+			cancelledCtx:  false,
+			start:         false,
+			expectError:   true,
+			expectedError: ErrInvalidPdfVersion,
+		},
 		{
 			scenario: "ErrMalformedPageRanges",
 			libreOffice: newLibreOfficeProcess(
@@ -565,6 +713,111 @@ func TestLibreOfficeProcess_pdf(t *testing.T) {
 	}
 }
 
+// imageHeavyDocument returns a flat OpenDocument text with a 1200x1200
+// pixels image of noise, i.e., about 200 DPI once printed on 6 inches. The
+// noise makes sure the image does not compress well.
+func imageHeavyDocument(t *testing.T) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 1200, 1200))
+
+	r := rand.New(rand.NewSource(1))
+	_, err := r.Read(img.Pix)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 0xff
+	}
+
+	var b bytes.Buffer
+	err = png.Encode(&b, img)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<office:document xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:draw="urn:oasis:names:tc:opendocument:xmlns:drawing:1.0" xmlns:svg="urn:oasis:names:tc:opendocument:xmlns:svg-compatible:1.0" office:version="1.2" office:mimetype="application/vnd.oasis.opendocument.text">
+  <office:body>
+    <office:text>
+      <text:p><draw:frame draw:name="image" text:anchor-type="as-char" svg:width="6in" svg:height="6in"><draw:image><office:binary-data>%s</office:binary-data></draw:image></draw:frame></text:p>
+    </office:text>
+  </office:body>
+</office:document>
+`, base64.StdEncoding.EncodeToString(b.Bytes())))
+}
+
+func TestLibreOfficeProcess_pdfReducedImageResolution(t *testing.T) {
+	// Force the debug level.
+	logger := zap.NewExample()
+
+	fs := gotenberg.NewFileSystem()
+
+	err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		err := os.RemoveAll(fs.WorkingDirPath())
+		if err != nil {
+			t.Fatalf("expected no error while cleaning up, but got: %v", err)
+		}
+	}()
+
+	inputPath := fmt.Sprintf("%s/document.fodt", fs.WorkingDirPath())
+
+	err = os.WriteFile(inputPath, imageHeavyDocument(t), 0o755)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	p := newLibreOfficeProcess(
+		libreOfficeArguments{
+			binPath:      os.Getenv("LIBREOFFICE_BIN_PATH"),
+			unoBinPath:   os.Getenv("UNOCONVERTER_BIN_PATH"),
+			startTimeout: 5 * time.Second,
+		},
+	)
+
+	err = p.Start(logger)
+	if err != nil {
+		t.Fatalf("setup error: %v", err)
+	}
+
+	defer func() {
+		err := p.Stop(logger)
+		if err != nil {
+			t.Fatalf("expected no error while cleaning up, but got: %v", err)
+		}
+	}()
+
+	size := func(options Options) int64 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(30)*time.Second)
+		defer cancel()
+
+		outputPath := fmt.Sprintf("%s/%s.pdf", fs.WorkingDirPath(), uuid.NewString())
+
+		err := p.pdf(ctx, logger, inputPath, outputPath, options)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		info, err := os.Stat(outputPath)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return info.Size()
+	}
+
+	defaultSize := size(Options{})
+	reducedSize := size(Options{ReduceImageResolution: true, MaxImageResolution: 75})
+
+	if reducedSize >= defaultSize {
+		t.Errorf("expected the PDF with reduced image resolution (%d bytes) to be smaller than the default one (%d bytes)", reducedSize, defaultSize)
+	}
+}
+
 func TestNonBasicLatinCharactersGuard(t *testing.T) {
 	for _, tc := range []struct {
 		scenario            string
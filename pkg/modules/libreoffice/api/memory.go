@@ -0,0 +1,50 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// processGroupMemory returns the resident set size, in bytes, of all the unix
+// processes of the given process group. It relies on the /proc filesystem.
+func processGroupMemory(pgid int) (int64, error) {
+	statPaths, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil {
+		return 0, fmt.Errorf("list processes: %w", err)
+	}
+
+	var total int64
+	for _, statPath := range statPaths {
+		b, err := os.ReadFile(statPath)
+		if err != nil {
+			// The process has likely exited in the meantime.
+			continue
+		}
+
+		// The second field, i.e., the executable name, may contain spaces;
+		// the fields we are interested in come after it.
+		stat := string(b)
+		fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+		if len(fields) < 22 {
+			continue
+		}
+
+		// See https://man7.org/linux/man-pages/man5/proc_pid_stat.5.html.
+		group, err := strconv.Atoi(fields[2])
+		if err != nil || group != pgid {
+			continue
+		}
+
+		pages, err := strconv.ParseInt(fields[21], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		total += pages * int64(os.Getpagesize())
+	}
+
+	return total, nil
+}
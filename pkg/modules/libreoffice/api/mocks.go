@@ -11,7 +11,9 @@ import (
 // ApiMock is a mock for the [Uno] interface.
 type ApiMock struct {
 	PdfMock        func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
-	HtmlMock	   func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	HtmlMock       func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	ExportMock     func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	ProbeMock      func(ctx context.Context, logger *zap.Logger, inputPath string) (DocumentProperties, error)
 	ExtensionsMock func() []string
 }
 
@@ -23,10 +25,28 @@ func (api *ApiMock) Html(ctx context.Context, logger *zap.Logger, inputPath, out
 	return api.HtmlMock(ctx, logger, inputPath, outputPath, options)
 }
 
+func (api *ApiMock) Export(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
+	return api.ExportMock(ctx, logger, inputPath, outputPath, options)
+}
+
+func (api *ApiMock) Probe(ctx context.Context, logger *zap.Logger, inputPath string) (DocumentProperties, error) {
+	return api.ProbeMock(ctx, logger, inputPath)
+}
+
 func (api *ApiMock) Extensions() []string {
 	return api.ExtensionsMock()
 }
 
+// ConcurrentApiMock is a mock for the [ConcurrentUno] interface.
+type ConcurrentApiMock struct {
+	ApiMock
+	ConcurrencyMock func() int
+}
+
+func (api *ConcurrentApiMock) Concurrency() int {
+	return api.ConcurrencyMock()
+}
+
 // ProviderMock is a mock for the [Provider] interface.
 type ProviderMock struct {
 	LibreOfficeMock func() (Uno, error)
@@ -39,8 +59,10 @@ func (provider *ProviderMock) LibreOffice() (Uno, error) {
 // libreOfficeMock is a mock for the [libreOffice] interface.
 type libreOfficeMock struct {
 	gotenberg.ProcessMock
-	pdfMock func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
-	htmlMock func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	pdfMock        func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	htmlMock       func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	exportMock     func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	statisticsMock func(ctx context.Context, logger *zap.Logger, inputPath string, options Options) (documentStatistics, error)
 }
 
 func (b *libreOfficeMock) pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
@@ -51,9 +73,32 @@ func (b *libreOfficeMock) html(ctx context.Context, logger *zap.Logger, inputPat
 	return b.htmlMock(ctx, logger, inputPath, outputPath, options)
 }
 
+func (b *libreOfficeMock) export(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
+	return b.exportMock(ctx, logger, inputPath, outputPath, options)
+}
+
+func (b *libreOfficeMock) statistics(ctx context.Context, logger *zap.Logger, inputPath string, options Options) (documentStatistics, error) {
+	return b.statisticsMock(ctx, logger, inputPath, options)
+}
+
+// recyclableLibreOfficeMock is a mock for the [libreOffice] interface which
+// also implements the [gotenberg.RecyclableProcess] interface.
+type recyclableLibreOfficeMock struct {
+	libreOfficeMock
+	restartReasonMock func(logger *zap.Logger) string
+}
+
+func (b *recyclableLibreOfficeMock) RestartReason(logger *zap.Logger) string {
+	return b.restartReasonMock(logger)
+}
+
 // Interface guards.
 var (
-	_ Uno         = (*ApiMock)(nil)
-	_ Provider    = (*ProviderMock)(nil)
-	_ libreOffice = (*libreOfficeMock)(nil)
+	_ Uno           = (*ApiMock)(nil)
+	_ ConcurrentUno = (*ConcurrentApiMock)(nil)
+	_ Provider      = (*ProviderMock)(nil)
+	_ libreOffice   = (*libreOfficeMock)(nil)
+
+	_ libreOffice                 = (*recyclableLibreOfficeMock)(nil)
+	_ gotenberg.RecyclableProcess = (*recyclableLibreOfficeMock)(nil)
 )
@@ -28,6 +28,19 @@ func TestApiMock(t *testing.T) {
 	}
 }
 
+func TestConcurrentApiMock(t *testing.T) {
+	mock := &ConcurrentApiMock{
+		ConcurrencyMock: func() int {
+			return 2
+		},
+	}
+
+	concurrency := mock.Concurrency()
+	if concurrency != 2 {
+		t.Errorf("expected 2 from ConcurrentApiMock.Concurrency, but got: %d", concurrency)
+	}
+}
+
 func TestProviderMock(t *testing.T) {
 	mock := &ProviderMock{
 		LibreOfficeMock: func() (Uno, error) {
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DocumentProperties are the properties of a document, as recorded by the
+// application which saved it. The counts are zero if unknown, e.g., for
+// the legacy binary formats; see [Uno.Probe] for LibreOffice to count them.
+type DocumentProperties struct {
+	// Format is the format of the document, detected from its content, e.g.,
+	// "docx" or "ods". It falls back to the extension of the document if
+	// its content does not tell, e.g., for the legacy binary formats.
+	Format string `json:"format"`
+
+	// Pages is the number of pages of a text document or of a drawing.
+	Pages int `json:"pages,omitempty"`
+
+	// Slides is the number of slides of a presentation.
+	Slides int `json:"slides,omitempty"`
+
+	// Sheets is the number of sheets of a spreadsheet.
+	Sheets int `json:"sheets,omitempty"`
+
+	// Author is the creator of the document.
+	Author string `json:"author,omitempty"`
+
+	// Created is the creation date of the document.
+	Created *time.Time `json:"created,omitempty"`
+
+	// Modified is the last modification date of the document.
+	Modified *time.Time `json:"modified,omitempty"`
+}
+
+// odfFormats are the formats of the ODF documents, by media type.
+var odfFormats = map[string]string{
+	"application/vnd.oasis.opendocument.text":                  "odt",
+	"application/vnd.oasis.opendocument.text-template":         "ott",
+	"application/vnd.oasis.opendocument.text-master":           "odm",
+	"application/vnd.oasis.opendocument.spreadsheet":           "ods",
+	"application/vnd.oasis.opendocument.spreadsheet-template":  "ots",
+	"application/vnd.oasis.opendocument.presentation":          "odp",
+	"application/vnd.oasis.opendocument.presentation-template": "otp",
+	"application/vnd.oasis.opendocument.graphics":              "odg",
+	"application/vnd.oasis.opendocument.graphics-template":     "otg",
+}
+
+var (
+	odfTableRegexp     = regexp.MustCompile(`<table:table[\s>]`)
+	odfDrawPageRegexp  = regexp.MustCompile(`<draw:page[\s>]`)
+	odfStatisticRegexp = regexp.MustCompile(`<meta:document-statistic\s[^>]*>`)
+	pptxSlideRegexp    = regexp.MustCompile(`<p:sldId\s`)
+)
+
+// ProbeDocument returns the properties of a document without converting it,
+// thanks to the metadata of the OOXML and ODF documents. A document which is
+// not what its extension tells, e.g., a truncated DOCX document, returns
+// [ErrCorruptDocument].
+func ProbeDocument(inputPath string) (DocumentProperties, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(inputPath)), ".")
+
+	header, err := readHeader(inputPath, 8)
+	if err != nil {
+		return DocumentProperties{}, fmt.Errorf("read header: %w", err)
+	}
+
+	if !bytes.HasPrefix(header, []byte("PK\x03\x04")) {
+		if isZipFormat(ext) {
+			return DocumentProperties{}, fmt.Errorf("'%s' is not a ZIP archive: %w", filepath.Base(inputPath), ErrCorruptDocument)
+		}
+
+		if bytes.HasPrefix(header, []byte(`{\rtf`)) {
+			return DocumentProperties{Format: "rtf"}, nil
+		}
+
+		return DocumentProperties{Format: ext}, nil
+	}
+
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return DocumentProperties{}, fmt.Errorf("open '%s': %w", filepath.Base(inputPath), ErrCorruptDocument)
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	// An entry which is too large fails the probe, while an unreadable one
+	// only lacks its properties.
+	var readErr error
+	read := func(name string) string {
+		file, ok := files[name]
+		if !ok {
+			return ""
+		}
+
+		content, err := readZipFile(file)
+		if errors.Is(err, ErrZipEntryTooLarge) && readErr == nil {
+			readErr = err
+		}
+		if err != nil {
+			return ""
+		}
+
+		return string(content)
+	}
+
+	var properties DocumentProperties
+	switch {
+	case files["mimetype"] != nil:
+		properties, err = probeOdf(read)
+	case files["[Content_Types].xml"] != nil:
+		properties, err = probeOoxml(files, read)
+	case isZipFormat(ext):
+		return DocumentProperties{}, fmt.Errorf("'%s' is neither an OOXML nor an ODF document: %w", filepath.Base(inputPath), ErrCorruptDocument)
+	default:
+		return DocumentProperties{Format: ext}, nil
+	}
+
+	if readErr != nil {
+		return DocumentProperties{}, readErr
+	}
+
+	return properties, err
+}
+
+// probeOdf returns the properties of an ODF document.
+func probeOdf(read func(name string) string) (DocumentProperties, error) {
+	mediaType := strings.TrimSpace(read("mimetype"))
+
+	format, ok := odfFormats[mediaType]
+	if !ok {
+		return DocumentProperties{}, fmt.Errorf("media type '%s': %w", mediaType, ErrCorruptDocument)
+	}
+
+	meta := read("meta.xml")
+	properties := DocumentProperties{
+		Format:   format,
+		Author:   xmlText(meta, "meta:initial-creator"),
+		Created:  xmlTime(xmlText(meta, "meta:creation-date")),
+		Modified: xmlTime(xmlText(meta, "dc:date")),
+	}
+
+	if properties.Author == "" {
+		properties.Author = xmlText(meta, "dc:creator")
+	}
+
+	switch format {
+	case "ods", "ots":
+		properties.Sheets = len(odfTableRegexp.FindAllStringIndex(read("content.xml"), -1))
+	case "odp", "otp":
+		properties.Slides = len(odfDrawPageRegexp.FindAllStringIndex(read("content.xml"), -1))
+	case "odg", "otg":
+		properties.Pages = len(odfDrawPageRegexp.FindAllStringIndex(read("content.xml"), -1))
+	default:
+		// The text documents do not have pages until they are laid out;
+		// the application which saved them records how many there were.
+		statistic := odfStatisticRegexp.FindString(meta)
+		pageCount, _ := xmlAttr(statistic, "meta:page-count")
+		properties.Pages, _ = strconv.Atoi(pageCount)
+	}
+
+	return properties, nil
+}
+
+// probeOoxml returns the properties of an OOXML document.
+func probeOoxml(files map[string]*zip.File, read func(name string) string) (DocumentProperties, error) {
+	core := read("docProps/core.xml")
+	properties := DocumentProperties{
+		Author:   xmlText(core, "dc:creator"),
+		Created:  xmlTime(xmlText(core, "dcterms:created")),
+		Modified: xmlTime(xmlText(core, "dcterms:modified")),
+	}
+
+	switch {
+	case files["word/document.xml"] != nil:
+		// Like the ODF text documents, see probeOdf.
+		properties.Format = "docx"
+		properties.Pages, _ = strconv.Atoi(xmlText(read("docProps/app.xml"), "Pages"))
+	case files["xl/workbook.xml"] != nil:
+		properties.Format = "xlsx"
+		properties.Sheets = len(xlsxSheetRegexp.FindAllStringIndex(read("xl/workbook.xml"), -1))
+	case files["ppt/presentation.xml"] != nil:
+		properties.Format = "pptx"
+		properties.Slides = len(pptxSlideRegexp.FindAllStringIndex(read("ppt/presentation.xml"), -1))
+	default:
+		return DocumentProperties{}, fmt.Errorf("no main part: %w", ErrCorruptDocument)
+	}
+
+	return properties, nil
+}
+
+// isZipFormat tells if the documents with the given extension are ZIP
+// archives, i.e., OOXML and ODF documents.
+func isZipFormat(ext string) bool {
+	switch ext {
+	case "docx", "docm", "dotx", "dotm", "xlsx", "xlsm", "xltx", "xltm", "pptx", "pptm", "potx", "potm", "ppsx", "ppsm":
+		return true
+	default:
+		for _, format := range odfFormats {
+			if ext == format {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// readHeader returns the first bytes of a file, fewer if the file is
+// smaller.
+func readHeader(path string, size int) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, size)
+
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+
+	return header[:n], nil
+}
+
+// xmlText returns the unescaped text of the first XML element with the
+// given name, without its tags.
+func xmlText(content, name string) string {
+	spans := xmlElementSpans(content, name)
+	if len(spans) == 0 {
+		return ""
+	}
+
+	element := content[spans[0][0]:spans[0][1]]
+	if strings.HasSuffix(element, "/>") {
+		return ""
+	}
+
+	text := element[strings.Index(element, ">")+1 : strings.LastIndex(element, "<")]
+
+	return strings.TrimSpace(xmlUnescaper.Replace(text))
+}
+
+// xmlTime returns the date of an XML value, either in the W3C format of the
+// OOXML documents or in the ISO 8601 format, without timezone, of the ODF
+// documents. It returns nil if the value is not a date.
+func xmlTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return &t
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProbeDocument(t *testing.T) {
+	created := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	modified := time.Date(2024, time.March, 2, 12, 30, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		scenario    string
+		filename    string
+		entries     [][2]string
+		content     string
+		expectError error
+		expect      DocumentProperties
+	}{
+		{
+			scenario: "DOCX document",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"[Content_Types].xml", `<Types/>`},
+				{"word/document.xml", `<w:document/>`},
+				{"docProps/core.xml", `<cp:coreProperties><dc:creator>Jane &amp; John</dc:creator><dcterms:created xsi:type="dcterms:W3CDTF">2024-03-01T10:00:00Z</dcterms:created><dcterms:modified xsi:type="dcterms:W3CDTF">2024-03-02T12:30:00Z</dcterms:modified></cp:coreProperties>`},
+				{"docProps/app.xml", `<Properties><Pages>12</Pages></Properties>`},
+			},
+			expect: DocumentProperties{Format: "docx", Pages: 12, Author: "Jane & John", Created: &created, Modified: &modified},
+		},
+		{
+			scenario: "XLSX document",
+			filename: "sheet.xlsx",
+			entries: [][2]string{
+				{"[Content_Types].xml", `<Types/>`},
+				{"xl/workbook.xml", `<workbook><sheets><sheet name="A" sheetId="1" r:id="rId1"/><sheet name="B" sheetId="2" r:id="rId2"/></sheets></workbook>`},
+			},
+			expect: DocumentProperties{Format: "xlsx", Sheets: 2},
+		},
+		{
+			scenario: "PPTX document",
+			filename: "slides.pptx",
+			entries: [][2]string{
+				{"[Content_Types].xml", `<Types/>`},
+				{"ppt/presentation.xml", `<p:presentation><p:sldIdLst><p:sldId id="256" r:id="rId2"/><p:sldId id="257" r:id="rId3"/><p:sldId id="258" r:id="rId4"/></p:sldIdLst></p:presentation>`},
+			},
+			expect: DocumentProperties{Format: "pptx", Slides: 3},
+		},
+		{
+			scenario: "ODT document",
+			filename: "document.odt",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.text"},
+				{"meta.xml", `<office:document-meta><office:meta><meta:initial-creator>Jane</meta:initial-creator><dc:creator>John</dc:creator><meta:creation-date>2024-03-01T10:00:00.123</meta:creation-date><dc:date>2024-03-02T12:30:00</dc:date><meta:document-statistic meta:table-count="0" meta:page-count="4"/></office:meta></office:document-meta>`},
+			},
+			expect: DocumentProperties{Format: "odt", Pages: 4, Author: "Jane", Created: func() *time.Time {
+				t := created.Add(123 * time.Millisecond)
+				return &t
+			}(), Modified: &modified},
+		},
+		{
+			scenario: "ODS document",
+			filename: "sheet.ods",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.spreadsheet"},
+				{"meta.xml", `<office:document-meta><office:meta><dc:creator>John</dc:creator></office:meta></office:document-meta>`},
+				{"content.xml", `<office:spreadsheet><table:table table:name="A"><table:table-row/></table:table><table:table table:name="B"/></office:spreadsheet>`},
+			},
+			expect: DocumentProperties{Format: "ods", Sheets: 2, Author: "John"},
+		},
+		{
+			scenario: "ODP document",
+			filename: "slides.odp",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.presentation"},
+				{"content.xml", `<office:presentation><draw:page draw:name="1"/><draw:page draw:name="2"></draw:page></office:presentation>`},
+			},
+			expect: DocumentProperties{Format: "odp", Slides: 2},
+		},
+		{
+			scenario: "detected format",
+			filename: "document.doc",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.text"},
+			},
+			expect: DocumentProperties{Format: "odt"},
+		},
+		{
+			scenario: "RTF document",
+			filename: "document.doc",
+			content:  `{\rtf1\ansi Hello}`,
+			expect:   DocumentProperties{Format: "rtf"},
+		},
+		{
+			scenario: "legacy document",
+			filename: "document.DOC",
+			content:  "\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1",
+			expect:   DocumentProperties{Format: "doc"},
+		},
+		{
+			scenario:    "truncated DOCX document",
+			filename:    "document.docx",
+			content:     "PK\x03\x04foo",
+			expectError: ErrCorruptDocument,
+		},
+		{
+			scenario:    "not a DOCX document",
+			filename:    "document.docx",
+			content:     "foo",
+			expectError: ErrCorruptDocument,
+		},
+		{
+			scenario: "ZIP archive without main part",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"foo.txt", "foo"},
+			},
+			expectError: ErrCorruptDocument,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			inputPath := filepath.Join(t.TempDir(), tc.filename)
+
+			if tc.entries != nil {
+				writeZip(t, inputPath, tc.entries)
+			} else {
+				err := os.WriteFile(inputPath, []byte(tc.content), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			actual, err := ProbeDocument(inputPath)
+
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected error %v but got: %v", tc.expectError, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
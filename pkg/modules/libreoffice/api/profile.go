@@ -0,0 +1,170 @@
+package api
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"golang.org/x/text/language"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// registryModificationsXcu is the file of a user profile directory in which
+// LibreOffice keeps the settings which differ from the defaults.
+const registryModificationsXcu = "registrymodifications.xcu"
+
+// emptyRegistryModifications is a registrymodifications.xcu file without
+// settings.
+const emptyRegistryModifications = `<?xml version="1.0" encoding="UTF-8"?>
+<oor:items xmlns:oor="http://openoffice.org/2001/registry" xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+</oor:items>
+`
+
+// emptyOorItemsRegexp matches the root element of a registrymodifications.xcu
+// file without settings, e.g., "<oor:items/>".
+var emptyOorItemsRegexp = regexp.MustCompile(`<oor:items(\s[^>]*)?/>`)
+
+// copyUserProfileTemplate copies the files of a user profile template, e.g.,
+// a registrymodifications.xcu file, into the user profile directory of a
+// LibreOffice instance. Other entries than directories and regular files,
+// like symbolic links, are left aside.
+func copyUserProfileTemplate(templateDirPath, userDirPath string) error {
+	return filepath.WalkDir(templateDirPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("walk '%s': %w", path, err)
+		}
+
+		relPath, err := filepath.Rel(templateDirPath, path)
+		if err != nil {
+			return fmt.Errorf("get relative path of '%s': %w", path, err)
+		}
+
+		dstPath := filepath.Join(userDirPath, relPath)
+
+		if d.IsDir() {
+			err = os.MkdirAll(dstPath, 0o755)
+			if err != nil {
+				return fmt.Errorf("create directory '%s': %w", dstPath, err)
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		return copyUserProfileFile(path, dstPath)
+	})
+}
+
+// copyUserProfileFonts copies font files into the "fonts" directory of the
+// user profile directory of a LibreOffice instance. Alongside the system font
+// directories, LibreOffice reads this one when it starts.
+func copyUserProfileFonts(userDirPath string, fonts []gotenberg.Font) error {
+	dirPath := filepath.Join(userDirPath, "fonts")
+
+	err := os.MkdirAll(dirPath, 0o755)
+	if err != nil {
+		return fmt.Errorf("create directory '%s': %w", dirPath, err)
+	}
+
+	for i, font := range fonts {
+		// The font files may share a name, e.g., if they come from different
+		// directories.
+		err = copyUserProfileFile(font.Path, filepath.Join(dirPath, fmt.Sprintf("%d-%s", i, filepath.Base(font.Path))))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setUserProfileLocale sets the locale setting of LibreOffice, i.e., the
+// locale of the number and date formats without an explicit one, and the
+// default language of the documents, in a user profile directory. The other
+// settings, e.g., those of a user profile template, stay as is; the later
+// items of a registrymodifications.xcu file take precedence.
+func setUserProfileLocale(userDirPath, locale string) error {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return fmt.Errorf("parse locale '%s': %w", locale, err)
+	}
+
+	var value bytes.Buffer
+	err = xml.EscapeText(&value, []byte(tag.String()))
+	if err != nil {
+		return fmt.Errorf("escape locale '%s': %w", locale, err)
+	}
+
+	items := fmt.Sprintf(
+		`<item oor:path="/org.openoffice.Setup/L10N"><prop oor:name="ooSetupSystemLocale" oor:op="fuse"><value>%[1]s</value></prop></item>`+"\n"+
+			`<item oor:path="/org.openoffice.Office.Linguistic/General"><prop oor:name="DefaultLocale" oor:op="fuse"><value>%[1]s</value></prop></item>`+"\n",
+		value.String(),
+	)
+
+	path := filepath.Join(userDirPath, registryModificationsXcu)
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		content = []byte(emptyRegistryModifications)
+	} else if err != nil {
+		return fmt.Errorf("read '%s': %w", path, err)
+	}
+
+	content = emptyOorItemsRegexp.ReplaceAll(content, []byte("<oor:items$1></oor:items>"))
+
+	i := bytes.LastIndex(content, []byte("</oor:items>"))
+	if i < 0 {
+		return fmt.Errorf("no 'oor:items' element in '%s'", path)
+	}
+
+	content = append(content[:i:i], append([]byte(items), content[i:]...)...)
+
+	err = os.MkdirAll(userDirPath, 0o755)
+	if err != nil {
+		return fmt.Errorf("create directory '%s': %w", userDirPath, err)
+	}
+
+	err = os.WriteFile(path, content, 0o600)
+	if err != nil {
+		return fmt.Errorf("write '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// copyUserProfileFile copies a file of a user profile template.
+func copyUserProfileFile(srcPath, dstPath string) error {
+	in, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("open '%s': %w", srcPath, err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(dstPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create '%s': %w", dstPath, err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return fmt.Errorf("copy '%s': %w", srcPath, err)
+	}
+
+	return nil
+}
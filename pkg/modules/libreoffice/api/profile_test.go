@@ -0,0 +1,205 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestCopyUserProfileTemplate(t *testing.T) {
+	templateDirPath := t.TempDir()
+
+	for name, content := range map[string]string{
+		"registrymodifications.xcu": "<oor:items/>",
+		"autocorr/acor_en-US.dat":   "autocorrect",
+	} {
+		path := filepath.Join(templateDirPath, name)
+
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		err = os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	err := os.Symlink("/etc/passwd", filepath.Join(templateDirPath, "passwd"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	userDirPath := filepath.Join(t.TempDir(), "user")
+
+	err = copyUserProfileTemplate(templateDirPath, userDirPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for name, expect := range map[string]string{
+		"registrymodifications.xcu": "<oor:items/>",
+		"autocorr/acor_en-US.dat":   "autocorrect",
+	} {
+		actual, err := os.ReadFile(filepath.Join(userDirPath, name))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if string(actual) != expect {
+			t.Errorf("expected '%s' for '%s' but got '%s'", expect, name, actual)
+		}
+	}
+
+	_, err = os.Lstat(filepath.Join(userDirPath, "passwd"))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected the symbolic link to be left aside, but got: %v", err)
+	}
+}
+
+func TestCopyUserProfileTemplate_missingTemplate(t *testing.T) {
+	err := copyUserProfileTemplate(filepath.Join(t.TempDir(), "foo"), t.TempDir())
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
+
+func TestCopyUserProfileFonts(t *testing.T) {
+	dirPath := t.TempDir()
+
+	var fonts []gotenberg.Font
+	for _, name := range []string{"a/font.ttf", "b/font.ttf"} {
+		path := filepath.Join(dirPath, name)
+
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		err = os.WriteFile(path, []byte(name), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		fonts = append(fonts, gotenberg.Font{Path: path, Format: gotenberg.FontTrueType})
+	}
+
+	userDirPath := filepath.Join(t.TempDir(), "user")
+
+	err := copyUserProfileFonts(userDirPath, fonts)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for name, expect := range map[string]string{
+		"fonts/0-font.ttf": "a/font.ttf",
+		"fonts/1-font.ttf": "b/font.ttf",
+	} {
+		actual, err := os.ReadFile(filepath.Join(userDirPath, name))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if string(actual) != expect {
+			t.Errorf("expected '%s' for '%s' but got '%s'", expect, name, actual)
+		}
+	}
+
+	err = copyUserProfileFonts(userDirPath, []gotenberg.Font{{Path: filepath.Join(dirPath, "missing.ttf")}})
+	if err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestSetUserProfileLocale(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		template    string
+		locale      string
+		expectError bool
+		expect      []string
+	}{
+		{
+			scenario: "no template",
+			locale:   "de_DE",
+			expect: []string{
+				`<item oor:path="/org.openoffice.Setup/L10N"><prop oor:name="ooSetupSystemLocale" oor:op="fuse"><value>de-DE</value></prop></item>`,
+				`<item oor:path="/org.openoffice.Office.Linguistic/General"><prop oor:name="DefaultLocale" oor:op="fuse"><value>de-DE</value></prop></item>`,
+				"</oor:items>\n",
+			},
+		},
+		{
+			scenario: "template",
+			template: `<?xml version="1.0" encoding="UTF-8"?><oor:items xmlns:oor="http://openoffice.org/2001/registry"><item oor:path="/org.openoffice.Setup/L10N"><prop oor:name="ooSetupSystemLocale" oor:op="fuse"><value>en-US</value></prop></item></oor:items>`,
+			locale:   "fr-FR",
+			expect: []string{
+				`<value>en-US</value></prop></item><item oor:path="/org.openoffice.Setup/L10N"><prop oor:name="ooSetupSystemLocale" oor:op="fuse"><value>fr-FR</value>`,
+			},
+		},
+		{
+			scenario: "template without settings",
+			template: `<oor:items xmlns:oor="http://openoffice.org/2001/registry"/>`,
+			locale:   "fr-FR",
+			expect: []string{
+				`<oor:items xmlns:oor="http://openoffice.org/2001/registry"><item oor:path="/org.openoffice.Setup/L10N">`,
+			},
+		},
+		{
+			scenario:    "invalid template",
+			template:    "foo",
+			locale:      "fr-FR",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid locale",
+			locale:      "<foo>",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			userDirPath := filepath.Join(t.TempDir(), "user")
+			path := filepath.Join(userDirPath, registryModificationsXcu)
+
+			if tc.template != "" {
+				err := os.MkdirAll(userDirPath, 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = os.WriteFile(path, []byte(tc.template), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			err := setUserProfileLocale(userDirPath, tc.locale)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			actual, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			for _, expect := range tc.expect {
+				if !strings.Contains(string(actual), expect) {
+					t.Errorf("expected '%s' in '%s'", expect, actual)
+				}
+			}
+		})
+	}
+}
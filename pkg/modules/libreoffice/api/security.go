@@ -0,0 +1,54 @@
+package api
+
+import (
+	"fmt"
+)
+
+const (
+	// MacroSecurityDisable prevents LibreOffice from executing the macros
+	// of a document.
+	MacroSecurityDisable string = "disable"
+
+	// MacroSecurityEnable lets LibreOffice execute the macros of a document,
+	// without confirmation. Only for trusted documents.
+	MacroSecurityEnable string = "enable"
+)
+
+// MacroSecurities are the available macro security levels.
+var MacroSecurities = []string{MacroSecurityDisable, MacroSecurityEnable}
+
+// See https://api.libreoffice.org/docs/idl/ref/namespacecom_1_1sun_1_1star_1_1document_1_1MacroExecMode.html
+// and https://api.libreoffice.org/docs/idl/ref/namespacecom_1_1sun_1_1star_1_1document_1_1UpdateDocMode.html.
+const (
+	macroExecModeNeverExecute        = 0
+	macroExecModeAlwaysExecuteNoWarn = 4
+	updateDocModeNoUpdate            = 0
+	updateDocModeQuietUpdate         = 1
+)
+
+// loadPropertiesArgs returns the unoconverter arguments which set the
+// properties LibreOffice loads a document with, according to the macro
+// security, external links and repair options. unoconverter takes the "key=value"
+// import options as load properties.
+func loadPropertiesArgs(options Options) []string {
+	macroExecutionMode := macroExecModeNeverExecute
+	if options.MacroSecurity == MacroSecurityEnable {
+		macroExecutionMode = macroExecModeAlwaysExecuteNoWarn
+	}
+
+	updateDocMode := updateDocModeNoUpdate
+	if options.AllowExternalLinks {
+		updateDocMode = updateDocModeQuietUpdate
+	}
+
+	args := []string{
+		"--import", fmt.Sprintf("MacroExecutionMode=%d", macroExecutionMode),
+		"--import", fmt.Sprintf("UpdateDocMode=%d", updateDocMode),
+	}
+
+	if options.repairPackage {
+		args = append(args, "--import", "RepairPackage=true")
+	}
+
+	return args
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadPropertiesArgs(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		options  Options
+		expect   []string
+	}{
+		{
+			scenario: "default options",
+			options:  Options{},
+			expect:   []string{"--import", "MacroExecutionMode=0", "--import", "UpdateDocMode=0"},
+		},
+		{
+			scenario: "disabled macros",
+			options:  Options{MacroSecurity: MacroSecurityDisable},
+			expect:   []string{"--import", "MacroExecutionMode=0", "--import", "UpdateDocMode=0"},
+		},
+		{
+			scenario: "enabled macros and external links",
+			options:  Options{MacroSecurity: MacroSecurityEnable, AllowExternalLinks: true},
+			expect:   []string{"--import", "MacroExecutionMode=4", "--import", "UpdateDocMode=1"},
+		},
+		{
+			scenario: "repair",
+			options:  Options{RepairMode: true, repairPackage: true},
+			expect:   []string{"--import", "MacroExecutionMode=0", "--import", "UpdateDocMode=0", "--import", "RepairPackage=true"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := loadPropertiesArgs(tc.options)
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSpreadsheetOptions happens if the spreadsheet options cannot be
+// applied to a document, e.g., if a sheet does not exist.
+var ErrInvalidSpreadsheetOptions = errors.New("invalid spreadsheet options")
+
+// SpreadsheetOptions gathers the print options of the spreadsheets. Only the
+// XLSX and ODS documents support them, see [SupportsSpreadsheetOptions].
+type SpreadsheetOptions struct {
+	// FitToWidth is the number of pages the width of each sheet fits to.
+	// Zero means automatic.
+	// Optional.
+	FitToWidth int
+
+	// FitToHeight is the number of pages the height of each sheet fits to.
+	// Zero means automatic.
+	// Optional.
+	FitToHeight int
+
+	// Sheets are the sheets to print, either their names or their 1-based
+	// indexes. Empty means all sheets.
+	// Optional.
+	Sheets []string
+
+	// PrintArea is the range of cells to print for each printed sheet, e.g.,
+	// "A1:H50".
+	// Optional.
+	PrintArea string
+}
+
+// IsSet tells if at least one option is set.
+func (o SpreadsheetOptions) IsSet() bool {
+	return o.FitToWidth > 0 || o.FitToHeight > 0 || len(o.Sheets) > 0 || o.PrintArea != ""
+}
+
+// printAreaRegexp matches a range of cells, e.g., "A1:H50" or "$A$1:$H$50".
+var printAreaRegexp = regexp.MustCompile(`^\$?([A-Za-z]{1,3})\$?([0-9]{1,7}):\$?([A-Za-z]{1,3})\$?([0-9]{1,7})$`)
+
+// ValidatePrintArea checks that a print area is a range of cells. An empty
+// print area is always valid.
+func ValidatePrintArea(printArea string) error {
+	if printArea == "" || printAreaRegexp.MatchString(printArea) {
+		return nil
+	}
+
+	return fmt.Errorf("print area '%s' is not a range of cells like 'A1:H50': %w", printArea, ErrInvalidSpreadsheetOptions)
+}
+
+// SupportsSpreadsheetOptions tells if the spreadsheet options apply to a
+// document, according to its extension.
+func SupportsSpreadsheetOptions(filename string) bool {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".xlsx", ".ods":
+		return true
+	default:
+		return false
+	}
+}
+
+// prepareSpreadsheet writes a copy of a spreadsheet with the given options
+// applied to its print settings.
+func prepareSpreadsheet(inputPath, outputPath string, options SpreadsheetOptions) error {
+	var prepare func(files map[string][]byte) error
+
+	switch strings.ToLower(path.Ext(inputPath)) {
+	case ".xlsx":
+		prepare = func(files map[string][]byte) error {
+			return prepareXlsx(files, options)
+		}
+	case ".ods":
+		prepare = func(files map[string][]byte) error {
+			return prepareOds(files, options)
+		}
+	default:
+		return fmt.Errorf("spreadsheet options for '%s': %w", path.Base(inputPath), ErrInvalidSpreadsheetOptions)
+	}
+
+	return rewriteZip(inputPath, outputPath, func(name string) bool {
+		return strings.HasSuffix(name, ".xml") || strings.HasSuffix(name, ".rels")
+	}, prepare)
+}
+
+// sheetIndexes returns the 0-based indexes of the selected sheets, in the
+// order of the document, or all the indexes if there is no selection.
+func sheetIndexes(names []string, selection []string) ([]int, error) {
+	if len(selection) == 0 {
+		indexes := make([]int, len(names))
+		for i := range names {
+			indexes[i] = i
+		}
+
+		return indexes, nil
+	}
+
+	selected := make([]bool, len(names))
+	for _, sheet := range selection {
+		found := false
+
+		for i, name := range names {
+			if name == sheet {
+				selected[i] = true
+				found = true
+			}
+		}
+
+		index, err := strconv.Atoi(sheet)
+		if !found && err == nil && index >= 1 && index <= len(names) {
+			selected[index-1] = true
+			found = true
+		}
+
+		if !found {
+			return nil, fmt.Errorf("sheet '%s' does not exist: %w", sheet, ErrInvalidSpreadsheetOptions)
+		}
+	}
+
+	var indexes []int
+	for i, ok := range selected {
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes, nil
+}
+
+// absoluteRange returns a range of cells with absolute references, e.g.,
+// "$A$1:$H$50" for "A1:H50".
+func absoluteRange(printArea string) string {
+	matches := printAreaRegexp.FindStringSubmatch(printArea)
+
+	return fmt.Sprintf("$%s$%s:$%s$%s", strings.ToUpper(matches[1]), matches[2], strings.ToUpper(matches[3]), matches[4])
+}
+
+// quoteSheetName returns a sheet name between single quotes, as expected in
+// the formulas.
+func quoteSheetName(name string) string {
+	return "'" + strings.ReplaceAll(name, "'", "''") + "'"
+}
+
+var (
+	xmlUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&amp;", "&")
+	xmlEscaper   = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;")
+)
+
+// xmlAttr returns the unescaped value of an attribute of an XML start tag.
+func xmlAttr(tag, name string) (string, bool) {
+	matches := regexp.MustCompile(`\s` + regexp.QuoteMeta(name) + `="([^"]*)"`).FindStringSubmatch(tag)
+	if matches == nil {
+		return "", false
+	}
+
+	return xmlUnescaper.Replace(matches[1]), true
+}
+
+// setXmlAttr sets an attribute of an XML start tag, escaping its value.
+func setXmlAttr(tag, name, value string) string {
+	attr := fmt.Sprintf(`%s="%s"`, name, xmlEscaper.Replace(value))
+
+	attrRegexp := regexp.MustCompile(`\s` + regexp.QuoteMeta(name) + `="[^"]*"`)
+	if attrRegexp.MatchString(tag) {
+		return attrRegexp.ReplaceAllLiteralString(tag, " "+attr)
+	}
+
+	if strings.HasSuffix(tag, "/>") {
+		return strings.TrimSuffix(tag, "/>") + " " + attr + "/>"
+	}
+
+	return strings.TrimSuffix(tag, ">") + " " + attr + ">"
+}
+
+// removeXmlAttr removes an attribute of an XML start tag.
+func removeXmlAttr(tag, name string) string {
+	return regexp.MustCompile(`\s`+regexp.QuoteMeta(name)+`="[^"]*"`).ReplaceAllLiteralString(tag, "")
+}
+
+var (
+	xlsxSheetRegexp         = regexp.MustCompile(`<sheet\s[^>]*>`)
+	xlsxRelationshipRegexp  = regexp.MustCompile(`<Relationship\s[^>]*>`)
+	xlsxWorkbookViewRegexp  = regexp.MustCompile(`<workbookView(\s[^>]*)?>`)
+	xlsxDefinedNameRegexp   = regexp.MustCompile(`(?s)<definedName\s[^>]*>.*?</definedName>`)
+	xlsxSheetPrRegexp       = regexp.MustCompile(`(?s)<sheetPr(\s[^>]*)?(/>|>.*?</sheetPr>)`)
+	xlsxPageSetUpPrRegexp   = regexp.MustCompile(`<pageSetUpPr(\s[^>]*)?/?>`)
+	xlsxPageSetupRegexp     = regexp.MustCompile(`<pageSetup(\s[^>]*)?/?>`)
+	xlsxWorksheetRegexp     = regexp.MustCompile(`<worksheet(\s[^>]*)?>`)
+	xlsxAfterPageSetupRegex = regexp.MustCompile(`<(headerFooter|rowBreaks|colBreaks|customProperties|cellWatches|ignoredErrors|smartTags|drawing|legacyDrawing|legacyDrawingHF|drawingHF|picture|oleObjects|controls|webPublishItems|tableParts|extLst)[\s/>]|</worksheet>`)
+)
+
+// prepareXlsx applies the spreadsheet options to the XML files of an XLSX
+// document.
+func prepareXlsx(files map[string][]byte, options SpreadsheetOptions) error {
+	workbook, ok := files["xl/workbook.xml"]
+	if !ok {
+		return fmt.Errorf("no workbook: %w", ErrInvalidSpreadsheetOptions)
+	}
+
+	workbookXml := string(workbook)
+	sheetTags := xlsxSheetRegexp.FindAllString(workbookXml, -1)
+
+	names := make([]string, len(sheetTags))
+	for i, tag := range sheetTags {
+		names[i], _ = xmlAttr(tag, "name")
+	}
+
+	indexes, err := sheetIndexes(names, options.Sheets)
+	if err != nil {
+		return err
+	}
+
+	targeted := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		targeted[index] = true
+	}
+
+	// The sheets which are not selected are hidden, as LibreOffice does not
+	// print the hidden sheets.
+	if len(options.Sheets) > 0 {
+		i := 0
+		workbookXml = xlsxSheetRegexp.ReplaceAllStringFunc(workbookXml, func(tag string) string {
+			defer func() { i++ }()
+
+			if targeted[i] {
+				return removeXmlAttr(tag, "state")
+			}
+
+			return setXmlAttr(tag, "state", "hidden")
+		})
+
+		// The active sheet must be visible.
+		workbookXml = xlsxWorkbookViewRegexp.ReplaceAllStringFunc(workbookXml, func(tag string) string {
+			return setXmlAttr(tag, "activeTab", strconv.Itoa(indexes[0]))
+		})
+	}
+
+	if options.PrintArea != "" {
+		workbookXml = xlsxDefinedNameRegexp.ReplaceAllStringFunc(workbookXml, func(element string) string {
+			name, _ := xmlAttr(element, "name")
+			localSheetId, _ := xmlAttr(element, "localSheetId")
+			index, err := strconv.Atoi(localSheetId)
+
+			if name == "_xlnm.Print_Area" && err == nil && targeted[index] {
+				return ""
+			}
+
+			return element
+		})
+
+		var definedNames strings.Builder
+		for _, index := range indexes {
+			definedNames.WriteString(fmt.Sprintf(
+				`<definedName name="_xlnm.Print_Area" localSheetId="%d">%s</definedName>`,
+				index,
+				xmlEscaper.Replace(quoteSheetName(names[index])+"!"+absoluteRange(options.PrintArea)),
+			))
+		}
+
+		switch {
+		case strings.Contains(workbookXml, "</definedNames>"):
+			workbookXml = strings.Replace(workbookXml, "</definedNames>", definedNames.String()+"</definedNames>", 1)
+		case strings.Contains(workbookXml, "<definedNames/>"):
+			workbookXml = strings.Replace(workbookXml, "<definedNames/>", "<definedNames>"+definedNames.String()+"</definedNames>", 1)
+		default:
+			workbookXml = strings.Replace(workbookXml, "</sheets>", "</sheets><definedNames>"+definedNames.String()+"</definedNames>", 1)
+		}
+	}
+
+	files["xl/workbook.xml"] = []byte(workbookXml)
+
+	if options.FitToWidth <= 0 && options.FitToHeight <= 0 {
+		return nil
+	}
+
+	// The sheets are worksheet parts, found thanks to the relationships of
+	// the workbook.
+	targets := make(map[string]string)
+	for _, tag := range xlsxRelationshipRegexp.FindAllString(string(files["xl/_rels/workbook.xml.rels"]), -1) {
+		id, _ := xmlAttr(tag, "Id")
+		target, _ := xmlAttr(tag, "Target")
+
+		if strings.HasPrefix(target, "/") {
+			targets[id] = strings.TrimPrefix(target, "/")
+		} else {
+			targets[id] = path.Join("xl", target)
+		}
+	}
+
+	for _, index := range indexes {
+		id, _ := xmlAttr(sheetTags[index], "r:id")
+
+		sheetPath, ok := targets[id]
+		if !ok {
+			return fmt.Errorf("no part for sheet '%s': %w", names[index], ErrInvalidSpreadsheetOptions)
+		}
+
+		sheet, ok := files[sheetPath]
+		if !ok {
+			// E.g., a chart sheet.
+			continue
+		}
+
+		files[sheetPath] = []byte(fitXlsxSheet(string(sheet), options.FitToWidth, options.FitToHeight))
+	}
+
+	return nil
+}
+
+// fitXlsxSheet sets the number of pages the width and the height of a
+// worksheet fit to.
+func fitXlsxSheet(sheetXml string, width, height int) string {
+	// First, the fitToPage property of the sheet.
+	pageSetUpPr := `<pageSetUpPr fitToPage="1"/>`
+
+	if loc := xlsxSheetPrRegexp.FindStringIndex(sheetXml); loc != nil {
+		sheetPr := sheetXml[loc[0]:loc[1]]
+
+		switch {
+		case xlsxPageSetUpPrRegexp.MatchString(sheetPr):
+			sheetPr = xlsxPageSetUpPrRegexp.ReplaceAllStringFunc(sheetPr, func(tag string) string {
+				return setXmlAttr(tag, "fitToPage", "1")
+			})
+		case strings.HasSuffix(sheetPr, "/>"):
+			sheetPr = strings.TrimSuffix(sheetPr, "/>") + ">" + pageSetUpPr + "</sheetPr>"
+		default:
+			sheetPr = strings.TrimSuffix(sheetPr, "</sheetPr>") + pageSetUpPr + "</sheetPr>"
+		}
+
+		sheetXml = sheetXml[:loc[0]] + sheetPr + sheetXml[loc[1]:]
+	} else if loc := xlsxWorksheetRegexp.FindStringIndex(sheetXml); loc != nil {
+		sheetXml = sheetXml[:loc[1]] + "<sheetPr>" + pageSetUpPr + "</sheetPr>" + sheetXml[loc[1]:]
+	}
+
+	// Then, the number of pages of the page setup.
+	setup := func(tag string) string {
+		tag = setXmlAttr(tag, "fitToWidth", strconv.Itoa(width))
+		return setXmlAttr(tag, "fitToHeight", strconv.Itoa(height))
+	}
+
+	if xlsxPageSetupRegexp.MatchString(sheetXml) {
+		return xlsxPageSetupRegexp.ReplaceAllStringFunc(sheetXml, setup)
+	}
+
+	if loc := xlsxAfterPageSetupRegex.FindStringIndex(sheetXml); loc != nil {
+		return sheetXml[:loc[0]] + setup("<pageSetup/>") + sheetXml[loc[0]:]
+	}
+
+	return sheetXml
+}
+
+// odsHiddenTableStyle is the name of the automatic style which hides the
+// sheets of an ODS document.
+const odsHiddenTableStyle = "taGotenbergHidden"
+
+var (
+	odsTableRegexp            = regexp.MustCompile(`<table:table(\s[^>]*)?>`)
+	odsPageLayoutPropsRegexp  = regexp.MustCompile(`<style:page-layout-properties(\s[^>]*)?/?>`)
+	odsAutomaticStylesRegexp  = regexp.MustCompile(`<office:automatic-styles(\s[^>]*)?/>|</office:automatic-styles>`)
+	odsDocumentContentRegexp  = regexp.MustCompile(`<office:document-content(\s[^>]*)?>`)
+	odsHiddenTableStyleString = `<style:style style:name="` + odsHiddenTableStyle + `" style:family="table"><style:table-properties table:display="false"/></style:style>`
+)
+
+// prepareOds applies the spreadsheet options to the XML files of an ODS
+// document.
+func prepareOds(files map[string][]byte, options SpreadsheetOptions) error {
+	content, ok := files["content.xml"]
+	if !ok {
+		return fmt.Errorf("no content: %w", ErrInvalidSpreadsheetOptions)
+	}
+
+	contentXml := string(content)
+	tableTags := odsTableRegexp.FindAllString(contentXml, -1)
+
+	names := make([]string, len(tableTags))
+	for i, tag := range tableTags {
+		names[i], _ = xmlAttr(tag, "table:name")
+	}
+
+	indexes, err := sheetIndexes(names, options.Sheets)
+	if err != nil {
+		return err
+	}
+
+	targeted := make(map[int]bool, len(indexes))
+	for _, index := range indexes {
+		targeted[index] = true
+	}
+
+	i := 0
+	contentXml = odsTableRegexp.ReplaceAllStringFunc(contentXml, func(tag string) string {
+		defer func() { i++ }()
+
+		// The sheets which are not selected are hidden, as LibreOffice
+		// does not print the hidden sheets.
+		if !targeted[i] {
+			return setXmlAttr(tag, "table:style-name", odsHiddenTableStyle)
+		}
+
+		if options.PrintArea != "" {
+			name := quoteSheetName(names[i])
+			matches := printAreaRegexp.FindStringSubmatch(options.PrintArea)
+			printRange := fmt.Sprintf("%s.%s%s:%s.%s%s", name, strings.ToUpper(matches[1]), matches[2], name, strings.ToUpper(matches[3]), matches[4])
+
+			return setXmlAttr(tag, "table:print-ranges", printRange)
+		}
+
+		return tag
+	})
+
+	if len(options.Sheets) > 0 {
+		contentXml = odsAutomaticStylesRegexp.ReplaceAllStringFunc(contentXml, func(tag string) string {
+			if strings.HasSuffix(tag, "/>") {
+				return strings.TrimSuffix(tag, "/>") + ">" + odsHiddenTableStyleString + "</office:automatic-styles>"
+			}
+
+			return odsHiddenTableStyleString + tag
+		})
+
+		if !strings.Contains(contentXml, odsHiddenTableStyleString) {
+			loc := odsDocumentContentRegexp.FindStringIndex(contentXml)
+			if loc == nil {
+				return fmt.Errorf("no document content: %w", ErrInvalidSpreadsheetOptions)
+			}
+
+			contentXml = contentXml[:loc[1]] + "<office:automatic-styles>" + odsHiddenTableStyleString + "</office:automatic-styles>" + contentXml[loc[1]:]
+		}
+	}
+
+	files["content.xml"] = []byte(contentXml)
+
+	if options.FitToWidth <= 0 && options.FitToHeight <= 0 {
+		return nil
+	}
+
+	// The scale of the pages belongs to the page styles, shared by the
+	// sheets: it applies to all of them.
+	styles, ok := files["styles.xml"]
+	if !ok {
+		return fmt.Errorf("no styles: %w", ErrInvalidSpreadsheetOptions)
+	}
+
+	files["styles.xml"] = []byte(odsPageLayoutPropsRegexp.ReplaceAllStringFunc(string(styles), func(tag string) string {
+		tag = removeXmlAttr(tag, "style:scale-to")
+		tag = removeXmlAttr(tag, "style:scale-to-pages")
+		tag = removeXmlAttr(tag, "style:scale-to-X")
+		tag = removeXmlAttr(tag, "style:scale-to-Y")
+
+		if options.FitToWidth > 0 {
+			tag = setXmlAttr(tag, "style:scale-to-X", strconv.Itoa(options.FitToWidth))
+		}
+
+		if options.FitToHeight > 0 {
+			tag = setXmlAttr(tag, "style:scale-to-Y", strconv.Itoa(options.FitToHeight))
+		}
+
+		return tag
+	}))
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeZip writes a ZIP archive with the given entries, in order.
+func writeZip(t *testing.T, path string, entries [][2]string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	writer := zip.NewWriter(f)
+
+	for _, entry := range entries {
+		w, err := writer.Create(entry[0])
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		_, err = w.Write([]byte(entry[1]))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+// readZip returns the entries of a ZIP archive, keyed by name.
+func readZip(t *testing.T, path string) map[string]string {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	entries := make(map[string]string)
+	for _, file := range reader.File {
+		content, err := readZipFile(file)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		entries[file.Name] = string(content)
+	}
+
+	return entries
+}
+
+const (
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8"?><workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><bookViews><workbookView activeTab="0"/></bookViews><sheets><sheet name="Summary" sheetId="1" r:id="rId1"/><sheet name="Q&amp;A" sheetId="2" r:id="rId2"/></sheets></workbook>`
+	xlsxRels     = `<?xml version="1.0" encoding="UTF-8"?><Relationships><Relationship Id="rId1" Type="worksheet" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Type="worksheet" Target="/xl/worksheets/sheet2.xml"/></Relationships>`
+	xlsxSheet1   = `<?xml version="1.0" encoding="UTF-8"?><worksheet><sheetData/><pageMargins left="0.7"/><headerFooter/></worksheet>`
+	xlsxSheet2   = `<?xml version="1.0" encoding="UTF-8"?><worksheet><sheetPr><tabColor rgb="FF0000"/></sheetPr><sheetData/><pageSetup orientation="landscape" fitToWidth="3"/></worksheet>`
+	odsContent   = `<?xml version="1.0" encoding="UTF-8"?><office:document-content><office:automatic-styles/><office:body><office:spreadsheet><table:table table:name="Summary" table:style-name="ta1"><table:table-row/></table:table><table:table table:name="Q&apos;s" table:style-name="ta1"><table:table-row/></table:table></office:spreadsheet></office:body></office:document-content>`
+	odsStyles    = `<?xml version="1.0" encoding="UTF-8"?><office:document-styles><style:page-layout style:name="pm1"><style:page-layout-properties style:scale-to="100%"/></style:page-layout></office:document-styles>`
+)
+
+func TestPrepareSpreadsheet(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		filename    string
+		entries     [][2]string
+		options     SpreadsheetOptions
+		expectError bool
+		expect      map[string][]string
+		notExpect   map[string][]string
+	}{
+		{
+			scenario:    "unsupported document",
+			filename:    "sheet.xls",
+			options:     SpreadsheetOptions{FitToWidth: 1},
+			expectError: true,
+		},
+		{
+			scenario: "XLSX: unknown sheet",
+			filename: "sheet.xlsx",
+			entries: [][2]string{
+				{"xl/workbook.xml", xlsxWorkbook},
+				{"xl/_rels/workbook.xml.rels", xlsxRels},
+			},
+			options:     SpreadsheetOptions{Sheets: []string{"Foo"}},
+			expectError: true,
+		},
+		{
+			scenario: "XLSX: fit to page",
+			filename: "sheet.xlsx",
+			entries: [][2]string{
+				{"xl/workbook.xml", xlsxWorkbook},
+				{"xl/_rels/workbook.xml.rels", xlsxRels},
+				{"xl/worksheets/sheet1.xml", xlsxSheet1},
+				{"xl/worksheets/sheet2.xml", xlsxSheet2},
+			},
+			options: SpreadsheetOptions{FitToWidth: 1},
+			expect: map[string][]string{
+				"xl/worksheets/sheet1.xml": {
+					`<worksheet><sheetPr><pageSetUpPr fitToPage="1"/></sheetPr>`,
+					`<pageMargins left="0.7"/><pageSetup fitToWidth="1" fitToHeight="0"/><headerFooter/>`,
+				},
+				"xl/worksheets/sheet2.xml": {
+					`<sheetPr><tabColor rgb="FF0000"/><pageSetUpPr fitToPage="1"/></sheetPr>`,
+					`<pageSetup orientation="landscape" fitToWidth="1" fitToHeight="0"/>`,
+				},
+			},
+		},
+		{
+			scenario: "XLSX: sheets and print area",
+			filename: "sheet.xlsx",
+			entries: [][2]string{
+				{"xl/workbook.xml", xlsxWorkbook},
+				{"xl/_rels/workbook.xml.rels", xlsxRels},
+				{"xl/worksheets/sheet1.xml", xlsxSheet1},
+				{"xl/worksheets/sheet2.xml", xlsxSheet2},
+			},
+			options: SpreadsheetOptions{Sheets: []string{"2"}, PrintArea: "a1:H50"},
+			expect: map[string][]string{
+				"xl/workbook.xml": {
+					`<workbookView activeTab="1"/>`,
+					`<sheet name="Summary" sheetId="1" r:id="rId1" state="hidden"/>`,
+					`<sheet name="Q&amp;A" sheetId="2" r:id="rId2"/>`,
+					`</sheets><definedNames><definedName name="_xlnm.Print_Area" localSheetId="1">&apos;Q&amp;A&apos;!$A$1:$H$50</definedName></definedNames>`,
+				},
+				"xl/worksheets/sheet1.xml": {xlsxSheet1},
+			},
+		},
+		{
+			scenario: "ODS: unknown sheet index",
+			filename: "sheet.ods",
+			entries: [][2]string{
+				{"content.xml", odsContent},
+				{"styles.xml", odsStyles},
+			},
+			options:     SpreadsheetOptions{Sheets: []string{"3"}},
+			expectError: true,
+		},
+		{
+			scenario: "ODS: all options",
+			filename: "sheet.ODS",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.spreadsheet"},
+				{"content.xml", odsContent},
+				{"styles.xml", odsStyles},
+			},
+			options: SpreadsheetOptions{FitToWidth: 2, FitToHeight: 3, Sheets: []string{"Q's"}, PrintArea: "B2:C3"},
+			expect: map[string][]string{
+				"content.xml": {
+					`<office:automatic-styles><style:style style:name="taGotenbergHidden" style:family="table"><style:table-properties table:display="false"/></style:style></office:automatic-styles>`,
+					`<table:table table:name="Summary" table:style-name="taGotenbergHidden">`,
+					`<table:table table:name="Q&apos;s" table:style-name="ta1" table:print-ranges="&apos;Q&apos;&apos;s&apos;.B2:&apos;Q&apos;&apos;s&apos;.C3">`,
+				},
+				"styles.xml": {
+					`<style:page-layout-properties style:scale-to-X="2" style:scale-to-Y="3"/>`,
+				},
+				"mimetype": {"application/vnd.oasis.opendocument.spreadsheet"},
+			},
+			notExpect: map[string][]string{
+				"styles.xml": {`style:scale-to="100%"`},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, tc.filename)
+			outputPath := filepath.Join(dirPath, "output"+filepath.Ext(tc.filename))
+
+			writeZip(t, inputPath, tc.entries)
+
+			err := prepareSpreadsheet(inputPath, outputPath, tc.options)
+
+			if tc.expectError {
+				if !errors.Is(err, ErrInvalidSpreadsheetOptions) {
+					t.Fatalf("expected error %v but got: %v", ErrInvalidSpreadsheetOptions, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			entries := readZip(t, outputPath)
+
+			for name, expects := range tc.expect {
+				for _, expect := range expects {
+					if !strings.Contains(entries[name], expect) {
+						t.Errorf("expected '%s' to contain '%s' but got: %s", name, expect, entries[name])
+					}
+				}
+			}
+
+			for name, notExpects := range tc.notExpect {
+				for _, notExpect := range notExpects {
+					if strings.Contains(entries[name], notExpect) {
+						t.Errorf("expected '%s' not to contain '%s' but got: %s", name, notExpect, entries[name])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePrintArea(t *testing.T) {
+	for _, tc := range []struct {
+		printArea   string
+		expectError bool
+	}{
+		{printArea: ""},
+		{printArea: "A1:H50"},
+		{printArea: "$a$1:$XFD$1048576"},
+		{printArea: "A1", expectError: true},
+		{printArea: "Sheet1!A1:H50", expectError: true},
+	} {
+		t.Run(tc.printArea, func(t *testing.T) {
+			err := ValidatePrintArea(tc.printArea)
+
+			if tc.expectError && !errors.Is(err, ErrInvalidSpreadsheetOptions) {
+				t.Fatalf("expected error %v but got: %v", ErrInvalidSpreadsheetOptions, err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestSupportsSpreadsheetOptions(t *testing.T) {
+	for _, tc := range []struct {
+		filename string
+		expect   bool
+	}{
+		{filename: "sheet.xlsx", expect: true},
+		{filename: "SHEET.ODS", expect: true},
+		{filename: "sheet.xls", expect: false},
+		{filename: "document.docx", expect: false},
+	} {
+		t.Run(tc.filename, func(t *testing.T) {
+			actual := SupportsSpreadsheetOptions(tc.filename)
+			if actual != tc.expect {
+				t.Errorf("expected %t but got %t", tc.expect, actual)
+			}
+		})
+	}
+}
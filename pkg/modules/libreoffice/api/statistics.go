@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// documentStatistics are the counts of a document, as LibreOffice lays it
+// out. The counts which do not apply to the document are zero.
+type documentStatistics struct {
+	Pages  int `json:"pages"`
+	Slides int `json:"slides"`
+	Sheets int `json:"sheets"`
+}
+
+// statistics returns the counts of a document, thanks to unodocument. A
+// document which is neither a text document, a spreadsheet, a presentation
+// nor a drawing returns errUnoDocumentNotSupported.
+func (p *libreOfficeProcess) statistics(ctx context.Context, logger *zap.Logger, inputPath string, options Options) (documentStatistics, error) {
+	if !p.isStarted.Load() {
+		return documentStatistics{}, errors.New("LibreOffice not started, cannot handle statistics")
+	}
+
+	outputPath := filepath.Join(filepath.Dir(inputPath), fmt.Sprintf("%s.json", uuid.NewString()))
+
+	err := p.unoDocument(ctx, logger, inputPath, options, "statistics", "--output", outputPath)
+	if err != nil {
+		return documentStatistics{}, err
+	}
+
+	defer func() {
+		err := os.Remove(outputPath)
+		if err != nil {
+			logger.Error(fmt.Sprintf("remove statistics file: %v", err))
+		}
+	}()
+
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		return documentStatistics{}, fmt.Errorf("read statistics: %w", err)
+	}
+
+	var statistics documentStatistics
+	err = json.Unmarshal(content, &statistics)
+	if err != nil {
+		return documentStatistics{}, fmt.Errorf("unmarshal statistics: %w", err)
+	}
+
+	return statistics, nil
+}
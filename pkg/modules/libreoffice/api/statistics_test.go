@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestLibreOfficeProcess_statistics(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		script        string
+		notStarted    bool
+		expectError   bool
+		expectErrorIs error
+		expect        documentStatistics
+	}{
+		{
+			scenario:    "LibreOffice not started",
+			notStarted:  true,
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			// The output path follows the --output argument.
+			script: `while [ "$1" != "--output" ]; do shift; done; echo '{"pages":3}' > "$2"`,
+			expect: documentStatistics{Pages: 3},
+		},
+		{
+			scenario:      "not supported",
+			script:        "exit 3",
+			expectError:   true,
+			expectErrorIs: errUnoDocumentNotSupported,
+		},
+		{
+			scenario:    "invalid statistics",
+			script:      `while [ "$1" != "--output" ]; do shift; done; echo 'foo' > "$2"`,
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+
+			binPath := filepath.Join(dirPath, "unodocument")
+			err := os.WriteFile(binPath, []byte("#!/bin/sh\n"+tc.script+"\n"), 0o755)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			p := &libreOfficeProcess{arguments: libreOfficeArguments{unoDocumentBinPath: binPath}}
+			p.isStarted.Store(!tc.notStarted)
+
+			actual, err := p.statistics(context.Background(), zap.NewNop(), filepath.Join(dirPath, "document.doc"), Options{})
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				if tc.expectErrorIs != nil && !errors.Is(err, tc.expectErrorIs) {
+					t.Fatalf("expected error %v but got: %v", tc.expectErrorIs, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual != tc.expect {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestApi_Probe(t *testing.T) {
+	dirPath := t.TempDir()
+
+	writeZip(t, filepath.Join(dirPath, "sheet.xlsx"), [][2]string{
+		{"[Content_Types].xml", `<Types/>`},
+		{"xl/workbook.xml", `<workbook><sheets><sheet name="A" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	})
+
+	for name, content := range map[string]string{
+		"document.doc":  "foo",
+		"document.docx": "foo",
+	} {
+		err := os.WriteFile(filepath.Join(dirPath, name), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario      string
+		filename      string
+		statistics    func() (documentStatistics, error)
+		expectError   bool
+		expectErrorIs error
+		expect        DocumentProperties
+	}{
+		{
+			scenario: "counts from the metadata",
+			filename: "sheet.xlsx",
+			statistics: func() (documentStatistics, error) {
+				return documentStatistics{}, errors.New("unexpected statistics")
+			},
+			expect: DocumentProperties{Format: "xlsx", Sheets: 1},
+		},
+		{
+			scenario:      "corrupt document",
+			filename:      "document.docx",
+			expectError:   true,
+			expectErrorIs: ErrCorruptDocument,
+		},
+		{
+			scenario: "counts from LibreOffice",
+			filename: "document.doc",
+			statistics: func() (documentStatistics, error) {
+				return documentStatistics{Pages: 2}, nil
+			},
+			expect: DocumentProperties{Format: "doc", Pages: 2},
+		},
+		{
+			scenario: "LibreOffice cannot load the document",
+			filename: "document.doc",
+			statistics: func() (documentStatistics, error) {
+				return documentStatistics{}, ErrCorruptDocument
+			},
+			expect: DocumentProperties{Format: "doc"},
+		},
+		{
+			scenario: "LibreOffice failure",
+			filename: "document.doc",
+			statistics: func() (documentStatistics, error) {
+				return documentStatistics{}, errors.New("foo")
+			},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			a := new(Api)
+			a.setInstances(&libreOfficeInstance{
+				libreOffice: &libreOfficeMock{
+					statisticsMock: func(ctx context.Context, logger *zap.Logger, inputPath string, options Options) (documentStatistics, error) {
+						return tc.statistics()
+					},
+				},
+				supervisor: &gotenberg.ProcessSupervisorMock{
+					HealthyMock: func() bool {
+						return true
+					},
+					RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+						return task()
+					},
+				},
+			})
+
+			actual, err := a.Probe(context.Background(), zap.NewNop(), filepath.Join(dirPath, tc.filename))
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				if tc.expectErrorIs != nil && !errors.Is(err, tc.expectErrorIs) {
+					t.Fatalf("expected error %v but got: %v", tc.expectErrorIs, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// TrackedChangesShow renders the tracked changes of a document with
+	// their markup, e.g., the deletions struck through.
+	TrackedChangesShow string = "show"
+
+	// TrackedChangesAccept renders a document as if all its tracked changes
+	// were accepted.
+	TrackedChangesAccept string = "accept"
+
+	// TrackedChangesReject renders a document as if all its tracked changes
+	// were rejected.
+	TrackedChangesReject string = "reject"
+)
+
+// ErrTrackedChangesNotSupported happens if the tracked changes option is set
+// for a document which is not a text document.
+var ErrTrackedChangesNotSupported = errors.New("tracked changes not supported")
+
+// SupportsTrackedChanges tells if the tracked changes option applies to a
+// document, according to its extension, i.e., if LibreOffice opens it as a
+// text document.
+func SupportsTrackedChanges(filename string) bool {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".doc", ".docm", ".docx", ".dot", ".dotm", ".dotx", ".fodt", ".odt", ".ott", ".rtf":
+		return true
+	default:
+		return false
+	}
+}
+
+// prepareTrackedChanges writes a copy of a text document, as an ODT
+// document, with its tracked changes either shown, accepted or rejected by
+// LibreOffice, so that the rendering does not depend on the state the
+// document was saved in.
+func (p *libreOfficeProcess) prepareTrackedChanges(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
+	if !SupportsTrackedChanges(inputPath) {
+		return fmt.Errorf("tracked changes for '%s': %w", path.Base(inputPath), ErrTrackedChangesNotSupported)
+	}
+
+	switch options.TrackedChanges {
+	case TrackedChangesShow, TrackedChangesAccept, TrackedChangesReject:
+	default:
+		return fmt.Errorf("tracked changes '%s' is not one of '%s', '%s' or '%s'", options.TrackedChanges, TrackedChangesShow, TrackedChangesAccept, TrackedChangesReject)
+	}
+
+	err := p.unoDocument(ctx, logger, inputPath, options, "tracked-changes", "--mode", options.TrackedChanges, "--output", outputPath)
+	if errors.Is(err, errUnoDocumentNotSupported) {
+		return fmt.Errorf("tracked changes for '%s': %w", path.Base(inputPath), ErrTrackedChangesNotSupported)
+	}
+
+	return err
+}
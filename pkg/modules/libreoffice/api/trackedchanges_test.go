@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSupportsTrackedChanges(t *testing.T) {
+	for _, tc := range []struct {
+		filename string
+		expect   bool
+	}{
+		{filename: "document.docx", expect: true},
+		{filename: "document.DOC", expect: true},
+		{filename: "document.odt", expect: true},
+		{filename: "document.rtf", expect: true},
+		{filename: "sheet.xlsx", expect: false},
+		{filename: "slides.odp", expect: false},
+	} {
+		actual := SupportsTrackedChanges(tc.filename)
+		if actual != tc.expect {
+			t.Errorf("expected %t for '%s' but got %t", tc.expect, tc.filename, actual)
+		}
+	}
+}
+
+func TestLibreOfficeProcess_prepareTrackedChanges(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		filename      string
+		mode          string
+		exitCode      int
+		expectError   bool
+		expectErrorIs error
+		expectArgs    string
+	}{
+		{
+			scenario:      "not a text document",
+			filename:      "sheet.xlsx",
+			mode:          TrackedChangesAccept,
+			expectError:   true,
+			expectErrorIs: ErrTrackedChangesNotSupported,
+		},
+		{
+			scenario:    "invalid mode",
+			filename:    "document.docx",
+			mode:        "foo",
+			expectError: true,
+		},
+		{
+			scenario:      "not a text document, according to LibreOffice",
+			filename:      "document.doc",
+			mode:          TrackedChangesAccept,
+			exitCode:      3,
+			expectError:   true,
+			expectErrorIs: ErrTrackedChangesNotSupported,
+		},
+		{
+			scenario:   "accept",
+			filename:   "document.doc",
+			mode:       TrackedChangesAccept,
+			expectArgs: "tracked-changes --mode accept --output",
+		},
+		{
+			scenario:   "reject",
+			filename:   "document.odt",
+			mode:       TrackedChangesReject,
+			expectArgs: "tracked-changes --mode reject --output",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			p := &libreOfficeProcess{arguments: libreOfficeArguments{unoDocumentBinPath: writeUnoDocumentMock(t, dirPath, tc.exitCode)}}
+
+			err := p.prepareTrackedChanges(context.Background(), zap.NewNop(), filepath.Join(dirPath, tc.filename), filepath.Join(dirPath, "output.odt"), Options{TrackedChanges: tc.mode})
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				if tc.expectErrorIs != nil && !errors.Is(err, tc.expectErrorIs) {
+					t.Fatalf("expected error %v but got: %v", tc.expectErrorIs, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			args, err := os.ReadFile(filepath.Join(dirPath, "args"))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !strings.Contains(string(args), tc.expectArgs) {
+				t.Errorf("expected the arguments to contain '%s' but got '%s'", tc.expectArgs, args)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// errUnoDocumentNotSupported happens if a command of unodocument does not
+// apply to a document, e.g., the tracked changes of a spreadsheet.
+var errUnoDocumentNotSupported = errors.New("unodocument command not supported")
+
+// unoDocument runs a command of unodocument (see build/unodocument.py) on a
+// document, thanks to the LibreOffice instance. The document loads like with
+// unoconverter, i.e., with the same import filter, password and load
+// properties.
+func (p *libreOfficeProcess) unoDocument(ctx context.Context, logger *zap.Logger, inputPath string, options Options, command ...string) error {
+	args := []string{"--port", fmt.Sprintf("%d", p.socketPort)}
+
+	filter := importFilter(inputPath, options)
+	if filter != "" {
+		args = append(args, "--import-filter-name", filter)
+	}
+	if options.ImportOptions != "" {
+		args = append(args, "--import", options.ImportOptions)
+	}
+	if options.Password != "" {
+		args = append(args, "--password", options.Password)
+	}
+
+	args = append(args, loadPropertiesArgs(options)...)
+	args = append(args, command...)
+	args = append(args, inputPath)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, p.arguments.unoDocumentBinPath, args...)
+	if err != nil {
+		return fmt.Errorf("create unodocument command: %w", err)
+	}
+
+	exitCode, err := cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	// See the PDF conversion.
+	switch {
+	case exitCode == 3:
+		return errUnoDocumentNotSupported
+	case exitCode == 6 && options.Password != "":
+		return ErrInvalidPassword
+	case exitCode == 6:
+		return ErrCorruptDocument
+	}
+
+	return fmt.Errorf("unodocument %s: %w", command[0], err)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// writeUnoDocumentMock writes a script which stands for unodocument: it
+// records its arguments in the "args" file of a directory, then exits with
+// the given code.
+func writeUnoDocumentMock(t *testing.T, dirPath string, exitCode int) string {
+	t.Helper()
+
+	path := filepath.Join(dirPath, "unodocument")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" > '%s'\nexit %d\n", filepath.Join(dirPath, "args"), exitCode)
+
+	err := os.WriteFile(path, []byte(script), 0o755)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	return path
+}
+
+func TestLibreOfficeProcess_unoDocument(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		options       Options
+		exitCode      int
+		expectError   bool
+		expectErrorIs error
+		expectArgs    string
+	}{
+		{
+			scenario:   "success",
+			options:    Options{ImportFilter: "MS Word 97", Password: "foo"},
+			expectArgs: "--port 2002 --import-filter-name MS Word 97 --password foo --import MacroExecutionMode=0 --import UpdateDocMode=0 foo --bar document.doc",
+		},
+		{
+			scenario:      "not supported",
+			exitCode:      3,
+			expectError:   true,
+			expectErrorIs: errUnoDocumentNotSupported,
+		},
+		{
+			scenario:      "invalid password",
+			options:       Options{Password: "foo"},
+			exitCode:      6,
+			expectError:   true,
+			expectErrorIs: ErrInvalidPassword,
+		},
+		{
+			scenario:      "corrupt document",
+			exitCode:      6,
+			expectError:   true,
+			expectErrorIs: ErrCorruptDocument,
+		},
+		{
+			scenario:    "other error",
+			exitCode:    1,
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			p := &libreOfficeProcess{
+				socketPort: 2002,
+				arguments:  libreOfficeArguments{unoDocumentBinPath: writeUnoDocumentMock(t, dirPath, tc.exitCode)},
+			}
+
+			err := p.unoDocument(context.Background(), zap.NewNop(), "document.doc", tc.options, "foo", "--bar")
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				if tc.expectErrorIs != nil && !errors.Is(err, tc.expectErrorIs) {
+					t.Fatalf("expected error %v but got: %v", tc.expectErrorIs, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			args, err := os.ReadFile(filepath.Join(dirPath, "args"))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if strings.TrimSpace(string(args)) != tc.expectArgs {
+				t.Errorf("expected arguments '%s' but got '%s'", tc.expectArgs, args)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"regexp"
+)
+
+// xmlTagRegexp returns a regular expression which matches the start, end
+// and empty tags of the XML elements with the given name.
+func xmlTagRegexp(name string) *regexp.Regexp {
+	return regexp.MustCompile(`<(/?)` + regexp.QuoteMeta(name) + `(?:\s[^>]*?)?(/?)>`)
+}
+
+// xmlElementSpans returns the start and end offsets of the outermost XML
+// elements with the given name, nested ones included.
+func xmlElementSpans(content, name string) [][2]int {
+	var spans [][2]int
+
+	depth, start := 0, 0
+	for _, match := range xmlTagRegexp(name).FindAllStringSubmatchIndex(content, -1) {
+		closing := match[3] > match[2]
+		empty := match[5] > match[4]
+
+		switch {
+		case empty:
+			if depth == 0 {
+				spans = append(spans, [2]int{match[0], match[1]})
+			}
+		case closing:
+			if depth == 0 {
+				// Malformed, i.e., an end tag without a start tag.
+				continue
+			}
+
+			depth--
+			if depth == 0 {
+				spans = append(spans, [2]int{start, match[1]})
+			}
+		default:
+			if depth == 0 {
+				start = match[0]
+			}
+
+			depth++
+		}
+	}
+
+	return spans
+}
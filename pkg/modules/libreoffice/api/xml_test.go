@@ -0,0 +1,14 @@
+package api
+
+import "testing"
+
+func TestXmlElementSpans(t *testing.T) {
+	content := `<a><w:ins><w:ins/><w:insText/></w:ins></a><w:ins w:id="1"/>`
+
+	actual := xmlElementSpans(content, "w:ins")
+	expect := [][2]int{{3, 38}, {42, 59}}
+
+	if len(actual) != len(expect) || actual[0] != expect[0] || actual[1] != expect[1] {
+		t.Errorf("expected %v but got %v", expect, actual)
+	}
+}
@@ -76,12 +76,30 @@ func (mod *LibreOffice) Routes() ([]api.Route, error) {
 
 	return []api.Route{
 		convertRoute(mod.api, mod.engine),
+		probeRoute(mod.api),
+		textRoute(mod.api, mod.engine),
+	}, nil
+}
+
+// Capabilities returns the input extensions and the PDF formats of the
+// route. Each LibreOffice instance runs one conversion at a time.
+func (mod *LibreOffice) Capabilities() (map[string]interface{}, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"extensions":     mod.api.Extensions(),
+		"pdfFormats":     []string{gotenberg.PdfA1b, gotenberg.PdfA2b, gotenberg.PdfA3b},
+		"pdfUa":          true,
+		"maxConcurrency": unoConcurrency(mod.api),
 	}, nil
 }
 
 // Interface guards.
 var (
-	_ gotenberg.Module      = (*LibreOffice)(nil)
-	_ gotenberg.Provisioner = (*LibreOffice)(nil)
-	_ api.Router            = (*LibreOffice)(nil)
+	_ gotenberg.Module         = (*LibreOffice)(nil)
+	_ gotenberg.Provisioner    = (*LibreOffice)(nil)
+	_ api.Router               = (*LibreOffice)(nil)
+	_ api.CapabilitiesProvider = (*LibreOffice)(nil)
 )
@@ -170,7 +170,7 @@ func TestLibreOffice_Routes(t *testing.T) {
 	}{
 		{
 			scenario:      "routes not disabled",
-			expectRoutes:  1,
+			expectRoutes:  3,
 			disableRoutes: false,
 		},
 		{
@@ -194,3 +194,48 @@ func TestLibreOffice_Routes(t *testing.T) {
 		})
 	}
 }
+
+func TestLibreOffice_Capabilities(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		disableRoutes    bool
+		expectExtensions []string
+	}{
+		{
+			scenario:         "routes not disabled",
+			disableRoutes:    false,
+			expectExtensions: []string{".docx"},
+		},
+		{
+			scenario:         "routes disabled",
+			disableRoutes:    true,
+			expectExtensions: nil,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(LibreOffice)
+			mod.disableRoutes = tc.disableRoutes
+			mod.api = &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			}
+
+			capabilities, err := mod.Capabilities()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectExtensions == nil {
+				if capabilities != nil {
+					t.Errorf("expected no capabilities but got: %+v", capabilities)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(capabilities["extensions"], tc.expectExtensions) {
+				t.Errorf("expected extensions %+v but got: %+v", tc.expectExtensions, capabilities["extensions"])
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -71,6 +72,76 @@ func (engine *LibreOfficePdfEngine) Convert(ctx context.Context, logger *zap.Log
 	return fmt.Errorf("convert PDF to '%+v' with LibreOffice: %w", formats, err)
 }
 
+// Decrypt is not available in this implementation.
+func (engine *LibreOfficePdfEngine) Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error {
+	return fmt.Errorf("decrypt PDF with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ExtractText is not available in this implementation.
+func (engine *LibreOfficePdfEngine) ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+	return fmt.Errorf("extract text from PDF with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Ocr is not available in this implementation.
+func (engine *LibreOfficePdfEngine) Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error {
+	return fmt.Errorf("OCR PDF with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Normalize is not available in this implementation.
+func (engine *LibreOfficePdfEngine) Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+	return fmt.Errorf("normalize PDF with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SetOutline is not available in this implementation.
+func (engine *LibreOfficePdfEngine) SetOutline(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+	return fmt.Errorf("set PDF outline with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ReadOutline is not available in this implementation.
+func (engine *LibreOfficePdfEngine) ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+	return gotenberg.PdfOutline{}, fmt.Errorf("read PDF outline with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Redact is not available in this implementation.
+func (engine *LibreOfficePdfEngine) Redact(ctx context.Context, logger *zap.Logger, redactions []gotenberg.PdfRedaction, inputPath, outputPath string) error {
+	return fmt.Errorf("redact PDF with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// StampPageNumbers is not available in this implementation.
+func (engine *LibreOfficePdfEngine) StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+	return fmt.Errorf("stamp page numbers with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Crop is not available in this implementation.
+func (engine *LibreOfficePdfEngine) Crop(ctx context.Context, logger *zap.Logger, crop gotenberg.PdfCrop, inputPath, outputPath string) error {
+	return fmt.Errorf("crop PDF with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ImportImages is not available in this implementation.
+func (engine *LibreOfficePdfEngine) ImportImages(ctx context.Context, logger *zap.Logger, images []gotenberg.PdfImage, outputPath string) error {
+	return fmt.Errorf("import images with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Check is not available in this implementation.
+func (engine *LibreOfficePdfEngine) Check(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfCheckReport, error) {
+	return gotenberg.PdfCheckReport{}, fmt.Errorf("check PDF with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Repair is not available in this implementation.
+func (engine *LibreOfficePdfEngine) Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	return fmt.Errorf("repair PDF with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Rasterize is not available in this implementation.
+func (engine *LibreOfficePdfEngine) Rasterize(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("rasterize PDF with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SplitPages is not available in this implementation.
+func (engine *LibreOfficePdfEngine) SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("split PDF pages with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module      = (*LibreOfficePdfEngine)(nil)
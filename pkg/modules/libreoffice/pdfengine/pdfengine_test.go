@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -166,3 +167,129 @@ func TestLibreOfficePdfEngine_Convert(t *testing.T) {
 		})
 	}
 }
+
+func TestLibreOfficePdfEngine_Decrypt(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.Decrypt(context.Background(), zap.NewNop(), "", "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_ExtractText(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.ExtractText(context.Background(), zap.NewNop(), 0, 0, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_Ocr(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.Ocr(context.Background(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_Normalize(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.Normalize(context.Background(), zap.NewNop(), time.Time{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_SetOutline(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.SetOutline(context.TODO(), zap.NewNop(), nil, false, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_ReadOutline(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	_, err := engine.ReadOutline(context.TODO(), zap.NewNop(), "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_Redact(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.Redact(context.TODO(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_StampPageNumbers(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.StampPageNumbers(context.TODO(), zap.NewNop(), gotenberg.PdfPageNumbers{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_Crop(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.Crop(context.TODO(), zap.NewNop(), gotenberg.PdfCrop{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_ImportImages(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.ImportImages(context.TODO(), zap.NewNop(), nil, "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_Check(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	_, err := engine.Check(context.TODO(), zap.NewNop(), "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_Repair(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	err := engine.Repair(context.TODO(), zap.NewNop(), "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_Rasterize(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	_, err := engine.Rasterize(context.TODO(), zap.NewNop(), gotenberg.PdfRaster{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestLibreOfficePdfEngine_SplitPages(t *testing.T) {
+	engine := new(LibreOfficePdfEngine)
+	_, err := engine.SplitPages(context.TODO(), zap.NewNop(), "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
@@ -1,17 +1,311 @@
 package libreoffice
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/text/language"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
 )
 
+// probeEntry is the properties of a document, in the response of the probe
+// route.
+type probeEntry struct {
+	Filename string `json:"filename"`
+	libreofficeapi.DocumentProperties
+}
+
+// probeRoute returns an [api.Route] which returns, as JSON, the properties of
+// LibreOffice documents, e.g., their page count, without converting them.
+func probeRoute(libreOffice libreofficeapi.Uno) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/libreoffice/probe",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var inputPaths []string
+
+			err := ctx.FormData().
+				MandatoryPathsUnchecked(libreOffice.Extensions(), &inputPaths).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			entries := make([]probeEntry, len(inputPaths))
+			for i, inputPath := range inputPaths {
+				properties, err := libreOffice.Probe(ctx, ctx.Log(), inputPath)
+				if errors.Is(err, libreofficeapi.ErrCorruptDocument) {
+					return api.WrapError(
+						fmt.Errorf("probe document: %w", err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' is corrupt and cannot be opened", filepath.Base(inputPath))),
+					)
+				}
+				if errors.Is(err, libreofficeapi.ErrZipEntryTooLarge) {
+					return zipEntryTooLargeError(fmt.Errorf("probe document: %w", err), inputPath)
+				}
+				if err != nil {
+					return fmt.Errorf("probe document: %w", err)
+				}
+
+				entries[i] = probeEntry{
+					Filename:           filepath.Base(inputPath),
+					DocumentProperties: properties,
+				}
+			}
+
+			outputPath, err := writeJson(ctx, "properties.json", struct {
+				Files []probeEntry `json:"files"`
+			}{Files: entries})
+			if err != nil {
+				return fmt.Errorf("write properties: %w", err)
+			}
+
+			err = ctx.AddOutputPaths(outputPath)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// textRoute returns an [api.Route] which extracts the text of LibreOffice
+// documents, without going through PDF when LibreOffice is able to export
+// their text.
+func textRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/libreoffice/text",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths     []string
+				outputFormat   string
+				trustExtension bool
+			)
+
+			err := ctx.FormData().
+				MandatoryPathsUnchecked(libreOffice.Extensions(), &inputPaths).
+				Custom("outputFormat", func(value string) error {
+					if value == "" {
+						outputFormat = "json"
+						return nil
+					}
+
+					if value != "json" && value != "txt" {
+						return errors.New("wrong value, expected either 'json' or 'txt'")
+					}
+
+					outputFormat = value
+
+					return nil
+				}).
+				Bool("trustExtension", &trustExtension, false).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			importFilters := make(map[string]string)
+			if !trustExtension {
+				importFilters, err = detectImportFilters(ctx, inputPaths)
+				if err != nil {
+					return err
+				}
+			}
+
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
+			// Alright, let's extract the text of the documents.
+			textPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				textPaths[i] = ctx.GeneratePath(".txt")
+
+				err = extractText(ctx, libreOffice, engine, inputPath, importFilters[inputPath], textPaths[i])
+				if err != nil {
+					return loadError(fmt.Errorf("extract text: %w", err), inputPath)
+				}
+			}
+
+			if outputFormat == "txt" {
+				err = ctx.AddOutputPaths(textPaths...)
+				if err != nil {
+					return fmt.Errorf("add output paths: %w", err)
+				}
+
+				return nil
+			}
+
+			// The JSON output gathers the texts by filename.
+			texts := make(map[string]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				content, err := os.ReadFile(textPaths[i])
+				if err != nil {
+					return fmt.Errorf("read text file: %w", err)
+				}
+
+				texts[filepath.Base(inputPath)] = strings.TrimSpace(string(content))
+			}
+
+			b, err := json.Marshal(texts)
+			if err != nil {
+				return fmt.Errorf("marshal texts to JSON: %w", err)
+			}
+
+			outputPath := ctx.GeneratePath(".json")
+
+			err = os.WriteFile(outputPath, b, 0o600)
+			if err != nil {
+				return fmt.Errorf("write JSON file: %w", err)
+			}
+
+			err = ctx.AddOutputPaths(outputPath)
+			if err != nil {
+				return fmt.Errorf("add output path: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// extractText writes the UTF-8 text of a document to the output path. The
+// text documents go through the text export filter, and the spreadsheets
+// through the CSV one, with their sheets separated by a form feed character,
+// like the pages of a PDF. The other documents, e.g., the presentations, go
+// through PDF. The import filter, if any, overrides the one of the extension.
+func extractText(ctx *api.Context, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, inputPath, importFilter, outputPath string) error {
+	switch libreofficeapi.TextFormat(inputPath) {
+	case "txt":
+		err := libreOffice.Export(ctx, ctx.Log(), inputPath, outputPath, libreofficeapi.Options{
+			OutputFormat:        "txt",
+			ExportFilterOptions: "UTF8",
+			ImportFilter:        importFilter,
+		})
+		if err != nil {
+			return fmt.Errorf("convert to TXT: %w", err)
+		}
+
+		return nil
+	case "csv":
+		csvPath := ctx.GeneratePath(".csv")
+
+		err := libreOffice.Export(ctx, ctx.Log(), inputPath, csvPath, libreofficeapi.Options{
+			OutputFormat: "csv",
+			Csv: libreofficeapi.CsvOptions{
+				Sheet: libreofficeapi.CsvAllSheets,
+			},
+			ImportFilter: importFilter,
+		})
+		if err != nil {
+			return fmt.Errorf("convert to CSV: %w", err)
+		}
+
+		sheetPaths, err := libreofficeapi.CsvSheetPaths(csvPath)
+		if err != nil {
+			return fmt.Errorf("get sheet paths: %w", err)
+		}
+
+		sheets := make([]string, len(sheetPaths))
+		for i, sheetPath := range sheetPaths {
+			content, err := os.ReadFile(sheetPath)
+			if err != nil {
+				return fmt.Errorf("read sheet: %w", err)
+			}
+
+			sheets[i] = string(content)
+		}
+
+		err = os.WriteFile(outputPath, []byte(strings.Join(sheets, "\f")), 0o600)
+		if err != nil {
+			return fmt.Errorf("write text file: %w", err)
+		}
+
+		return nil
+	}
+
+	pdfPath := inputPath
+	if strings.ToLower(filepath.Ext(inputPath)) != ".pdf" {
+		pdfPath = ctx.GeneratePath(".pdf")
+
+		err := libreOffice.Pdf(ctx, ctx.Log(), inputPath, pdfPath, libreofficeapi.Options{ImportFilter: importFilter})
+		if err != nil {
+			return fmt.Errorf("convert to PDF: %w", err)
+		}
+	}
+
+	err := engine.ExtractText(ctx, ctx.Log(), 0, 0, pdfPath, outputPath)
+	if err != nil {
+		return fmt.Errorf("extract text from PDF: %w", err)
+	}
+
+	return nil
+}
+
+// detectImportFilters returns the import filters of the documents whose
+// content is another format than their extension tells, by input path, see
+// [libreofficeapi.DetectImportFilter]. It is the only content check of the
+// LibreOffice routes, which bind the documents without the generic one of
+// the form data.
+func detectImportFilters(ctx *api.Context, inputPaths []string) (map[string]string, error) {
+	importFilters := make(map[string]string)
+
+	for _, inputPath := range inputPaths {
+		filter, err := libreofficeapi.DetectImportFilter(inputPath)
+		if errors.Is(err, libreofficeapi.ErrFormatMismatch) {
+			return nil, api.WrapError(
+				fmt.Errorf("detect import filter: %w", err),
+				api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The content of the document '%s' does not match its extension (%s)", filepath.Base(inputPath), err)),
+			)
+		}
+		if err != nil {
+			// LibreOffice reports the unreadable documents.
+			continue
+		}
+
+		if filter != "" {
+			ctx.Log().Debug(fmt.Sprintf("'%s' is imported with the '%s' filter, according to its content", filepath.Base(inputPath), filter))
+			importFilters[inputPath] = filter
+		}
+	}
+
+	return importFilters, nil
+}
+
+// maxImageDpi is the maximum resolution of the images of the pages.
+const maxImageDpi = 1200
+
 // convertRoute returns an [api.Route] which can convert LibreOffice documents
 // to PDF.
 func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) api.Route {
@@ -24,126 +318,1035 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 
 			// Let's get the data from the form and validate them.
 			var (
-				inputPaths       []string
-				landscape        bool
-				nativePageRanges string
-				pdfa             string
-				pdfua            bool
-				nativePdfFormats bool
-				htmlFormat	   	 bool
-				merge            bool
-				importFilter     string
-				importOptions    string
+				inputPaths                      []string
+				landscape                       bool
+				nativePageRanges                string
+				pdfa                            string
+				pdfVersion                      string
+				pdfua                           bool
+				nativePdfFormats                bool
+				htmlFormat                      bool
+				htmlBundle                      string
+				outputFormat                    string
+				password                        string
+				passwords                       map[string]string
+				optionsJson                     string
+				filesOptions                    map[string]fileOptions
+				merge                           bool
+				mergeOutline                    bool
+				separatorPage                   bool
+				separatorPageTemplate           string
+				importFilter                    string
+				importOptions                   string
+				exportComments                  bool
+				exportNotesMode                 string
+				exportNotes                     bool
+				includeHiddenSlides             bool
+				reduceImageResolution           bool
+				maxImageResolution              int
+				jpegQuality                     int
+				losslessImageCompression        bool
+				userPassword                    string
+				ownerPassword                   string
+				allowPrinting                   bool
+				allowCopying                    bool
+				allowModifying                  bool
+				allowUnknownFilterData          bool
+				singlePage                      bool
+				exportFormFields                string
+				formsType                       string
+				exportBookmarks                 bool
+				initialView                     string
+				magnification                   string
+				pageLayout                      string
+				exportBookmarksToPdfDestination bool
+				exportLinksRelativeFsys         bool
+				updateIndexes                   bool
+				macroSecurity                   string
+				blockExternalLinks              bool
+				repairMode                      bool
+				trackedChanges                  string
+				locale                          string
+				timezone                        string
+				fitToWidth                      int
+				fitToHeight                     int
+				sheets                          []string
+				printArea                       string
+				imageFormat                     string
+				imageDpi                        int
+				splitPages                      bool
+				embeddedObjects                 bool
+				extractEmbeddedObjects          bool
+				filterData                      map[string]interface{}
+				exportFilterOptions             string
+				exportFilterData                map[string]interface{}
+				csv                             libreofficeapi.CsvOptions
+				concurrency                     int
+				continueOnError                 bool
+				trustExtension                  bool
 			)
 
-			err := ctx.FormData().
-				MandatoryPaths(libreOffice.Extensions(), &inputPaths).
+			// The remote documents are handled like the uploaded ones, as
+			// long as LibreOffice supports them.
+			for _, filename := range ctx.DownloadedFilenames() {
+				if !slices.Contains(libreOffice.Extensions(), strings.ToLower(filepath.Ext(filename))) {
+					return api.WrapError(
+						fmt.Errorf("downloaded file '%s' has an unsupported extension", filename),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'downloadFrom' form field value: the file '%s' has an unsupported extension", filename)),
+					)
+				}
+			}
+
+			form := ctx.FormData()
+			reproducible := api.FormDataReproducible(form)
+			pageNumbers := api.FormDataPageNumbers(form)
+			order := api.FormDataOrder(form)
+			fonts := api.FormDataFonts(form)
+
+			err := form.
+				MandatoryPathsUnchecked(libreOffice.Extensions(), &inputPaths).
+				Content("options.json", &optionsJson, "").
 				Bool("landscape", &landscape, false).
 				String("nativePageRanges", &nativePageRanges, "").
 				String("pdfa", &pdfa, "").
+				String("pdfVersion", &pdfVersion, "").
 				Bool("pdfua", &pdfua, false).
 				Bool("nativePdfFormats", &nativePdfFormats, true).
 				Bool("htmlFormat", &htmlFormat, false).
+				Custom("htmlBundle", namedValue(&htmlBundle, libreofficeapi.HtmlBundles)).
+				Custom("outputFormat", func(value string) error {
+					if value == "" {
+						outputFormat = "pdf"
+						return nil
+					}
+
+					err := libreofficeapi.ValidateOutputFormat(value)
+					if err != nil {
+						return fmt.Errorf("wrong value, expected either 'pdf', '%s' or empty", strings.Join(libreofficeapi.OutputFormats, "', '"))
+					}
+
+					outputFormat = value
+
+					return nil
+				}).
 				Bool("merge", &merge, false).
+				Bool("mergeOutline", &mergeOutline, false).
+				Bool("separatorPage", &separatorPage, false).
+				Bool("continueOnError", &continueOnError, false).
+				String("separatorPageTemplate", &separatorPageTemplate, "").
+				String("password", &password, "").
+				Custom("passwords", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					err := json.Unmarshal([]byte(value), &passwords)
+					if err != nil {
+						return fmt.Errorf("unmarshal passwords: %w", err)
+					}
+
+					return nil
+				}).
 				String("importFilter", &importFilter, "").
 				String("importOptions", &importOptions, "").
+				Bool("exportComments", &exportComments, false).
+				Custom("exportNotesMode", func(value string) error {
+					if value == "" {
+						exportNotesMode = "annotations"
+						return nil
+					}
+
+					if value != "annotations" && value != "margin" {
+						return errors.New("wrong value, expected either 'annotations', 'margin' or empty")
+					}
+
+					exportNotesMode = value
+
+					return nil
+				}).
+				Bool("exportNotes", &exportNotes, false).
+				Bool("includeHiddenSlides", &includeHiddenSlides, false).
+				Bool("reduceImageResolution", &reduceImageResolution, false).
+				Int("maxImageResolution", &maxImageResolution, 300).
+				Int("jpegQuality", &jpegQuality, 0).
+				Bool("losslessImageCompression", &losslessImageCompression, false).
+				String("userPassword", &userPassword, "").
+				String("ownerPassword", &ownerPassword, "").
+				Bool("allowPrinting", &allowPrinting, true).
+				Bool("allowCopying", &allowCopying, true).
+				Bool("allowModifying", &allowModifying, true).
+				Bool("allowUnknownFilterData", &allowUnknownFilterData, false).
+				Bool("singlePage", &singlePage, false).
+				Custom("exportFormFields", func(value string) error {
+					if value != "" && value != libreofficeapi.ExportFormFieldsInteractive && value != libreofficeapi.ExportFormFieldsFlattened {
+						return errors.New("wrong value, expected either 'interactive', 'flattened' or empty")
+					}
+
+					exportFormFields = value
+
+					return nil
+				}).
+				Bool("exportBookmarks", &exportBookmarks, true).
+				Custom("initialView", namedValue(&initialView, libreofficeapi.PdfInitialViews)).
+				Custom("magnification", namedValue(&magnification, libreofficeapi.PdfMagnifications)).
+				Custom("pageLayout", namedValue(&pageLayout, libreofficeapi.PdfPageLayouts)).
+				Custom("formsType", namedValue(&formsType, libreofficeapi.PdfFormsTypes)).
+				Bool("exportBookmarksToPdfDestination", &exportBookmarksToPdfDestination, false).
+				Bool("exportLinksRelativeFsys", &exportLinksRelativeFsys, false).
+				Bool("updateIndexes", &updateIndexes, false).
+				Custom("macroSecurity", namedValue(&macroSecurity, libreofficeapi.MacroSecurities)).
+				Bool("blockExternalLinks", &blockExternalLinks, true).
+				Bool("repairMode", &repairMode, false).
+				Custom("trackedChanges", namedValue(&trackedChanges, []string{libreofficeapi.TrackedChangesShow, libreofficeapi.TrackedChangesAccept, libreofficeapi.TrackedChangesReject})).
+				Custom("showTrackedChanges", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					// An alias of the trackedChanges form field: hiding the
+					// markup renders the document as if its tracked changes
+					// were accepted.
+					show, err := strconv.ParseBool(value)
+					if err != nil {
+						return err
+					}
+
+					mode := libreofficeapi.TrackedChangesAccept
+					if show {
+						mode = libreofficeapi.TrackedChangesShow
+					}
+
+					if trackedChanges != "" && trackedChanges != mode {
+						return fmt.Errorf("value contradicts the 'trackedChanges' form field '%s'", trackedChanges)
+					}
+
+					trackedChanges = mode
+
+					return nil
+				}).
+				Custom("locale", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					// The POSIX form, e.g., "de_DE", is accepted too.
+					tag, err := language.Parse(strings.ReplaceAll(value, "_", "-"))
+					if err != nil {
+						return errors.New("wrong value, expected a BCP 47 language tag, e.g., 'de-DE'")
+					}
+
+					locale = tag.String()
+
+					return nil
+				}).
+				Custom("timezone", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					// Note: "Local" is the timezone of the container, not an
+					// IANA name.
+					_, err := time.LoadLocation(value)
+					if err != nil || value == "Local" {
+						return errors.New("wrong value, expected an IANA timezone, e.g., 'Europe/Berlin'")
+					}
+
+					timezone = value
+
+					return nil
+				}).
+				Int("fitToWidth", &fitToWidth, 0).
+				Int("fitToHeight", &fitToHeight, 0).
+				Strings("sheets", &sheets).
+				Custom("printArea", func(value string) error {
+					err := libreofficeapi.ValidatePrintArea(value)
+					if err != nil {
+						return errors.New("wrong value, expected a range of cells like 'A1:H50' or empty")
+					}
+
+					printArea = value
+
+					return nil
+				}).
+				Custom("imageFormat", func(value string) error {
+					if value != "" && value != gotenberg.PdfRasterPng && value != gotenberg.PdfRasterJpeg {
+						return errors.New("wrong value, expected either 'png', 'jpeg' or empty")
+					}
+
+					imageFormat = value
+
+					return nil
+				}).
+				Custom("imageDpi", func(value string) error {
+					if value == "" {
+						imageDpi = 150
+						return nil
+					}
+
+					dpi, err := strconv.Atoi(value)
+					if err != nil {
+						return err
+					}
+
+					if dpi < 1 || dpi > maxImageDpi {
+						return fmt.Errorf("value is not within 1 and %d", maxImageDpi)
+					}
+
+					imageDpi = dpi
+
+					return nil
+				}).
+				Bool("splitPages", &splitPages, false).
+				Bool("embeddedObjects", &embeddedObjects, false).
+				Bool("extractEmbeddedObjects", &extractEmbeddedObjects, false).
+				Bool("trustExtension", &trustExtension, false).
+				Custom("exportFilterOptions", func(value string) error {
+					// A JSON object sets the properties of the export
+					// filter, any other value its options string.
+					if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+						exportFilterOptions = value
+						return nil
+					}
+
+					var err error
+					exportFilterData, err = libreofficeapi.ParseFilterData(value, true)
+					if err != nil {
+						return err
+					}
+
+					return nil
+				}).
+				Custom("csvDelimiter", func(value string) error {
+					// The tab is hard to send as a form field value.
+					if value == "tab" {
+						csv.Delimiter = '\t'
+						return nil
+					}
+
+					return csvCharacter(&csv.Delimiter)(value)
+				}).
+				Custom("csvQuote", csvCharacter(&csv.Quote)).
+				Custom("csvEncoding", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					if _, ok := libreofficeapi.CsvEncodings[strings.ToLower(value)]; !ok {
+						encodings := make([]string, 0, len(libreofficeapi.CsvEncodings))
+						for encoding := range libreofficeapi.CsvEncodings {
+							encodings = append(encodings, encoding)
+						}
+
+						slices.Sort(encodings)
+
+						return fmt.Errorf("wrong value, expected either '%s' or empty", strings.Join(encodings, "', '"))
+					}
+
+					csv.Encoding = value
+
+					return nil
+				}).
+				Bool("csvQuoteAll", &csv.QuoteAll, false).
+				Custom("csvSheet", func(value string) error {
+					switch value {
+					case "":
+						return nil
+					case "all":
+						csv.Sheet = libreofficeapi.CsvAllSheets
+						return nil
+					}
+
+					sheet, err := strconv.Atoi(value)
+					if err != nil || sheet < 1 {
+						return errors.New("wrong value, expected either 'all', a 1-based sheet index or empty")
+					}
+
+					csv.Sheet = sheet
+
+					return nil
+				}).
+				Custom("concurrency", func(value string) error {
+					maxConcurrency := unoConcurrency(libreOffice)
+					concurrency = maxConcurrency
+
+					if value == "" {
+						return nil
+					}
+
+					n, err := strconv.Atoi(value)
+					if err != nil || n < 1 {
+						return errors.New("wrong value, expected a positive integer or empty")
+					}
+
+					// The LibreOffice instances bound the concurrency.
+					concurrency = min(n, maxConcurrency)
+
+					return nil
+				}).
+				Custom("filterData", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					var err error
+					filterData, err = libreofficeapi.ParseFilterData(value, allowUnknownFilterData)
+					if err != nil {
+						return err
+					}
+
+					return nil
+				}).
 				Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
-			// Check for conflicts with HTML output flag.
-			if htmlFormat && merge && len(inputPaths) > 1 {
+			inputPaths, err = api.OrderPaths(ctx.InUploadOrder(inputPaths), *order)
+			if err != nil {
 				return api.WrapError(
-					errors.New("unable to merge multiple files with htmlFormat"),
-					api.NewSentinelHttpError(http.StatusBadRequest, "Unable to merge multiple files using htmlFormat"),
+					fmt.Errorf("order input paths: %w", err),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode("INVALID_FORM_DATA"),
 				)
 			}
 
-			// If HTML format is requested, and either native formats or PDFUA is requested, return an error.
-			if htmlFormat && (pdfa != "" || pdfua) {
-				return api.WrapError(
-					errors.New("got both 'htmlFormat' and 'nativePdfFormats' form fields"),
-					api.NewSentinelHttpError(http.StatusBadRequest, "Both 'htmlFormat' and 'nativePdfFormats' form fields are provided"),
-				)
+			if exportFilterData != nil {
+				if filterData != nil {
+					return api.WrapError(
+						errors.New("got both 'filterData' and 'exportFilterOptions' form fields"),
+						api.NewSentinelHttpError(http.StatusBadRequest, "Both 'filterData' and 'exportFilterOptions' form fields are provided as JSON"),
+					)
+				}
+
+				filterData = exportFilterData
+			}
+
+			filesOptions, err = parseFilesOptions(optionsJson)
+			if err != nil {
+				return api.WrapError(
+					fmt.Errorf("parse files options: %w", err),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s (options.json)", err)).WithCode("INVALID_FORM_DATA"),
+				)
+			}
+
+			// The passwords and the files options must target the documents
+			// of the request.
+			for filename := range passwords {
+				err = checkDocument(inputPaths, filename, "passwords")
+				if err != nil {
+					return err
+				}
+			}
+
+			for filename := range filesOptions {
+				err = checkDocument(inputPaths, filename, "options.json")
+				if err != nil {
+					return err
+				}
+			}
+
+			// The htmlFormat form field is a shortcut for the HTML output
+			// format.
+			formatField := "outputFormat"
+			if htmlFormat {
+				if outputFormat != "pdf" && outputFormat != "html" {
+					return api.WrapError(
+						errors.New("got both 'htmlFormat' and 'outputFormat' form fields"),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Both 'htmlFormat' and 'outputFormat' form fields are provided, with '%s' as output format", outputFormat)),
+					)
+				}
+
+				formatField = "htmlFormat"
+				outputFormat = "html"
+			}
+
+			pdfOutput := outputFormat == "pdf"
+
+			if htmlBundle != "" && outputFormat != "html" {
+				return api.WrapError(
+					errors.New("got 'htmlBundle' form field without HTML output"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "The 'htmlBundle' form field requires the HTML output format (htmlFormat or outputFormat)"),
+				)
+			}
+
+			if csv.IsSet() {
+				if outputFormat != "csv" {
+					return api.WrapError(
+						errors.New("got CSV form fields without CSV output"),
+						api.NewSentinelHttpError(http.StatusBadRequest, "The CSV form fields (csvDelimiter, csvQuote, csvEncoding, csvQuoteAll or csvSheet) require the 'csv' output format (outputFormat)"),
+					)
+				}
+
+				if exportFilterOptions != "" {
+					return api.WrapError(
+						errors.New("got both 'exportFilterOptions' and CSV form fields"),
+						api.NewSentinelHttpError(http.StatusBadRequest, "Both 'exportFilterOptions' and CSV form fields (csvDelimiter, csvQuote, csvEncoding, csvQuoteAll or csvSheet) are provided"),
+					)
+				}
+
+				err = libreofficeapi.ValidateCsvOptions(csv)
+				if err != nil {
+					return api.WrapError(
+						fmt.Errorf("validate CSV options: %w", err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid CSV form fields: %s", err)),
+					)
+				}
+			}
+
+			if separatorPage && !merge {
+				return api.WrapError(
+					errors.New("got 'separatorPage' form field without 'merge' form field"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "The 'separatorPage' form field requires the 'merge' form field"),
+				)
+			}
+
+			if separatorPageTemplate != "" && !separatorPage {
+				return api.WrapError(
+					errors.New("got 'separatorPageTemplate' form field without 'separatorPage' form field"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "The 'separatorPageTemplate' form field requires the 'separatorPage' form field"),
+				)
+			}
+
+			// When merging, the PDFs are merged as is, without going through
+			// LibreOffice.
+			passthrough := func(inputPath string) bool {
+				return merge && pdfOutput && strings.ToLower(filepath.Ext(inputPath)) == ".pdf"
+			}
+
+			// The images are rendered from the resulting PDFs.
+			if !pdfOutput && imageFormat != "" {
+				return api.WrapError(
+					fmt.Errorf("got both '%s' and 'imageFormat' form fields", formatField),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Both '%s' and 'imageFormat' form fields are provided", formatField)),
+				)
+			}
+
+			// The pages are split from the resulting PDFs, one PDF per
+			// document.
+			if splitPages {
+				var field string
+				switch {
+				case !pdfOutput:
+					field = formatField
+				case merge:
+					field = "merge"
+				case imageFormat != "":
+					field = "imageFormat"
+				}
+
+				if field != "" {
+					return api.WrapError(
+						fmt.Errorf("got both '%s' and 'splitPages' form fields", field),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Both '%s' and 'splitPages' form fields are provided", field)),
+					)
+				}
+			}
+
+			raster := gotenberg.PdfRaster{
+				Format: imageFormat,
+				Dpi:    imageDpi,
+			}
+
+			// Check for conflicts with other output formats than PDF.
+			if !pdfOutput && merge && len(inputPaths) > 1 {
+				return api.WrapError(
+					fmt.Errorf("unable to merge multiple files with %s", formatField),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Unable to merge multiple files using %s", formatField)),
+				)
+			}
+
+			// If another output format than PDF is requested, and either
+			// native formats or PDFUA is requested, return an error.
+			if !pdfOutput && (pdfa != "" || pdfua) {
+				return api.WrapError(
+					fmt.Errorf("got both '%s' and 'nativePdfFormats' form fields", formatField),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Both '%s' and 'nativePdfFormats' form fields are provided", formatField)),
+				)
+			}
+
+			// We cannot support page ranges in other formats than PDF.
+			pageRanges := nativePageRanges != ""
+			for _, options := range filesOptions {
+				pageRanges = pageRanges || options.hasPageRanges()
+			}
+
+			if !pdfOutput && pageRanges {
+				return api.WrapError(
+					fmt.Errorf("got both '%s' and 'nativePageRanges' form fields", formatField),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Both '%s' and 'nativePageRanges' form fields are provided", formatField)),
+				)
+			}
+
+			// A single page only makes sense in PDF format.
+			if !pdfOutput && singlePage {
+				return api.WrapError(
+					fmt.Errorf("got both '%s' and 'singlePage' form fields", formatField),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Both '%s' and 'singlePage' form fields are provided", formatField)),
+				)
+			}
+
+			// Rather than silently paginating, reject the documents which
+			// cannot be rendered on a single page.
+			if singlePage {
+				for _, inputPath := range inputPaths {
+					if !passthrough(inputPath) && !libreofficeapi.SupportsSinglePage(inputPath) {
+						return api.WrapError(
+							fmt.Errorf("single page for '%s': %w", filepath.Base(inputPath), libreofficeapi.ErrSinglePageNotSupported),
+							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' cannot be rendered on a single page; only spreadsheets can (singlePage)", filepath.Base(inputPath))),
+						)
+					}
+				}
+			}
+
+			if fitToWidth < 0 || fitToHeight < 0 {
+				return api.WrapError(
+					fmt.Errorf("negative fit to width %d or height %d", fitToWidth, fitToHeight),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: 'fitToWidth' and 'fitToHeight' must be positive, or zero for automatic").WithCode("INVALID_FORM_DATA"),
+				)
+			}
+
+			spreadsheet := libreofficeapi.SpreadsheetOptions{
+				FitToWidth:  fitToWidth,
+				FitToHeight: fitToHeight,
+				Sheets:      sheets,
+				PrintArea:   printArea,
+			}
+
+			// Like a single page, the print options only make sense for the
+			// spreadsheets exported to PDF.
+			if spreadsheet.IsSet() {
+				if !pdfOutput {
+					return api.WrapError(
+						fmt.Errorf("got both '%s' and spreadsheet form fields", formatField),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Both '%s' and spreadsheet form fields (fitToWidth, fitToHeight, sheets or printArea) are provided", formatField)),
+					)
+				}
+
+				if singlePage {
+					return api.WrapError(
+						errors.New("got both 'singlePage' and spreadsheet form fields"),
+						api.NewSentinelHttpError(http.StatusBadRequest, "Both 'singlePage' and spreadsheet form fields (fitToWidth, fitToHeight, sheets or printArea) are provided"),
+					)
+				}
+
+				for _, inputPath := range inputPaths {
+					if !passthrough(inputPath) && !libreofficeapi.SupportsSpreadsheetOptions(inputPath) {
+						return api.WrapError(
+							fmt.Errorf("spreadsheet options for '%s': %w", filepath.Base(inputPath), libreofficeapi.ErrInvalidSpreadsheetOptions),
+							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' does not support the spreadsheet form fields; only XLSX and ODS documents do (fitToWidth, fitToHeight, sheets or printArea)", filepath.Base(inputPath))),
+						)
+					}
+				}
+			}
+
+			// Rather than silently ignoring the tracked changes option, reject
+			// the documents it does not apply to.
+			if trackedChanges != "" {
+				for _, inputPath := range inputPaths {
+					if !passthrough(inputPath) && !libreofficeapi.SupportsTrackedChanges(inputPath) {
+						return api.WrapError(
+							fmt.Errorf("tracked changes for '%s': %w", filepath.Base(inputPath), libreofficeapi.ErrTrackedChangesNotSupported),
+							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' does not support the 'trackedChanges' nor 'showTrackedChanges' form fields; only the text documents do, e.g., DOCX, DOC, ODT or RTF", filepath.Base(inputPath))),
+						)
+					}
+				}
+			}
+
+			// The submit format only makes sense for interactive fields.
+			if formsType != "" && exportFormFields == libreofficeapi.ExportFormFieldsFlattened {
+				return api.WrapError(
+					errors.New("got both 'formsType' and flattened 'exportFormFields' form fields"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Both 'formsType' and 'exportFormFields' form fields are provided, with flattened form fields"),
+				)
+			}
+
+			// The permissions are enforced by the owner password.
+			if ownerPassword == "" && (!allowPrinting || !allowCopying || !allowModifying) {
+				return api.WrapError(
+					errors.New("got permissions form fields without 'ownerPassword' form field"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "The 'allowPrinting', 'allowCopying' and 'allowModifying' form fields require the 'ownerPassword' form field"),
+				)
+			}
+
+			// LibreOffice encrypts the PDFs, which may then neither be
+			// post-processed by the PDF engines nor conform to PDF/A.
+			if userPassword != "" || ownerPassword != "" {
+				var field string
+				switch {
+				case !pdfOutput:
+					field = formatField
+				case merge:
+					field = "merge"
+				case pdfa != "":
+					field = "pdfa"
+				case pdfua && !nativePdfFormats:
+					field = "pdfua"
+				case pageNumbers != nil:
+					field = "pageNumbers"
+				case reproducible != nil:
+					field = "reproducible"
+				case imageFormat != "":
+					field = "imageFormat"
+				case splitPages:
+					field = "splitPages"
+				}
+
+				if field != "" {
+					return api.WrapError(
+						fmt.Errorf("got both encryption and '%s' form fields", field),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Both 'userPassword' or 'ownerPassword' and '%s' form fields are provided", field)),
+					)
+				}
+			}
+
+			pdfFormats := gotenberg.PdfFormats{
+				PdfA:  pdfa,
+				PdfUa: pdfua,
+			}
+
+			err = api.ValidateReproducible(reproducible, pdfFormats)
+			if err != nil {
+				return err
+			}
+
+			// The PDF/A conformance level, whether native or not, dictates
+			// the PDF version.
+			err = libreofficeapi.ValidatePdfVersion(pdfVersion, gotenberg.PdfFormats{})
+			if err != nil {
+				return api.WrapError(
+					fmt.Errorf("validate PDF version: %w", err),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid PDF version '%s', expected either 1.5, 1.6, 1.7 or 2.0 (pdfVersion)", pdfVersion)),
+				)
+			}
+
+			err = libreofficeapi.ValidatePdfVersion(pdfVersion, pdfFormats)
+			if err != nil {
+				return api.WrapError(
+					fmt.Errorf("validate PDF version: %w", err),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The PDF version '%s' (pdfVersion) is not compatible with '%s' (pdfa)", pdfVersion, pdfa)),
+				)
+			}
+
+			// The content of the documents tells their actual format, which
+			// may be another one than their extension tells.
+			importFilters := make(map[string]string)
+			if !trustExtension {
+				importFilters, err = detectImportFilters(ctx, inputPaths)
+				if err != nil {
+					return err
+				}
+			}
+
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
+			// Alright, let's convert each document to PDF.
+			outputPaths := make([]string, len(inputPaths))
+			convert := func(convertCtx context.Context, i int, inputPath string) error {
+				var err error
+
+				if passthrough(inputPath) {
+					outputPaths[i], err = passthroughPdf(ctx, engine, nativePdfFormats, pdfFormats, inputPath)
+					if err != nil {
+						return fmt.Errorf("pass through PDF: %w", err)
+					}
+
+					return nil
+				}
+
+				outputExt := "." + outputFormat
+				if htmlBundle == libreofficeapi.HtmlBundleZip {
+					outputExt = ".zip"
+				}
+
+				if outputFormat == "csv" && csv.Delimiter == '\t' {
+					outputExt = ".tsv"
+				}
+
+				outputPaths[i] = ctx.GeneratePath(outputExt)
+
+				options := libreofficeapi.Options{
+					Landscape:                       landscape,
+					PageRanges:                      nativePageRanges,
+					Password:                        password,
+					ImportFilter:                    importFilter,
+					ImportOptions:                   importOptions,
+					ExportComments:                  exportComments,
+					ExportCommentsInMargin:          exportNotesMode == "margin",
+					ExportNotesPages:                exportNotes,
+					ExportHiddenSlides:              includeHiddenSlides,
+					ReduceImageResolution:           reduceImageResolution,
+					MaxImageResolution:              maxImageResolution,
+					JpegQuality:                     jpegQuality,
+					LosslessImageCompression:        losslessImageCompression,
+					UserPassword:                    userPassword,
+					OwnerPassword:                   ownerPassword,
+					AllowPrinting:                   allowPrinting,
+					AllowCopying:                    allowCopying,
+					AllowModifying:                  allowModifying,
+					PdfVersion:                      pdfVersion,
+					SinglePage:                      singlePage,
+					ExportFormFields:                exportFormFields,
+					FormsType:                       formsType,
+					SkipBookmarks:                   !exportBookmarks,
+					InitialView:                     initialView,
+					Magnification:                   magnification,
+					PageLayout:                      pageLayout,
+					ExportBookmarksToPdfDestination: exportBookmarksToPdfDestination,
+					ExportLinksRelativeFsys:         exportLinksRelativeFsys,
+					TrackedChanges:                  trackedChanges,
+					Fonts:                           fonts,
+					Locale:                          locale,
+					Timezone:                        timezone,
+					UpdateIndexes:                   updateIndexes,
+					MacroSecurity:                   macroSecurity,
+					AllowExternalLinks:              !blockExternalLinks,
+					RepairMode:                      repairMode,
+					Spreadsheet:                     spreadsheet,
+					FilterData:                      filterData,
+					ExportFilterOptions:             exportFilterOptions,
+					Csv:                             csv,
+				}
+
+				if outputFormat == "html" {
+					options.HTMLformat = true
+					options.HtmlBundle = htmlBundle
+				} else if !pdfOutput {
+					options.OutputFormat = outputFormat
+				}
+
+				if filePassword, ok := passwords[filepath.Base(inputPath)]; ok {
+					options.Password = filePassword
+				}
+
+				if documentOptions, ok := filesOptions[filepath.Base(inputPath)]; ok {
+					documentOptions.apply(&options)
+				}
+
+				// The import filter of the client wins over the detected
+				// one.
+				if options.ImportFilter == "" {
+					options.ImportFilter = importFilters[inputPath]
+				}
+
+				if nativePdfFormats {
+					options.PdfFormats = pdfFormats
+				}
+
+				if outputFormat == "html" {
+					err = libreOffice.Html(convertCtx, ctx.Log(), inputPath, outputPaths[i], options)
+					if err != nil {
+						return loadError(fmt.Errorf("convert to HTML: %w", err), inputPath)
+					}
+				} else if !pdfOutput {
+					err = libreOffice.Export(convertCtx, ctx.Log(), inputPath, outputPaths[i], options)
+					if err != nil {
+						return loadError(fmt.Errorf("convert to %s: %w", outputFormat, err), inputPath)
+					}
+				} else {
+					err = libreOffice.Pdf(convertCtx, ctx.Log(), inputPath, outputPaths[i], options)
+					if err != nil {
+						if errors.Is(err, libreofficeapi.ErrInvalidPdfFormats) {
+							return api.WrapError(
+								fmt.Errorf("convert to PDF: %w", err),
+								api.NewSentinelHttpError(
+									http.StatusBadRequest,
+									fmt.Sprintf("A PDF format in '%+v' is not supported", pdfFormats),
+								),
+							)
+						}
+
+						if errors.Is(err, libreofficeapi.ErrInvalidMaxImageResolution) {
+							return api.WrapError(
+								fmt.Errorf("convert to PDF: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid max image resolution '%d', expected either 75, 150, 300, 600 or 1200 (maxImageResolution)", options.MaxImageResolution)),
+							)
+						}
+
+						if errors.Is(err, libreofficeapi.ErrInvalidJpegQuality) {
+							return api.WrapError(
+								fmt.Errorf("convert to PDF: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid JPEG quality '%d', expected a value between 1 and 100 (jpegQuality)", options.JpegQuality)),
+							)
+						}
+
+						if errors.Is(err, libreofficeapi.ErrMalformedPageRanges) {
+							return api.WrapError(
+								fmt.Errorf("convert to PDF: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (nativePageRanges)", options.PageRanges)),
+							)
+						}
+
+						if errors.Is(err, libreofficeapi.ErrInvalidSpreadsheetOptions) {
+							return api.WrapError(
+								fmt.Errorf("convert to PDF: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid spreadsheet form fields for '%s': %s", filepath.Base(inputPath), err)),
+							)
+						}
+
+						if errors.Is(err, libreofficeapi.ErrTrackedChangesNotSupported) {
+							return api.WrapError(
+								fmt.Errorf("convert to PDF: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' does not support the 'trackedChanges' nor 'showTrackedChanges' form fields; only the text documents do, e.g., DOCX, DOC, ODT or RTF", filepath.Base(inputPath))),
+							)
+						}
+
+						if errors.Is(err, libreofficeapi.ErrSinglePageNotSupported) {
+							return api.WrapError(
+								fmt.Errorf("convert to PDF: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' cannot be rendered on a single page; only spreadsheets can (singlePage)", filepath.Base(inputPath))),
+							)
+						}
+
+						return loadError(fmt.Errorf("convert to PDF: %w", err), inputPath)
+					}
+				}
+
+				return nil
+			}
+
+			// With continueOnError, the conversion errors are reported in
+			// the manifest instead.
+			convertErrs := make([]error, len(inputPaths))
+
+			if concurrency < 2 || len(inputPaths) < 2 {
+				for i, inputPath := range inputPaths {
+					err = convert(ctx, i, inputPath)
+					if err != nil && !continueOnError {
+						return err
+					}
+
+					convertErrs[i] = err
+				}
+			} else {
+				// At most concurrency conversions at a time; once one
+				// fails, the others are cancelled.
+				eg, egCtx := errgroup.WithContext(ctx)
+				eg.SetLimit(concurrency)
+
+				for i, inputPath := range inputPaths {
+					i, inputPath := i, inputPath
+
+					eg.Go(func() error {
+						if egCtx.Err() != nil {
+							return nil
+						}
+
+						err := convert(egCtx, i, inputPath)
+						if err != nil && !continueOnError {
+							return err
+						}
+
+						convertErrs[i] = err
+
+						return nil
+					})
+				}
+
+				err = eg.Wait()
+				if err != nil {
+					return err
+				}
 			}
 
-			// We cannot support page ranges in HTML format.
-			if htmlFormat && nativePageRanges != "" {
-				return api.WrapError(
-					errors.New("got both 'htmlFormat' and 'nativePageRanges' form fields"),
-					api.NewSentinelHttpError(http.StatusBadRequest, "Both 'htmlFormat' and 'nativePageRanges' form fields are provided"),
-				)
-			}
+			if continueOnError {
+				manifestPath, err := api.WriteManifest(ctx, inputPaths, convertErrs)
+				if err != nil {
+					return fmt.Errorf("write manifest: %w", err)
+				}
 
-			pdfFormats := gotenberg.PdfFormats{
-				PdfA:  pdfa,
-				PdfUa: pdfua,
-			}
+				var convertedInputPaths, convertedOutputPaths []string
+				for i, convertErr := range convertErrs {
+					if convertErr != nil {
+						ctx.Log().Warn(fmt.Sprintf("skip '%s': %s", filepath.Base(inputPaths[i]), convertErr))
+						continue
+					}
 
-			// Alright, let's convert each document to PDF.
-			outputPaths := make([]string, len(inputPaths))
-			for i, inputPath := range inputPaths {
-				if htmlFormat {
-					outputPaths[i] = ctx.GeneratePath(".html")
-				} else {
-					outputPaths[i] = ctx.GeneratePath(".pdf")
+					convertedInputPaths = append(convertedInputPaths, inputPaths[i])
+					convertedOutputPaths = append(convertedOutputPaths, outputPaths[i])
 				}
 
-				options := libreofficeapi.Options{
-					Landscape:  landscape,
-					PageRanges: nativePageRanges,
-					ImportFilter:  importFilter,
-					ImportOptions: importOptions,
+				// Nothing to return but the errors.
+				if len(convertedOutputPaths) == 0 {
+					return convertErrs[0]
 				}
 
-				if htmlFormat {
-					options.HTMLformat = htmlFormat
+				// Important: from now on, only the converted documents
+				// remain.
+				inputPaths = convertedInputPaths
+				outputPaths = convertedOutputPaths
+
+				err = ctx.AddOutputPaths(manifestPath)
+				if err != nil {
+					return fmt.Errorf("add manifest path: %w", err)
 				}
+			}
 
-				if nativePdfFormats {
-					options.PdfFormats = pdfFormats
+			// The embedded objects do not survive the conversion, so they
+			// are reported, or extracted, alongside the resulting files.
+			if embeddedObjects || extractEmbeddedObjects {
+				embeddedPaths, err := writeEmbeddedObjects(ctx, inputPaths, extractEmbeddedObjects)
+				if err != nil {
+					return fmt.Errorf("write embedded objects: %w", err)
 				}
 
-				if htmlFormat {
-					err = libreOffice.Html(ctx, ctx.Log(), inputPath, outputPaths[i], options)
-					if err != nil {
-						return fmt.Errorf("convert to HTML: %w", err)
-					}
-				} else {
-					err = libreOffice.Pdf(ctx, ctx.Log(), inputPath, outputPaths[i], options)
-					if err != nil {
-						if errors.Is(err, libreofficeapi.ErrInvalidPdfFormats) {
-							return api.WrapError(
-								fmt.Errorf("convert to PDF: %w", err),
-								api.NewSentinelHttpError(
-									http.StatusBadRequest,
-									fmt.Sprintf("A PDF format in '%+v' is not supported", pdfFormats),
-								),
-							)
-						}
-	
-						if errors.Is(err, libreofficeapi.ErrMalformedPageRanges) {
-							return api.WrapError(
-								fmt.Errorf("convert to PDF: %w", err),
-								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (nativePageRanges)", options.PageRanges)),
-							)
-						}
-	
-						return fmt.Errorf("convert to PDF: %w", err)
-					}
+				err = ctx.AddOutputPaths(embeddedPaths...)
+				if err != nil {
+					return fmt.Errorf("add embedded objects paths: %w", err)
 				}
 			}
 
 			// So far so good, let's check if we have to merge the PDFs. Quick
-			// win: if doing HTML, or if there is only one PDF, skip this step.
-			if !htmlFormat {
+			// win: if not doing PDF, or if there is only one PDF, skip this
+			// step.
+			if pdfOutput {
 				if len(outputPaths) > 1 && merge {
-					outputPath := ctx.GeneratePath(".pdf")
+					var outputPath string
 
-					err = engine.Merge(ctx, ctx.Log(), outputPaths, outputPath)
-					if err != nil {
-						return fmt.Errorf("merge PDFs: %w", err)
+					// Every document but the first starts with its
+					// separator page, which its outline entry targets.
+					if separatorPage {
+						separatorOptions := libreofficeapi.Options{}
+						if nativePdfFormats {
+							separatorOptions.PdfFormats = pdfFormats
+						}
+
+						for i := 1; i < len(outputPaths); i++ {
+							outputPaths[i], err = prependSeparatorPage(ctx, libreOffice, engine, separatorOptions, separatorPageTemplate, inputPaths[i], i+1, len(inputPaths), outputPaths[i])
+							if err != nil {
+								return fmt.Errorf("prepend separator page: %w", err)
+							}
+						}
+					}
+
+					if mergeOutline {
+						titles := make([]string, len(inputPaths))
+						for i, inputPath := range inputPaths {
+							titles[i] = filepath.Base(inputPath)
+						}
+
+						outputPath, err = api.MergePdfsWithOutline(ctx, engine, outputPaths, titles)
+						if err != nil {
+							return fmt.Errorf("merge PDFs with outline: %w", err)
+						}
+					} else {
+						outputPath = ctx.GeneratePath(".pdf")
+
+						err = engine.Merge(ctx, ctx.Log(), outputPaths, outputPath)
+						if err != nil {
+							return fmt.Errorf("merge PDFs: %w", err)
+						}
+					}
+
+					if pageNumbers != nil {
+						stampOutputPaths, err := api.StampPageNumbers(ctx, engine, *pageNumbers, outputPath)
+						if err != nil {
+							return fmt.Errorf("stamp page numbers: %w", err)
+						}
+
+						// Important: the output path is now the numbered file.
+						outputPath = stampOutputPaths[0]
 					}
 
 					// Now, let's check if the client want to convert this result
@@ -172,6 +1375,33 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 						outputPath = convertOutputPath
 					}
 
+					if reproducible != nil {
+						normalizeOutputPaths, err := api.NormalizePdfs(ctx, engine, *reproducible, outputPath)
+						if err != nil {
+							return fmt.Errorf("normalize PDF: %w", err)
+						}
+
+						// Important: the output path is now the normalized file.
+						outputPath = normalizeOutputPaths[0]
+					}
+
+					if imageFormat != "" {
+						imagePaths, err := rasterizePdfs(ctx, engine, raster, outputPath)
+						if err != nil {
+							return fmt.Errorf("rasterize PDF: %w", err)
+						}
+
+						// Last but not least, add the images to the context
+						// so that the Uno is able to send them as a response
+						// to the client.
+						err = ctx.AddOutputPaths(imagePaths...)
+						if err != nil {
+							return fmt.Errorf("add output paths: %w", err)
+						}
+
+						return nil
+					}
+
 					// Last but not least, add the output path to the context so that
 					// the Uno is able to send it as a response to the client.
 
@@ -183,8 +1413,17 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 					return nil
 				}
 
-				// Ok, we don't have to merge the PDFs. Let's check if the client
-				// want to convert each PDF to a specific PDF format.
+				// Ok, we don't have to merge the PDFs. Each PDF has its own page
+				// numbers.
+				if pageNumbers != nil {
+					outputPaths, err = api.StampPageNumbers(ctx, engine, *pageNumbers, outputPaths...)
+					if err != nil {
+						return fmt.Errorf("stamp page numbers: %w", err)
+					}
+				}
+
+				// Let's check if the client want to convert each PDF to a
+				// specific PDF format.
 				zeroValued := gotenberg.PdfFormats{}
 				if !nativePdfFormats && pdfFormats != zeroValued {
 					convertOutputPaths := make([]string, len(outputPaths))
@@ -213,6 +1452,47 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 					// Important: the output paths are now the converted files.
 					outputPaths = convertOutputPaths
 				}
+
+				if reproducible != nil {
+					outputPaths, err = api.NormalizePdfs(ctx, engine, *reproducible, outputPaths...)
+					if err != nil {
+						return fmt.Errorf("normalize PDFs: %w", err)
+					}
+				}
+
+				if imageFormat != "" {
+					outputPaths, err = rasterizePdfs(ctx, engine, raster, outputPaths...)
+					if err != nil {
+						return fmt.Errorf("rasterize PDFs: %w", err)
+					}
+				}
+
+				if splitPages {
+					outputPaths, err = splitPdfs(ctx, engine, inputPaths, outputPaths)
+					if err != nil {
+						return fmt.Errorf("split PDFs: %w", err)
+					}
+				}
+			}
+
+			// Each sheet has been exported to its own CSV file.
+			if csv.Sheet == libreofficeapi.CsvAllSheets {
+				var sheetPaths []string
+
+				for _, outputPath := range outputPaths {
+					paths, err := libreofficeapi.CsvSheetPaths(outputPath)
+					if err != nil {
+						return fmt.Errorf("get CSV sheet paths: %w", err)
+					}
+
+					if len(paths) == 0 {
+						paths = []string{outputPath}
+					}
+
+					sheetPaths = append(sheetPaths, paths...)
+				}
+
+				outputPaths = sheetPaths
 			}
 
 			// Last but not least, add the output paths to the context so that
@@ -227,3 +1507,476 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 		},
 	}
 }
+
+// fileOptions are the options of a document which override the ones of the
+// request, thanks to the "options.json" form file keyed by filename.
+type fileOptions struct {
+	Landscape        *bool   `json:"landscape"`
+	NativePageRanges *string `json:"nativePageRanges"`
+	ImportFilter     *string `json:"importFilter"`
+	Password         *string `json:"password"`
+}
+
+// hasPageRanges tells if the options select the pages to convert.
+func (o fileOptions) hasPageRanges() bool {
+	return o.NativePageRanges != nil && *o.NativePageRanges != ""
+}
+
+// apply overrides the given conversion options with the set ones.
+func (o fileOptions) apply(options *libreofficeapi.Options) {
+	if o.Landscape != nil {
+		options.Landscape = *o.Landscape
+	}
+
+	if o.NativePageRanges != nil {
+		options.PageRanges = *o.NativePageRanges
+	}
+
+	if o.ImportFilter != nil {
+		options.ImportFilter = *o.ImportFilter
+	}
+
+	if o.Password != nil {
+		options.Password = *o.Password
+	}
+}
+
+// parseFilesOptions parses the content of the "options.json" form file. The
+// unknown options are rejected, so that a typo does not go unnoticed.
+func parseFilesOptions(content string) (map[string]fileOptions, error) {
+	if content == "" {
+		return nil, nil
+	}
+
+	var filesOptions map[string]fileOptions
+
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(&filesOptions)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal options: %w", err)
+	}
+
+	return filesOptions, nil
+}
+
+// checkDocument returns an HTTP error if no input path has the given
+// filename.
+func checkDocument(inputPaths []string, filename, field string) error {
+	if slices.ContainsFunc(inputPaths, func(inputPath string) bool {
+		return filepath.Base(inputPath) == filename
+	}) {
+		return nil
+	}
+
+	return api.WrapError(
+		fmt.Errorf("%s for unknown document '%s'", field, filename),
+		api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: the document '%s' does not exist (%s)", filename, field)),
+	)
+}
+
+// embeddedObjectsEntry is the objects embedded in a document, in the
+// response of the "embeddedObjects" form field.
+type embeddedObjectsEntry struct {
+	Filename string                `json:"filename"`
+	Objects  []embeddedObjectEntry `json:"objects"`
+}
+
+// embeddedObjectEntry is an object embedded in a document, with the name of
+// its file if extracted.
+type embeddedObjectEntry struct {
+	libreofficeapi.EmbeddedObject
+	ExtractedAs string `json:"extractedAs,omitempty"`
+}
+
+// writeEmbeddedObjects writes, as "embedded-objects.json", the objects
+// embedded in each document, in order, and returns its path. If extract is
+// set, the objects are also written to their own files, named after their
+// document, e.g., "report-oleObject1.bin", whose paths follow.
+func writeEmbeddedObjects(ctx *api.Context, inputPaths []string, extract bool) ([]string, error) {
+	var dirPath string
+	var extractedPaths []string
+
+	if extract {
+		dirPath = ctx.GeneratePath("")
+
+		err := os.MkdirAll(dirPath, 0o755)
+		if err != nil {
+			return nil, fmt.Errorf("create directory: %w", err)
+		}
+	}
+
+	names := api.DocumentNames(inputPaths)
+	entries := make([]embeddedObjectsEntry, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		objects, err := libreofficeapi.EmbeddedObjects(inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("get embedded objects of '%s': %w", filepath.Base(inputPath), err)
+		}
+
+		entries[i] = embeddedObjectsEntry{
+			Filename: filepath.Base(inputPath),
+			Objects:  make([]embeddedObjectEntry, len(objects)),
+		}
+
+		for j, object := range objects {
+			entries[i].Objects[j].EmbeddedObject = object
+
+			if !extract {
+				continue
+			}
+
+			filename := fmt.Sprintf("%s-%s", names[i], object.Filename())
+			outputPath := filepath.Join(dirPath, filename)
+
+			err = libreofficeapi.ExtractEmbeddedObject(inputPath, object, outputPath)
+			if errors.Is(err, libreofficeapi.ErrZipEntryTooLarge) {
+				return nil, zipEntryTooLargeError(fmt.Errorf("extract '%s' of '%s': %w", object.Name, filepath.Base(inputPath), err), inputPath)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("extract '%s' of '%s': %w", object.Name, filepath.Base(inputPath), err)
+			}
+
+			entries[i].Objects[j].ExtractedAs = filename
+			extractedPaths = append(extractedPaths, outputPath)
+		}
+	}
+
+	jsonPath, err := writeJson(ctx, "embedded-objects.json", struct {
+		Files []embeddedObjectsEntry `json:"files"`
+	}{Files: entries})
+	if err != nil {
+		return nil, fmt.Errorf("write embedded objects: %w", err)
+	}
+
+	return append([]string{jsonPath}, extractedPaths...), nil
+}
+
+// writeJson writes a value as a JSON file with the given name, e.g., so that
+// it keeps its name in an archive, and returns its path.
+func writeJson(ctx *api.Context, filename string, value interface{}) (string, error) {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal JSON: %w", err)
+	}
+
+	dirPath := ctx.GeneratePath("")
+
+	err = os.MkdirAll(dirPath, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("create directory: %w", err)
+	}
+
+	path := filepath.Join(dirPath, filename)
+
+	err = os.WriteFile(path, content, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write JSON: %w", err)
+	}
+
+	return path, nil
+}
+
+// unoConcurrency returns the number of conversions the [libreofficeapi.Uno]
+// handles at a time.
+func unoConcurrency(libreOffice libreofficeapi.Uno) int {
+	concurrent, ok := libreOffice.(libreofficeapi.ConcurrentUno)
+	if !ok {
+		return 1
+	}
+
+	return max(concurrent.Concurrency(), 1)
+}
+
+// csvCharacter returns a form field validator which binds a single
+// character, or nothing, to target.
+func csvCharacter(target *rune) func(value string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+
+		if utf8.RuneCountInString(value) != 1 {
+			return errors.New("wrong value, expected a single character or empty")
+		}
+
+		*target, _ = utf8.DecodeRuneInString(value)
+
+		return nil
+	}
+}
+
+// namedValue returns a form field validator which binds one of the given
+// values, or an empty string, to target.
+func namedValue(target *string, values []string) func(value string) error {
+	return func(value string) error {
+		if value != "" && !slices.Contains(values, value) {
+			return fmt.Errorf("wrong value, expected either '%s' or empty", strings.Join(values, "', '"))
+		}
+
+		*target = value
+
+		return nil
+	}
+}
+
+// rasterizePdfs renders each page of the given PDFs as an image, and returns
+// the images in the order of the PDFs and of their pages.
+func rasterizePdfs(ctx *api.Context, engine gotenberg.PdfEngine, raster gotenberg.PdfRaster, inputPaths ...string) ([]string, error) {
+	var outputPaths []string
+
+	for _, inputPath := range inputPaths {
+		imagePaths, err := engine.Rasterize(ctx, ctx.Log(), raster, inputPath, ctx.GeneratePath(""))
+		if err != nil {
+			return nil, err
+		}
+
+		outputPaths = append(outputPaths, imagePaths...)
+	}
+
+	return outputPaths, nil
+}
+
+// splitPdfs writes each page of the given PDFs to its own PDF, and returns
+// the pages in the order of the PDFs and of their pages. The pages are named
+// after their document and zero-padded page number, e.g., "report-001.pdf"
+// for the first page of "report.docx".
+func splitPdfs(ctx *api.Context, engine gotenberg.PdfEngine, inputPaths, pdfPaths []string) ([]string, error) {
+	dirPath := ctx.GeneratePath("")
+
+	err := os.MkdirAll(dirPath, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+
+	var outputPaths []string
+	names := api.DocumentNames(inputPaths)
+
+	for i, pdfPath := range pdfPaths {
+		pagePaths, err := engine.SplitPages(ctx, ctx.Log(), pdfPath, ctx.GeneratePath(""))
+		if err != nil {
+			return nil, fmt.Errorf("split '%s': %w", filepath.Base(inputPaths[i]), err)
+		}
+
+		width := len(strconv.Itoa(len(pagePaths)))
+		if width < 3 {
+			width = 3
+		}
+
+		for page, pagePath := range pagePaths {
+			outputPath := filepath.Join(dirPath, fmt.Sprintf("%s-%0*d.pdf", names[i], width, page+1))
+
+			err = os.Rename(pagePath, outputPath)
+			if err != nil {
+				return nil, fmt.Errorf("rename page %d of '%s': %w", page+1, filepath.Base(inputPaths[i]), err)
+			}
+
+			outputPaths = append(outputPaths, outputPath)
+		}
+	}
+
+	return outputPaths, nil
+}
+
+// defaultSeparatorPageTemplate is the template of the separator pages if the
+// client does not provide one.
+const defaultSeparatorPageTemplate = "{filename}\nDocument {index} of {total}"
+
+// separatorPageHtml returns the HTML of the separator page of a document.
+// Each line of the template is a centered paragraph, where the {filename},
+// {index} and {total} placeholders are replaced. The template is plain text,
+// so that it cannot reference any resource.
+func separatorPageHtml(template, filename string, index, total int) string {
+	if template == "" {
+		template = defaultSeparatorPageTemplate
+	}
+
+	replacer := strings.NewReplacer(
+		"{filename}", filename,
+		"{index}", strconv.Itoa(index),
+		"{total}", strconv.Itoa(total),
+	)
+
+	var b strings.Builder
+	b.WriteString(`<!DOCTYPE html><html><head><meta charset="utf-8"></head><body>`)
+
+	for _, line := range strings.Split(strings.ReplaceAll(template, "\r\n", "\n"), "\n") {
+		b.WriteString(`<p style="text-align: center; font-size: 18pt">`)
+		b.WriteString(html.EscapeString(replacer.Replace(line)))
+		b.WriteString(`</p>`)
+	}
+
+	b.WriteString(`</body></html>`)
+
+	return b.String()
+}
+
+// prependSeparatorPage converts the separator page of a document to PDF,
+// thanks to LibreOffice, and merges it before the PDF of the document. It
+// returns the path of the resulting PDF.
+func prependSeparatorPage(ctx *api.Context, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, options libreofficeapi.Options, template, inputPath string, index, total int, pdfPath string) (string, error) {
+	htmlPath := ctx.GeneratePath(".html")
+
+	err := os.WriteFile(htmlPath, []byte(separatorPageHtml(template, filepath.Base(inputPath), index, total)), 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write separator page: %w", err)
+	}
+
+	separatorPath := ctx.GeneratePath(".pdf")
+
+	err = libreOffice.Pdf(ctx, ctx.Log(), htmlPath, separatorPath, options)
+	if err != nil {
+		return "", fmt.Errorf("convert separator page to PDF: %w", err)
+	}
+
+	outputPath := ctx.GeneratePath(".pdf")
+
+	err = engine.Merge(ctx, ctx.Log(), []string{separatorPath, pdfPath}, outputPath)
+	if err != nil {
+		return "", fmt.Errorf("merge separator page: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// loadError wraps a conversion error with an HTTP error if LibreOffice
+// cannot load the document, i.e., the password does not open it or it is
+// corrupt.
+func loadError(err error, inputPath string) error {
+	if errors.Is(err, libreofficeapi.ErrInvalidPassword) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The password does not open the document '%s' (password or passwords)", filepath.Base(inputPath))),
+		)
+	}
+
+	if errors.Is(err, libreofficeapi.ErrCorruptDocument) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' is corrupt and cannot be opened (repairMode may help)", filepath.Base(inputPath))),
+		)
+	}
+
+	if errors.Is(err, libreofficeapi.ErrZipEntryTooLarge) {
+		return zipEntryTooLargeError(err, inputPath)
+	}
+
+	return err
+}
+
+// zipEntryTooLargeError wraps an error with an HTTP error if an entry of the
+// document is too large to be read, e.g., a ZIP bomb.
+func zipEntryTooLargeError(err error, inputPath string) error {
+	return api.WrapError(
+		err,
+		api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The document '%s' has an entry which is too large once uncompressed", filepath.Base(inputPath))),
+	)
+}
+
+// passthroughPdf returns the path of a PDF to merge as is. If the PDF
+// formats are native, i.e., the other documents already comply with them,
+// the PDF is converted too, unless it already complies with the requested
+// PDF/A conformance level.
+func passthroughPdf(ctx *api.Context, engine gotenberg.PdfEngine, nativePdfFormats bool, pdfFormats gotenberg.PdfFormats, inputPath string) (string, error) {
+	zeroValued := gotenberg.PdfFormats{}
+	if !nativePdfFormats || pdfFormats == zeroValued {
+		return inputPath, nil
+	}
+
+	if !pdfFormats.PdfUa {
+		conformance, err := pdfaConformance(inputPath)
+		if err != nil {
+			return "", fmt.Errorf("read PDF/A conformance level: %w", err)
+		}
+
+		if strings.EqualFold(conformance, pdfFormats.PdfA) {
+			ctx.Log().Debug(fmt.Sprintf("'%s' is already %s, skip conversion", filepath.Base(inputPath), conformance))
+			return inputPath, nil
+		}
+	}
+
+	outputPath := ctx.GeneratePath(".pdf")
+
+	err := engine.Convert(ctx, ctx.Log(), pdfFormats, inputPath, outputPath)
+	if err != nil {
+		if errors.Is(err, gotenberg.ErrPdfFormatNotSupported) {
+			return "", api.WrapError(
+				fmt.Errorf("convert PDF: %w", err),
+				api.NewSentinelHttpError(
+					http.StatusBadRequest,
+					fmt.Sprintf("At least one PDF engine does not handle one of the PDF format in '%+v', while other have failed to convert for other reasons", pdfFormats),
+				),
+			)
+		}
+
+		return "", fmt.Errorf("convert PDF: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// pdfaIdentificationRegexp matches the PDF/A identification schema of the
+// XMP metadata, either as attributes or as elements.
+var pdfaIdentificationRegexp = regexp.MustCompile(`pdfaid:(part|conformance)\s*(?:=\s*["']|>)\s*([0-9A-Za-z])`)
+
+// pdfaConformance returns the PDF/A conformance level of a PDF (e.g.,
+// "PDF/A-2b"), or an empty string if the PDF does not claim any. PDF/A
+// forbids the compression of the XMP metadata, so reading the raw bytes is
+// enough.
+func pdfaConformance(inputPath string) (string, error) {
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return "", fmt.Errorf("open PDF: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	const (
+		chunkSize = 64 * 1024
+		overlap   = 256
+	)
+
+	var (
+		part        string
+		conformance string
+		window      []byte
+	)
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			window = append(window, buf[:n]...)
+
+			for _, match := range pdfaIdentificationRegexp.FindAllSubmatch(window, -1) {
+				switch string(match[1]) {
+				case "part":
+					part = string(match[2])
+				case "conformance":
+					conformance = strings.ToLower(string(match[2]))
+				}
+			}
+
+			if part != "" && conformance != "" {
+				return fmt.Sprintf("PDF/A-%s%s", part, conformance), nil
+			}
+
+			// Keep the end of the window, in case a match spans two chunks.
+			if len(window) > overlap {
+				window = append(window[:0], window[len(window)-overlap:]...)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return "", nil
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("read PDF: %w", err)
+		}
+	}
+}
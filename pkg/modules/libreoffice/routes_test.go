@@ -1,13 +1,26 @@
 package libreoffice
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/binary"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf16"
 
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
+	"golang.org/x/image/font/gofont/goregular"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
@@ -15,6 +28,47 @@ import (
 )
 
 func TestConvertRoute(t *testing.T) {
+	optionsDirPath := t.TempDir()
+
+	// writeOptions writes an "options.json" form file and returns its path.
+	writeOptions := func(content string) string {
+		dirPath, err := os.MkdirTemp(optionsDirPath, "")
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		path := filepath.Join(dirPath, "options.json")
+
+		err = os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return path
+	}
+
+	fontPath := filepath.Join(optionsDirPath, "font.ttf")
+	err := os.WriteFile(fontPath, goregular.TTF, 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	// A password-protected DOCX document is a Compound File Binary
+	// container with the encrypted ZIP archive.
+	protectedDocxPath := filepath.Join(optionsDirPath, "protected.docx")
+	writeCfb(t, protectedDocxPath, []string{"\x06DataSpaces", "EncryptionInfo", "EncryptedPackage"})
+
+	// The other documents of the same kind are imported according to their
+	// content, whatever their extension.
+	legacyDocxPath := filepath.Join(optionsDirPath, "legacy.docx")
+	writeCfb(t, legacyDocxPath, []string{"\x01CompObj", "WordDocument", "1Table"})
+
+	rtfDocxPath := filepath.Join(optionsDirPath, "letter.docx")
+	err = os.WriteFile(rtfDocxPath, []byte(`{\rtf1\ansi Hello}`), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
 	for _, tc := range []struct {
 		scenario               string
 		ctx                    *api.ContextMock
@@ -37,6 +91,47 @@ func TestConvertRoute(t *testing.T) {
 			expectHttpStatus:       http.StatusBadRequest,
 			expectOutputPathsCount: 0,
 		},
+		{
+			scenario: "downloaded file with an unsupported extension",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+					"script.sh":     "/script.sh",
+				})
+				ctx.SetDownloadedFilenames([]string{"script.sh"})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".docx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "validate only",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValidateOnly(true)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return errors.New("foo")
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
 		{
 			scenario: "ErrPdfFormatNotSupported (nativePdfFormats)",
 			ctx: func() *api.ContextMock {
@@ -59,6 +154,57 @@ func TestConvertRoute(t *testing.T) {
 			expectHttpStatus:       http.StatusBadRequest,
 			expectOutputPathsCount: 0,
 		},
+		{
+			scenario: "singlePage with a document which is not a spreadsheet",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"singlePage": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "htmlFormat and singlePage",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"htmlFormat": {
+						"true",
+					},
+					"singlePage": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
 		{
 			scenario: "ErrMalformedPageRanges",
 			ctx: func() *api.ContextMock {
@@ -86,6 +232,63 @@ func TestConvertRoute(t *testing.T) {
 			expectHttpStatus:       http.StatusBadRequest,
 			expectOutputPathsCount: 0,
 		},
+		{
+			scenario: "ErrInvalidMaxImageResolution",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"reduceImageResolution": {
+						"true",
+					},
+					"maxImageResolution": {
+						"42",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return libreofficeapi.ErrInvalidMaxImageResolution
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrInvalidJpegQuality",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"jpegQuality": {
+						"101",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return libreofficeapi.ErrInvalidJpegQuality
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
 		{
 			scenario: "error from LibreOffice",
 			ctx: func() *api.ContextMock {
@@ -220,17 +423,34 @@ func TestConvertRoute(t *testing.T) {
 			expectOutputPathsCount: 1,
 		},
 		{
-			scenario: "success (many files)",
+			scenario: "success (passwords)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
 					"document.docx":  "/document.docx",
 					"document2.docx": "/document2.docx",
 				})
+				ctx.SetValues(map[string][]string{
+					"password": {
+						"foo",
+					},
+					"passwords": {
+						`{"document2.docx":"bar"}`,
+					},
+				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
 				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					expect := "foo"
+					if filepath.Base(inputPath) == "document2.docx" {
+						expect = "bar"
+					}
+
+					if options.Password != expect {
+						return fmt.Errorf("expected '%s' password but got '%s'", expect, options.Password)
+					}
+
 					return nil
 				},
 				ExtensionsMock: func() []string {
@@ -242,21 +462,29 @@ func TestConvertRoute(t *testing.T) {
 			expectOutputPathsCount: 2,
 		},
 		{
-			scenario: "success (HTML file)",
+			scenario: "success (password-protected DOCX document)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx": "/document.docx",
+					"protected.docx": protectedDocxPath,
 				})
 				ctx.SetValues(map[string][]string{
-					"htmlFormat": {
-						"true",
+					"password": {
+						"foo",
 					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
-				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.Password != "foo" {
+						return fmt.Errorf("expected 'foo' password but got '%s'", options.Password)
+					}
+
+					if options.ImportFilter != "" {
+						return fmt.Errorf("expected no import filter but got '%s'", options.ImportFilter)
+					}
+
 					return nil
 				},
 				ExtensionsMock: func() []string {
@@ -268,55 +496,50 @@ func TestConvertRoute(t *testing.T) {
 			expectOutputPathsCount: 1,
 		},
 		{
-			scenario: "success with non-native PDF/A & PDF/UA (single file)",
+			scenario: "success (DOC and RTF documents named DOCX)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx": "/document.docx",
-				})
-				ctx.SetValues(map[string][]string{
-					"pdfa": {
-						gotenberg.PdfA1b,
-					},
-					"pdfua": {
-						"true",
-					},
-					"nativePdfFormats": {
-						"false",
-					},
+					"legacy.docx": legacyDocxPath,
+					"letter.docx": rtfDocxPath,
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
 				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					expect := "MS Word 97"
+					if filepath.Base(inputPath) == "letter.docx" {
+						expect = "Rich Text Format"
+					}
+
+					if options.ImportFilter != expect {
+						return fmt.Errorf("expected '%s' import filter but got '%s'", expect, options.ImportFilter)
+					}
+
 					return nil
 				},
 				ExtensionsMock: func() []string {
 					return []string{".docx"}
 				},
 			},
-			engine: &gotenberg.PdfEngineMock{
-				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
-					return nil
-				},
-			},
 			expectError:            false,
 			expectHttpError:        false,
-			expectOutputPathsCount: 1,
+			expectOutputPathsCount: 2,
 		},
 		{
-			scenario: "success with native PDF/A & PDF/UA (single file)",
+			scenario: "success (images)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx": "/document.docx",
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
 				})
 				ctx.SetValues(map[string][]string{
-					"pdfa": {
-						gotenberg.PdfA1b,
+					"imageFormat": {
+						"jpeg",
 					},
-					"pdfua": {
-						"true",
+					"imageDpi": {
+						"300",
 					},
 				})
 				return ctx
@@ -330,16 +553,20 @@ func TestConvertRoute(t *testing.T) {
 				},
 			},
 			engine: &gotenberg.PdfEngineMock{
-				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
-					return nil
+				RasterizeMock: func(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+					if raster.Format != gotenberg.PdfRasterJpeg || raster.Dpi != 300 {
+						return nil, fmt.Errorf("unexpected raster options: %+v", raster)
+					}
+
+					return []string{outputPathPrefix + "-1.jpeg", outputPathPrefix + "-2.jpeg"}, nil
 				},
 			},
 			expectError:            false,
 			expectHttpError:        false,
-			expectOutputPathsCount: 1,
+			expectOutputPathsCount: 4,
 		},
 		{
-			scenario: "merge error",
+			scenario: "success (images of merged PDF)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
@@ -347,6 +574,9 @@ func TestConvertRoute(t *testing.T) {
 					"document2.docx": "/document2.docx",
 				})
 				ctx.SetValues(map[string][]string{
+					"imageFormat": {
+						"png",
+					},
 					"merge": {
 						"true",
 					},
@@ -363,173 +593,284 @@ func TestConvertRoute(t *testing.T) {
 			},
 			engine: &gotenberg.PdfEngineMock{
 				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
-					return errors.New("foo")
+					return nil
+				},
+				RasterizeMock: func(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+					if raster.Dpi != 150 {
+						return nil, fmt.Errorf("expected 150 DPI but got %d", raster.Dpi)
+					}
+
+					return []string{outputPathPrefix + "-1.png", outputPathPrefix + "-2.png", outputPathPrefix + "-3.png"}, nil
 				},
 			},
-			expectError:            true,
+			expectError:            false,
 			expectHttpError:        false,
-			expectOutputPathsCount: 0,
+			expectOutputPathsCount: 3,
 		},
 		{
-			scenario: "ErrPdfFormatNotSupported (merge)",
+			scenario: "success (options.json)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx":  "/document.docx",
-					"document2.docx": "/document2.docx",
+					"document.docx": "/document.docx",
+					"sheet.csv":     "/sheet.csv",
+					"options.json":  writeOptions(`{"sheet.csv":{"landscape":true,"nativePageRanges":"1-2","importFilter":"Text - txt - csv (StarCalc)","password":"bar"}}`),
 				})
 				ctx.SetValues(map[string][]string{
-					"merge": {
-						"true",
+					"nativePageRanges": {
+						"1",
 					},
-					"pdfa": {
+					"password": {
 						"foo",
 					},
-					"nativePdfFormats": {
-						"false",
-					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
 				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					expect := libreofficeapi.Options{PageRanges: "1", Password: "foo"}
+					if filepath.Base(inputPath) == "sheet.csv" {
+						expect = libreofficeapi.Options{Landscape: true, PageRanges: "1-2", ImportFilter: "Text - txt - csv (StarCalc)", Password: "bar"}
+					}
+
+					if options.Landscape != expect.Landscape || options.PageRanges != expect.PageRanges || options.ImportFilter != expect.ImportFilter || options.Password != expect.Password {
+						return fmt.Errorf("expected %+v options for '%s' but got %+v", expect, filepath.Base(inputPath), options)
+					}
+
 					return nil
 				},
 				ExtensionsMock: func() []string {
-					return []string{".docx"}
-				},
-			},
-			engine: &gotenberg.PdfEngineMock{
-				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
-					return nil
-				},
-				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
-					return gotenberg.ErrPdfFormatNotSupported
+					return []string{".docx", ".csv"}
 				},
 			},
-			expectError:            true,
-			expectHttpError:        true,
-			expectHttpStatus:       http.StatusBadRequest,
-			expectOutputPathsCount: 0,
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
 		},
 		{
-			scenario: "PDF engine convert error (merge)",
+			scenario: "success (exportFilterOptions as string)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx":  "/document.docx",
-					"document2.docx": "/document2.docx",
+					"sheet.ods": "/sheet.ods",
 				})
 				ctx.SetValues(map[string][]string{
-					"merge": {
-						"true",
+					"outputFormat": {
+						"csv",
 					},
-					"pdfa": {
-						gotenberg.PdfA1b,
-					},
-					"nativePdfFormats": {
-						"false",
+					"exportFilterOptions": {
+						"44,34,UTF8",
 					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
-				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				ExportMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.ExportFilterOptions != "44,34,UTF8" {
+						return fmt.Errorf("expected '44,34,UTF8' export filter options but got '%s'", options.ExportFilterOptions)
+					}
+
 					return nil
 				},
 				ExtensionsMock: func() []string {
-					return []string{".docx"}
-				},
-			},
-			engine: &gotenberg.PdfEngineMock{
-				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
-					return nil
-				},
-				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
-					return errors.New("foo")
+					return []string{".ods"}
 				},
 			},
-			expectError:            true,
+			expectError:            false,
 			expectHttpError:        false,
-			expectOutputPathsCount: 0,
+			expectOutputPathsCount: 1,
 		},
 		{
-			scenario: "cannot add output paths (merge)",
+			scenario: "success (exportFilterOptions as JSON)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx":  "/document.docx",
-					"document2.docx": "/document2.docx",
+					"document.docx": "/document.docx",
 				})
 				ctx.SetValues(map[string][]string{
-					"merge": {
-						"true",
+					"exportFilterOptions": {
+						`{"EmbedStandardFonts":true,"Foo":"bar"}`,
 					},
 				})
-				ctx.SetCancelled(true)
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
 				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.FilterData["EmbedStandardFonts"] != true || options.FilterData["Foo"] != "bar" {
+						return fmt.Errorf("unexpected filter data: %+v", options.FilterData)
+					}
+
+					if options.ExportFilterOptions != "" {
+						return fmt.Errorf("expected no export filter options but got '%s'", options.ExportFilterOptions)
+					}
+
 					return nil
 				},
 				ExtensionsMock: func() []string {
 					return []string{".docx"}
 				},
 			},
-			engine: &gotenberg.PdfEngineMock{
-				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success (spreadsheet options)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"fitToWidth": {
+						"1",
+					},
+					"sheets": {
+						"Summary",
+						"3",
+					},
+					"printArea": {
+						"A1:H50",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					expect := libreofficeapi.SpreadsheetOptions{FitToWidth: 1, Sheets: []string{"Summary", "3"}, PrintArea: "A1:H50"}
+					if !reflect.DeepEqual(options.Spreadsheet, expect) {
+						return fmt.Errorf("expected spreadsheet options %+v but got %+v", expect, options.Spreadsheet)
+					}
+
 					return nil
 				},
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
 			},
-			expectError:            true,
+			expectError:            false,
 			expectHttpError:        false,
-			expectOutputPathsCount: 0,
+			expectOutputPathsCount: 1,
 		},
 		{
-			scenario: "success (merge)",
+			scenario: "success (many files)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
 					"document.docx":  "/document.docx",
 					"document2.docx": "/document2.docx",
 				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+		{
+			scenario: "success (HTML file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
 				ctx.SetValues(map[string][]string{
-					"merge": {
+					"htmlFormat": {
 						"true",
 					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
-				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
 					return nil
 				},
 				ExtensionsMock: func() []string {
 					return []string{".docx"}
 				},
 			},
-			engine: &gotenberg.PdfEngineMock{
-				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success (DOCX file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.odt": "/document.odt",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"docx",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExportMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.OutputFormat != "docx" {
+						return fmt.Errorf("expected 'docx' output format but got '%s'", options.OutputFormat)
+					}
+
+					if filepath.Ext(outputPath) != ".docx" {
+						return fmt.Errorf("expected a '.docx' output path but got '%s'", outputPath)
+					}
+
 					return nil
 				},
+				ExtensionsMock: func() []string {
+					return []string{".odt"}
+				},
 			},
 			expectError:            false,
 			expectHttpError:        false,
 			expectOutputPathsCount: 1,
 		},
 		{
-			scenario: "success with non-native PDF/A & PDF/UA (merge)",
+			scenario: "success (HTML output format)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx":  "/document.docx",
-					"document2.docx": "/document2.docx",
+					"document.docx": "/document.docx",
 				})
 				ctx.SetValues(map[string][]string{
-					"merge": {
+					"outputFormat": {
+						"html",
+					},
+					"htmlFormat": {
 						"true",
 					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with non-native PDF/A & PDF/UA (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
 					"pdfa": {
 						gotenberg.PdfA1b,
 					},
@@ -551,9 +892,6 @@ func TestConvertRoute(t *testing.T) {
 				},
 			},
 			engine: &gotenberg.PdfEngineMock{
-				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
-					return nil
-				},
 				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
 					return nil
 				},
@@ -563,26 +901,19 @@ func TestConvertRoute(t *testing.T) {
 			expectOutputPathsCount: 1,
 		},
 		{
-			scenario: "success with non-native PDF/A & PDF/UA (merge)",
+			scenario: "success with native PDF/A & PDF/UA (single file)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx":  "/document.docx",
-					"document2.docx": "/document2.docx",
+					"document.docx": "/document.docx",
 				})
 				ctx.SetValues(map[string][]string{
-					"merge": {
-						"true",
-					},
 					"pdfa": {
 						gotenberg.PdfA1b,
 					},
 					"pdfua": {
 						"true",
 					},
-					"nativePdfFormats": {
-						"false",
-					},
 				})
 				return ctx
 			}(),
@@ -595,9 +926,6 @@ func TestConvertRoute(t *testing.T) {
 				},
 			},
 			engine: &gotenberg.PdfEngineMock{
-				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
-					return nil
-				},
 				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
 					return nil
 				},
@@ -607,24 +935,22 @@ func TestConvertRoute(t *testing.T) {
 			expectOutputPathsCount: 1,
 		},
 		{
-			scenario: "invalid form data: htmlFormat and merge are set",
+			scenario: "merge error",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
 					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
 				})
 				ctx.SetValues(map[string][]string{
 					"merge": {
 						"true",
 					},
-					"htmlFormat": {
-						"true",
-					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
-				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
 					return nil
 				},
 				ExtensionsMock: func() []string {
@@ -633,33 +959,36 @@ func TestConvertRoute(t *testing.T) {
 			},
 			engine: &gotenberg.PdfEngineMock{
 				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
-					return nil
+					return errors.New("foo")
 				},
 			},
 			expectError:            true,
-			expectHttpError:        true,
-			expectHttpStatus:       http.StatusBadRequest,
+			expectHttpError:        false,
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "invalid form data: htmlFormat and nativePageRanges set",
+			scenario: "ErrPdfFormatNotSupported (merge)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
 					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
 				})
 				ctx.SetValues(map[string][]string{
-					"nativePageRanges": {
-						"1-2",
-					},
-					"htmlFormat": {
+					"merge": {
 						"true",
 					},
+					"pdfa": {
+						"foo",
+					},
+					"nativePdfFormats": {
+						"false",
+					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
-				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
 					return nil
 				},
 				ExtensionsMock: func() []string {
@@ -670,6 +999,9 @@ func TestConvertRoute(t *testing.T) {
 				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
 					return nil
 				},
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return gotenberg.ErrPdfFormatNotSupported
+				},
 			},
 			expectError:            true,
 			expectHttpError:        true,
@@ -677,24 +1009,28 @@ func TestConvertRoute(t *testing.T) {
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "invalid form data: htmlFormat and pdfa set",
+			scenario: "PDF engine convert error (merge)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
 					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
 				})
 				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
 					"pdfa": {
-						"1",
+						gotenberg.PdfA1b,
 					},
-					"htmlFormat": {
-						"true",
+					"nativePdfFormats": {
+						"false",
 					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
-				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
 					return nil
 				},
 				ExtensionsMock: func() []string {
@@ -705,31 +1041,64 @@ func TestConvertRoute(t *testing.T) {
 				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
 					return nil
 				},
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return errors.New("foo")
+				},
 			},
 			expectError:            true,
-			expectHttpError:        true,
-			expectHttpStatus:       http.StatusBadRequest,
+			expectHttpError:        false,
 			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "invalid form data: htmlFormat and pdfua set",
+			scenario: "cannot add output paths (merge)",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
 					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
 				})
 				ctx.SetValues(map[string][]string{
-					"pdfua": {
+					"merge": {
 						"true",
 					},
-					"htmlFormat": {
+				})
+				ctx.SetCancelled(true)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (merge)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
 						"true",
 					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
-				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
 					return nil
 				},
 				ExtensionsMock: func() []string {
@@ -741,22 +1110,3540 @@ func TestConvertRoute(t *testing.T) {
 					return nil
 				},
 			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid order form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+					"document.pdf":  "/document.pdf",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"order": {
+						`["document.pdf","foo.docx"]`,
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx", ".pdf"}
+				},
+			},
 			expectError:            true,
 			expectHttpError:        true,
 			expectHttpStatus:       http.StatusBadRequest,
 			expectOutputPathsCount: 0,
 		},
-	} {
-		t.Run(tc.scenario, func(t *testing.T) {
-			tc.ctx.SetLogger(zap.NewNop())
-			c := echo.New().NewContext(nil, nil)
-			c.Set("context", tc.ctx.Context)
-
-			err := convertRoute(tc.libreOffice, tc.engine).Handler(c)
-
-			if tc.expectError && err == nil {
-				t.Fatal("expected error but got none", err)
-			}
+		{
+			scenario: "success (merge office documents and PDFs)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+					"document.xlsx": "/document.xlsx",
+					"document.pdf":  "/document.pdf",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"order": {
+						`["document.docx","document.xlsx","document.pdf"]`,
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if inputPath == "/document.pdf" {
+						return errors.New("PDF converted with LibreOffice")
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx", ".xlsx", ".pdf"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					if len(inputPaths) != 3 || inputPaths[2] != "/document.pdf" {
+						return fmt.Errorf("unexpected input paths: %+v", inputPaths)
+					}
+
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "error from PDF engine (mergeOutline)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"mergeOutline": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				ReadOutlineMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+					return gotenberg.PdfOutline{}, errors.New("foo")
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (mergeOutline)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"mergeOutline": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				ReadOutlineMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+					return gotenberg.PdfOutline{PageCount: 1}, nil
+				},
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+				SetOutlineMock: func(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+					if entries[0].Title != "document.docx" || entries[1].Title != "document2.docx" {
+						return fmt.Errorf("unexpected outline entries: %+v", entries)
+					}
+
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success (merge with pageNumbers)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"pageNumbers": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+				StampPageNumbersMock: func(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with non-native PDF/A & PDF/UA (merge)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"pdfa": {
+						gotenberg.PdfA1b,
+					},
+					"pdfua": {
+						"true",
+					},
+					"nativePdfFormats": {
+						"false",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with non-native PDF/A & PDF/UA (merge)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"pdfa": {
+						gotenberg.PdfA1b,
+					},
+					"pdfua": {
+						"true",
+					},
+					"nativePdfFormats": {
+						"false",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid pdfVersion form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfVersion": {
+						"1.4",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "pdfVersion form field conflicting with pdfa form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfa": {
+						gotenberg.PdfA1b,
+					},
+					"pdfVersion": {
+						"2.0",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with pdfVersion (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfVersion": {
+						"2.0",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.PdfVersion != "2.0" {
+						return fmt.Errorf("expected PDF version 2.0 but got '%s'", options.PdfVersion)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid form data: unknown imageFormat",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"imageFormat": {
+						"gif",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: imageDpi out of range",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"imageFormat": {
+						"png",
+					},
+					"imageDpi": {
+						"0",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: imageFormat and outputFormat set",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"imageFormat": {
+						"png",
+					},
+					"outputFormat": {
+						"docx",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: malformed options.json",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+					"options.json":  writeOptions(`foo`),
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: unknown option in options.json",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+					"options.json":  writeOptions(`{"document.docx":{"foo":true}}`),
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: options.json for an unknown document",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+					"options.json":  writeOptions(`{"foo.docx":{"landscape":true}}`),
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: outputFormat and page ranges in options.json",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+					"options.json":  writeOptions(`{"document.docx":{"nativePageRanges":"1"}}`),
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"odt",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: malformed exportFilterOptions JSON",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportFilterOptions": {
+						`{foo`,
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: exportFilterOptions and filterData as JSON",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportFilterOptions": {
+						`{"Quality":90}`,
+					},
+					"filterData": {
+						`{"Quality":80}`,
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: negative fitToWidth",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"fitToWidth": {
+						"-1",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: invalid printArea",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"printArea": {
+						"Sheet1!A1",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: spreadsheet options and outputFormat",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"sheets": {
+						"1",
+					},
+					"outputFormat": {
+						"csv",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: spreadsheet options and singlePage",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"fitToHeight": {
+						"1",
+					},
+					"singlePage": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: spreadsheet options and not a spreadsheet",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"fitToWidth": {
+						"1",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: unknown sheet",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.ods": "/sheet.ods",
+				})
+				ctx.SetValues(map[string][]string{
+					"sheets": {
+						"Foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return libreofficeapi.ErrInvalidSpreadsheetOptions
+				},
+				ExtensionsMock: func() []string {
+					return []string{".ods"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: permissions without ownerPassword",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"allowPrinting": {
+						"false",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: userPassword and merge",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"userPassword": {
+						"foo",
+					},
+					"merge": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: ownerPassword and pdfa",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"ownerPassword": {
+						"foo",
+					},
+					"pdfa": {
+						"PDF/A-2b",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: userPassword and outputFormat",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"userPassword": {
+						"foo",
+					},
+					"outputFormat": {
+						"docx",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: initialView",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"initialView": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: magnification",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"magnification": {
+						"zoom",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: pageLayout",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pageLayout": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: formsType",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"formsType": {
+						"json",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: formsType and flattened exportFormFields",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"formsType": {
+						"pdf",
+					},
+					"exportFormFields": {
+						"flattened",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: showTrackedChanges",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"showTrackedChanges": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: showTrackedChanges contradicts trackedChanges",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"trackedChanges": {
+						"reject",
+					},
+					"showTrackedChanges": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: trackedChanges",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"trackedChanges": {
+						"hide",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: invalid font file",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+					"font.otf":      writeOptions("foo"),
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: locale",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.odt": "/document.odt",
+				})
+				ctx.SetValues(map[string][]string{
+					"locale": {
+						"foo bar",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".odt"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: timezone",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.odt": "/document.odt",
+				})
+				ctx.SetValues(map[string][]string{
+					"timezone": {
+						"Local",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".odt"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: CSV form fields without CSV output",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"csvDelimiter": {
+						";",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: CSV form fields and exportFilterOptions",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"csv",
+					},
+					"csvDelimiter": {
+						";",
+					},
+					"exportFilterOptions": {
+						"44,34,UTF8",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: same csvDelimiter and csvQuote",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"csv",
+					},
+					"csvDelimiter": {
+						";",
+					},
+					"csvQuote": {
+						";",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: invalid csvSheet",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"csv",
+					},
+					"csvSheet": {
+						"0",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: invalid csvEncoding",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"csv",
+					},
+					"csvEncoding": {
+						"ebcdic",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: separatorPage without merge",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"separatorPage": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: separatorPageTemplate without separatorPage",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"separatorPageTemplate": {
+						"{filename}",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: invalid concurrency",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"concurrency": {
+						"0",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "continueOnError and every conversion fails",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"continueOnError": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return libreofficeapi.ErrInvalidPassword
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: macroSecurity",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"macroSecurity": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: splitPages and merge",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"splitPages": {
+						"true",
+					},
+					"merge": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: splitPages and imageFormat",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"splitPages": {
+						"true",
+					},
+					"imageFormat": {
+						"png",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: splitPages and outputFormat",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"splitPages": {
+						"true",
+					},
+					"outputFormat": {
+						"docx",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: splitPages and ownerPassword",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"splitPages": {
+						"true",
+					},
+					"ownerPassword": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: content does not match extension",
+			ctx: func() *api.ContextMock {
+				inputPath := filepath.Join(optionsDirPath, "sheet.docx")
+
+				f, err := os.Create(inputPath)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				writer := zip.NewWriter(f)
+				for _, name := range []string{"[Content_Types].xml", "xl/workbook.xml"} {
+					_, err = writer.Create(name)
+					if err != nil {
+						t.Fatalf("expected no error but got: %v", err)
+					}
+				}
+
+				err = writer.Close()
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = f.Close()
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.docx": inputPath,
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: showTrackedChanges and not a text document",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.xlsx": "/document.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"showTrackedChanges": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: trackedChanges and not a text document",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.xlsx": "/document.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"trackedChanges": {
+						"reject",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: malformed passwords",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"passwords": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: password for an unknown document",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"passwords": {
+						`{"foo.docx":"bar"}`,
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "cannot rasterize PDF",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"imageFormat": {
+						"png",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				RasterizeMock: func(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+					return nil, errors.New("foo")
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrInvalidPassword",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"password": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return libreofficeapi.ErrInvalidPassword
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrCorruptDocument",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return libreofficeapi.ErrCorruptDocument
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: unknown outputFormat",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: htmlFormat and another outputFormat are set",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"odt",
+					},
+					"htmlFormat": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: outputFormat and pdfa set",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"odt",
+					},
+					"pdfa": {
+						gotenberg.PdfA1b,
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: htmlBundle",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"htmlFormat": {
+						"true",
+					},
+					"htmlBundle": {
+						"tar",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: htmlBundle without HTML output",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"htmlBundle": {
+						"zip",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: htmlFormat and merge are set",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"htmlFormat": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: htmlFormat and nativePageRanges set",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"nativePageRanges": {
+						"1-2",
+					},
+					"htmlFormat": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: htmlFormat and pdfa set",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfa": {
+						"1",
+					},
+					"htmlFormat": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: htmlFormat and pdfua set",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfua": {
+						"true",
+					},
+					"htmlFormat": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid form data: unknown filterData property",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"filterData": {
+						`{"Foo":true}`,
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with filterData (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"filterData": {
+						`{"ExportFormFields":false,"Foo":"bar"}`,
+					},
+					"allowUnknownFilterData": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.FilterData["ExportFormFields"] != false || options.FilterData["Foo"] != "bar" {
+						return fmt.Errorf("unexpected filter data: %+v", options.FilterData)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid form data: exportNotesMode",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportNotesMode": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with comments in margin (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportComments": {
+						"true",
+					},
+					"exportNotesMode": {
+						"margin",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if !options.ExportComments || !options.ExportCommentsInMargin {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with notes pages and hidden slides (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"slides.pptx": "/slides.pptx",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportNotes": {
+						"true",
+					},
+					"includeHiddenSlides": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if !options.ExportNotesPages || !options.ExportHiddenSlides {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".pptx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "invalid form data: exportFormFields",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportFormFields": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with updateIndexes (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"updateIndexes": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if !options.UpdateIndexes {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with updateIndexes and htmlFormat (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"updateIndexes": {
+						"true",
+					},
+					"htmlFormat": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if !options.UpdateIndexes {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with flattened form fields and links options (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportFormFields": {
+						"flattened",
+					},
+					"exportBookmarksToPdfDestination": {
+						"true",
+					},
+					"exportLinksRelativeFsys": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.ExportFormFields != libreofficeapi.ExportFormFieldsFlattened || !options.ExportBookmarksToPdfDestination || !options.ExportLinksRelativeFsys {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with reduced image resolution (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"reduceImageResolution": {
+						"true",
+					},
+					"maxImageResolution": {
+						"150",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if !options.ReduceImageResolution || options.MaxImageResolution != 150 {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with image compression options (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"jpegQuality": {
+						"75",
+					},
+					"losslessImageCompression": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.JpegQuality != 75 || !options.LosslessImageCompression {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with encryption (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"userPassword": {
+						"foo",
+					},
+					"ownerPassword": {
+						"bar",
+					},
+					"allowCopying": {
+						"false",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.UserPassword != "foo" || options.OwnerPassword != "bar" || !options.AllowPrinting || options.AllowCopying || !options.AllowModifying {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with bookmarks and viewer preferences (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportBookmarks": {
+						"false",
+					},
+					"initialView": {
+						"outline",
+					},
+					"magnification": {
+						"fitWidth",
+					},
+					"pageLayout": {
+						"twoColumns",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if !options.SkipBookmarks || options.InitialView != "outline" || options.Magnification != "fitWidth" || options.PageLayout != "twoColumns" {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with forms type (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"form.odt": "/form.odt",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportFormFields": {
+						"interactive",
+					},
+					"formsType": {
+						"xml",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.ExportFormFields != libreofficeapi.ExportFormFieldsInteractive || options.FormsType != "xml" {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".odt"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with hidden tracked changes (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"showTrackedChanges": {
+						"false",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.TrackedChanges != libreofficeapi.TrackedChangesAccept {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with shown tracked changes (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"trackedChanges": {
+						"show",
+					},
+					"showTrackedChanges": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.TrackedChanges != libreofficeapi.TrackedChangesShow {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with tracked changes (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"trackedChanges": {
+						"accept",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.TrackedChanges != libreofficeapi.TrackedChangesAccept {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with font files (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.xlsx": "/document.xlsx",
+					"font.ttf":      fontPath,
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if len(options.Fonts) != 1 || options.Fonts[0].Family != "Go" {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with locale and timezone (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.xlsx": "/document.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"locale": {
+						"de_DE",
+					},
+					"timezone": {
+						"Europe/Berlin",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.Locale != "de-DE" || options.Timezone != "Europe/Berlin" {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with htmlBundle (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"html",
+					},
+					"htmlBundle": {
+						"zip",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				HtmlMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.HtmlBundle != libreofficeapi.HtmlBundleZip || filepath.Ext(outputPath) != ".zip" {
+						return fmt.Errorf("unexpected options or output path: %+v, %s", options, outputPath)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with CSV options",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"csv",
+					},
+					"csvDelimiter": {
+						"tab",
+					},
+					"csvQuote": {
+						"'",
+					},
+					"csvEncoding": {
+						"ISO-8859-1",
+					},
+					"csvQuoteAll": {
+						"true",
+					},
+					"csvSheet": {
+						"2",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExportMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					expect := libreofficeapi.CsvOptions{Delimiter: '\t', Quote: '\'', Encoding: "ISO-8859-1", QuoteAll: true, Sheet: 2}
+					if options.Csv != expect {
+						return fmt.Errorf("expected %+v CSV options but got %+v", expect, options.Csv)
+					}
+
+					if filepath.Ext(outputPath) != ".tsv" {
+						return fmt.Errorf("expected a '.tsv' output path but got '%s'", outputPath)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with each sheet to CSV",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"sheet.xlsx": "/sheet.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"csv",
+					},
+					"csvSheet": {
+						"all",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExportMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.Csv.Sheet != libreofficeapi.CsvAllSheets {
+						return fmt.Errorf("expected all sheets but got %d", options.Csv.Sheet)
+					}
+
+					for _, sheet := range []string{"Sales", "Costs"} {
+						err := os.WriteFile(strings.TrimSuffix(outputPath, ".csv")+"-"+sheet+".csv", []byte("a,b"), 0o600)
+						if err != nil {
+							return err
+						}
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+		{
+			scenario: "success with separatorPage (merge)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"separatorPage": {
+						"true",
+					},
+					"separatorPageTemplate": {
+						"Exhibit {index}: {filename}",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if filepath.Ext(inputPath) != ".html" {
+						return nil
+					}
+
+					content, err := os.ReadFile(inputPath)
+					if err != nil {
+						return err
+					}
+
+					if !strings.Contains(string(content), "Exhibit 2: document2.docx") {
+						return fmt.Errorf("expected the separator page of 'document2.docx' but got: %s", content)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					if len(inputPaths) != 2 {
+						return fmt.Errorf("expected 2 input paths but got %d", len(inputPaths))
+					}
+
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with concurrent conversions",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: &api.Context{Context: context.Background()}}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				return ctx
+			}(),
+			libreOffice: func() libreofficeapi.Uno {
+				var wg sync.WaitGroup
+				wg.Add(2)
+
+				return &libreofficeapi.ConcurrentApiMock{
+					ApiMock: libreofficeapi.ApiMock{
+						PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+							wg.Done()
+
+							done := make(chan struct{})
+							go func() {
+								wg.Wait()
+								close(done)
+							}()
+
+							select {
+							case <-done:
+								return nil
+							case <-time.After(time.Duration(5) * time.Second):
+								return errors.New("expected both conversions to run at the same time")
+							}
+						},
+						ExtensionsMock: func() []string {
+							return []string{".docx"}
+						},
+					},
+					ConcurrencyMock: func() int {
+						return 4
+					},
+				}
+			}(),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+		{
+			scenario: "success with continueOnError (merge)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+					"document3.docx": "/document3.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"continueOnError": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if filepath.Base(inputPath) == "document2.docx" {
+						return libreofficeapi.ErrInvalidPassword
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					if len(inputPaths) != 2 {
+						return fmt.Errorf("expected 2 input paths but got %d", len(inputPaths))
+					}
+
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+		{
+			scenario: "success with default security options (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.MacroSecurity != "" || options.AllowExternalLinks {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with macroSecurity and blockExternalLinks (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"macroSecurity": {
+						"enable",
+					},
+					"blockExternalLinks": {
+						"false",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.MacroSecurity != libreofficeapi.MacroSecurityEnable || !options.AllowExternalLinks {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with repairMode (single file)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"repairMode": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if !options.RepairMode {
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success (split pages)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"splitPages": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				SplitPagesMock: writePages(2),
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 4,
+		},
+		{
+			scenario: "success (embedded objects)",
+			ctx: func() *api.ContextMock {
+				inputPath := filepath.Join(optionsDirPath, "document.rtf")
+
+				err := os.WriteFile(inputPath, []byte(`{\rtf1\ansi Hello}`), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.rtf": inputPath,
+				})
+				ctx.SetValues(map[string][]string{
+					"extractEmbeddedObjects": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".rtf"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+		{
+			scenario: "success (import filter from content)",
+			ctx: func() *api.ContextMock {
+				inputPath := filepath.Join(optionsDirPath, "letter.doc")
+
+				err := os.WriteFile(inputPath, []byte(`{\rtf1\ansi Hello}`), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"letter.doc": inputPath,
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.ImportFilter != "Rich Text Format" {
+						return fmt.Errorf("expected 'Rich Text Format' import filter but got '%s'", options.ImportFilter)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".doc"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with reproducible (merge)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"merge": {
+						"true",
+					},
+					"reproducible": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+				NormalizeMock: func(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "reproducible with PDF formats",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"reproducible": {
+						"true",
+					},
+					"pdfua": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "PDF engine normalize error (many files)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"reproducible": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				NormalizeMock: func(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with reproducible (many files)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"reproducible": {
+						"true",
+					},
+					"sourceDateEpoch": {
+						"1704067200",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				NormalizeMock: func(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+					if date.Unix() != 1704067200 {
+						return fmt.Errorf("unexpected date: %s", date)
+					}
+
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := convertRoute(tc.libreOffice, tc.engine).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+		})
+	}
+}
+
+func TestProbeRoute(t *testing.T) {
+	dirPath := t.TempDir()
+
+	for name, content := range map[string]string{
+		"document.rtf":  `{\rtf1\ansi Hello}`,
+		"document.docx": "foo",
+	} {
+		err := os.WriteFile(filepath.Join(dirPath, name), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+		expectProperties       string
+	}{
+		{
+			scenario:               "missing mandatory files form field",
+			ctx:                    &api.ContextMock{Context: new(api.Context)},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrCorruptDocument",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": filepath.Join(dirPath, "document.docx"),
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.rtf": filepath.Join(dirPath, "document.rtf"),
+				})
+				return ctx
+			}(),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectProperties:       `{"files":[{"filename":"document.rtf","format":"rtf","pages":1}]}`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			libreOffice := &libreofficeapi.ApiMock{
+				ProbeMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (libreofficeapi.DocumentProperties, error) {
+					properties, err := libreofficeapi.ProbeDocument(inputPath)
+					if err != nil {
+						return properties, err
+					}
+
+					// Like LibreOffice, for the documents without counts in
+					// their metadata.
+					properties.Pages = 1
+
+					return properties, nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx", ".rtf"}
+				},
+			}
+
+			err := probeRoute(libreOffice).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Fatalf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+
+			if tc.expectProperties == "" {
+				return
+			}
+
+			content, err := os.ReadFile(tc.ctx.OutputPaths()[0])
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var actual bytes.Buffer
+
+			err = json.Compact(&actual, content)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual.String() != tc.expectProperties {
+				t.Errorf("expected %s but got %s", tc.expectProperties, actual.String())
+			}
+		})
+	}
+}
+
+func TestTextRoute(t *testing.T) {
+	writeText := func(content string) func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+		return func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+			return os.WriteFile(outputPath, []byte(content), 0o600)
+		}
+	}
+
+	legacyDocxPath := filepath.Join(t.TempDir(), "legacy.docx")
+	writeCfb(t, legacyDocxPath, []string{"\x01CompObj", "WordDocument", "1Table"})
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		libreOffice            *libreofficeapi.ApiMock
+		engine                 *gotenberg.PdfEngineMock
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+		expectTexts            map[string]string
+	}{
+		{
+			scenario:               "missing mandatory files form field",
+			ctx:                    &api.ContextMock{Context: new(api.Context)},
+			libreOffice:            &libreofficeapi.ApiMock{ExtensionsMock: func() []string { return []string{".docx"} }},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid outputFormat form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"xml",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice:            &libreofficeapi.ApiMock{ExtensionsMock: func() []string { return []string{".docx"} }},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrCorruptDocument",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExportMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return libreofficeapi.ErrCorruptDocument
+				},
+				ExtensionsMock: func() []string { return []string{".docx"} },
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (JSON)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+					"sheet.xlsx":    "/sheet.xlsx",
+					"slides.pptx":   "/slides.pptx",
+					"document.pdf":  "/document.pdf",
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExportMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					switch options.OutputFormat {
+					case "txt":
+						if options.ExportFilterOptions != "UTF8" {
+							return fmt.Errorf("unexpected options: %+v", options)
+						}
+
+						return os.WriteFile(outputPath, []byte("Hello\n"), 0o600)
+					case "csv":
+						if options.Csv.Sheet != libreofficeapi.CsvAllSheets {
+							return fmt.Errorf("unexpected options: %+v", options)
+						}
+
+						base := strings.TrimSuffix(outputPath, ".csv")
+						for _, sheet := range [][2]string{{"-Sheet1.csv", "a,b\n"}, {"-Sheet2.csv", "c,d\n"}} {
+							err := os.WriteFile(base+sheet[0], []byte(sheet[1]), 0o600)
+							if err != nil {
+								return err
+							}
+						}
+
+						return nil
+					default:
+						return fmt.Errorf("unexpected options: %+v", options)
+					}
+				},
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if inputPath != "/slides.pptx" {
+						return fmt.Errorf("unexpected input path: %s", inputPath)
+					}
+
+					return nil
+				},
+				ExtensionsMock: func() []string { return []string{".docx", ".xlsx", ".pptx", ".pdf"} },
+			},
+			engine: &gotenberg.PdfEngineMock{
+				ExtractTextMock: func(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+					if inputPath == "/document.pdf" {
+						return os.WriteFile(outputPath, []byte("PDF\f"), 0o600)
+					}
+
+					return os.WriteFile(outputPath, []byte("Slide 1\fSlide 2\f"), 0o600)
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectTexts: map[string]string{
+				"document.docx": "Hello",
+				"sheet.xlsx":    "a,b\n\fc,d",
+				"slides.pptx":   "Slide 1\fSlide 2",
+				"document.pdf":  "PDF",
+			},
+		},
+		{
+			scenario: "success (DOC document named DOCX)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"legacy.docx": legacyDocxPath,
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"txt",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExportMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.ImportFilter != "MS Word 97" {
+						return fmt.Errorf("expected 'MS Word 97' import filter but got '%s'", options.ImportFilter)
+					}
+
+					return os.WriteFile(outputPath, []byte("Hello"), 0o600)
+				},
+				ExtensionsMock: func() []string { return []string{".docx"} },
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success (TXT)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"outputFormat": {
+						"txt",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ExportMock:     writeText("Hello"),
+				ExtensionsMock: func() []string { return []string{".docx"} },
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := textRoute(tc.libreOffice, tc.engine).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
 
 			if !tc.expectError && err != nil {
 				t.Fatalf("expected no error but got: %v", err)
@@ -781,7 +4668,460 @@ func TestConvertRoute(t *testing.T) {
 			}
 
 			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
-				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+				t.Fatalf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+
+			if tc.expectTexts == nil {
+				return
+			}
+
+			content, err := os.ReadFile(tc.ctx.OutputPaths()[0])
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var actual map[string]string
+
+			err = json.Unmarshal(content, &actual)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expectTexts) {
+				t.Errorf("expected %+v but got %+v", tc.expectTexts, actual)
+			}
+		})
+	}
+}
+
+// writePages returns a mock of [gotenberg.PdfEngine.SplitPages] which writes
+// the given number of pages.
+func writePages(count int) func(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	return func(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+		var outputPaths []string
+
+		for page := 1; page <= count; page++ {
+			outputPath := fmt.Sprintf("%s-%d.pdf", outputPathPrefix, page)
+
+			err := os.WriteFile(outputPath, []byte("%PDF-1.7"), 0o600)
+			if err != nil {
+				return nil, err
+			}
+
+			outputPaths = append(outputPaths, outputPath)
+		}
+
+		return outputPaths, nil
+	}
+}
+
+// writeCfb writes a Compound File Binary document, with 512 bytes sectors,
+// whose directory has the given entries.
+func writeCfb(t *testing.T, path string, names []string) {
+	content := make([]byte, 512*3)
+
+	// Header.
+	copy(content, "\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")
+	binary.LittleEndian.PutUint16(content[30:], 9)
+	binary.LittleEndian.PutUint32(content[44:], 1)
+	binary.LittleEndian.PutUint32(content[48:], 1)
+	for i := 0; i < 109; i++ {
+		binary.LittleEndian.PutUint32(content[76+4*i:], 0xFFFFFFFF)
+	}
+	binary.LittleEndian.PutUint32(content[76:], 0)
+
+	// Sector 0: the FAT, in which the directory is a single sector.
+	for i := 0; i < 128; i++ {
+		binary.LittleEndian.PutUint32(content[512+4*i:], 0xFFFFFFFF)
+	}
+	binary.LittleEndian.PutUint32(content[512:], 0xFFFFFFFD)
+	binary.LittleEndian.PutUint32(content[512+4:], 0xFFFFFFFE)
+
+	// Sector 1: the directory.
+	for i, name := range append([]string{"Root Entry"}, names...) {
+		entry := content[1024+128*i : 1024+128*(i+1)]
+
+		units := utf16.Encode([]rune(name))
+		for j, unit := range units {
+			binary.LittleEndian.PutUint16(entry[2*j:], unit)
+		}
+		binary.LittleEndian.PutUint16(entry[64:], uint16(2*len(units)+2))
+	}
+
+	err := os.WriteFile(path, content, 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func TestSplitPdfs(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		inputPaths      []string
+		engine          *gotenberg.PdfEngineMock
+		expectError     bool
+		expectFilenames []string
+	}{
+		{
+			scenario:   "split error",
+			inputPaths: []string{"/report.docx"},
+			engine: &gotenberg.PdfEngineMock{
+				SplitPagesMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+					return nil, errors.New("foo")
+				},
+			},
+			expectError: true,
+		},
+		{
+			scenario:   "success",
+			inputPaths: []string{"/report.docx", "/report.xlsx", "/slides.pptx"},
+			engine: &gotenberg.PdfEngineMock{
+				SplitPagesMock: writePages(2),
+			},
+			expectFilenames: []string{
+				"report-001.pdf", "report-002.pdf",
+				"report.xlsx-001.pdf", "report.xlsx-002.pdf",
+				"slides-001.pdf", "slides-002.pdf",
+			},
+		},
+		{
+			scenario:   "success (more than 999 pages)",
+			inputPaths: []string{"/book.odt"},
+			engine: &gotenberg.PdfEngineMock{
+				SplitPagesMock: writePages(1000),
+			},
+			expectFilenames: func() []string {
+				filenames := make([]string, 1000)
+				for i := range filenames {
+					filenames[i] = fmt.Sprintf("book-%04d.pdf", i+1)
+				}
+				return filenames
+			}(),
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(t.TempDir())
+			ctx.SetLogger(zap.NewNop())
+
+			pdfPaths := make([]string, len(tc.inputPaths))
+			for i := range pdfPaths {
+				pdfPaths[i] = ctx.GeneratePath(".pdf")
+			}
+
+			outputPaths, err := splitPdfs(ctx.Context, tc.engine, tc.inputPaths, pdfPaths)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var filenames []string
+			for _, outputPath := range outputPaths {
+				_, err = os.Stat(outputPath)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				filenames = append(filenames, filepath.Base(outputPath))
+			}
+
+			if !reflect.DeepEqual(filenames, tc.expectFilenames) {
+				t.Errorf("expected %v but got %v", tc.expectFilenames, filenames)
+			}
+		})
+	}
+}
+
+func TestWriteEmbeddedObjects(t *testing.T) {
+	dirPath := t.TempDir()
+
+	// writeDocument writes a DOCX document with the given embedded objects.
+	writeDocument := func(filename string, embeddings map[string]string) string {
+		path := filepath.Join(dirPath, filename)
+
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		writer := zip.NewWriter(f)
+
+		entries := map[string]string{
+			"[Content_Types].xml": `<Types/>`,
+			"word/document.xml":   `<w:document/>`,
+		}
+		for name, content := range embeddings {
+			entries["word/embeddings/"+name] = content
+		}
+
+		for name, content := range entries {
+			w, err := writer.Create(name)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			_, err = w.Write([]byte(content))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		}
+
+		err = writer.Close()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return path
+	}
+
+	inputPaths := []string{
+		writeDocument("report.docx", map[string]string{"oleObject1.bin": "ole"}),
+		writeDocument("empty.docx", nil),
+	}
+
+	for _, tc := range []struct {
+		scenario        string
+		extract         bool
+		expectFilenames []string
+		expectJson      string
+	}{
+		{
+			scenario:        "report only",
+			extract:         false,
+			expectFilenames: []string{"embedded-objects.json"},
+			expectJson:      `{"files":[{"filename":"report.docx","objects":[{"name":"word/embeddings/oleObject1.bin","type":"ole","size":3}]},{"filename":"empty.docx","objects":[]}]}`,
+		},
+		{
+			scenario:        "extract",
+			extract:         true,
+			expectFilenames: []string{"embedded-objects.json", "report-oleObject1.bin"},
+			expectJson:      `{"files":[{"filename":"report.docx","objects":[{"name":"word/embeddings/oleObject1.bin","type":"ole","size":3,"extractedAs":"report-oleObject1.bin"}]},{"filename":"empty.docx","objects":[]}]}`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(t.TempDir())
+
+			outputPaths, err := writeEmbeddedObjects(ctx.Context, inputPaths, tc.extract)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var filenames []string
+			for _, outputPath := range outputPaths {
+				filenames = append(filenames, filepath.Base(outputPath))
+			}
+
+			if !reflect.DeepEqual(filenames, tc.expectFilenames) {
+				t.Fatalf("expected %v but got %v", tc.expectFilenames, filenames)
+			}
+
+			content, err := os.ReadFile(outputPaths[0])
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var actual bytes.Buffer
+			err = json.Compact(&actual, content)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual.String() != tc.expectJson {
+				t.Errorf("expected %s but got %s", tc.expectJson, actual.String())
+			}
+		})
+	}
+}
+
+func TestSeparatorPageHtml(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		template string
+		expect   string
+	}{
+		{
+			scenario: "default template",
+			expect: `<!DOCTYPE html><html><head><meta charset="utf-8"></head><body>` +
+				`<p style="text-align: center; font-size: 18pt">a &lt;b&gt;.docx</p>` +
+				`<p style="text-align: center; font-size: 18pt">Document 2 of 3</p></body></html>`,
+		},
+		{
+			scenario: "custom template",
+			template: "<img src=\"file:///etc/passwd\">\r\nBates {index}",
+			expect: `<!DOCTYPE html><html><head><meta charset="utf-8"></head><body>` +
+				`<p style="text-align: center; font-size: 18pt">&lt;img src=&#34;file:///etc/passwd&#34;&gt;</p>` +
+				`<p style="text-align: center; font-size: 18pt">Bates 2</p></body></html>`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := separatorPageHtml(tc.template, "a <b>.docx", 2, 3)
+			if actual != tc.expect {
+				t.Errorf("expected:\n%s\nbut got:\n%s", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestPassthroughPdf(t *testing.T) {
+	dirPath := t.TempDir()
+
+	pdfaPath := fmt.Sprintf("%s/pdfa.pdf", dirPath)
+	err := os.WriteFile(pdfaPath, []byte(`%PDF-1.7 <x:xmpmeta><pdfaid:part>2</pdfaid:part><pdfaid:conformance>B</pdfaid:conformance></x:xmpmeta>`), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario         string
+		nativePdfFormats bool
+		pdfFormats       gotenberg.PdfFormats
+		inputPath        string
+		engine           gotenberg.PdfEngine
+		expectConversion bool
+		expectError      bool
+		expectHttpError  bool
+	}{
+		{
+			scenario:         "no PDF formats",
+			nativePdfFormats: true,
+			inputPath:        "/document.pdf",
+		},
+		{
+			scenario:         "non-native PDF formats",
+			nativePdfFormats: false,
+			pdfFormats:       gotenberg.PdfFormats{PdfA: gotenberg.PdfA2b},
+			inputPath:        "/document.pdf",
+		},
+		{
+			scenario:         "already the requested PDF/A",
+			nativePdfFormats: true,
+			pdfFormats:       gotenberg.PdfFormats{PdfA: gotenberg.PdfA2b},
+			inputPath:        pdfaPath,
+		},
+		{
+			scenario:         "another PDF/A",
+			nativePdfFormats: true,
+			pdfFormats:       gotenberg.PdfFormats{PdfA: gotenberg.PdfA3b},
+			inputPath:        pdfaPath,
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectConversion: true,
+		},
+		{
+			scenario:         "PDF/UA",
+			nativePdfFormats: true,
+			pdfFormats:       gotenberg.PdfFormats{PdfA: gotenberg.PdfA2b, PdfUa: true},
+			inputPath:        pdfaPath,
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectConversion: true,
+		},
+		{
+			scenario:         "cannot read the PDF",
+			nativePdfFormats: true,
+			pdfFormats:       gotenberg.PdfFormats{PdfA: gotenberg.PdfA2b},
+			inputPath:        fmt.Sprintf("%s/foo.pdf", dirPath),
+			expectError:      true,
+		},
+		{
+			scenario:         "ErrPdfFormatNotSupported",
+			nativePdfFormats: true,
+			pdfFormats:       gotenberg.PdfFormats{PdfA: gotenberg.PdfA3b},
+			inputPath:        pdfaPath,
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return gotenberg.ErrPdfFormatNotSupported
+				},
+			},
+			expectError:     true,
+			expectHttpError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetLogger(zap.NewNop())
+
+			outputPath, err := passthroughPdf(ctx.Context, tc.engine, tc.nativePdfFormats, tc.pdfFormats, tc.inputPath)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError != isHttpError {
+				t.Errorf("expected an HTTP error to be %t but got: %v", tc.expectHttpError, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if tc.expectConversion == (outputPath == tc.inputPath) {
+				t.Errorf("expected conversion to be %t but got output path '%s'", tc.expectConversion, outputPath)
+			}
+		})
+	}
+}
+
+func TestPdfaConformance(t *testing.T) {
+	dirPath := t.TempDir()
+
+	for i, tc := range []struct {
+		scenario          string
+		content           []byte
+		expectConformance string
+	}{
+		{
+			scenario: "no PDF/A identification",
+			content:  []byte("%PDF-1.7"),
+		},
+		{
+			scenario:          "attributes",
+			content:           []byte(`%PDF-1.7 <rdf:Description pdfaid:part="3" pdfaid:conformance="U"/>`),
+			expectConformance: "PDF/A-3u",
+		},
+		{
+			scenario:          "elements spanning two chunks",
+			content:           append(bytes.Repeat([]byte(" "), 64*1024-12), []byte("<pdfaid:part>1</pdfaid:part><pdfaid:conformance>B</pdfaid:conformance>")...),
+			expectConformance: "PDF/A-1b",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			inputPath := fmt.Sprintf("%s/%d.pdf", dirPath, i)
+
+			err := os.WriteFile(inputPath, tc.content, 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			conformance, err := pdfaConformance(inputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if conformance != tc.expectConformance {
+				t.Errorf("expected conformance '%s' but got '%s'", tc.expectConformance, conformance)
 			}
 		})
 	}
@@ -0,0 +1,8 @@
+// Package mutool provides an implementation of the gotenberg.PdfEngine
+// interface using the mutool command-line tool from MuPDF. This package
+// allows for the redaction of PDF files, but does not support other PDF
+// operations. The path to the mutool binary must be specified using the
+// MUTOOL_BIN_PATH environment variable.
+//
+// See: https://mupdf.com.
+package mutool
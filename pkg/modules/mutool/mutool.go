@@ -0,0 +1,634 @@
+package mutool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// jpegQuality is the quality of the JPEG images of the rasterized pages.
+const jpegQuality = 90
+
+func init() {
+	gotenberg.MustRegisterModule(new(MuTool))
+}
+
+// MuTool abstracts the CLI tool mutool and implements the
+// [gotenberg.PdfEngine] interface.
+type MuTool struct {
+	binPath string
+}
+
+// Descriptor returns a [MuTool]'s module descriptor.
+func (engine *MuTool) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID:  "mutool",
+		New: func() gotenberg.Module { return new(MuTool) },
+	}
+}
+
+// Provision sets the modules properties.
+func (engine *MuTool) Provision(ctx *gotenberg.Context) error {
+	binPath, ok := os.LookupEnv("MUTOOL_BIN_PATH")
+	if !ok {
+		return errors.New("MUTOOL_BIN_PATH environment variable is not set")
+	}
+
+	engine.binPath = binPath
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (engine *MuTool) Validate() error {
+	_, err := os.Stat(engine.binPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("mutool binary path does not exist: %w", err)
+	}
+
+	return nil
+}
+
+// Merge is not available in this implementation.
+func (engine *MuTool) Merge(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+	return fmt.Errorf("merge PDFs with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Convert is not available in this implementation.
+func (engine *MuTool) Convert(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+	return fmt.Errorf("convert PDF to '%+v' with mutool: %w", formats, gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Decrypt is not available in this implementation.
+func (engine *MuTool) Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error {
+	return fmt.Errorf("decrypt PDF with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ExtractText is not available in this implementation.
+func (engine *MuTool) ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+	return fmt.Errorf("extract text from PDF with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Ocr is not available in this implementation.
+func (engine *MuTool) Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error {
+	return fmt.Errorf("OCR PDF with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Normalize is not available in this implementation.
+func (engine *MuTool) Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+	return fmt.Errorf("normalize PDF with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SetOutline is not available in this implementation.
+func (engine *MuTool) SetOutline(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+	return fmt.Errorf("set PDF outline with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ReadOutline is not available in this implementation.
+func (engine *MuTool) ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+	return gotenberg.PdfOutline{}, fmt.Errorf("read PDF outline with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Redact removes the content under areas of a PDF thanks to the redaction
+// annotations of MuPDF: the text, the vector graphics and the pixels of the
+// images within the areas are deleted, then the areas are painted black. The
+// PDF is fully rewritten, so that the removed content does not remain as
+// unused objects. The texts are searched case-insensitively.
+func (engine *MuTool) Redact(ctx context.Context, logger *zap.Logger, redactions []gotenberg.PdfRedaction, inputPath, outputPath string) error {
+	var report struct {
+		OutOfBounds []string `json:"outOfBounds"`
+		NotFound    []string `json:"notFound"`
+	}
+
+	err := engine.run(ctx, logger, redactScript, redactions, inputPath, outputPath, &report)
+	if err != nil {
+		return fmt.Errorf("redact PDF with mutool: %w", err)
+	}
+
+	if len(report.OutOfBounds) > 0 {
+		return fmt.Errorf("redact PDF with mutool: %w", &gotenberg.PdfRedactionOutOfBoundsError{Entries: report.OutOfBounds})
+	}
+
+	if len(report.NotFound) > 0 {
+		return fmt.Errorf("redact PDF with mutool: '%s': %w", strings.Join(report.NotFound, "', '"), gotenberg.ErrPdfRedactionTextNotFound)
+	}
+
+	return nil
+}
+
+// StampPageNumbers is not available in this implementation.
+func (engine *MuTool) StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+	return fmt.Errorf("stamp page numbers with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Crop sets the crop box of the pages of a PDF. The areas are relative to
+// the pages as displayed, i.e., with their current crop box and rotation. The
+// whitespace margins are detected by rendering the pages in grayscale; blank
+// pages are not trimmed.
+func (engine *MuTool) Crop(ctx context.Context, logger *zap.Logger, crop gotenberg.PdfCrop, inputPath, outputPath string) error {
+	var report struct {
+		OutOfBounds []string `json:"outOfBounds"`
+	}
+
+	err := engine.run(ctx, logger, cropScript, crop, inputPath, outputPath, &report)
+	if err != nil {
+		return fmt.Errorf("crop PDF with mutool: %w", err)
+	}
+
+	if len(report.OutOfBounds) > 0 {
+		return fmt.Errorf("crop PDF with mutool: %w", &gotenberg.PdfCropOutOfBoundsError{Entries: report.OutOfBounds})
+	}
+
+	return nil
+}
+
+// ImportImages is not available in this implementation.
+func (engine *MuTool) ImportImages(ctx context.Context, logger *zap.Logger, images []gotenberg.PdfImage, outputPath string) error {
+	return fmt.Errorf("import images with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Check is not available in this implementation.
+func (engine *MuTool) Check(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfCheckReport, error) {
+	return gotenberg.PdfCheckReport{}, fmt.Errorf("check PDF with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Repair is not available in this implementation.
+func (engine *MuTool) Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	return fmt.Errorf("repair PDF with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Rasterize renders each page of a PDF as an image with "mutool draw".
+// MuTool writes PNG images only, which are then re-encoded if the requested
+// format is JPEG.
+func (engine *MuTool) Rasterize(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+	if raster.Format != gotenberg.PdfRasterPng && raster.Format != gotenberg.PdfRasterJpeg {
+		return nil, fmt.Errorf("rasterize PDF with mutool: unknown format '%s'", raster.Format)
+	}
+
+	args := []string{"draw", "-q", "-o", fmt.Sprintf("%s-%%d.png", outputPathPrefix)}
+	if raster.Dpi > 0 {
+		args = append(args, "-r", strconv.Itoa(raster.Dpi))
+	}
+	args = append(args, inputPath)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("rasterize PDF with mutool: %w", err)
+	}
+
+	var outputPaths []string
+	for page := 1; ; page++ {
+		outputPath := fmt.Sprintf("%s-%d.png", outputPathPrefix, page)
+
+		_, err = os.Stat(outputPath)
+		if err != nil {
+			break
+		}
+
+		if raster.Format == gotenberg.PdfRasterJpeg {
+			outputPath, err = pngToJpeg(outputPath)
+			if err != nil {
+				return nil, fmt.Errorf("convert page %d to JPEG: %w", page, err)
+			}
+		}
+
+		outputPaths = append(outputPaths, outputPath)
+	}
+
+	if len(outputPaths) == 0 {
+		return nil, errors.New("rasterize PDF with mutool: no image rendered")
+	}
+
+	return outputPaths, nil
+}
+
+// SplitPages is not available in this implementation.
+func (engine *MuTool) SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("split PDF pages with mutool: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// pngToJpeg re-encodes a PNG image as a JPEG one, next to it, and removes
+// the PNG image.
+func pngToJpeg(pngPath string) (string, error) {
+	in, err := os.Open(pngPath)
+	if err != nil {
+		return "", fmt.Errorf("open PNG: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	img, err := png.Decode(in)
+	if err != nil {
+		return "", fmt.Errorf("decode PNG: %w", err)
+	}
+
+	jpegPath := strings.TrimSuffix(pngPath, ".png") + ".jpeg"
+
+	out, err := os.Create(jpegPath)
+	if err != nil {
+		return "", fmt.Errorf("create JPEG: %w", err)
+	}
+
+	err = jpeg.Encode(out, img, &jpeg.Options{Quality: jpegQuality})
+	if err != nil {
+		_ = out.Close()
+		return "", fmt.Errorf("encode JPEG: %w", err)
+	}
+
+	err = out.Close()
+	if err != nil {
+		return "", fmt.Errorf("close JPEG: %w", err)
+	}
+
+	err = os.Remove(pngPath)
+	if err != nil {
+		return "", fmt.Errorf("remove PNG: %w", err)
+	}
+
+	return jpegPath, nil
+}
+
+// run executes a "mutool run" script with the input path, the output path,
+// the path of the JSON arguments and the path of the JSON report as
+// arguments, then unmarshals the report.
+func (engine *MuTool) run(ctx context.Context, logger *zap.Logger, script string, args interface{}, inputPath, outputPath string, report interface{}) error {
+	dirPath := filepath.Dir(outputPath)
+	scriptPath := fmt.Sprintf("%s/%s.js", dirPath, uuid.New())
+	argsPath := fmt.Sprintf("%s/%s.json", dirPath, uuid.New())
+	reportPath := fmt.Sprintf("%s/%s.json", dirPath, uuid.New())
+
+	err := os.WriteFile(scriptPath, []byte(script), 0o600)
+	if err != nil {
+		return fmt.Errorf("write script file: %w", err)
+	}
+
+	b, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("marshal arguments: %w", err)
+	}
+
+	err = os.WriteFile(argsPath, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write arguments file: %w", err)
+	}
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, "run", scriptPath, inputPath, outputPath, argsPath, reportPath)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		return fmt.Errorf("read report file: %w", err)
+	}
+
+	err = json.Unmarshal(content, report)
+	if err != nil {
+		return fmt.Errorf("unmarshal report: %w", err)
+	}
+
+	return nil
+}
+
+// redactScript is the "mutool run" script which redacts a PDF. Its arguments
+// are the input path, the output path, the path of the JSON redactions and
+// the path of the JSON report. If an area is out of bounds or a text is not
+// found, it only writes the report.
+const redactScript = `
+var inputPath = scriptArgs[0];
+var outputPath = scriptArgs[1];
+var redactions = JSON.parse(read(scriptArgs[2]));
+var reportPath = scriptArgs[3];
+
+var doc = new PDFDocument(inputPath);
+var pageCount = doc.countPages();
+var rects = [];
+var report = { outOfBounds: [], notFound: [] };
+
+function addRect(index, rect) {
+	if (!rects[index]) {
+		rects[index] = [];
+	}
+	rects[index].push(rect);
+}
+
+function quadRect(quad) {
+	var rect = [quad[0], quad[1], quad[0], quad[1]];
+	for (var i = 2; i < 8; i += 2) {
+		rect[0] = Math.min(rect[0], quad[i]);
+		rect[1] = Math.min(rect[1], quad[i + 1]);
+		rect[2] = Math.max(rect[2], quad[i]);
+		rect[3] = Math.max(rect[3], quad[i + 1]);
+	}
+	return rect;
+}
+
+for (var i = 0; i < redactions.length; i++) {
+	var redaction = redactions[i];
+
+	if (redaction.page > pageCount) {
+		report.outOfBounds.push("page " + redaction.page + ": not within 1-" + pageCount);
+		continue;
+	}
+
+	if (redaction.text) {
+		var first = 0;
+		var last = pageCount - 1;
+		if (redaction.page > 0) {
+			first = last = redaction.page - 1;
+		}
+
+		var found = false;
+		for (var p = first; p <= last; p++) {
+			var hits = doc.loadPage(p).search(redaction.text);
+			for (var h = 0; h < hits.length; h++) {
+				// Depending on the MuPDF version, a hit is either a quad or
+				// an array of quads.
+				var quads = typeof hits[h][0] === "number" ? [hits[h]] : hits[h];
+				for (var q = 0; q < quads.length; q++) {
+					addRect(p, quadRect(quads[q]));
+					found = true;
+				}
+			}
+		}
+
+		if (!found) {
+			report.notFound.push(redaction.text);
+		}
+		continue;
+	}
+
+	var bounds = doc.loadPage(redaction.page - 1).getBounds();
+	var width = bounds[2] - bounds[0];
+	var height = bounds[3] - bounds[1];
+	var area = [redaction.x, redaction.y, redaction.x + redaction.width, redaction.y + redaction.height];
+
+	if (area[0] < 0 || area[1] < 0 || area[2] > width || area[3] > height) {
+		report.outOfBounds.push("page " + redaction.page + ": [" + area.join(" ") + "] not within [0 0 " + width + " " + height + "]");
+		continue;
+	}
+
+	addRect(redaction.page - 1, [bounds[0] + area[0], bounds[1] + area[1], bounds[0] + area[2], bounds[1] + area[3]]);
+}
+
+if (report.outOfBounds.length === 0 && report.notFound.length === 0) {
+	for (var index = 0; index < pageCount; index++) {
+		if (!rects[index]) {
+			continue;
+		}
+
+		var page = doc.loadPage(index);
+		for (var r = 0; r < rects[index].length; r++) {
+			page.createAnnotation("Redact").setRect(rects[index][r]);
+		}
+
+		// Black boxes, and the pixels of the images under the areas are
+		// blanked.
+		page.applyRedactions(true, 2);
+	}
+
+	doc.save(outputPath, "garbage=4,compress");
+}
+
+var buffer = new Buffer();
+buffer.write(JSON.stringify(report));
+buffer.save(reportPath);
+`
+
+// cropScript is the "mutool run" script which crops a PDF. Its arguments are
+// the input path, the output path, the path of the JSON crop and the path of
+// the JSON report. If an area is not within the media box of its page, it
+// only writes the report.
+const cropScript = `
+var inputPath = scriptArgs[0];
+var outputPath = scriptArgs[1];
+var crop = JSON.parse(read(scriptArgs[2]));
+var reportPath = scriptArgs[3];
+
+var doc = new PDFDocument(inputPath);
+var pageCount = doc.countPages();
+var boxes = [];
+var report = { outOfBounds: [] };
+
+function inherited(pageObj, key) {
+	for (var obj = pageObj; obj && !obj.isNull(); obj = obj.get("Parent")) {
+		var value = obj.get(key);
+		if (!value.isNull()) {
+			return value;
+		}
+	}
+	return null;
+}
+
+function rect(obj) {
+	var r = [Number(obj.get(0)), Number(obj.get(1)), Number(obj.get(2)), Number(obj.get(3))];
+	return [Math.min(r[0], r[2]), Math.min(r[1], r[3]), Math.max(r[0], r[2]), Math.max(r[1], r[3])];
+}
+
+// toPdf converts an area of a page as displayed, from its top-left corner,
+// to the PDF user space.
+function toPdf(area, cropBox, rotate) {
+	var corners = [[area[0], area[1]], [area[2], area[3]]];
+	var points = [];
+	for (var i = 0; i < corners.length; i++) {
+		var fx = corners[i][0];
+		var fy = corners[i][1];
+		switch (rotate) {
+		case 90:
+			points.push([cropBox[0] + fy, cropBox[1] + fx]);
+			break;
+		case 180:
+			points.push([cropBox[2] - fx, cropBox[1] + fy]);
+			break;
+		case 270:
+			points.push([cropBox[2] - fy, cropBox[3] - fx]);
+			break;
+		default:
+			points.push([cropBox[0] + fx, cropBox[3] - fy]);
+		}
+	}
+	return [
+		Math.min(points[0][0], points[1][0]), Math.min(points[0][1], points[1][1]),
+		Math.max(points[0][0], points[1][0]), Math.max(points[0][1], points[1][1])
+	];
+}
+
+function selected(page) {
+	if (!crop.pages || crop.pages.length === 0) {
+		return true;
+	}
+	for (var i = 0; i < crop.pages.length; i++) {
+		var r = crop.pages[i];
+		if (page >= r.first && (r.last === 0 || page <= r.last)) {
+			return true;
+		}
+	}
+	return false;
+}
+
+// contentArea returns the area of a page, as displayed, which is not white,
+// or null if the page is blank.
+function contentArea(page) {
+	var pixmap = page.toPixmap(Matrix.identity, ColorSpace.DeviceGray, false);
+	var width = pixmap.getWidth();
+	var height = pixmap.getHeight();
+
+	function blankRow(y) {
+		for (var x = 0; x < width; x++) {
+			if (pixmap.getSample(x, y, 0) < 250) {
+				return false;
+			}
+		}
+		return true;
+	}
+
+	function blankColumn(x, top, bottom) {
+		for (var y = top; y <= bottom; y++) {
+			if (pixmap.getSample(x, y, 0) < 250) {
+				return false;
+			}
+		}
+		return true;
+	}
+
+	var top = 0;
+	while (top < height && blankRow(top)) {
+		top++;
+	}
+	if (top === height) {
+		return null;
+	}
+
+	var bottom = height - 1;
+	while (blankRow(bottom)) {
+		bottom--;
+	}
+
+	var left = 0;
+	while (blankColumn(left, top, bottom)) {
+		left++;
+	}
+
+	var right = width - 1;
+	while (blankColumn(right, top, bottom)) {
+		right--;
+	}
+
+	return [left, top, right + 1, bottom + 1];
+}
+
+var areas = [];
+
+for (var i = 0; i < (crop.boxes || []).length; i++) {
+	var box = crop.boxes[i];
+	if (box.page > pageCount) {
+		report.outOfBounds.push("page " + box.page + ": not within 1-" + pageCount);
+		continue;
+	}
+	areas[box.page - 1] = [box.x, box.y, box.x + box.width, box.y + box.height];
+}
+
+if (!crop.boxes || crop.boxes.length === 0) {
+	for (var index = 0; index < pageCount; index++) {
+		if (!selected(index + 1)) {
+			continue;
+		}
+
+		var bounds = doc.loadPage(index).getBounds();
+		var width = bounds[2] - bounds[0];
+		var height = bounds[3] - bounds[1];
+		var margin = crop.margin || 0;
+
+		if (!crop.autoTrim) {
+			areas[index] = [margin, margin, width - margin, height - margin];
+			continue;
+		}
+
+		var content = contentArea(doc.loadPage(index));
+		if (!content) {
+			continue;
+		}
+
+		areas[index] = [
+			Math.max(0, content[0] - margin), Math.max(0, content[1] - margin),
+			Math.min(width, content[2] + margin), Math.min(height, content[3] + margin)
+		];
+	}
+}
+
+for (var index = 0; index < pageCount; index++) {
+	var area = areas[index];
+	if (!area) {
+		continue;
+	}
+
+	var pageObj = doc.findPage(index);
+	var mediaBox = rect(inherited(pageObj, "MediaBox"));
+	var cropBoxObj = inherited(pageObj, "CropBox");
+	var cropBox = cropBoxObj ? rect(cropBoxObj) : mediaBox;
+	var rotateObj = inherited(pageObj, "Rotate");
+	var rotate = rotateObj ? ((Number(rotateObj) % 360) + 360) % 360 : 0;
+	var box = toPdf(area, cropBox, rotate);
+
+	if (area[2] <= area[0] || area[3] <= area[1] ||
+		box[0] < mediaBox[0] - 0.01 || box[1] < mediaBox[1] - 0.01 ||
+		box[2] > mediaBox[2] + 0.01 || box[3] > mediaBox[3] + 0.01) {
+		report.outOfBounds.push("page " + (index + 1) + ": [" + box.join(" ") + "] not within [" + mediaBox.join(" ") + "]");
+		continue;
+	}
+
+	boxes[index] = box;
+}
+
+if (report.outOfBounds.length === 0) {
+	for (var index = 0; index < pageCount; index++) {
+		if (!boxes[index]) {
+			continue;
+		}
+
+		var cropBoxArray = doc.newArray();
+		for (var k = 0; k < 4; k++) {
+			cropBoxArray.push(boxes[index][k]);
+		}
+		doc.findPage(index).put("CropBox", cropBoxArray);
+	}
+
+	doc.save(outputPath, "garbage,compress");
+}
+
+var buffer = new Buffer();
+buffer.write(JSON.stringify(report));
+buffer.save(reportPath);
+`
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*MuTool)(nil)
+	_ gotenberg.Provisioner = (*MuTool)(nil)
+	_ gotenberg.Validator   = (*MuTool)(nil)
+	_ gotenberg.PdfEngine   = (*MuTool)(nil)
+)
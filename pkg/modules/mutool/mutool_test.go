@@ -0,0 +1,481 @@
+package mutool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestMuTool_Descriptor(t *testing.T) {
+	descriptor := new(MuTool).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(MuTool))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestMuTool_Provision(t *testing.T) {
+	engine := new(MuTool)
+	ctx := gotenberg.NewContext(gotenberg.ParsedFlags{}, nil)
+
+	err := engine.Provision(ctx)
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+}
+
+func TestMuTool_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		binPath     string
+		expectError bool
+	}{
+		{
+			scenario:    "empty bin path",
+			binPath:     "",
+			expectError: true,
+		},
+		{
+			scenario:    "bin path does not exist",
+			binPath:     "/foo",
+			expectError: true,
+		},
+		{
+			scenario:    "validate success",
+			binPath:     os.Getenv("MUTOOL_BIN_PATH"),
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(MuTool)
+			engine.binPath = tc.binPath
+			err := engine.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestMuTool_Merge(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.Merge(context.TODO(), zap.NewNop(), nil, "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_Convert(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.Convert(context.TODO(), zap.NewNop(), gotenberg.PdfFormats{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_Decrypt(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.Decrypt(context.TODO(), zap.NewNop(), "", "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_ExtractText(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.ExtractText(context.TODO(), zap.NewNop(), 0, 0, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_Ocr(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.Ocr(context.TODO(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_Normalize(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.Normalize(context.TODO(), zap.NewNop(), time.Time{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_SetOutline(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.SetOutline(context.TODO(), zap.NewNop(), nil, false, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_ReadOutline(t *testing.T) {
+	engine := new(MuTool)
+	_, err := engine.ReadOutline(context.TODO(), zap.NewNop(), "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_Redact(t *testing.T) {
+	for _, tc := range []struct {
+		scenario                string
+		ctx                     context.Context
+		redactions              []gotenberg.PdfRedaction
+		inputPath               string
+		expectError             bool
+		expectOutOfBoundsError  bool
+		expectTextNotFoundError bool
+	}{
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid input path",
+			ctx:         context.TODO(),
+			redactions:  []gotenberg.PdfRedaction{{Page: 1, Width: 10, Height: 10}},
+			inputPath:   "foo",
+			expectError: true,
+		},
+		{
+			scenario:               "page out of bounds",
+			ctx:                    context.TODO(),
+			redactions:             []gotenberg.PdfRedaction{{Page: 99, Width: 10, Height: 10}},
+			inputPath:              "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectError:            true,
+			expectOutOfBoundsError: true,
+		},
+		{
+			scenario:               "area out of bounds",
+			ctx:                    context.TODO(),
+			redactions:             []gotenberg.PdfRedaction{{Page: 1, X: 500, Y: 10, Width: 200, Height: 10}},
+			inputPath:              "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectError:            true,
+			expectOutOfBoundsError: true,
+		},
+		{
+			scenario:                "text not found",
+			ctx:                     context.TODO(),
+			redactions:              []gotenberg.PdfRedaction{{Text: "foo bar baz"}},
+			inputPath:               "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectError:             true,
+			expectTextNotFoundError: true,
+		},
+		{
+			scenario: "success",
+			ctx:      context.TODO(),
+			redactions: []gotenberg.PdfRedaction{
+				{Page: 1, X: 10, Y: 10, Width: 100, Height: 50},
+				{Text: "Gutenberg"},
+			},
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(MuTool)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			err = engine.Redact(tc.ctx, zap.NewNop(), tc.redactions, tc.inputPath, outputDir+"/foo.pdf")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			var outOfBoundsErr *gotenberg.PdfRedactionOutOfBoundsError
+			if tc.expectOutOfBoundsError && !errors.As(err, &outOfBoundsErr) {
+				t.Fatalf("expected error %T, but got: %v", outOfBoundsErr, err)
+			}
+
+			if tc.expectTextNotFoundError && !errors.Is(err, gotenberg.ErrPdfRedactionTextNotFound) {
+				t.Fatalf("expected error %v, but got: %v", gotenberg.ErrPdfRedactionTextNotFound, err)
+			}
+		})
+	}
+}
+
+func TestMuTool_StampPageNumbers(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.StampPageNumbers(context.TODO(), zap.NewNop(), gotenberg.PdfPageNumbers{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_Crop(t *testing.T) {
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    context.Context
+		crop                   gotenberg.PdfCrop
+		inputPath              string
+		expectError            bool
+		expectOutOfBoundsError bool
+	}{
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid input path",
+			ctx:         context.TODO(),
+			crop:        gotenberg.PdfCrop{Margin: 10},
+			inputPath:   "foo",
+			expectError: true,
+		},
+		{
+			scenario:               "page out of bounds",
+			ctx:                    context.TODO(),
+			crop:                   gotenberg.PdfCrop{Boxes: []gotenberg.PdfCropBox{{Page: 99, Width: 10, Height: 10}}},
+			inputPath:              "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectError:            true,
+			expectOutOfBoundsError: true,
+		},
+		{
+			scenario:               "area out of bounds",
+			ctx:                    context.TODO(),
+			crop:                   gotenberg.PdfCrop{Boxes: []gotenberg.PdfCropBox{{Page: 1, X: 500, Y: 10, Width: 2000, Height: 10}}},
+			inputPath:              "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectError:            true,
+			expectOutOfBoundsError: true,
+		},
+		{
+			scenario:               "margin too large",
+			ctx:                    context.TODO(),
+			crop:                   gotenberg.PdfCrop{Margin: 1000},
+			inputPath:              "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectError:            true,
+			expectOutOfBoundsError: true,
+		},
+		{
+			scenario:  "success (boxes)",
+			ctx:       context.TODO(),
+			crop:      gotenberg.PdfCrop{Boxes: []gotenberg.PdfCropBox{{Page: 1, X: 10, Y: 10, Width: 100, Height: 50}}},
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+		},
+		{
+			scenario:  "success (margin)",
+			ctx:       context.TODO(),
+			crop:      gotenberg.PdfCrop{Margin: 10, Pages: []gotenberg.PdfPageRange{{First: 2}}},
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+		},
+		{
+			scenario:  "success (autoTrim)",
+			ctx:       context.TODO(),
+			crop:      gotenberg.PdfCrop{AutoTrim: true, Margin: 5},
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(MuTool)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			err = engine.Crop(tc.ctx, zap.NewNop(), tc.crop, tc.inputPath, outputDir+"/foo.pdf")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			var outOfBoundsErr *gotenberg.PdfCropOutOfBoundsError
+			if tc.expectOutOfBoundsError && !errors.As(err, &outOfBoundsErr) {
+				t.Fatalf("expected error %T, but got: %v", outOfBoundsErr, err)
+			}
+		})
+	}
+}
+
+func TestMuTool_ImportImages(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.ImportImages(context.TODO(), zap.NewNop(), nil, "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_Check(t *testing.T) {
+	engine := new(MuTool)
+	_, err := engine.Check(context.TODO(), zap.NewNop(), "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_Repair(t *testing.T) {
+	engine := new(MuTool)
+	err := engine.Repair(context.TODO(), zap.NewNop(), "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestMuTool_Rasterize(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		ctx               context.Context
+		raster            gotenberg.PdfRaster
+		inputPath         string
+		expectError       bool
+		expectOutputPaths int
+		expectExtension   string
+	}{
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			raster:      gotenberg.PdfRaster{Format: gotenberg.PdfRasterPng},
+			expectError: true,
+		},
+		{
+			scenario:    "unknown format",
+			ctx:         context.TODO(),
+			raster:      gotenberg.PdfRaster{Format: "gif"},
+			inputPath:   "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid input path",
+			ctx:         context.TODO(),
+			raster:      gotenberg.PdfRaster{Format: gotenberg.PdfRasterPng},
+			inputPath:   "foo",
+			expectError: true,
+		},
+		{
+			scenario:          "success (PNG)",
+			ctx:               context.TODO(),
+			raster:            gotenberg.PdfRaster{Format: gotenberg.PdfRasterPng, Dpi: 72},
+			inputPath:         "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectOutputPaths: 3,
+			expectExtension:   ".png",
+		},
+		{
+			scenario:          "success (JPEG)",
+			ctx:               context.TODO(),
+			raster:            gotenberg.PdfRaster{Format: gotenberg.PdfRasterJpeg, Dpi: 72},
+			inputPath:         "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectOutputPaths: 3,
+			expectExtension:   ".jpeg",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(MuTool)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			outputPaths, err := engine.Rasterize(tc.ctx, zap.NewNop(), tc.raster, tc.inputPath, outputDir+"/foo")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if len(outputPaths) != tc.expectOutputPaths {
+				t.Fatalf("expected %d images but got %d", tc.expectOutputPaths, len(outputPaths))
+			}
+
+			for _, outputPath := range outputPaths {
+				if filepath.Ext(outputPath) != tc.expectExtension {
+					t.Errorf("expected '%s' extension but got '%s'", tc.expectExtension, outputPath)
+				}
+			}
+		})
+	}
+}
+
+func TestMuTool_SplitPages(t *testing.T) {
+	engine := new(MuTool)
+	_, err := engine.SplitPages(context.TODO(), zap.NewNop(), "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
@@ -0,0 +1,10 @@
+// Package ocrmypdf provides an implementation of the gotenberg.PdfEngine
+// interface using the OCRmyPDF command-line tool, which relies on Tesseract.
+// This package allows for adding a searchable text layer to scanned PDF files,
+// but does not support other PDF operations. As OCR is resource intensive,
+// the feature is disabled by default. The paths to the OCRmyPDF and Tesseract
+// binaries must be specified using the OCRMYPDF_BIN_PATH and
+// TESSERACT_BIN_PATH environment variables.
+//
+// See: https://github.com/ocrmypdf/OCRmyPDF.
+package ocrmypdf
@@ -0,0 +1,314 @@
+package ocrmypdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(OcrMyPdf))
+}
+
+// OcrMyPdf abstracts the CLI tool OCRmyPDF and implements the
+// [gotenberg.PdfEngine] interface.
+type OcrMyPdf struct {
+	binPath          string
+	tesseractBinPath string
+	enable           bool
+	maxConcurrency   int
+	languages        []string
+	semaphore        chan struct{}
+}
+
+// Descriptor returns a [OcrMyPdf]'s module descriptor.
+func (engine *OcrMyPdf) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "ocrmypdf",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("ocrmypdf", flag.ExitOnError)
+			fs.Bool("ocrmypdf-enable", false, "Enable the OCR feature, which is resource intensive")
+			fs.Int("ocrmypdf-max-concurrency", 1, "Set the maximum number of PDFs processed by OCR at the same time")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(OcrMyPdf) },
+	}
+}
+
+// Provision sets the modules properties.
+func (engine *OcrMyPdf) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	engine.enable = flags.MustBool("ocrmypdf-enable")
+	engine.maxConcurrency = flags.MustInt("ocrmypdf-max-concurrency")
+
+	if !engine.enable {
+		return nil
+	}
+
+	binPath, ok := os.LookupEnv("OCRMYPDF_BIN_PATH")
+	if !ok {
+		return errors.New("OCRMYPDF_BIN_PATH environment variable is not set")
+	}
+
+	tesseractBinPath, ok := os.LookupEnv("TESSERACT_BIN_PATH")
+	if !ok {
+		return errors.New("TESSERACT_BIN_PATH environment variable is not set")
+	}
+
+	engine.binPath = binPath
+	engine.tesseractBinPath = tesseractBinPath
+
+	if engine.maxConcurrency > 0 {
+		engine.semaphore = make(chan struct{}, engine.maxConcurrency)
+	}
+
+	return nil
+}
+
+// Validate validates the module properties. It also lists the languages
+// installed for Tesseract.
+func (engine *OcrMyPdf) Validate() error {
+	if !engine.enable {
+		return nil
+	}
+
+	var err error
+
+	if engine.maxConcurrency < 1 {
+		err = multierr.Append(err, errors.New("max concurrency must be at least 1"))
+	}
+
+	_, statErr := os.Stat(engine.binPath)
+	if os.IsNotExist(statErr) {
+		err = multierr.Append(err, fmt.Errorf("OCRmyPDF binary path does not exist: %w", statErr))
+	}
+
+	_, statErr = os.Stat(engine.tesseractBinPath)
+	if os.IsNotExist(statErr) {
+		err = multierr.Append(err, fmt.Errorf("Tesseract binary path does not exist: %w", statErr))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	output, err := exec.Command(engine.tesseractBinPath, "--list-langs").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("list Tesseract languages: %w", err)
+	}
+
+	engine.languages = parseLanguages(string(output))
+	if len(engine.languages) == 0 {
+		return errors.New("no Tesseract language data installed")
+	}
+
+	return nil
+}
+
+// SystemMessages returns one message with the installed Tesseract languages,
+// if the OCR feature is enabled.
+func (engine *OcrMyPdf) SystemMessages() []string {
+	if !engine.enable {
+		return []string{"OCR feature disabled"}
+	}
+
+	return []string{
+		fmt.Sprintf("OCR languages: %s", strings.Join(engine.languages, " ")),
+	}
+}
+
+// Capabilities returns the installed Tesseract languages and the maximum
+// number of PDFs processed at the same time, if the OCR feature is enabled.
+func (engine *OcrMyPdf) Capabilities() (map[string]interface{}, error) {
+	if !engine.enable {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"languages":      engine.languages,
+		"maxConcurrency": engine.maxConcurrency,
+	}, nil
+}
+
+// Merge is not available in this implementation.
+func (engine *OcrMyPdf) Merge(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+	return fmt.Errorf("merge PDFs with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Convert is not available in this implementation.
+func (engine *OcrMyPdf) Convert(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+	return fmt.Errorf("convert PDF to '%+v' with OCRmyPDF: %w", formats, gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Decrypt is not available in this implementation.
+func (engine *OcrMyPdf) Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error {
+	return fmt.Errorf("decrypt PDF with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ExtractText is not available in this implementation.
+func (engine *OcrMyPdf) ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+	return fmt.Errorf("extract text from PDF with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Ocr adds a searchable text layer to a PDF. It waits for a free slot if the
+// maximum number of concurrent OCR processes is reached.
+func (engine *OcrMyPdf) Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error {
+	if !engine.enable {
+		return fmt.Errorf("OCR PDF with OCRmyPDF (disabled): %w", gotenberg.ErrPdfEngineMethodNotSupported)
+	}
+
+	var missingLanguages []string
+	for _, language := range languages {
+		if !engine.isLanguageInstalled(language) {
+			missingLanguages = append(missingLanguages, language)
+		}
+	}
+
+	if len(missingLanguages) > 0 {
+		return fmt.Errorf("OCR PDF with OCRmyPDF, missing %s: %w", missingLanguages, gotenberg.ErrOcrLanguagesNotInstalled)
+	}
+
+	if ctx == nil {
+		return errors.New("nil context")
+	}
+
+	select {
+	case engine.semaphore <- struct{}{}:
+		defer func() {
+			<-engine.semaphore
+		}()
+	case <-ctx.Done():
+		return fmt.Errorf("wait for an OCR slot: %w", ctx.Err())
+	}
+
+	args := []string{
+		// Pages with text are left untouched.
+		"--skip-text",
+		// Keep the appearance of the PDF: no PDF/A conversion nor
+		// optimization.
+		"--output-type", "pdf",
+		"--optimize", "0",
+		"--quiet",
+	}
+
+	if len(languages) > 0 {
+		args = append(args, "--language", strings.Join(languages, "+"))
+	}
+
+	args = append(args, inputPath, outputPath)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, args...)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("OCR PDF with OCRmyPDF: %w", err)
+}
+
+// Normalize is not available in this implementation.
+func (engine *OcrMyPdf) Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+	return fmt.Errorf("normalize PDF with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SetOutline is not available in this implementation.
+func (engine *OcrMyPdf) SetOutline(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+	return fmt.Errorf("set PDF outline with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ReadOutline is not available in this implementation.
+func (engine *OcrMyPdf) ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+	return gotenberg.PdfOutline{}, fmt.Errorf("read PDF outline with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Redact is not available in this implementation.
+func (engine *OcrMyPdf) Redact(ctx context.Context, logger *zap.Logger, redactions []gotenberg.PdfRedaction, inputPath, outputPath string) error {
+	return fmt.Errorf("redact PDF with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// StampPageNumbers is not available in this implementation.
+func (engine *OcrMyPdf) StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+	return fmt.Errorf("stamp page numbers with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Crop is not available in this implementation.
+func (engine *OcrMyPdf) Crop(ctx context.Context, logger *zap.Logger, crop gotenberg.PdfCrop, inputPath, outputPath string) error {
+	return fmt.Errorf("crop PDF with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ImportImages is not available in this implementation.
+func (engine *OcrMyPdf) ImportImages(ctx context.Context, logger *zap.Logger, images []gotenberg.PdfImage, outputPath string) error {
+	return fmt.Errorf("import images with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Check is not available in this implementation.
+func (engine *OcrMyPdf) Check(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfCheckReport, error) {
+	return gotenberg.PdfCheckReport{}, fmt.Errorf("check PDF with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Repair is not available in this implementation.
+func (engine *OcrMyPdf) Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	return fmt.Errorf("repair PDF with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Rasterize is not available in this implementation.
+func (engine *OcrMyPdf) Rasterize(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("rasterize PDF with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SplitPages is not available in this implementation.
+func (engine *OcrMyPdf) SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("split PDF pages with OCRmyPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+func (engine *OcrMyPdf) isLanguageInstalled(language string) bool {
+	for _, installed := range engine.languages {
+		if installed == language {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseLanguages parses the output of the "tesseract --list-langs" command.
+func parseLanguages(output string) []string {
+	var languages []string
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "List of available languages") {
+			continue
+		}
+
+		languages = append(languages, line)
+	}
+
+	return languages
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module         = (*OcrMyPdf)(nil)
+	_ gotenberg.Provisioner    = (*OcrMyPdf)(nil)
+	_ gotenberg.Validator      = (*OcrMyPdf)(nil)
+	_ gotenberg.SystemLogger   = (*OcrMyPdf)(nil)
+	_ gotenberg.PdfEngine      = (*OcrMyPdf)(nil)
+	_ api.CapabilitiesProvider = (*OcrMyPdf)(nil)
+)
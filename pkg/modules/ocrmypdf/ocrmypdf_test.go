@@ -0,0 +1,325 @@
+package ocrmypdf
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestOcrMyPdf_Descriptor(t *testing.T) {
+	descriptor := new(OcrMyPdf).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(OcrMyPdf))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestOcrMyPdf_Provision(t *testing.T) {
+	engine := new(OcrMyPdf)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(OcrMyPdf).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := engine.Provision(ctx)
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+}
+
+func TestOcrMyPdf_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		engine      *OcrMyPdf
+		expectError bool
+	}{
+		{
+			scenario: "OCR disabled",
+			engine:   new(OcrMyPdf),
+		},
+		{
+			scenario: "invalid max concurrency",
+			engine: &OcrMyPdf{
+				enable:           true,
+				maxConcurrency:   0,
+				binPath:          "/foo",
+				tesseractBinPath: "/bar",
+			},
+			expectError: true,
+		},
+		{
+			scenario: "bin paths do not exist",
+			engine: &OcrMyPdf{
+				enable:           true,
+				maxConcurrency:   1,
+				binPath:          "/foo",
+				tesseractBinPath: "/bar",
+			},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.engine.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestOcrMyPdf_SystemMessages(t *testing.T) {
+	engine := &OcrMyPdf{
+		enable:    true,
+		languages: []string{"eng", "deu"},
+	}
+
+	messages := engine.SystemMessages()
+	if len(messages) != 1 {
+		t.Errorf("expected one and only one message, but got %d", len(messages))
+	}
+
+	expect := "OCR languages: eng deu"
+	if messages[0] != expect {
+		t.Errorf("expected message '%s', but got '%s'", expect, messages[0])
+	}
+}
+
+func TestOcrMyPdf_Merge(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.Merge(context.TODO(), zap.NewNop(), nil, "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_Convert(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.Convert(context.TODO(), zap.NewNop(), gotenberg.PdfFormats{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_Decrypt(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.Decrypt(context.TODO(), zap.NewNop(), "", "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_ExtractText(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.ExtractText(context.TODO(), zap.NewNop(), 0, 0, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_Ocr(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		engine      *OcrMyPdf
+		ctx         context.Context
+		languages   []string
+		expectError bool
+		expectedErr error
+	}{
+		{
+			scenario:    "OCR disabled",
+			engine:      new(OcrMyPdf),
+			ctx:         context.TODO(),
+			expectError: true,
+			expectedErr: gotenberg.ErrPdfEngineMethodNotSupported,
+		},
+		{
+			scenario: "language not installed",
+			engine: &OcrMyPdf{
+				enable:    true,
+				languages: []string{"eng"},
+				semaphore: make(chan struct{}, 1),
+			},
+			ctx:         context.TODO(),
+			languages:   []string{"eng", "deu"},
+			expectError: true,
+			expectedErr: gotenberg.ErrOcrLanguagesNotInstalled,
+		},
+		{
+			scenario: "invalid context",
+			engine: &OcrMyPdf{
+				enable:    true,
+				languages: []string{"eng"},
+				semaphore: make(chan struct{}, 1),
+			},
+			ctx:         nil,
+			languages:   []string{"eng"},
+			expectError: true,
+		},
+		{
+			scenario: "no free slot before context is done",
+			engine: func() *OcrMyPdf {
+				engine := &OcrMyPdf{
+					enable:    true,
+					languages: []string{"eng"},
+					semaphore: make(chan struct{}, 1),
+				}
+				engine.semaphore <- struct{}{}
+
+				return engine
+			}(),
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx
+			}(),
+			languages:   []string{"eng"},
+			expectError: true,
+			expectedErr: context.Canceled,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.engine.Ocr(tc.ctx, zap.NewNop(), tc.languages, "", "")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestOcrMyPdf_Normalize(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.Normalize(context.TODO(), zap.NewNop(), time.Time{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_SetOutline(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.SetOutline(context.TODO(), zap.NewNop(), nil, false, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_ReadOutline(t *testing.T) {
+	engine := new(OcrMyPdf)
+	_, err := engine.ReadOutline(context.TODO(), zap.NewNop(), "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_Redact(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.Redact(context.TODO(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_StampPageNumbers(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.StampPageNumbers(context.TODO(), zap.NewNop(), gotenberg.PdfPageNumbers{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_Crop(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.Crop(context.TODO(), zap.NewNop(), gotenberg.PdfCrop{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_ImportImages(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.ImportImages(context.TODO(), zap.NewNop(), nil, "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_Check(t *testing.T) {
+	engine := new(OcrMyPdf)
+	_, err := engine.Check(context.TODO(), zap.NewNop(), "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_Repair(t *testing.T) {
+	engine := new(OcrMyPdf)
+	err := engine.Repair(context.TODO(), zap.NewNop(), "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestParseLanguages(t *testing.T) {
+	actual := parseLanguages("List of available languages in \"/usr/share/tesseract-ocr/5/tessdata/\" (3):\neng\nosd\ndeu\n")
+	expect := []string{"eng", "osd", "deu"}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got: %+v", expect, actual)
+	}
+}
+
+func TestOcrMyPdf_Rasterize(t *testing.T) {
+	engine := new(OcrMyPdf)
+	_, err := engine.Rasterize(context.TODO(), zap.NewNop(), gotenberg.PdfRaster{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestOcrMyPdf_SplitPages(t *testing.T) {
+	engine := new(OcrMyPdf)
+	_, err := engine.SplitPages(context.TODO(), zap.NewNop(), "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
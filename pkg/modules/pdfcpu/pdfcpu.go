@@ -3,10 +3,17 @@ package pdfcpu
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
 	pdfcpuLog "github.com/pdfcpu/pdfcpu/pkg/log"
+	pdfcpuCore "github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	pdfcpuTypes "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 	"go.uber.org/zap"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
@@ -54,6 +61,413 @@ func (engine *PdfCpu) Convert(ctx context.Context, logger *zap.Logger, formats g
 	return fmt.Errorf("convert PDF to '%+v' with PDFcpu: %w", formats, gotenberg.ErrPdfEngineMethodNotSupported)
 }
 
+// Decrypt is not available in this implementation.
+func (engine *PdfCpu) Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error {
+	return fmt.Errorf("decrypt PDF with PDFcpu: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ExtractText is not available in this implementation.
+func (engine *PdfCpu) ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+	return fmt.Errorf("extract text from PDF with PDFcpu: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Ocr is not available in this implementation.
+func (engine *PdfCpu) Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error {
+	return fmt.Errorf("OCR PDF with PDFcpu: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Normalize is not available in this implementation.
+func (engine *PdfCpu) Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+	return fmt.Errorf("normalize PDF with PDFcpu: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SetOutline writes an outline to a PDF. If replace is false, the entries are
+// merged with the existing outline, ordered by target page.
+func (engine *PdfCpu) SetOutline(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+	pageCount, err := pdfcpuAPI.PageCountFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("count pages with PDFcpu: %w", err)
+	}
+
+	bookmarks, outOfRange := outlineBookmarks(entries, pageCount, 1)
+	if len(outOfRange) > 0 {
+		return fmt.Errorf("set PDF outline with PDFcpu: %w", &gotenberg.PdfOutlineOutOfRangeError{
+			PageCount: pageCount,
+			Entries:   outOfRange,
+		})
+	}
+
+	if !replace {
+		existing, err := engine.bookmarks(inputPath)
+		if err != nil {
+			return fmt.Errorf("read PDF outline with PDFcpu: %w", err)
+		}
+
+		bookmarks = append(existing, bookmarks...)
+		sort.SliceStable(bookmarks, func(i, j int) bool {
+			return bookmarks[i].PageFrom < bookmarks[j].PageFrom
+		})
+	}
+
+	err = pdfcpuAPI.AddBookmarksFile(inputPath, outputPath, bookmarks, true, engine.conf)
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("set PDF outline with PDFcpu: %w", err)
+}
+
+// ReadOutline returns the outline of a PDF.
+func (engine *PdfCpu) ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+	pageCount, err := pdfcpuAPI.PageCountFile(inputPath)
+	if err != nil {
+		return gotenberg.PdfOutline{}, fmt.Errorf("count pages with PDFcpu: %w", err)
+	}
+
+	bookmarks, err := engine.bookmarks(inputPath)
+	if err != nil {
+		return gotenberg.PdfOutline{}, fmt.Errorf("read PDF outline with PDFcpu: %w", err)
+	}
+
+	return gotenberg.PdfOutline{
+		PageCount: pageCount,
+		Entries:   outlineEntries(bookmarks),
+	}, nil
+}
+
+// Redact is not available in this implementation.
+func (engine *PdfCpu) Redact(ctx context.Context, logger *zap.Logger, redactions []gotenberg.PdfRedaction, inputPath, outputPath string) error {
+	return fmt.Errorf("redact PDF with PDFcpu: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// StampPageNumbers writes page numbers on top of the pages of a PDF. The
+// total is the number of the last numbered page, so that it remains correct
+// whatever the excluded pages.
+func (engine *PdfCpu) StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+	pageCount, err := pdfcpuAPI.PageCountFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("count pages with PDFcpu: %w", err)
+	}
+
+	var pages []int
+	for page := 1; page <= pageCount; page++ {
+		if !numbers.Excluded(page) {
+			pages = append(pages, page)
+		}
+	}
+
+	if len(pages) == 0 {
+		logger.Debug("no page to number, skipping")
+
+		b, err := os.ReadFile(inputPath)
+		if err != nil {
+			return fmt.Errorf("read PDF: %w", err)
+		}
+
+		err = os.WriteFile(outputPath, b, 0o600)
+		if err != nil {
+			return fmt.Errorf("write PDF: %w", err)
+		}
+
+		return nil
+	}
+
+	total := numbers.StartAt + len(pages) - 1
+	description := pageNumberDescription(numbers.Position)
+	watermarks := make(map[int]*pdfcpuConfig.Watermark, len(pages))
+
+	for i, page := range pages {
+		watermark, err := pdfcpuAPI.TextWatermark(numbers.Text(numbers.StartAt+i, total), description, true, false, pdfcpuTypes.POINTS)
+		if err != nil {
+			return fmt.Errorf("create page number with PDFcpu: %w", err)
+		}
+
+		watermarks[page] = watermark
+	}
+
+	err = pdfcpuAPI.AddWatermarksMapFile(inputPath, outputPath, watermarks, engine.conf)
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("stamp page numbers with PDFcpu: %w", err)
+}
+
+// Crop is not available in this implementation.
+func (engine *PdfCpu) Crop(ctx context.Context, logger *zap.Logger, crop gotenberg.PdfCrop, inputPath, outputPath string) error {
+	return fmt.Errorf("crop PDF with PDFcpu: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ImportImages creates a PDF with one page per image. The supported images
+// are PNG, JPEG, TIFF and WebP.
+func (engine *PdfCpu) ImportImages(ctx context.Context, logger *zap.Logger, images []gotenberg.PdfImage, outputPath string) error {
+	conf := *engine.conf
+	conf.Cmd = pdfcpuConfig.IMPORTIMAGES
+
+	pdfCtx, err := pdfcpuCore.CreateContextWithXRefTable(&conf, pdfcpuTypes.PaperSize["A4"])
+	if err != nil {
+		return fmt.Errorf("create PDF with PDFcpu: %w", err)
+	}
+
+	pagesIndRef, err := pdfCtx.Pages()
+	if err != nil {
+		return fmt.Errorf("get page tree with PDFcpu: %w", err)
+	}
+
+	pagesDict, err := pdfCtx.DereferenceDict(*pagesIndRef)
+	if err != nil {
+		return fmt.Errorf("get page tree with PDFcpu: %w", err)
+	}
+
+	for _, image := range images {
+		imp, err := importConfig(image)
+		if err != nil {
+			return fmt.Errorf("import '%s' with PDFcpu: %w", filepath.Base(image.Path), err)
+		}
+
+		err = importImage(pdfCtx, pagesIndRef, pagesDict, imp, image.Path)
+		if err != nil {
+			return fmt.Errorf("import '%s' with PDFcpu: %w", filepath.Base(image.Path), err)
+		}
+	}
+
+	if conf.ValidationMode != pdfcpuConfig.ValidationNone {
+		err = pdfcpuAPI.ValidateContext(pdfCtx)
+		if err != nil {
+			return fmt.Errorf("validate PDF with PDFcpu: %w", err)
+		}
+	}
+
+	err = pdfcpuAPI.WriteContextFile(pdfCtx, outputPath)
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("write PDF with PDFcpu: %w", err)
+}
+
+// Check verifies the structure of a PDF thanks to the validation of PDFcpu.
+// An error in the strict mode, i.e., full compliance with the PDF
+// specification, is a warning if the relaxed mode, i.e., the leniency of
+// most PDF readers, accepts the PDF.
+func (engine *PdfCpu) Check(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfCheckReport, error) {
+	var report gotenberg.PdfCheckReport
+
+	conf := *engine.conf
+	conf.ValidationMode = pdfcpuConfig.ValidationStrict
+
+	strictErr := pdfcpuAPI.ValidateFile(inputPath, &conf)
+	if strictErr == nil {
+		return report, nil
+	}
+
+	conf.ValidationMode = pdfcpuConfig.ValidationRelaxed
+
+	relaxedErr := pdfcpuAPI.ValidateFile(inputPath, &conf)
+	if relaxedErr != nil {
+		report.Errors = []string{checkMessage(relaxedErr, inputPath)}
+		return report, nil
+	}
+
+	report.Warnings = []string{checkMessage(strictErr, inputPath)}
+
+	return report, nil
+}
+
+// Repair rewrites a PDF. PDFcpu reads a PDF in the relaxed mode, and
+// reconstructs its cross-reference table if need be.
+func (engine *PdfCpu) Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	conf := *engine.conf
+	conf.ValidationMode = pdfcpuConfig.ValidationRelaxed
+
+	err := pdfcpuAPI.OptimizeFile(inputPath, outputPath, &conf)
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("repair PDF with PDFcpu: %s: %w", checkMessage(err, inputPath), gotenberg.ErrPdfNotRepairable)
+}
+
+// Rasterize is not available in this implementation.
+func (engine *PdfCpu) Rasterize(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("rasterize PDF with PDFcpu: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SplitPages is not available in this implementation.
+func (engine *PdfCpu) SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("split PDF pages with PDFcpu: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// checkMessage returns the message of a PDFcpu error, with the filename
+// instead of the path of the PDF.
+func checkMessage(err error, inputPath string) string {
+	return strings.ReplaceAll(err.Error(), inputPath, filepath.Base(inputPath))
+}
+
+// importConfig returns the PDFcpu import configuration of an image. Without
+// a page size, the page takes the dimensions of the image.
+func importConfig(image gotenberg.PdfImage) (*pdfcpuCore.Import, error) {
+	imp := pdfcpuCore.DefaultImportConfig()
+	if image.PageSize == "" {
+		return imp, nil
+	}
+
+	dim, _, err := pdfcpuTypes.ParsePageFormat(image.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("page size '%s': %w", image.PageSize, gotenberg.ErrPdfPageSizeNotSupported)
+	}
+
+	imp.PageDim = dim
+	imp.PageSize = image.PageSize
+	imp.UserDim = true
+	imp.Pos = pdfcpuTypes.Center
+	imp.Scale = 1
+
+	switch image.Fit {
+	case "", gotenberg.PdfImageFitContain:
+	case gotenberg.PdfImageFitNone:
+		imp.ScaleAbs = true
+	default:
+		return nil, fmt.Errorf("unknown fit '%s'", image.Fit)
+	}
+
+	return imp, nil
+}
+
+// importImage appends a page with the given image to the page tree.
+func importImage(pdfCtx *pdfcpuConfig.Context, pagesIndRef *pdfcpuTypes.IndirectRef, pagesDict pdfcpuTypes.Dict, imp *pdfcpuCore.Import, imagePath string) error {
+	f, err := os.Open(imagePath)
+	if err != nil {
+		return fmt.Errorf("open image: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	indRef, err := pdfcpuCore.NewPageForImage(pdfCtx.XRefTable, f, pagesIndRef, imp)
+	if err != nil {
+		return fmt.Errorf("create page: %w", err)
+	}
+
+	err = pdfcpuConfig.AppendPageTree(indRef, 1, pagesDict)
+	if err != nil {
+		return fmt.Errorf("append page: %w", err)
+	}
+
+	pdfCtx.PageCount++
+
+	return nil
+}
+
+// pageNumberDescription returns the PDFcpu description of a page number at
+// the given position, 10 points high and 30 points from the edges of the
+// page.
+func pageNumberDescription(position string) string {
+	var dx, dy int
+
+	switch {
+	case strings.HasSuffix(position, "-left"):
+		dx = 30
+	case strings.HasSuffix(position, "-right"):
+		dx = -30
+	}
+
+	switch {
+	case strings.HasPrefix(position, "top-"):
+		dy = -30
+	case strings.HasPrefix(position, "bottom-"):
+		dy = 30
+	}
+
+	return fmt.Sprintf("fontname:Helvetica, points:10, scalefactor:1 abs, rotation:0, fillcolor:#000000, position:%s, offset:%d %d", position, dx, dy)
+}
+
+// bookmarks reads the outline of a PDF. Contrary to the Bookmarks function
+// of PDFcpu, it does not skip the top-level entries which are alone on their
+// level.
+func (engine *PdfCpu) bookmarks(inputPath string) ([]pdfcpuCore.Bookmark, error) {
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("open PDF: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	conf := *engine.conf
+	conf.ValidationMode = pdfcpuConfig.ValidationRelaxed
+
+	pdfCtx, _, _, _, err := pdfcpuAPI.ReadValidateAndOptimize(f, &conf, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("read PDF: %w", err)
+	}
+
+	err = pdfCtx.LocateNameTree("Dests", false)
+	if err != nil {
+		return nil, fmt.Errorf("locate named destinations: %w", err)
+	}
+
+	if pdfCtx.Outlines == nil {
+		return nil, nil
+	}
+
+	first := pdfCtx.Outlines.IndirectRefEntry("First")
+	if first == nil {
+		return nil, nil
+	}
+
+	return pdfcpuCore.BookmarksForOutlineItem(pdfCtx, first, nil)
+}
+
+// outlineBookmarks converts outline entries to bookmarks, with the siblings
+// sorted by page as PDFcpu expects. It also returns the entries which target
+// a page outside the PDF or before the page of their parent.
+func outlineBookmarks(entries []gotenberg.PdfOutlineEntry, pageCount, parentPage int) ([]pdfcpuCore.Bookmark, []string) {
+	var outOfRange []string
+	bookmarks := make([]pdfcpuCore.Bookmark, len(entries))
+
+	for i, entry := range entries {
+		if entry.Page < parentPage || entry.Page > pageCount {
+			outOfRange = append(outOfRange, fmt.Sprintf("'%s' (page %d)", entry.Title, entry.Page))
+		}
+
+		kids, kidsOutOfRange := outlineBookmarks(entry.Children, pageCount, entry.Page)
+		outOfRange = append(outOfRange, kidsOutOfRange...)
+
+		bookmarks[i] = pdfcpuCore.Bookmark{
+			Title:    entry.Title,
+			PageFrom: entry.Page,
+			Kids:     kids,
+		}
+	}
+
+	sort.SliceStable(bookmarks, func(i, j int) bool {
+		return bookmarks[i].PageFrom < bookmarks[j].PageFrom
+	})
+
+	return bookmarks, outOfRange
+}
+
+// outlineEntries converts bookmarks to outline entries.
+func outlineEntries(bookmarks []pdfcpuCore.Bookmark) []gotenberg.PdfOutlineEntry {
+	if len(bookmarks) == 0 {
+		return nil
+	}
+
+	entries := make([]gotenberg.PdfOutlineEntry, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		entries[i] = gotenberg.PdfOutlineEntry{
+			Title:    bookmark.Title,
+			Page:     bookmark.PageFrom,
+			Children: outlineEntries(bookmark.Kids),
+		}
+	}
+
+	return entries
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module      = (*PdfCpu)(nil)
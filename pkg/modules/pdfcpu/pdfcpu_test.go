@@ -3,10 +3,16 @@ package pdfcpu
 import (
 	"context"
 	"errors"
+	"fmt"
+	"image"
+	"image/png"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
 	"go.uber.org/zap"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
@@ -102,3 +108,514 @@ func TestPdfCpu_Convert(t *testing.T) {
 		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
 	}
 }
+
+func TestPdfCpu_Decrypt(t *testing.T) {
+	mod := new(PdfCpu)
+	err := mod.Decrypt(context.TODO(), zap.NewNop(), "", "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfCpu_ExtractText(t *testing.T) {
+	mod := new(PdfCpu)
+	err := mod.ExtractText(context.TODO(), zap.NewNop(), 0, 0, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfCpu_Ocr(t *testing.T) {
+	mod := new(PdfCpu)
+	err := mod.Ocr(context.TODO(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfCpu_Normalize(t *testing.T) {
+	mod := new(PdfCpu)
+	err := mod.Normalize(context.TODO(), zap.NewNop(), time.Time{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfCpu_SetOutline(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		inputPath        string
+		entries          []gotenberg.PdfOutlineEntry
+		replace          bool
+		expectError      bool
+		expectOutOfRange bool
+	}{
+		{
+			scenario:    "invalid input path",
+			inputPath:   "foo",
+			entries:     []gotenberg.PdfOutlineEntry{{Title: "foo", Page: 1}},
+			replace:     true,
+			expectError: true,
+		},
+		{
+			scenario:  "entries out of range",
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+			entries: []gotenberg.PdfOutlineEntry{
+				{Title: "foo", Page: 0},
+				{Title: "bar", Page: 1, Children: []gotenberg.PdfOutlineEntry{{Title: "baz", Page: 1000}}},
+			},
+			replace:          true,
+			expectError:      true,
+			expectOutOfRange: true,
+		},
+		{
+			scenario:  "success (replace)",
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+			entries: []gotenberg.PdfOutlineEntry{
+				{Title: "foo", Page: 1, Children: []gotenberg.PdfOutlineEntry{{Title: "bar", Page: 1}}},
+			},
+			replace:     true,
+			expectError: false,
+		},
+		{
+			scenario:    "success (merge)",
+			inputPath:   "/tests/test/testdata/pdfengines/sample1.pdf",
+			entries:     []gotenberg.PdfOutlineEntry{{Title: "foo", Page: 1}},
+			replace:     false,
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(PdfCpu)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			err = engine.SetOutline(context.TODO(), zap.NewNop(), tc.entries, tc.replace, tc.inputPath, outputDir+"/foo.pdf")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			var outOfRangeErr *gotenberg.PdfOutlineOutOfRangeError
+			if tc.expectOutOfRange && !errors.As(err, &outOfRangeErr) {
+				t.Fatalf("expected a PdfOutlineOutOfRangeError but got: %v", err)
+			}
+
+			if tc.expectOutOfRange && len(outOfRangeErr.Entries) != 2 {
+				t.Errorf("expected 2 entries out of range but got: %v", outOfRangeErr.Entries)
+			}
+		})
+	}
+}
+
+func TestPdfCpu_ReadOutline(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		entries         []gotenberg.PdfOutlineEntry
+		inputPath       string
+		expectError     bool
+		expectPageCount int
+		expectEntries   []gotenberg.PdfOutlineEntry
+	}{
+		{
+			scenario:    "invalid input path",
+			inputPath:   "foo",
+			expectError: true,
+		},
+		{
+			scenario:        "success (no outline)",
+			inputPath:       "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectError:     false,
+			expectPageCount: 3,
+		},
+		{
+			scenario: "success",
+			entries: []gotenberg.PdfOutlineEntry{
+				{Title: "foo", Page: 1, Children: []gotenberg.PdfOutlineEntry{{Title: "bar", Page: 2}}},
+			},
+			inputPath:       "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectError:     false,
+			expectPageCount: 3,
+			expectEntries: []gotenberg.PdfOutlineEntry{
+				{Title: "foo", Page: 1, Children: []gotenberg.PdfOutlineEntry{{Title: "bar", Page: 2}}},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(PdfCpu)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			inputPath := tc.inputPath
+			if tc.entries != nil {
+				inputPath = outputDir + "/foo.pdf"
+				err = engine.SetOutline(context.TODO(), zap.NewNop(), tc.entries, true, tc.inputPath, inputPath)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			outline, err := engine.ReadOutline(context.TODO(), zap.NewNop(), inputPath)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if outline.PageCount != tc.expectPageCount {
+				t.Errorf("expected %d pages but got %d", tc.expectPageCount, outline.PageCount)
+			}
+
+			if !reflect.DeepEqual(outline.Entries, tc.expectEntries) {
+				t.Errorf("expected entries %+v but got %+v", tc.expectEntries, outline.Entries)
+			}
+		})
+	}
+}
+
+func TestPdfCpu_Redact(t *testing.T) {
+	engine := new(PdfCpu)
+	err := engine.Redact(context.TODO(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfCpu_StampPageNumbers(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		inputPath   string
+		numbers     gotenberg.PdfPageNumbers
+		expectError bool
+	}{
+		{
+			scenario:    "invalid input path",
+			inputPath:   "foo",
+			numbers:     gotenberg.PdfPageNumbers{Format: "{page}", Position: "bottom-center", StartAt: 1},
+			expectError: true,
+		},
+		{
+			scenario:  "all pages excluded",
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+			numbers: gotenberg.PdfPageNumbers{
+				Format:        "{page}",
+				Position:      "bottom-center",
+				StartAt:       1,
+				ExcludedPages: []gotenberg.PdfPageRange{{First: 1}},
+			},
+			expectError: false,
+		},
+		{
+			scenario:    "success",
+			inputPath:   "/tests/test/testdata/pdfengines/sample1.pdf",
+			numbers:     gotenberg.PdfPageNumbers{Format: "Page {page} of {total}", Position: "top-right", StartAt: 3},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(PdfCpu)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			err = engine.StampPageNumbers(context.TODO(), zap.NewNop(), tc.numbers, tc.inputPath, outputDir+"/foo.pdf")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			_, err = os.Stat(outputDir + "/foo.pdf")
+			if err != nil {
+				t.Errorf("expected the output PDF to exist but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestPdfCpu_Crop(t *testing.T) {
+	engine := new(PdfCpu)
+	err := engine.Crop(context.TODO(), zap.NewNop(), gotenberg.PdfCrop{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfCpu_ImportImages(t *testing.T) {
+	writePng := func(path string, width, height int) {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		err = png.Encode(f, image.NewGray(image.Rect(0, 0, width, height)))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		images                 []gotenberg.PdfImage
+		expectError            bool
+		expectPageSizeError    bool
+		expectPageCount        int
+		createImagesWithSample bool
+	}{
+		{
+			scenario:    "invalid image path",
+			images:      []gotenberg.PdfImage{{Path: "foo.png"}},
+			expectError: true,
+		},
+		{
+			scenario:               "unknown page size",
+			images:                 []gotenberg.PdfImage{{Path: "a.png", PageSize: "foo"}},
+			expectError:            true,
+			expectPageSizeError:    true,
+			createImagesWithSample: true,
+		},
+		{
+			scenario:               "unknown fit",
+			images:                 []gotenberg.PdfImage{{Path: "a.png", PageSize: "A4", Fit: "foo"}},
+			expectError:            true,
+			createImagesWithSample: true,
+		},
+		{
+			scenario: "success",
+			images: []gotenberg.PdfImage{
+				{Path: "a.png"},
+				{Path: "b.png", PageSize: "A4", Fit: gotenberg.PdfImageFitContain},
+				{Path: "a.png", PageSize: "LetterL", Fit: gotenberg.PdfImageFitNone},
+			},
+			expectPageCount:        3,
+			createImagesWithSample: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(PdfCpu)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			dirPath := t.TempDir()
+			images := make([]gotenberg.PdfImage, len(tc.images))
+			copy(images, tc.images)
+
+			if tc.createImagesWithSample {
+				writePng(dirPath+"/a.png", 200, 100)
+				writePng(dirPath+"/b.png", 50, 100)
+
+				for i := range images {
+					images[i].Path = fmt.Sprintf("%s/%s", dirPath, images[i].Path)
+				}
+			}
+
+			outputPath := dirPath + "/foo.pdf"
+			err = engine.ImportImages(context.TODO(), zap.NewNop(), images, outputPath)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectPageSizeError && !errors.Is(err, gotenberg.ErrPdfPageSizeNotSupported) {
+				t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfPageSizeNotSupported, err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			pageCount, err := pdfcpuAPI.PageCountFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if pageCount != tc.expectPageCount {
+				t.Errorf("expected %d pages but got %d", tc.expectPageCount, pageCount)
+			}
+		})
+	}
+}
+
+func TestPdfCpu_CheckAndRepair(t *testing.T) {
+	engine := new(PdfCpu)
+	err := engine.Provision(nil)
+	if err != nil {
+		t.Fatalf("expected error but got: %v", err)
+	}
+
+	dirPath := t.TempDir()
+
+	f, err := os.Create(dirPath + "/a.png")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = png.Encode(f, image.NewGray(image.Rect(0, 0, 50, 50)))
+	_ = f.Close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	validPath := dirPath + "/valid.pdf"
+	err = engine.ImportImages(context.TODO(), zap.NewNop(), []gotenberg.PdfImage{{Path: dirPath + "/a.png"}}, validPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	invalidPath := dirPath + "/invalid.pdf"
+	err = os.WriteFile(invalidPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario          string
+		inputPath         string
+		expectStatus      string
+		expectRepairError bool
+	}{
+		{
+			scenario:     "valid",
+			inputPath:    validPath,
+			expectStatus: gotenberg.PdfCheckValid,
+		},
+		{
+			scenario:          "invalid",
+			inputPath:         invalidPath,
+			expectStatus:      gotenberg.PdfCheckInvalid,
+			expectRepairError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			report, err := engine.Check(context.TODO(), zap.NewNop(), tc.inputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if report.Status() != tc.expectStatus {
+				t.Errorf("expected status '%s' but got '%s' (%+v)", tc.expectStatus, report.Status(), report)
+			}
+
+			err = engine.Repair(context.TODO(), zap.NewNop(), tc.inputPath, tc.inputPath+".repaired.pdf")
+
+			if !tc.expectRepairError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectRepairError && !errors.Is(err, gotenberg.ErrPdfNotRepairable) {
+				t.Fatalf("expected error %v but got: %v", gotenberg.ErrPdfNotRepairable, err)
+			}
+		})
+	}
+}
+
+func TestPageNumberDescription(t *testing.T) {
+	for _, tc := range []struct {
+		position     string
+		expectOffset string
+	}{
+		{position: "top-left", expectOffset: "offset:30 -30"},
+		{position: "top-center", expectOffset: "offset:0 -30"},
+		{position: "bottom-right", expectOffset: "offset:-30 30"},
+	} {
+		t.Run(tc.position, func(t *testing.T) {
+			description := pageNumberDescription(tc.position)
+
+			if !strings.HasSuffix(description, fmt.Sprintf("position:%s, %s", tc.position, tc.expectOffset)) {
+				t.Errorf("expected description to end with position '%s' and '%s' but got: %s", tc.position, tc.expectOffset, description)
+			}
+		})
+	}
+}
+
+func TestPdfCpu_Rasterize(t *testing.T) {
+	engine := new(PdfCpu)
+	_, err := engine.Rasterize(context.TODO(), zap.NewNop(), gotenberg.PdfRaster{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfCpu_SplitPages(t *testing.T) {
+	engine := new(PdfCpu)
+	_, err := engine.SplitPages(context.TODO(), zap.NewNop(), "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
@@ -3,6 +3,8 @@ package pdfengines
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -14,12 +16,39 @@ type multiPdfEngines struct {
 	engines []gotenberg.PdfEngine
 }
 
+// engineName returns the identifier of a [gotenberg.PdfEngine] module.
+func engineName(engine gotenberg.PdfEngine) string {
+	mod, ok := engine.(gotenberg.Module)
+	if !ok {
+		return "unknown"
+	}
+
+	return mod.Descriptor().ID
+}
+
 func newMultiPdfEngines(engines ...gotenberg.PdfEngine) *multiPdfEngines {
 	return &multiPdfEngines{
 		engines: engines,
 	}
 }
 
+// only returns a [multiPdfEngines] with only the child of the given name,
+// e.g., so that a client works around the bug of an engine on a particular
+// file.
+func (multi *multiPdfEngines) only(name string) (*multiPdfEngines, error) {
+	names := make([]string, len(multi.engines))
+
+	for i, engine := range multi.engines {
+		names[i] = engineName(engine)
+
+		if names[i] == name {
+			return newMultiPdfEngines(engine), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown PDF engine, expected one of: %s", strings.Join(names, ", "))
+}
+
 // Merge tries to merge the given PDFs into a unique PDF thanks to its
 // children. If the context is done, it stops and returns an error.
 func (multi *multiPdfEngines) Merge(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
@@ -28,7 +57,10 @@ func (multi *multiPdfEngines) Merge(ctx context.Context, logger *zap.Logger, inp
 
 	for _, engine := range multi.engines {
 		go func(engine gotenberg.PdfEngine) {
-			errChan <- engine.Merge(ctx, logger, inputPaths, outputPath)
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.merge", engineName(engine), len(inputPaths))
+			err := engine.Merge(spanCtx, logger, inputPaths, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
 		}(engine)
 
 		select {
@@ -53,7 +85,10 @@ func (multi *multiPdfEngines) Convert(ctx context.Context, logger *zap.Logger, f
 
 	for _, engine := range multi.engines {
 		go func(engine gotenberg.PdfEngine) {
-			errChan <- engine.Convert(ctx, logger, formats, inputPath, outputPath)
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.convert", engineName(engine), 1)
+			err := engine.Convert(spanCtx, logger, formats, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
 		}(engine)
 
 		select {
@@ -70,6 +105,418 @@ func (multi *multiPdfEngines) Convert(ctx context.Context, logger *zap.Logger, f
 	return fmt.Errorf("convert PDF to '%+v' with multi PDF engines: %w", formats, err)
 }
 
+// Decrypt removes the encryption of the given PDF thanks to its children. If
+// the context is done, it stops and returns an error.
+func (multi *multiPdfEngines) Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.decrypt", engineName(engine), 1)
+			err := engine.Decrypt(spanCtx, logger, password, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case decryptErr := <-errChan:
+			errored := multierr.AppendInto(&err, decryptErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("decrypt PDF with multi PDF engines: %w", err)
+}
+
+// ExtractText extracts the text of the given PDF thanks to its children. If
+// the context is done, it stops and returns an error.
+func (multi *multiPdfEngines) ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.extract_text", engineName(engine), 1)
+			err := engine.ExtractText(spanCtx, logger, firstPage, lastPage, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case extractErr := <-errChan:
+			errored := multierr.AppendInto(&err, extractErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("extract text from PDF with multi PDF engines: %w", err)
+}
+
+// Ocr adds a searchable text layer to the given PDF thanks to its children.
+// If the context is done, it stops and returns an error.
+func (multi *multiPdfEngines) Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.ocr", engineName(engine), 1)
+			err := engine.Ocr(spanCtx, logger, languages, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case ocrErr := <-errChan:
+			errored := multierr.AppendInto(&err, ocrErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("OCR PDF with multi PDF engines: %w", err)
+}
+
+// Normalize normalizes a PDF thanks to its children. If the context is done,
+// it stops and returns an error.
+func (multi *multiPdfEngines) Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.normalize", engineName(engine), 1)
+			err := engine.Normalize(spanCtx, logger, date, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case normalizeErr := <-errChan:
+			errored := multierr.AppendInto(&err, normalizeErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("normalize PDF with multi PDF engines: %w", err)
+}
+
+// SetOutline writes an outline to a PDF thanks to its children. If the
+// context is done, it stops and returns an error.
+func (multi *multiPdfEngines) SetOutline(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.outline", engineName(engine), 1)
+			err := engine.SetOutline(spanCtx, logger, entries, replace, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case outlineErr := <-errChan:
+			errored := multierr.AppendInto(&err, outlineErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("set PDF outline with multi PDF engines: %w", err)
+}
+
+// ReadOutline returns the outline of a PDF thanks to its children. If the
+// context is done, it stops and returns an error.
+func (multi *multiPdfEngines) ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+	type result struct {
+		outline gotenberg.PdfOutline
+		err     error
+	}
+
+	var err error
+	resultChan := make(chan result, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.read_outline", engineName(engine), 1)
+			outline, err := engine.ReadOutline(spanCtx, logger, inputPath)
+			gotenberg.EndSpan(span, "", err)
+			resultChan <- result{outline: outline, err: err}
+		}(engine)
+
+		select {
+		case res := <-resultChan:
+			errored := multierr.AppendInto(&err, res.err)
+			if !errored {
+				return res.outline, nil
+			}
+		case <-ctx.Done():
+			return gotenberg.PdfOutline{}, ctx.Err()
+		}
+	}
+
+	return gotenberg.PdfOutline{}, fmt.Errorf("read PDF outline with multi PDF engines: %w", err)
+}
+
+// Redact removes the content under areas of a PDF thanks to its children. If
+// the context is done, it stops and returns an error.
+func (multi *multiPdfEngines) Redact(ctx context.Context, logger *zap.Logger, redactions []gotenberg.PdfRedaction, inputPath, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.redact", engineName(engine), 1)
+			err := engine.Redact(spanCtx, logger, redactions, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case redactErr := <-errChan:
+			errored := multierr.AppendInto(&err, redactErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("redact PDF with multi PDF engines: %w", err)
+}
+
+// StampPageNumbers writes page numbers on a PDF thanks to its children. If
+// the context is done, it stops and returns an error.
+func (multi *multiPdfEngines) StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.stamp_page_numbers", engineName(engine), 1)
+			err := engine.StampPageNumbers(spanCtx, logger, numbers, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case stampErr := <-errChan:
+			errored := multierr.AppendInto(&err, stampErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("stamp page numbers with multi PDF engines: %w", err)
+}
+
+// Crop sets the crop box of the pages of a PDF thanks to its children. If
+// the context is done, it stops and returns an error.
+func (multi *multiPdfEngines) Crop(ctx context.Context, logger *zap.Logger, crop gotenberg.PdfCrop, inputPath, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.crop", engineName(engine), 1)
+			err := engine.Crop(spanCtx, logger, crop, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case cropErr := <-errChan:
+			errored := multierr.AppendInto(&err, cropErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("crop PDF with multi PDF engines: %w", err)
+}
+
+// ImportImages creates a PDF from images thanks to its children. If the
+// context is done, it stops and returns an error.
+func (multi *multiPdfEngines) ImportImages(ctx context.Context, logger *zap.Logger, images []gotenberg.PdfImage, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.import_images", engineName(engine), len(images))
+			err := engine.ImportImages(spanCtx, logger, images, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case importErr := <-errChan:
+			errored := multierr.AppendInto(&err, importErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("import images with multi PDF engines: %w", err)
+}
+
+// Check verifies the structure of a PDF thanks to its children. If the
+// context is done, it stops and returns an error.
+func (multi *multiPdfEngines) Check(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfCheckReport, error) {
+	type result struct {
+		report gotenberg.PdfCheckReport
+		err    error
+	}
+
+	var err error
+	resultChan := make(chan result, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.check", engineName(engine), 1)
+			report, err := engine.Check(spanCtx, logger, inputPath)
+			gotenberg.EndSpan(span, "", err)
+			resultChan <- result{report: report, err: err}
+		}(engine)
+
+		select {
+		case res := <-resultChan:
+			errored := multierr.AppendInto(&err, res.err)
+			if !errored {
+				return res.report, nil
+			}
+		case <-ctx.Done():
+			return gotenberg.PdfCheckReport{}, ctx.Err()
+		}
+	}
+
+	return gotenberg.PdfCheckReport{}, fmt.Errorf("check PDF with multi PDF engines: %w", err)
+}
+
+// Repair fixes a PDF thanks to its children. If the context is done, it
+// stops and returns an error.
+func (multi *multiPdfEngines) Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.repair", engineName(engine), 1)
+			err := engine.Repair(spanCtx, logger, inputPath, outputPath)
+			gotenberg.EndSpan(span, outputPath, err)
+			errChan <- err
+		}(engine)
+
+		select {
+		case repairErr := <-errChan:
+			errored := multierr.AppendInto(&err, repairErr)
+			if !errored {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("repair PDF with multi PDF engines: %w", err)
+}
+
+// Rasterize renders the pages of a PDF as images thanks to its children. If
+// the context is done, it stops and returns an error.
+func (multi *multiPdfEngines) Rasterize(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+	type result struct {
+		outputPaths []string
+		err         error
+	}
+
+	var err error
+	resultChan := make(chan result, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.rasterize", engineName(engine), 1)
+			outputPaths, err := engine.Rasterize(spanCtx, logger, raster, inputPath, outputPathPrefix)
+			gotenberg.EndSpan(span, "", err)
+			resultChan <- result{outputPaths: outputPaths, err: err}
+		}(engine)
+
+		select {
+		case res := <-resultChan:
+			errored := multierr.AppendInto(&err, res.err)
+			if !errored {
+				return res.outputPaths, nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, fmt.Errorf("rasterize PDF with multi PDF engines: %w", err)
+}
+
+// SplitPages writes each page of a PDF to its own PDF thanks to its
+// children. If the context is done, it stops and returns an error.
+func (multi *multiPdfEngines) SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	type result struct {
+		outputPaths []string
+		err         error
+	}
+
+	var err error
+	resultChan := make(chan result, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			spanCtx, span := gotenberg.StartSpan(ctx, "pdfengine.split_pages", engineName(engine), 1)
+			outputPaths, err := engine.SplitPages(spanCtx, logger, inputPath, outputPathPrefix)
+			gotenberg.EndSpan(span, "", err)
+			resultChan <- result{outputPaths: outputPaths, err: err}
+		}(engine)
+
+		select {
+		case res := <-resultChan:
+			errored := multierr.AppendInto(&err, res.err)
+			if !errored {
+				return res.outputPaths, nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return nil, fmt.Errorf("split PDF pages with multi PDF engines: %w", err)
+}
+
 // Interface guards.
 var (
 	_ gotenberg.PdfEngine = (*multiPdfEngines)(nil)
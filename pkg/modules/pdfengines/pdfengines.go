@@ -168,6 +168,24 @@ func (mod *PdfEngines) Routes() ([]api.Route, error) {
 	return []api.Route{
 		mergeRoute(engine),
 		convertRoute(engine),
+		imagesRoute(engine),
+		decryptRoute(engine),
+		outlineRoute(engine),
+		redactRoute(engine),
+		cropRoute(engine),
+		textRoute(engine),
+		validateRoute(engine),
+	}, nil
+}
+
+// Capabilities returns the selected PDF engines, in their order.
+func (mod *PdfEngines) Capabilities() (map[string]interface{}, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return map[string]interface{}{
+		"engines": mod.names,
 	}, nil
 }
 
@@ -179,4 +197,5 @@ var (
 	_ gotenberg.SystemLogger      = (*PdfEngines)(nil)
 	_ gotenberg.PdfEngineProvider = (*PdfEngines)(nil)
 	_ api.Router                  = (*PdfEngines)(nil)
+	_ api.CapabilitiesProvider    = (*PdfEngines)(nil)
 )
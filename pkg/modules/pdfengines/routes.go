@@ -1,9 +1,14 @@
 package pdfengines
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 
@@ -22,15 +27,36 @@ func mergeRoute(engine gotenberg.PdfEngine) api.Route {
 
 			// Let's get the data from the form and validate them.
 			var (
-				inputPaths []string
-				pdfa       string
-				pdfua      bool
+				inputPaths   []string
+				pdfa         string
+				pdfua        bool
+				ocr          bool
+				ocrLanguages []string
+				mergeOutline bool
+				pdfEngine    string
+				mergeEngine  gotenberg.PdfEngine
 			)
 
-			err := ctx.FormData().
+			form := ctx.FormData()
+			reproducible := api.FormDataReproducible(form)
+			pageNumbers := api.FormDataPageNumbers(form)
+
+			err := formDataPdfEngine(form, engine, &pdfEngine, &mergeEngine).
 				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				Bool("mergeOutline", &mergeOutline, false).
 				String("pdfa", &pdfa, "").
 				Bool("pdfua", &pdfua, false).
+				Bool("ocr", &ocr, false).
+				Custom("ocrLanguages", func(value string) error {
+					languages, err := parseOcrLanguages(value)
+					if err != nil {
+						return err
+					}
+
+					ocrLanguages = languages
+
+					return nil
+				}).
 				Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
@@ -41,13 +67,51 @@ func mergeRoute(engine gotenberg.PdfEngine) api.Route {
 				PdfUa: pdfua,
 			}
 
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
 			// Alright, let's merge the PDFs.
 
-			outputPath := ctx.GeneratePath(".pdf")
+			var outputPath string
 
-			err = engine.Merge(ctx, ctx.Log(), inputPaths, outputPath)
-			if err != nil {
-				return fmt.Errorf("merge PDFs: %w", err)
+			if mergeOutline {
+				titles := make([]string, len(inputPaths))
+				for i, inputPath := range inputPaths {
+					titles[i] = filepath.Base(inputPath)
+				}
+
+				outputPath, err = api.MergePdfsWithOutline(ctx, mergeEngine, inputPaths, titles)
+				if err != nil {
+					return handlePdfEngineError(fmt.Errorf("merge PDFs with outline: %w", err), pdfEngine)
+				}
+			} else {
+				outputPath = ctx.GeneratePath(".pdf")
+
+				err = mergeEngine.Merge(ctx, ctx.Log(), inputPaths, outputPath)
+				if err != nil {
+					return handlePdfEngineError(fmt.Errorf("merge PDFs: %w", err), pdfEngine)
+				}
+			}
+
+			if ocr {
+				outputPath, err = ocrPdf(ctx, engine, ocrLanguages, outputPath)
+				if err != nil {
+					return err
+				}
+			}
+
+			// The page numbers come after the merge, so that the total is the
+			// one of the resulting PDF.
+			if pageNumbers != nil {
+				outputPaths, err := api.StampPageNumbers(ctx, engine, *pageNumbers, outputPath)
+				if err != nil {
+					return fmt.Errorf("stamp page numbers: %w", err)
+				}
+
+				// Important: the output path is now the numbered file.
+				outputPath = outputPaths[0]
 			}
 
 			// So far so good, the PDFs are merged into one unique PDF.
@@ -78,6 +142,16 @@ func mergeRoute(engine gotenberg.PdfEngine) api.Route {
 				outputPath = convertOutputPath
 			}
 
+			if reproducible != nil {
+				outputPaths, err := api.NormalizePdfs(ctx, engine, *reproducible, outputPath)
+				if err != nil {
+					return fmt.Errorf("normalize PDF: %w", err)
+				}
+
+				// Important: the output path is now the normalized file.
+				outputPath = outputPaths[0]
+			}
+
 			// Last but not least, add the output path to the context so that
 			// the API is able to send it as a response to the client.
 
@@ -103,15 +177,35 @@ func convertRoute(engine gotenberg.PdfEngine) api.Route {
 
 			// Let's get the data from the form and validate them.
 			var (
-				inputPaths []string
-				pdfa       string
-				pdfua      bool
+				inputPaths      []string
+				pdfa            string
+				pdfua           bool
+				ocr             bool
+				ocrLanguages    []string
+				continueOnError bool
+				pdfEngine       string
+				convertEngine   gotenberg.PdfEngine
 			)
 
-			err := ctx.FormData().
+			form := ctx.FormData()
+			reproducible := api.FormDataReproducible(form)
+
+			err := formDataPdfEngine(form, engine, &pdfEngine, &convertEngine).
 				MandatoryPaths([]string{".pdf"}, &inputPaths).
 				String("pdfa", &pdfa, "").
 				Bool("pdfua", &pdfua, false).
+				Bool("ocr", &ocr, false).
+				Custom("ocrLanguages", func(value string) error {
+					languages, err := parseOcrLanguages(value)
+					if err != nil {
+						return err
+					}
+
+					ocrLanguages = languages
+
+					return nil
+				}).
+				Bool("continueOnError", &continueOnError, false).
 				Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
@@ -123,37 +217,468 @@ func convertRoute(engine gotenberg.PdfEngine) api.Route {
 			}
 
 			zeroValued := gotenberg.PdfFormats{}
-			if pdfFormats == zeroValued {
+			if pdfFormats == zeroValued && !ocr && reproducible == nil {
 				return api.WrapError(
 					errors.New("no PDF formats"),
 					api.NewSentinelHttpError(
 						http.StatusBadRequest,
-						"Invalid form data: either 'pdfa' or 'pdfua' form fields must be provided",
+						"Invalid form data: either 'pdfa', 'pdfua', 'ocr' or 'reproducible' form fields must be provided",
 					),
 				)
 			}
 
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
 			// Alright, let's convert the PDFs.
-			outputPaths := make([]string, len(inputPaths))
+			var (
+				outputPaths []string
+				convertErr  error
+			)
 
-			for i, inputPath := range inputPaths {
-				outputPaths[i] = ctx.GeneratePath(".pdf")
+			for _, inputPath := range inputPaths {
+				outputPath, err := convertPdf(ctx, convertEngine, pdfFormats, ocr, ocrLanguages, inputPath)
+				err = handlePdfEngineError(err, pdfEngine)
+				if err != nil {
+					if !continueOnError {
+						return err
+					}
+
+					// The client prefers a partial result over no result at
+					// all; the failed PDFs are left out of the response.
+					ctx.Log().Warn(fmt.Sprintf("skip '%s': %s", filepath.Base(inputPath), err.Error()))
+					convertErr = err
+
+					continue
+				}
+
+				outputPaths = append(outputPaths, outputPath)
+			}
 
-				err = engine.Convert(ctx, ctx.Log(), pdfFormats, inputPath, outputPaths[i])
+			if len(outputPaths) == 0 {
+				return convertErr
+			}
 
+			if reproducible != nil {
+				outputPaths, err = api.NormalizePdfs(ctx, engine, *reproducible, outputPaths...)
 				if err != nil {
-					if errors.Is(err, gotenberg.ErrPdfFormatNotSupported) {
-						return api.WrapError(
-							fmt.Errorf("convert PDF: %w", err),
-							api.NewSentinelHttpError(
-								http.StatusBadRequest,
-								fmt.Sprintf("At least one PDF engine does not handle one of the PDF format in '%+v', while other have failed to convert for other reasons", pdfFormats),
-							),
-						)
+					return fmt.Errorf("normalize PDFs: %w", err)
+				}
+			}
+
+			// Last but not least, add the output paths to the context so that
+			// the API is able to send them as a response to the client.
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// convertPdf applies the OCR, if any, and converts a PDF to the given PDF
+// formats. It returns the path of the resulting PDF.
+func convertPdf(ctx *api.Context, engine gotenberg.PdfEngine, pdfFormats gotenberg.PdfFormats, ocr bool, ocrLanguages []string, inputPath string) (string, error) {
+	var err error
+	if ocr {
+		inputPath, err = ocrPdf(ctx, engine, ocrLanguages, inputPath)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	zeroValued := gotenberg.PdfFormats{}
+	if pdfFormats == zeroValued {
+		return inputPath, nil
+	}
+
+	outputPath := ctx.GeneratePath(".pdf")
+
+	err = engine.Convert(ctx, ctx.Log(), pdfFormats, inputPath, outputPath)
+	if err != nil {
+		if errors.Is(err, gotenberg.ErrPdfFormatNotSupported) {
+			return "", api.WrapError(
+				fmt.Errorf("convert PDF: %w", err),
+				api.NewSentinelHttpError(
+					http.StatusBadRequest,
+					fmt.Sprintf("At least one PDF engine does not handle one of the PDF format in '%+v', while other have failed to convert for other reasons", pdfFormats),
+				),
+			)
+		}
+
+		return "", fmt.Errorf("convert PDF: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// formDataPdfEngine binds the "pdfEngine" form field, i.e., the PDF engine
+// which handles the main operation of a request. Fallback to the given engine,
+// i.e., the engines in their default order.
+func formDataPdfEngine(form *api.FormData, engine gotenberg.PdfEngine, name *string, target *gotenberg.PdfEngine) *api.FormData {
+	*target = engine
+
+	return form.Custom("pdfEngine", func(value string) error {
+		if value == "" {
+			return nil
+		}
+
+		multi, ok := engine.(*multiPdfEngines)
+		if !ok {
+			return errors.New("the selection of a PDF engine is not available")
+		}
+
+		selected, err := multi.only(value)
+		if err != nil {
+			return err
+		}
+
+		*name = value
+		*target = selected
+
+		return nil
+	})
+}
+
+// handlePdfEngineError returns a 400 error if the PDF engine selected by the
+// client does not support an operation.
+func handlePdfEngineError(err error, pdfEngine string) error {
+	if err == nil || pdfEngine == "" || !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		return err
+	}
+
+	return api.WrapError(
+		err,
+		api.NewSentinelHttpError(
+			http.StatusBadRequest,
+			fmt.Sprintf("Invalid form data: the PDF engine '%s' does not support this operation", pdfEngine),
+		),
+	)
+}
+
+// parseOcrLanguages parses the "ocrLanguages" form field value, i.e.,
+// Tesseract languages separated by a "+" (e.g., "eng+deu"). It defaults to
+// English.
+func parseOcrLanguages(value string) ([]string, error) {
+	if value == "" {
+		return []string{"eng"}, nil
+	}
+
+	languages := strings.Split(value, "+")
+	for _, language := range languages {
+		if strings.TrimSpace(language) == "" {
+			return nil, errors.New("wrong value, expected languages separated by a '+' (e.g., 'eng+deu')")
+		}
+	}
+
+	return languages, nil
+}
+
+// ocrPdf adds a searchable text layer to a PDF and returns the path of the
+// resulting PDF.
+func ocrPdf(ctx *api.Context, engine gotenberg.PdfEngine, languages []string, inputPath string) (string, error) {
+	outputPath := ctx.GeneratePath(".pdf")
+
+	err := engine.Ocr(ctx, ctx.Log(), languages, inputPath, outputPath)
+	if err != nil {
+		if errors.Is(err, gotenberg.ErrOcrLanguagesNotInstalled) {
+			return "", api.WrapError(
+				fmt.Errorf("OCR PDF: %w", err),
+				api.NewSentinelHttpError(
+					http.StatusBadRequest,
+					fmt.Sprintf("The data of at least one of the OCR languages '%s' is not installed", strings.Join(languages, "+")),
+				),
+			)
+		}
+
+		return "", fmt.Errorf("OCR PDF: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+// imageExtensions are the extensions of the images the images route
+// accepts.
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".tif", ".tiff", ".webp"}
+
+// imageSettings are the page size and fit of an image, overriding the ones of
+// the request.
+type imageSettings struct {
+	PageSize *string `json:"pageSize"`
+	Fit      *string `json:"fit"`
+}
+
+// imagesRoute returns an [api.Route] which can assemble images into a PDF,
+// one image per page, and optionally make it searchable and convert it to
+// PDF/A and PDF/UA.
+func imagesRoute(engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdfengines/images",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths   []string
+				pageSize     string
+				fit          string
+				settings     map[string]imageSettings
+				pdfa         string
+				pdfua        bool
+				ocr          bool
+				ocrLanguages []string
+				pdfEngine    string
+				importEngine gotenberg.PdfEngine
+			)
+
+			form := ctx.FormData()
+			reproducible := api.FormDataReproducible(form)
+			order := api.FormDataOrder(form)
+
+			err := formDataPdfEngine(form, engine, &pdfEngine, &importEngine).
+				MandatoryPaths(imageExtensions, &inputPaths).
+				OnlyExtensions(imageExtensions).
+				String("pageSize", &pageSize, "").
+				Custom("fit", func(value string) error {
+					return parseImageFit(value, &fit)
+				}).
+				Custom("images", func(value string) error {
+					if value == "" {
+						return nil
 					}
 
-					return fmt.Errorf("convert PDF: %w", err)
+					err := json.Unmarshal([]byte(value), &settings)
+					if err != nil {
+						return fmt.Errorf("wrong value, expected a JSON object of image settings by filename: %w", err)
+					}
+
+					for filename, setting := range settings {
+						if setting.Fit == nil {
+							continue
+						}
+
+						err = parseImageFit(*setting.Fit, setting.Fit)
+						if err != nil {
+							return fmt.Errorf("image '%s': %w", filename, err)
+						}
+					}
+
+					return nil
+				}).
+				String("pdfa", &pdfa, "").
+				Bool("pdfua", &pdfua, false).
+				Bool("ocr", &ocr, false).
+				Custom("ocrLanguages", func(value string) error {
+					languages, err := parseOcrLanguages(value)
+					if err != nil {
+						return err
+					}
+
+					ocrLanguages = languages
+
+					return nil
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			images, err := orderImages(inputPaths, *order, pageSize, fit, settings)
+			if err != nil {
+				return api.WrapError(
+					fmt.Errorf("order images: %w", err),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode("INVALID_FORM_DATA"),
+				)
+			}
+
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
+			// Alright, let's assemble the images.
+			outputPath := ctx.GeneratePath(".pdf")
+
+			err = importEngine.ImportImages(ctx, ctx.Log(), images, outputPath)
+			if err != nil {
+				if errors.Is(err, gotenberg.ErrPdfPageSizeNotSupported) {
+					return api.WrapError(
+						fmt.Errorf("import images: %w", err),
+						api.NewSentinelHttpError(
+							http.StatusBadRequest,
+							"Invalid form data: at least one page size is not supported (e.g., 'A4', 'A4L' or 'Letter')",
+						),
+					)
+				}
+
+				err = handlePdfEngineError(err, pdfEngine)
+
+				return fmt.Errorf("import images: %w", err)
+			}
+
+			pdfFormats := gotenberg.PdfFormats{
+				PdfA:  pdfa,
+				PdfUa: pdfua,
+			}
+
+			outputPath, err = convertPdf(ctx, importEngine, pdfFormats, ocr, ocrLanguages, outputPath)
+			err = handlePdfEngineError(err, pdfEngine)
+			if err != nil {
+				return err
+			}
+
+			outputPaths := []string{outputPath}
+
+			if reproducible != nil {
+				outputPaths, err = api.NormalizePdfs(ctx, engine, *reproducible, outputPaths...)
+				if err != nil {
+					return fmt.Errorf("normalize PDFs: %w", err)
+				}
+			}
+
+			// Last but not least, add the output paths to the context so that
+			// the API is able to send them as a response to the client.
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// parseImageFit parses the fit of an image, i.e., either "contain", "none"
+// or empty.
+func parseImageFit(value string, target *string) error {
+	switch value {
+	case "", gotenberg.PdfImageFitContain, gotenberg.PdfImageFitNone:
+		*target = value
+
+		return nil
+	default:
+		return fmt.Errorf("wrong value, expected either '%s', '%s' or empty", gotenberg.PdfImageFitContain, gotenberg.PdfImageFitNone)
+	}
+}
+
+// orderImages returns the images to import, either ordered by filename or in
+// the given order, which must then list each image exactly once. The
+// settings by filename override the page size and fit of the request.
+func orderImages(inputPaths, order []string, pageSize, fit string, settings map[string]imageSettings) ([]gotenberg.PdfImage, error) {
+	pathsByFilename := make(map[string]string, len(inputPaths))
+	for _, inputPath := range inputPaths {
+		pathsByFilename[filepath.Base(inputPath)] = inputPath
+	}
+
+	for filename := range settings {
+		if _, ok := pathsByFilename[filename]; !ok {
+			return nil, fmt.Errorf("the 'images' form field references the unknown image '%s'", filename)
+		}
+	}
+
+	inputPaths, err := api.OrderPaths(inputPaths, order)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]gotenberg.PdfImage, len(inputPaths))
+	for i, inputPath := range inputPaths {
+		images[i] = gotenberg.PdfImage{
+			Path:     inputPath,
+			PageSize: pageSize,
+			Fit:      fit,
+		}
+
+		setting, ok := settings[filepath.Base(inputPath)]
+		if !ok {
+			continue
+		}
+
+		if setting.PageSize != nil {
+			images[i].PageSize = *setting.PageSize
+		}
+
+		if setting.Fit != nil {
+			images[i].Fit = *setting.Fit
+		}
+	}
+
+	return images, nil
+}
+
+// decryptRoute returns an [api.Route] which can remove the encryption of PDFs.
+func decryptRoute(engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdfengines/decrypt",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths      []string
+				password        string
+				continueOnError bool
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				MandatoryString("password", &password).
+				Bool("continueOnError", &continueOnError, false).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
+			// Alright, let's decrypt the PDFs. With continueOnError, the
+			// errors are reported in the manifest instead.
+			var outputPaths []string
+			decryptErrs := make([]error, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				outputPath := ctx.GeneratePath(".pdf")
+
+				err = decryptPdf(ctx, engine, password, inputPath, outputPath)
+				if err != nil {
+					if !continueOnError {
+						return err
+					}
+
+					ctx.Log().Warn(fmt.Sprintf("skip '%s': %s", filepath.Base(inputPath), err))
+					decryptErrs[i] = err
+
+					continue
+				}
+
+				outputPaths = append(outputPaths, outputPath)
+			}
+
+			if continueOnError {
+				// Nothing to return but the errors.
+				if len(outputPaths) == 0 {
+					return decryptErrs[0]
+				}
+
+				manifestPath, err := api.WriteManifest(ctx, inputPaths, decryptErrs)
+				if err != nil {
+					return fmt.Errorf("write manifest: %w", err)
 				}
+
+				outputPaths = append(outputPaths, manifestPath)
 			}
 
 			// Last but not least, add the output paths to the context so that
@@ -168,3 +693,656 @@ func convertRoute(engine gotenberg.PdfEngine) api.Route {
 		},
 	}
 }
+
+// decryptPdf removes the encryption of a PDF. A wrong password gives an
+// [api.HttpError] with the INVALID_PDF_PASSWORD code.
+func decryptPdf(ctx *api.Context, engine gotenberg.PdfEngine, password, inputPath, outputPath string) error {
+	err := engine.Decrypt(ctx, ctx.Log(), password, inputPath, outputPath)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gotenberg.ErrPdfInvalidPassword) {
+		return api.WrapError(
+			fmt.Errorf("decrypt PDF: %w", err),
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				fmt.Sprintf("The password does not open the PDF '%s'", filepath.Base(inputPath)),
+			).WithCode("INVALID_PDF_PASSWORD"),
+		)
+	}
+
+	return fmt.Errorf("decrypt PDF: %w", err)
+}
+
+// outlineRoute returns an [api.Route] which can write an outline, also known
+// as bookmarks, to PDFs.
+func outlineRoute(engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdfengines/outline",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				entries    []gotenberg.PdfOutlineEntry
+				mode       string
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				MandatoryCustom("outline", func(value string) error {
+					err := json.Unmarshal([]byte(value), &entries)
+					if err != nil {
+						return fmt.Errorf("unmarshal outline: %w", err)
+					}
+
+					if len(entries) == 0 {
+						return errors.New("wrong value, expected at least one entry")
+					}
+
+					return nil
+				}).
+				Custom("mode", func(value string) error {
+					if value == "" {
+						mode = "replace"
+						return nil
+					}
+
+					if value != "replace" && value != "merge" {
+						return errors.New("wrong value, expected either 'replace', 'merge' or empty")
+					}
+
+					mode = value
+
+					return nil
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
+			// Alright, let's write the outline to the PDFs.
+			outputPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				outputPaths[i] = ctx.GeneratePath(".pdf")
+
+				err = engine.SetOutline(ctx, ctx.Log(), entries, mode == "replace", inputPath, outputPaths[i])
+				if err != nil {
+					var outOfRangeErr *gotenberg.PdfOutlineOutOfRangeError
+					if errors.As(err, &outOfRangeErr) {
+						return api.WrapError(
+							fmt.Errorf("set PDF outline: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf(
+									"The PDF '%s' has %d page(s), but the following outline entries target a page out of range or before the page of their parent: %s",
+									filepath.Base(inputPath), outOfRangeErr.PageCount, strings.Join(outOfRangeErr.Entries, ", "),
+								),
+							),
+						)
+					}
+
+					return fmt.Errorf("set PDF outline: %w", err)
+				}
+			}
+
+			// Last but not least, add the output paths to the context so that
+			// the API is able to send them as a response to the client.
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// redactRoute returns an [api.Route] which can remove the content under
+// areas of PDFs.
+func redactRoute(engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdfengines/redact",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				redactions []gotenberg.PdfRedaction
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				MandatoryCustom("redactions", func(value string) error {
+					var err error
+					redactions, err = parseRedactions(value)
+
+					return err
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
+			// Alright, let's redact the PDFs.
+			outputPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				outputPaths[i] = ctx.GeneratePath(".pdf")
+
+				err = engine.Redact(ctx, ctx.Log(), redactions, inputPath, outputPaths[i])
+				if err != nil {
+					var outOfBoundsErr *gotenberg.PdfRedactionOutOfBoundsError
+					if errors.As(err, &outOfBoundsErr) {
+						return api.WrapError(
+							fmt.Errorf("redact PDF: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf(
+									"The following redactions are not within the pages of the PDF '%s': %s",
+									filepath.Base(inputPath), strings.Join(outOfBoundsErr.Entries, ", "),
+								),
+							),
+						)
+					}
+
+					if errors.Is(err, gotenberg.ErrPdfRedactionTextNotFound) {
+						return api.WrapError(
+							fmt.Errorf("redact PDF: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf("At least one of the redaction texts is not in the PDF '%s'", filepath.Base(inputPath)),
+							),
+						)
+					}
+
+					return fmt.Errorf("redact PDF: %w", err)
+				}
+			}
+
+			// Last but not least, add the output paths to the context so that
+			// the API is able to send them as a response to the client.
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// parseRedactions parses the "redactions" form field value, i.e., a JSON
+// array of either rectangles or texts. The bounds of the rectangles are
+// checked against the pages by the PDF engines.
+func parseRedactions(value string) ([]gotenberg.PdfRedaction, error) {
+	var redactions []gotenberg.PdfRedaction
+
+	err := json.Unmarshal([]byte(value), &redactions)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal redactions: %w", err)
+	}
+
+	if len(redactions) == 0 {
+		return nil, errors.New("wrong value, expected at least one redaction")
+	}
+
+	for i, redaction := range redactions {
+		if redaction.Text != "" {
+			if redaction.Page < 0 {
+				return nil, fmt.Errorf("redaction %d: wrong page, expected 0 (all pages) or a page number", i)
+			}
+
+			continue
+		}
+
+		if redaction.Page < 1 {
+			return nil, fmt.Errorf("redaction %d: wrong page, expected a page number", i)
+		}
+
+		if redaction.X < 0 || redaction.Y < 0 || redaction.Width <= 0 || redaction.Height <= 0 {
+			return nil, fmt.Errorf("redaction %d: wrong rectangle, expected positive coordinates and dimensions", i)
+		}
+	}
+
+	return redactions, nil
+}
+
+// cropRoute returns an [api.Route] which can crop the pages of PDFs.
+func cropRoute(engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdfengines/crop",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				crop       gotenberg.PdfCrop
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				Custom("boxes", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					var err error
+					crop.Boxes, err = parseCropBoxes(value)
+
+					return err
+				}).
+				Bool("autoTrim", &crop.AutoTrim, false).
+				Custom("margin", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					margin, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						return err
+					}
+
+					if margin < 0 {
+						return errors.New("value is negative")
+					}
+
+					crop.Margin = margin
+
+					return nil
+				}).
+				Custom("pageRanges", func(value string) error {
+					var err error
+					crop.Pages, err = api.ParsePageRanges(value)
+
+					return err
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			if len(crop.Boxes) > 0 && (crop.AutoTrim || crop.Margin > 0 || len(crop.Pages) > 0) {
+				return api.WrapError(
+					errors.New("got both 'boxes' and either 'autoTrim', 'margin' or 'pageRanges' form fields"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "The 'boxes' form field cannot be used with the 'autoTrim', 'margin' or 'pageRanges' form fields"),
+				)
+			}
+
+			if len(crop.Boxes) == 0 && !crop.AutoTrim && crop.Margin == 0 {
+				return api.WrapError(
+					errors.New("got neither 'boxes', 'autoTrim' nor 'margin' form fields"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Either the 'boxes', 'autoTrim' or 'margin' form field is required"),
+				)
+			}
+
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
+			// Alright, let's crop the PDFs.
+			outputPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				outputPaths[i] = ctx.GeneratePath(".pdf")
+
+				err = engine.Crop(ctx, ctx.Log(), crop, inputPath, outputPaths[i])
+				if err != nil {
+					var outOfBoundsErr *gotenberg.PdfCropOutOfBoundsError
+					if errors.As(err, &outOfBoundsErr) {
+						return api.WrapError(
+							fmt.Errorf("crop PDF: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf(
+									"The following crop boxes are not within the media box of the pages of the PDF '%s': %s",
+									filepath.Base(inputPath), strings.Join(outOfBoundsErr.Entries, ", "),
+								),
+							),
+						)
+					}
+
+					return fmt.Errorf("crop PDF: %w", err)
+				}
+			}
+
+			// Last but not least, add the output paths to the context so that
+			// the API is able to send them as a response to the client.
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// parseCropBoxes parses the "boxes" form field value, i.e., a JSON array of
+// areas with at most one per page. The bounds of the areas are checked
+// against the pages by the PDF engines.
+func parseCropBoxes(value string) ([]gotenberg.PdfCropBox, error) {
+	var boxes []gotenberg.PdfCropBox
+
+	err := json.Unmarshal([]byte(value), &boxes)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal boxes: %w", err)
+	}
+
+	pages := make(map[int]bool)
+
+	for i, box := range boxes {
+		if box.Page < 1 {
+			return nil, fmt.Errorf("box %d: wrong page, expected a page number", i)
+		}
+
+		if pages[box.Page] {
+			return nil, fmt.Errorf("box %d: page %d has already a box", i, box.Page)
+		}
+
+		pages[box.Page] = true
+
+		if box.X < 0 || box.Y < 0 || box.Width <= 0 || box.Height <= 0 {
+			return nil, fmt.Errorf("box %d: wrong area, expected positive coordinates and dimensions", i)
+		}
+	}
+
+	return boxes, nil
+}
+
+// textRoute returns an [api.Route] which can extract the text of PDFs.
+func textRoute(engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdfengines/text",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths          []string
+				firstPage, lastPage int
+				perPage             bool
+				outputFormat        string
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				Custom("pages", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					first, last, found := strings.Cut(value, "-")
+
+					var err error
+					firstPage, err = strconv.Atoi(strings.TrimSpace(first))
+					if err != nil || firstPage < 1 {
+						return errors.New("wrong value, expected either a page number (e.g., '2') or a range of pages (e.g., '2-5')")
+					}
+
+					lastPage = firstPage
+					if found {
+						lastPage = 0
+
+						if strings.TrimSpace(last) != "" {
+							lastPage, err = strconv.Atoi(strings.TrimSpace(last))
+							if err != nil || lastPage < firstPage {
+								return errors.New("wrong value, expected either a page number (e.g., '2') or a range of pages (e.g., '2-5')")
+							}
+						}
+					}
+
+					return nil
+				}).
+				Bool("perPage", &perPage, false).
+				Custom("outputFormat", func(value string) error {
+					if value == "" {
+						outputFormat = "json"
+						return nil
+					}
+
+					if value != "json" && value != "txt" {
+						return errors.New("wrong value, expected either 'json' or 'txt'")
+					}
+
+					outputFormat = value
+
+					return nil
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
+			// Alright, let's extract the text of the PDFs. The text files
+			// are named after their PDF, e.g., "report.txt".
+			dirPath := ctx.GeneratePath("")
+
+			err = os.MkdirAll(dirPath, 0o755)
+			if err != nil {
+				return fmt.Errorf("create directory: %w", err)
+			}
+
+			names := api.DocumentNames(inputPaths)
+			textPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				textPaths[i] = filepath.Join(dirPath, fmt.Sprintf("%s.txt", names[i]))
+
+				err = engine.ExtractText(ctx, ctx.Log(), firstPage, lastPage, inputPath, textPaths[i])
+				if err != nil {
+					return fmt.Errorf("extract text from PDF: %w", err)
+				}
+			}
+
+			if outputFormat == "txt" {
+				err = ctx.AddOutputPaths(textPaths...)
+				if err != nil {
+					return fmt.Errorf("add output paths: %w", err)
+				}
+
+				return nil
+			}
+
+			// The JSON output gathers the texts by filename.
+			texts := make(map[string]interface{}, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				content, err := os.ReadFile(textPaths[i])
+				if err != nil {
+					return fmt.Errorf("read text file: %w", err)
+				}
+
+				pages := textPages(string(content))
+				if perPage {
+					texts[filepath.Base(inputPath)] = pages
+					continue
+				}
+
+				texts[filepath.Base(inputPath)] = strings.TrimSpace(strings.Join(pages, "\n"))
+			}
+
+			b, err := json.Marshal(texts)
+			if err != nil {
+				return fmt.Errorf("marshal texts to JSON: %w", err)
+			}
+
+			outputPath := ctx.GeneratePath(".json")
+
+			err = os.WriteFile(outputPath, b, 0o600)
+			if err != nil {
+				return fmt.Errorf("write JSON file: %w", err)
+			}
+
+			// Last but not least, add the output path to the context so that
+			// the API is able to send it as a response to the client.
+
+			err = ctx.AddOutputPaths(outputPath)
+			if err != nil {
+				return fmt.Errorf("add output path: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// checkReport is the JSON representation of a [gotenberg.PdfCheckReport].
+type checkReport struct {
+	Status   string   `json:"status"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// validateRoute returns an [api.Route] which can check the structure of PDFs
+// and return a JSON report by filename, or repair them.
+func validateRoute(engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdfengines/validate",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths     []string
+				repair         bool
+				pdfEngine      string
+				validateEngine gotenberg.PdfEngine
+			)
+
+			err := formDataPdfEngine(ctx.FormData(), engine, &pdfEngine, &validateEngine).
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				Bool("repair", &repair, false).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// The client only wants to validate its request.
+			if ctx.ValidateOnly() {
+				return nil
+			}
+
+			if repair {
+				// Alright, let's repair the PDFs.
+				outputPaths := make([]string, len(inputPaths))
+
+				for i, inputPath := range inputPaths {
+					outputPaths[i] = ctx.GeneratePath(".pdf")
+
+					err = validateEngine.Repair(ctx, ctx.Log(), inputPath, outputPaths[i])
+					if err != nil {
+						if errors.Is(err, gotenberg.ErrPdfNotRepairable) {
+							return api.WrapError(
+								fmt.Errorf("repair PDF: %w", err),
+								api.NewSentinelHttpError(
+									http.StatusBadRequest,
+									fmt.Sprintf("The PDF '%s' cannot be repaired", filepath.Base(inputPath)),
+								).WithCode("PDF_NOT_REPAIRABLE"),
+							)
+						}
+
+						err = handlePdfEngineError(err, pdfEngine)
+
+						return fmt.Errorf("repair PDF: %w", err)
+					}
+				}
+
+				err = ctx.AddOutputPaths(outputPaths...)
+				if err != nil {
+					return fmt.Errorf("add output paths: %w", err)
+				}
+
+				return nil
+			}
+
+			// Alright, let's check the PDFs. The JSON output gathers the
+			// reports by filename.
+			reports := make(map[string]checkReport, len(inputPaths))
+
+			for _, inputPath := range inputPaths {
+				report, err := validateEngine.Check(ctx, ctx.Log(), inputPath)
+				if err != nil {
+					err = handlePdfEngineError(err, pdfEngine)
+
+					return fmt.Errorf("check PDF: %w", err)
+				}
+
+				reports[filepath.Base(inputPath)] = checkReport{
+					Status:   report.Status(),
+					Errors:   append(make([]string, 0, len(report.Errors)), report.Errors...),
+					Warnings: append(make([]string, 0, len(report.Warnings)), report.Warnings...),
+				}
+			}
+
+			b, err := json.Marshal(reports)
+			if err != nil {
+				return fmt.Errorf("marshal reports to JSON: %w", err)
+			}
+
+			outputPath := ctx.GeneratePath(".json")
+
+			err = os.WriteFile(outputPath, b, 0o600)
+			if err != nil {
+				return fmt.Errorf("write JSON file: %w", err)
+			}
+
+			// Last but not least, add the output path to the context so that
+			// the API is able to send it as a response to the client.
+
+			err = ctx.AddOutputPaths(outputPath)
+			if err != nil {
+				return fmt.Errorf("add output path: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// textPages splits the text of a PDF into pages. A page without text, e.g.,
+// from a scanned document, results in an empty string.
+func textPages(content string) []string {
+	// Each page ends with a form feed character.
+	pages := strings.Split(strings.TrimSuffix(content, "\f"), "\f")
+
+	for i, page := range pages {
+		pages[i] = strings.TrimSpace(page)
+	}
+
+	return pages
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -75,6 +76,76 @@ func (engine *PdfTk) Convert(ctx context.Context, logger *zap.Logger, formats go
 	return fmt.Errorf("convert PDF to '%+v' with PDFtk: %w", formats, gotenberg.ErrPdfEngineMethodNotSupported)
 }
 
+// Decrypt is not available in this implementation.
+func (engine *PdfTk) Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error {
+	return fmt.Errorf("decrypt PDF with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ExtractText is not available in this implementation.
+func (engine *PdfTk) ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+	return fmt.Errorf("extract text from PDF with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Ocr is not available in this implementation.
+func (engine *PdfTk) Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error {
+	return fmt.Errorf("OCR PDF with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Normalize is not available in this implementation.
+func (engine *PdfTk) Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+	return fmt.Errorf("normalize PDF with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SetOutline is not available in this implementation.
+func (engine *PdfTk) SetOutline(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+	return fmt.Errorf("set PDF outline with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ReadOutline is not available in this implementation.
+func (engine *PdfTk) ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+	return gotenberg.PdfOutline{}, fmt.Errorf("read PDF outline with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Redact is not available in this implementation.
+func (engine *PdfTk) Redact(ctx context.Context, logger *zap.Logger, redactions []gotenberg.PdfRedaction, inputPath, outputPath string) error {
+	return fmt.Errorf("redact PDF with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// StampPageNumbers is not available in this implementation.
+func (engine *PdfTk) StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+	return fmt.Errorf("stamp page numbers with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Crop is not available in this implementation.
+func (engine *PdfTk) Crop(ctx context.Context, logger *zap.Logger, crop gotenberg.PdfCrop, inputPath, outputPath string) error {
+	return fmt.Errorf("crop PDF with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ImportImages is not available in this implementation.
+func (engine *PdfTk) ImportImages(ctx context.Context, logger *zap.Logger, images []gotenberg.PdfImage, outputPath string) error {
+	return fmt.Errorf("import images with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Check is not available in this implementation.
+func (engine *PdfTk) Check(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfCheckReport, error) {
+	return gotenberg.PdfCheckReport{}, fmt.Errorf("check PDF with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Repair is not available in this implementation.
+func (engine *PdfTk) Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	return fmt.Errorf("repair PDF with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Rasterize is not available in this implementation.
+func (engine *PdfTk) Rasterize(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("rasterize PDF with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SplitPages is not available in this implementation.
+func (engine *PdfTk) SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("split PDF pages with PDFtk: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module      = (*PdfTk)(nil)
@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -148,3 +149,129 @@ func TestPdfTk_Convert(t *testing.T) {
 		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
 	}
 }
+
+func TestPdfTk_Decrypt(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.Decrypt(context.TODO(), zap.NewNop(), "", "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_ExtractText(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.ExtractText(context.TODO(), zap.NewNop(), 0, 0, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_Ocr(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.Ocr(context.TODO(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_Normalize(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.Normalize(context.TODO(), zap.NewNop(), time.Time{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_SetOutline(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.SetOutline(context.TODO(), zap.NewNop(), nil, false, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_ReadOutline(t *testing.T) {
+	engine := new(PdfTk)
+	_, err := engine.ReadOutline(context.TODO(), zap.NewNop(), "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_Redact(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.Redact(context.TODO(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_StampPageNumbers(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.StampPageNumbers(context.TODO(), zap.NewNop(), gotenberg.PdfPageNumbers{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_Crop(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.Crop(context.TODO(), zap.NewNop(), gotenberg.PdfCrop{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_ImportImages(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.ImportImages(context.TODO(), zap.NewNop(), nil, "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_Check(t *testing.T) {
+	engine := new(PdfTk)
+	_, err := engine.Check(context.TODO(), zap.NewNop(), "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_Repair(t *testing.T) {
+	engine := new(PdfTk)
+	err := engine.Repair(context.TODO(), zap.NewNop(), "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_Rasterize(t *testing.T) {
+	engine := new(PdfTk)
+	_, err := engine.Rasterize(context.TODO(), zap.NewNop(), gotenberg.PdfRaster{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestPdfTk_SplitPages(t *testing.T) {
+	engine := new(PdfTk)
+	_, err := engine.SplitPages(context.TODO(), zap.NewNop(), "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
@@ -0,0 +1,8 @@
+// Package pdftotext provides an implementation of the gotenberg.PdfEngine
+// interface using the pdftotext command-line tool from Poppler. This package
+// allows for the extraction of the text of PDF files, but does not support
+// other PDF operations. The path to the pdftotext binary must be specified
+// using the PDFTOTEXT_BIN_PATH environment variable.
+//
+// See: https://poppler.freedesktop.org.
+package pdftotext
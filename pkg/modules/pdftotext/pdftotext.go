@@ -0,0 +1,165 @@
+package pdftotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(PdfToText))
+}
+
+// PdfToText abstracts the CLI tool pdftotext and implements the
+// [gotenberg.PdfEngine] interface.
+type PdfToText struct {
+	binPath string
+}
+
+// Descriptor returns a [PdfToText]'s module descriptor.
+func (engine *PdfToText) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID:  "pdftotext",
+		New: func() gotenberg.Module { return new(PdfToText) },
+	}
+}
+
+// Provision sets the modules properties.
+func (engine *PdfToText) Provision(ctx *gotenberg.Context) error {
+	binPath, ok := os.LookupEnv("PDFTOTEXT_BIN_PATH")
+	if !ok {
+		return errors.New("PDFTOTEXT_BIN_PATH environment variable is not set")
+	}
+
+	engine.binPath = binPath
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (engine *PdfToText) Validate() error {
+	_, err := os.Stat(engine.binPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("pdftotext binary path does not exist: %w", err)
+	}
+
+	return nil
+}
+
+// Merge is not available in this implementation.
+func (engine *PdfToText) Merge(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+	return fmt.Errorf("merge PDFs with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Convert is not available in this implementation.
+func (engine *PdfToText) Convert(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+	return fmt.Errorf("convert PDF to '%+v' with pdftotext: %w", formats, gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Decrypt is not available in this implementation.
+func (engine *PdfToText) Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error {
+	return fmt.Errorf("decrypt PDF with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ExtractText extracts the UTF-8 text of a PDF. pdftotext separates the pages
+// with a form feed character.
+func (engine *PdfToText) ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+	args := []string{"-enc", "UTF-8"}
+
+	if firstPage > 0 {
+		args = append(args, "-f", strconv.Itoa(firstPage))
+	}
+
+	if lastPage > 0 {
+		args = append(args, "-l", strconv.Itoa(lastPage))
+	}
+
+	args = append(args, inputPath, outputPath)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, args...)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("extract text from PDF with pdftotext: %w", err)
+}
+
+// Ocr is not available in this implementation.
+func (engine *PdfToText) Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error {
+	return fmt.Errorf("OCR PDF with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Normalize is not available in this implementation.
+func (engine *PdfToText) Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+	return fmt.Errorf("normalize PDF with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SetOutline is not available in this implementation.
+func (engine *PdfToText) SetOutline(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+	return fmt.Errorf("set PDF outline with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ReadOutline is not available in this implementation.
+func (engine *PdfToText) ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+	return gotenberg.PdfOutline{}, fmt.Errorf("read PDF outline with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Redact is not available in this implementation.
+func (engine *PdfToText) Redact(ctx context.Context, logger *zap.Logger, redactions []gotenberg.PdfRedaction, inputPath, outputPath string) error {
+	return fmt.Errorf("redact PDF with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// StampPageNumbers is not available in this implementation.
+func (engine *PdfToText) StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+	return fmt.Errorf("stamp page numbers with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Crop is not available in this implementation.
+func (engine *PdfToText) Crop(ctx context.Context, logger *zap.Logger, crop gotenberg.PdfCrop, inputPath, outputPath string) error {
+	return fmt.Errorf("crop PDF with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ImportImages is not available in this implementation.
+func (engine *PdfToText) ImportImages(ctx context.Context, logger *zap.Logger, images []gotenberg.PdfImage, outputPath string) error {
+	return fmt.Errorf("import images with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Check is not available in this implementation.
+func (engine *PdfToText) Check(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfCheckReport, error) {
+	return gotenberg.PdfCheckReport{}, fmt.Errorf("check PDF with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Repair is not available in this implementation.
+func (engine *PdfToText) Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	return fmt.Errorf("repair PDF with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Rasterize is not available in this implementation.
+func (engine *PdfToText) Rasterize(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("rasterize PDF with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SplitPages is not available in this implementation.
+func (engine *PdfToText) SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("split PDF pages with pdftotext: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*PdfToText)(nil)
+	_ gotenberg.Provisioner = (*PdfToText)(nil)
+	_ gotenberg.Validator   = (*PdfToText)(nil)
+	_ gotenberg.PdfEngine   = (*PdfToText)(nil)
+)
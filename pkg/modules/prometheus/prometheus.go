@@ -105,10 +105,14 @@ func (mod *Prometheus) Validate() error {
 			return errors.New("metric name cannot be empty")
 		}
 
-		if metric.Read == nil {
+		if metric.Label == "" && metric.Read == nil {
 			return fmt.Errorf("metric '%s' has nil read method", metric.Name)
 		}
 
+		if metric.Label != "" && metric.ReadByLabel == nil {
+			return fmt.Errorf("metric '%s' has nil read by label method", metric.Name)
+		}
+
 		if _, ok := metricsMap[metric.Name]; ok {
 			return fmt.Errorf("metric '%s' is already registered", metric.Name)
 		}
@@ -127,6 +131,30 @@ func (mod *Prometheus) Start() error {
 	}
 
 	for _, metric := range mod.metrics {
+		if metric.Label != "" {
+			gaugeVec := prometheus.NewGaugeVec(
+				prometheus.GaugeOpts{
+					Namespace: mod.namespace,
+					Name:      metric.Name,
+					Help:      metric.Description,
+				},
+				[]string{metric.Label},
+			)
+
+			mod.registry.MustRegister(gaugeVec)
+
+			go func(gaugeVec *prometheus.GaugeVec, metric gotenberg.Metric) {
+				for {
+					for label, value := range metric.ReadByLabel() {
+						gaugeVec.WithLabelValues(label).Set(value)
+					}
+					time.Sleep(mod.interval)
+				}
+			}(gaugeVec, metric)
+
+			continue
+		}
+
 		gauge := prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: mod.namespace,
@@ -204,6 +204,19 @@ func TestPrometheus_Validate(t *testing.T) {
 			disableCollect: false,
 			expectError:    true,
 		},
+		{
+			scenario:  "nil read by label metric method",
+			namespace: "foo",
+			metrics: []gotenberg.Metric{
+				{
+					Name:        "foo",
+					Label:       "route",
+					ReadByLabel: nil,
+				},
+			},
+			disableCollect: false,
+			expectError:    true,
+		},
 		{
 			scenario:  "already registered metric",
 			namespace: "foo",
@@ -234,6 +247,13 @@ func TestPrometheus_Validate(t *testing.T) {
 						return 0
 					},
 				},
+				{
+					Name:  "baz",
+					Label: "route",
+					ReadByLabel: func() map[string]float64 {
+						return nil
+					},
+				},
 				{
 					Name: "bar",
 					Read: func() float64 {
@@ -283,6 +303,13 @@ func TestPrometheus_Start(t *testing.T) {
 						return 0
 					},
 				},
+				{
+					Name:  "bar",
+					Label: "route",
+					ReadByLabel: func() map[string]float64 {
+						return map[string]float64{"/forms/foo": 1}
+					},
+				},
 			},
 		},
 	} {
@@ -2,10 +2,15 @@ package qpdf
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
@@ -77,6 +82,337 @@ func (engine *QPdf) Convert(ctx context.Context, logger *zap.Logger, formats got
 	return fmt.Errorf("convert PDF to '%+v' with QPDF: %w", formats, gotenberg.ErrPdfEngineMethodNotSupported)
 }
 
+// Decrypt removes the encryption of a PDF. The password is given to QPDF
+// through a temporary file, so that it does not appear in the command line
+// (and therefore in the logs).
+func (engine *QPdf) Decrypt(ctx context.Context, logger *zap.Logger, password, inputPath, outputPath string) error {
+	passwordPath := fmt.Sprintf("%s/%s.txt", filepath.Dir(outputPath), uuid.New())
+
+	err := os.WriteFile(passwordPath, []byte(password), 0o600)
+	if err != nil {
+		return fmt.Errorf("write password file: %w", err)
+	}
+
+	defer func() {
+		err := os.Remove(passwordPath)
+		if err != nil {
+			logger.Error(fmt.Sprintf("remove password file: %s", err))
+		}
+	}()
+
+	passwordArg := fmt.Sprintf("--password-file=%s", passwordPath)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, passwordArg, "--decrypt", inputPath, outputPath)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	// QPDF does not have a specific exit code for an invalid password. Yet,
+	// with the --requires-password option, it exits with 0 if the given
+	// password does not open the PDF.
+	checkCmd, checkErr := gotenberg.CommandContext(ctx, logger, engine.binPath, passwordArg, "--requires-password", inputPath)
+	if checkErr != nil {
+		return fmt.Errorf("decrypt PDF with QPDF: %w", err)
+	}
+
+	exitCode, _ := checkCmd.Exec()
+	if exitCode == 0 {
+		return fmt.Errorf("decrypt PDF with QPDF: %w", gotenberg.ErrPdfInvalidPassword)
+	}
+
+	return fmt.Errorf("decrypt PDF with QPDF: %w", err)
+}
+
+// ExtractText is not available in this implementation.
+func (engine *QPdf) ExtractText(ctx context.Context, logger *zap.Logger, firstPage, lastPage int, inputPath, outputPath string) error {
+	return fmt.Errorf("extract text from PDF with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Ocr is not available in this implementation.
+func (engine *QPdf) Ocr(ctx context.Context, logger *zap.Logger, languages []string, inputPath, outputPath string) error {
+	return fmt.Errorf("OCR PDF with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Normalize makes a PDF reproducible. QPDF derives the file identifier from
+// the content, while the dates of the document information dictionary are
+// replaced thanks to a JSON update. The XMP metadata, if any, are left
+// untouched: the routes reject the PDF formats, which require them, along
+// with reproducible PDFs.
+func (engine *QPdf) Normalize(ctx context.Context, logger *zap.Logger, date time.Time, inputPath, outputPath string) error {
+	dirPath := filepath.Dir(outputPath)
+	jsonPath := fmt.Sprintf("%s/%s.json", dirPath, uuid.New())
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, "--json-output=2", "--json-stream-data=none", inputPath, jsonPath)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return fmt.Errorf("export PDF to JSON with QPDF: %w", err)
+	}
+
+	content, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return fmt.Errorf("read JSON file: %w", err)
+	}
+
+	update, err := infoDatesUpdate(content, date)
+	if err != nil {
+		return fmt.Errorf("create JSON update: %w", err)
+	}
+
+	args := []string{"--deterministic-id"}
+
+	if update != nil {
+		updatePath := fmt.Sprintf("%s/%s.json", dirPath, uuid.New())
+
+		err = os.WriteFile(updatePath, update, 0o600)
+		if err != nil {
+			return fmt.Errorf("write JSON update file: %w", err)
+		}
+
+		args = append(args, fmt.Sprintf("--update-from-json=%s", updatePath))
+	}
+
+	args = append(args, inputPath, outputPath)
+
+	cmd, err = gotenberg.CommandContext(ctx, logger, engine.binPath, args...)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("normalize PDF with QPDF: %w", err)
+}
+
+// SetOutline is not available in this implementation.
+func (engine *QPdf) SetOutline(ctx context.Context, logger *zap.Logger, entries []gotenberg.PdfOutlineEntry, replace bool, inputPath, outputPath string) error {
+	return fmt.Errorf("set PDF outline with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ReadOutline is not available in this implementation.
+func (engine *QPdf) ReadOutline(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfOutline, error) {
+	return gotenberg.PdfOutline{}, fmt.Errorf("read PDF outline with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Redact is not available in this implementation.
+func (engine *QPdf) Redact(ctx context.Context, logger *zap.Logger, redactions []gotenberg.PdfRedaction, inputPath, outputPath string) error {
+	return fmt.Errorf("redact PDF with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// StampPageNumbers is not available in this implementation.
+func (engine *QPdf) StampPageNumbers(ctx context.Context, logger *zap.Logger, numbers gotenberg.PdfPageNumbers, inputPath, outputPath string) error {
+	return fmt.Errorf("stamp page numbers with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// Crop is not available in this implementation.
+func (engine *QPdf) Crop(ctx context.Context, logger *zap.Logger, crop gotenberg.PdfCrop, inputPath, outputPath string) error {
+	return fmt.Errorf("crop PDF with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// ImportImages is not available in this implementation.
+func (engine *QPdf) ImportImages(ctx context.Context, logger *zap.Logger, images []gotenberg.PdfImage, outputPath string) error {
+	return fmt.Errorf("import images with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// QPDF exit codes, see https://qpdf.readthedocs.io/en/stable/cli.html#exit-status.
+const (
+	exitCodeErrors   = 2
+	exitCodeWarnings = 3
+)
+
+// Check verifies the structure of a PDF thanks to the --check option of
+// QPDF, which exits with 2 if the PDF has errors, or with 3 if it only has
+// warnings.
+func (engine *QPdf) Check(ctx context.Context, logger *zap.Logger, inputPath string) (gotenberg.PdfCheckReport, error) {
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, "--check", inputPath)
+	if err != nil {
+		return gotenberg.PdfCheckReport{}, fmt.Errorf("create command: %w", err)
+	}
+
+	output, exitCode, err := cmd.ExecOutput()
+	if err != nil && exitCode != exitCodeErrors && exitCode != exitCodeWarnings {
+		return gotenberg.PdfCheckReport{}, fmt.Errorf("check PDF with QPDF: %w", err)
+	}
+
+	return checkReport(string(output), inputPath, exitCode), nil
+}
+
+// Repair rewrites a PDF. QPDF reconstructs a damaged PDF while reading it,
+// and exits with 3 if it had to.
+func (engine *QPdf) Repair(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, inputPath, outputPath)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	exitCode, err := cmd.Exec()
+	if err == nil || exitCode == exitCodeWarnings {
+		return nil
+	}
+
+	if exitCode == exitCodeErrors {
+		return fmt.Errorf("repair PDF with QPDF: %w", gotenberg.ErrPdfNotRepairable)
+	}
+
+	return fmt.Errorf("repair PDF with QPDF: %w", err)
+}
+
+// Rasterize is not available in this implementation.
+func (engine *QPdf) Rasterize(ctx context.Context, logger *zap.Logger, raster gotenberg.PdfRaster, inputPath, outputPathPrefix string) ([]string, error) {
+	return nil, fmt.Errorf("rasterize PDF with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
+// SplitPages writes each page of a PDF to its own PDF. QPDF replaces the %d
+// of the output path with the page number, zero-padded to the same width
+// for all the pages, so that the PDFs sort in the order of the pages.
+func (engine *QPdf) SplitPages(ctx context.Context, logger *zap.Logger, inputPath, outputPathPrefix string) ([]string, error) {
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, "--split-pages", inputPath, fmt.Sprintf("%s-%%d.pdf", outputPathPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("create command: %w", err)
+	}
+
+	exitCode, err := cmd.Exec()
+	if err != nil && exitCode != exitCodeWarnings {
+		return nil, fmt.Errorf("split PDF pages with QPDF: %w", err)
+	}
+
+	outputPaths, err := filepath.Glob(outputPathPrefix + "-*.pdf")
+	if err != nil {
+		return nil, fmt.Errorf("glob pages: %w", err)
+	}
+
+	if len(outputPaths) == 0 {
+		return nil, errors.New("split PDF pages with QPDF: no page written")
+	}
+
+	return outputPaths, nil
+}
+
+// checkReport parses the output of the --check option of QPDF. The
+// warnings start with "WARNING: ", while the errors are the other
+// diagnostics, if QPDF exited with 2. The path of the PDF is replaced by its
+// filename.
+func checkReport(output, inputPath string, exitCode int) gotenberg.PdfCheckReport {
+	var report gotenberg.PdfCheckReport
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.ReplaceAll(line, inputPath, filepath.Base(inputPath)))
+
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "WARNING: "):
+			report.Warnings = append(report.Warnings, strings.TrimPrefix(line, "WARNING: "))
+		case exitCode == exitCodeErrors && (strings.HasPrefix(line, "qpdf: ") || strings.HasPrefix(line, "ERROR: ") || strings.HasPrefix(line, filepath.Base(inputPath))):
+			report.Errors = append(report.Errors, strings.TrimPrefix(strings.TrimPrefix(line, "qpdf: "), "ERROR: "))
+		}
+	}
+
+	if exitCode == exitCodeErrors && len(report.Errors) == 0 {
+		report.Errors = append(report.Errors, "QPDF found errors")
+	}
+
+	return report
+}
+
+// infoDatesUpdate creates a QPDF JSON update, which sets the creation and
+// modification dates of the document information dictionary. It returns nil
+// if the PDF does not have such a dictionary.
+func infoDatesUpdate(content []byte, date time.Time) ([]byte, error) {
+	var export struct {
+		Qpdf []json.RawMessage `json:"qpdf"`
+	}
+
+	err := json.Unmarshal(content, &export)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal JSON: %w", err)
+	}
+
+	if len(export.Qpdf) != 2 {
+		return nil, fmt.Errorf("expected 2 elements in the 'qpdf' key, got %d", len(export.Qpdf))
+	}
+
+	var objects map[string]struct {
+		Value json.RawMessage `json:"value"`
+	}
+
+	err = json.Unmarshal(export.Qpdf[1], &objects)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal objects: %w", err)
+	}
+
+	var trailer map[string]interface{}
+
+	err = json.Unmarshal(objects["trailer"].Value, &trailer)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal trailer: %w", err)
+	}
+
+	// The document information dictionary is usually an indirect object,
+	// e.g., "2 0 R", but may also be directly in the trailer.
+	var (
+		key  string
+		info map[string]interface{}
+	)
+
+	switch value := trailer["/Info"].(type) {
+	case string:
+		key = fmt.Sprintf("obj:%s", value)
+
+		err = json.Unmarshal(objects[key].Value, &info)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal '%s': %w", key, err)
+		}
+	case map[string]interface{}:
+		key = "trailer"
+		info = value
+	default:
+		return nil, nil
+	}
+
+	if info == nil {
+		return nil, nil
+	}
+
+	pdfDate := fmt.Sprintf("u:%s", date.UTC().Format("D:20060102150405Z"))
+	info["/CreationDate"] = pdfDate
+	info["/ModDate"] = pdfDate
+
+	if key == "trailer" {
+		trailer["/Info"] = info
+		info = trailer
+	}
+
+	update := map[string]interface{}{
+		"qpdf": []interface{}{
+			export.Qpdf[0],
+			map[string]interface{}{
+				key: map[string]interface{}{
+					"value": info,
+				},
+			},
+		},
+	}
+
+	b, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("marshal JSON update: %w", err)
+	}
+
+	return b, nil
+}
+
 var (
 	_ gotenberg.Module      = (*QPdf)(nil)
 	_ gotenberg.Provisioner = (*QPdf)(nil)
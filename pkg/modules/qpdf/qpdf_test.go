@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -148,3 +149,484 @@ func TestQPdf_Convert(t *testing.T) {
 		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
 	}
 }
+
+func TestQPdf_Decrypt(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         context.Context
+		password    string
+		inputPath   string
+		expectError bool
+		expectedErr error
+	}{
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid input path",
+			ctx:         context.TODO(),
+			password:    "foo",
+			inputPath:   "foo",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid password",
+			ctx:         context.TODO(),
+			password:    "bar",
+			inputPath:   "/tests/test/testdata/pdfengines/sample1_encrypted.pdf",
+			expectError: true,
+			expectedErr: gotenberg.ErrPdfInvalidPassword,
+		},
+		{
+			scenario:  "success",
+			ctx:       context.TODO(),
+			password:  "foo",
+			inputPath: "/tests/test/testdata/pdfengines/sample1_encrypted.pdf",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(QPdf)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			err = engine.Decrypt(tc.ctx, zap.NewNop(), tc.password, tc.inputPath, outputDir+"/foo.pdf")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestQPdf_ExtractText(t *testing.T) {
+	engine := new(QPdf)
+	err := engine.ExtractText(context.TODO(), zap.NewNop(), 0, 0, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestQPdf_Ocr(t *testing.T) {
+	engine := new(QPdf)
+	err := engine.Ocr(context.TODO(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestQPdf_SetOutline(t *testing.T) {
+	engine := new(QPdf)
+	err := engine.SetOutline(context.TODO(), zap.NewNop(), nil, false, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestQPdf_ReadOutline(t *testing.T) {
+	engine := new(QPdf)
+	_, err := engine.ReadOutline(context.TODO(), zap.NewNop(), "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestQPdf_Redact(t *testing.T) {
+	engine := new(QPdf)
+	err := engine.Redact(context.TODO(), zap.NewNop(), nil, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestQPdf_StampPageNumbers(t *testing.T) {
+	engine := new(QPdf)
+	err := engine.StampPageNumbers(context.TODO(), zap.NewNop(), gotenberg.PdfPageNumbers{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestQPdf_Crop(t *testing.T) {
+	engine := new(QPdf)
+	err := engine.Crop(context.TODO(), zap.NewNop(), gotenberg.PdfCrop{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestQPdf_ImportImages(t *testing.T) {
+	engine := new(QPdf)
+	err := engine.ImportImages(context.TODO(), zap.NewNop(), nil, "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestQPdf_Check(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		ctx          context.Context
+		inputPath    string
+		expectStatus string
+		expectError  bool
+	}{
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			expectError: true,
+		},
+		{
+			scenario:     "invalid input path",
+			ctx:          context.TODO(),
+			inputPath:    "foo",
+			expectStatus: gotenberg.PdfCheckInvalid,
+		},
+		{
+			scenario:     "success",
+			ctx:          context.TODO(),
+			inputPath:    "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectStatus: gotenberg.PdfCheckValid,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(QPdf)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			report, err := engine.Check(tc.ctx, zap.NewNop(), tc.inputPath)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if err == nil && report.Status() != tc.expectStatus {
+				t.Errorf("expected status '%s' but got '%s'", tc.expectStatus, report.Status())
+			}
+		})
+	}
+}
+
+func TestQPdf_Repair(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         context.Context
+		inputPath   string
+		expectError bool
+	}{
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid input path",
+			ctx:         context.TODO(),
+			inputPath:   "foo",
+			expectError: true,
+		},
+		{
+			scenario:  "success",
+			ctx:       context.TODO(),
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(QPdf)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			err = engine.Repair(tc.ctx, zap.NewNop(), tc.inputPath, outputDir+"/foo.pdf")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestCheckReport(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		output         string
+		exitCode       int
+		expectWarnings []string
+		expectErrors   []string
+	}{
+		{
+			scenario: "valid",
+			output:   "checking /tmp/foo.pdf\nPDF Version: 1.4\nFile is not encrypted\nFile is not linearized\nNo syntax or stream encoding errors found; the file may still contain\nerrors that qpdf cannot detect\n",
+			exitCode: 0,
+		},
+		{
+			scenario:       "valid with warnings",
+			output:         "checking /tmp/foo.pdf\nWARNING: /tmp/foo.pdf: file is damaged\nWARNING: /tmp/foo.pdf (offset 1234): xref not found\nqpdf: operation succeeded with warnings\n",
+			exitCode:       3,
+			expectWarnings: []string{"foo.pdf: file is damaged", "foo.pdf (offset 1234): xref not found"},
+		},
+		{
+			scenario:       "invalid",
+			output:         "WARNING: /tmp/foo.pdf: file is damaged\nqpdf: /tmp/foo.pdf: unable to find trailer dictionary while recovering damaged file\n",
+			exitCode:       2,
+			expectWarnings: []string{"foo.pdf: file is damaged"},
+			expectErrors:   []string{"foo.pdf: unable to find trailer dictionary while recovering damaged file"},
+		},
+		{
+			scenario:     "invalid without diagnostics",
+			output:       "checking /tmp/foo.pdf\n",
+			exitCode:     2,
+			expectErrors: []string{"QPDF found errors"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			report := checkReport(tc.output, "/tmp/foo.pdf", tc.exitCode)
+
+			if !reflect.DeepEqual(report.Warnings, tc.expectWarnings) {
+				t.Errorf("expected warnings %+v but got: %+v", tc.expectWarnings, report.Warnings)
+			}
+
+			if !reflect.DeepEqual(report.Errors, tc.expectErrors) {
+				t.Errorf("expected errors %+v but got: %+v", tc.expectErrors, report.Errors)
+			}
+		})
+	}
+}
+
+func TestQPdf_Normalize(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         context.Context
+		inputPath   string
+		expectError bool
+	}{
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid input path",
+			ctx:         context.TODO(),
+			inputPath:   "foo",
+			expectError: true,
+		},
+		{
+			scenario:  "success",
+			ctx:       context.TODO(),
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(QPdf)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			err = engine.Normalize(tc.ctx, zap.NewNop(), time.Unix(0, 0), tc.inputPath, outputDir+"/foo.pdf")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestInfoDatesUpdate(t *testing.T) {
+	header := `{"jsonversion":2,"maxobjectid":3}`
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tc := range []struct {
+		scenario     string
+		content      string
+		expectUpdate string
+		expectError  bool
+	}{
+		{
+			scenario:    "invalid JSON",
+			content:     "foo",
+			expectError: true,
+		},
+		{
+			scenario:    "missing objects",
+			content:     `{"qpdf":[` + header + `]}`,
+			expectError: true,
+		},
+		{
+			scenario: "no document information dictionary",
+			content:  `{"qpdf":[` + header + `,{"trailer":{"value":{"/Root":"1 0 R"}}}]}`,
+		},
+		{
+			scenario:     "indirect document information dictionary",
+			content:      `{"qpdf":[` + header + `,{"obj:2 0 R":{"value":{"/Title":"u:foo","/CreationDate":"u:D:20230101000000Z"}},"trailer":{"value":{"/Info":"2 0 R","/Root":"1 0 R"}}}]}`,
+			expectUpdate: `{"qpdf":[` + header + `,{"obj:2 0 R":{"value":{"/CreationDate":"u:D:20240102030405Z","/ModDate":"u:D:20240102030405Z","/Title":"u:foo"}}}]}`,
+		},
+		{
+			scenario:     "direct document information dictionary",
+			content:      `{"qpdf":[` + header + `,{"trailer":{"value":{"/Info":{"/Title":"u:foo"},"/Root":"1 0 R"}}}]}`,
+			expectUpdate: `{"qpdf":[` + header + `,{"trailer":{"value":{"/Info":{"/CreationDate":"u:D:20240102030405Z","/ModDate":"u:D:20240102030405Z","/Title":"u:foo"},"/Root":"1 0 R"}}}]}`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			update, err := infoDatesUpdate([]byte(tc.content), date)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if string(update) != tc.expectUpdate {
+				t.Errorf("expected '%s' but got '%s'", tc.expectUpdate, string(update))
+			}
+		})
+	}
+}
+
+func TestQPdf_Rasterize(t *testing.T) {
+	engine := new(QPdf)
+	_, err := engine.Rasterize(context.TODO(), zap.NewNop(), gotenberg.PdfRaster{}, "", "")
+
+	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+	}
+}
+
+func TestQPdf_SplitPages(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		ctx               context.Context
+		inputPath         string
+		expectError       bool
+		expectOutputPaths int
+	}{
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid input path",
+			ctx:         context.TODO(),
+			inputPath:   "foo",
+			expectError: true,
+		},
+		{
+			scenario:          "success",
+			ctx:               context.TODO(),
+			inputPath:         "/tests/test/testdata/pdfengines/sample1.pdf",
+			expectOutputPaths: 3,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(QPdf)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			outputPaths, err := engine.SplitPages(tc.ctx, zap.NewNop(), tc.inputPath, outputDir+"/foo")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if len(outputPaths) != tc.expectOutputPaths {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPaths, len(outputPaths))
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package ratelimit provides a module which adds a token bucket rate limiter
+// to the multipart/form-data routes. Clients are identified either by a
+// header the auth module validates (e.g., an API key) or by their IP.
+package ratelimit
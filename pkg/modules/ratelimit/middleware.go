@@ -0,0 +1,166 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func rateLimitMiddleware(mod *RateLimit) api.Middleware {
+	defaultLimiter := newLimiter(limit{rate: mod.rate, burst: mod.burst})
+
+	routeLimiters := make(map[string]*limiter, len(mod.routes))
+	for path, l := range mod.routes {
+		routeLimiters[path] = newLimiter(l)
+	}
+
+	// The X-Forwarded-For and X-Real-IP headers are only honored from the
+	// trusted proxies, as any client may set them.
+	extractIP := echo.ExtractIPDirect()
+	if len(mod.trustedProxies) > 0 {
+		trustOptions := []echo.TrustOption{
+			echo.TrustLoopback(false),
+			echo.TrustLinkLocal(false),
+			echo.TrustPrivateNet(false),
+		}
+
+		for _, ipRange := range mod.trustedProxies {
+			trustOptions = append(trustOptions, echo.TrustIPRange(ipRange))
+		}
+
+		extractIP = echo.ExtractIPFromXFFHeader(trustOptions...)
+	}
+
+	return api.Middleware{
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					// c.Path() contains the root path of the API, so that we
+					// have to look for the route path at its end. The health
+					// check and metrics routes are not multipart/form-data
+					// routes; they are never limited.
+					path := c.Path()
+					if !strings.Contains(path, "/forms/") {
+						return next(c)
+					}
+
+					// Several route paths may match (e.g., /convert and
+					// /forms/chromium/convert); the longest one is the most
+					// specific.
+					l := defaultLimiter
+					matchLen := 0
+					for routePath, routeLimiter := range routeLimiters {
+						if len(routePath) > matchLen && strings.HasSuffix(path, routePath) {
+							l = routeLimiter
+							matchLen = len(routePath)
+						}
+					}
+
+					// Any client may make up a value for the key header; it
+					// only identifies a client once the auth module has
+					// validated it.
+					key := extractIP(c.Request())
+					authHeader, _ := c.Get("authHeader").(string)
+					if mod.keyHeader != "" && strings.EqualFold(mod.keyHeader, authHeader) {
+						value := c.Request().Header.Get(mod.keyHeader)
+						if value != "" {
+							key = value
+						}
+					}
+
+					ok, retryAfter := l.allow(key)
+					if ok {
+						return next(c)
+					}
+
+					c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+
+					return api.WrapError(
+						errors.New("rate limit exceeded"),
+						api.NewSentinelHttpError(
+							http.StatusTooManyRequests,
+							fmt.Sprintf("Too many requests, retry in %s", retryAfter.Round(time.Second)),
+						),
+					)
+				}
+			}
+		}(),
+	}
+}
+
+// bucket is the token bucket of a client.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// limiter keeps one token bucket per client. Each bucket starts full, and
+// regains tokens at the given rate, up to the given burst.
+type limiter struct {
+	limit     limit
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+func newLimiter(l limit) *limiter {
+	return &limiter{
+		limit:     l,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// allow consumes a token from the bucket of the given key. If the bucket is
+// empty, it returns false and the duration after which a token will be
+// available.
+func (l *limiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(l.limit.burst), b.tokens+now.Sub(b.last).Seconds()*l.limit.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	return false, time.Duration((1 - b.tokens) / l.limit.rate * float64(time.Second))
+}
+
+// sweep removes, at most once per minute, the buckets which are full again,
+// as they are no different from new buckets.
+func (l *limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.limit.rate >= float64(l.limit.burst) {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.lastSweep = now
+}
@@ -0,0 +1,210 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	next := func(c echo.Context) error {
+		return nil
+	}
+
+	for _, tc := range []struct {
+		scenario         string
+		mod              *RateLimit
+		path             string
+		keys             []string
+		authHeader       string
+		forwardedFor     bool
+		expectHttpStatus int
+		expectRetryAfter string
+	}{
+		{
+			scenario:         "not a multipart/form-data route",
+			mod:              &RateLimit{rate: 1, burst: 1},
+			path:             "/health",
+			keys:             []string{"", "", ""},
+			expectHttpStatus: 0,
+		},
+		{
+			scenario:         "limit exceeded",
+			mod:              &RateLimit{rate: 0.5, burst: 2},
+			path:             "/forms/foo",
+			keys:             []string{"", "", ""},
+			expectHttpStatus: http.StatusTooManyRequests,
+			expectRetryAfter: "2",
+		},
+		{
+			scenario:         "limit per key",
+			mod:              &RateLimit{rate: 1, burst: 1, keyHeader: "X-Api-Key"},
+			path:             "/forms/foo",
+			keys:             []string{"foo", "bar", "baz"},
+			authHeader:       "X-Api-Key",
+			expectHttpStatus: 0,
+		},
+		{
+			scenario:         "key header not validated by the auth module",
+			mod:              &RateLimit{rate: 1, burst: 1, keyHeader: "X-Api-Key"},
+			path:             "/forms/foo",
+			keys:             []string{"foo", "bar"},
+			authHeader:       "Authorization",
+			expectHttpStatus: http.StatusTooManyRequests,
+			expectRetryAfter: "1",
+		},
+		{
+			scenario:         "X-Forwarded-For header from an untrusted client",
+			mod:              &RateLimit{rate: 1, burst: 1},
+			path:             "/forms/foo",
+			keys:             []string{"", ""},
+			forwardedFor:     true,
+			expectHttpStatus: http.StatusTooManyRequests,
+			expectRetryAfter: "1",
+		},
+		{
+			scenario: "X-Forwarded-For header from a trusted proxy",
+			mod: &RateLimit{rate: 1, burst: 1, trustedProxies: func() []*net.IPNet {
+				// The remote address of the test requests.
+				_, ipRange, _ := net.ParseCIDR("192.0.2.0/24")
+				return []*net.IPNet{ipRange}
+			}()},
+			path:             "/forms/foo",
+			keys:             []string{"", "", ""},
+			forwardedFor:     true,
+			expectHttpStatus: 0,
+		},
+		{
+			scenario: "specific limit for a route",
+			mod: &RateLimit{rate: 1, burst: 10, routes: map[string]limit{
+				"/forms/foo": {rate: 1, burst: 1},
+			}},
+			path:             "/root/forms/foo",
+			keys:             []string{"", ""},
+			expectHttpStatus: http.StatusTooManyRequests,
+			expectRetryAfter: "1",
+		},
+		{
+			scenario: "most specific limit for a route",
+			mod: &RateLimit{rate: 1, burst: 10, routes: map[string]limit{
+				"/foo":           {rate: 1, burst: 10},
+				"/forms/foo":     {rate: 1, burst: 1},
+				"/bar/forms/foo": {rate: 1, burst: 10},
+			}},
+			path:             "/root/forms/foo",
+			keys:             []string{"", ""},
+			expectHttpStatus: http.StatusTooManyRequests,
+			expectRetryAfter: "1",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			srv := echo.New()
+			handler := rateLimitMiddleware(tc.mod).Handler(next)
+
+			var (
+				err error
+				c   echo.Context
+			)
+
+			for i, key := range tc.keys {
+				req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+				if key != "" {
+					req.Header.Set("X-Api-Key", key)
+				}
+				if tc.forwardedFor {
+					req.Header.Set(echo.HeaderXForwardedFor, fmt.Sprintf("203.0.113.%d", i+1))
+				}
+
+				c = srv.NewContext(req, httptest.NewRecorder())
+				c.SetPath(tc.path)
+				if tc.authHeader != "" {
+					c.Set("authHeader", tc.authHeader)
+				}
+
+				err = handler(c)
+				if err != nil {
+					break
+				}
+			}
+
+			if tc.expectHttpStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return
+			}
+
+			var httpErr api.HttpError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected an HTTP error but got: %v", err)
+			}
+
+			status, _ := httpErr.HttpError()
+			if status != tc.expectHttpStatus {
+				t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+			}
+
+			retryAfter := c.Response().Header().Get("Retry-After")
+			if retryAfter != tc.expectRetryAfter {
+				t.Errorf("expected '%s' as Retry-After header but got '%s'", tc.expectRetryAfter, retryAfter)
+			}
+		})
+	}
+}
+
+func TestLimiter_allow(t *testing.T) {
+	now := time.Now()
+
+	l := newLimiter(limit{rate: 1, burst: 2})
+	l.now = func() time.Time {
+		return now
+	}
+
+	for i := 0; i < 2; i++ {
+		ok, _ := l.allow("foo")
+		if !ok {
+			t.Fatalf("expected request %d to be allowed", i)
+		}
+	}
+
+	ok, retryAfter := l.allow("foo")
+	if ok {
+		t.Fatal("expected request to be denied")
+	}
+
+	if retryAfter != time.Second {
+		t.Errorf("expected %s but got %s", time.Second, retryAfter)
+	}
+
+	// Another key has its own bucket.
+	ok, _ = l.allow("bar")
+	if !ok {
+		t.Error("expected request from another key to be allowed")
+	}
+
+	// The bucket regains tokens over time.
+	now = now.Add(time.Second)
+
+	ok, _ = l.allow("foo")
+	if !ok {
+		t.Error("expected request to be allowed after a refill")
+	}
+
+	// Full buckets are removed once per minute.
+	now = now.Add(time.Minute)
+
+	l.sweep(now)
+
+	if len(l.buckets) != 0 {
+		t.Errorf("expected no buckets but got %d", len(l.buckets))
+	}
+}
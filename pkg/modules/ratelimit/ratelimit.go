@@ -0,0 +1,164 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(RateLimit))
+}
+
+// RateLimit is a module which provides a middleware for limiting the number
+// of requests a client may send to the multipart/form-data routes.
+type RateLimit struct {
+	enable         bool
+	rate           float64
+	burst          int
+	keyHeader      string
+	trustedProxies []*net.IPNet
+	routes         map[string]limit
+}
+
+// limit is the rate, in requests per second, and the burst of a token bucket.
+type limit struct {
+	rate  float64
+	burst int
+}
+
+// Descriptor returns a [RateLimit]'s module descriptor.
+func (mod *RateLimit) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "ratelimit",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("ratelimit", flag.ExitOnError)
+			fs.Bool("ratelimit-enable", false, "Enable the rate limiting of the multipart/form-data routes")
+			fs.Float64("ratelimit-rate", 10, "Set the number of requests per second a client may send")
+			fs.Int("ratelimit-burst", 20, "Set the number of requests a client may send at once")
+			fs.String("ratelimit-key-header", "", "Set the header identifying a client, which must be the header validated by the auth module (e.g., X-Api-Key) - fallback to the client IP if empty, not provided or not validated")
+			fs.StringSlice("ratelimit-trusted-proxies", make([]string, 0), "Set the IP ranges of the proxies whose X-Forwarded-For header tells the client IP (e.g., 10.0.0.0/8) - the client IP is the remote address if empty")
+			fs.StringSlice("ratelimit-routes", make([]string, 0), "Set specific limits for some routes, i.e., path=rate:burst (e.g., /forms/chromium/convert/url=1:2)")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(RateLimit) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *RateLimit) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("ratelimit-enable")
+	mod.rate = flags.MustFloat64("ratelimit-rate")
+	mod.burst = flags.MustInt("ratelimit-burst")
+	mod.keyHeader = flags.MustString("ratelimit-key-header")
+
+	routes, err := parseRoutes(flags.MustStringSlice("ratelimit-routes"))
+	if err != nil {
+		return fmt.Errorf("parse routes: %w", err)
+	}
+
+	mod.routes = routes
+
+	for _, value := range flags.MustStringSlice("ratelimit-trusted-proxies") {
+		_, ipRange, err := net.ParseCIDR(value)
+		if err != nil {
+			return fmt.Errorf("parse trusted proxy: %w", err)
+		}
+
+		mod.trustedProxies = append(mod.trustedProxies, ipRange)
+	}
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *RateLimit) Validate() error {
+	if !mod.enable {
+		return nil
+	}
+
+	var err error
+
+	if mod.rate <= 0 {
+		err = multierr.Append(err,
+			errors.New("rate must be more than 0"),
+		)
+	}
+
+	if mod.burst < 1 {
+		err = multierr.Append(err,
+			errors.New("burst must be more than 0"),
+		)
+	}
+
+	for path, l := range mod.routes {
+		if l.rate <= 0 || l.burst < 1 {
+			err = multierr.Append(err,
+				fmt.Errorf("route '%s' must have a rate and a burst more than 0", path),
+			)
+		}
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware.
+func (mod *RateLimit) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		rateLimitMiddleware(mod),
+	}, nil
+}
+
+// parseRoutes parses the "ratelimit-routes" flag values, i.e.,
+// path=rate:burst.
+func parseRoutes(values []string) (map[string]limit, error) {
+	routes := make(map[string]limit, len(values))
+
+	for _, value := range values {
+		path, rawLimit, ok := strings.Cut(value, "=")
+		if !ok || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("'%s' does not match the path=rate:burst format", value)
+		}
+
+		rawRate, rawBurst, ok := strings.Cut(rawLimit, ":")
+		if !ok {
+			return nil, fmt.Errorf("'%s' does not match the path=rate:burst format", value)
+		}
+
+		rate, err := strconv.ParseFloat(rawRate, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse rate of '%s': %w", value, err)
+		}
+
+		burst, err := strconv.Atoi(rawBurst)
+		if err != nil {
+			return nil, fmt.Errorf("parse burst of '%s': %w", value, err)
+		}
+
+		routes[path] = limit{rate: rate, burst: burst}
+	}
+
+	return routes, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*RateLimit)(nil)
+	_ gotenberg.Provisioner  = (*RateLimit)(nil)
+	_ gotenberg.Validator    = (*RateLimit)(nil)
+	_ api.MiddlewareProvider = (*RateLimit)(nil)
+)
@@ -0,0 +1,224 @@
+package ratelimit
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestRateLimit_Descriptor(t *testing.T) {
+	descriptor := new(RateLimit).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(RateLimit))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestRateLimit_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		expectError bool
+	}{
+		{
+			scenario: "default flags",
+			ctx: gotenberg.NewContext(
+				gotenberg.ParsedFlags{
+					FlagSet: new(RateLimit).Descriptor().FlagSet,
+				},
+				nil,
+			),
+			expectError: false,
+		},
+		{
+			scenario: "invalid routes",
+			ctx: func() *gotenberg.Context {
+				fs := new(RateLimit).Descriptor().FlagSet
+				err := fs.Parse([]string{"--ratelimit-routes=/forms/foo=1"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+			}(),
+			expectError: true,
+		},
+		{
+			scenario: "invalid trusted proxies",
+			ctx: func() *gotenberg.Context {
+				fs := new(RateLimit).Descriptor().FlagSet
+				err := fs.Parse([]string{"--ratelimit-trusted-proxies=10.0.0.1"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+			}(),
+			expectError: true,
+		},
+		{
+			scenario: "trusted proxies",
+			ctx: func() *gotenberg.Context {
+				fs := new(RateLimit).Descriptor().FlagSet
+				err := fs.Parse([]string{"--ratelimit-trusted-proxies=10.0.0.0/8,2001:db8::/32"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+			}(),
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(RateLimit)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestRateLimit_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *RateLimit
+		expectError bool
+	}{
+		{
+			scenario:    "disabled",
+			mod:         &RateLimit{enable: false, rate: 0, burst: 0},
+			expectError: false,
+		},
+		{
+			scenario:    "invalid rate and burst",
+			mod:         &RateLimit{enable: true, rate: 0, burst: 0},
+			expectError: true,
+		},
+		{
+			scenario: "invalid route limit",
+			mod: &RateLimit{enable: true, rate: 1, burst: 1, routes: map[string]limit{
+				"/forms/foo": {rate: 0, burst: 1},
+			}},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			mod: &RateLimit{enable: true, rate: 1, burst: 1, routes: map[string]limit{
+				"/forms/foo": {rate: 0.5, burst: 1},
+			}},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestRateLimit_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		enable            bool
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "rate limiting disabled",
+			enable:            false,
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "rate limiting enabled",
+			enable:            true,
+			expectMiddlewares: 1,
+		},
+	} {
+		mod := &RateLimit{enable: tc.enable, rate: 1, burst: 1}
+
+		middlewares, err := mod.Middlewares()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if tc.expectMiddlewares != len(middlewares) {
+			t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+		}
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		values       []string
+		expectRoutes map[string]limit
+		expectError  bool
+	}{
+		{
+			scenario:     "no routes",
+			values:       nil,
+			expectRoutes: map[string]limit{},
+		},
+		{
+			scenario:    "missing limit",
+			values:      []string{"/forms/foo"},
+			expectError: true,
+		},
+		{
+			scenario:    "missing burst",
+			values:      []string{"/forms/foo=1"},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid rate",
+			values:      []string{"/forms/foo=a:1"},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid burst",
+			values:      []string{"/forms/foo=1:a"},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			values:   []string{"/forms/foo=0.5:1", "/forms/bar=2:4"},
+			expectRoutes: map[string]limit{
+				"/forms/foo": {rate: 0.5, burst: 1},
+				"/forms/bar": {rate: 2, burst: 4},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			routes, err := parseRoutes(tc.values)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && !reflect.DeepEqual(routes, tc.expectRoutes) {
+				t.Errorf("expected %+v but got %+v", tc.expectRoutes, routes)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package tracing provides a module which exports OpenTelemetry traces to an
+// OTLP/HTTP endpoint. It adds a span per request, linked to the incoming
+// trace context (i.e., the traceparent header), and registers the tracer
+// provider used by the Chromium, LibreOffice and PDF engines operations.
+package tracing
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// tracerName is the instrumentation name of the requests' spans.
+const tracerName = "github.com/gotenberg/gotenberg/v8/pkg/modules/tracing"
+
+// tracingMiddleware starts a span per request. The span continues the trace
+// of the incoming trace context, if any, and is the parent of the spans
+// created while handling the request.
+func tracingMiddleware() api.Middleware {
+	return api.Middleware{
+		Priority: api.VeryHighPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					req := c.Request()
+					route := c.Path()
+
+					ctx := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+					ctx, span := otel.Tracer(tracerName).Start(ctx, fmt.Sprintf("%s %s", req.Method, route),
+						trace.WithSpanKind(trace.SpanKindServer),
+						trace.WithAttributes(
+							semconv.HTTPRequestMethodKey.String(req.Method),
+							semconv.HTTPRoute(route),
+							semconv.URLPath(req.URL.Path),
+						),
+					)
+					defer span.End()
+
+					gotenbergTrace, ok := c.Get("trace").(string)
+					if ok {
+						span.SetAttributes(attribute.String("gotenberg.trace", gotenbergTrace))
+					}
+
+					// The request context carries the span to the handlers
+					// (see api.Context).
+					c.SetRequest(req.WithContext(ctx))
+
+					// Call the next middleware in the chain.
+					err := next(c)
+
+					status := c.Response().Status
+					if err != nil {
+						// The error handler has not written the response yet.
+						status, _ = api.ParseError(err)
+						span.RecordError(err)
+					}
+
+					span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+					if status >= http.StatusInternalServerError {
+						span.SetStatus(codes.Error, http.StatusText(status))
+					}
+
+					return err
+				}
+			}
+		}(),
+	}
+}
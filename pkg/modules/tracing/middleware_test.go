@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	for _, tc := range []struct {
+		scenario         string
+		traceparent      string
+		next             echo.HandlerFunc
+		expectError      bool
+		expectTraceId    string
+		expectHttpStatus int64
+		expectSpanStatus codes.Code
+	}{
+		{
+			scenario: "new trace",
+			next: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+			expectHttpStatus: http.StatusOK,
+			expectSpanStatus: codes.Unset,
+		},
+		{
+			scenario:    "incoming trace context",
+			traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			next: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+			expectTraceId:    "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectHttpStatus: http.StatusOK,
+			expectSpanStatus: codes.Unset,
+		},
+		{
+			scenario: "client error",
+			next: func(c echo.Context) error {
+				return api.WrapError(errors.New("foo"), api.NewSentinelHttpError(http.StatusBadRequest, "foo"))
+			},
+			expectError:      true,
+			expectHttpStatus: http.StatusBadRequest,
+			expectSpanStatus: codes.Unset,
+		},
+		{
+			scenario: "server error",
+			next: func(c echo.Context) error {
+				return errors.New("foo")
+			},
+			expectError:      true,
+			expectHttpStatus: http.StatusInternalServerError,
+			expectSpanStatus: codes.Error,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			srv := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/forms/foo", nil)
+			if tc.traceparent != "" {
+				req.Header.Set("traceparent", tc.traceparent)
+			}
+
+			c := srv.NewContext(req, httptest.NewRecorder())
+			c.SetPath("/forms/foo")
+			c.Set("trace", "foo")
+
+			var handlerSpanContext trace.SpanContext
+			next := func(c echo.Context) error {
+				handlerSpanContext = trace.SpanContextFromContext(c.Request().Context())
+				return tc.next(c)
+			}
+
+			err := tracingMiddleware().Handler(next)(c)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+
+			if span.Name() != "POST /forms/foo" {
+				t.Errorf("expected span name 'POST /forms/foo' but got '%s'", span.Name())
+			}
+
+			if !handlerSpanContext.Equal(span.SpanContext()) {
+				t.Error("expected request context to carry the span")
+			}
+
+			if tc.expectTraceId != "" && span.SpanContext().TraceID().String() != tc.expectTraceId {
+				t.Errorf("expected trace ID '%s' but got '%s'", tc.expectTraceId, span.SpanContext().TraceID())
+			}
+
+			var status int64
+			for _, attr := range span.Attributes() {
+				if attr.Key == semconv.HTTPResponseStatusCodeKey {
+					status = attr.Value.AsInt64()
+				}
+			}
+
+			if status != tc.expectHttpStatus {
+				t.Errorf("expected HTTP status %d but got %d", tc.expectHttpStatus, status)
+			}
+
+			if span.Status().Code != tc.expectSpanStatus {
+				t.Errorf("expected span status '%s' but got '%s'", tc.expectSpanStatus, span.Status().Code)
+			}
+		})
+	}
+}
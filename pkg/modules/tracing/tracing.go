@@ -0,0 +1,160 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	flag "github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Tracing))
+}
+
+// Tracing is a module which exports OpenTelemetry traces. It is disabled
+// unless an OTLP endpoint is provided.
+type Tracing struct {
+	endpoint    string
+	serviceName string
+	sampleRatio float64
+
+	provider *sdktrace.TracerProvider
+}
+
+// Descriptor returns a [Tracing]'s module descriptor.
+func (mod *Tracing) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "tracing",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("tracing", flag.ExitOnError)
+			fs.String("tracing-otlp-endpoint", "", "Set the OTLP/HTTP endpoint to which the traces are exported (e.g., http://otel-collector:4318) - empty means tracing is disabled")
+			fs.String("tracing-service-name", "gotenberg", "Set the service name of the traces")
+			fs.Float64("tracing-sample-ratio", 1, "Set the ratio of traces to sample, from 0 to 1 - incoming sampled traces are always sampled")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Tracing) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Tracing) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.endpoint = flags.MustString("tracing-otlp-endpoint")
+	mod.serviceName = flags.MustString("tracing-service-name")
+	mod.sampleRatio = flags.MustFloat64("tracing-sample-ratio")
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Tracing) Validate() error {
+	if mod.endpoint == "" {
+		return nil
+	}
+
+	var err error
+
+	u, parseErr := url.Parse(mod.endpoint)
+	if parseErr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		err = multierr.Append(err,
+			fmt.Errorf("OTLP endpoint '%s' must be an http or https URL", mod.endpoint),
+		)
+	}
+
+	if mod.serviceName == "" {
+		err = multierr.Append(err,
+			errors.New("service name must not be empty"),
+		)
+	}
+
+	if mod.sampleRatio < 0 || mod.sampleRatio > 1 {
+		err = multierr.Append(err,
+			errors.New("sample ratio must be between 0 and 1"),
+		)
+	}
+
+	return err
+}
+
+// Start registers the global tracer provider, which batches and exports the
+// spans to the OTLP endpoint.
+func (mod *Tracing) Start() error {
+	if mod.endpoint == "" {
+		return nil
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(mod.endpoint))
+	if err != nil {
+		return fmt.Errorf("create OTLP exporter: %w", err)
+	}
+
+	mod.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(mod.serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(mod.sampleRatio))),
+	)
+
+	otel.SetTracerProvider(mod.provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Tracing) StartupMessage() string {
+	if mod.endpoint == "" {
+		return "tracing disabled"
+	}
+
+	return fmt.Sprintf("exporting traces to %s", mod.endpoint)
+}
+
+// Stop flushes the remaining spans and stops the tracer provider.
+func (mod *Tracing) Stop(ctx context.Context) error {
+	if mod.provider == nil {
+		return nil
+	}
+
+	err := mod.provider.Shutdown(ctx)
+	if err != nil {
+		return fmt.Errorf("shutdown tracer provider: %w", err)
+	}
+
+	return nil
+}
+
+// Middlewares returns the middleware.
+func (mod *Tracing) Middlewares() ([]api.Middleware, error) {
+	if mod.endpoint == "" {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		tracingMiddleware(),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*Tracing)(nil)
+	_ gotenberg.Provisioner  = (*Tracing)(nil)
+	_ gotenberg.Validator    = (*Tracing)(nil)
+	_ gotenberg.App          = (*Tracing)(nil)
+	_ api.MiddlewareProvider = (*Tracing)(nil)
+)
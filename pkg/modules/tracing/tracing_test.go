@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestTracing_Descriptor(t *testing.T) {
+	descriptor := new(Tracing).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Tracing))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestTracing_Provision(t *testing.T) {
+	mod := new(Tracing)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Tracing).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func TestTracing_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Tracing
+		expectError bool
+	}{
+		{
+			scenario:    "disabled",
+			mod:         &Tracing{endpoint: "", sampleRatio: 42},
+			expectError: false,
+		},
+		{
+			scenario:    "invalid endpoint",
+			mod:         &Tracing{endpoint: "localhost:4318", serviceName: "gotenberg", sampleRatio: 1},
+			expectError: true,
+		},
+		{
+			scenario:    "empty service name",
+			mod:         &Tracing{endpoint: "http://localhost:4318", serviceName: "", sampleRatio: 1},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid sample ratio",
+			mod:         &Tracing{endpoint: "http://localhost:4318", serviceName: "gotenberg", sampleRatio: 1.5},
+			expectError: true,
+		},
+		{
+			scenario:    "success",
+			mod:         &Tracing{endpoint: "https://localhost:4318", serviceName: "gotenberg", sampleRatio: 0.5},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestTracing_Start(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		mod            *Tracing
+		expectProvider bool
+	}{
+		{
+			scenario:       "disabled",
+			mod:            &Tracing{},
+			expectProvider: false,
+		},
+		{
+			scenario:       "enabled",
+			mod:            &Tracing{endpoint: "http://localhost:4318", serviceName: "gotenberg", sampleRatio: 1},
+			expectProvider: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Start()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectProvider != (tc.mod.provider != nil) {
+				t.Errorf("expected provider to be registered: %t", tc.expectProvider)
+			}
+
+			if tc.mod.StartupMessage() == "" {
+				t.Error("expected non-empty startup message")
+			}
+
+			err = tc.mod.Stop(context.Background())
+			if err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestTracing_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		endpoint          string
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "tracing disabled",
+			endpoint:          "",
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "tracing enabled",
+			endpoint:          "http://localhost:4318",
+			expectMiddlewares: 1,
+		},
+	} {
+		mod := &Tracing{endpoint: tc.endpoint}
+
+		middlewares, err := mod.Middlewares()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if tc.expectMiddlewares != len(middlewares) {
+			t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+		}
+	}
+}
@@ -14,12 +14,14 @@ import (
 
 // client gathers all the data required to send a request to a webhook.
 type client struct {
-	url              string
-	method           string
-	errorUrl         string
-	errorMethod      string
-	extraHttpHeaders map[string]string
-	startTime        time.Time
+	url                   string
+	method                string
+	errorUrl              string
+	errorMethod           string
+	extraHttpHeaders      map[string]string
+	errorExtraHttpHeaders map[string]string
+	traceHttpHeaders      map[string]string
+	startTime             time.Time
 
 	client *retryablehttp.Client
 	logger *zap.Logger
@@ -49,12 +51,25 @@ func (c client) send(body io.Reader, headers map[string]string, erroed bool) err
 		req.Header.Set(key, value)
 	}
 
-	// Middleware caller's headers > extra HTTP headers from the user.
+	// Extra HTTP headers for the error URL > extra HTTP headers.
+	if erroed {
+		for key, value := range c.errorExtraHttpHeaders {
+			req.Header.Set(key, value)
+		}
+	}
+
+	// Trace context headers (e.g., traceparent) > extra HTTP headers from the
+	// user.
+	for key, value := range c.traceHttpHeaders {
+		req.Header.Set(key, value)
+	}
+
+	// Middleware caller's headers > trace context headers.
 
 	contentLength, ok := headers[echo.HeaderContentLength]
 	if ok {
 		// Golang "http" package should automatically calculate the size of the
-		// body. But, when using a buffered file reader, it does not work.
+		// body. But, when using a file reader, it does not work.
 		// Worse, the "Content-Length" header is also removed. Therefore, in
 		// order to keep this valuable information, we have to trust the caller
 		// by reading the value of the "Content-Length" entry and set it as the
@@ -111,6 +126,38 @@ func (c client) send(body io.Reader, headers map[string]string, erroed bool) err
 	return nil
 }
 
+// requestLogHook returns a [retryablehttp.RequestLogHook] which logs each
+// attempt to deliver a request to a webhook.
+func requestLogHook(logger *zap.Logger) retryablehttp.RequestLogHook {
+	return func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+		logger.Debug(
+			"webhook delivery attempt",
+			zap.String("webhook_url", req.URL.String()),
+			zap.String("method", req.Method),
+			zap.Int("attempt", attempt+1),
+		)
+	}
+}
+
+// responseLogHook returns a [retryablehttp.ResponseLogHook] which logs the
+// response of each attempt to deliver a request to a webhook.
+func responseLogHook(logger *zap.Logger) retryablehttp.ResponseLogHook {
+	return func(_ retryablehttp.Logger, resp *http.Response) {
+		fields := []zap.Field{
+			zap.String("webhook_url", resp.Request.URL.String()),
+			zap.String("method", resp.Request.Method),
+			zap.Int("status", resp.StatusCode),
+		}
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			logger.Warn("webhook delivery attempt failed", fields...)
+			return
+		}
+
+		logger.Info("webhook delivery attempt succeeded", fields...)
+	}
+}
+
 // leveledLogger is wrapper around a [zap.Logger] which is used by the
 // [retryablehttp.Client].
 type leveledLogger struct {
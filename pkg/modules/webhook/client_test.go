@@ -1,6 +1,8 @@
 package webhook
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"go.uber.org/zap"
@@ -21,3 +23,18 @@ func TestLeveledLogger_Info(t *testing.T) {
 func TestLeveledLogger_Debug(t *testing.T) {
 	leveledLogger{logger: zap.NewNop()}.Debug("foo")
 }
+
+func TestRequestLogHook(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "http://localhost/", nil)
+	requestLogHook(zap.NewNop())(nil, req, 0)
+}
+
+func TestResponseLogHook(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusInternalServerError} {
+		resp := &http.Response{
+			StatusCode: status,
+			Request:    httptest.NewRequest(http.MethodPatch, "http://localhost/", nil),
+		}
+		responseLogHook(zap.NewNop())(nil, resp)
+	}
+}
@@ -1,13 +1,13 @@
 package webhook
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"strconv"
@@ -16,13 +16,41 @@ import (
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
+// idempotencyKeyHeader is the default header of the idempotency key of a
+// request, forwarded as is to the webhook.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// checkUrl returns an error if the given webhook URL is not an absolute
+// HTTP(S) URL.
+func checkUrl(URL string) error {
+	u, err := url.Parse(URL)
+	if err != nil {
+		return fmt.Errorf("parse URL: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("'%s' is not an HTTP(S) URL", URL)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("'%s' has no host", URL)
+	}
+
+	return nil
+}
+
 func webhookMiddleware(w *Webhook) api.Middleware {
 	return api.Middleware{
 		Stack: api.MultipartStack,
+		// The other multipart/form-data middlewares, e.g., the cache one,
+		// must run within the goroutine of a webhook request.
+		Priority: api.HighPriority,
 		Handler: func() echo.MiddlewareFunc {
 			return func(next echo.HandlerFunc) echo.HandlerFunc {
 				return func(c echo.Context) error {
@@ -80,6 +108,19 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 						return fmt.Errorf("filter webhook error URL: %w", err)
 					}
 
+					for header, URL := range map[string]string{
+						"Gotenberg-Webhook-Url":       webhookUrl,
+						"Gotenberg-Webhook-Error-Url": webhookErrorUrl,
+					} {
+						err = checkUrl(URL)
+						if err != nil {
+							return api.WrapError(
+								fmt.Errorf("check webhook URL: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' header value: %s", header, err)),
+							)
+						}
+					}
+
 					// Let's check the HTTP methods for calling the webhook URLs.
 					methodFromHeader := func(header string) (string, error) {
 						method := c.Request().Header.Get(header)
@@ -118,31 +159,88 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 						return fmt.Errorf("get method to use for webhook error: %w", err)
 					}
 
-					// What about extra HTTP headers?
-					var extraHTTPHeaders map[string]string
+					// What about extra HTTP headers? The ones for the error URL
+					// override the common ones.
+					extraHttpHeadersFromHeader := func(header string) (map[string]string, error) {
+						var extraHttpHeaders map[string]string
+
+						extraHttpHeadersJson := c.Request().Header.Get(header)
+						if extraHttpHeadersJson == "" {
+							return nil, nil
+						}
 
-					extraHTTPHeadersJSON := c.Request().Header.Get("Gotenberg-Webhook-Extra-Http-Headers")
-					if extraHTTPHeadersJSON != "" {
-						err = json.Unmarshal([]byte(extraHTTPHeadersJSON), &extraHTTPHeaders)
+						err := json.Unmarshal([]byte(extraHttpHeadersJson), &extraHttpHeaders)
 						if err != nil {
-							return api.WrapError(
+							return nil, api.WrapError(
 								fmt.Errorf("unmarshal webhook extra HTTP headers: %w", err),
-								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'Gotenberg-Webhook-Extra-Http-Headers' header value: %s", err.Error())),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' header value: %s", header, err.Error())),
 							)
 						}
+
+						return extraHttpHeaders, nil
+					}
+
+					extraHTTPHeaders, err := extraHttpHeadersFromHeader("Gotenberg-Webhook-Extra-Http-Headers")
+					if err != nil {
+						return fmt.Errorf("get extra HTTP headers for webhook: %w", err)
+					}
+
+					errorExtraHttpHeaders, err := extraHttpHeadersFromHeader("Gotenberg-Webhook-Error-Extra-Http-Headers")
+					if err != nil {
+						return fmt.Errorf("get extra HTTP headers for webhook error: %w", err)
+					}
+
+					// The client only wants to validate its request, including
+					// the webhook headers: nothing to send asynchronously.
+					if ctx.ValidateOnly() {
+						return next(c)
+					}
+
+					// Link the requests to the webhook to the trace of the
+					// current request, if any.
+					traceHttpHeaders := make(map[string]string)
+					otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(traceHttpHeaders))
+
+					// Same for the idempotency key of the current request, if
+					// any, so that the webhook may correlate its deliveries.
+					idempotencyKey := c.Request().Header.Get(idempotencyKeyHeader)
+					if idempotencyKey != "" {
+						traceHttpHeaders[idempotencyKeyHeader] = idempotencyKey
 					}
 
 					client := &client{
-						url:              webhookUrl,
-						method:           webhookMethod,
-						errorUrl:         webhookErrorUrl,
-						errorMethod:      webhookErrorMethod,
-						extraHttpHeaders: extraHTTPHeaders,
-						startTime:        c.Get("startTime").(time.Time),
+						url:                   webhookUrl,
+						method:                webhookMethod,
+						errorUrl:              webhookErrorUrl,
+						errorMethod:           webhookErrorMethod,
+						extraHttpHeaders:      extraHTTPHeaders,
+						errorExtraHttpHeaders: errorExtraHttpHeaders,
+						traceHttpHeaders:      traceHttpHeaders,
+						startTime:             c.Get("startTime").(time.Time),
 
 						client: &retryablehttp.Client{
 							HTTPClient: &http.Client{
-								Timeout: w.clientTimeout,
+								Timeout:   w.clientTimeout,
+								Transport: api.NewGuardedTransport(w.allowPrivateIps),
+								CheckRedirect: func(req *http.Request, via []*http.Request) error {
+									if len(via) >= 10 {
+										return errors.New("stopped after 10 redirects")
+									}
+
+									// The redirections must comply with the
+									// lists of the target.
+									header, allowList, denyList := "Gotenberg-Webhook-Url", w.allowList, w.denyList
+									if via[0].URL.String() == webhookErrorUrl {
+										header, allowList, denyList = "Gotenberg-Webhook-Error-Url", w.errorAllowList, w.errorDenyList
+									}
+
+									err := checkUrl(req.URL.String())
+									if err != nil {
+										return err
+									}
+
+									return filter(req.URL.String(), header, allowList, denyList)
+								},
 							},
 							RetryMax:     w.maxRetry,
 							RetryWaitMin: w.retryMinWait,
@@ -152,22 +250,31 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 							},
 							CheckRetry: retryablehttp.DefaultRetryPolicy,
 							Backoff:    retryablehttp.DefaultBackoff,
+							// Log each delivery attempt with its target,
+							// method and status.
+							RequestLogHook:  requestLogHook(ctx.Log()),
+							ResponseLogHook: responseLogHook(ctx.Log()),
 						},
 						logger: ctx.Log(),
 					}
 
 					// This method parses an "asynchronous" error and sends a
 					// request to the webhook error URL with a JSON body
-					// containing the status and the error message.
+					// containing the status, the error message and the trace.
 					handleAsyncError := func(err error) {
+						err = ctx.ShutdownError(err)
 						status, message := api.ParseError(err)
 
 						body := struct {
 							Status  int    `json:"status"`
+							Code    string `json:"code"`
 							Message string `json:"message"`
+							Trace   string `json:"trace"`
 						}{
 							Status:  status,
+							Code:    api.ErrorCode(err),
 							Message: message,
+							Trace:   ctx.Trace(),
 						}
 
 						b, err := json.Marshal(body)
@@ -260,8 +367,14 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 							c.Get("traceHeader").(string): c.Get("trace").(string),
 						}
 
-						// Send the output file to the webhook.
-						err = client.send(bufio.NewReader(outputFile), headers, false)
+						for header, value := range ctx.PdfMetadataHeaders(outputPath) {
+							headers[header] = value
+						}
+
+						// Send the output file to the webhook. As the file is
+						// seekable, the client streams it from the disk, even
+						// when it retries, instead of loading it in memory.
+						err = client.send(outputFile, headers, false)
 						if err != nil {
 							ctx.Log().Error(fmt.Sprintf("send output file to webhook: %s", err))
 							handleAsyncError(err)
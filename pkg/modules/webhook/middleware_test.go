@@ -9,6 +9,7 @@ import (
 	"io"
 	"math/rand"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"regexp"
@@ -83,7 +84,7 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "no webhook error URL",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
 				return req
 			}(),
 			mod:              buildWebhookModule(),
@@ -95,8 +96,8 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "webhook URL is not allowed",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				return req
 			}(),
 			mod: func() *Webhook {
@@ -112,8 +113,8 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "webhook URL is denied",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				return req
 			}(),
 			mod: func() *Webhook {
@@ -129,8 +130,8 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "webhook error URL is not allowed",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				return req
 			}(),
 			mod: func() *Webhook {
@@ -146,8 +147,8 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "webhook error URL is denied",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				return req
 			}(),
 			mod: func() *Webhook {
@@ -163,9 +164,9 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "invalid webhook method (GET)",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
 				req.Header.Set("Gotenberg-Webhook-Method", http.MethodGet)
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				return req
 			}(),
 			mod:              buildWebhookModule(),
@@ -177,8 +178,8 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "invalid webhook error method (GET)",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				req.Header.Set("Gotenberg-Webhook-Error-Method", http.MethodGet)
 				return req
 			}(),
@@ -191,9 +192,9 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "valid webhook method (POST) but invalid webhook error method (GET)",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
 				req.Header.Set("Gotenberg-Webhook-Method", http.MethodPost)
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				req.Header.Set("Gotenberg-Webhook-Error-Method", http.MethodGet)
 				return req
 			}(),
@@ -206,9 +207,9 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "valid webhook method (PATH) but invalid webhook error method (GET)",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
 				req.Header.Set("Gotenberg-Webhook-Method", http.MethodPatch)
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				req.Header.Set("Gotenberg-Webhook-Error-Method", http.MethodGet)
 				return req
 			}(),
@@ -221,9 +222,9 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "valid webhook method (PUT) but invalid webhook error method (GET)",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
 				req.Header.Set("Gotenberg-Webhook-Method", http.MethodPut)
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				req.Header.Set("Gotenberg-Webhook-Error-Method", http.MethodGet)
 				return req
 			}(),
@@ -236,8 +237,8 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			scenario: "invalid webhook extra HTTP headers",
 			request: func() *http.Request {
 				req := buildMultipartFormDataRequest()
-				req.Header.Set("Gotenberg-Webhook-Url", "foo")
-				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
 				req.Header.Set("Gotenberg-Webhook-Extra-Http-Headers", "foo")
 				return req
 			}(),
@@ -246,6 +247,46 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			expectHttpError:  true,
 			expectHttpStatus: http.StatusBadRequest,
 		},
+		{
+			scenario: "webhook URL is not an HTTP(S) URL",
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set("Gotenberg-Webhook-Url", "file:///etc/passwd")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
+				return req
+			}(),
+			mod:              buildWebhookModule(),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "webhook error URL without host",
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http:///bar")
+				return req
+			}(),
+			mod:              buildWebhookModule(),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "invalid webhook error extra HTTP headers",
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set("Gotenberg-Webhook-Url", "http://foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "http://bar")
+				req.Header.Set("Gotenberg-Webhook-Error-Extra-Http-Headers", "foo")
+				return req
+			}(),
+			mod:              buildWebhookModule(),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			srv := echo.New()
@@ -328,7 +369,9 @@ func TestWebhookMiddlewareAsynchronousProcess(t *testing.T) {
 			retryMinWait:   0,
 			retryMaxWait:   0,
 			clientTimeout:  time.Duration(30) * time.Second,
-			disable:        false,
+			// The webhook of the tests listens on localhost.
+			allowPrivateIps: true,
+			disable:         false,
 		}
 	}
 
@@ -359,6 +402,27 @@ func TestWebhookMiddlewareAsynchronousProcess(t *testing.T) {
 			expectWebhookErrorStatus:  http.StatusInternalServerError,
 			expectWebhookErrorMessage: http.StatusText(http.StatusInternalServerError),
 		},
+		{
+			scenario: "next handler return an error (error extra HTTP headers and idempotency key)",
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set("Gotenberg-Webhook-Extra-Http-Headers", `{ "foo": "bar", "baz": "qux" }`)
+				req.Header.Set("Gotenberg-Webhook-Error-Extra-Http-Headers", `{ "foo": "error" }`)
+				req.Header.Set("Idempotency-Key", "key")
+				return req
+			}(),
+			mod: buildWebhookModule(),
+			next: func() echo.HandlerFunc {
+				return func(c echo.Context) error {
+					return errors.New("foo")
+				}
+			}(),
+			expectWebhookContentType:      echo.MIMEApplicationJSONCharsetUTF8,
+			expectWebhookMethod:           http.MethodPost,
+			expectWebhookExtraHttpHeaders: map[string]string{"foo": "error", "baz": "qux", "Idempotency-Key": "key"},
+			expectWebhookErrorStatus:      http.StatusInternalServerError,
+			expectWebhookErrorMessage:     http.StatusText(http.StatusInternalServerError),
+		},
 		{
 			scenario: "next handler return an HTTP error",
 			request:  buildMultipartFormDataRequest(),
@@ -537,8 +601,17 @@ func TestWebhookMiddlewareAsynchronousProcess(t *testing.T) {
 				}(),
 			)
 
+			// Listen before the middleware sends its request, as it does not
+			// retry.
+			listener, err := net.Listen("tcp", fmt.Sprintf(":%d", webhookPort))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			webhook.Listener = listener
+
 			go func() {
-				err := webhook.Start(fmt.Sprintf(":%d", webhookPort))
+				err := webhook.Start("")
 				if !errors.Is(err, http.ErrServerClosed) {
 					t.Errorf("expected no error but got: %v", err)
 				}
@@ -551,7 +624,7 @@ func TestWebhookMiddlewareAsynchronousProcess(t *testing.T) {
 				}
 			}()
 
-			err := webhookMiddleware(tc.mod).Handler(tc.next)(c)
+			err = webhookMiddleware(tc.mod).Handler(tc.next)(c)
 			if err != nil && !errors.Is(err, api.ErrAsyncProcess) {
 				t.Errorf("expected no error but got: %v", err)
 			}
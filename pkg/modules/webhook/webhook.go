@@ -17,15 +17,16 @@ func init() {
 // Webhook is a module which provides a middleware for uploading output files
 // to any destinations in an asynchronous fashion.
 type Webhook struct {
-	allowList      *regexp.Regexp
-	denyList       *regexp.Regexp
-	errorAllowList *regexp.Regexp
-	errorDenyList  *regexp.Regexp
-	maxRetry       int
-	retryMinWait   time.Duration
-	retryMaxWait   time.Duration
-	clientTimeout  time.Duration
-	disable        bool
+	allowList       *regexp.Regexp
+	denyList        *regexp.Regexp
+	errorAllowList  *regexp.Regexp
+	errorDenyList   *regexp.Regexp
+	maxRetry        int
+	retryMinWait    time.Duration
+	retryMaxWait    time.Duration
+	clientTimeout   time.Duration
+	allowPrivateIps bool
+	disable         bool
 }
 
 // Descriptor returns an [Webhook]'s module descriptor.
@@ -42,6 +43,7 @@ func (w *Webhook) Descriptor() gotenberg.ModuleDescriptor {
 			fs.Duration("webhook-retry-min-wait", time.Duration(1)*time.Second, "Set the minimum duration to wait before trying to call the webhook again")
 			fs.Duration("webhook-retry-max-wait", time.Duration(30)*time.Second, "Set the maximum duration to wait before trying to call the webhook again")
 			fs.Duration("webhook-client-timeout", time.Duration(30)*time.Second, "Set the time limit for requests to the webhook")
+			fs.Bool("webhook-allow-private-ips", false, "Allow the webhook URLs which target loopback, private and shared IP addresses - link-local and cloud metadata addresses are always denied")
 			fs.Bool("webhook-disable", false, "Disable the webhook feature")
 
 			return fs
@@ -61,6 +63,7 @@ func (w *Webhook) Provision(ctx *gotenberg.Context) error {
 	w.retryMinWait = flags.MustDuration("webhook-retry-min-wait")
 	w.retryMaxWait = flags.MustDuration("webhook-retry-max-wait")
 	w.clientTimeout = flags.MustDuration("webhook-client-timeout")
+	w.allowPrivateIps = flags.MustBool("webhook-allow-private-ips")
 	w.disable = flags.MustBool("webhook-disable")
 
 	return nil
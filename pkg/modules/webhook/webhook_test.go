@@ -31,6 +31,10 @@ func TestWebhook_Provision(t *testing.T) {
 	if err != nil {
 		t.Errorf("expected no error but got: %v", err)
 	}
+
+	if mod.allowPrivateIps {
+		t.Error("expected the webhook URLs which target private IP addresses to be denied by default")
+	}
 }
 
 func TestWebhook_Middlewares(t *testing.T) {